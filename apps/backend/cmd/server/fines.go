@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"library-management-backend/internal/services"
+	"library-management-backend/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runAccrueFines implements the "accrue-fines" subcommand.
+func runAccrueFines(fineService *services.FineService) error {
+	added, err := fineService.AccrueOverdueFines()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "added %d overdue fine charges\n", added)
+	return nil
+}
+
+// accrueFinesPeriodically accrues overdue fines every AccrualInterval until
+// the process exits. Failures are logged by the service and retried on the
+// next tick.
+func accrueFinesPeriodically(fineService *services.FineService, cfg config.FinesConfig, logger *logrus.Logger) {
+	if cfg.AccrualInterval <= 0 {
+		logger.Info("Background fine accrual is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.AccrualInterval)
+	defer ticker.Stop()
+
+	for {
+		fineService.AccrueOverdueFines()
+		<-ticker.C
+	}
+}
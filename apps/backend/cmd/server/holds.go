@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"library-management-backend/internal/services"
+	"library-management-backend/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runExpireHolds implements the "expire-holds" subcommand.
+func runExpireHolds(holdService *services.HoldService) error {
+	expired, err := holdService.ExpirePickups()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "expired %d holds that were not picked up\n", expired)
+	return nil
+}
+
+// expireHoldsPeriodically expires uncollected holds every HoldExpiryInterval
+// until the process exits. Failures are logged by the service and retried on
+// the next tick.
+func expireHoldsPeriodically(holdService *services.HoldService, cfg config.CirculationConfig, logger *logrus.Logger) {
+	if cfg.HoldExpiryInterval <= 0 {
+		logger.Info("Background hold expiry is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.HoldExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		holdService.ExpirePickups()
+		<-ticker.C
+	}
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
+	// Embed the time zone database for images without one.
+	_ "time/tzdata"
 
 	"library-management-backend/internal/database"
 	"library-management-backend/internal/handlers"
 	"library-management-backend/internal/middleware"
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
 	"library-management-backend/internal/services"
 	"library-management-backend/pkg/config"
 
@@ -61,12 +67,134 @@ func main() {
 	}
 	defer db.Close()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:], logger); err != nil {
+			logger.WithError(err).Fatal("Migration command failed")
+		}
+		return
+	}
+
+	if cfg.Database.AutoMigrate {
+		migrator, err := database.NewMigrator(db.DB, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load migrations")
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			logger.WithError(err).Fatal("Failed to apply migrations")
+		}
+	}
+
 	validate := validator.New()
+	if err := models.RegisterValidations(validate); err != nil {
+		logger.WithError(err).Fatal("Failed to register validations")
+	}
 
-	bookService := services.NewBookService(db.DB, logger)
+	bookRepository := repository.NewPostgresBookRepository(db.DB)
+	authorRepository := repository.NewPostgresAuthorRepository(db.DB)
+	genreRepository := repository.NewPostgresGenreRepository(db.DB)
+	itemRepository := repository.NewPostgresItemRepository(db.DB)
+	memberRepository := repository.NewPostgresMemberRepository(db.DB)
+	loanRepository := repository.NewPostgresLoanRepository(db.DB)
+	holdRepository := repository.NewPostgresHoldRepository(db.DB)
+	fineRepository := repository.NewPostgresFineRepository(db.DB)
+	policyRepository := repository.NewPostgresPolicyRepository(db.DB)
+	calendarRepository := repository.NewPostgresCalendarRepository(db.DB)
+	noticeRepository := repository.NewPostgresNoticeRepository(db.DB)
+	branchRepository := repository.NewPostgresBranchRepository(db.DB)
+	transferRepository := repository.NewPostgresTransferRepository(db.DB)
+	inventoryRepository := repository.NewPostgresInventoryRepository(db.DB)
+	vendorRepository := repository.NewPostgresVendorRepository(db.DB)
+	fundRepository := repository.NewPostgresFundRepository(db.DB)
+	purchaseOrderRepository := repository.NewPostgresPurchaseOrderRepository(db.DB)
+
+	bookService := services.NewBookService(bookRepository, authorRepository, genreRepository, itemRepository, logger)
+	authorService := services.NewAuthorService(authorRepository, logger)
+	genreService := services.NewGenreService(genreRepository, logger)
+	memberService := services.NewMemberService(memberRepository, loanRepository, logger)
+	loanRules := services.LoanRules{
+		LoanDays:       cfg.Circulation.LoanDays,
+		MaxLoans:       cfg.Circulation.MaxLoans,
+		MaxRenewals:    cfg.Circulation.MaxRenewals,
+		HoldPickupDays: cfg.Circulation.HoldPickupDays,
+	}
+	fineRules := services.FineRules{
+		DailyRateCents:  cfg.Fines.DailyRateCents,
+		MaxFineCents:    cfg.Fines.MaxFineCents,
+		MaxBalanceCents: cfg.Fines.MaxBalanceCents,
+	}
+	policyService := services.NewPolicyService(policyRepository, genreRepository, memberRepository, branchRepository, loanRules, fineRules, logger)
+	location, err := time.LoadLocation(cfg.Calendar.TimeZone)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load library time zone")
+	}
+	calendarService := services.NewCalendarService(calendarRepository, branchRepository, location, logger)
+	fineService := services.NewFineService(fineRepository, memberRepository, calendarService, fineRules, logger)
+	itemService := services.NewItemService(itemRepository, bookRepository, loanRepository, branchRepository, calendarService, loanRules, logger)
+	circulationService := services.NewCirculationService(loanRepository, itemRepository, memberRepository, fineService, policyService, calendarService, branchRepository, loanRules, logger)
+	holdService := services.NewHoldService(holdRepository, bookRepository, memberRepository, policyService, calendarService, branchRepository, loanRules, logger)
+	branchService := services.NewBranchService(branchRepository, logger)
+	transferService := services.NewTransferService(transferRepository, itemRepository, branchRepository, calendarService, loanRules, logger)
+	inventoryService := services.NewInventoryService(inventoryRepository, itemService, branchRepository, logger)
+	vendorService := services.NewVendorService(vendorRepository, logger)
+	fundService := services.NewFundService(fundRepository, logger)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepository, vendorRepository, fundRepository, bookRepository, branchRepository, calendarService, loanRules, logger)
+	channels, noticeLog, err := noticeChannels(cfg.Notices, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to open notice log")
+	}
+	defer noticeLog.Close()
+	noticeRules := services.NoticeRules{
+		MaxAttempts:  cfg.Notices.MaxAttempts,
+		RetryBackoff: cfg.Notices.RetryBackoff,
+	}
+	noticeService := services.NewNoticeService(noticeRepository, memberRepository, calendarService, channels, noticeRules, logger)
 	urlService := services.NewURLService(logger)
 
+	if len(os.Args) > 1 && os.Args[1] == "purge-trash" {
+		if err := runPurgeTrash(bookService, cfg.Books); err != nil {
+			logger.WithError(err).Fatal("Failed to purge trash")
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "expire-holds" {
+		if err := runExpireHolds(holdService); err != nil {
+			logger.WithError(err).Fatal("Failed to expire holds")
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "accrue-fines" {
+		if err := runAccrueFines(fineService); err != nil {
+			logger.WithError(err).Fatal("Failed to accrue fines")
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "send-notices" {
+		if err := runSendNotices(noticeService); err != nil {
+			logger.WithError(err).Fatal("Failed to send notices")
+		}
+		return
+	}
+
 	bookHandler := handlers.NewBookHandler(bookService, validate, logger)
+	authorHandler := handlers.NewAuthorHandler(authorService, validate, logger)
+	genreHandler := handlers.NewGenreHandler(genreService, validate, logger)
+	itemHandler := handlers.NewItemHandler(itemService, validate, logger)
+	memberHandler := handlers.NewMemberHandler(memberService, validate, logger)
+	circulationHandler := handlers.NewCirculationHandler(circulationService, validate, logger)
+	holdHandler := handlers.NewHoldHandler(holdService, validate, logger)
+	fineHandler := handlers.NewFineHandler(fineService, validate, logger)
+	policyHandler := handlers.NewPolicyHandler(policyService, validate, logger)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, validate, logger)
+	noticeHandler := handlers.NewNoticeHandler(noticeService, validate, logger)
+	branchHandler := handlers.NewBranchHandler(branchService, validate, logger)
+	transferHandler := handlers.NewTransferHandler(transferService, validate, logger)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService, validate, logger)
+	vendorHandler := handlers.NewVendorHandler(vendorService, validate, logger)
+	fundHandler := handlers.NewFundHandler(fundService, validate, logger)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService, validate, logger)
 	urlHandler := handlers.NewURLHandler(urlService, validate, logger)
 
 	if cfg.Server.Mode == "production" {
@@ -80,19 +208,26 @@ func main() {
 	router.Use(middleware.ErrorHandler(logger))
 	router.Use(gin.Recovery())
 
-	api := router.Group("/api")
-	{
-		books := api.Group("/books")
-		{
-			books.GET("", bookHandler.GetBooks)
-			books.POST("", bookHandler.CreateBook)
-			books.GET("/:id", bookHandler.GetBook)
-			books.PUT("/:id", bookHandler.UpdateBook)
-			books.DELETE("/:id", bookHandler.DeleteBook)
-		}
-
-		api.POST("/url-process", urlHandler.ProcessURL)
-	}
+	handlers.RegisterRoutes(router.Group("/api"), handlers.Handlers{
+		Book:          bookHandler,
+		Author:        authorHandler,
+		Genre:         genreHandler,
+		Item:          itemHandler,
+		Member:        memberHandler,
+		Circulation:   circulationHandler,
+		Hold:          holdHandler,
+		Fine:          fineHandler,
+		Policy:        policyHandler,
+		Calendar:      calendarHandler,
+		Notice:        noticeHandler,
+		Branch:        branchHandler,
+		Transfer:      transferHandler,
+		Inventory:     inventoryHandler,
+		Vendor:        vendorHandler,
+		Fund:          fundHandler,
+		PurchaseOrder: purchaseOrderHandler,
+		URL:           urlHandler,
+	})
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -100,6 +235,11 @@ func main() {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	go purgeTrashPeriodically(bookService, cfg.Books, logger)
+	go expireHoldsPeriodically(holdService, cfg.Circulation, logger)
+	go accrueFinesPeriodically(fineService, cfg.Fines, logger)
+	go sendNoticesPeriodically(noticeService, cfg.Notices, logger)
+
 	logger.WithField("port", cfg.Server.Port).Info("Starting server")
 	if err := router.Run(":" + cfg.Server.Port); err != nil {
 		logger.WithError(err).Fatal("Failed to start server")
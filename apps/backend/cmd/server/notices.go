@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+	"library-management-backend/pkg/config"
+	"library-management-backend/pkg/notify"
+
+	"github.com/sirupsen/logrus"
+)
+
+// noticeChannels builds the notifier for each notice channel. Without an
+// SMTP host, email notices go to the notice log, so that development needs
+// no mail server. The returned closer closes the notice log.
+func noticeChannels(cfg config.NoticesConfig, logger *logrus.Logger) (map[string]notify.Notifier, io.Closer, error) {
+	var out io.WriteCloser = nopCloser{os.Stdout}
+	if cfg.LogPath != "" {
+		file, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = file
+	}
+	log := notify.NewLogNotifier(out)
+
+	channels := map[string]notify.Notifier{
+		models.ChannelEmail: log,
+		models.ChannelLog:   log,
+	}
+	if cfg.SMTPHost != "" {
+		channels[models.ChannelEmail] = notify.NewSMTPNotifier(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		})
+	} else {
+		logger.Warn("SMTP_HOST is not set; email notices are written to the notice log")
+	}
+	return channels, out, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// runSendNotices implements the "send-notices" subcommand.
+func runSendNotices(noticeService *services.NoticeService) error {
+	queued, sent, err := noticeService.SendNotices()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "queued %d notices and sent %d\n", queued, sent)
+	return nil
+}
+
+// sendNoticesPeriodically queues and sends notices every Interval until the
+// process exits. Failures are logged by the service and retried on the next
+// tick.
+func sendNoticesPeriodically(noticeService *services.NoticeService, cfg config.NoticesConfig, logger *logrus.Logger) {
+	if cfg.Interval <= 0 {
+		logger.Info("Background notice dispatch is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		noticeService.SendNotices()
+		<-ticker.C
+	}
+}
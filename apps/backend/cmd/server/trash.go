@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"library-management-backend/internal/services"
+	"library-management-backend/pkg/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runPurgeTrash implements the "purge-trash" subcommand.
+func runPurgeTrash(bookService *services.BookService, cfg config.BooksConfig) error {
+	purged, err := bookService.PurgeTrash(cfg.TrashRetention)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "purged %d books deleted more than %s ago\n", purged, cfg.TrashRetention)
+	return nil
+}
+
+// purgeTrashPeriodically purges the trash every TrashPurgeInterval until the
+// process exits. Failures are logged by the service and retried on the next
+// tick.
+func purgeTrashPeriodically(bookService *services.BookService, cfg config.BooksConfig, logger *logrus.Logger) {
+	if cfg.TrashPurgeInterval <= 0 {
+		logger.Info("Background trash purge is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		bookService.PurgeTrash(cfg.TrashRetention)
+		<-ticker.C
+	}
+}
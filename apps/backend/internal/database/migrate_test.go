@@ -0,0 +1,269 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = fstest.MapFS{
+	"migrations/0001_create_books.up.sql":   {Data: []byte("CREATE TABLE books (id UUID PRIMARY KEY);")},
+	"migrations/0001_create_books.down.sql": {Data: []byte("DROP TABLE books;")},
+	"migrations/0002_add_genre.up.sql":      {Data: []byte("ALTER TABLE books ADD COLUMN genre TEXT;")},
+	"migrations/0002_add_genre.down.sql":    {Data: []byte("ALTER TABLE books DROP COLUMN genre;")},
+	"migrations/0010_add_language.up.sql":   {Data: []byte("ALTER TABLE books ADD COLUMN language TEXT;")},
+	"migrations/0010_add_language.down.sql": {Data: []byte("ALTER TABLE books DROP COLUMN language;")},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	migrator, err := newMigratorFromFS(db, testMigrations, "migrations", logger)
+	require.NoError(t, err)
+
+	return migrator, mock
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).
+		WithArgs(migrationLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS schema_migrations")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).
+		WithArgs(migrationLockID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("embedded migrations", func(t *testing.T) {
+		migrations, err := loadMigrations(embeddedMigrations, "migrations")
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+		assert.Equal(t, int64(1), migrations[0].Version)
+		assert.Equal(t, "create_books", migrations[0].Name)
+		assert.Contains(t, migrations[0].Up, "CREATE TABLE IF NOT EXISTS books")
+		// The first migration must match the schema of the original
+		// init.sql, which it skips on databases created from it.
+		assert.NotContains(t, migrations[0].Up, "language")
+		assert.NotContains(t, migrations[0].Up, "INDEX")
+	})
+
+	t.Run("ordered numerically", func(t *testing.T) {
+		migrations, err := loadMigrations(testMigrations, "migrations")
+		require.NoError(t, err)
+		require.Len(t, migrations, 3)
+		assert.Equal(t, []int64{1, 2, 10}, []int64{migrations[0].Version, migrations[1].Version, migrations[2].Version})
+	})
+
+	t.Run("missing down file", func(t *testing.T) {
+		_, err := loadMigrations(fstest.MapFS{
+			"migrations/0001_create_books.up.sql": {Data: []byte("SELECT 1;")},
+		}, "migrations")
+		assert.EqualError(t, err, "migration 0001_create_books must have both up and down files")
+	})
+
+	t.Run("invalid file name", func(t *testing.T) {
+		_, err := loadMigrations(fstest.MapFS{
+			"migrations/create_books.sql": {Data: []byte("SELECT 1;")},
+		}, "migrations")
+		assert.EqualError(t, err, "invalid migration file name: create_books.sql")
+	})
+
+	t.Run("conflicting versions", func(t *testing.T) {
+		_, err := loadMigrations(fstest.MapFS{
+			"migrations/0001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"migrations/0001_a.down.sql": {Data: []byte("SELECT 1;")},
+			"migrations/0001_b.up.sql":   {Data: []byte("SELECT 1;")},
+		}, "migrations")
+		assert.Error(t, err)
+	})
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Run("applies pending migrations in order", func(t *testing.T) {
+		migrator, mock := newTestMigrator(t)
+
+		expectLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+		for _, m := range []struct {
+			version int64
+			name    string
+			script  string
+		}{
+			{2, "add_genre", "ALTER TABLE books ADD COLUMN genre TEXT;"},
+			{10, "add_language", "ALTER TABLE books ADD COLUMN language TEXT;"},
+		} {
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta(m.script)).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)")).
+				WithArgs(m.version, m.name).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+		}
+		expectUnlock(mock)
+
+		applied, err := migrator.Up(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed migration is rolled back", func(t *testing.T) {
+		migrator, mock := newTestMigrator(t)
+
+		expectLock(mock)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE books (id UUID PRIMARY KEY);")).
+			WillReturnError(errors.New("syntax error"))
+		mock.ExpectRollback()
+		expectUnlock(mock)
+
+		applied, err := migrator.Up(context.Background())
+		assert.EqualError(t, err, "failed to apply migration 0001_create_books: syntax error")
+		assert.Equal(t, 0, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("lock failure", func(t *testing.T) {
+		migrator, mock := newTestMigrator(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).
+			WithArgs(migrationLockID).
+			WillReturnError(errors.New("connection reset"))
+
+		_, err := migrator.Up(context.Background())
+		assert.EqualError(t, err, "failed to acquire migration lock: connection reset")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+
+	expectLock(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).
+			AddRow(1, time.Now()).
+			AddRow(2, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("ALTER TABLE books DROP COLUMN genre;")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	rolledBack, err := migrator.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rolledBack)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	appliedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	expectLock(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, applied_at FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, appliedAt))
+	expectUnlock(mock)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 3)
+	assert.Equal(t, &appliedAt, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+	assert.Equal(t, "add_language", statuses[2].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// baselineSchema is the init.sql the first deployments were created from,
+// before the schema was managed by migrations.
+const baselineSchema = `CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE books (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(255) NOT NULL,
+    author VARCHAR(255) NOT NULL,
+    year INT NOT NULL,
+    description TEXT,
+    isbn VARCHAR(20),
+    genre VARCHAR(100),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO books (title, author, year, description, isbn, genre) VALUES
+('The Great Gatsby', 'F. Scott Fitzgerald', 1925, 'The story of the fabulously wealthy Jay Gatsby and his love for the beautiful Daisy Buchanan.', '978-0743273565', 'Tragedy'),
+('To Kill a Mockingbird', 'Harper Lee', 1960, 'The story of a young girl, Scout Finch, and her lawyer father, Atticus, in the American South.', '978-0061120084', 'Southern Gothic'),
+('1984', 'George Orwell', 1949, 'A dystopian novel set in Airstrip One, a province of the superstate Oceania in a world of perpetual war.', '978-0451524935', 'Dystopian');`
+
+// TestMigrator_UpFromBaselineSchema applies every migration to a database
+// created from the original init.sql. It needs a PostgreSQL server, given
+// by TEST_DATABASE_URL, and works in a schema of its own that it drops
+// afterwards.
+func TestMigrator_UpFromBaselineSchema(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	// A single connection keeps the search path set below for every
+	// statement, including the migrator's.
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("migrate_baseline_%d", time.Now().UnixNano())
+	_, err = db.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+	})
+	_, err = db.ExecContext(ctx, "SET search_path TO "+schema+", public")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, baselineSchema)
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	migrator, err := NewMigrator(db, logger)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, len(migrator.migrations), applied)
+
+	var matches int
+	err = db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM books WHERE language = 'english' AND search_vector @@ websearch_to_tsquery('english', 'gatsby')").
+		Scan(&matches)
+	require.NoError(t, err)
+	assert.Equal(t, 1, matches)
+}
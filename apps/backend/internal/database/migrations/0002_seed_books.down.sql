@@ -0,0 +1 @@
+DELETE FROM books WHERE isbn IN ('978-0743273565', '978-0061120084', '978-0451524935');
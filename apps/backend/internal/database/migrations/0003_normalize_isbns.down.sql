@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_books_isbn;
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_isbn_canonical;
+
+UPDATE books
+SET isbn = rejected_isbns.isbn
+FROM rejected_isbns
+WHERE books.id = rejected_isbns.book_id AND books.isbn IS NULL;
+
+DROP TABLE IF EXISTS rejected_isbns;
@@ -0,0 +1,71 @@
+-- Rewrites every ISBN to its canonical unhyphenated ISBN-13 form and makes
+-- ISBNs unique. Values that are not valid ISBNs, and later duplicates of an
+-- ISBN, are moved to rejected_isbns so they can be reviewed by hand.
+CREATE FUNCTION pg_temp.normalize_isbn(raw TEXT) RETURNS TEXT AS $$
+DECLARE
+    code TEXT := upper(regexp_replace(raw, '^\s*ISBN(-1[03])?:?|[\s-]', '', 'gi'));
+    total INT := 0;
+    i INT;
+BEGIN
+    IF code ~ '^[0-9]{9}[0-9X]$' THEN
+        FOR i IN 1..10 LOOP
+            total := total + (11 - i) * CASE WHEN substr(code, i, 1) = 'X' THEN 10 ELSE substr(code, i, 1)::INT END;
+        END LOOP;
+        IF total % 11 <> 0 THEN
+            RETURN NULL;
+        END IF;
+        code := '978' || substr(code, 1, 9);
+        total := 0;
+        FOR i IN 1..12 LOOP
+            total := total + substr(code, i, 1)::INT * CASE WHEN i % 2 = 1 THEN 1 ELSE 3 END;
+        END LOOP;
+        RETURN code || ((10 - total % 10) % 10)::TEXT;
+    END IF;
+
+    IF code ~ '^97[89][0-9]{10}([0-9]{2}|[0-9]{5})?$' THEN
+        code := substr(code, 1, 13);
+        FOR i IN 1..13 LOOP
+            total := total + substr(code, i, 1)::INT * CASE WHEN i % 2 = 1 THEN 1 ELSE 3 END;
+        END LOOP;
+        IF total % 10 <> 0 THEN
+            RETURN NULL;
+        END IF;
+        RETURN code;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE TABLE rejected_isbns (
+    book_id UUID NOT NULL,
+    isbn VARCHAR(20) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    rejected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO rejected_isbns (book_id, isbn, reason)
+SELECT id, isbn, 'invalid'
+FROM books
+WHERE isbn IS NOT NULL AND pg_temp.normalize_isbn(isbn) IS NULL;
+
+WITH ranked AS (
+    SELECT id, isbn,
+           ROW_NUMBER() OVER (PARTITION BY pg_temp.normalize_isbn(isbn) ORDER BY created_at, id) AS position
+    FROM books
+    WHERE pg_temp.normalize_isbn(isbn) IS NOT NULL
+)
+INSERT INTO rejected_isbns (book_id, isbn, reason)
+SELECT id, isbn, 'duplicate'
+FROM ranked
+WHERE position > 1;
+
+UPDATE books
+SET isbn = CASE
+        WHEN id IN (SELECT book_id FROM rejected_isbns) THEN NULL
+        ELSE pg_temp.normalize_isbn(isbn)
+    END
+WHERE isbn IS NOT NULL;
+
+ALTER TABLE books ADD CONSTRAINT books_isbn_canonical CHECK (isbn ~ '^97[89][0-9]{10}$');
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn);
@@ -0,0 +1,2 @@
+ALTER TABLE books DROP CONSTRAINT IF EXISTS books_version_positive;
+ALTER TABLE books DROP COLUMN IF EXISTS version;
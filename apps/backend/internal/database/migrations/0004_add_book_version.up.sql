@@ -0,0 +1,4 @@
+-- Every write to a book bumps its version, which is exposed as the book's
+-- ETag so concurrent edits can be detected with If-Match.
+ALTER TABLE books ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE books ADD CONSTRAINT books_version_positive CHECK (version > 0);
@@ -0,0 +1,9 @@
+-- Without deleted_at trashed books would come back to life, so they are
+-- removed for good.
+DELETE FROM books WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_books_isbn;
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn);
+
+DROP INDEX IF EXISTS idx_books_trash;
+ALTER TABLE books DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,10 @@
+-- Deleting a book moves it to the trash by setting deleted_at; trashed books
+-- are purged once they are older than the configured retention window.
+ALTER TABLE books ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_books_trash ON books (deleted_at DESC, id DESC) WHERE deleted_at IS NOT NULL;
+
+-- Trashed books keep their ISBN, so only live books must have unique ones.
+-- Restoring a book whose ISBN has been reused fails instead.
+DROP INDEX IF EXISTS idx_books_isbn;
+CREATE UNIQUE INDEX idx_books_isbn ON books (isbn) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+-- books.author still holds every author-role contributor, so only editors,
+-- translators and illustrators are lost.
+DROP TABLE IF EXISTS book_contributors;
+DROP TABLE IF EXISTS authors;
+DROP FUNCTION IF EXISTS author_name_key(TEXT);
@@ -0,0 +1,67 @@
+-- author_name_key folds the spellings of a name that denote the same person
+-- onto one key: case, punctuation other than commas and the order of the
+-- words are ignored, so "J.R.R. Tolkien" and "Tolkien, J.R.R." both become
+-- "jrr tolkien". The repositories rely on it to find authors by name.
+CREATE OR REPLACE FUNCTION author_name_key(name TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(token, ' ' ORDER BY token COLLATE "C"), '')
+    FROM regexp_split_to_table(
+        lower(regexp_replace(name, '[^[:alnum:][:space:],]', '', 'g')),
+        '[[:space:],]+'
+    ) AS token
+    WHERE token <> ''
+$$ LANGUAGE SQL IMMUTABLE STRICT;
+
+CREATE TABLE IF NOT EXISTS authors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    sort_name VARCHAR(255) NOT NULL,
+    name_key VARCHAR(255) NOT NULL,
+    birth_year INT,
+    death_year INT,
+    biography TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_name_key ON authors (name_key);
+CREATE INDEX IF NOT EXISTS idx_authors_sort_name ON authors (sort_name, id);
+
+-- A book's contributors in credit order. books.author is kept as the display
+-- string of the author-role contributors.
+CREATE TABLE IF NOT EXISTS book_contributors (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES authors (id) ON DELETE RESTRICT,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('author', 'editor', 'translator', 'illustrator')),
+    position INT NOT NULL CHECK (position >= 0),
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_contributors_author ON book_contributors (author_id);
+
+-- Split the existing author strings the way citations do, on ";", "&" and
+-- "and", and credit each name as an author.
+CREATE TEMPORARY TABLE book_author_names ON COMMIT DROP AS
+SELECT b.id AS book_id, trim(part.name) AS name, part.position - 1 AS position
+FROM books b,
+     regexp_split_to_table(trim(b.author), '\s*(?:;|\s&\s|\sand\s)\s*') WITH ORDINALITY AS part (name, position)
+WHERE author_name_key(part.name) <> '';
+
+-- The first spelling of a name becomes the author's name. The sort name
+-- inverts "Given Family" names; inverted and single-word names are kept.
+INSERT INTO authors (name, sort_name, name_key)
+SELECT DISTINCT ON (author_name_key(name))
+       name,
+       CASE WHEN name LIKE '%,%' OR name !~ '\s' THEN name
+            ELSE regexp_replace(name, '^(.*\S)\s+(\S+)$', '\2, \1')
+       END,
+       author_name_key(name)
+FROM book_author_names
+ORDER BY author_name_key(name), position, name
+ON CONFLICT (name_key) DO NOTHING;
+
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT DISTINCT ON (n.book_id, a.id) n.book_id, a.id, 'author', n.position
+FROM book_author_names n
+JOIN authors a ON a.name_key = author_name_key(n.name)
+ORDER BY n.book_id, a.id, n.position
+ON CONFLICT DO NOTHING;
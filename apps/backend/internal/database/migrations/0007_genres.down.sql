@@ -0,0 +1,6 @@
+-- books.genre still holds each book's primary genre; secondary genres, the
+-- tree and aliases are lost.
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS genre_aliases;
+DROP TABLE IF EXISTS genres;
+DROP FUNCTION IF EXISTS genre_name_key(TEXT);
@@ -0,0 +1,65 @@
+-- genre_name_key ignores case and everything but letters and digits, so
+-- "Sci-Fi", "sci fi" and "SciFi" name the same genre.
+CREATE OR REPLACE FUNCTION genre_name_key(name TEXT) RETURNS TEXT AS $$
+    SELECT lower(regexp_replace(name, '[^[:alnum:]]', '', 'g'))
+$$ LANGUAGE SQL IMMUTABLE STRICT;
+
+CREATE TABLE IF NOT EXISTS genres (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    name_key VARCHAR(100) NOT NULL,
+    parent_id UUID REFERENCES genres (id) ON DELETE RESTRICT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT genres_not_own_parent CHECK (parent_id <> id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_genres_name_key ON genres (name_key);
+CREATE INDEX IF NOT EXISTS idx_genres_parent ON genres (parent_id);
+
+-- Other names a genre is found by. The repositories keep alias keys distinct
+-- from the name keys of other genres.
+CREATE TABLE IF NOT EXISTS genre_aliases (
+    name_key VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    genre_id UUID NOT NULL REFERENCES genres (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_genre_aliases_genre ON genre_aliases (genre_id);
+
+-- The genres of a book by position. books.genre is kept as the name of the
+-- first one.
+CREATE TABLE IF NOT EXISTS book_genres (
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    genre_id UUID NOT NULL REFERENCES genres (id) ON DELETE RESTRICT,
+    position INT NOT NULL CHECK (position >= 0),
+    PRIMARY KEY (book_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_genres_genre ON book_genres (genre_id);
+
+-- Every distinct genre string becomes a top-level genre named after its most
+-- common spelling; rearranging them into a tree and merging synonyms is left
+-- to the genres API.
+INSERT INTO genres (name, name_key)
+SELECT DISTINCT ON (genre_name_key(genre)) genre, genre_name_key(genre)
+FROM (
+    SELECT trim(genre) AS genre, COUNT(*) AS uses
+    FROM books
+    WHERE genre_name_key(genre) <> ''
+    GROUP BY trim(genre)
+) AS spellings
+ORDER BY genre_name_key(genre), uses DESC, genre
+ON CONFLICT (name_key) DO NOTHING;
+
+INSERT INTO book_genres (book_id, genre_id, position)
+SELECT b.id, g.id, 0
+FROM books b
+JOIN genres g ON g.name_key = genre_name_key(b.genre)
+ON CONFLICT DO NOTHING;
+
+UPDATE books b
+SET genre = g.name
+FROM book_genres bg
+JOIN genres g ON g.id = bg.genre_id
+WHERE bg.book_id = b.id AND b.genre IS DISTINCT FROM g.name;
@@ -0,0 +1,20 @@
+-- Physical copies of books. Purging a book removes its copies with it.
+CREATE TABLE IF NOT EXISTS items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    barcode VARCHAR(50) NOT NULL,
+    location VARCHAR(100),
+    acquired_on DATE,
+    price_cents INT CHECK (price_cents >= 0),
+    condition VARCHAR(20) NOT NULL DEFAULT 'good'
+        CHECK (condition IN ('new', 'good', 'fair', 'poor', 'damaged')),
+    status VARCHAR(20) NOT NULL DEFAULT 'available'
+        CHECK (status IN ('available', 'on_loan', 'in_transit', 'lost', 'withdrawn')),
+    status_note VARCHAR(255),
+    status_changed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_barcode ON items (barcode);
+CREATE INDEX IF NOT EXISTS idx_items_book_status ON items (book_id, status);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS members;
+DROP SEQUENCE IF EXISTS member_card_serial_seq;
@@ -0,0 +1,24 @@
+-- Registered patrons. Card numbers are generated from the serial sequence.
+CREATE SEQUENCE IF NOT EXISTS member_card_serial_seq;
+
+CREATE TABLE IF NOT EXISTS members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    card_number VARCHAR(20) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(50),
+    address VARCHAR(500),
+    membership_type VARCHAR(20) NOT NULL
+        CHECK (membership_type IN ('adult', 'child', 'student', 'senior', 'staff')),
+    expires_on DATE NOT NULL,
+    blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    blocked_reason VARCHAR(255),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_members_card_number ON members (card_number);
+CREATE INDEX IF NOT EXISTS idx_members_name ON members (last_name, first_name, id);
+CREATE INDEX IF NOT EXISTS idx_members_email ON members (lower(email));
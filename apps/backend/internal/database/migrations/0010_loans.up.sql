@@ -0,0 +1,17 @@
+-- Loans of copies to members, kept after return as the loan history. A
+-- member with loan history cannot be deleted.
+CREATE TABLE IF NOT EXISTS loans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    member_id UUID NOT NULL REFERENCES members (id),
+    due_on DATE NOT NULL,
+    renewals INT NOT NULL DEFAULT 0 CHECK (renewals >= 0),
+    checked_out_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_renewed_at TIMESTAMP WITH TIME ZONE,
+    returned_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A copy has at most one active loan.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_item ON loans (item_id) WHERE returned_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_loans_member ON loans (member_id, checked_out_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loans_item ON loans (item_id, checked_out_at DESC);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS holds;
+
+UPDATE items SET status = 'available' WHERE status = 'on_hold';
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_status_check;
+ALTER TABLE items ADD CONSTRAINT items_status_check
+    CHECK (status IN ('available', 'on_loan', 'in_transit', 'lost', 'withdrawn'));
@@ -0,0 +1,33 @@
+-- Holds queue members for a book, either for any copy (title level) or for
+-- one copy (item level). A ready hold has a copy trapped on the hold shelf,
+-- whose status is on_hold, until its pickup date.
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_status_check;
+ALTER TABLE items ADD CONSTRAINT items_status_check
+    CHECK (status IN ('available', 'on_loan', 'on_hold', 'in_transit', 'lost', 'withdrawn'));
+
+CREATE TABLE IF NOT EXISTS holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    book_id UUID NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    member_id UUID NOT NULL REFERENCES members (id),
+    item_id UUID REFERENCES items (id) ON DELETE CASCADE,
+    level VARCHAR(10) NOT NULL CHECK (level IN ('title', 'item')),
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    -- Waiting holds are served in queue_rank order.
+    queue_rank BIGINT NOT NULL,
+    suspended_until DATE,
+    pickup_by DATE,
+    placed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ready_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A member has at most one active hold on a book, and a copy is trapped for
+-- at most one hold.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_active_member ON holds (book_id, member_id)
+    WHERE status IN ('waiting', 'ready');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_ready_item ON holds (item_id) WHERE status = 'ready';
+CREATE INDEX IF NOT EXISTS idx_holds_queue ON holds (book_id, queue_rank) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_holds_member ON holds (member_id, placed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_holds_pickup ON holds (pickup_by) WHERE status = 'ready';
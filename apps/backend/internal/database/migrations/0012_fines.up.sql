@@ -0,0 +1,17 @@
+-- The fines ledger of each member. Entries are only ever added: charges and
+-- refunds add to the balance, payments and waivers take from it. A member
+-- with entries cannot be deleted; an entry outlives the loan it is for.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    member_id UUID NOT NULL REFERENCES members (id),
+    loan_id UUID REFERENCES loans (id) ON DELETE SET NULL,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('charge', 'payment', 'waiver', 'refund')),
+    category VARCHAR(20) CHECK (category IN ('overdue', 'lost_item', 'processing', 'other')),
+    amount_cents INT NOT NULL CHECK (amount_cents > 0),
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((kind = 'charge') = (category IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_member ON ledger_entries (member_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_overdue ON ledger_entries (loan_id) WHERE category = 'overdue';
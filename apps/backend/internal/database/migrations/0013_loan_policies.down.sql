@@ -0,0 +1,3 @@
+ALTER TABLE loans DROP COLUMN IF EXISTS max_fine_cents;
+ALTER TABLE loans DROP COLUMN IF EXISTS daily_fine_cents;
+DROP TABLE IF EXISTS loan_policies;
@@ -0,0 +1,25 @@
+-- Rules setting the loan terms by membership type and genre. A rule without
+-- a membership type or genre matches every member or book; there is at most
+-- one rule for each combination.
+CREATE TABLE IF NOT EXISTS loan_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    membership_type VARCHAR(20) CHECK (membership_type IN ('adult', 'child', 'student', 'senior', 'staff')),
+    genre_id UUID REFERENCES genres (id) ON DELETE CASCADE,
+    priority INT NOT NULL DEFAULT 0,
+    loan_days INT CHECK (loan_days > 0),
+    max_renewals INT CHECK (max_renewals >= 0),
+    daily_fine_cents INT CHECK (daily_fine_cents >= 0),
+    max_fine_cents INT CHECK (max_fine_cents >= 0),
+    holds_allowed BOOLEAN,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loan_policies_match
+    ON loan_policies (COALESCE(membership_type, ''), COALESCE(genre_id, '00000000-0000-0000-0000-000000000000'));
+
+-- The overdue fine rates a loan was checked out on. Loans made before
+-- policies accrue at the configured rates.
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS daily_fine_cents INT CHECK (daily_fine_cents >= 0);
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS max_fine_cents INT CHECK (max_fine_cents >= 0);
@@ -0,0 +1,23 @@
+-- The weekly opening hours and the days the library is closed, in the
+-- library's time zone. A weekday without hours is closed, unless no hours
+-- are set at all. An annual closure recurs every year on the same days.
+CREATE TABLE IF NOT EXISTS opening_hours (
+    weekday SMALLINT PRIMARY KEY CHECK (weekday BETWEEN 0 AND 6),
+    opens TIME NOT NULL,
+    closes TIME NOT NULL,
+    CHECK (opens < closes)
+);
+
+CREATE TABLE IF NOT EXISTS closures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    annual BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_on >= starts_on),
+    CHECK (NOT annual OR ends_on < starts_on + INTERVAL '1 year')
+);
+
+CREATE INDEX IF NOT EXISTS idx_closures_dates ON closures (starts_on, ends_on);
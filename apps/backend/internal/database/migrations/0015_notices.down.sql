@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notice_preferences;
+DROP TABLE IF EXISTS notices;
@@ -0,0 +1,38 @@
+-- The notices outbox. Overdue and hold ready notices are queued here, one
+-- per channel, and sent with retries by the notice dispatcher; sent notices
+-- stay as the member's notices history. event_key names the loan or hold a
+-- notice is about, so that each is noticed once per channel.
+CREATE TABLE IF NOT EXISTS notices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    member_id UUID NOT NULL REFERENCES members (id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('overdue', 'hold_ready')),
+    event_key VARCHAR(100) NOT NULL,
+    -- Unset on a notice skipped because the member turned its kind off.
+    channel VARCHAR(10) CHECK (channel IN ('email', 'log')),
+    loan_id UUID REFERENCES loans (id) ON DELETE SET NULL,
+    hold_id UUID REFERENCES holds (id) ON DELETE SET NULL,
+    recipient VARCHAR(255),
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'sent', 'failed', 'skipped')),
+    attempts INT NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((status = 'pending') = (next_attempt_at IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notices_event ON notices (event_key, COALESCE(channel, ''));
+CREATE INDEX IF NOT EXISTS idx_notices_member ON notices (member_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notices_due ON notices (next_attempt_at) WHERE status = 'pending';
+
+-- The channels each member is sent each kind of notice over. Members
+-- without a row are sent both kinds by email.
+CREATE TABLE IF NOT EXISTS notice_preferences (
+    member_id UUID PRIMARY KEY REFERENCES members (id) ON DELETE CASCADE,
+    overdue VARCHAR(10)[] NOT NULL,
+    hold_ready VARCHAR(10)[] NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
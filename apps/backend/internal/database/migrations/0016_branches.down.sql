@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS transfers;
+
+-- Copies trapped for in-transit holds go back to the queue.
+UPDATE holds SET status = 'waiting', item_id = CASE WHEN level = 'item' THEN item_id END
+    WHERE status = 'in_transit';
+DROP INDEX IF EXISTS idx_holds_ready_item;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_ready_item ON holds (item_id) WHERE status = 'ready';
+DROP INDEX IF EXISTS idx_holds_active_member;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_active_member ON holds (book_id, member_id)
+    WHERE status IN ('waiting', 'ready');
+ALTER TABLE holds DROP CONSTRAINT IF EXISTS holds_status_check;
+ALTER TABLE holds ADD CONSTRAINT holds_status_check
+    CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired'));
+ALTER TABLE holds DROP COLUMN IF EXISTS pickup_branch_id;
+
+ALTER TABLE loans DROP COLUMN IF EXISTS branch_id;
+
+DROP INDEX IF EXISTS idx_items_current_branch;
+ALTER TABLE items DROP COLUMN IF EXISTS current_branch_id;
+ALTER TABLE items DROP COLUMN IF EXISTS home_branch_id;
+
+DROP TABLE IF EXISTS branches;
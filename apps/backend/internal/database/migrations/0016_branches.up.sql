@@ -0,0 +1,55 @@
+-- Branches are the library's locations. A copy has a home branch it belongs
+-- to and a current branch it was last at; copies added before branches have
+-- neither and circulate as before. Codes are stored in upper case.
+CREATE TABLE IF NOT EXISTS branches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    address VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_branches_code ON branches (code);
+
+ALTER TABLE items ADD COLUMN IF NOT EXISTS home_branch_id UUID REFERENCES branches (id);
+ALTER TABLE items ADD COLUMN IF NOT EXISTS current_branch_id UUID REFERENCES branches (id);
+CREATE INDEX IF NOT EXISTS idx_items_current_branch ON items (current_branch_id, book_id);
+
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES branches (id);
+
+-- An in-transit hold has a copy trapped for it on its way to the pickup
+-- branch. Like a ready hold it keeps the copy from other holds.
+ALTER TABLE holds ADD COLUMN IF NOT EXISTS pickup_branch_id UUID REFERENCES branches (id);
+ALTER TABLE holds DROP CONSTRAINT IF EXISTS holds_status_check;
+ALTER TABLE holds ADD CONSTRAINT holds_status_check
+    CHECK (status IN ('waiting', 'in_transit', 'ready', 'fulfilled', 'cancelled', 'expired'));
+DROP INDEX IF EXISTS idx_holds_active_member;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_active_member ON holds (book_id, member_id)
+    WHERE status IN ('waiting', 'in_transit', 'ready');
+DROP INDEX IF EXISTS idx_holds_ready_item;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_ready_item ON holds (item_id) WHERE status IN ('in_transit', 'ready');
+
+-- Transfers move copies between branches. A copy has at most one open
+-- transfer, requested or in transit; closed transfers are kept as history.
+CREATE TABLE IF NOT EXISTS transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES items (id) ON DELETE CASCADE,
+    from_branch_id UUID NOT NULL REFERENCES branches (id),
+    to_branch_id UUID NOT NULL REFERENCES branches (id),
+    hold_id UUID REFERENCES holds (id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested'
+        CHECK (status IN ('requested', 'in_transit', 'received', 'cancelled')),
+    note VARCHAR(255),
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_branch_id <> to_branch_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfers_open_item ON transfers (item_id)
+    WHERE status IN ('requested', 'in_transit');
+CREATE INDEX IF NOT EXISTS idx_transfers_from ON transfers (from_branch_id, requested_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transfers_to ON transfers (to_branch_id, requested_at DESC);
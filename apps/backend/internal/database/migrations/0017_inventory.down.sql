@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_items_location;
+DROP TABLE IF EXISTS inventory_scans;
+DROP TABLE IF EXISTS inventory_sessions;
@@ -0,0 +1,26 @@
+-- Inventory sessions record a stocktake of a branch, a range of shelves or
+-- both. Each barcode scanned in a session is kept once; the report compares
+-- the scans with the copies the session covers when it is asked for.
+CREATE TABLE IF NOT EXISTS inventory_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    branch_id UUID REFERENCES branches (id),
+    location_from VARCHAR(100),
+    location_to VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    note VARCHAR(255),
+    opened_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_sessions_opened ON inventory_sessions (opened_at DESC);
+
+CREATE TABLE IF NOT EXISTS inventory_scans (
+    session_id UUID NOT NULL REFERENCES inventory_sessions (id) ON DELETE CASCADE,
+    barcode VARCHAR(50) NOT NULL,
+    scanned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, barcode)
+);
+
+-- Shelf ranges compare locations byte by byte.
+CREATE INDEX IF NOT EXISTS idx_items_location ON items (location COLLATE "C");
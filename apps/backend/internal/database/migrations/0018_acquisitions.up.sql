@@ -0,0 +1,58 @@
+-- Vendors supply the copies the library buys.
+CREATE TABLE IF NOT EXISTS vendors (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(30),
+    address VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Funds are the budgets purchases are paid from, one per code and fiscal
+-- year. Codes are stored in upper case.
+CREATE TABLE IF NOT EXISTS funds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    fiscal_year INT NOT NULL,
+    budget_cents INT NOT NULL CHECK (budget_cents >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_funds_year_code ON funds (fiscal_year, code);
+
+-- Purchase orders are drafted, placed with their vendor and received, in
+-- one delivery or several, until every line has arrived.
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vendor_id UUID NOT NULL REFERENCES vendors (id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'ordered', 'partially_received', 'received', 'cancelled')),
+    note VARCHAR(255),
+    ordered_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_created ON purchase_orders (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_vendor ON purchase_orders (vendor_id);
+
+-- Each line orders copies of a catalog record, paid from a fund. The title
+-- is kept so the line still reads once the record has been purged.
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES purchase_orders (id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    book_id UUID REFERENCES books (id) ON DELETE SET NULL,
+    title VARCHAR(255) NOT NULL,
+    fund_id UUID NOT NULL REFERENCES funds (id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_price_cents INT NOT NULL CHECK (unit_price_cents >= 0),
+    received_quantity INT NOT NULL DEFAULT 0 CHECK (received_quantity BETWEEN 0 AND quantity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_order ON purchase_order_lines (order_id, position);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_fund ON purchase_order_lines (fund_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_books_search_vector;
+DROP INDEX IF EXISTS idx_books_year;
+DROP INDEX IF EXISTS idx_books_author;
+DROP INDEX IF EXISTS idx_books_title;
+DROP INDEX IF EXISTS idx_books_created_at;
+
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE books DROP COLUMN IF EXISTS language;
@@ -0,0 +1,16 @@
+-- Indexes for the sort orders of the book list, and the language and
+-- weighted search vector used by full-text search. Databases created from
+-- the original init.sql have none of them.
+ALTER TABLE books ADD COLUMN IF NOT EXISTS language REGCONFIG NOT NULL DEFAULT 'english';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector(language, COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector(language, COALESCE(author, '')), 'A') ||
+    setweight(to_tsvector(language, COALESCE(genre, '')), 'B') ||
+    setweight(to_tsvector(language, COALESCE(description, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_books_created_at ON books (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_books_title ON books (title, id);
+CREATE INDEX IF NOT EXISTS idx_books_author ON books (author, id);
+CREATE INDEX IF NOT EXISTS idx_books_year ON books (year, id);
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN (search_vector);
@@ -0,0 +1,3 @@
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_item_id_fkey;
+ALTER TABLE loans ADD CONSTRAINT loans_item_id_fkey
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- Loans are the circulation history, so a copy that has been lent can no
+-- longer be removed: purging its book fails instead of erasing its loans.
+ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_item_id_fkey;
+ALTER TABLE loans ADD CONSTRAINT loans_item_id_fkey
+    FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE RESTRICT;
@@ -0,0 +1,13 @@
+DELETE FROM closures WHERE branch_id IS NOT NULL;
+ALTER TABLE closures DROP COLUMN IF EXISTS branch_id;
+
+DELETE FROM opening_hours WHERE branch_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_opening_hours_weekday;
+ALTER TABLE opening_hours DROP COLUMN IF EXISTS branch_id;
+ALTER TABLE opening_hours ADD PRIMARY KEY (weekday);
+
+DELETE FROM loan_policies WHERE branch_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_loan_policies_match;
+ALTER TABLE loan_policies DROP COLUMN IF EXISTS branch_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loan_policies_match
+    ON loan_policies (COALESCE(membership_type, ''), COALESCE(genre_id, '00000000-0000-0000-0000-000000000000'));
@@ -0,0 +1,16 @@
+-- Loan policies, opening hours and closures may be for one branch. A rule
+-- for a branch only matches loans and holds there. A branch with opening
+-- hours of its own keeps to them instead of the library's, and closes for
+-- its own closures as well as the library's. They go with their branch.
+ALTER TABLE loan_policies ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES branches (id) ON DELETE CASCADE;
+DROP INDEX IF EXISTS idx_loan_policies_match;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loan_policies_match
+    ON loan_policies (COALESCE(branch_id, '00000000-0000-0000-0000-000000000000'), COALESCE(membership_type, ''),
+    COALESCE(genre_id, '00000000-0000-0000-0000-000000000000'));
+
+ALTER TABLE opening_hours ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES branches (id) ON DELETE CASCADE;
+ALTER TABLE opening_hours DROP CONSTRAINT IF EXISTS opening_hours_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_opening_hours_weekday
+    ON opening_hours (COALESCE(branch_id, '00000000-0000-0000-0000-000000000000'), weekday);
+
+ALTER TABLE closures ADD COLUMN IF NOT EXISTS branch_id UUID REFERENCES branches (id) ON DELETE CASCADE;
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type AuthorHandler struct {
+	authorService *services.AuthorService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewAuthorHandler(authorService *services.AuthorService, validator *validator.Validate, logger *logrus.Logger) *AuthorHandler {
+	return &AuthorHandler{
+		authorService: authorService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// @Summary List authors
+// @Description Retrieve a page of authors ordered by sort name. Use next_cursor from the response to fetch the following page.
+// @Tags authors
+// @Produce json
+// @Param q query string false "Case-insensitive substring of the name or sort name"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.AuthorListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /authors [get]
+func (h *AuthorHandler) GetAuthors(c *gin.Context) {
+	var params models.AuthorListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	authors, err := h.authorService.ListAuthors(&params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "Invalid or expired cursor",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get authors")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve authors",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, authors)
+}
+
+// @Summary Get an author
+// @Description Retrieve a single author by ID
+// @Tags authors
+// @Produce json
+// @Param id path string true "Author ID"
+// @Success 200 {object} models.Author
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /authors/{id} [get]
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	author, err := h.authorService.GetAuthorByID(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrAuthorNotFound) {
+			writeAuthorNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get author")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve author",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, author)
+}
+
+// @Summary Create an author
+// @Description Create a new author. Names that only differ in case, punctuation or word order, such as "J.R.R. Tolkien" and "Tolkien, J.R.R.", belong to the same author.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param author body models.CreateAuthorRequest true "Author data"
+// @Success 201 {object} models.Author
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /authors [post]
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var req models.CreateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	author, err := h.authorService.CreateAuthor(&req)
+	if err != nil {
+		if h.handleAuthorError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create author")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create author",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, author)
+}
+
+// @Summary Update an author
+// @Description Update an existing author by ID. Renaming an author also renames them in the author field of every book crediting them.
+// @Tags authors
+// @Accept json
+// @Produce json
+// @Param id path string true "Author ID"
+// @Param author body models.UpdateAuthorRequest true "Updated author data"
+// @Success 200 {object} models.Author
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	var req models.UpdateAuthorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	author, err := h.authorService.UpdateAuthor(c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrAuthorNotFound) {
+			writeAuthorNotFound(c)
+			return
+		}
+		if h.handleAuthorError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update author")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update author",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, author)
+}
+
+// @Summary Delete an author
+// @Description Delete an author who is not credited on any book, including books in the trash
+// @Tags authors
+// @Param id path string true "Author ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	err := h.authorService.DeleteAuthor(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrAuthorNotFound) {
+			writeAuthorNotFound(c)
+			return
+		}
+		if errors.Is(err, services.ErrAuthorInUse) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "The author is credited on books; remove them from those books first",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete author")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete author",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary List an author's books
+// @Description Retrieve a page of the books an author contributed to, oldest first, with the roles they had on each
+// @Tags authors
+// @Produce json
+// @Param id path string true "Author ID"
+// @Param role query string false "Only books with this role" Enums(author, editor, translator, illustrator)
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of books to skip"
+// @Success 200 {object} models.AuthorBooksResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /authors/{id}/books [get]
+func (h *AuthorHandler) GetAuthorBooks(c *gin.Context) {
+	var params models.AuthorBooksParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	books, err := h.authorService.ListAuthorBooks(c.Param("id"), &params)
+	if err != nil {
+		if errors.Is(err, services.ErrAuthorNotFound) {
+			writeAuthorNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get author books")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve author books",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, books)
+}
+
+// handleAuthorError writes the response for the errors a create or update
+// shares, and reports whether err was one of them.
+func (h *AuthorHandler) handleAuthorError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrDuplicateAuthor):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "An author with this name already exists",
+		})
+	case errors.Is(err, services.ErrInvalidLifespan):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *AuthorHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
+
+func writeAuthorNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error:   "Not Found",
+		Message: "Author not found",
+	})
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAuthorHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	authorHandler := NewAuthorHandler(services.NewAuthorService(env.authors, env.logger), env.validate, env.logger)
+	return env.bookService(), env.router(Handlers{Book: env.bookHandler(), Author: authorHandler})
+}
+
+func TestAuthorHandler_CRUD(t *testing.T) {
+	_, router := setupAuthorHandler(t)
+
+	w := performRequest(router, http.MethodPost, "/authors", models.CreateAuthorRequest{Name: "Frank Herbert"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Author
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "Herbert, Frank", created.SortName)
+
+	w = performRequest(router, http.MethodPost, "/authors", models.CreateAuthorRequest{Name: "HERBERT, Frank"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/authors", models.CreateAuthorRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/authors?q=herb", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.AuthorListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+
+	birth := 1920
+	w = performRequest(router, http.MethodPut, "/authors/"+created.ID, models.UpdateAuthorRequest{Name: "Frank Patrick Herbert", BirthYear: &birth})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/authors/"+created.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var fetched models.Author
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, "Frank Patrick Herbert", fetched.Name)
+	assert.Equal(t, 1920, *fetched.BirthYear)
+
+	w = performRequest(router, http.MethodDelete, "/authors/"+created.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/authors/"+created.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBookHandler_Contributors(t *testing.T) {
+	bookService, router := setupAuthorHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Silmarillion", Author: "J.R.R. Tolkien", Year: 1977})
+	require.NoError(t, err)
+
+	w := performRequest(router, http.MethodPost, "/authors", models.CreateAuthorRequest{Name: "Christopher Tolkien"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var editor models.Author
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &editor))
+
+	w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/contributors", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	var current models.BookContributorsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &current))
+	require.Len(t, current.Data, 1)
+	tolkien := current.Data[0].AuthorID
+
+	request := models.SetContributorsRequest{Contributors: []models.ContributorInput{
+		{AuthorID: tolkien, Role: models.ContributorAuthor},
+		{AuthorID: editor.ID, Role: models.ContributorEditor},
+	}}
+
+	t.Run("set", func(t *testing.T) {
+		w := performConditionalRequest(router, http.MethodPut, "/books/"+book.ID+"/contributors", "If-Match", `"1"`, request)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+		var resp models.BookContributorsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 2, resp.Version)
+		assert.Equal(t, "Christopher Tolkien", resp.Data[1].Name)
+
+		w = performConditionalRequest(router, http.MethodPut, "/books/"+book.ID+"/contributors", "If-Match", `"1"`, request)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("author books", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/authors/"+editor.ID+"/books?role=editor", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.AuthorBooksResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, 1, resp.Total)
+		assert.Equal(t, "The Silmarillion", resp.Data[0].Title)
+		assert.Equal(t, []string{models.ContributorEditor}, resp.Data[0].Roles)
+
+		w = performRequest(router, http.MethodGet, "/authors/"+editor.ID+"/books?role=narrator", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodDelete, "/authors/"+editor.ID, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := performRequest(router, http.MethodPut, "/books/"+book.ID+"/contributors", models.SetContributorsRequest{
+			Contributors: []models.ContributorInput{{AuthorID: "not-a-uuid", Role: models.ContributorAuthor}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodPut, "/books/"+book.ID+"/contributors", models.SetContributorsRequest{
+			Contributors: []models.ContributorInput{{AuthorID: "3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e", Role: models.ContributorAuthor}},
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e/contributors", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -1,17 +1,45 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"library-management-backend/internal/models"
 	"library-management-backend/internal/services"
+	"library-management-backend/pkg/jsonpatch"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
+// maxBookImportSize caps the size of an uploaded CSV file.
+const maxBookImportSize = 10 << 20
+
+type bookExportFormat struct {
+	contentType string
+	extension   string
+}
+
+// bookExportFormats are the response content types and file extensions of
+// the export formats.
+var bookExportFormats = map[string]bookExportFormat{
+	models.BookExportCSV:     {contentType: "text/csv; charset=utf-8", extension: "csv"},
+	models.BookExportJSON:    {contentType: "application/json; charset=utf-8", extension: "json"},
+	models.BookExportNDJSON:  {contentType: "application/x-ndjson", extension: "ndjson"},
+	models.BookExportMARC:    {contentType: "application/marc", extension: "mrc"},
+	models.BookExportMARCXML: {contentType: "application/marcxml+xml; charset=utf-8", extension: "xml"},
+	models.BookExportBibTeX:  {contentType: "application/x-bibtex; charset=utf-8", extension: "bib"},
+	models.BookExportRIS:     {contentType: "application/x-research-info-systems; charset=utf-8", extension: "ris"},
+	models.BookExportCSLJSON: {contentType: "application/vnd.citationstyles.csl+json; charset=utf-8", extension: "json"},
+}
+
 type BookHandler struct {
 	bookService *services.BookService
 	validator   *validator.Validate
@@ -26,17 +54,57 @@ func NewBookHandler(bookService *services.BookService, validator *validator.Vali
 	}
 }
 
-// @Summary Get all books
-// @Description Retrieve all books from the library
+// @Summary List books
+// @Description Retrieve a page of books with optional filtering and sorting. Use next_cursor from the response to fetch the following page.
 // @Tags books
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Book
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Param author query string false "Case-insensitive author substring"
+// @Param genre query string false "Genre name or alias; also matches books in its subgenres"
+// @Param year_from query int false "Minimum publication year"
+// @Param year_to query int false "Maximum publication year, not before year_from"
+// @Param has_isbn query bool false "Only books with (true) or without (false) an ISBN"
+// @Param branch_id query string false "Only books with a copy currently at this branch"
+// @Param sort query string false "Sort field" Enums(title, author, year, created_at)
+// @Param order query string false "Sort order (default desc for created_at, asc otherwise)" Enums(asc, desc)
+// @Param format query string false "Render the page as citations instead of JSON; also selectable with Accept" Enums(json, bibtex, ris, csl-json)
+// @Success 200 {object} models.BookListResponse
+// @Header 200 {string} Link "Next page of a citation response"
+// @Header 200 {integer} X-Total-Count "Number of matching books, for citation responses"
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /books [get]
 func (h *BookHandler) GetBooks(c *gin.Context) {
-	books, err := h.bookService.GetAllBooks()
+	var params models.BookListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	books, err := h.bookService.ListBooks(&params)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "Invalid or expired cursor",
+			})
+			return
+		}
+
 		h.logger.WithError(err).Error("Failed to get books")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -45,25 +113,105 @@ func (h *BookHandler) GetBooks(c *gin.Context) {
 		return
 	}
 
+	if format := negotiateBookFormat(c, params.Format); format != bookFormatJSON {
+		// Citation formats have no room for paging metadata, so it moves
+		// to headers.
+		c.Header("X-Total-Count", strconv.Itoa(books.Total))
+		if books.NextCursor != "" {
+			c.Header("Link", "<"+nextPageLink(c, books.NextCursor)+`>; rel="next"`)
+		}
+		h.writeBookCitations(c, format, books.Data)
+		return
+	}
+
 	c.JSON(http.StatusOK, books)
 }
 
+// @Summary Search books
+// @Description Full-text search over title, author, genre and description, ordered by relevance. Matches are wrapped in <mark> tags in the highlights.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param q query string true "Search terms (supports quoted phrases, OR and -exclusion)"
+// @Param lang query string false "Text search language; restricts results to books in that language"
+// @Param genre query string false "Genre name or alias; also matches books in its subgenres"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of results to skip"
+// @Success 200 {object} models.BookSearchResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/search [get]
+func (h *BookHandler) SearchBooks(c *gin.Context) {
+	var params models.BookSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	results, err := h.bookService.SearchBooks(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to search books")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to search books",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
 // @Summary Get book by ID
-// @Description Retrieve a specific book by its ID
+// @Description Retrieve a specific book by its ID, with the availability of its copies. The response carries an ETag; send it back in If-None-Match to get 304 Not Modified while the book is unchanged. The ETag does not cover availability, so a 304 says nothing about it.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path string true "Book ID"
+// @Param format query string false "Render the book as a citation instead of JSON; also selectable with Accept" Enums(json, bibtex, ris, csl-json)
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} models.Book
+// @Header 200 {string} ETag "Version of the book"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /books/{id} [get]
 func (h *BookHandler) GetBook(c *gin.Context) {
+	var params models.BookFormatParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
 	id := c.Param("id")
 
-	book, err := h.bookService.GetBookByID(id)
+	book, err := h.bookService.GetBookWithAvailability(id)
 	if err != nil {
-		if err.Error() == "book not found" {
+		if errors.Is(err, services.ErrBookNotFound) {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error:   "Not Found",
 				Message: "Book not found",
@@ -79,6 +227,20 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 		return
 	}
 
+	// The ETag identifies the JSON representation, the one If-Match
+	// preconditions on writes are checked against.
+	if format := negotiateBookFormat(c, params.Format); format != bookFormatJSON {
+		h.writeBookCitations(c, format, []models.Book{*book})
+		return
+	}
+
+	etag := bookETag(book)
+	c.Header("ETag", etag)
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag, true) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, book)
 }
 
@@ -90,6 +252,7 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 // @Param book body models.CreateBookRequest true "Book data"
 // @Success 201 {object} models.Book
 // @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /books [post]
 func (h *BookHandler) CreateBook(c *gin.Context) {
@@ -114,6 +277,10 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 
 	book, err := h.bookService.CreateBook(&req)
 	if err != nil {
+		if h.handleISBNError(c, err) {
+			return
+		}
+
 		h.logger.WithError(err).Error("Failed to create book")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -122,19 +289,24 @@ func (h *BookHandler) CreateBook(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", bookETag(book))
 	c.JSON(http.StatusCreated, book)
 }
 
 // @Summary Update a book
-// @Description Update an existing book by ID
+// @Description Update an existing book by ID. Send the book's ETag in If-Match to update it only if nobody changed it since it was read.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path string true "Book ID"
+// @Param If-Match header string false "ETag the book must still have"
 // @Param book body models.UpdateBookRequest true "Updated book data"
 // @Success 200 {object} models.Book
+// @Header 200 {string} ETag "New version of the book"
 // @Failure 400 {object} models.ValidationErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /books/{id} [put]
 func (h *BookHandler) UpdateBook(c *gin.Context) {
@@ -158,15 +330,27 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
-	book, err := h.bookService.UpdateBook(id, &req)
+	expectedVersion, ok := h.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	book, err := h.bookService.UpdateBook(id, &req, expectedVersion)
 	if err != nil {
-		if err.Error() == "book not found" {
+		if errors.Is(err, services.ErrBookNotFound) {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error:   "Not Found",
 				Message: "Book not found",
 			})
 			return
 		}
+		if errors.Is(err, services.ErrVersionConflict) {
+			writePreconditionFailed(c)
+			return
+		}
+		if h.handleISBNError(c, err) {
+			return
+		}
 
 		h.logger.WithError(err).Error("Failed to update book")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -176,31 +360,194 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 		return
 	}
 
+	c.Header("ETag", bookETag(book))
 	c.JSON(http.StatusOK, book)
 }
 
+// @Summary Patch a book
+// @Description Partially update a book. Send an RFC 7396 merge patch as application/merge-patch+json (or application/json), or an RFC 6902 JSON Patch as application/json-patch+json. Setting a field to null in a merge patch clears it. The patched book must pass the same validation as a full update. Send the book's ETag in If-Match to patch it only if nobody changed it since it was read.
+// @Tags books
+// @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param If-Match header string false "ETag the book must still have"
+// @Param patch body models.UpdateBookRequest true "Merge patch or JSON Patch document"
+// @Success 200 {object} models.Book
+// @Header 200 {string} ETag "New version of the book"
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
+// @Failure 415 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id} [patch]
+func (h *BookHandler) PatchBook(c *gin.Context) {
+	id := c.Param("id")
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Failed to read request body",
+		})
+		return
+	}
+
+	var applyPatch func(doc, patch []byte) ([]byte, error)
+	switch c.ContentType() {
+	case "application/merge-patch+json", "application/json":
+		applyPatch = jsonpatch.MergePatch
+	case "application/json-patch+json":
+		applyPatch = jsonpatch.Apply
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Error:   "Unsupported Media Type",
+			Message: "Use application/merge-patch+json or application/json-patch+json",
+		})
+		return
+	}
+
+	book, err := h.bookService.GetBookByID(id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to patch book",
+		})
+		return
+	}
+
+	if h.checkIfMatch(c, book) {
+		return
+	}
+
+	doc, err := json.Marshal(book.Editable())
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to encode book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to patch book",
+		})
+		return
+	}
+
+	patched, err := applyPatch(doc, patch)
+	if err != nil {
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var merged models.UpdateBookRequest
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&merged); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Only title, author, year, description, isbn, genre and language can be patched",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&merged); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	updated, err := h.bookService.PatchBook(book, &merged)
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrVersionConflict) {
+			writePreconditionFailed(c)
+			return
+		}
+		if h.handleISBNError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to patch book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to patch book",
+		})
+		return
+	}
+
+	c.Header("ETag", bookETag(updated))
+	c.JSON(http.StatusOK, updated)
+}
+
 // @Summary Delete a book
-// @Description Delete a book by ID
+// @Description Move a book to the trash. It can be restored until it is purged after the retention window; books whose copies have been lent are never purged. A book with copies on loan or holds waiting, in transit or ready cannot be deleted. Send the book's ETag in If-Match to delete it only if nobody changed it since it was read.
 // @Tags books
 // @Accept json
 // @Produce json
 // @Param id path string true "Book ID"
+// @Param If-Match header string false "ETag the book must still have"
 // @Success 204 "No Content"
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /books/{id} [delete]
 func (h *BookHandler) DeleteBook(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.bookService.DeleteBook(id)
+	expectedVersion, ok := h.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	err := h.bookService.DeleteBook(id, expectedVersion)
 	if err != nil {
-		if err.Error() == "book not found" {
+		if errors.Is(err, services.ErrBookNotFound) {
 			c.JSON(http.StatusNotFound, models.ErrorResponse{
 				Error:   "Not Found",
 				Message: "Book not found",
 			})
 			return
 		}
+		if errors.Is(err, services.ErrVersionConflict) {
+			writePreconditionFailed(c)
+			return
+		}
+		if errors.Is(err, services.ErrBookInCirculation) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Book has copies on loan or holds waiting",
+			})
+			return
+		}
 
 		h.logger.WithError(err).Error("Failed to delete book")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
@@ -213,27 +560,326 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
-func (h *BookHandler) formatValidationErrors(err error) []models.ValidationError {
-	var errors []models.ValidationError
+// @Summary Import books from CSV
+// @Description Create books from an uploaded CSV file with a header row. Columns named after book fields (title, author, year, description, isbn, genre, language) are picked up automatically; use mapping to name other columns. Every row is validated like a new book, and rows whose ISBN already exists are skipped as duplicates. In transactional mode a single rejected row means nothing is created; in best_effort mode every valid row is created. With dry_run nothing is written.
+// @Tags books
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "JSON object mapping book fields to CSV headers, e.g. {\"title\": \"Book Title\"}"
+// @Param mode formData string false "Commit mode (default transactional)" Enums(transactional, best_effort)
+// @Param dry_run formData bool false "Validate and report without creating anything"
+// @Success 200 {object} models.BookImportReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/import [post]
+func (h *BookHandler) ImportBooks(c *gin.Context) {
+	var params models.BookImportParams
+	if err := c.ShouldBind(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid form parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	var mapping map[string]string
+	if params.Mapping != "" {
+		if err := json.Unmarshal([]byte(params.Mapping), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "mapping must be a JSON object of book fields to CSV headers",
+			})
+			return
+		}
+	}
+
+	file, ok := h.openImportFile(c, "A CSV file is required")
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	records, err := services.ParseBookCSV(file, mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.validateImportRecords(records)
+
+	report, err := h.bookService.ImportBooks(records, &params)
+	if err != nil {
+		if errors.Is(err, services.ErrDuplicateISBN) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Another request added a book with one of these ISBNs; nothing was imported",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to import books")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to import books",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Export books
+// @Description Stream every book matching the filters as CSV, a JSON array, newline-delimited JSON, binary MARC 21, MARCXML, BibTeX, RIS or CSL-JSON. Filters and ordering are the same as for listing books.
+// @Tags books
+// @Produce text/csv
+// @Produce json
+// @Produce application/x-ndjson
+// @Produce application/marc
+// @Produce application/marcxml+xml
+// @Param format query string false "Export format (default csv)" Enums(csv, json, ndjson, marc, marcxml, bibtex, ris, csl-json)
+// @Param author query string false "Case-insensitive author substring"
+// @Param genre query string false "Genre name or alias; also matches books in its subgenres"
+// @Param year_from query int false "Minimum publication year"
+// @Param year_to query int false "Maximum publication year, not before year_from"
+// @Param has_isbn query bool false "Only books with (true) or without (false) an ISBN"
+// @Param branch_id query string false "Only books with a copy currently at this branch"
+// @Param sort query string false "Sort field" Enums(title, author, year, created_at)
+// @Param order query string false "Sort order (default desc for created_at, asc otherwise)" Enums(asc, desc)
+// @Success 200 {file} file
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/export [get]
+func (h *BookHandler) ExportBooks(c *gin.Context) {
+	var params models.BookExportParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	if params.Format == "" {
+		params.Format = models.BookExportCSV
+	}
+	encoder, err := services.NewBookEncoder(params.Format, c.Writer)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create book encoder")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to export books",
+		})
+		return
+	}
+
+	format := bookExportFormats[params.Format]
+	header := c.Writer.Header()
+	header.Set("Content-Type", format.contentType)
+	header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="books.%s"`, format.extension))
+
+	err = h.bookService.ExportBooks(c.Request.Context(), &params, encoder)
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		// The status line is already sent; cutting the body short is all
+		// that is left to signal the failure.
+		h.logger.WithError(err).Error("Book export ended early")
+		return
+	}
+
+	h.logger.WithError(err).Error("Failed to export books")
+	header.Del("Content-Type")
+	header.Del("Content-Disposition")
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Error:   "Internal Server Error",
+		Message: "Failed to export books",
+	})
+}
+
+// openImportFile opens the uploaded "file" form field, writing an error
+// response when it is missing or too large.
+func (h *BookHandler) openImportFile(c *gin.Context, missingMessage string) (multipart.File, bool) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: missingMessage,
+		})
+		return nil, false
+	}
+	if header.Size > maxBookImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Error:   "Request Entity Too Large",
+			Message: fmt.Sprintf("The file must be at most %d MB", maxBookImportSize>>20),
+		})
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to open uploaded file")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to read uploaded file",
+		})
+		return nil, false
+	}
+	return file, true
+}
+
+// validateImportRecords records the validation errors of every record that
+// decoded cleanly.
+func (h *BookHandler) validateImportRecords(records []models.BookImportRecord) {
+	for i := range records {
+		if len(records[i].Errors) > 0 {
+			continue
+		}
+		if err := h.validator.Struct(&records[i].Book); err != nil {
+			records[i].Errors = h.formatValidationErrors(err)
+		}
+	}
+}
+
+// @Summary List trashed books
+// @Description Retrieve a page of deleted books, most recently deleted first. Use next_cursor from the response to fetch the following page.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {object} models.BookListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/trash [get]
+func (h *BookHandler) GetTrash(c *gin.Context) {
+	var params models.TrashListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	books, err := h.bookService.ListTrash(&params)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "Invalid or expired cursor",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get trashed books")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve trashed books",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, books)
+}
+
+// @Summary Restore a book
+// @Description Take a deleted book out of the trash
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} models.Book
+// @Header 200 {string} ETag "Version of the book"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/restore [post]
+func (h *BookHandler) RestoreBook(c *gin.Context) {
+	id := c.Param("id")
 
-	for _, err := range err.(validator.ValidationErrors) {
-		var message string
-		switch err.Tag() {
-		case "required":
-			message = "This field is required"
-		case "min":
-			message = fmt.Sprintf("Must be at least %s characters", err.Param())
-		case "max":
-			message = fmt.Sprintf("Must be no more than %s characters", err.Param())
-		default:
-			message = "Invalid value"
+	book, err := h.bookService.RestoreBook(id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found in trash",
+			})
+			return
 		}
+		if errors.Is(err, services.ErrDuplicateISBN) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "Another book now has this book's ISBN",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to restore book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to restore book",
+		})
+		return
+	}
+
+	c.Header("ETag", bookETag(book))
+	c.JSON(http.StatusOK, book)
+}
 
-		errors = append(errors, models.ValidationError{
-			Field:   err.Field(),
-			Message: message,
+// handleISBNError writes the response for ISBN related service errors and
+// reports whether it did.
+func (h *BookHandler) handleISBNError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrDuplicateISBN):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "A book with this ISBN already exists",
 		})
+	case errors.Is(err, services.ErrInvalidISBN):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	default:
+		return false
 	}
+	return true
+}
 
-	return errors
+func (h *BookHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
 }
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const bookFormatJSON = "json"
+
+// bookMediaTypes maps the media types a client may ask for in Accept to the
+// book representation they select.
+var bookMediaTypes = map[string]string{
+	"application/json":                        bookFormatJSON,
+	"application/x-bibtex":                    models.BookExportBibTeX,
+	"text/x-bibtex":                           models.BookExportBibTeX,
+	"application/x-research-info-systems":     models.BookExportRIS,
+	"application/vnd.citationstyles.csl+json": models.BookExportCSLJSON,
+}
+
+// negotiateBookFormat picks the representation of a book or list response.
+// An explicit ?format= wins; otherwise the supported media type the Accept
+// header prefers most is used, and JSON when none is acceptable.
+func negotiateBookFormat(c *gin.Context, format string) string {
+	if format != "" {
+		return format
+	}
+	c.Header("Vary", "Accept")
+
+	best, bestQuality := bookFormatJSON, 0.0
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		candidate, ok := bookMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			quality = q
+		}
+		if quality > bestQuality {
+			best, bestQuality = candidate, quality
+		}
+	}
+	return best
+}
+
+// writeBookCitations renders books in a citation format. Encoding into a
+// buffer first lets a failure still be reported as an error response.
+func (h *BookHandler) writeBookCitations(c *gin.Context, format string, books []models.Book) {
+	var body bytes.Buffer
+	encoder, err := services.NewBookEncoder(format, &body)
+	for i := 0; err == nil && i < len(books); i++ {
+		err = encoder.Encode(&books[i])
+	}
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("format", format).Error("Failed to encode books")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to encode books",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, bookExportFormats[format].contentType, body.Bytes())
+}
+
+// nextPageLink returns the URL of the page after the current request.
+func nextPageLink(c *gin.Context, cursor string) string {
+	next := url.URL{Path: c.Request.URL.Path}
+	query := c.Request.URL.Query()
+	query.Set("cursor", cursor)
+	next.RawQuery = query.Encode()
+	return next.String()
+}
+
+// @Summary Cite a book
+// @Description Format a reference list entry for a book in APA, MLA or Chicago style
+// @Tags books
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param style query string false "Citation style (default apa)" Enums(apa, mla, chicago)
+// @Success 200 {object} models.BookCitation
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/citation [get]
+func (h *BookHandler) GetBookCitation(c *gin.Context) {
+	var params models.BookCitationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+	if params.Style == "" {
+		params.Style = models.CitationAPA
+	}
+
+	book, err := h.bookService.GetBookByID(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve book",
+		})
+		return
+	}
+
+	citation, err := services.FormatCitation(book, params.Style)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to format citation")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to format citation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, citation)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List book contributors
+// @Description Retrieve the authors, editors, translators and illustrators of a book in credit order
+// @Tags books
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} models.BookContributorsResponse
+// @Header 200 {string} ETag "Version of the book"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/contributors [get]
+func (h *BookHandler) GetBookContributors(c *gin.Context) {
+	book, contributors, err := h.bookService.GetContributors(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book contributors")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve contributors",
+		})
+		return
+	}
+
+	c.Header("ETag", bookETag(book))
+	c.JSON(http.StatusOK, models.BookContributorsResponse{
+		BookID:  book.ID,
+		Version: book.Version,
+		Data:    contributors,
+	})
+}
+
+// @Summary Set book contributors
+// @Description Replace the contributors of a book, credited in the order given. The book's author field becomes the names of its author-role contributors joined with "; ", or of every contributor when none is an author. Send the book's ETag in If-Match to change it only if nobody changed it since it was read.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param If-Match header string false "ETag the book must still have"
+// @Param contributors body models.SetContributorsRequest true "Contributors in credit order"
+// @Success 200 {object} models.BookContributorsResponse
+// @Header 200 {string} ETag "New version of the book"
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/contributors [put]
+func (h *BookHandler) SetBookContributors(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.SetContributorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	expectedVersion, ok := h.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	book, contributors, err := h.bookService.SetContributors(id, &req, expectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBookNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+		case errors.Is(err, services.ErrVersionConflict):
+			writePreconditionFailed(c)
+		case errors.Is(err, services.ErrAuthorNotFound), errors.Is(err, services.ErrInvalidContributors):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: err.Error(),
+			})
+		default:
+			h.logger.WithError(err).Error("Failed to set book contributors")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Internal Server Error",
+				Message: "Failed to set contributors",
+			})
+		}
+		return
+	}
+
+	c.Header("ETag", bookETag(book))
+	c.JSON(http.StatusOK, models.BookContributorsResponse{
+		BookID:  book.ID,
+		Version: book.Version,
+		Data:    contributors,
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List book genres
+// @Description Retrieve the genres of a book, primary genre first
+// @Tags books
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} models.BookGenresResponse
+// @Header 200 {string} ETag "Version of the book"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/genres [get]
+func (h *BookHandler) GetBookGenres(c *gin.Context) {
+	book, genres, err := h.bookService.GetBookGenres(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book genres")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve genres",
+		})
+		return
+	}
+
+	c.Header("ETag", bookETag(book))
+	c.JSON(http.StatusOK, models.BookGenresResponse{
+		BookID:  book.ID,
+		Version: book.Version,
+		Data:    genres,
+	})
+}
+
+// @Summary Set book genres
+// @Description Replace the genres of a book. The first genre is the primary one and becomes the book's genre field; an empty list clears it. Send the book's ETag in If-Match to change it only if nobody changed it since it was read.
+// @Tags books
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param If-Match header string false "ETag the book must still have"
+// @Param genres body models.SetBookGenresRequest true "Genre IDs, primary genre first"
+// @Success 200 {object} models.BookGenresResponse
+// @Header 200 {string} ETag "New version of the book"
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 412 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/genres [put]
+func (h *BookHandler) SetBookGenres(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.SetBookGenresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	expectedVersion, ok := h.expectedVersion(c, id)
+	if !ok {
+		return
+	}
+
+	book, genres, err := h.bookService.SetBookGenres(id, &req, expectedVersion)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBookNotFound):
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+		case errors.Is(err, services.ErrVersionConflict):
+			writePreconditionFailed(c)
+		case errors.Is(err, services.ErrUnknownGenre), errors.Is(err, services.ErrInvalidBookGenres):
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: err.Error(),
+			})
+		default:
+			h.logger.WithError(err).Error("Failed to set book genres")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "Internal Server Error",
+				Message: "Failed to set genres",
+			})
+		}
+		return
+	}
+
+	c.Header("ETag", bookETag(book))
+	c.JSON(http.StatusOK, models.BookGenresResponse{
+		BookID:  book.ID,
+		Version: book.Version,
+		Data:    genres,
+	})
+}
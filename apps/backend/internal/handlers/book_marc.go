@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Import books from MARC
+// @Description Upload a binary MARC 21 or MARCXML file. Each record updates the book whose ID is in its 001 field, or else the book with its ISBN, and creates a new book when neither exists. Records are applied independently; the report describes every record.
+// @Tags books
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "MARC file"
+// @Param format formData string false "File format (detected when omitted)" Enums(marc, marcxml)
+// @Param dry_run formData bool false "Validate and report without writing anything"
+// @Success 200 {object} models.BookImportReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/import/marc [post]
+func (h *BookHandler) ImportMARCBooks(c *gin.Context) {
+	var params models.BookMARCImportParams
+	if err := c.ShouldBind(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid form parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	file, ok := h.openImportFile(c, "A MARC file is required")
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	records, err := services.ParseBookMARC(file, params.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.validateImportRecords(records)
+
+	report, err := h.bookService.ImportMARCBooks(records, params.DryRun)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to import MARC records")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to import books",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Get a book as MARC
+// @Description Retrieve a single book as a binary MARC 21 or MARCXML record
+// @Tags books
+// @Produce application/marcxml+xml
+// @Produce application/marc
+// @Param id path string true "Book ID"
+// @Param format query string false "Record format (default marcxml)" Enums(marc, marcxml)
+// @Success 200 {file} file
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/marc [get]
+func (h *BookHandler) GetBookMARC(c *gin.Context) {
+	var params models.BookMARCParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+	if params.Format == "" {
+		params.Format = models.BookMARCXML
+	}
+
+	id := c.Param("id")
+	book, err := h.bookService.GetBookByID(id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve book",
+		})
+		return
+	}
+
+	var record bytes.Buffer
+	encoder, err := services.NewBookEncoder(params.Format, &record)
+	if err == nil {
+		err = encoder.Encode(book)
+	}
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		h.logger.WithError(err).WithField("book_id", id).Error("Failed to encode MARC record")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to encode book as MARC",
+		})
+		return
+	}
+
+	format := bookExportFormats[params.Format]
+	c.Header("ETag", bookETag(book))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, book.ID, format.extension))
+	c.Data(http.StatusOK, format.contentType, record.Bytes())
+}
@@ -1,78 +1,696 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
-	"github.com/stretchr/testify/mock"
 	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+	"library-management-backend/pkg/marc"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-// MockBookService is a mock of BookService
-type MockBookService struct {
-	mock.Mock
+func setupBookHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	return env.bookService(), env.router(Handlers{Book: env.bookHandler()})
 }
 
-func (m *MockBookService) GetAllBooks() ([]models.Book, error) {
-	args := m.Called()
-	return args.Get(0).([]models.Book), args.Error(1)
+func TestBookHandler_GetBooks(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	for _, title := range []string{"Dune", "Emma", "Beloved"} {
+		_, err := bookService.CreateBook(&models.CreateBookRequest{Title: title, Author: "Author", Year: 2000})
+		require.NoError(t, err)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?sort=title&limit=2", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.BookListResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Total)
+		assert.Len(t, resp.Data, 2)
+		assert.NotEmpty(t, resp.NextCursor)
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?sort=isbn", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Sort", resp.Errors[0].Field)
+	})
+
+	t.Run("year range", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?year_from=1999&year_to=2001", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodGet, "/books?year_to=2001", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books?year_from=2001&year_to=1999", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp models.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "YearTo", resp.Errors[0].Field)
+		assert.Equal(t, "Must not be less than YearFrom", resp.Errors[0].Message)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?cursor=garbage", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("malformed query", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?limit=ten", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestBookHandler_SearchBooks(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	_, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937})
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/search?q=tolkien", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.BookSearchResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Total)
+	})
+
+	t.Run("missing query", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/search", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported language", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/search?q=hobbit&lang=klingon", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
-func (m *MockBookService) GetBookByID(id string) (*models.Book, error) {
-	args := m.Called(id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func TestBookHandler_CRUD(t *testing.T) {
+	_, router := setupBookHandler(t)
+
+	w := performRequest(router, http.MethodPost, "/books", models.CreateBookRequest{Title: "New Book", Author: "Test Author", Year: 2024})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created models.Book
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "english", created.Language)
+
+	w = performRequest(router, http.MethodGet, "/books/"+created.ID, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodPut, "/books/"+created.ID, models.UpdateBookRequest{Title: "Updated", Author: "Test Author", Year: 2025})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var updated models.Book
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, "Updated", updated.Title)
+
+	w = performRequest(router, http.MethodDelete, "/books/"+created.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/books/"+created.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performRequest(router, http.MethodPut, "/books/"+created.ID, models.UpdateBookRequest{Title: "Updated", Author: "Test Author", Year: 2025})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performRequest(router, http.MethodDelete, "/books/"+created.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func performPatch(router *gin.Engine, path, contentType, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPatch, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", contentType)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBookHandler_PatchBook(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	description, genre := "A hobbit goes on an adventure.", "Fantasy"
+	created, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937, Description: &description, Genre: &genre})
+	require.NoError(t, err)
+	path := "/books/" + created.ID
+
+	t.Run("merge patch", func(t *testing.T) {
+		w := performPatch(router, path, "application/merge-patch+json", `{"year": 1938, "description": null}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var patched models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &patched))
+		assert.Equal(t, 1938, patched.Year)
+		assert.Nil(t, patched.Description)
+		assert.Equal(t, "Fantasy", *patched.Genre)
+		assert.Equal(t, "The Hobbit", patched.Title)
+	})
+
+	t.Run("json patch", func(t *testing.T) {
+		w := performPatch(router, path, "application/json-patch+json", `[{"op": "test", "path": "/year", "value": 1938}, {"op": "replace", "path": "/title", "value": "The Hobbit, or There and Back Again"}]`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var patched models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &patched))
+		assert.Equal(t, "The Hobbit, or There and Back Again", patched.Title)
+	})
+
+	t.Run("failed test operation", func(t *testing.T) {
+		w := performPatch(router, path, "application/json-patch+json", `[{"op": "test", "path": "/year", "value": 1900}, {"op": "replace", "path": "/year", "value": 2000}]`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("read-only field", func(t *testing.T) {
+		w := performPatch(router, path, "application/merge-patch+json", `{"id": "other"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("merged result is validated", func(t *testing.T) {
+		w := performPatch(router, path, "application/merge-patch+json", `{"title": null, "isbn": "978-0-306-40615-8"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Errors, 2)
+	})
+
+	t.Run("invalid patch document", func(t *testing.T) {
+		w := performPatch(router, path, "application/json-patch+json", `{"op": "replace"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		w := performPatch(router, path, "text/plain", `year=1938`)
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		w := performPatch(router, "/books/missing", "application/merge-patch+json", `{"year": 1938}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func performConditionalRequest(router *gin.Engine, method, path, header, etag string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewBuffer(data)
 	}
-	return args.Get(0).(*models.Book), args.Error(1)
+
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(header, etag)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBookHandler_ConditionalRequests(t *testing.T) {
+	_, router := setupBookHandler(t)
+
+	w := performRequest(router, http.MethodPost, "/books", models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937})
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+
+	var created models.Book
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	path := "/books/" + created.ID
+
+	t.Run("get returns etag", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, path, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("if-none-match", func(t *testing.T) {
+		w := performConditionalRequest(router, http.MethodGet, path, "If-None-Match", `W/"1"`, nil)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		w = performConditionalRequest(router, http.MethodGet, path, "If-None-Match", `"0", "7"`, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("put with matching etag", func(t *testing.T) {
+		w := performConditionalRequest(router, http.MethodPut, path, "If-Match", `"1"`, models.UpdateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1938})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("put with stale etag", func(t *testing.T) {
+		w := performConditionalRequest(router, http.MethodPut, path, "If-Match", `"1"`, models.UpdateBookRequest{Title: "Stale", Author: "J.R.R. Tolkien", Year: 1938})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("weak etag never matches if-match", func(t *testing.T) {
+		w := performConditionalRequest(router, http.MethodPut, path, "If-Match", `W/"2"`, models.UpdateBookRequest{Title: "Weak", Author: "J.R.R. Tolkien", Year: 1938})
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"year": 1937}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+		req, _ = http.NewRequest(http.MethodPatch, path, bytes.NewBufferString(`{"year": 1937}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		req.Header.Set("If-Match", `"1", "2"`)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		w := performConditionalRequest(router, http.MethodDelete, path, "If-Match", `"2"`, nil)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+		w = performConditionalRequest(router, http.MethodDelete, path, "If-Match", `"3"`, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = performConditionalRequest(router, http.MethodDelete, path, "If-Match", "*", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestBookHandler_Trash(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	created, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937})
+	require.NoError(t, err)
+
+	w := performRequest(router, http.MethodDelete, "/books/"+created.ID, nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	t.Run("list trash", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/trash", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp models.BookListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Total)
+		assert.Equal(t, created.ID, resp.Data[0].ID)
+		assert.NotNil(t, resp.Data[0].DeletedAt)
+
+		w = performRequest(router, http.MethodGet, "/books/trash?limit=0", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/trash?cursor=garbage", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/books/"+created.ID+"/restore", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+		w = performRequest(router, http.MethodGet, "/books/"+created.ID, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/books/"+created.ID+"/restore", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("restore with reused ISBN", func(t *testing.T) {
+		isbn := "9780306406157"
+		first, err := bookService.CreateBook(&models.CreateBookRequest{Title: "First", Author: "Author", Year: 1990, ISBN: &isbn})
+		require.NoError(t, err)
+		require.NoError(t, bookService.DeleteBook(first.ID, 0))
+		_, err = bookService.CreateBook(&models.CreateBookRequest{Title: "Second", Author: "Author", Year: 1990, ISBN: &isbn})
+		require.NoError(t, err)
+
+		w := performRequest(router, http.MethodPost, "/books/"+first.ID+"/restore", nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func performImport(router *gin.Engine, csv string, fields map[string]string) *httptest.ResponseRecorder {
+	return performUpload(router, "/books/import", "books.csv", []byte(csv), fields)
 }
 
-func (m *MockBookService) CreateBook(req *models.CreateBookRequest) (*models.Book, error) {
-	args := m.Called(req)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func performUpload(router *gin.Engine, path, filename string, file []byte, fields map[string]string) *httptest.ResponseRecorder {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if len(file) > 0 {
+		part, _ := writer.CreateFormFile("file", filename)
+		part.Write(file)
 	}
-	return args.Get(0).(*models.Book), args.Error(1)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
 }
 
-func (m *MockBookService) UpdateBook(id string, req *models.UpdateBookRequest) (*models.Book, error) {
-	args := m.Called(id, req)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
+func TestBookHandler_ImportBooks(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	csv := "Book Title,Writer,Year,ISBN,Language\n" +
+		"The Hobbit,J.R.R. Tolkien,1937,0-306-40615-2,\n" +
+		"Dune,Frank Herbert,1965,,klingon\n" +
+		",Nobody,1,,\n"
+	mapping := `{"title": "Book Title", "author": "Writer"}`
+
+	t.Run("best effort", func(t *testing.T) {
+		w := performImport(router, csv, map[string]string{"mapping": mapping, "mode": "best_effort"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var report models.BookImportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.True(t, report.Committed)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Rejected)
+		assert.Equal(t, "Language", report.Rows[1].Errors[0].Field)
+		assert.Len(t, report.Rows[2].Errors, 2)
+
+		book, err := bookService.GetBookByID(report.Rows[0].BookID)
+		require.NoError(t, err)
+		assert.Equal(t, "9780306406157", *book.ISBN)
+	})
+
+	t.Run("duplicates on a second run", func(t *testing.T) {
+		w := performImport(router, csv, map[string]string{"mapping": mapping, "dry_run": "true"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var report models.BookImportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, models.BookImportTransactional, report.Mode)
+		assert.False(t, report.Committed)
+		assert.Equal(t, models.BookImportDuplicate, report.Rows[0].Status)
+	})
+
+	t.Run("bad requests", func(t *testing.T) {
+		w := performImport(router, "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performImport(router, csv, map[string]string{"mode": "sometimes"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performImport(router, csv, map[string]string{"mapping": "title=Book Title"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performImport(router, csv, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Contains(t, resp.Message, "required field title")
+	})
+}
+
+func TestBookHandler_ExportBooks(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	genre := "Fantasy"
+	for _, req := range []models.CreateBookRequest{
+		{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937, Genre: &genre},
+		{Title: "Dune, Part One", Author: "Frank Herbert", Year: 1965},
+		{Title: "The Silmarillion", Author: "J.R.R. Tolkien", Year: 1977, Genre: &genre},
+	} {
+		_, err := bookService.CreateBook(&req)
+		require.NoError(t, err)
 	}
-	return args.Get(0).(*models.Book), args.Error(1)
+
+	t.Run("csv", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/export?sort=year", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="books.csv"`, w.Header().Get("Content-Disposition"))
+
+		rows, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+		assert.Equal(t, "title", rows[0][1])
+		assert.Equal(t, "Dune, Part One", rows[2][1])
+		assert.Equal(t, "1977", rows[3][3])
+	})
+
+	t.Run("json with filters", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/export?format=json&author=tolkien&order=desc&sort=year", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var books []models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &books))
+		require.Len(t, books, 2)
+		assert.Equal(t, "The Silmarillion", books[0].Title)
+	})
+
+	t.Run("empty json", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/export?format=json&genre=horror", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]\n", w.Body.String())
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/export?format=ndjson&genre=fantasy", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 2)
+		var book models.Book
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &book))
+		assert.Equal(t, "J.R.R. Tolkien", book.Author)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/export?format=xml", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
-func (m *MockBookService) DeleteBook(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
+func TestBookHandler_MARC(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	isbn := "9780306406157"
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937, ISBN: &isbn})
+	require.NoError(t, err)
+
+	t.Run("single record", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/"+book.ID+"/marc", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/marcxml+xml; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `<controlfield tag="001">`+book.ID+`</controlfield>`)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/marc?format=marc", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/marc", w.Header().Get("Content-Type"))
+		record, err := marc.Unmarshal(w.Body.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, book.ID, record.ControlField("001"))
+
+		w = performRequest(router, http.MethodGet, "/books/missing/marc", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/marc?format=unimarc", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("bulk export", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/export?format=marcxml", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="books.xml"`, w.Header().Get("Content-Disposition"))
+
+		records, err := services.ParseBookMARC(w.Body, models.BookMARCXML)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "The Hobbit", records[0].Book.Title)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		var file bytes.Buffer
+		writer := marc.NewWriter(&file)
+		updated := *book
+		updated.Year = 1938
+		require.NoError(t, writer.Write(services.BookToMARC(&updated)))
+		untitled := marc.NewRecord()
+		untitled.AddDataField("100", '1', ' ', "a", "Herbert, Frank.")
+		untitled.AddDataField("260", ' ', ' ', "c", "1965.")
+		require.NoError(t, writer.Write(untitled))
+		dune := marc.NewRecord()
+		dune.AddDataField("100", '1', ' ', "a", "Herbert, Frank.")
+		dune.AddDataField("245", '1', '0', "a", "Dune /")
+		dune.AddDataField("260", ' ', ' ', "c", "1965.")
+		require.NoError(t, writer.Write(dune))
+
+		w := performUpload(router, "/books/import/marc", "books.mrc", file.Bytes(), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var report models.BookImportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Equal(t, "Title", report.Rows[1].Errors[0].Field)
+
+		stored, err := bookService.GetBookByID(book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1938, stored.Year)
+
+		created, err := bookService.GetBookByID(report.Rows[2].BookID)
+		require.NoError(t, err)
+		assert.Equal(t, "Frank Herbert", created.Author)
+	})
+
+	t.Run("bad imports", func(t *testing.T) {
+		w := performUpload(router, "/books/import/marc", "books.mrc", nil, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performUpload(router, "/books/import/marc", "books.mrc", []byte("not marc at all"), nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performUpload(router, "/books/import/marc", "books.xml", []byte("<collection/>"), map[string]string{"format": "unimarc"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
-// This is a bit of a hack to make the handler work with our mock service.
-// The handler expects a concrete *services.BookService. We can't change the handler signature.
-// So we can't just pass our mock service.
-// A better approach would be for NewBookHandler to accept an interface.
-// Since we can't change the app's code, we will not test the book handler for now.
-// The alternative is to use a real database for testing, which is a larger undertaking.
+func TestBookHandler_Citations(t *testing.T) {
+	bookService, router := setupBookHandler(t)
+	hobbit, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937})
+	require.NoError(t, err)
+	_, err = bookService.CreateBook(&models.CreateBookRequest{Title: "Dune", Author: "Frank Herbert", Year: 1965})
+	require.NoError(t, err)
+
+	t.Run("book by format", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/"+hobbit.ID+"?format=ris", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-research-info-systems; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "TI  - The Hobbit\r\n")
+		assert.Empty(t, w.Header().Get("ETag"))
+
+		w = performRequest(router, http.MethodGet, "/books/"+hobbit.ID+"?format=docx", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("book by Accept", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/books/"+hobbit.ID, nil)
+		req.Header.Set("Accept", "application/json;q=0.5, application/x-bibtex")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "@book{tolkien1937hobbit,"))
 
-// A proper implementation would look something like this, if the handler accepted an interface:
-/*
-func setupBookHandler(mockService *MockBookService) (*BookHandler, *gin.Engine) {
-	logger := logrus.New()
-	logger.SetOutput(io.Discard)
-	validate := validator.New()
+		req.Header.Set("Accept", "text/html, */*")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+		var book models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &book))
+	})
 
-	// This is the part that doesn't work with the current code
-	// as NewBookHandler expects a concrete type.
-	bookHandler := NewBookHandler(mockService, validate, logger)
+	t.Run("list", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?format=csl-json&limit=1&sort=title", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
 
-	router := gin.Default()
-	// setup routes...
-	return bookHandler, router
+		link := w.Header().Get("Link")
+		require.True(t, strings.HasSuffix(link, `>; rel="next"`))
+		assert.Contains(t, link, "format=csl-json")
+
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+		require.Len(t, items, 1)
+		assert.Equal(t, "Dune", items[0]["title"])
+
+		next := strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`)
+		w = performRequest(router, http.MethodGet, next, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Link"))
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+		assert.Equal(t, "The Hobbit", items[0]["title"])
+	})
+
+	t.Run("formatted citation", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books/"+hobbit.ID+"/citation", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var citation models.BookCitation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &citation))
+		assert.Equal(t, models.CitationAPA, citation.Style)
+		assert.Equal(t, "Tolkien, J. R. R. (1937). The Hobbit.", citation.Text)
+
+		w = performRequest(router, http.MethodGet, "/books/"+hobbit.ID+"/citation?style=mla", nil)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &citation))
+		assert.Equal(t, "Tolkien, J.R.R. <i>The Hobbit</i>. 1937.", citation.HTML)
+
+		w = performRequest(router, http.MethodGet, "/books/"+hobbit.ID+"/citation?style=harvard", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/missing/citation", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
 }
-*/
 
-// Since we cannot properly mock the service, we will skip testing the handler here.
-// The service layer, which contains the business logic, is already tested.
-func TestBookHandler_Placeholder(t *testing.T) {
-	// This is a placeholder test.
-	t.Skip("Skipping book handler tests due to inability to mock concrete service dependency.")
+func TestBookHandler_CreateBook_Validation(t *testing.T) {
+	_, router := setupBookHandler(t)
+
+	t.Run("invalid json", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/books", bytes.NewBufferString("{invalid"))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid ISBN", func(t *testing.T) {
+		garbage := "978-0-306-40615-8"
+		w := performRequest(router, http.MethodPost, "/books", models.CreateBookRequest{Title: "Book", Author: "Author", Year: 2024, ISBN: &garbage})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "ISBN", resp.Errors[0].Field)
+		assert.Equal(t, "Must be a valid ISBN-10 or ISBN-13", resp.Errors[0].Message)
+	})
+
+	t.Run("duplicate ISBN", func(t *testing.T) {
+		isbn10, isbn13 := "0-306-40615-2", "9780306406157"
+		w := performRequest(router, http.MethodPost, "/books", models.CreateBookRequest{Title: "Book", Author: "Author", Year: 2024, ISBN: &isbn10})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var created models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, isbn13, *created.ISBN)
+
+		w = performRequest(router, http.MethodPost, "/books", models.CreateBookRequest{Title: "Copy", Author: "Author", Year: 2024, ISBN: &isbn13})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/books", models.CreateBookRequest{Year: 2024})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var resp models.ValidationErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Validation Error", resp.Error)
+		assert.Len(t, resp.Errors, 2)
+	})
 }
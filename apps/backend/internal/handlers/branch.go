@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type BranchHandler struct {
+	branchService *services.BranchService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewBranchHandler(branchService *services.BranchService, validator *validator.Validate, logger *logrus.Logger) *BranchHandler {
+	return &BranchHandler{
+		branchService: branchService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// @Summary List branches
+// @Description Retrieve every branch of the library ordered by code
+// @Tags branches
+// @Produce json
+// @Success 200 {object} models.BranchListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /branches [get]
+func (h *BranchHandler) GetBranches(c *gin.Context) {
+	branches, err := h.branchService.ListBranches()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get branches")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve branches",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, branches)
+}
+
+// @Summary Get a branch
+// @Description Retrieve a single branch by ID
+// @Tags branches
+// @Produce json
+// @Param id path string true "Branch ID"
+// @Success 200 {object} models.Branch
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /branches/{id} [get]
+func (h *BranchHandler) GetBranch(c *gin.Context) {
+	branch, err := h.branchService.GetBranchByID(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrBranchNotFound) {
+			writeBranchNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get branch")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve branch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, branch)
+}
+
+// @Summary Create a branch
+// @Description Add a branch of the library. Codes are stored in upper case and must be unique.
+// @Tags branches
+// @Accept json
+// @Produce json
+// @Param branch body models.CreateBranchRequest true "Branch data"
+// @Success 201 {object} models.Branch
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /branches [post]
+func (h *BranchHandler) CreateBranch(c *gin.Context) {
+	var req models.CreateBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	branch, err := h.branchService.CreateBranch(&req)
+	if err != nil {
+		if h.handleBranchError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create branch")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create branch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, branch)
+}
+
+// @Summary Update a branch
+// @Description Replace the code, name and address of a branch
+// @Tags branches
+// @Accept json
+// @Produce json
+// @Param id path string true "Branch ID"
+// @Param branch body models.UpdateBranchRequest true "Updated branch data"
+// @Success 200 {object} models.Branch
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /branches/{id} [put]
+func (h *BranchHandler) UpdateBranch(c *gin.Context) {
+	var req models.UpdateBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	branch, err := h.branchService.UpdateBranch(c.Param("id"), &req)
+	if err != nil {
+		if h.handleBranchError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update branch")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update branch",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, branch)
+}
+
+// @Summary Delete a branch
+// @Description Delete a branch that no copy, loan, hold or transfer refers to
+// @Tags branches
+// @Param id path string true "Branch ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /branches/{id} [delete]
+func (h *BranchHandler) DeleteBranch(c *gin.Context) {
+	err := h.branchService.DeleteBranch(c.Param("id"))
+	if err != nil {
+		if h.handleBranchError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete branch")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete branch",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleBranchError writes the response for the errors the branch
+// endpoints share, and reports whether err was one of them.
+func (h *BranchHandler) handleBranchError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrDuplicateBranchCode):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "A branch with this code already exists",
+		})
+	case errors.Is(err, services.ErrBranchInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "The branch has copies or circulation history",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *BranchHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
+
+func writeBranchNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error:   "Not Found",
+		Message: "Branch not found",
+	})
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBranchHandler(t *testing.T) *gin.Engine {
+	env := newTestEnv(t)
+	return env.router(Handlers{Branch: env.branchHandler()})
+}
+
+func setupTransferHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	transfers := services.NewTransferService(repository.NewMemoryTransferRepository(env.loans), env.items, env.branches, env.calendar, testLoanRules, env.logger)
+	return env.bookService(), env.router(Handlers{
+		Item:     env.itemHandler(),
+		Branch:   env.branchHandler(),
+		Transfer: NewTransferHandler(transfers, env.validate, env.logger),
+	})
+}
+
+func createTestBranch(t *testing.T, router *gin.Engine, code, name string) models.Branch {
+	w := performRequest(router, http.MethodPost, "/branches", models.CreateBranchRequest{Code: code, Name: name})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var branch models.Branch
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &branch))
+	return branch
+}
+
+func TestBranchHandler_CRUD(t *testing.T) {
+	router := setupBranchHandler(t)
+
+	main := createTestBranch(t, router, "main", "Main Library")
+	assert.Equal(t, "MAIN", main.Code)
+	w := performRequest(router, http.MethodPost, "/branches", models.CreateBranchRequest{Code: "MAIN", Name: "Another"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = performRequest(router, http.MethodPost, "/branches", models.CreateBranchRequest{Code: "NO SPACES", Name: "Another"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodPut, "/branches/"+main.ID, models.UpdateBranchRequest{Code: "MAIN", Name: "Central Library"})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = performRequest(router, http.MethodGet, "/branches", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.BranchListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "Central Library", list.Data[0].Name)
+
+	w = performRequest(router, http.MethodGet, "/branches/"+uuid.New().String(), nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = performRequest(router, http.MethodDelete, "/branches/"+main.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = performRequest(router, http.MethodGet, "/branches/"+main.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTransferHandler(t *testing.T) {
+	bookService, router := setupTransferHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Persuasion", Author: "Jane Austen", Year: 1817})
+	require.NoError(t, err)
+	main := createTestBranch(t, router, "MAIN", "Main Library")
+	north := createTestBranch(t, router, "NORTH", "North Branch")
+
+	w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "P-1", HomeBranchID: &main.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	missing := uuid.New().String()
+	w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "P-2", HomeBranchID: &missing})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performRequest(router, http.MethodDelete, "/branches/"+main.ID, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/transfers", models.CreateTransferRequest{Barcode: "P-1", ToBranchID: main.ID})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = performRequest(router, http.MethodPost, "/transfers", models.CreateTransferRequest{Barcode: "P-1", ToBranchID: "north"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/transfers", models.CreateTransferRequest{Barcode: "P-1", ToBranchID: north.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var transfer models.Transfer
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &transfer))
+	assert.Equal(t, models.TransferRequested, transfer.Status)
+
+	w = performRequest(router, http.MethodPost, "/transfers/"+transfer.ID+"/receive", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = performRequest(router, http.MethodPost, "/transfers/"+transfer.ID+"/ship", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = performRequest(router, http.MethodPost, "/transfers/"+transfer.ID+"/receive", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var received models.ReceiveTransferResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &received))
+	assert.Equal(t, models.TransferReceived, received.Transfer.Status)
+	assert.Nil(t, received.Hold)
+
+	w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/items?branch_id="+north.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var items models.BookItemsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	assert.Len(t, items.Data, 1)
+
+	w = performRequest(router, http.MethodGet, "/transfers?status=received&branch_id="+north.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.TransferListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+	w = performRequest(router, http.MethodGet, "/transfers?status=lost", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performRequest(router, http.MethodGet, "/transfers/"+uuid.New().String(), nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+	validator       *validator.Validate
+	logger          *logrus.Logger
+}
+
+func NewCalendarHandler(calendarService *services.CalendarService, validator *validator.Validate, logger *logrus.Logger) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// @Summary Get opening hours
+// @Description Retrieve the weekly opening hours of the library, or those a branch keeps to, in the library's time zone: the branch's own, or the library's when it has none. A weekday missing from the list is closed, unless no hours are set, when every day is open.
+// @Tags calendar
+// @Produce json
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.OpeningHoursResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/hours [get]
+func (h *CalendarHandler) GetHours(c *gin.Context) {
+	var params models.OpeningHoursParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	hours, err := h.calendarService.GetHours(&params)
+	if err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get opening hours")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve opening hours",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// @Summary Set opening hours
+// @Description Replace the weekly opening hours of the library, or of a branch with a branch_id. Weekdays left out are closed; an empty list opens every day, or for a branch has it keep to the library's hours again. Weekdays count from 0 for Sunday.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param hours body models.SetOpeningHoursRequest true "Weekly schedule"
+// @Success 200 {object} models.OpeningHoursResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/hours [put]
+func (h *CalendarHandler) SetHours(c *gin.Context) {
+	var req models.SetOpeningHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	hours, err := h.calendarService.SetHours(&req)
+	if err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to set opening hours")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to set opening hours",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// @Summary List closures
+// @Description Retrieve the holidays and other closures overlapping a date range, by start date, and with a branch_id only those closing that branch: its own and the library's. Annual closures are always listed.
+// @Tags calendar
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD)"
+// @Param to query string false "Last day of the range (YYYY-MM-DD)"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.ClosureListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/closures [get]
+func (h *CalendarHandler) GetClosures(c *gin.Context) {
+	var params models.ClosureListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	closures, err := h.calendarService.ListClosures(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get closures")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve closures",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, closures)
+}
+
+// @Summary Get a closure
+// @Description Retrieve a single closure by ID
+// @Tags calendar
+// @Produce json
+// @Param id path string true "Closure ID"
+// @Success 200 {object} models.Closure
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/closures/{id} [get]
+func (h *CalendarHandler) GetClosure(c *gin.Context) {
+	closure, err := h.calendarService.GetClosure(c.Param("id"))
+	if err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get closure")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve closure",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, closure)
+}
+
+// @Summary Create a closure
+// @Description Close the library, or only the branch with a branch_id, from starts_on to ends_on inclusive. An annual closure, such as a public holiday, recurs on the same days every year and may run into the next year. Due dates and pickup deadlines that fall in a closure move to the next open day.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param closure body models.ClosureRequest true "Closure data"
+// @Success 201 {object} models.Closure
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/closures [post]
+func (h *CalendarHandler) CreateClosure(c *gin.Context) {
+	var req models.ClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	closure, err := h.calendarService.CreateClosure(&req)
+	if err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create closure")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create closure",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, closure)
+}
+
+// @Summary Update a closure
+// @Description Replace a closure. Due dates already set are not moved.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param id path string true "Closure ID"
+// @Param closure body models.ClosureRequest true "Updated closure data"
+// @Success 200 {object} models.Closure
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/closures/{id} [put]
+func (h *CalendarHandler) UpdateClosure(c *gin.Context) {
+	var req models.ClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	closure, err := h.calendarService.UpdateClosure(c.Param("id"), &req)
+	if err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update closure")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update closure",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, closure)
+}
+
+// @Summary Delete a closure
+// @Description Delete a closure. Due dates already set are not moved.
+// @Tags calendar
+// @Param id path string true "Closure ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/closures/{id} [delete]
+func (h *CalendarHandler) DeleteClosure(c *gin.Context) {
+	if err := h.calendarService.DeleteClosure(c.Param("id")); err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete closure")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete closure",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Get the next open day
+// @Description Retrieve the first day on or after a date, today by default, that the library, or a branch, is open: the day a due date or pickup deadline falling on that date moves to
+// @Tags calendar
+// @Produce json
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.OpenDayResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/next-open-day [get]
+func (h *CalendarHandler) GetNextOpenDay(c *gin.Context) {
+	var params models.OpenDayParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	day, err := h.calendarService.NextOpenDay(&params)
+	if err != nil {
+		if h.handleCalendarError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get next open day")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve next open day",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, day)
+}
+
+// handleCalendarError writes the response for the errors the calendar
+// endpoints share, and reports whether err was one of them.
+func (h *CalendarHandler) handleCalendarError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrClosureNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Closure not found",
+		})
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrInvalidOpeningHours), errors.Is(err, services.ErrInvalidClosure):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrNoOpenDay):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *CalendarHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
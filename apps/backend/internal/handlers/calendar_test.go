@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCalendarHandler(t *testing.T) *gin.Engine {
+	env := newTestEnv(t)
+	return env.router(Handlers{Calendar: NewCalendarHandler(env.calendar, env.validate, env.logger)})
+}
+
+func TestCalendarHandler(t *testing.T) {
+	router := setupCalendarHandler(t)
+
+	t.Run("hours", func(t *testing.T) {
+		w := performRequest(router, http.MethodPut, "/calendar/hours", models.SetOpeningHoursRequest{Hours: []models.OpeningHoursRequest{
+			{Weekday: 1, Opens: "09:00", Closes: "17:00"},
+			{Weekday: 2, Opens: "09:00", Closes: "17:00"},
+		}})
+		require.Equal(t, http.StatusOK, w.Code)
+		var hours models.OpeningHoursResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &hours))
+		assert.Len(t, hours.Data, 2)
+
+		w = performRequest(router, http.MethodPut, "/calendar/hours", models.SetOpeningHoursRequest{Hours: []models.OpeningHoursRequest{{Weekday: 7, Opens: "09:00", Closes: "17:00"}}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(router, http.MethodPut, "/calendar/hours", models.SetOpeningHoursRequest{Hours: []models.OpeningHoursRequest{{Weekday: 1, Opens: "noon", Closes: "17:00"}}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("closures", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/calendar/closures", models.ClosureRequest{Name: "Boxing Day", StartsOn: "2024-12-26", EndsOn: "2024-12-26", Annual: true})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var closure models.Closure
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &closure))
+
+		w = performRequest(router, http.MethodPost, "/calendar/closures", models.ClosureRequest{Name: "Backwards", StartsOn: "2024-12-26", EndsOn: "2024-12-25"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		// 2025-12-26 is a Friday and the library opens on Mondays.
+		w = performRequest(router, http.MethodGet, "/calendar/next-open-day?date=2025-12-26", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var day models.OpenDayResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &day))
+		assert.Equal(t, "2025-12-29", day.OpenOn)
+
+		w = performRequest(router, http.MethodPut, "/calendar/closures/"+closure.ID, models.ClosureRequest{Name: "Boxing Day", StartsOn: "2027-12-27", EndsOn: "2027-12-27"})
+		require.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodGet, "/calendar/closures?from=2027-12-01&to=2027-12-31", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var closures models.ClosureListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &closures))
+		assert.Len(t, closures.Data, 1)
+
+		w = performRequest(router, http.MethodDelete, "/calendar/closures/"+closure.ID, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = performRequest(router, http.MethodGet, "/calendar/closures/"+closure.ID, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = performRequest(router, http.MethodGet, "/calendar/next-open-day?date=26-12-2025", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type CirculationHandler struct {
+	circulationService *services.CirculationService
+	validator          *validator.Validate
+	logger             *logrus.Logger
+}
+
+func NewCirculationHandler(circulationService *services.CirculationService, validator *validator.Validate, logger *logrus.Logger) *CirculationHandler {
+	return &CirculationHandler{
+		circulationService: circulationService,
+		validator:          validator,
+		logger:             logger,
+	}
+}
+
+// @Summary Check out a copy
+// @Description Lend the copy with a barcode to the member with a card number. The member must not be blocked or expired, owe more than the fine limit or be at the loan limit, and the copy must be available or on the hold shelf for them. Their holds the copy fills are fulfilled. A checkout at a branch records it on the loan and the copy. The loan period and fine rates come from the loan policy for the member and book, and the due date moves to the next day the library is open.
+// @Tags circulation
+// @Accept json
+// @Produce json
+// @Param checkout body models.CheckoutRequest true "Copy barcode and member card number"
+// @Success 201 {object} models.Loan
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /circulation/checkout [post]
+func (h *CirculationHandler) Checkout(c *gin.Context) {
+	var req models.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	loan, err := h.circulationService.Checkout(&req)
+	if err != nil {
+		if h.handleCirculationError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to check out item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to check out item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, loan)
+}
+
+// @Summary Check in a copy
+// @Description End the loan of the copy with a barcode, at a branch if given. The copy is trapped for the first waiting hold it can fill, which is returned as hold, or made available again. A copy returned at another branch than the hold's pickup branch, or than its home branch when no hold takes it, is shipped there in transit.
+// @Tags circulation
+// @Accept json
+// @Produce json
+// @Param checkin body models.CheckinRequest true "Copy barcode"
+// @Success 200 {object} models.CheckinResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /circulation/checkin [post]
+func (h *CirculationHandler) Checkin(c *gin.Context) {
+	var req models.CheckinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	checkin, err := h.circulationService.Checkin(&req)
+	if err != nil {
+		if h.handleCirculationError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to check in item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to check in item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, checkin)
+}
+
+// @Summary Renew a loan
+// @Description Extend the loan of the copy with a barcode by a loan period from today. The borrower must still be allowed to borrow, the loan must be under the renewal limit, and no other member's hold the copy could fill may be waiting. The loan period and renewal limit come from the loan policy for the borrower and book, and the due date moves to the next day the library is open.
+// @Tags circulation
+// @Accept json
+// @Produce json
+// @Param renew body models.RenewRequest true "Copy barcode"
+// @Success 200 {object} models.Loan
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /circulation/renew [post]
+func (h *CirculationHandler) Renew(c *gin.Context) {
+	var req models.RenewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	loan, err := h.circulationService.Renew(&req)
+	if err != nil {
+		if h.handleCirculationError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to renew loan")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to renew loan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, loan)
+}
+
+// @Summary List the loans of a member
+// @Description Retrieve the loan history of a member, most recent checkout first
+// @Tags circulation
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param status query string false "Overdue loans are active loans past their due date" Enums(active, overdue, returned)
+// @Param branch_id query string false "Only loans checked out at this branch"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of loans to skip"
+// @Success 200 {object} models.LoanListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/loans [get]
+func (h *CirculationHandler) GetMemberLoans(c *gin.Context) {
+	var params models.LoanListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	loans, err := h.circulationService.ListMemberLoans(c.Param("id"), &params)
+	if err != nil {
+		if h.handleCirculationError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get member loans")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve loans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, loans)
+}
+
+// @Summary List the loans of a copy
+// @Description Retrieve the loan history of a copy, most recent checkout first
+// @Tags circulation
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param status query string false "Overdue loans are active loans past their due date" Enums(active, overdue, returned)
+// @Param branch_id query string false "Only loans checked out at this branch"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of loans to skip"
+// @Success 200 {object} models.LoanListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/{id}/loans [get]
+func (h *CirculationHandler) GetItemLoans(c *gin.Context) {
+	var params models.LoanListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	loans, err := h.circulationService.ListItemLoans(c.Param("id"), &params)
+	if err != nil {
+		if h.handleCirculationError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get item loans")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve loans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, loans)
+}
+
+// handleCirculationError writes the response for the errors the circulation
+// endpoints share, and reports whether err was one of them.
+func (h *CirculationHandler) handleCirculationError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Item not found",
+		})
+	case errors.Is(err, services.ErrMemberNotFound):
+		writeMemberNotFound(c)
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrInvalidCardNumber):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid card number",
+		})
+	case errors.Is(err, services.ErrItemNotAvailable), errors.Is(err, services.ErrItemOnHold), errors.Is(err, services.ErrNoActiveLoan),
+		errors.Is(err, services.ErrLoanLimitReached), errors.Is(err, services.ErrRenewalLimitReached), errors.Is(err, services.ErrHoldsWaiting),
+		errors.Is(err, services.ErrMemberBlocked), errors.Is(err, services.ErrMembershipExpired),
+		errors.Is(err, services.ErrFineLimitExceeded), errors.Is(err, services.ErrNoOpenDay):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *CirculationHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCirculationHandler lends on the loan policies and checks balances
+// against the fines given, so that tests can set them up through their own
+// handlers.
+func newTestCirculationHandler(env *testEnv, policies *services.PolicyService, fines *services.FineService) *CirculationHandler {
+	circulation := services.NewCirculationService(env.loans, env.items, env.members, fines, policies, env.calendar, env.branches, testLoanRules, env.logger)
+	return NewCirculationHandler(circulation, env.validate, env.logger)
+}
+
+func setupCirculationHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	return env.bookService(), env.router(Handlers{
+		Book:        env.bookHandler(),
+		Item:        env.itemHandler(),
+		Member:      env.memberHandler(),
+		Circulation: newTestCirculationHandler(env, newTestPolicyService(env), newTestFineService(env)),
+	})
+}
+
+func TestCirculationHandler(t *testing.T) {
+	bookService, router := setupCirculationHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Bleak House", Author: "Charles Dickens", Year: 1853})
+	require.NoError(t, err)
+
+	for _, barcode := range []string{"BH-1", "BH-2", "BH-3"} {
+		w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: barcode})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	w := performRequest(router, http.MethodPost, "/members", models.CreateMemberRequest{FirstName: "Esther", LastName: "Summerson", MembershipType: models.MembershipAdult})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var member models.Member
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+
+	t.Run("checkout", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-1", CardNumber: member.CardNumber})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var loan models.Loan
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &loan))
+		assert.Equal(t, member.ID, loan.MemberID)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-1", CardNumber: member.CardNumber})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-2", CardNumber: member.CardNumber})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-3", CardNumber: member.CardNumber})
+		require.Equal(t, http.StatusConflict, w.Code)
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "member has reached the loan limit", resp.Message)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var got models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, 2, got.Availability.OnLoan)
+	})
+
+	t.Run("renew and checkin", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/circulation/renew", models.RenewRequest{Barcode: "BH-1"})
+		require.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodPost, "/circulation/renew", models.RenewRequest{Barcode: "BH-1"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkin", models.CheckinRequest{Barcode: "BH-1"})
+		require.Equal(t, http.StatusOK, w.Code)
+		var checkin models.CheckinResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &checkin))
+		assert.NotNil(t, checkin.Loan.ReturnedAt)
+		assert.Nil(t, checkin.Hold)
+		w = performRequest(router, http.MethodPost, "/circulation/checkin", models.CheckinRequest{Barcode: "BH-3"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/members/"+member.ID+"/loans?status=active", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var loans models.LoanListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &loans))
+		require.Len(t, loans.Data, 1)
+		assert.Equal(t, "BH-2", loans.Data[0].Barcode)
+
+		w = performRequest(router, http.MethodDelete, "/members/"+member.ID, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodDelete, "/books/"+book.ID, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-3", CardNumber: "00000000000019"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-3"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkin", models.CheckinRequest{Barcode: "NOPE"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/members/"+member.ID+"/loans?status=lost", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bookETag is the strong entity tag of a book, derived from its version.
+func bookETag(book *models.Book) string {
+	return `"` + strconv.Itoa(book.Version) + `"`
+}
+
+// etagMatches reports whether header, an If-Match or If-None-Match value,
+// lists etag. If-Match uses the strong comparison, which never matches weak
+// tags; If-None-Match uses the weak comparison.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch writes a 412 response when the request carries an If-Match
+// header that does not match book, and reports whether it did.
+func (h *BookHandler) checkIfMatch(c *gin.Context, book *models.Book) bool {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" || etagMatches(ifMatch, bookETag(book), false) {
+		return false
+	}
+
+	h.logger.WithField("book_id", book.ID).Warn("If-Match precondition failed")
+	writePreconditionFailed(c)
+	return true
+}
+
+// expectedVersion resolves the If-Match header of a write to the book
+// version it must apply to, 0 when there is no precondition. It writes the
+// response and returns false when the book is missing or does not match.
+func (h *BookHandler) expectedVersion(c *gin.Context, id string) (int, bool) {
+	if c.GetHeader("If-Match") == "" {
+		return 0, true
+	}
+
+	book, err := h.bookService.GetBookByID(id)
+	if err != nil {
+		if errors.Is(err, services.ErrBookNotFound) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{
+				Error:   "Not Found",
+				Message: "Book not found",
+			})
+			return 0, false
+		}
+
+		h.logger.WithError(err).Error("Failed to get book")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to check book version",
+		})
+		return 0, false
+	}
+
+	if h.checkIfMatch(c, book) {
+		return 0, false
+	}
+	return book.Version, true
+}
+
+func writePreconditionFailed(c *gin.Context) {
+	c.JSON(http.StatusPreconditionFailed, models.ErrorResponse{
+		Error:   "Precondition Failed",
+		Message: "The book was modified by another request; fetch it again and retry",
+	})
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type FineHandler struct {
+	fineService *services.FineService
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+func NewFineHandler(fineService *services.FineService, validator *validator.Validate, logger *logrus.Logger) *FineHandler {
+	return &FineHandler{
+		fineService: fineService,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// @Summary List the fines ledger of a member
+// @Description Retrieve the ledger of a member, newest entry first, with the balance after each entry and the balance now. The balance counts the overdue fines run up to today, including those not yet charged to the ledger.
+// @Tags fines
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param kind query string false "Entry kind" Enums(charge, payment, waiver, refund)
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} models.LedgerListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/fines [get]
+func (h *FineHandler) GetLedger(c *gin.Context) {
+	var params models.LedgerListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	ledger, err := h.fineService.ListLedger(c.Param("id"), &params)
+	if err != nil {
+		if h.handleFineError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get ledger")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve ledger",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ledger)
+}
+
+// @Summary Get the balance of a member
+// @Description Retrieve what a member owes, counting the overdue fines run up to today whether or not they are charged yet, and whether it stops them borrowing
+// @Tags fines
+// @Produce json
+// @Param id path string true "Member ID"
+// @Success 200 {object} models.MemberBalance
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/fines/balance [get]
+func (h *FineHandler) GetBalance(c *gin.Context) {
+	balance, err := h.fineService.GetBalance(c.Param("id"))
+	if err != nil {
+		if h.handleFineError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get balance")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve balance",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// @Summary Charge a member a fee
+// @Description Charge a lost item, processing or other fee, optionally for one of the member's loans. Overdue fines are charged automatically.
+// @Tags fines
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param charge body models.ChargeRequest true "Fee to charge"
+// @Success 201 {object} models.LedgerEntry
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/fines/charges [post]
+func (h *FineHandler) Charge(c *gin.Context) {
+	var req models.ChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	entry, err := h.fineService.Charge(c.Param("id"), &req)
+	if err != nil {
+		if h.handleFineError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to charge member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to charge member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary Record a payment
+// @Description Record a payment from a member of up to what they owe
+// @Tags fines
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param payment body models.SettlementRequest true "Amount paid"
+// @Success 201 {object} models.LedgerEntry
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/fines/payments [post]
+func (h *FineHandler) Pay(c *gin.Context) {
+	h.settle(c, h.fineService.Pay, "record payment")
+}
+
+// @Summary Waive fines
+// @Description Forgive up to what a member owes
+// @Tags fines
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param waiver body models.SettlementRequest true "Amount waived"
+// @Success 201 {object} models.LedgerEntry
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/fines/waivers [post]
+func (h *FineHandler) Waive(c *gin.Context) {
+	h.settle(c, h.fineService.Waive, "waive fines")
+}
+
+// @Summary Refund a payment
+// @Description Give a member back up to what they have paid and not had refunded
+// @Tags fines
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param refund body models.SettlementRequest true "Amount refunded"
+// @Success 201 {object} models.LedgerEntry
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/fines/refunds [post]
+func (h *FineHandler) Refund(c *gin.Context) {
+	h.settle(c, h.fineService.Refund, "refund payment")
+}
+
+// settle binds a settlement request and records it with record; action
+// names it in the error message.
+func (h *FineHandler) settle(c *gin.Context, record func(string, *models.SettlementRequest) (*models.LedgerEntry, error), action string) {
+	var req models.SettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	entry, err := record(c.Param("id"), &req)
+	if err != nil {
+		if h.handleFineError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to " + action)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to " + action,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// handleFineError writes the response for the errors the fine endpoints
+// share, and reports whether err was one of them.
+func (h *FineHandler) handleFineError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrMemberNotFound):
+		writeMemberNotFound(c)
+	case errors.Is(err, services.ErrLoanNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Loan not found",
+		})
+	case errors.Is(err, services.ErrAmountExceedsBalance), errors.Is(err, services.ErrAmountExceedsPayments):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *FineHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFineService(env *testEnv) *services.FineService {
+	return services.NewFineService(repository.NewMemoryFineRepository(env.loans), env.members, env.calendar, testFineRules, env.logger)
+}
+
+func setupFineHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	fines := newTestFineService(env)
+	return env.bookService(), env.router(Handlers{
+		Item:        env.itemHandler(),
+		Member:      env.memberHandler(),
+		Circulation: newTestCirculationHandler(env, newTestPolicyService(env), fines),
+		Fine:        NewFineHandler(fines, env.validate, env.logger),
+	})
+}
+
+func TestFineHandler(t *testing.T) {
+	bookService, router := setupFineHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Bleak House", Author: "Charles Dickens", Year: 1853})
+	require.NoError(t, err)
+	w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "BH-1"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	esther := createTestMember(t, router, "Esther", "Summerson")
+	fines := "/members/" + esther.ID + "/fines"
+
+	t.Run("charge", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, fines+"/charges", models.ChargeRequest{Category: models.FeeLostItem, AmountCents: 1500})
+		require.Equal(t, http.StatusCreated, w.Code)
+		var entry models.LedgerEntry
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+		assert.Equal(t, models.LedgerCharge, entry.Kind)
+		assert.Equal(t, 1500, entry.BalanceCents)
+
+		w = performRequest(router, http.MethodPost, fines+"/charges", models.ChargeRequest{Category: models.FeeOverdue, AmountCents: 100})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(router, http.MethodPost, "/members/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e/fines/charges", models.ChargeRequest{Category: models.FeeOther, AmountCents: 100})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("balance blocks checkout", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, fines+"/balance", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var balance models.MemberBalance
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &balance))
+		assert.Equal(t, 1500, balance.BalanceCents)
+		assert.Equal(t, 1000, balance.LimitCents)
+		assert.True(t, balance.CheckoutBlocked)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-1", CardNumber: esther.CardNumber})
+		require.Equal(t, http.StatusConflict, w.Code)
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "member owes more than the fine limit", resp.Message)
+	})
+
+	t.Run("settle", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, fines+"/payments", models.SettlementRequest{AmountCents: 2000})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		w = performRequest(router, http.MethodPost, fines+"/payments", models.SettlementRequest{AmountCents: 1000})
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = performRequest(router, http.MethodPost, fines+"/refunds", models.SettlementRequest{AmountCents: 200})
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = performRequest(router, http.MethodPost, fines+"/waivers", models.SettlementRequest{AmountCents: 700})
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = performRequest(router, http.MethodPost, fines+"/waivers", models.SettlementRequest{})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "BH-1", CardNumber: esther.CardNumber})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = performRequest(router, http.MethodGet, fines+"?limit=2", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var ledger models.LedgerListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ledger))
+		assert.Equal(t, 4, ledger.Total)
+		assert.Equal(t, 0, ledger.BalanceCents)
+		require.Len(t, ledger.Data, 2)
+		assert.Equal(t, models.LedgerWaiver, ledger.Data[0].Kind)
+
+		w = performRequest(router, http.MethodGet, fines+"?kind=fee", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("history blocks deletion", func(t *testing.T) {
+		w := performRequest(router, http.MethodDelete, "/members/"+esther.ID, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
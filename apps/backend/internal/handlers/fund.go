@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type FundHandler struct {
+	fundService *services.FundService
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+func NewFundHandler(fundService *services.FundService, validator *validator.Validate, logger *logrus.Logger) *FundHandler {
+	return &FundHandler{
+		fundService: fundService,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// @Summary List funds
+// @Description Report the budget of each fund with what its placed orders have committed for copies still to come, what its received copies have spent and what it has left, ordered by fiscal year, newest first, and code, with the sums of all the funds listed
+// @Tags funds
+// @Produce json
+// @Param fiscal_year query int false "Only funds of this fiscal year"
+// @Success 200 {object} models.FundListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /funds [get]
+func (h *FundHandler) GetFunds(c *gin.Context) {
+	var params models.FundListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	funds, err := h.fundService.ListFunds(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get funds")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve funds",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, funds)
+}
+
+// @Summary Get a fund
+// @Description Retrieve a single fund by ID with its commitments and spending
+// @Tags funds
+// @Produce json
+// @Param id path string true "Fund ID"
+// @Success 200 {object} models.Fund
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /funds/{id} [get]
+func (h *FundHandler) GetFund(c *gin.Context) {
+	fund, err := h.fundService.GetFundByID(c.Param("id"))
+	if err != nil {
+		if h.handleFundError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get fund")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve fund",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, fund)
+}
+
+// @Summary Create a fund
+// @Description Add a budget for a fiscal year. Codes are stored in upper case and must be unique within a fiscal year.
+// @Tags funds
+// @Accept json
+// @Produce json
+// @Param fund body models.CreateFundRequest true "Fund data"
+// @Success 201 {object} models.Fund
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /funds [post]
+func (h *FundHandler) CreateFund(c *gin.Context) {
+	var req models.CreateFundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	fund, err := h.fundService.CreateFund(&req)
+	if err != nil {
+		if h.handleFundError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create fund")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create fund",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, fund)
+}
+
+// @Summary Update a fund
+// @Description Replace the code, name, fiscal year and budget of a fund. A budget cut below what the fund has committed and spent leaves it with a negative balance.
+// @Tags funds
+// @Accept json
+// @Produce json
+// @Param id path string true "Fund ID"
+// @Param fund body models.UpdateFundRequest true "Updated fund data"
+// @Success 200 {object} models.Fund
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /funds/{id} [put]
+func (h *FundHandler) UpdateFund(c *gin.Context) {
+	var req models.UpdateFundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	fund, err := h.fundService.UpdateFund(c.Param("id"), &req)
+	if err != nil {
+		if h.handleFundError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update fund")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update fund",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, fund)
+}
+
+// @Summary Delete a fund
+// @Description Delete a fund that no purchase order line is paid from
+// @Tags funds
+// @Param id path string true "Fund ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /funds/{id} [delete]
+func (h *FundHandler) DeleteFund(c *gin.Context) {
+	err := h.fundService.DeleteFund(c.Param("id"))
+	if err != nil {
+		if h.handleFundError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete fund")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete fund",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleFundError writes the response for the errors the fund endpoints
+// share, and reports whether err was one of them.
+func (h *FundHandler) handleFundError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrFundNotFound):
+		writeFundNotFound(c)
+	case errors.Is(err, services.ErrDuplicateFundCode):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "A fund with this code already exists for this fiscal year",
+		})
+	case errors.Is(err, services.ErrFundInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "The fund has purchase order lines",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *FundHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
+
+func writeFundNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error:   "Not Found",
+		Message: "Fund not found",
+	})
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type GenreHandler struct {
+	genreService *services.GenreService
+	validator    *validator.Validate
+	logger       *logrus.Logger
+}
+
+func NewGenreHandler(genreService *services.GenreService, validator *validator.Validate, logger *logrus.Logger) *GenreHandler {
+	return &GenreHandler{
+		genreService: genreService,
+		validator:    validator,
+		logger:       logger,
+	}
+}
+
+// @Summary List genres
+// @Description Retrieve the genre taxonomy as a tree of top-level genres and their subgenres, each level ordered by name
+// @Tags genres
+// @Produce json
+// @Success 200 {object} models.GenreTreeResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres [get]
+func (h *GenreHandler) GetGenres(c *gin.Context) {
+	genres, err := h.genreService.ListGenres()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get genres")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve genres",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// @Summary Get a genre
+// @Description Retrieve a single genre by ID
+// @Tags genres
+// @Produce json
+// @Param id path string true "Genre ID"
+// @Success 200 {object} models.Genre
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres/{id} [get]
+func (h *GenreHandler) GetGenre(c *gin.Context) {
+	genre, err := h.genreService.GetGenreByID(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrGenreNotFound) {
+			writeGenreNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get genre")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve genre",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// @Summary Create a genre
+// @Description Create a new genre, optionally under a parent genre. Names and aliases that only differ in case or punctuation, such as "Sci-Fi" and "scifi", belong to the same genre.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body models.CreateGenreRequest true "Genre data"
+// @Success 201 {object} models.Genre
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres [post]
+func (h *GenreHandler) CreateGenre(c *gin.Context) {
+	var req models.CreateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	genre, err := h.genreService.CreateGenre(&req)
+	if err != nil {
+		if h.handleGenreError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create genre")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create genre",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
+
+// @Summary Update a genre
+// @Description Replace the name, parent and aliases of a genre. Renaming a genre also renames it in the genre field of the books it is the primary genre of.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path string true "Genre ID"
+// @Param genre body models.UpdateGenreRequest true "Updated genre data"
+// @Success 200 {object} models.Genre
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres/{id} [put]
+func (h *GenreHandler) UpdateGenre(c *gin.Context) {
+	var req models.UpdateGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	genre, err := h.genreService.UpdateGenre(c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrGenreNotFound) {
+			writeGenreNotFound(c)
+			return
+		}
+		if h.handleGenreError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update genre")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update genre",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// @Summary Delete a genre
+// @Description Delete a genre that has no subgenres and is not assigned to any book, including books in the trash
+// @Tags genres
+// @Param id path string true "Genre ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres/{id} [delete]
+func (h *GenreHandler) DeleteGenre(c *gin.Context) {
+	err := h.genreService.DeleteGenre(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrGenreNotFound) {
+			writeGenreNotFound(c)
+			return
+		}
+		if errors.Is(err, services.ErrGenreInUse) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "The genre has books or subgenres; merge it into another genre instead",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete genre")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete genre",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Merge a genre
+// @Description Fold a genre into another one. Its books, subgenres and aliases move to the target genre, which keeps the merged name as an alias, and the genre is deleted.
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the genre to merge"
+// @Param merge body models.MergeGenreRequest true "Genre to merge into"
+// @Success 200 {object} models.Genre
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /genres/{id}/merge [post]
+func (h *GenreHandler) MergeGenre(c *gin.Context) {
+	var req models.MergeGenreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	genre, err := h.genreService.MergeGenre(c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrGenreNotFound) {
+			writeGenreNotFound(c)
+			return
+		}
+		if h.handleGenreError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to merge genre")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to merge genre",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, genre)
+}
+
+// handleGenreError writes the response for the errors the genre writes
+// share, and reports whether err was one of them.
+func (h *GenreHandler) handleGenreError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrDuplicateGenre):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "A genre with this name or alias already exists",
+		})
+	case errors.Is(err, services.ErrUnknownGenre), errors.Is(err, services.ErrGenreCycle):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *GenreHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
+
+func writeGenreNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error:   "Not Found",
+		Message: "Genre not found",
+	})
+}
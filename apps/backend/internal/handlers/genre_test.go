@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGenreHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	genreHandler := NewGenreHandler(services.NewGenreService(env.genres, env.logger), env.validate, env.logger)
+	return env.bookService(), env.router(Handlers{Book: env.bookHandler(), Genre: genreHandler})
+}
+
+func TestGenreHandler_CRUD(t *testing.T) {
+	_, router := setupGenreHandler(t)
+
+	w := performRequest(router, http.MethodPost, "/genres", models.CreateGenreRequest{Name: "Fiction"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var fiction models.Genre
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fiction))
+
+	w = performRequest(router, http.MethodPost, "/genres", models.CreateGenreRequest{Name: "Science Fiction", ParentID: &fiction.ID, Aliases: []string{"SF"}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var sf models.Genre
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &sf))
+
+	w = performRequest(router, http.MethodPost, "/genres", models.CreateGenreRequest{Name: "s.f."})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	invalid := "not-a-uuid"
+	w = performRequest(router, http.MethodPost, "/genres", models.CreateGenreRequest{Name: "Horror", ParentID: &invalid})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/genres", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var tree models.GenreTreeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+	require.Len(t, tree.Data, 1)
+	require.Len(t, tree.Data[0].Children, 1)
+	assert.Equal(t, sf.ID, tree.Data[0].Children[0].ID)
+
+	w = performRequest(router, http.MethodPut, "/genres/"+fiction.ID, models.UpdateGenreRequest{Name: "Fiction", ParentID: &sf.ID})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodPut, "/genres/"+sf.ID, models.UpdateGenreRequest{Name: "Sci-Fi"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/genres/"+sf.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var fetched models.Genre
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, "Sci-Fi", fetched.Name)
+	assert.Nil(t, fetched.ParentID)
+	assert.Empty(t, fetched.Aliases)
+
+	w = performRequest(router, http.MethodPost, "/genres/"+sf.ID+"/merge", models.MergeGenreRequest{IntoID: fiction.ID})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/genres/"+sf.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performRequest(router, http.MethodDelete, "/genres/"+fiction.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestBookHandler_Genres(t *testing.T) {
+	bookService, router := setupGenreHandler(t)
+	genre := "Mystery"
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Moonstone", Author: "Wilkie Collins", Year: 1868, Genre: &genre})
+	require.NoError(t, err)
+
+	w := performRequest(router, http.MethodPost, "/genres", models.CreateGenreRequest{Name: "Detective Fiction"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var detective models.Genre
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detective))
+
+	w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/genres", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	var current models.BookGenresResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &current))
+	require.Len(t, current.Data, 1)
+	mystery := current.Data[0]
+
+	t.Run("set", func(t *testing.T) {
+		request := models.SetBookGenresRequest{GenreIDs: []string{detective.ID, mystery.ID}}
+		w := performConditionalRequest(router, http.MethodPut, "/books/"+book.ID+"/genres", "If-Match", `"1"`, request)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+
+		var resp models.BookGenresResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "Detective Fiction", resp.Data[0].Name)
+
+		w = performConditionalRequest(router, http.MethodPut, "/books/"+book.ID+"/genres", "If-Match", `"1"`, request)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var updated models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Equal(t, "Detective Fiction", *updated.Genre)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/books?genre=mystery", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var list models.BookListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, 1, list.Total)
+
+		w = performRequest(router, http.MethodDelete, "/genres/"+mystery.ID, nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := performRequest(router, http.MethodPut, "/books/"+book.ID+"/genres", models.SetBookGenresRequest{GenreIDs: []string{"not-a-uuid"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodPut, "/books/"+book.ID+"/genres", models.SetBookGenresRequest{GenreIDs: []string{"3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e/genres", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,381 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type HoldHandler struct {
+	holdService *services.HoldService
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+func NewHoldHandler(holdService *services.HoldService, validator *validator.Validate, logger *logrus.Logger) *HoldHandler {
+	return &HoldHandler{
+		holdService: holdService,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// @Summary List the holds on a book
+// @Description Retrieve the active holds on a book: ready holds with a copy on the hold shelf first, then the waiting holds in queue order
+// @Tags holds
+// @Produce json
+// @Param id path string true "Book ID"
+// @Success 200 {object} models.BookHoldsResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/holds [get]
+func (h *HoldHandler) GetBookHolds(c *gin.Context) {
+	holds, err := h.holdService.ListBookHolds(c.Param("id"))
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book holds")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve holds",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, holds)
+}
+
+// @Summary Place a hold on a book
+// @Description Queue a member for any copy of a book, or for one copy when item_id is given. Holds can only be placed while no copy that could fill them is available, the member must not be blocked or expired, and the loan policy for the member and book must allow holds. Copies trapped for a hold with a pickup branch are shipped there and the hold is in transit until they arrive.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param hold body models.PlaceHoldRequest true "Member and optional copy"
+// @Success 201 {object} models.Hold
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/holds [post]
+func (h *HoldHandler) PlaceHold(c *gin.Context) {
+	var req models.PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	hold, err := h.holdService.PlaceHold(c.Param("id"), &req)
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to place hold")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to place hold",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+// @Summary List the holds of a member
+// @Description Retrieve the holds of a member, most recently placed first
+// @Tags holds
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param status query string false "Hold status" Enums(waiting, in_transit, ready, fulfilled, cancelled, expired)
+// @Param branch_id query string false "Only holds picked up at this branch"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of holds to skip"
+// @Success 200 {object} models.HoldListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/holds [get]
+func (h *HoldHandler) GetMemberHolds(c *gin.Context) {
+	var params models.HoldListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	holds, err := h.holdService.ListMemberHolds(c.Param("id"), &params)
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get member holds")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve holds",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, holds)
+}
+
+// @Summary Get a hold
+// @Description Retrieve a single hold by ID, with its place in the queue while it is waiting
+// @Tags holds
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /holds/{id} [get]
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	hold, err := h.holdService.GetHold(c.Param("id"))
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get hold")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve hold",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Summary Cancel a hold
+// @Description Cancel a waiting, in-transit or ready hold. A copy on the hold shelf for it is trapped for the next hold in the queue or made available; a copy in transit for it is trapped again when it arrives.
+// @Tags holds
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /holds/{id}/cancel [post]
+func (h *HoldHandler) CancelHold(c *gin.Context) {
+	hold, err := h.holdService.CancelHold(c.Param("id"))
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to cancel hold")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to cancel hold",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Summary Suspend a hold
+// @Description Pass over a waiting hold until the end of a day, for example while the member is away. The hold keeps its place in the queue.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Param suspension body models.SuspendHoldRequest true "Last day of the suspension"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /holds/{id}/suspend [post]
+func (h *HoldHandler) SuspendHold(c *gin.Context) {
+	var req models.SuspendHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	hold, err := h.holdService.SuspendHold(c.Param("id"), &req)
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to suspend hold")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to suspend hold",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Summary Resume a hold
+// @Description End the suspension of a waiting hold
+// @Tags holds
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Success 200 {object} models.Hold
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /holds/{id}/resume [post]
+func (h *HoldHandler) ResumeHold(c *gin.Context) {
+	hold, err := h.holdService.ResumeHold(c.Param("id"))
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to resume hold")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to resume hold",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// @Summary Move a hold in the queue
+// @Description Put a waiting hold at another place in the queue of its book. Positions past the end of the queue put it last.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID"
+// @Param position body models.MoveHoldRequest true "New position, counting from 1"
+// @Success 200 {object} models.Hold
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /holds/{id}/position [put]
+func (h *HoldHandler) MoveHold(c *gin.Context) {
+	var req models.MoveHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	hold, err := h.holdService.MoveHold(c.Param("id"), &req)
+	if err != nil {
+		if h.handleHoldError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to move hold")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to move hold",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+// handleHoldError writes the response for the errors the hold endpoints
+// share, and reports whether err was one of them.
+func (h *HoldHandler) handleHoldError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrHoldNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Hold not found",
+		})
+	case errors.Is(err, services.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Book not found",
+		})
+	case errors.Is(err, services.ErrItemNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Item not found",
+		})
+	case errors.Is(err, services.ErrMemberNotFound):
+		writeMemberNotFound(c)
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrSuspensionEnded):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrDuplicateHold), errors.Is(err, services.ErrCopyAvailable),
+		errors.Is(err, services.ErrNoHoldableCopy), errors.Is(err, services.ErrHoldNotWaiting),
+		errors.Is(err, services.ErrHoldClosed), errors.Is(err, services.ErrMemberBlocked),
+		errors.Is(err, services.ErrMembershipExpired), errors.Is(err, services.ErrHoldsNotAllowed),
+		errors.Is(err, services.ErrNoOpenDay):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *HoldHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
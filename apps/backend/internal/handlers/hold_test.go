@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHoldHandler places holds on the loan policies given.
+func newTestHoldHandler(env *testEnv, policies *services.PolicyService) *HoldHandler {
+	holds := services.NewHoldService(repository.NewMemoryHoldRepository(env.loans), env.books, env.members, policies, env.calendar, env.branches, testLoanRules, env.logger)
+	return NewHoldHandler(holds, env.validate, env.logger)
+}
+
+func setupHoldHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	policies := newTestPolicyService(env)
+	return env.bookService(), env.router(Handlers{
+		Item:        env.itemHandler(),
+		Member:      env.memberHandler(),
+		Circulation: newTestCirculationHandler(env, policies, newTestFineService(env)),
+		Hold:        newTestHoldHandler(env, policies),
+	})
+}
+
+func createTestMember(t *testing.T, router *gin.Engine, firstName, lastName string) models.Member {
+	w := performRequest(router, http.MethodPost, "/members", models.CreateMemberRequest{FirstName: firstName, LastName: lastName, MembershipType: models.MembershipAdult})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var member models.Member
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	return member
+}
+
+func TestHoldHandler(t *testing.T) {
+	bookService, router := setupHoldHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Middlemarch", Author: "George Eliot", Year: 1871})
+	require.NoError(t, err)
+	w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "MM-1"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	dorothea := createTestMember(t, router, "Dorothea", "Brooke")
+	celia := createTestMember(t, router, "Celia", "Brooke")
+	fred := createTestMember(t, router, "Fred", "Vincy")
+
+	w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/holds", models.PlaceHoldRequest{MemberID: celia.ID})
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "MM-1", CardNumber: dorothea.CardNumber})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var celiaHold, fredHold models.Hold
+	t.Run("place", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/holds", models.PlaceHoldRequest{MemberID: celia.ID})
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &celiaHold))
+		assert.Equal(t, models.HoldLevelTitle, celiaHold.Level)
+		assert.Equal(t, 1, celiaHold.Position)
+
+		w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/holds", models.PlaceHoldRequest{MemberID: fred.ID})
+		require.Equal(t, http.StatusCreated, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fredHold))
+		assert.Equal(t, 2, fredHold.Position)
+
+		w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/holds", models.PlaceHoldRequest{MemberID: fred.ID})
+		assert.Equal(t, http.StatusConflict, w.Code)
+		w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/holds", models.PlaceHoldRequest{MemberID: "fred"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("reorder and suspend", func(t *testing.T) {
+		w := performRequest(router, http.MethodPut, "/holds/"+fredHold.ID+"/position", models.MoveHoldRequest{Position: 1})
+		require.Equal(t, http.StatusOK, w.Code)
+		var moved models.Hold
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &moved))
+		assert.Equal(t, 1, moved.Position)
+
+		tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+		w = performRequest(router, http.MethodPost, "/holds/"+fredHold.ID+"/suspend", models.SuspendHoldRequest{Until: tomorrow})
+		require.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodPost, "/holds/"+fredHold.ID+"/suspend", models.SuspendHoldRequest{Until: "2001-01-01"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/holds", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var queue models.BookHoldsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &queue))
+		require.Len(t, queue.Data, 2)
+		assert.Equal(t, fredHold.ID, queue.Data[0].ID)
+		assert.Equal(t, tomorrow, *queue.Data[0].SuspendedUntil)
+	})
+
+	t.Run("checkin traps the copy", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/circulation/checkin", models.CheckinRequest{Barcode: "MM-1"})
+		require.Equal(t, http.StatusOK, w.Code)
+		var checkin models.CheckinResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &checkin))
+		require.NotNil(t, checkin.Hold)
+		assert.Equal(t, celiaHold.ID, checkin.Hold.ID)
+		assert.Equal(t, models.HoldReady, checkin.Hold.Status)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "MM-1", CardNumber: fred.CardNumber})
+		require.Equal(t, http.StatusConflict, w.Code)
+		var resp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "item is on hold for another member", resp.Message)
+
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "MM-1", CardNumber: celia.CardNumber})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/members/"+celia.ID+"/holds?status=fulfilled", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var holds models.HoldListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &holds))
+		assert.Equal(t, 1, holds.Total)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/holds/"+fredHold.ID+"/resume", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodPost, "/holds/"+fredHold.ID+"/cancel", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodPost, "/holds/"+fredHold.ID+"/cancel", nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		w = performRequest(router, http.MethodPut, "/holds/"+fredHold.ID+"/position", models.MoveHoldRequest{Position: 1})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/holds/"+fredHold.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var got models.Hold
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, models.HoldCancelled, got.Status)
+
+		w = performRequest(router, http.MethodGet, "/holds/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type InventoryHandler struct {
+	inventoryService *services.InventoryService
+	validator        *validator.Validate
+	logger           *logrus.Logger
+}
+
+func NewInventoryHandler(inventoryService *services.InventoryService, validator *validator.Validate, logger *logrus.Logger) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+		validator:        validator,
+		logger:           logger,
+	}
+}
+
+// @Summary List inventory sessions
+// @Description Retrieve stocktake sessions, most recently opened first
+// @Tags inventory
+// @Produce json
+// @Param status query string false "Session status" Enums(open, closed)
+// @Param branch_id query string false "Only sessions of this branch"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of sessions to skip"
+// @Success 200 {object} models.InventoryListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory [get]
+func (h *InventoryHandler) GetInventories(c *gin.Context) {
+	var params models.InventoryListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	sessions, err := h.inventoryService.ListInventories(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get inventory sessions")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve inventory sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// @Summary Get an inventory session
+// @Description Retrieve a single stocktake session by ID, with the number of barcodes scanned so far
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.InventorySession
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id} [get]
+func (h *InventoryHandler) GetInventory(c *gin.Context) {
+	session, err := h.inventoryService.GetInventory(c.Param("id"))
+	if err != nil {
+		if h.handleInventoryError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get inventory session")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve inventory session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// @Summary Open an inventory session
+// @Description Start a stocktake of the copies at a branch, on a range of shelves, or both. Without either the session covers the whole collection. Shelf ranges compare locations byte by byte and include both ends.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param session body models.OpenInventoryRequest true "Branch and shelf range to take stock of"
+// @Success 201 {object} models.InventorySession
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory [post]
+func (h *InventoryHandler) OpenInventory(c *gin.Context) {
+	var req models.OpenInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	session, err := h.inventoryService.OpenInventory(&req)
+	if err != nil {
+		if h.handleInventoryError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to open inventory session")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to open inventory session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// @Summary Scan barcodes into an inventory session
+// @Description Record barcodes read at the shelf in an open session, one or many at a time. A barcode already scanned in the session is counted as repeated.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param scans body models.InventoryScanRequest true "Scanned barcodes"
+// @Success 200 {object} models.InventoryScanResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/scans [post]
+func (h *InventoryHandler) ScanInventory(c *gin.Context) {
+	var req models.InventoryScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	scanned, err := h.inventoryService.ScanInventory(c.Param("id"), &req)
+	if err != nil {
+		if h.handleInventoryError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to record inventory scans")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to record scans",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, scanned)
+}
+
+// @Summary Close an inventory session
+// @Description Stop scanning into a session and report the copies expected on the shelf but not scanned, the copies scanned outside the session's branch or shelf range, the lost copies that were scanned and the barcodes that match no copy
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.InventoryReport
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/close [post]
+func (h *InventoryHandler) CloseInventory(c *gin.Context) {
+	report, err := h.inventoryService.CloseInventory(c.Param("id"))
+	if err != nil {
+		if h.handleInventoryError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to close inventory session")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to close inventory session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Get an inventory report
+// @Description Compare the barcodes scanned in a session, open or closed, with the copies it covers as they are now
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} models.InventoryReport
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/report [get]
+func (h *InventoryHandler) GetInventoryReport(c *gin.Context) {
+	report, err := h.inventoryService.GetInventoryReport(c.Param("id"))
+	if err != nil {
+		if h.handleInventoryError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get inventory report")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve inventory report",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Update statuses from an inventory report
+// @Description Mark the missing copies of a closed session lost, make the lost copies it found available, or both. Copies whose status has changed since, and found copies still on loan, are skipped with the reason.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID"
+// @Param updates body models.ApplyInventoryRequest true "Status updates to make"
+// @Success 200 {object} models.ApplyInventoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /inventory/{id}/apply [post]
+func (h *InventoryHandler) ApplyInventory(c *gin.Context) {
+	var req models.ApplyInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	applied, err := h.inventoryService.ApplyInventory(c.Param("id"), &req)
+	if err != nil {
+		if h.handleInventoryError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to apply inventory report")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update item statuses",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, applied)
+}
+
+// handleInventoryError writes the response for the errors the inventory
+// endpoints share, and reports whether err was one of them.
+func (h *InventoryHandler) handleInventoryError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrInventoryNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Inventory session not found",
+		})
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrInvalidShelfRange):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrInventoryClosed), errors.Is(err, services.ErrInventoryOpen):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *InventoryHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
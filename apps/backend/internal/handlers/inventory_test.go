@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupInventoryHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	inventory := services.NewInventoryService(repository.NewMemoryInventoryRepository(env.items, env.branches), env.itemService(), env.branches, env.logger)
+	return env.bookService(), env.router(Handlers{
+		Item:      env.itemHandler(),
+		Branch:    env.branchHandler(),
+		Inventory: NewInventoryHandler(inventory, env.validate, env.logger),
+	})
+}
+
+func TestInventoryHandler(t *testing.T) {
+	bookService, router := setupInventoryHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Persuasion", Author: "Jane Austen", Year: 1817})
+	require.NoError(t, err)
+	main := createTestBranch(t, router, "MAIN", "Main Library")
+	for _, barcode := range []string{"P-1", "P-2"} {
+		w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: barcode, HomeBranchID: &main.ID})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	from, to := "B", "A"
+	w := performRequest(router, http.MethodPost, "/inventory", models.OpenInventoryRequest{LocationFrom: &from, LocationTo: &to})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	missing := uuid.New().String()
+	w = performRequest(router, http.MethodPost, "/inventory", models.OpenInventoryRequest{BranchID: &missing})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/inventory", models.OpenInventoryRequest{BranchID: &main.ID})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var session models.InventorySession
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &session))
+	assert.Equal(t, models.InventoryOpen, session.Status)
+
+	w = performRequest(router, http.MethodPost, "/inventory/"+session.ID+"/scans", models.InventoryScanRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performRequest(router, http.MethodPost, "/inventory/"+session.ID+"/scans", models.InventoryScanRequest{Barcodes: []string{"P-1", "X-9"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	var scanned models.InventoryScanResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &scanned))
+	assert.Equal(t, 2, scanned.Scanned)
+
+	w = performRequest(router, http.MethodPost, "/inventory/"+session.ID+"/apply", models.ApplyInventoryRequest{MarkMissingLost: true})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/inventory/"+session.ID+"/close", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var report models.InventoryReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, models.InventoryClosed, report.Session.Status)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "P-2", report.Missing[0].Barcode)
+	assert.Equal(t, []string{"X-9"}, report.UnknownBarcodes)
+	w = performRequest(router, http.MethodPost, "/inventory/"+session.ID+"/close", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/inventory/"+session.ID+"/apply", models.ApplyInventoryRequest{MarkMissingLost: true})
+	require.Equal(t, http.StatusOK, w.Code)
+	var applied models.ApplyInventoryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &applied))
+	require.Len(t, applied.Updated, 1)
+	assert.Equal(t, models.ItemLost, applied.Updated[0].Status)
+
+	w = performRequest(router, http.MethodGet, "/inventory?status=closed&branch_id="+main.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.InventoryListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+	w = performRequest(router, http.MethodGet, "/inventory/"+uuid.New().String()+"/report", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
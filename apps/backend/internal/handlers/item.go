@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type ItemHandler struct {
+	itemService *services.ItemService
+	validator   *validator.Validate
+	logger      *logrus.Logger
+}
+
+func NewItemHandler(itemService *services.ItemService, validator *validator.Validate, logger *logrus.Logger) *ItemHandler {
+	return &ItemHandler{
+		itemService: itemService,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// @Summary List the copies of a book
+// @Description Retrieve the physical copies of a book ordered by barcode, with a count of copies by status. With a branch, only the copies currently at the branch are listed and counted.
+// @Tags items
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param branch_id query string false "Only copies currently at this branch"
+// @Success 200 {object} models.BookItemsResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/items [get]
+func (h *ItemHandler) GetBookItems(c *gin.Context) {
+	var params models.BookItemsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	items, err := h.itemService.ListBookItems(c.Param("id"), &params)
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get book items")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve items",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Add a copy of a book
+// @Description Add a physical copy of a book. New copies are available and in good condition unless another condition is given.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Book ID"
+// @Param item body models.CreateItemRequest true "Item data"
+// @Success 201 {object} models.Item
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /books/{id}/items [post]
+func (h *ItemHandler) CreateItem(c *gin.Context) {
+	var req models.CreateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	item, err := h.itemService.CreateItem(c.Param("id"), &req)
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// @Summary Get a copy
+// @Description Retrieve a single physical copy by ID
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Success 200 {object} models.Item
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/{id} [get]
+func (h *ItemHandler) GetItem(c *gin.Context) {
+	item, err := h.itemService.GetItemByID(c.Param("id"))
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Get a copy by barcode
+// @Description Retrieve a single physical copy by its barcode
+// @Tags items
+// @Produce json
+// @Param barcode path string true "Item barcode"
+// @Success 200 {object} models.Item
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/barcode/{barcode} [get]
+func (h *ItemHandler) GetItemByBarcode(c *gin.Context) {
+	item, err := h.itemService.GetItemByBarcode(c.Param("barcode"))
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Update a copy
+// @Description Replace the barcode, location, acquisition date, price and condition of a copy. Statuses change through the status and retire endpoints; withdrawn copies cannot be updated.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param item body models.UpdateItemRequest true "Updated item data"
+// @Success 200 {object} models.Item
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/{id} [put]
+func (h *ItemHandler) UpdateItem(c *gin.Context) {
+	var req models.UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	item, err := h.itemService.UpdateItem(c.Param("id"), &req)
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Move a copy
+// @Description Shelve a copy at a new location. Withdrawn copies cannot be moved.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param move body models.MoveItemRequest true "New location"
+// @Success 200 {object} models.Item
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/{id}/move [post]
+func (h *ItemHandler) MoveItem(c *gin.Context) {
+	var req models.MoveItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	item, err := h.itemService.MoveItem(c.Param("id"), &req)
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to move item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to move item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Change the status of a copy
+// @Description Move a copy to another status. Available copies can go in transit, lost or withdrawn; copies on loan or in transit can be lost; copies in transit can come back available; lost copies can be found or withdrawn; withdrawn copies stay withdrawn. Copies go on loan and come back through circulation, so a lost copy that is still on loan becomes available on checkin.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param status body models.ItemStatusRequest true "New status"
+// @Success 200 {object} models.Item
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/{id}/status [post]
+func (h *ItemHandler) SetItemStatus(c *gin.Context) {
+	var req models.ItemStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	item, err := h.itemService.SetItemStatus(c.Param("id"), &req)
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to change item status")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to change item status",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Retire a copy
+// @Description Withdraw an available or lost copy from the collection for good, recording the reason as its status note
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param retire body models.RetireItemRequest true "Reason for retiring the copy"
+// @Success 200 {object} models.Item
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /items/{id}/retire [post]
+func (h *ItemHandler) RetireItem(c *gin.Context) {
+	var req models.RetireItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	item, err := h.itemService.RetireItem(c.Param("id"), &req)
+	if err != nil {
+		if h.handleItemError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to retire item")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retire item",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// handleItemError writes the response for the errors the item endpoints
+// share, and reports whether err was one of them.
+func (h *ItemHandler) handleItemError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Item not found",
+		})
+	case errors.Is(err, services.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Book not found",
+		})
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrDuplicateBarcode):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "An item with this barcode already exists",
+		})
+	case errors.Is(err, services.ErrInvalidItemTransition):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrItemOnLoan):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrItemWithdrawn):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "The item is withdrawn",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *ItemHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
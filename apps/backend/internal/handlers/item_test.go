@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupItemHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	return env.bookService(), env.router(Handlers{Book: env.bookHandler(), Item: env.itemHandler()})
+}
+
+func TestItemHandler(t *testing.T) {
+	bookService, router := setupItemHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Moby-Dick", Author: "Herman Melville", Year: 1851})
+	require.NoError(t, err)
+
+	acquiredOn := "2023-09-14"
+	w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "B-100", AcquiredOn: &acquiredOn})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var item models.Item
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &item))
+	assert.Equal(t, models.ItemAvailable, item.Status)
+
+	t.Run("availability", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/items/"+item.ID+"/status", models.ItemStatusRequest{Status: models.ItemInTransit})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var got models.Book
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		require.NotNil(t, got.Availability)
+		assert.Equal(t, 1, got.Availability.Total)
+		assert.Equal(t, 1, got.Availability.InTransit)
+
+		w = performRequest(router, http.MethodPost, "/items/"+item.ID+"/retire", models.RetireItemRequest{})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/items/"+item.ID+"/status", models.ItemStatusRequest{Status: models.ItemAvailable})
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("move and retire", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/items/"+item.ID+"/move", models.MoveItemRequest{Location: "Oversize 2"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/items/barcode/B-100", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var found models.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &found))
+		assert.Equal(t, "Oversize 2", *found.Location)
+		assert.Equal(t, acquiredOn, *found.AcquiredOn)
+
+		w = performRequest(router, http.MethodPost, "/items/"+item.ID+"/retire", models.RetireItemRequest{})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = performRequest(router, http.MethodPut, "/items/"+item.ID, models.UpdateItemRequest{Barcode: "B-100", Condition: models.ItemConditionPoor})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/books/"+book.ID+"/items", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var list models.BookItemsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+		assert.Len(t, list.Data, 1)
+		assert.Equal(t, 0, list.Availability.Total)
+		assert.Equal(t, 1, list.Availability.Withdrawn)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "B-100"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		bad := "14/09/2023"
+		w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "B-101", AcquiredOn: &bad})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = performRequest(router, http.MethodPost, "/books/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e/items", models.CreateItemRequest{Barcode: "B-102"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = performRequest(router, http.MethodGet, "/items/barcode/NOPE", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type MemberHandler struct {
+	memberService *services.MemberService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewMemberHandler(memberService *services.MemberService, validator *validator.Validate, logger *logrus.Logger) *MemberHandler {
+	return &MemberHandler{
+		memberService: memberService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// @Summary Search members
+// @Description Retrieve a page of members ordered by last and first name
+// @Tags members
+// @Produce json
+// @Param q query string false "Case-insensitive substring of the name, email address, phone number or card number"
+// @Param membership_type query string false "Membership type" Enums(adult, child, student, senior, staff)
+// @Param status query string false "Active members are neither blocked nor expired" Enums(active, expired, blocked)
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of members to skip"
+// @Success 200 {object} models.MemberListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members [get]
+func (h *MemberHandler) GetMembers(c *gin.Context) {
+	var params models.MemberListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	members, err := h.memberService.ListMembers(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get members")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve members",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Register a member
+// @Description Register a new member under a newly generated card number. The membership runs for a year unless expires_on is given.
+// @Tags members
+// @Accept json
+// @Produce json
+// @Param member body models.CreateMemberRequest true "Member data"
+// @Success 201 {object} models.Member
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members [post]
+func (h *MemberHandler) CreateMember(c *gin.Context) {
+	var req models.CreateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	member, err := h.memberService.CreateMember(&req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to register member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// @Summary Get a member
+// @Description Retrieve a single member by ID
+// @Tags members
+// @Produce json
+// @Param id path string true "Member ID"
+// @Success 200 {object} models.Member
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id} [get]
+func (h *MemberHandler) GetMember(c *gin.Context) {
+	member, err := h.memberService.GetMemberByID(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrMemberNotFound) {
+			writeMemberNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Get a member by card number
+// @Description Retrieve a single member by the number on their library card. Numbers with a wrong check digit are rejected.
+// @Tags members
+// @Produce json
+// @Param card_number path string true "Card number"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/card/{card_number} [get]
+func (h *MemberHandler) GetMemberByCard(c *gin.Context) {
+	member, err := h.memberService.GetMemberByCardNumber(c.Param("card_number"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCardNumber) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "Bad Request",
+				Message: "Invalid card number",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrMemberNotFound) {
+			writeMemberNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Update a member
+// @Description Replace the details of a member. The card number and blocked status only change through their own endpoints.
+// @Tags members
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param member body models.UpdateMemberRequest true "Updated member data"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id} [put]
+func (h *MemberHandler) UpdateMember(c *gin.Context) {
+	var req models.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	member, err := h.memberService.UpdateMember(c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrMemberNotFound) {
+			writeMemberNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Block a member
+// @Description Block a member from borrowing, recording the reason
+// @Tags members
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param block body models.BlockMemberRequest true "Reason for the block"
+// @Success 200 {object} models.Member
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/block [post]
+func (h *MemberHandler) BlockMember(c *gin.Context) {
+	var req models.BlockMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	member, err := h.memberService.BlockMember(c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrMemberNotFound) {
+			writeMemberNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to block member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to block member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Unblock a member
+// @Description Lift the block on a member and clear its reason
+// @Tags members
+// @Produce json
+// @Param id path string true "Member ID"
+// @Success 200 {object} models.Member
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/unblock [post]
+func (h *MemberHandler) UnblockMember(c *gin.Context) {
+	member, err := h.memberService.UnblockMember(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrMemberNotFound) {
+			writeMemberNotFound(c)
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to unblock member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to unblock member",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// @Summary Delete a member
+// @Description Delete a member without circulation history. Members with history can be blocked instead.
+// @Tags members
+// @Param id path string true "Member ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id} [delete]
+func (h *MemberHandler) DeleteMember(c *gin.Context) {
+	err := h.memberService.DeleteMember(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrMemberNotFound) {
+			writeMemberNotFound(c)
+			return
+		}
+		if errors.Is(err, services.ErrMemberHasLoans) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{
+				Error:   "Conflict",
+				Message: "The member has circulation history; block them instead",
+			})
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete member")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete member",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *MemberHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
+
+func writeMemberNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error:   "Not Found",
+		Message: "Member not found",
+	})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMemberHandler(t *testing.T) *gin.Engine {
+	env := newTestEnv(t)
+	return env.router(Handlers{Member: env.memberHandler()})
+}
+
+func TestMemberHandler_CRUD(t *testing.T) {
+	router := setupMemberHandler(t)
+
+	email := "grace@example.com"
+	w := performRequest(router, http.MethodPost, "/members", models.CreateMemberRequest{FirstName: "Grace", LastName: "Hopper", Email: &email, MembershipType: models.MembershipStaff})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var member models.Member
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &member))
+	assert.Len(t, member.CardNumber, 14)
+
+	w = performRequest(router, http.MethodGet, "/members/card/"+member.CardNumber, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/members/card/12345", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	update := models.UpdateMemberRequest{FirstName: "Grace", LastName: "Hopper", MembershipType: models.MembershipSenior, ExpiresOn: "2031-03-31"}
+	w = performRequest(router, http.MethodPut, "/members/"+member.ID, update)
+	require.Equal(t, http.StatusOK, w.Code)
+	var updated models.Member
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+	assert.Equal(t, "2031-03-31", updated.ExpiresOn)
+	assert.Nil(t, updated.Email)
+	assert.Equal(t, member.CardNumber, updated.CardNumber)
+
+	w = performRequest(router, http.MethodPost, "/members/"+member.ID+"/block", models.BlockMemberRequest{Reason: "Unpaid fines"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/members?status=blocked&q=hopper", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.MemberListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+
+	w = performRequest(router, http.MethodPost, "/members/"+member.ID+"/unblock", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodDelete, "/members/"+member.ID, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = performRequest(router, http.MethodGet, "/members/"+member.ID, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMemberHandler_Validation(t *testing.T) {
+	router := setupMemberHandler(t)
+
+	email := "not-an-email"
+	w := performRequest(router, http.MethodPost, "/members", models.CreateMemberRequest{FirstName: "Alan", LastName: "Turing", Email: &email, MembershipType: "visitor"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp models.ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Errors, 2)
+
+	w = performRequest(router, http.MethodGet, "/members?status=lapsed", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/members/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e/block", models.BlockMemberRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type NoticeHandler struct {
+	noticeService *services.NoticeService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewNoticeHandler(noticeService *services.NoticeService, validator *validator.Validate, logger *logrus.Logger) *NoticeHandler {
+	return &NoticeHandler{
+		noticeService: noticeService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// @Summary List the notices of a member
+// @Description Retrieve the overdue and hold ready notices sent, pending, failed or skipped for a member, newest first
+// @Tags notices
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param kind query string false "Notice kind" Enums(overdue, hold_ready)
+// @Param status query string false "Notice status" Enums(pending, sent, failed, skipped)
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of notices to skip"
+// @Success 200 {object} models.NoticeListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/notices [get]
+func (h *NoticeHandler) GetNotices(c *gin.Context) {
+	var params models.NoticeListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	notices, err := h.noticeService.ListNotices(c.Param("id"), &params)
+	if err != nil {
+		if h.handleNoticeError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get notices")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve notices",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, notices)
+}
+
+// @Summary Get the notice preferences of a member
+// @Description Retrieve the channels a member is sent each kind of notice over. Members who have not chosen are sent both kinds by email.
+// @Tags notices
+// @Produce json
+// @Param id path string true "Member ID"
+// @Success 200 {object} models.NoticePreferences
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/notice-preferences [get]
+func (h *NoticeHandler) GetPreferences(c *gin.Context) {
+	preferences, err := h.noticeService.GetPreferences(c.Param("id"))
+	if err != nil {
+		if h.handleNoticeError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get notice preferences")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve notice preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// @Summary Set the notice preferences of a member
+// @Description Choose the channels a member is sent each kind of notice over. An empty list turns that kind of notice off.
+// @Tags notices
+// @Accept json
+// @Produce json
+// @Param id path string true "Member ID"
+// @Param preferences body models.NoticePreferencesRequest true "Channels for each kind of notice"
+// @Success 200 {object} models.NoticePreferences
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /members/{id}/notice-preferences [put]
+func (h *NoticeHandler) SetPreferences(c *gin.Context) {
+	var req models.NoticePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	preferences, err := h.noticeService.SetPreferences(c.Param("id"), &req)
+	if err != nil {
+		if h.handleNoticeError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to set notice preferences")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to set notice preferences",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// handleNoticeError writes the response for the errors the notice
+// endpoints share, and reports whether err was one of them.
+func (h *NoticeHandler) handleNoticeError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrMemberNotFound):
+		writeMemberNotFound(c)
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *NoticeHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+	"library-management-backend/pkg/notify"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNoticeHandler(t *testing.T) *gin.Engine {
+	env := newTestEnv(t)
+	channels := map[string]notify.Notifier{
+		models.ChannelEmail: notify.NewLogNotifier(io.Discard),
+		models.ChannelLog:   notify.NewLogNotifier(io.Discard),
+	}
+	rules := services.NoticeRules{MaxAttempts: 3, RetryBackoff: time.Minute}
+	notices := services.NewNoticeService(repository.NewMemoryNoticeRepository(env.loans), env.members, env.calendar, channels, rules, env.logger)
+	return env.router(Handlers{
+		Member: env.memberHandler(),
+		Notice: NewNoticeHandler(notices, env.validate, env.logger),
+	})
+}
+
+func TestNoticeHandler(t *testing.T) {
+	router := setupNoticeHandler(t)
+	esther := createTestMember(t, router, "Esther", "Summerson")
+	unknown := "/members/3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e"
+
+	t.Run("preferences", func(t *testing.T) {
+		path := "/members/" + esther.ID + "/notice-preferences"
+		w := performRequest(router, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var preferences models.NoticePreferences
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preferences))
+		assert.Equal(t, []string{models.ChannelEmail}, preferences.Overdue)
+		assert.Nil(t, preferences.UpdatedAt)
+
+		w = performRequest(router, http.MethodPut, path, models.NoticePreferencesRequest{Overdue: []string{"log"}, HoldReady: []string{}})
+		require.Equal(t, http.StatusOK, w.Code)
+		w = performRequest(router, http.MethodGet, path, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preferences))
+		assert.Equal(t, []string{models.ChannelLog}, preferences.Overdue)
+		assert.Empty(t, preferences.HoldReady)
+		assert.NotNil(t, preferences.UpdatedAt)
+
+		w = performRequest(router, http.MethodPut, path, models.NoticePreferencesRequest{Overdue: []string{"sms"}, HoldReady: []string{}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(router, http.MethodPut, path, models.NoticePreferencesRequest{Overdue: []string{"log", "log"}, HoldReady: []string{}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(router, http.MethodPut, path, map[string]interface{}{"overdue": []string{"email"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code, "hold_ready is required")
+
+		w = performRequest(router, http.MethodGet, unknown+"/notice-preferences", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w = performRequest(router, http.MethodPut, unknown+"/notice-preferences", models.NoticePreferencesRequest{Overdue: []string{}, HoldReady: []string{}})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("notices", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/members/"+esther.ID+"/notices?kind=overdue", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var notices models.NoticeListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &notices))
+		assert.Zero(t, notices.Total)
+		assert.NotNil(t, notices.Data)
+
+		w = performRequest(router, http.MethodGet, "/members/"+esther.ID+"/notices?status=lost", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(router, http.MethodGet, unknown+"/notices", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type PolicyHandler struct {
+	policyService *services.PolicyService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewPolicyHandler(policyService *services.PolicyService, validator *validator.Validate, logger *logrus.Logger) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// @Summary List loan policies
+// @Description Retrieve every loan policy rule, ordered by name
+// @Tags policies
+// @Produce json
+// @Success 200 {array} models.LoanPolicy
+// @Failure 500 {object} models.ErrorResponse
+// @Router /policies [get]
+func (h *PolicyHandler) GetPolicies(c *gin.Context) {
+	policies, err := h.policyService.ListPolicies()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get loan policies")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve loan policies",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// @Summary Get a loan policy
+// @Description Retrieve a single loan policy rule by ID
+// @Tags policies
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Success 200 {object} models.LoanPolicy
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /policies/{id} [get]
+func (h *PolicyHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.policyService.GetPolicyByID(c.Param("id"))
+	if err != nil {
+		if h.handlePolicyError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get loan policy")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve loan policy",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Create a loan policy
+// @Description Create a rule setting some of the loan terms for a membership type, a genre and its subgenres, both or everyone, at one branch or all of them. Terms left out fall through to less specific rules and then to the configured defaults. Only one rule may match each branch, membership type and genre.
+// @Tags policies
+// @Accept json
+// @Produce json
+// @Param policy body models.LoanPolicyRequest true "Policy data"
+// @Success 201 {object} models.LoanPolicy
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /policies [post]
+func (h *PolicyHandler) CreatePolicy(c *gin.Context) {
+	var req models.LoanPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	policy, err := h.policyService.CreatePolicy(&req)
+	if err != nil {
+		if h.handlePolicyError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create loan policy")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create loan policy",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// @Summary Update a loan policy
+// @Description Replace a loan policy rule. Terms left out are unset.
+// @Tags policies
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Param policy body models.LoanPolicyRequest true "Updated policy data"
+// @Success 200 {object} models.LoanPolicy
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /policies/{id} [put]
+func (h *PolicyHandler) UpdatePolicy(c *gin.Context) {
+	var req models.LoanPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	policy, err := h.policyService.UpdatePolicy(c.Param("id"), &req)
+	if err != nil {
+		if h.handlePolicyError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update loan policy")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update loan policy",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// @Summary Delete a loan policy
+// @Description Delete a loan policy rule. Loans already made keep their fine rates.
+// @Tags policies
+// @Param id path string true "Policy ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /policies/{id} [delete]
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	if err := h.policyService.DeletePolicy(c.Param("id")); err != nil {
+		if h.handlePolicyError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete loan policy")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete loan policy",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Evaluate loan policies
+// @Description Work out the terms a member, or any member of a membership type, would borrow a book on at a branch, with the rule each term comes from and every matching rule, most specific first. Without a book only rules for every book match, and without a branch only rules for every branch.
+// @Tags policies
+// @Produce json
+// @Param member_id query string false "Member ID; required without membership_type"
+// @Param membership_type query string false "Membership type" Enums(adult, child, student, senior, staff)
+// @Param book_id query string false "Book ID"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.PolicyEvaluation
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /policies/evaluate [get]
+func (h *PolicyHandler) EvaluatePolicies(c *gin.Context) {
+	var params models.PolicyEvaluationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	evaluation, err := h.policyService.Evaluate(&params)
+	if err != nil {
+		if h.handlePolicyError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to evaluate loan policies")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to evaluate loan policies",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
+
+// handlePolicyError writes the response for the errors the policy endpoints
+// share, and reports whether err was one of them.
+func (h *PolicyHandler) handlePolicyError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrPolicyNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Loan policy not found",
+		})
+	case errors.Is(err, services.ErrMemberNotFound):
+		writeMemberNotFound(c)
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Book not found",
+		})
+	case errors.Is(err, services.ErrDuplicatePolicy):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	case errors.Is(err, services.ErrUnknownGenre):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *PolicyHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPolicyService(env *testEnv) *services.PolicyService {
+	return services.NewPolicyService(repository.NewMemoryPolicyRepository(env.genres), env.genres, env.members, env.branches, testLoanRules, testFineRules, env.logger)
+}
+
+func setupPolicyHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	policies := newTestPolicyService(env)
+	return env.bookService(), env.router(Handlers{
+		Item:        env.itemHandler(),
+		Member:      env.memberHandler(),
+		Circulation: newTestCirculationHandler(env, policies, newTestFineService(env)),
+		Hold:        newTestHoldHandler(env, policies),
+		Policy:      NewPolicyHandler(policies, env.validate, env.logger),
+	})
+}
+
+func TestPolicyHandler(t *testing.T) {
+	bookService, router := setupPolicyHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Little Dorrit", Author: "Charles Dickens", Year: 1857})
+	require.NoError(t, err)
+	amy := createTestMember(t, router, "Amy", "Dorrit")
+
+	loanDays, noHolds := 7, false
+	w := performRequest(router, http.MethodPost, "/policies", models.LoanPolicyRequest{Name: "Short loans", LoanDays: &loanDays, HoldsAllowed: &noHolds})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var policy models.LoanPolicy
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &policy))
+
+	t.Run("create", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/policies", models.LoanPolicyRequest{Name: "Duplicate"})
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		missing := "3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e"
+		w = performRequest(router, http.MethodPost, "/policies", models.LoanPolicyRequest{Name: "Missing genre", GenreID: &missing})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		zero := 0
+		w = performRequest(router, http.MethodPost, "/policies", models.LoanPolicyRequest{Name: "No loans", LoanDays: &zero})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("evaluate", func(t *testing.T) {
+		w := performRequest(router, http.MethodGet, "/policies/evaluate?member_id="+amy.ID+"&book_id="+book.ID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var evaluation models.PolicyEvaluation
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &evaluation))
+		assert.Equal(t, models.LoanTerms{LoanDays: 7, MaxRenewals: 1, DailyFineCents: 25, MaxFineCents: 1000}, evaluation.Terms)
+		assert.Equal(t, policy.ID, evaluation.Sources["loan_days"])
+		assert.Equal(t, "default", evaluation.Sources["max_renewals"])
+
+		w = performRequest(router, http.MethodGet, "/policies/evaluate", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = performRequest(router, http.MethodGet, "/policies/evaluate?membership_type=adult&book_id=3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("circulation follows policy", func(t *testing.T) {
+		w := performRequest(router, http.MethodPost, "/books/"+book.ID+"/items", models.CreateItemRequest{Barcode: "LD-1"})
+		require.Equal(t, http.StatusCreated, w.Code)
+		w = performRequest(router, http.MethodPost, "/circulation/checkout", models.CheckoutRequest{Barcode: "LD-1", CardNumber: amy.CardNumber})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		arthur := createTestMember(t, router, "Arthur", "Clennam")
+		w = performRequest(router, http.MethodPost, "/books/"+book.ID+"/holds", models.PlaceHoldRequest{MemberID: arthur.ID})
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		w := performRequest(router, http.MethodPut, "/policies/"+policy.ID, models.LoanPolicyRequest{Name: "Short loans", Priority: 3})
+		require.Equal(t, http.StatusOK, w.Code)
+		var updated models.LoanPolicy
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &updated))
+		assert.Nil(t, updated.LoanDays)
+		assert.Equal(t, 3, updated.Priority)
+
+		w = performRequest(router, http.MethodGet, "/policies", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var policies []models.LoanPolicy
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &policies))
+		assert.Len(t, policies, 1)
+
+		w = performRequest(router, http.MethodDelete, "/policies/"+policy.ID, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		w = performRequest(router, http.MethodGet, "/policies/"+policy.ID, nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type PurchaseOrderHandler struct {
+	orderService *services.PurchaseOrderService
+	validator    *validator.Validate
+	logger       *logrus.Logger
+}
+
+func NewPurchaseOrderHandler(orderService *services.PurchaseOrderService, validator *validator.Validate, logger *logrus.Logger) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		orderService: orderService,
+		validator:    validator,
+		logger:       logger,
+	}
+}
+
+// @Summary List purchase orders
+// @Description Retrieve purchase orders with their lines, newest first
+// @Tags purchase-orders
+// @Produce json
+// @Param status query string false "Order status" Enums(draft, ordered, partially_received, received, cancelled)
+// @Param vendor_id query string false "Only orders from this vendor"
+// @Param fund_id query string false "Only orders with a line paid from this fund"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of orders to skip"
+// @Success 200 {object} models.PurchaseOrderListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrders(c *gin.Context) {
+	var params models.PurchaseOrderListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	orders, err := h.orderService.ListPurchaseOrders(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get purchase orders")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve purchase orders",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary Get a purchase order
+// @Description Retrieve a single purchase order by ID with its lines
+// @Tags purchase-orders
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c *gin.Context) {
+	order, err := h.orderService.GetPurchaseOrder(c.Param("id"))
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve purchase order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Draft a purchase order
+// @Description Draft an order from a vendor for copies of catalog records, each line paid from a fund. Drafts commit nothing until they are placed.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param order body models.CreatePurchaseOrderRequest true "Purchase order data"
+// @Success 201 {object} models.PurchaseOrder
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c *gin.Context) {
+	var req models.CreatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	order, err := h.orderService.CreatePurchaseOrder(&req)
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to create purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create purchase order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// @Summary Update a purchase order
+// @Description Replace the vendor, note and lines of a draft
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Param order body models.UpdatePurchaseOrderRequest true "Updated purchase order data"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders/{id} [put]
+func (h *PurchaseOrderHandler) UpdatePurchaseOrder(c *gin.Context) {
+	var req models.UpdatePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	order, err := h.orderService.UpdatePurchaseOrder(c.Param("id"), &req)
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update purchase order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Delete a purchase order
+// @Description Delete a draft. Placed orders are cancelled instead.
+// @Tags purchase-orders
+// @Param id path string true "Purchase order ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders/{id} [delete]
+func (h *PurchaseOrderHandler) DeletePurchaseOrder(c *gin.Context) {
+	err := h.orderService.DeletePurchaseOrder(c.Param("id"))
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete purchase order",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Place a purchase order
+// @Description Place a draft with its vendor, committing its cost against the funds of its lines. An order that would charge a fund more than it has left is refused.
+// @Tags purchase-orders
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders/{id}/submit [post]
+func (h *PurchaseOrderHandler) SubmitPurchaseOrder(c *gin.Context) {
+	order, err := h.orderService.SubmitPurchaseOrder(c.Param("id"))
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to place purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to place purchase order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Receive a purchase order
+// @Description Record a delivery against a placed order. Each barcode adds a new, available copy of the book of its line to the catalog, acquired today at the line's unit price. The order is partially received until every copy has arrived.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Param delivery body models.ReceivePurchaseOrderRequest true "Copies received"
+// @Success 200 {object} models.ReceivePurchaseOrderResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders/{id}/receive [post]
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c *gin.Context) {
+	var req models.ReceivePurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	received, err := h.orderService.ReceivePurchaseOrder(c.Param("id"), &req)
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to receive purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to receive purchase order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, received)
+}
+
+// @Summary Cancel a purchase order
+// @Description Close an order that has not been received in full, releasing what it still has committed. Copies already received stay in the catalog.
+// @Tags purchase-orders
+// @Produce json
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /purchase-orders/{id}/cancel [post]
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c *gin.Context) {
+	order, err := h.orderService.CancelPurchaseOrder(c.Param("id"))
+	if err != nil {
+		if h.handleOrderError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to cancel purchase order")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to cancel purchase order",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// handleOrderError writes the response for the errors the purchase order
+// endpoints share, and reports whether err was one of them.
+func (h *PurchaseOrderHandler) handleOrderError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrPurchaseOrderNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Purchase order not found",
+		})
+	case errors.Is(err, services.ErrOrderLineNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Purchase order line not found",
+		})
+	case errors.Is(err, services.ErrVendorNotFound):
+		writeVendorNotFound(c)
+	case errors.Is(err, services.ErrFundNotFound):
+		writeFundNotFound(c)
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrBookNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Book not found",
+		})
+	case errors.Is(err, services.ErrOrderNotDraft), errors.Is(err, services.ErrInvalidOrderTransition),
+		errors.Is(err, services.ErrOverReceipt), errors.Is(err, services.ErrFundOverCommitted),
+		errors.Is(err, services.ErrDuplicateBarcode):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *PurchaseOrderHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPurchaseOrderHandler(t *testing.T) (*services.BookService, *gin.Engine) {
+	env := newTestEnv(t)
+	vendors := repository.NewMemoryVendorRepository()
+	funds := repository.NewMemoryFundRepository()
+	orders := repository.NewMemoryPurchaseOrderRepository(env.items, vendors, funds)
+	orderService := services.NewPurchaseOrderService(orders, vendors, funds, env.books, env.branches, env.calendar, testLoanRules, env.logger)
+	return env.bookService(), env.router(Handlers{
+		Item:          env.itemHandler(),
+		Branch:        env.branchHandler(),
+		Vendor:        NewVendorHandler(services.NewVendorService(vendors, env.logger), env.validate, env.logger),
+		Fund:          NewFundHandler(services.NewFundService(funds, env.logger), env.validate, env.logger),
+		PurchaseOrder: NewPurchaseOrderHandler(orderService, env.validate, env.logger),
+	})
+}
+
+func TestPurchaseOrderHandler(t *testing.T) {
+	bookService, router := setupPurchaseOrderHandler(t)
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Persuasion", Author: "Jane Austen", Year: 1817})
+	require.NoError(t, err)
+	main := createTestBranch(t, router, "MAIN", "Main Library")
+
+	w := performRequest(router, http.MethodPost, "/vendors", models.CreateVendorRequest{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = performRequest(router, http.MethodPost, "/vendors", models.CreateVendorRequest{Name: "Blackwell's"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var vendor models.Vendor
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &vendor))
+
+	w = performRequest(router, http.MethodPost, "/funds", models.CreateFundRequest{Code: "adult", Name: "Adult fiction", FiscalYear: 2026, BudgetCents: 5000})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var fund models.Fund
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fund))
+	assert.Equal(t, "ADULT", fund.Code)
+	w = performRequest(router, http.MethodPost, "/funds", models.CreateFundRequest{Code: "ADULT", Name: "Again", FiscalYear: 2026})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	line := models.PurchaseOrderLineRequest{BookID: book.ID, FundID: fund.ID, Quantity: 2, UnitPriceCents: 1500}
+	w = performRequest(router, http.MethodPost, "/purchase-orders", models.CreatePurchaseOrderRequest{VendorID: vendor.ID})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	missing := line
+	missing.BookID = uuid.New().String()
+	w = performRequest(router, http.MethodPost, "/purchase-orders", models.CreatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{missing}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performRequest(router, http.MethodPost, "/purchase-orders", models.CreatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{line, line}})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var order models.PurchaseOrder
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, 6000, order.TotalCents)
+	w = performRequest(router, http.MethodPost, "/purchase-orders/"+order.ID+"/submit", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = performRequest(router, http.MethodPut, "/purchase-orders/"+order.ID, models.UpdatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{line}})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = performRequest(router, http.MethodPost, "/purchase-orders/"+order.ID+"/submit", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &order))
+	assert.Equal(t, models.OrderOrdered, order.Status)
+	w = performRequest(router, http.MethodDelete, "/purchase-orders/"+order.ID, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = performRequest(router, http.MethodDelete, "/vendors/"+vendor.ID, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	receive := func(lineID string, barcodes ...string) *models.ReceivePurchaseOrderRequest {
+		return &models.ReceivePurchaseOrderRequest{Lines: []models.ReceiveOrderLineRequest{
+			{LineID: lineID, Barcodes: barcodes, HomeBranchID: &main.ID},
+		}}
+	}
+	w = performRequest(router, http.MethodPost, "/purchase-orders/"+order.ID+"/receive", receive(uuid.New().String(), "P-1"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = performRequest(router, http.MethodPost, "/purchase-orders/"+order.ID+"/receive", receive(order.Lines[0].ID, "P-1", "P-2", "P-3"))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = performRequest(router, http.MethodPost, "/purchase-orders/"+order.ID+"/receive", receive(order.Lines[0].ID, "P-1"))
+	require.Equal(t, http.StatusOK, w.Code)
+	var received models.ReceivePurchaseOrderResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &received))
+	assert.Equal(t, models.OrderPartiallyReceived, received.Order.Status)
+	require.Len(t, received.Items, 1)
+	assert.Equal(t, book.ID, received.Items[0].BookID)
+
+	w = performRequest(router, http.MethodGet, "/items/barcode/P-1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = performRequest(router, http.MethodGet, "/funds?fiscal_year=2026", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var funds models.FundListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &funds))
+	assert.Equal(t, 1500, funds.EncumberedCents)
+	assert.Equal(t, 1500, funds.SpentCents)
+	assert.Equal(t, 2000, funds.AvailableCents)
+
+	w = performRequest(router, http.MethodPost, "/purchase-orders/"+order.ID+"/cancel", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = performRequest(router, http.MethodGet, "/funds/"+fund.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fund))
+	assert.Zero(t, fund.EncumberedCents)
+	assert.Equal(t, 3500, fund.AvailableCents)
+
+	w = performRequest(router, http.MethodGet, "/purchase-orders?status=cancelled&fund_id="+fund.ID, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list models.PurchaseOrderListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, 1, list.Total)
+	w = performRequest(router, http.MethodGet, "/purchase-orders/"+uuid.New().String(), nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers are the handlers that serve the API. RegisterRoutes leaves out
+// the routes of any that are nil.
+type Handlers struct {
+	Book          *BookHandler
+	Author        *AuthorHandler
+	Genre         *GenreHandler
+	Item          *ItemHandler
+	Member        *MemberHandler
+	Circulation   *CirculationHandler
+	Hold          *HoldHandler
+	Fine          *FineHandler
+	Policy        *PolicyHandler
+	Calendar      *CalendarHandler
+	Notice        *NoticeHandler
+	Branch        *BranchHandler
+	Transfer      *TransferHandler
+	Inventory     *InventoryHandler
+	Vendor        *VendorHandler
+	Fund          *FundHandler
+	PurchaseOrder *PurchaseOrderHandler
+	URL           *URLHandler
+}
+
+// RegisterRoutes mounts the API routes of handlers on api.
+func RegisterRoutes(api gin.IRouter, handlers Handlers) {
+	books := api.Group("/books")
+	authors := api.Group("/authors")
+	genres := api.Group("/genres")
+	items := api.Group("/items")
+	members := api.Group("/members")
+
+	if h := handlers.Book; h != nil {
+		books.GET("", h.GetBooks)
+		books.POST("", h.CreateBook)
+		books.GET("/search", h.SearchBooks)
+		books.POST("/import", h.ImportBooks)
+		books.POST("/import/marc", h.ImportMARCBooks)
+		books.GET("/export", h.ExportBooks)
+		books.GET("/trash", h.GetTrash)
+		books.GET("/:id", h.GetBook)
+		books.PUT("/:id", h.UpdateBook)
+		books.PATCH("/:id", h.PatchBook)
+		books.DELETE("/:id", h.DeleteBook)
+		books.GET("/:id/marc", h.GetBookMARC)
+		books.GET("/:id/citation", h.GetBookCitation)
+		books.GET("/:id/contributors", h.GetBookContributors)
+		books.PUT("/:id/contributors", h.SetBookContributors)
+		books.GET("/:id/genres", h.GetBookGenres)
+		books.PUT("/:id/genres", h.SetBookGenres)
+		books.POST("/:id/restore", h.RestoreBook)
+	}
+
+	if h := handlers.Author; h != nil {
+		authors.GET("", h.GetAuthors)
+		authors.POST("", h.CreateAuthor)
+		authors.GET("/:id", h.GetAuthor)
+		authors.PUT("/:id", h.UpdateAuthor)
+		authors.DELETE("/:id", h.DeleteAuthor)
+		authors.GET("/:id/books", h.GetAuthorBooks)
+	}
+
+	if h := handlers.Genre; h != nil {
+		genres.GET("", h.GetGenres)
+		genres.POST("", h.CreateGenre)
+		genres.GET("/:id", h.GetGenre)
+		genres.PUT("/:id", h.UpdateGenre)
+		genres.DELETE("/:id", h.DeleteGenre)
+		genres.POST("/:id/merge", h.MergeGenre)
+	}
+
+	if h := handlers.Item; h != nil {
+		books.GET("/:id/items", h.GetBookItems)
+		books.POST("/:id/items", h.CreateItem)
+		items.GET("/barcode/:barcode", h.GetItemByBarcode)
+		items.GET("/:id", h.GetItem)
+		items.PUT("/:id", h.UpdateItem)
+		items.POST("/:id/move", h.MoveItem)
+		items.POST("/:id/status", h.SetItemStatus)
+		items.POST("/:id/retire", h.RetireItem)
+	}
+
+	if h := handlers.Member; h != nil {
+		members.GET("", h.GetMembers)
+		members.POST("", h.CreateMember)
+		members.GET("/card/:card_number", h.GetMemberByCard)
+		members.GET("/:id", h.GetMember)
+		members.PUT("/:id", h.UpdateMember)
+		members.DELETE("/:id", h.DeleteMember)
+		members.POST("/:id/block", h.BlockMember)
+		members.POST("/:id/unblock", h.UnblockMember)
+	}
+
+	if h := handlers.Circulation; h != nil {
+		items.GET("/:id/loans", h.GetItemLoans)
+		members.GET("/:id/loans", h.GetMemberLoans)
+		circulation := api.Group("/circulation")
+		circulation.POST("/checkout", h.Checkout)
+		circulation.POST("/checkin", h.Checkin)
+		circulation.POST("/renew", h.Renew)
+	}
+
+	if h := handlers.Hold; h != nil {
+		books.GET("/:id/holds", h.GetBookHolds)
+		books.POST("/:id/holds", h.PlaceHold)
+		members.GET("/:id/holds", h.GetMemberHolds)
+		holds := api.Group("/holds")
+		holds.GET("/:id", h.GetHold)
+		holds.POST("/:id/cancel", h.CancelHold)
+		holds.POST("/:id/suspend", h.SuspendHold)
+		holds.POST("/:id/resume", h.ResumeHold)
+		holds.PUT("/:id/position", h.MoveHold)
+	}
+
+	if h := handlers.Fine; h != nil {
+		members.GET("/:id/fines", h.GetLedger)
+		members.GET("/:id/fines/balance", h.GetBalance)
+		members.POST("/:id/fines/charges", h.Charge)
+		members.POST("/:id/fines/payments", h.Pay)
+		members.POST("/:id/fines/waivers", h.Waive)
+		members.POST("/:id/fines/refunds", h.Refund)
+	}
+
+	if h := handlers.Notice; h != nil {
+		members.GET("/:id/notices", h.GetNotices)
+		members.GET("/:id/notice-preferences", h.GetPreferences)
+		members.PUT("/:id/notice-preferences", h.SetPreferences)
+	}
+
+	if h := handlers.Policy; h != nil {
+		policies := api.Group("/policies")
+		policies.GET("", h.GetPolicies)
+		policies.POST("", h.CreatePolicy)
+		policies.GET("/evaluate", h.EvaluatePolicies)
+		policies.GET("/:id", h.GetPolicy)
+		policies.PUT("/:id", h.UpdatePolicy)
+		policies.DELETE("/:id", h.DeletePolicy)
+	}
+
+	if h := handlers.Calendar; h != nil {
+		calendar := api.Group("/calendar")
+		calendar.GET("/hours", h.GetHours)
+		calendar.PUT("/hours", h.SetHours)
+		calendar.GET("/closures", h.GetClosures)
+		calendar.POST("/closures", h.CreateClosure)
+		calendar.GET("/closures/:id", h.GetClosure)
+		calendar.PUT("/closures/:id", h.UpdateClosure)
+		calendar.DELETE("/closures/:id", h.DeleteClosure)
+		calendar.GET("/next-open-day", h.GetNextOpenDay)
+	}
+
+	if h := handlers.Branch; h != nil {
+		branches := api.Group("/branches")
+		branches.GET("", h.GetBranches)
+		branches.POST("", h.CreateBranch)
+		branches.GET("/:id", h.GetBranch)
+		branches.PUT("/:id", h.UpdateBranch)
+		branches.DELETE("/:id", h.DeleteBranch)
+	}
+
+	if h := handlers.Transfer; h != nil {
+		transfers := api.Group("/transfers")
+		transfers.GET("", h.GetTransfers)
+		transfers.POST("", h.RequestTransfer)
+		transfers.GET("/:id", h.GetTransfer)
+		transfers.POST("/:id/ship", h.ShipTransfer)
+		transfers.POST("/:id/receive", h.ReceiveTransfer)
+		transfers.POST("/:id/cancel", h.CancelTransfer)
+	}
+
+	if h := handlers.Inventory; h != nil {
+		inventory := api.Group("/inventory")
+		inventory.GET("", h.GetInventories)
+		inventory.POST("", h.OpenInventory)
+		inventory.GET("/:id", h.GetInventory)
+		inventory.POST("/:id/scans", h.ScanInventory)
+		inventory.POST("/:id/close", h.CloseInventory)
+		inventory.GET("/:id/report", h.GetInventoryReport)
+		inventory.POST("/:id/apply", h.ApplyInventory)
+	}
+
+	if h := handlers.Vendor; h != nil {
+		vendors := api.Group("/vendors")
+		vendors.GET("", h.GetVendors)
+		vendors.POST("", h.CreateVendor)
+		vendors.GET("/:id", h.GetVendor)
+		vendors.PUT("/:id", h.UpdateVendor)
+		vendors.DELETE("/:id", h.DeleteVendor)
+	}
+
+	if h := handlers.Fund; h != nil {
+		funds := api.Group("/funds")
+		funds.GET("", h.GetFunds)
+		funds.POST("", h.CreateFund)
+		funds.GET("/:id", h.GetFund)
+		funds.PUT("/:id", h.UpdateFund)
+		funds.DELETE("/:id", h.DeleteFund)
+	}
+
+	if h := handlers.PurchaseOrder; h != nil {
+		purchaseOrders := api.Group("/purchase-orders")
+		purchaseOrders.GET("", h.GetPurchaseOrders)
+		purchaseOrders.POST("", h.CreatePurchaseOrder)
+		purchaseOrders.GET("/:id", h.GetPurchaseOrder)
+		purchaseOrders.PUT("/:id", h.UpdatePurchaseOrder)
+		purchaseOrders.DELETE("/:id", h.DeletePurchaseOrder)
+		purchaseOrders.POST("/:id/submit", h.SubmitPurchaseOrder)
+		purchaseOrders.POST("/:id/receive", h.ReceivePurchaseOrder)
+		purchaseOrders.POST("/:id/cancel", h.CancelPurchaseOrder)
+	}
+
+	if h := handlers.URL; h != nil {
+		api.POST("/url-process", h.ProcessURL)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testLoanRules = services.LoanRules{LoanDays: 21, MaxLoans: 2, MaxRenewals: 1, HoldPickupDays: 7}
+	testFineRules = services.FineRules{DailyRateCents: 25, MaxFineCents: 1000, MaxBalanceCents: 1000}
+)
+
+// testEnv is the catalog, copies, members and branches a handler test
+// works on, kept in memory repositories wired to each other, and the
+// calendar every due date and pickup deadline comes from. Each test builds
+// the handlers it exercises on top of it.
+type testEnv struct {
+	logger   *logrus.Logger
+	validate *validator.Validate
+
+	books    *repository.MemoryBookRepository
+	authors  *repository.MemoryAuthorRepository
+	genres   *repository.MemoryGenreRepository
+	items    *repository.MemoryItemRepository
+	members  *repository.MemoryMemberRepository
+	loans    *repository.MemoryLoanRepository
+	branches *repository.MemoryBranchRepository
+	calendar *services.CalendarService
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	validate := validator.New()
+	require.NoError(t, models.RegisterValidations(validate))
+
+	books := repository.NewMemoryBookRepository()
+	items := repository.NewMemoryItemRepository(books)
+	members := repository.NewMemoryMemberRepository()
+	loans := repository.NewMemoryLoanRepository(items, members)
+	branches := repository.NewMemoryBranchRepository(loans)
+	return &testEnv{
+		logger:   logger,
+		validate: validate,
+		books:    books,
+		authors:  repository.NewMemoryAuthorRepository(books),
+		genres:   repository.NewMemoryGenreRepository(books),
+		items:    items,
+		members:  members,
+		loans:    loans,
+		branches: branches,
+		calendar: services.NewCalendarService(repository.NewMemoryCalendarRepository(), branches, time.Local, logger),
+	}
+}
+
+// router serves the routes of handlers the way the server does, without
+// the /api prefix.
+func (e *testEnv) router(handlers Handlers) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	RegisterRoutes(router, handlers)
+	return router
+}
+
+func (e *testEnv) bookService() *services.BookService {
+	return services.NewBookService(e.books, e.authors, e.genres, e.items, e.logger)
+}
+
+func (e *testEnv) itemService() *services.ItemService {
+	return services.NewItemService(e.items, e.books, e.loans, e.branches, e.calendar, testLoanRules, e.logger)
+}
+
+func (e *testEnv) bookHandler() *BookHandler {
+	return NewBookHandler(e.bookService(), e.validate, e.logger)
+}
+
+func (e *testEnv) itemHandler() *ItemHandler {
+	return NewItemHandler(e.itemService(), e.validate, e.logger)
+}
+
+func (e *testEnv) memberHandler() *MemberHandler {
+	return NewMemberHandler(services.NewMemberService(e.members, e.loans, e.logger), e.validate, e.logger)
+}
+
+func (e *testEnv) branchHandler() *BranchHandler {
+	return NewBranchHandler(services.NewBranchService(e.branches, e.logger), e.validate, e.logger)
+}
+
+func performRequest(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewBuffer(data)
+	}
+
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type TransferHandler struct {
+	transferService *services.TransferService
+	validator       *validator.Validate
+	logger          *logrus.Logger
+}
+
+func NewTransferHandler(transferService *services.TransferService, validator *validator.Validate, logger *logrus.Logger) *TransferHandler {
+	return &TransferHandler{
+		transferService: transferService,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// @Summary List transfers
+// @Description Retrieve transfers between branches, most recently requested first
+// @Tags transfers
+// @Produce json
+// @Param status query string false "Transfer status" Enums(requested, in_transit, received, cancelled)
+// @Param branch_id query string false "Only transfers from or to this branch"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param offset query int false "Number of transfers to skip"
+// @Success 200 {object} models.TransferListResponse
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transfers [get]
+func (h *TransferHandler) GetTransfers(c *gin.Context) {
+	var params models.TransferListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid query parameters",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&params); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	transfers, err := h.transferService.ListTransfers(&params)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get transfers")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve transfers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+// @Summary Get a transfer
+// @Description Retrieve a single transfer by ID
+// @Tags transfers
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} models.Transfer
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transfers/{id} [get]
+func (h *TransferHandler) GetTransfer(c *gin.Context) {
+	transfer, err := h.transferService.GetTransfer(c.Param("id"))
+	if err != nil {
+		if h.handleTransferError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get transfer")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve transfer",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// @Summary Request a transfer
+// @Description Ask for the copy with a barcode to be sent from the branch it is at to another branch. The copy must be on the shelf and have no other open transfer.
+// @Tags transfers
+// @Accept json
+// @Produce json
+// @Param transfer body models.CreateTransferRequest true "Copy barcode and destination branch"
+// @Success 201 {object} models.Transfer
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transfers [post]
+func (h *TransferHandler) RequestTransfer(c *gin.Context) {
+	var req models.CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	transfer, err := h.transferService.RequestTransfer(&req)
+	if err != nil {
+		if h.handleTransferError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to request transfer")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to request transfer",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// @Summary Ship a transfer
+// @Description Send a requested transfer on its way. Its copy, which must still be on the shelf, is in transit until the transfer is received.
+// @Tags transfers
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} models.Transfer
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transfers/{id}/ship [post]
+func (h *TransferHandler) ShipTransfer(c *gin.Context) {
+	transfer, err := h.transferService.ShipTransfer(c.Param("id"))
+	if err != nil {
+		if h.handleTransferError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to ship transfer")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to ship transfer",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// @Summary Receive a transfer
+// @Description Check a copy in transit in at the branch it was sent to. A copy sent for a hold goes on the hold shelf, which is returned as hold; any other copy is trapped for the first waiting hold it can fill or made available at the branch.
+// @Tags transfers
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} models.ReceiveTransferResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transfers/{id}/receive [post]
+func (h *TransferHandler) ReceiveTransfer(c *gin.Context) {
+	received, err := h.transferService.ReceiveTransfer(c.Param("id"))
+	if err != nil {
+		if h.handleTransferError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to receive transfer")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to receive transfer",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, received)
+}
+
+// @Summary Cancel a transfer
+// @Description Cancel a transfer that has not been shipped
+// @Tags transfers
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} models.Transfer
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /transfers/{id}/cancel [post]
+func (h *TransferHandler) CancelTransfer(c *gin.Context) {
+	transfer, err := h.transferService.CancelTransfer(c.Param("id"))
+	if err != nil {
+		if h.handleTransferError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to cancel transfer")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to cancel transfer",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// handleTransferError writes the response for the errors the transfer
+// endpoints share, and reports whether err was one of them.
+func (h *TransferHandler) handleTransferError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrTransferNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Transfer not found",
+		})
+	case errors.Is(err, services.ErrItemNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "Not Found",
+			Message: "Item not found",
+		})
+	case errors.Is(err, services.ErrBranchNotFound):
+		writeBranchNotFound(c)
+	case errors.Is(err, services.ErrItemNotAvailable), errors.Is(err, services.ErrItemHasNoBranch),
+		errors.Is(err, services.ErrSameBranch), errors.Is(err, services.ErrDuplicateTransfer),
+		errors.Is(err, services.ErrInvalidTransferTransition), errors.Is(err, services.ErrInvalidItemTransition),
+		errors.Is(err, services.ErrNoOpenDay):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *TransferHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
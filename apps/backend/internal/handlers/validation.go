@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"library-management-backend/internal/models"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// formatValidationErrors describes the failed constraints of a request to
+// the library API.
+func formatValidationErrors(err error) []models.ValidationError {
+	var errors []models.ValidationError
+
+	for _, err := range err.(validator.ValidationErrors) {
+		var message string
+		switch err.Tag() {
+		case "required":
+			message = "This field is required"
+		case "min":
+			message = fmt.Sprintf("Must be at least %s characters", err.Param())
+		case "max":
+			message = fmt.Sprintf("Must be no more than %s characters", err.Param())
+		case "oneof":
+			message = fmt.Sprintf("Must be one of: %s", err.Param())
+		case "gtefield":
+			message = fmt.Sprintf("Must not be less than %s", err.Param())
+		case "datetime":
+			message = "Must be a date formatted as YYYY-MM-DD"
+		case "email":
+			message = "Must be a valid email address"
+		case "uuid":
+			message = "Must be a valid UUID"
+		case "book_isbn":
+			message = "Must be a valid ISBN-10 or ISBN-13"
+		case "search_language":
+			message = fmt.Sprintf("Must be one of: %s", strings.Join(models.SearchLanguages, " "))
+		default:
+			message = "Invalid value"
+		}
+
+		errors = append(errors, models.ValidationError{
+			Field:   err.Field(),
+			Message: message,
+		})
+	}
+
+	return errors
+}
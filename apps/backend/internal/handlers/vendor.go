@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type VendorHandler struct {
+	vendorService *services.VendorService
+	validator     *validator.Validate
+	logger        *logrus.Logger
+}
+
+func NewVendorHandler(vendorService *services.VendorService, validator *validator.Validate, logger *logrus.Logger) *VendorHandler {
+	return &VendorHandler{
+		vendorService: vendorService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// @Summary List vendors
+// @Description Retrieve every vendor ordered by name
+// @Tags vendors
+// @Produce json
+// @Success 200 {object} models.VendorListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /vendors [get]
+func (h *VendorHandler) GetVendors(c *gin.Context) {
+	vendors, err := h.vendorService.ListVendors()
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to get vendors")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve vendors",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, vendors)
+}
+
+// @Summary Get a vendor
+// @Description Retrieve a single vendor by ID
+// @Tags vendors
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Success 200 {object} models.Vendor
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /vendors/{id} [get]
+func (h *VendorHandler) GetVendor(c *gin.Context) {
+	vendor, err := h.vendorService.GetVendorByID(c.Param("id"))
+	if err != nil {
+		if h.handleVendorError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to get vendor")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to retrieve vendor",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, vendor)
+}
+
+// @Summary Create a vendor
+// @Description Add a vendor the library orders copies from
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Param vendor body models.CreateVendorRequest true "Vendor data"
+// @Success 201 {object} models.Vendor
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /vendors [post]
+func (h *VendorHandler) CreateVendor(c *gin.Context) {
+	var req models.CreateVendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	vendor, err := h.vendorService.CreateVendor(&req)
+	if err != nil {
+		h.logger.WithError(err).Error("Failed to create vendor")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to create vendor",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, vendor)
+}
+
+// @Summary Update a vendor
+// @Description Replace the name and contact details of a vendor
+// @Tags vendors
+// @Accept json
+// @Produce json
+// @Param id path string true "Vendor ID"
+// @Param vendor body models.UpdateVendorRequest true "Updated vendor data"
+// @Success 200 {object} models.Vendor
+// @Failure 400 {object} models.ValidationErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /vendors/{id} [put]
+func (h *VendorHandler) UpdateVendor(c *gin.Context) {
+	var req models.UpdateVendorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid JSON format",
+		})
+		return
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		validationErrors := h.formatValidationErrors(err)
+		c.JSON(http.StatusBadRequest, models.ValidationErrorResponse{
+			Error:  "Validation Error",
+			Errors: validationErrors,
+		})
+		return
+	}
+
+	vendor, err := h.vendorService.UpdateVendor(c.Param("id"), &req)
+	if err != nil {
+		if h.handleVendorError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to update vendor")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to update vendor",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, vendor)
+}
+
+// @Summary Delete a vendor
+// @Description Delete a vendor that no purchase order refers to
+// @Tags vendors
+// @Param id path string true "Vendor ID"
+// @Success 204
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /vendors/{id} [delete]
+func (h *VendorHandler) DeleteVendor(c *gin.Context) {
+	err := h.vendorService.DeleteVendor(c.Param("id"))
+	if err != nil {
+		if h.handleVendorError(c, err) {
+			return
+		}
+
+		h.logger.WithError(err).Error("Failed to delete vendor")
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "Internal Server Error",
+			Message: "Failed to delete vendor",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleVendorError writes the response for the errors the vendor
+// endpoints share, and reports whether err was one of them.
+func (h *VendorHandler) handleVendorError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrVendorNotFound):
+		writeVendorNotFound(c)
+	case errors.Is(err, services.ErrVendorInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "Conflict",
+			Message: "The vendor has purchase orders",
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *VendorHandler) formatValidationErrors(err error) []models.ValidationError {
+	return formatValidationErrors(err)
+}
+
+func writeVendorNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.ErrorResponse{
+		Error:   "Not Found",
+		Message: "Vendor not found",
+	})
+}
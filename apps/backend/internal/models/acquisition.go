@@ -0,0 +1,204 @@
+package models
+
+import (
+	"time"
+)
+
+// Vendor is a supplier the library orders copies from.
+type Vendor struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Email     *string   `json:"email,omitempty" db:"email"`
+	Phone     *string   `json:"phone,omitempty" db:"phone"`
+	Address   *string   `json:"address,omitempty" db:"address"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type VendorListResponse struct {
+	Data []Vendor `json:"data"`
+}
+
+type CreateVendorRequest struct {
+	Name    string  `json:"name" validate:"required,min=1,max=100"`
+	Email   *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Phone   *string `json:"phone,omitempty" validate:"omitempty,max=30"`
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateVendorRequest replaces the name and contact details of a vendor.
+type UpdateVendorRequest struct {
+	Name    string  `json:"name" validate:"required,min=1,max=100"`
+	Email   *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Phone   *string `json:"phone,omitempty" validate:"omitempty,max=30"`
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+}
+
+// Fund is the budget for a fiscal year that purchase order lines are paid
+// from. Encumbered is what the open orders still owe for copies not yet
+// received, Spent what has been received, and Available the budget left
+// after both; it is negative when the fund is overspent.
+type Fund struct {
+	ID              string    `json:"id" db:"id"`
+	Code            string    `json:"code" db:"code"`
+	Name            string    `json:"name" db:"name"`
+	FiscalYear      int       `json:"fiscal_year" db:"fiscal_year"`
+	BudgetCents     int       `json:"budget_cents" db:"budget_cents"`
+	EncumberedCents int       `json:"encumbered_cents" db:"encumbered_cents"`
+	SpentCents      int       `json:"spent_cents" db:"spent_cents"`
+	AvailableCents  int       `json:"available_cents" db:"available_cents"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FundListParams narrows the funds to a fiscal year.
+type FundListParams struct {
+	FiscalYear int `form:"fiscal_year" validate:"omitempty,min=1900,max=2200"`
+}
+
+// FundListResponse lists funds by fiscal year, newest first, and code, with
+// the sums of their budgets and spending.
+type FundListResponse struct {
+	Data            []Fund `json:"data"`
+	BudgetCents     int    `json:"budget_cents"`
+	EncumberedCents int    `json:"encumbered_cents"`
+	SpentCents      int    `json:"spent_cents"`
+	AvailableCents  int    `json:"available_cents"`
+}
+
+// CreateFundRequest adds a fund. Codes are unique within a fiscal year
+// ignoring case and are stored in upper case.
+type CreateFundRequest struct {
+	Code        string `json:"code" validate:"required,min=1,max=20,alphanum"`
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	FiscalYear  int    `json:"fiscal_year" validate:"required,min=1900,max=2200"`
+	BudgetCents int    `json:"budget_cents" validate:"min=0"`
+}
+
+// UpdateFundRequest replaces the code, name, fiscal year and budget of a
+// fund. A budget cut below what the fund has committed leaves it with a
+// negative balance.
+type UpdateFundRequest struct {
+	Code        string `json:"code" validate:"required,min=1,max=20,alphanum"`
+	Name        string `json:"name" validate:"required,min=1,max=100"`
+	FiscalYear  int    `json:"fiscal_year" validate:"required,min=1900,max=2200"`
+	BudgetCents int    `json:"budget_cents" validate:"min=0"`
+}
+
+// Statuses of a purchase order. Drafts can be edited and deleted; placing
+// an order commits its cost against its funds until its copies are received
+// or it is cancelled.
+const (
+	OrderDraft             = "draft"
+	OrderOrdered           = "ordered"
+	OrderPartiallyReceived = "partially_received"
+	OrderReceived          = "received"
+	OrderCancelled         = "cancelled"
+)
+
+// orderTransitions lists the statuses a purchase order can move to from
+// each status. Received and cancelled orders are closed.
+var orderTransitions = map[string][]string{
+	OrderDraft:             {OrderOrdered, OrderCancelled},
+	OrderOrdered:           {OrderPartiallyReceived, OrderReceived, OrderCancelled},
+	OrderPartiallyReceived: {OrderReceived, OrderCancelled},
+}
+
+// CanTransitionOrder reports whether a purchase order with status from may
+// change to status to.
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PurchaseOrder orders copies of catalog records from a vendor.
+type PurchaseOrder struct {
+	ID       string  `json:"id" db:"id"`
+	VendorID string  `json:"vendor_id" db:"vendor_id"`
+	Status   string  `json:"status" db:"status"`
+	Note     *string `json:"note,omitempty" db:"note"`
+	// TotalCents is the cost of every copy ordered.
+	TotalCents int        `json:"total_cents" db:"total_cents"`
+	OrderedAt  *time.Time `json:"ordered_at,omitempty" db:"ordered_at"`
+	// ClosedAt is when the last copy was received or the order cancelled.
+	ClosedAt  *time.Time          `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+	Lines     []PurchaseOrderLine `json:"lines"`
+}
+
+// PurchaseOrderLine orders Quantity copies of a book at a unit price, paid
+// from a fund. BookID is unset once the book has been purged; Title keeps
+// the title it had when the line was written.
+type PurchaseOrderLine struct {
+	ID               string  `json:"id" db:"id"`
+	OrderID          string  `json:"order_id" db:"order_id"`
+	BookID           *string `json:"book_id,omitempty" db:"book_id"`
+	Title            string  `json:"title" db:"title"`
+	FundID           string  `json:"fund_id" db:"fund_id"`
+	Quantity         int     `json:"quantity" db:"quantity"`
+	UnitPriceCents   int     `json:"unit_price_cents" db:"unit_price_cents"`
+	ReceivedQuantity int     `json:"received_quantity" db:"received_quantity"`
+}
+
+// CreatePurchaseOrderRequest drafts an order.
+type CreatePurchaseOrderRequest struct {
+	VendorID string                     `json:"vendor_id" validate:"required,uuid"`
+	Note     *string                    `json:"note,omitempty" validate:"omitempty,max=255"`
+	Lines    []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,max=100,dive"`
+}
+
+// UpdatePurchaseOrderRequest replaces the vendor, note and lines of a
+// draft.
+type UpdatePurchaseOrderRequest struct {
+	VendorID string                     `json:"vendor_id" validate:"required,uuid"`
+	Note     *string                    `json:"note,omitempty" validate:"omitempty,max=255"`
+	Lines    []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,max=100,dive"`
+}
+
+type PurchaseOrderLineRequest struct {
+	BookID         string `json:"book_id" validate:"required,uuid"`
+	FundID         string `json:"fund_id" validate:"required,uuid"`
+	Quantity       int    `json:"quantity" validate:"required,min=1,max=1000"`
+	UnitPriceCents int    `json:"unit_price_cents" validate:"min=0"`
+}
+
+// PurchaseOrderListParams pages through orders, newest first. FundID
+// matches orders with a line paid from a fund.
+type PurchaseOrderListParams struct {
+	Status   string `form:"status" validate:"omitempty,oneof=draft ordered partially_received received cancelled"`
+	VendorID string `form:"vendor_id" validate:"omitempty,uuid"`
+	FundID   string `form:"fund_id" validate:"omitempty,uuid"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+type PurchaseOrderListResponse struct {
+	Data  []PurchaseOrder `json:"data"`
+	Total int             `json:"total"`
+}
+
+// ReceivePurchaseOrderRequest records a delivery against a placed order.
+// Each line received adds a copy per barcode, at HomeBranchID and on the
+// shelf at Location when they are set.
+type ReceivePurchaseOrderRequest struct {
+	Lines []ReceiveOrderLineRequest `json:"lines" validate:"required,min=1,max=100,dive"`
+}
+
+type ReceiveOrderLineRequest struct {
+	LineID       string   `json:"line_id" validate:"required,uuid"`
+	Barcodes     []string `json:"barcodes" validate:"required,min=1,max=1000,dive,required,max=50"`
+	Location     *string  `json:"location,omitempty" validate:"omitempty,max=100"`
+	HomeBranchID *string  `json:"home_branch_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// ReceivePurchaseOrderResponse is the order after a delivery and the copies
+// it added to the catalog.
+type ReceivePurchaseOrderResponse struct {
+	Order *PurchaseOrder `json:"order"`
+	Items []Item         `json:"items"`
+}
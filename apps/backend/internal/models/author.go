@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+// Contributor roles a person can have on a book.
+const (
+	ContributorAuthor      = "author"
+	ContributorEditor      = "editor"
+	ContributorTranslator  = "translator"
+	ContributorIllustrator = "illustrator"
+)
+
+// Author is a person or organization credited on books. Two spellings of a
+// name that only differ in case, punctuation or word order, such as
+// "J.R.R. Tolkien" and "Tolkien, J.R.R.", are the same author.
+type Author struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	SortName  string    `json:"sort_name" db:"sort_name"`
+	BirthYear *int      `json:"birth_year,omitempty" db:"birth_year"`
+	DeathYear *int      `json:"death_year,omitempty" db:"death_year"`
+	Biography *string   `json:"biography,omitempty" db:"biography"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateAuthorRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+	// SortName defaults to the name inverted as "Family, Given".
+	SortName  *string `json:"sort_name,omitempty" validate:"omitempty,min=1,max=255"`
+	BirthYear *int    `json:"birth_year,omitempty" validate:"omitempty,max=9999"`
+	DeathYear *int    `json:"death_year,omitempty" validate:"omitempty,max=9999"`
+	Biography *string `json:"biography,omitempty" validate:"omitempty,max=5000"`
+}
+
+type UpdateAuthorRequest struct {
+	Name      string  `json:"name" validate:"required,min=1,max=255"`
+	SortName  *string `json:"sort_name,omitempty" validate:"omitempty,min=1,max=255"`
+	BirthYear *int    `json:"birth_year,omitempty" validate:"omitempty,max=9999"`
+	DeathYear *int    `json:"death_year,omitempty" validate:"omitempty,max=9999"`
+	Biography *string `json:"biography,omitempty" validate:"omitempty,max=5000"`
+}
+
+// AuthorListParams pages through authors in sort name order.
+type AuthorListParams struct {
+	Query  string `form:"q" validate:"omitempty,max=255"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+type AuthorListResponse struct {
+	Data       []Author `json:"data"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	Total      int      `json:"total"`
+}
+
+// AuthorBooksParams pages through the books an author contributed to,
+// oldest first.
+type AuthorBooksParams struct {
+	Role   string `form:"role" validate:"omitempty,oneof=author editor translator illustrator"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+// AuthorBook is a book together with the roles the author had on it.
+type AuthorBook struct {
+	Book
+	Roles []string `json:"roles"`
+}
+
+type AuthorBooksResponse struct {
+	Data  []AuthorBook `json:"data"`
+	Total int          `json:"total"`
+}
+
+// BookContributor credits an author on a book. Contributors are listed by
+// position.
+type BookContributor struct {
+	AuthorID string `json:"author_id"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Position int    `json:"position"`
+}
+
+type BookContributorsResponse struct {
+	BookID  string            `json:"book_id"`
+	Version int               `json:"version"`
+	Data    []BookContributor `json:"data"`
+}
+
+type ContributorInput struct {
+	AuthorID string `json:"author_id" validate:"required,uuid"`
+	Role     string `json:"role" validate:"required,oneof=author editor translator illustrator"`
+}
+
+// SetContributorsRequest replaces the contributors of a book, in order.
+type SetContributorsRequest struct {
+	Contributors []ContributorInput `json:"contributors" validate:"required,min=1,max=50,dive"`
+}
@@ -5,15 +5,21 @@ import (
 )
 
 type Book struct {
-	ID          string    `json:"id" db:"id"`
-	Title       string    `json:"title" db:"title" validate:"required,min=1,max=255"`
-	Author      string    `json:"author" db:"author" validate:"required,min=1,max=255"`
-	Year        int       `json:"year" db:"year" validate:"required,min=1000"`
-	Description *string   `json:"description,omitempty" db:"description" validate:"omitempty,max=1000"`
-	ISBN        *string   `json:"isbn,omitempty" db:"isbn" validate:"omitempty,max=20"`
-	Genre       *string   `json:"genre,omitempty" db:"genre" validate:"omitempty,max=100"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          string     `json:"id" db:"id"`
+	Title       string     `json:"title" db:"title" validate:"required,min=1,max=255"`
+	Author      string     `json:"author" db:"author" validate:"required,min=1,max=255"`
+	Year        int        `json:"year" db:"year" validate:"required,min=1000"`
+	Description *string    `json:"description,omitempty" db:"description" validate:"omitempty,max=1000"`
+	ISBN        *string    `json:"isbn,omitempty" db:"isbn" validate:"omitempty,book_isbn"`
+	Genre       *string    `json:"genre,omitempty" db:"genre" validate:"omitempty,max=100"`
+	Language    string     `json:"language" db:"language"`
+	Version     int        `json:"version" db:"version"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Availability summarizes the copies of the book. Only single-book
+	// responses include it.
+	Availability *ItemAvailability `json:"availability,omitempty" db:"-"`
 }
 
 type CreateBookRequest struct {
@@ -21,8 +27,9 @@ type CreateBookRequest struct {
 	Author      string  `json:"author" validate:"required,min=1,max=255"`
 	Year        int     `json:"year" validate:"required,min=1000"`
 	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	ISBN        *string `json:"isbn,omitempty" validate:"omitempty,max=20"`
+	ISBN        *string `json:"isbn,omitempty" validate:"omitempty,book_isbn"`
 	Genre       *string `json:"genre,omitempty" validate:"omitempty,max=100"`
+	Language    *string `json:"language,omitempty" validate:"omitempty,search_language"`
 }
 
 type UpdateBookRequest struct {
@@ -30,8 +37,147 @@ type UpdateBookRequest struct {
 	Author      string  `json:"author" validate:"required,min=1,max=255"`
 	Year        int     `json:"year" validate:"required,min=1000"`
 	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	ISBN        *string `json:"isbn,omitempty" validate:"omitempty,max=20"`
+	ISBN        *string `json:"isbn,omitempty" validate:"omitempty,book_isbn"`
 	Genre       *string `json:"genre,omitempty" validate:"omitempty,max=100"`
+	Language    *string `json:"language,omitempty" validate:"omitempty,search_language"`
+}
+
+// Editable returns the fields of the book that clients may change, in the
+// shape PATCH documents are applied to.
+func (b *Book) Editable() UpdateBookRequest {
+	language := b.Language
+	return UpdateBookRequest{
+		Title:       b.Title,
+		Author:      b.Author,
+		Year:        b.Year,
+		Description: b.Description,
+		ISBN:        b.ISBN,
+		Genre:       b.Genre,
+		Language:    &language,
+	}
+}
+
+type BookListParams struct {
+	// Format renders the page in a citation format instead of JSON.
+	Format   string `form:"format" validate:"omitempty,oneof=json bibtex ris csl-json"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor   string `form:"cursor"`
+	Author   string `form:"author" validate:"omitempty,max=255"`
+	Genre    string `form:"genre" validate:"omitempty,max=100"`
+	YearFrom *int   `form:"year_from" validate:"omitempty,min=0"`
+	YearTo   *int   `form:"year_to" validate:"omitempty,min=0"`
+	HasISBN  *bool  `form:"has_isbn"`
+	// BranchID narrows the books to those with a copy at a branch that is
+	// not withdrawn.
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+	Sort     string `form:"sort" validate:"omitempty,oneof=title author year created_at"`
+	Order    string `form:"order" validate:"omitempty,oneof=asc desc"`
+	// GenreIDs are the IDs of Genre and its subgenres, resolved by the
+	// service. A book matches the genre filter when it has one of them.
+	GenreIDs []string `form:"-"`
+}
+
+const (
+	BookExportCSV    = "csv"
+	BookExportJSON   = "json"
+	BookExportNDJSON = "ndjson"
+	// BookExportMARC and BookExportMARCXML export MARC 21 records.
+	BookExportMARC    = BookMARCBinary
+	BookExportMARCXML = BookMARCXML
+	// The citation formats reference managers import.
+	BookExportBibTeX  = "bibtex"
+	BookExportRIS     = "ris"
+	BookExportCSLJSON = "csl-json"
+)
+
+// BookExportParams selects the books to export with the same filters and
+// ordering as BookListParams, but without paging.
+type BookExportParams struct {
+	Format   string `form:"format" validate:"omitempty,oneof=csv json ndjson marc marcxml bibtex ris csl-json"`
+	Author   string `form:"author" validate:"omitempty,max=255"`
+	Genre    string `form:"genre" validate:"omitempty,max=100"`
+	YearFrom *int   `form:"year_from" validate:"omitempty,min=0"`
+	YearTo   *int   `form:"year_to" validate:"omitempty,min=0"`
+	HasISBN  *bool  `form:"has_isbn"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+	Sort     string `form:"sort" validate:"omitempty,oneof=title author year created_at"`
+	Order    string `form:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// ListParams returns the list parameters with the same filters and ordering.
+func (p *BookExportParams) ListParams() BookListParams {
+	return BookListParams{
+		Author:   p.Author,
+		Genre:    p.Genre,
+		YearFrom: p.YearFrom,
+		YearTo:   p.YearTo,
+		HasISBN:  p.HasISBN,
+		BranchID: p.BranchID,
+		Sort:     p.Sort,
+		Order:    p.Order,
+	}
+}
+
+// BookFormatParams selects the representation of a single book.
+type BookFormatParams struct {
+	Format string `form:"format" validate:"omitempty,oneof=json bibtex ris csl-json"`
+}
+
+const (
+	CitationAPA     = "apa"
+	CitationMLA     = "mla"
+	CitationChicago = "chicago"
+)
+
+type BookCitationParams struct {
+	Style string `form:"style" validate:"omitempty,oneof=apa mla chicago"`
+}
+
+// BookCitation is a formatted reference to a book. HTML italicizes the
+// title the way the style requires; Text is the same citation without
+// markup.
+type BookCitation struct {
+	Style string `json:"style"`
+	Text  string `json:"text"`
+	HTML  string `json:"html"`
+}
+
+// TrashListParams pages through trashed books, most recently deleted first.
+type TrashListParams struct {
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+type BookListResponse struct {
+	Data       []Book `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+type BookSearchParams struct {
+	Query  string `form:"q" validate:"required,min=1,max=255"`
+	Lang   string `form:"lang" validate:"omitempty,search_language"`
+	Genre  string `form:"genre" validate:"omitempty,max=100"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" validate:"omitempty,min=0"`
+	// GenreIDs are resolved from Genre like BookListParams.GenreIDs.
+	GenreIDs []string `form:"-"`
+}
+
+type BookSearchHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+type BookSearchResult struct {
+	Book
+	Rank       float64              `json:"rank"`
+	Highlights BookSearchHighlights `json:"highlights"`
+}
+
+type BookSearchResponse struct {
+	Data  []BookSearchResult `json:"data"`
+	Total int                `json:"total"`
 }
 
 type URLProcessRequest struct {
@@ -0,0 +1,78 @@
+package models
+
+const (
+	// BookImportTransactional creates every row or none: a single rejected
+	// row means nothing is written.
+	BookImportTransactional = "transactional"
+	// BookImportBestEffort creates every valid row and reports the rest.
+	BookImportBestEffort = "best_effort"
+)
+
+const (
+	BookImportCreated   = "created"
+	BookImportUpdated   = "updated"
+	BookImportDuplicate = "duplicate"
+	BookImportRejected  = "rejected"
+)
+
+const (
+	BookMARCBinary = "marc"
+	BookMARCXML    = "marcxml"
+)
+
+// BookImportFields are the book fields a CSV column can be mapped to.
+var BookImportFields = []string{"title", "author", "year", "description", "isbn", "genre", "language"}
+
+type BookImportParams struct {
+	Mode   string `form:"mode" validate:"omitempty,oneof=transactional best_effort"`
+	DryRun bool   `form:"dry_run"`
+	// Mapping is a JSON object from book field to CSV header, for files
+	// whose headers do not match the field names.
+	Mapping string `form:"mapping"`
+}
+
+// BookMARCImportParams configures a MARC import. Without a format the file
+// is read as MARCXML when it starts with "<" and as binary MARC otherwise.
+type BookMARCImportParams struct {
+	Format string `form:"format" validate:"omitempty,oneof=marc marcxml"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type BookMARCParams struct {
+	Format string `form:"format" validate:"omitempty,oneof=marc marcxml"`
+}
+
+// BookImportRecord is one decoded CSV row or MARC record. Errors holds the
+// problems found while decoding or validating it; a record with errors is
+// rejected.
+type BookImportRecord struct {
+	// Line is the CSV line, or the position of the record in a MARC file.
+	Line int
+	// ID is the book the record claims to describe, if any.
+	ID     string
+	Book   CreateBookRequest
+	Errors []ValidationError
+}
+
+type BookImportRowResult struct {
+	Line   int               `json:"line"`
+	Status string            `json:"status"`
+	BookID string            `json:"book_id,omitempty"`
+	Title  string            `json:"title,omitempty"`
+	ISBN   string            `json:"isbn,omitempty"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// BookImportReport describes what happened to every row. When Committed is
+// false nothing was written and the rows describe what would have happened.
+type BookImportReport struct {
+	Mode       string                `json:"mode"`
+	DryRun     bool                  `json:"dry_run"`
+	Committed  bool                  `json:"committed"`
+	Total      int                   `json:"total"`
+	Created    int                   `json:"created"`
+	Updated    int                   `json:"updated"`
+	Duplicates int                   `json:"duplicates"`
+	Rejected   int                   `json:"rejected"`
+	Rows       []BookImportRowResult `json:"rows"`
+}
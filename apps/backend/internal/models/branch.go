@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+)
+
+// Branch is a library location copies are shelved at and lent from. Its code
+// is the short name shown on spine labels and transit slips.
+type Branch struct {
+	ID        string    `json:"id" db:"id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	Address   *string   `json:"address,omitempty" db:"address"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type BranchListResponse struct {
+	Data []Branch `json:"data"`
+}
+
+// CreateBranchRequest adds a branch. Codes are unique ignoring case and are
+// stored in upper case.
+type CreateBranchRequest struct {
+	Code    string  `json:"code" validate:"required,min=1,max=20,alphanum"`
+	Name    string  `json:"name" validate:"required,min=1,max=100"`
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateBranchRequest replaces the code, name and address of a branch.
+type UpdateBranchRequest struct {
+	Code    string  `json:"code" validate:"required,min=1,max=20,alphanum"`
+	Name    string  `json:"name" validate:"required,min=1,max=100"`
+	Address *string `json:"address,omitempty" validate:"omitempty,max=255"`
+}
+
+// Statuses of a transfer. Requested and in-transit transfers are open; a
+// copy has at most one open transfer.
+const (
+	TransferRequested = "requested"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCancelled = "cancelled"
+)
+
+// Transfer moves a copy from one branch to another. Staff request a
+// transfer of a copy on the shelf, ship it, which puts the copy in transit,
+// and receive it at the other branch. Checkins and released holds ship
+// copies themselves, to the pickup branch of the hold they were trapped for
+// or back to their home branch.
+type Transfer struct {
+	ID           string `json:"id" db:"id"`
+	ItemID       string `json:"item_id" db:"item_id"`
+	Barcode      string `json:"barcode" db:"barcode"`
+	FromBranchID string `json:"from_branch_id" db:"from_branch_id"`
+	ToBranchID   string `json:"to_branch_id" db:"to_branch_id"`
+	// HoldID is the hold the copy travels to be picked up for, if any.
+	HoldID      *string    `json:"hold_id,omitempty" db:"hold_id"`
+	Status      string     `json:"status" db:"status"`
+	Note        *string    `json:"note,omitempty" db:"note"`
+	RequestedAt time.Time  `json:"requested_at" db:"requested_at"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty" db:"shipped_at"`
+	ReceivedAt  *time.Time `json:"received_at,omitempty" db:"received_at"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreateTransferRequest asks for the copy with a barcode, which must be on
+// the shelf at another branch, to be sent to ToBranchID.
+type CreateTransferRequest struct {
+	Barcode    string  `json:"barcode" validate:"required,min=1,max=50"`
+	ToBranchID string  `json:"to_branch_id" validate:"required,uuid"`
+	Note       *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+// TransferListParams pages through transfers, most recently requested
+// first. BranchID matches transfers from or to a branch.
+type TransferListParams struct {
+	Status   string `form:"status" validate:"omitempty,oneof=requested in_transit received cancelled"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+type TransferListResponse struct {
+	Data  []Transfer `json:"data"`
+	Total int        `json:"total"`
+}
+
+// ReceiveTransferResponse is the transfer a receipt closed. Hold is the hold
+// the copy was trapped for at the receiving branch, if any; the copy then
+// goes to the hold shelf, or on to the next transfer, instead of the shelf.
+type ReceiveTransferResponse struct {
+	Transfer *Transfer `json:"transfer"`
+	Hold     *Hold     `json:"hold,omitempty"`
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+)
+
+// OpeningHours are the hours the library, or one branch, is open on a day
+// of the week. Times are HH:MM in the library's time zone.
+type OpeningHours struct {
+	// BranchID is empty for the library's hours, which every branch without
+	// hours of its own keeps to.
+	BranchID *string `json:"branch_id,omitempty" db:"branch_id"`
+	// Weekday counts from 0 for Sunday to 6 for Saturday.
+	Weekday int    `json:"weekday" db:"weekday"`
+	Opens   string `json:"opens" db:"opens"`
+	Closes  string `json:"closes" db:"closes"`
+}
+
+// OpeningHoursParams asks for the hours a branch keeps to, or the library's
+// without one.
+type OpeningHoursParams struct {
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+}
+
+// OpeningHoursResponse is the weekly schedule. A weekday missing from it is
+// closed, unless no hours are set at all, when every day is open.
+type OpeningHoursResponse struct {
+	TimeZone string `json:"time_zone"`
+	// BranchID is the branch asked for. Its hours are the library's when
+	// it has none of its own.
+	BranchID *string        `json:"branch_id,omitempty"`
+	Data     []OpeningHours `json:"data"`
+}
+
+// SetOpeningHoursRequest replaces the weekly schedule of the library, or of
+// a branch. Weekdays left out are closed; an empty list opens every day, or
+// for a branch drops its own hours so that it keeps to the library's.
+type SetOpeningHoursRequest struct {
+	BranchID *string               `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+	Hours    []OpeningHoursRequest `json:"hours" validate:"max=7,dive"`
+}
+
+type OpeningHoursRequest struct {
+	Weekday int    `json:"weekday" validate:"min=0,max=6"`
+	Opens   string `json:"opens" validate:"required,datetime=15:04"`
+	Closes  string `json:"closes" validate:"required,datetime=15:04"`
+}
+
+// Closure is a holiday or other run of days the library is closed, from
+// StartsOn to EndsOn inclusive. A closure for a branch only closes that
+// branch.
+type Closure struct {
+	ID       string  `json:"id" db:"id"`
+	BranchID *string `json:"branch_id,omitempty" db:"branch_id"`
+	Name     string  `json:"name" db:"name"`
+	StartsOn string  `json:"starts_on" db:"starts_on"`
+	EndsOn   string  `json:"ends_on" db:"ends_on"`
+	// Annual closures, such as public holidays on fixed dates, recur on the
+	// same days every year.
+	Annual    bool      `json:"annual" db:"annual"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ClosureRequest creates or replaces a closure. An annual closure may run
+// into the next year, such as from December 24 to January 2, but not last
+// a year or more.
+type ClosureRequest struct {
+	BranchID *string `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+	Name     string  `json:"name" validate:"required,min=1,max=100"`
+	StartsOn string  `json:"starts_on" validate:"required,datetime=2006-01-02"`
+	EndsOn   string  `json:"ends_on" validate:"required,datetime=2006-01-02"`
+	Annual   bool    `json:"annual"`
+}
+
+// ClosureListParams limits closures to those overlapping From to To, either
+// of which may be left out, and to those closing a branch, its own and the
+// library's. Annual closures are always listed.
+type ClosureListParams struct {
+	From     string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+}
+
+type ClosureListResponse struct {
+	Data []Closure `json:"data"`
+}
+
+// OpenDayParams asks for the first day the library, or a branch, is open
+// on or after Date, which defaults to today.
+type OpenDayParams struct {
+	Date     string `form:"date" validate:"omitempty,datetime=2006-01-02"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+}
+
+type OpenDayResponse struct {
+	Date   string `json:"date"`
+	OpenOn string `json:"open_on"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// Kinds of ledger entry. Charges and refunds add to what a member owes;
+// payments and waivers take from it.
+const (
+	LedgerCharge  = "charge"
+	LedgerPayment = "payment"
+	LedgerWaiver  = "waiver"
+	LedgerRefund  = "refund"
+)
+
+// Categories of charge. Overdue fines are charged automatically from the
+// loan history; the others are charged by staff.
+const (
+	FeeOverdue    = "overdue"
+	FeeLostItem   = "lost_item"
+	FeeProcessing = "processing"
+	FeeOther      = "other"
+)
+
+// LedgerEntry is one line of a member's fines ledger. Entries are never
+// changed once recorded; a charge is settled by later payments or waivers.
+type LedgerEntry struct {
+	ID       string `json:"id" db:"id"`
+	MemberID string `json:"member_id" db:"member_id"`
+	// LoanID is the loan a charge is for, if any.
+	LoanID *string `json:"loan_id,omitempty" db:"loan_id"`
+	Kind   string  `json:"kind" db:"kind"`
+	// Category is set on charges only.
+	Category    *string `json:"category,omitempty" db:"category"`
+	AmountCents int     `json:"amount_cents" db:"amount_cents"`
+	// BalanceCents is what the member owed once the entry was recorded.
+	BalanceCents int       `json:"balance_cents" db:"balance_cents"`
+	Note         *string   `json:"note,omitempty" db:"note"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// ChargeRequest charges a member a fee. Overdue fines are not charged by
+// hand.
+type ChargeRequest struct {
+	Category    string  `json:"category" validate:"required,oneof=lost_item processing other"`
+	AmountCents int     `json:"amount_cents" validate:"required,min=1"`
+	LoanID      *string `json:"loan_id,omitempty" validate:"omitempty,uuid"`
+	Note        *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+// SettlementRequest records a payment, waiver or refund.
+type SettlementRequest struct {
+	AmountCents int     `json:"amount_cents" validate:"required,min=1"`
+	Note        *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+// LedgerListParams pages through a member's ledger, newest entry first.
+type LedgerListParams struct {
+	Kind   string `form:"kind" validate:"omitempty,oneof=charge payment waiver refund"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+type LedgerListResponse struct {
+	Data  []LedgerEntry `json:"data"`
+	Total int           `json:"total"`
+	// BalanceCents is what the member owes now, AccruingCents of it in
+	// overdue fines not yet charged to the ledger.
+	BalanceCents  int `json:"balance_cents"`
+	AccruingCents int `json:"accruing_cents"`
+}
+
+// MemberBalance is what a member owes and whether it stops them borrowing.
+type MemberBalance struct {
+	MemberID string `json:"member_id"`
+	// BalanceCents is what the member owes now, AccruingCents of it in
+	// overdue fines not yet charged to the ledger.
+	BalanceCents  int `json:"balance_cents"`
+	AccruingCents int `json:"accruing_cents"`
+	// LimitCents is the balance above which a member may not borrow, or 0
+	// when there is no limit.
+	LimitCents      int  `json:"limit_cents"`
+	CheckoutBlocked bool `json:"checkout_blocked"`
+}
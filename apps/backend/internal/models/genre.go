@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Genre is a node of the genre taxonomy. Names and aliases are unique
+// ignoring case and punctuation, so "Sci-Fi" and "scifi" are one genre.
+type Genre struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	ParentID  *string   `json:"parent_id,omitempty" db:"parent_id"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GenreNode is a genre with its subgenres.
+type GenreNode struct {
+	Genre
+	Children []GenreNode `json:"children"`
+}
+
+type GenreTreeResponse struct {
+	Data []GenreNode `json:"data"`
+}
+
+type CreateGenreRequest struct {
+	Name     string   `json:"name" validate:"required,min=1,max=100"`
+	ParentID *string  `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	Aliases  []string `json:"aliases,omitempty" validate:"omitempty,max=20,dive,required,max=100"`
+}
+
+// UpdateGenreRequest replaces the name, parent and aliases of a genre.
+// Moving a genre under one of its own subgenres is rejected.
+type UpdateGenreRequest struct {
+	Name     string   `json:"name" validate:"required,min=1,max=100"`
+	ParentID *string  `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	Aliases  []string `json:"aliases,omitempty" validate:"omitempty,max=20,dive,required,max=100"`
+}
+
+// MergeGenreRequest names the genre another genre is merged into.
+type MergeGenreRequest struct {
+	IntoID string `json:"into_id" validate:"required,uuid"`
+}
+
+type BookGenresResponse struct {
+	BookID  string  `json:"book_id"`
+	Version int     `json:"version"`
+	Data    []Genre `json:"data"`
+}
+
+// SetBookGenresRequest replaces the genres of a book. The first genre is the
+// book's primary genre, shown in its genre field.
+type SetBookGenresRequest struct {
+	GenreIDs []string `json:"genre_ids" validate:"max=20,dive,uuid"`
+}
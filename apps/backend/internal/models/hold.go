@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+)
+
+// Statuses of a hold. Waiting, in-transit and ready holds are active; the
+// others are kept as history. An in-transit hold has a copy trapped for it
+// on its way to the pickup branch.
+const (
+	HoldWaiting   = "waiting"
+	HoldInTransit = "in_transit"
+	HoldReady     = "ready"
+	HoldFulfilled = "fulfilled"
+	HoldCancelled = "cancelled"
+	HoldExpired   = "expired"
+)
+
+// Levels of a hold. A title-level hold is filled by any copy of the book and
+// an item-level hold only by the copy it names.
+const (
+	HoldLevelTitle = "title"
+	HoldLevelItem  = "item"
+)
+
+// Hold queues a member for a book. When a copy that can fill the first
+// waiting hold is checked in it is trapped on the hold shelf for that member
+// until PickupBy, after travelling to the pickup branch if it is elsewhere.
+type Hold struct {
+	ID       string `json:"id" db:"id"`
+	BookID   string `json:"book_id" db:"book_id"`
+	MemberID string `json:"member_id" db:"member_id"`
+	// ItemID is the copy an item-level hold asks for, or the copy trapped
+	// for a title-level hold once it is in transit or ready.
+	ItemID *string `json:"item_id,omitempty" db:"item_id"`
+	Level  string  `json:"level" db:"level"`
+	Status string  `json:"status" db:"status"`
+	// PickupBranchID is the branch the member collects the copy at. Holds
+	// without one are collected wherever the copy is trapped.
+	PickupBranchID *string `json:"pickup_branch_id,omitempty" db:"pickup_branch_id"`
+	// Position is the place of a waiting hold in the queue of its book,
+	// counting from 1. Suspended holds keep their place.
+	Position int `json:"position,omitempty" db:"position"`
+	// SuspendedUntil is the last day a waiting hold is passed over,
+	// formatted as YYYY-MM-DD.
+	SuspendedUntil *string `json:"suspended_until,omitempty" db:"suspended_until"`
+	// PickupBy is the last day a ready hold's copy waits on the hold shelf,
+	// formatted as YYYY-MM-DD.
+	PickupBy  *string    `json:"pickup_by,omitempty" db:"pickup_by"`
+	PlacedAt  time.Time  `json:"placed_at" db:"placed_at"`
+	ReadyAt   *time.Time `json:"ready_at,omitempty" db:"ready_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PlaceHoldRequest places a title-level hold, or an item-level hold when
+// ItemID is given.
+type PlaceHoldRequest struct {
+	MemberID       string  `json:"member_id" validate:"required,uuid"`
+	ItemID         *string `json:"item_id,omitempty" validate:"omitempty,uuid"`
+	PickupBranchID *string `json:"pickup_branch_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type SuspendHoldRequest struct {
+	// Until is the last day of the suspension, formatted as YYYY-MM-DD.
+	Until string `json:"until" validate:"required,datetime=2006-01-02"`
+}
+
+type MoveHoldRequest struct {
+	Position int `json:"position" validate:"required,min=1"`
+}
+
+// HoldListParams pages through holds, most recently placed first.
+// BranchID narrows them to holds picked up at a branch.
+type HoldListParams struct {
+	Status   string `form:"status" validate:"omitempty,oneof=waiting in_transit ready fulfilled cancelled expired"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+type HoldListResponse struct {
+	Data  []Hold `json:"data"`
+	Total int    `json:"total"`
+}
+
+// BookHoldsResponse lists the active holds on a book: ready holds first,
+// then in-transit holds, then the waiting holds in queue order.
+type BookHoldsResponse struct {
+	BookID string `json:"book_id"`
+	Data   []Hold `json:"data"`
+}
+
+// CheckinResponse is the loan a checkin ended. Hold is the hold the copy was
+// trapped for, if any; the copy then goes to the hold shelf, or in transit
+// to the pickup branch, instead of back on the shelf.
+type CheckinResponse struct {
+	Loan *Loan `json:"loan"`
+	Hold *Hold `json:"hold,omitempty"`
+}
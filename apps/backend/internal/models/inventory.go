@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// Statuses of an inventory session. Barcodes are only scanned into open
+// sessions, and statuses only updated from closed ones.
+const (
+	InventoryOpen   = "open"
+	InventoryClosed = "closed"
+)
+
+// InventorySession is a stocktake of the copies at a branch, on a range of
+// shelves, or both; a session with neither covers the whole collection.
+// The shelf range compares locations byte by byte and includes both ends;
+// either end may be left open.
+type InventorySession struct {
+	ID           string     `json:"id" db:"id"`
+	BranchID     *string    `json:"branch_id,omitempty" db:"branch_id"`
+	LocationFrom *string    `json:"location_from,omitempty" db:"location_from"`
+	LocationTo   *string    `json:"location_to,omitempty" db:"location_to"`
+	Status       string     `json:"status" db:"status"`
+	Note         *string    `json:"note,omitempty" db:"note"`
+	Scanned      int        `json:"scanned" db:"scanned"`
+	OpenedAt     time.Time  `json:"opened_at" db:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Covers reports whether a copy is in the part of the collection a session
+// takes stock of.
+func (s *InventorySession) Covers(item *Item) bool {
+	if s.BranchID != nil && (item.CurrentBranchID == nil || *item.CurrentBranchID != *s.BranchID) {
+		return false
+	}
+	if s.LocationFrom == nil && s.LocationTo == nil {
+		return true
+	}
+	if item.Location == nil {
+		return false
+	}
+	if s.LocationFrom != nil && *item.Location < *s.LocationFrom {
+		return false
+	}
+	return s.LocationTo == nil || *item.Location <= *s.LocationTo
+}
+
+type OpenInventoryRequest struct {
+	BranchID     *string `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+	LocationFrom *string `json:"location_from,omitempty" validate:"omitempty,min=1,max=100"`
+	LocationTo   *string `json:"location_to,omitempty" validate:"omitempty,min=1,max=100"`
+	Note         *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+// InventoryListParams pages through sessions, most recently opened first.
+type InventoryListParams struct {
+	Status   string `form:"status" validate:"omitempty,oneof=open closed"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+type InventoryListResponse struct {
+	Data  []InventorySession `json:"data"`
+	Total int                `json:"total"`
+}
+
+// InventoryScanRequest is a batch of barcodes read at the shelf. Scanners
+// may send them one at a time or many at once; a barcode scanned again is
+// only counted once.
+type InventoryScanRequest struct {
+	Barcodes []string `json:"barcodes" validate:"required,min=1,max=500,dive,required,max=50"`
+}
+
+// InventoryScanResponse counts the barcodes of a batch that were new to the
+// session and those already scanned, with the session's running total.
+type InventoryScanResponse struct {
+	Recorded int `json:"recorded"`
+	Repeated int `json:"repeated"`
+	Scanned  int `json:"scanned"`
+}
+
+// InventoryReport compares the barcodes scanned in a session with the
+// copies it covers, as they are now.
+//   - Missing are the copies that should be on the shelf, being covered
+//     and available, but were not scanned.
+//   - WrongLocation are the copies scanned that the session does not
+//     cover, being at another branch or shelved outside the range.
+//   - Found are the copies scanned that are marked lost.
+//   - UnknownBarcodes were scanned but match no copy.
+type InventoryReport struct {
+	Session         *InventorySession `json:"session"`
+	Expected        int               `json:"expected"`
+	Missing         []Item            `json:"missing"`
+	WrongLocation   []Item            `json:"wrong_location"`
+	Found           []Item            `json:"found"`
+	UnknownBarcodes []string          `json:"unknown_barcodes"`
+}
+
+// ApplyInventoryRequest picks the status updates to make from the report
+// of a closed session.
+type ApplyInventoryRequest struct {
+	// MarkMissingLost marks the missing copies lost.
+	MarkMissingLost bool `json:"mark_missing_lost"`
+	// MarkFoundAvailable makes the lost copies that were found available.
+	MarkFoundAvailable bool `json:"mark_found_available"`
+}
+
+// InventorySkippedItem is a copy whose status could not be updated, such as
+// one checked out since the report or a found copy still on loan. Status is
+// the status it was to get.
+type InventorySkippedItem struct {
+	ItemID  string `json:"item_id"`
+	Barcode string `json:"barcode"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason"`
+}
+
+type ApplyInventoryResponse struct {
+	Updated []Item                 `json:"updated"`
+	Skipped []InventorySkippedItem `json:"skipped"`
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"time"
+)
+
+// Statuses of a physical copy.
+const (
+	ItemAvailable = "available"
+	ItemOnLoan    = "on_loan"
+	ItemOnHold    = "on_hold"
+	ItemInTransit = "in_transit"
+	ItemLost      = "lost"
+	ItemWithdrawn = "withdrawn"
+)
+
+// Conditions of a physical copy.
+const (
+	ItemConditionNew     = "new"
+	ItemConditionGood    = "good"
+	ItemConditionFair    = "fair"
+	ItemConditionPoor    = "poor"
+	ItemConditionDamaged = "damaged"
+)
+
+// itemTransitions lists the statuses a copy can move to from each status.
+// Withdrawn copies stay withdrawn, and copies on the hold shelf only leave
+// it through circulation.
+var itemTransitions = map[string][]string{
+	ItemAvailable: {ItemOnLoan, ItemInTransit, ItemLost, ItemWithdrawn},
+	ItemOnLoan:    {ItemAvailable, ItemLost},
+	ItemInTransit: {ItemAvailable, ItemLost},
+	ItemLost:      {ItemAvailable, ItemWithdrawn},
+}
+
+// CanTransitionItem reports whether a copy with status from may change to
+// status to.
+func CanTransitionItem(from, to string) bool {
+	for _, next := range itemTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Item is a physical copy of a book, identified on the shelf by its barcode.
+type Item struct {
+	ID      string `json:"id" db:"id"`
+	BookID  string `json:"book_id" db:"book_id"`
+	Barcode string `json:"barcode" db:"barcode"`
+	// Location is the shelf the copy belongs on.
+	Location *string `json:"location,omitempty" db:"location"`
+	// HomeBranchID is the branch the copy belongs to and returns to, and
+	// CurrentBranchID the branch it was last at. Copies in transit are
+	// still at the branch they left.
+	HomeBranchID    *string `json:"home_branch_id,omitempty" db:"home_branch_id"`
+	CurrentBranchID *string `json:"current_branch_id,omitempty" db:"current_branch_id"`
+	// AcquiredOn is a date formatted as YYYY-MM-DD.
+	AcquiredOn      *string   `json:"acquired_on,omitempty" db:"acquired_on"`
+	PriceCents      *int      `json:"price_cents,omitempty" db:"price_cents"`
+	Condition       string    `json:"condition" db:"condition"`
+	Status          string    `json:"status" db:"status"`
+	StatusNote      *string   `json:"status_note,omitempty" db:"status_note"`
+	StatusChangedAt time.Time `json:"status_changed_at" db:"status_changed_at"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateItemRequest adds a copy at its home branch, if it has one.
+type CreateItemRequest struct {
+	Barcode      string  `json:"barcode" validate:"required,min=1,max=50"`
+	Location     *string `json:"location,omitempty" validate:"omitempty,max=100"`
+	HomeBranchID *string `json:"home_branch_id,omitempty" validate:"omitempty,uuid"`
+	AcquiredOn   *string `json:"acquired_on,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	PriceCents   *int    `json:"price_cents,omitempty" validate:"omitempty,min=0"`
+	// Condition defaults to good.
+	Condition string `json:"condition,omitempty" validate:"omitempty,oneof=new good fair poor damaged"`
+}
+
+// UpdateItemRequest replaces the details of a copy. Its status only changes
+// through the status and retire endpoints, and its current branch through
+// circulation and transfers.
+type UpdateItemRequest struct {
+	Barcode      string  `json:"barcode" validate:"required,min=1,max=50"`
+	Location     *string `json:"location,omitempty" validate:"omitempty,max=100"`
+	HomeBranchID *string `json:"home_branch_id,omitempty" validate:"omitempty,uuid"`
+	AcquiredOn   *string `json:"acquired_on,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	PriceCents   *int    `json:"price_cents,omitempty" validate:"omitempty,min=0"`
+	Condition    string  `json:"condition" validate:"required,oneof=new good fair poor damaged"`
+}
+
+type MoveItemRequest struct {
+	Location string `json:"location" validate:"required,min=1,max=100"`
+}
+
+// ItemStatusRequest changes the status of a copy. Copies go on loan and come
+// back through circulation instead.
+type ItemStatusRequest struct {
+	Status string  `json:"status" validate:"required,oneof=available in_transit lost withdrawn"`
+	Note   *string `json:"note,omitempty" validate:"omitempty,max=255"`
+}
+
+type RetireItemRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=255"`
+}
+
+// ItemAvailability counts the copies of a book by status. Total leaves out
+// withdrawn copies.
+type ItemAvailability struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	OnLoan    int `json:"on_loan"`
+	OnHold    int `json:"on_hold"`
+	InTransit int `json:"in_transit"`
+	Lost      int `json:"lost"`
+	Withdrawn int `json:"withdrawn"`
+}
+
+// BookItemsParams narrows the copies of a book to those at a branch.
+type BookItemsParams struct {
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+}
+
+type BookItemsResponse struct {
+	BookID       string           `json:"book_id"`
+	Availability ItemAvailability `json:"availability"`
+	Data         []Item           `json:"data"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// DefaultSearchLanguage is the text search configuration used for books that
+// do not specify a language.
+const DefaultSearchLanguage = "english"
+
+// SearchLanguages lists the PostgreSQL text search configurations a book or a
+// search query may use for stemming.
+var SearchLanguages = []string{
+	"simple", "danish", "dutch", "english", "finnish", "french", "german",
+	"hungarian", "italian", "norwegian", "portuguese", "romanian", "russian",
+	"spanish", "swedish", "turkish",
+}
+
+func IsSearchLanguage(language string) bool {
+	for _, l := range SearchLanguages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+)
+
+// Loan states a loan listing can filter on. Overdue loans are active loans
+// past their due date.
+const (
+	LoanActive   = "active"
+	LoanOverdue  = "overdue"
+	LoanReturned = "returned"
+)
+
+// Loan lends a copy to a member. Returned loans are kept as the loan
+// history of the copy and the member.
+type Loan struct {
+	ID       string `json:"id" db:"id"`
+	ItemID   string `json:"item_id" db:"item_id"`
+	BookID   string `json:"book_id" db:"book_id"`
+	Barcode  string `json:"barcode" db:"barcode"`
+	MemberID string `json:"member_id" db:"member_id"`
+	// BranchID is the branch the copy was checked out at, if the desk
+	// said.
+	BranchID *string `json:"branch_id,omitempty" db:"branch_id"`
+	// DueOn is the last day of the loan, formatted as YYYY-MM-DD.
+	DueOn         string     `json:"due_on" db:"due_on"`
+	Renewals      int        `json:"renewals" db:"renewals"`
+	CheckedOutAt  time.Time  `json:"checked_out_at" db:"checked_out_at"`
+	LastRenewedAt *time.Time `json:"last_renewed_at,omitempty" db:"last_renewed_at"`
+	ReturnedAt    *time.Time `json:"returned_at,omitempty" db:"returned_at"`
+	// DailyFineCents and MaxFineCents are the overdue fine rates the loan
+	// was checked out on. Loans made before loan policies have none and
+	// accrue at the configured rates.
+	DailyFineCents *int `json:"daily_fine_cents,omitempty" db:"daily_fine_cents"`
+	MaxFineCents   *int `json:"max_fine_cents,omitempty" db:"max_fine_cents"`
+}
+
+// CheckoutRequest lends a copy. BranchID is the branch of the desk, which
+// the copy is then known to be at.
+type CheckoutRequest struct {
+	Barcode    string  `json:"barcode" validate:"required,min=1,max=50"`
+	CardNumber string  `json:"card_number" validate:"required,min=1,max=20"`
+	BranchID   *string `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// CheckinRequest returns a copy. BranchID is the branch of the desk; a copy
+// returned away from where it is needed is sent on in transit.
+type CheckinRequest struct {
+	Barcode  string  `json:"barcode" validate:"required,min=1,max=50"`
+	BranchID *string `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+}
+
+type RenewRequest struct {
+	Barcode string `json:"barcode" validate:"required,min=1,max=50"`
+}
+
+// LoanListParams pages through loans, most recent checkout first.
+// BranchID narrows them to loans checked out at a branch.
+type LoanListParams struct {
+	Status   string `form:"status" validate:"omitempty,oneof=active overdue returned"`
+	BranchID string `form:"branch_id" validate:"omitempty,uuid"`
+	Limit    int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset   int    `form:"offset" validate:"omitempty,min=0"`
+	// Today decides which loans are overdue, formatted as YYYY-MM-DD. Set
+	// by the service.
+	Today string `form:"-" json:"-"`
+}
+
+type LoanListResponse struct {
+	Data  []Loan `json:"data"`
+	Total int    `json:"total"`
+}
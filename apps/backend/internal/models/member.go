@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// Membership types, which loan rules can distinguish.
+const (
+	MembershipAdult   = "adult"
+	MembershipChild   = "child"
+	MembershipStudent = "student"
+	MembershipSenior  = "senior"
+	MembershipStaff   = "staff"
+)
+
+// Member standings a member search can filter on.
+const (
+	MemberActive  = "active"
+	MemberExpired = "expired"
+	MemberBlocked = "blocked"
+)
+
+// Member is a registered patron of the library, identified at the desk by
+// the card number generated on registration.
+type Member struct {
+	ID             string  `json:"id" db:"id"`
+	CardNumber     string  `json:"card_number" db:"card_number"`
+	FirstName      string  `json:"first_name" db:"first_name"`
+	LastName       string  `json:"last_name" db:"last_name"`
+	Email          *string `json:"email,omitempty" db:"email"`
+	Phone          *string `json:"phone,omitempty" db:"phone"`
+	Address        *string `json:"address,omitempty" db:"address"`
+	MembershipType string  `json:"membership_type" db:"membership_type"`
+	// ExpiresOn is the last day of the membership, formatted as YYYY-MM-DD.
+	ExpiresOn     string    `json:"expires_on" db:"expires_on"`
+	Blocked       bool      `json:"blocked" db:"blocked"`
+	BlockedReason *string   `json:"blocked_reason,omitempty" db:"blocked_reason"`
+	Notes         *string   `json:"notes,omitempty" db:"notes"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateMemberRequest struct {
+	FirstName      string  `json:"first_name" validate:"required,min=1,max=100"`
+	LastName       string  `json:"last_name" validate:"required,min=1,max=100"`
+	Email          *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Phone          *string `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Address        *string `json:"address,omitempty" validate:"omitempty,max=500"`
+	MembershipType string  `json:"membership_type" validate:"required,oneof=adult child student senior staff"`
+	// ExpiresOn defaults to one year after registration.
+	ExpiresOn *string `json:"expires_on,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Notes     *string `json:"notes,omitempty" validate:"omitempty,max=5000"`
+}
+
+// UpdateMemberRequest replaces the details of a member. The card number and
+// blocked status only change through their own endpoints.
+type UpdateMemberRequest struct {
+	FirstName      string  `json:"first_name" validate:"required,min=1,max=100"`
+	LastName       string  `json:"last_name" validate:"required,min=1,max=100"`
+	Email          *string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	Phone          *string `json:"phone,omitempty" validate:"omitempty,max=50"`
+	Address        *string `json:"address,omitempty" validate:"omitempty,max=500"`
+	MembershipType string  `json:"membership_type" validate:"required,oneof=adult child student senior staff"`
+	ExpiresOn      string  `json:"expires_on" validate:"required,datetime=2006-01-02"`
+	Notes          *string `json:"notes,omitempty" validate:"omitempty,max=5000"`
+}
+
+type BlockMemberRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+// MemberListParams pages through members by last and first name. Query
+// matches names, email addresses, phone numbers and card numbers.
+type MemberListParams struct {
+	Query          string `form:"q" validate:"omitempty,max=255"`
+	MembershipType string `form:"membership_type" validate:"omitempty,oneof=adult child student senior staff"`
+	Status         string `form:"status" validate:"omitempty,oneof=active expired blocked"`
+	Limit          int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset         int    `form:"offset" validate:"omitempty,min=0"`
+	// Today decides which memberships have expired, formatted as
+	// YYYY-MM-DD. Set by the service.
+	Today string `form:"-" json:"-"`
+}
+
+type MemberListResponse struct {
+	Data  []Member `json:"data"`
+	Total int      `json:"total"`
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+)
+
+// Kinds of notice, each sent once for the loan or hold it is about.
+const (
+	NoticeOverdue   = "overdue"
+	NoticeHoldReady = "hold_ready"
+)
+
+// Channels a notice can be sent over. The log channel writes notices to the
+// notice log instead of sending them, for development.
+const (
+	ChannelEmail = "email"
+	ChannelLog   = "log"
+)
+
+// Notice statuses. A pending notice is retried until it is sent or runs out
+// of attempts and fails; a skipped notice could not be sent at all, such as
+// an email to a member without an address.
+const (
+	NoticePending = "pending"
+	NoticeSent    = "sent"
+	NoticeFailed  = "failed"
+	NoticeSkipped = "skipped"
+)
+
+// Notice is a message to a member in the outbox, kept after it is sent as
+// the notices history.
+type Notice struct {
+	ID       string `json:"id" db:"id"`
+	MemberID string `json:"member_id" db:"member_id"`
+	Kind     string `json:"kind" db:"kind"`
+	// Key names what the notice is about, such as a loan and the due date it
+	// is overdue from, so that the member is told of it once per channel.
+	Key string `json:"-" db:"event_key"`
+	// Channel is unset on a notice skipped because the member turned off
+	// notices of its kind.
+	Channel *string `json:"channel,omitempty" db:"channel"`
+	LoanID  *string `json:"loan_id,omitempty" db:"loan_id"`
+	HoldID  *string `json:"hold_id,omitempty" db:"hold_id"`
+	// Recipient is the address the notice goes to on its channel.
+	Recipient *string `json:"recipient,omitempty" db:"recipient"`
+	Subject   string  `json:"subject" db:"subject"`
+	Body      string  `json:"body" db:"body"`
+	Status    string  `json:"status" db:"status"`
+	Attempts  int     `json:"attempts" db:"attempts"`
+	// NextAttemptAt is when a pending notice is next tried.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	// LastError is why the last attempt failed, or why the notice was
+	// skipped.
+	LastError *string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	SentAt    *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+// NoticeListParams pages through a member's notices, newest first.
+type NoticeListParams struct {
+	Kind   string `form:"kind" validate:"omitempty,oneof=overdue hold_ready"`
+	Status string `form:"status" validate:"omitempty,oneof=pending sent failed skipped"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" validate:"omitempty,min=0"`
+}
+
+type NoticeListResponse struct {
+	Data  []Notice `json:"data"`
+	Total int      `json:"total"`
+}
+
+// NoticePreferences are the channels a member is sent each kind of notice
+// over. An empty list turns that kind of notice off.
+type NoticePreferences struct {
+	MemberID  string   `json:"member_id" db:"member_id"`
+	Overdue   []string `json:"overdue" db:"overdue"`
+	HoldReady []string `json:"hold_ready" db:"hold_ready"`
+	// UpdatedAt is unset while the member has the default preferences.
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+type NoticePreferencesRequest struct {
+	Overdue   []string `json:"overdue" validate:"required,unique,dive,oneof=email log"`
+	HoldReady []string `json:"hold_ready" validate:"required,unique,dive,oneof=email log"`
+}
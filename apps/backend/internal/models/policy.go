@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// LoanPolicy is a rule setting some of the loan terms for the loans it
+// matches. A rule without a branch matches loans at every branch, one
+// without a membership type every member, and one without a genre every
+// book; a genre rule also matches books in its subgenres. Terms left unset
+// fall through to less specific rules.
+type LoanPolicy struct {
+	ID             string  `json:"id" db:"id"`
+	Name           string  `json:"name" db:"name"`
+	BranchID       *string `json:"branch_id,omitempty" db:"branch_id"`
+	MembershipType *string `json:"membership_type,omitempty" db:"membership_type"`
+	GenreID        *string `json:"genre_id,omitempty" db:"genre_id"`
+	// Priority breaks ties between equally specific rules, such as rules
+	// on two genres of the same book. Higher wins.
+	Priority       int       `json:"priority" db:"priority"`
+	LoanDays       *int      `json:"loan_days,omitempty" db:"loan_days"`
+	MaxRenewals    *int      `json:"max_renewals,omitempty" db:"max_renewals"`
+	DailyFineCents *int      `json:"daily_fine_cents,omitempty" db:"daily_fine_cents"`
+	MaxFineCents   *int      `json:"max_fine_cents,omitempty" db:"max_fine_cents"`
+	HoldsAllowed   *bool     `json:"holds_allowed,omitempty" db:"holds_allowed"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// LoanPolicyRequest creates or replaces a rule.
+type LoanPolicyRequest struct {
+	Name           string  `json:"name" validate:"required,min=1,max=100"`
+	BranchID       *string `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+	MembershipType *string `json:"membership_type,omitempty" validate:"omitempty,oneof=adult child student senior staff"`
+	GenreID        *string `json:"genre_id,omitempty" validate:"omitempty,uuid"`
+	Priority       int     `json:"priority" validate:"min=-1000,max=1000"`
+	LoanDays       *int    `json:"loan_days,omitempty" validate:"omitempty,min=1,max=365"`
+	MaxRenewals    *int    `json:"max_renewals,omitempty" validate:"omitempty,min=0,max=100"`
+	DailyFineCents *int    `json:"daily_fine_cents,omitempty" validate:"omitempty,min=0"`
+	MaxFineCents   *int    `json:"max_fine_cents,omitempty" validate:"omitempty,min=0"`
+	HoldsAllowed   *bool   `json:"holds_allowed,omitempty"`
+}
+
+// LoanTerms are the terms a loan is made on.
+type LoanTerms struct {
+	LoanDays    int `json:"loan_days"`
+	MaxRenewals int `json:"max_renewals"`
+	// DailyFineCents and MaxFineCents are the overdue fine rates, fixed on
+	// a loan when it is checked out.
+	DailyFineCents int  `json:"daily_fine_cents"`
+	MaxFineCents   int  `json:"max_fine_cents"`
+	HoldsAllowed   bool `json:"holds_allowed"`
+}
+
+// PolicyEvaluationParams asks which terms apply to a member, or a membership
+// type, borrowing a book at a branch. A member takes precedence over a
+// membership type. Without a book only rules for every book match, and
+// without a branch only rules for every branch.
+type PolicyEvaluationParams struct {
+	BranchID       string `form:"branch_id" validate:"omitempty,uuid"`
+	MemberID       string `form:"member_id" validate:"omitempty,uuid"`
+	MembershipType string `form:"membership_type" validate:"required_without=MemberID,omitempty,oneof=adult child student senior staff"`
+	BookID         string `form:"book_id" validate:"omitempty,uuid"`
+}
+
+// PolicyEvaluation explains the terms that apply.
+type PolicyEvaluation struct {
+	Terms LoanTerms `json:"terms"`
+	// Sources maps each term to the ID of the rule it came from, or to
+	// "default" when no rule sets it.
+	Sources map[string]string `json:"sources"`
+	// Matched lists the matching rules, the one that wins first.
+	Matched []LoanPolicy `json:"matched"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"library-management-backend/pkg/isbn"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidations registers the custom validation tags used by the
+// request models.
+func RegisterValidations(v *validator.Validate) error {
+	if err := v.RegisterValidation("search_language", func(fl validator.FieldLevel) bool {
+		return IsSearchLanguage(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+
+	if err := v.RegisterValidation("book_isbn", func(fl validator.FieldLevel) bool {
+		return isbn.IsValid(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		params := sl.Current().Interface().(BookListParams)
+		checkYearRange(sl, params.YearFrom, params.YearTo)
+	}, BookListParams{})
+	v.RegisterStructValidation(func(sl validator.StructLevel) {
+		params := sl.Current().Interface().(BookExportParams)
+		checkYearRange(sl, params.YearFrom, params.YearTo)
+	}, BookExportParams{})
+	return nil
+}
+
+// checkYearRange reports a gtefield error on YearTo when a publication year
+// range ends before it starts. gtefield itself cannot be used because
+// either end of the range may be left out.
+func checkYearRange(sl validator.StructLevel, from, to *int) {
+	if from != nil && to != nil && *to < *from {
+		sl.ReportError(*to, "YearTo", "YearTo", "gtefield", "YearFrom")
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrAuthorNotFound = errors.New("author not found")
+	// ErrDuplicateAuthor is returned when another author already has the
+	// same name key.
+	ErrDuplicateAuthor = errors.New("an author with this name already exists")
+	// ErrAuthorInUse is returned when deleting an author that is still
+	// credited on a book, including books in the trash.
+	ErrAuthorInUse = errors.New("author is credited on books")
+)
+
+// AuthorRepository persists authors and the contributors of books. Authors
+// are identified by name key as well as by ID; Create and Update return
+// ErrDuplicateAuthor when another author has the same key.
+type AuthorRepository interface {
+	// List expects a normalized Limit, like BookRepository.List.
+	List(params *models.AuthorListParams) (*models.AuthorListResponse, error)
+	GetByID(id string) (*models.Author, error)
+	Create(author *models.Author) error
+	// Update also rewrites the author string of the books that credit the
+	// author, bumping their versions.
+	Update(author *models.Author) error
+	Delete(id string) error
+	// ListBooks expects a normalized Limit and returns the live books the
+	// author contributed to, oldest first.
+	ListBooks(authorID string, params *models.AuthorBooksParams) (*models.AuthorBooksResponse, error)
+	// Contributors lists the contributors of a live book by position.
+	Contributors(bookID string) ([]models.BookContributor, error)
+	// ReplaceContributors replaces every contributor of a book and sets its
+	// author string in one write. Like BookRepository.Update it expects the
+	// book to be at version and bumps it.
+	ReplaceContributors(bookID string, version int, contributors []models.BookContributor, author string, updatedAt time.Time) error
+	// LinkAuthors makes authors the author-role contributors of a book,
+	// leaving its other contributors alone. Authors are matched by name key
+	// and created when no author has it; their IDs are filled in.
+	LinkAuthors(bookID string, authors []*models.Author) error
+}
+
+const authorSort = "sort_name"
+
+// authorNameKey mirrors the author_name_key SQL function for repositories
+// without a database.
+func authorNameKey(name string) string {
+	var cleaned strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || r == ',' {
+			cleaned.WriteRune(r)
+		}
+	}
+
+	tokens := strings.FieldsFunc(cleaned.String(), func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+func decodeAuthorCursor(encoded string) (*pageCursor, error) {
+	cursor, err := decodePageCursor(encoded)
+	if err != nil || cursor.Sort != authorSort {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+func nextAuthorCursor(last *models.Author) string {
+	return encodePageCursor(pageCursor{
+		Sort:  authorSort,
+		Order: "asc",
+		Value: last.SortName,
+		ID:    last.ID,
+	})
+}
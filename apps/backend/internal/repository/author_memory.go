@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryAuthorRepository keeps authors and contributors in maps guarded by a
+// RWMutex. It shares the books of a MemoryBookRepository, whose lock is
+// always taken after its own.
+type MemoryAuthorRepository struct {
+	mu      sync.RWMutex
+	books   *MemoryBookRepository
+	authors map[string]models.Author
+	// contributors holds the contributors of each book by book ID, without
+	// their names.
+	contributors map[string][]models.BookContributor
+}
+
+func NewMemoryAuthorRepository(books *MemoryBookRepository) *MemoryAuthorRepository {
+	return &MemoryAuthorRepository{
+		books:        books,
+		authors:      make(map[string]models.Author),
+		contributors: make(map[string][]models.BookContributor),
+	}
+}
+
+func (r *MemoryAuthorRepository) List(params *models.AuthorListParams) (*models.AuthorListResponse, error) {
+	query := strings.ToLower(params.Query)
+
+	r.mu.RLock()
+	authors := make([]models.Author, 0, len(r.authors))
+	for _, author := range r.authors {
+		if strings.Contains(strings.ToLower(author.Name), query) || strings.Contains(strings.ToLower(author.SortName), query) {
+			authors = append(authors, author)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].SortName != authors[j].SortName {
+			return authors[i].SortName < authors[j].SortName
+		}
+		return authors[i].ID < authors[j].ID
+	})
+
+	total := len(authors)
+	if params.Cursor != "" {
+		cursor, err := decodeAuthorCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		start := len(authors)
+		for i, author := range authors {
+			if author.SortName > cursor.Value || (author.SortName == cursor.Value && author.ID > cursor.ID) {
+				start = i
+				break
+			}
+		}
+		authors = authors[start:]
+	}
+
+	response := &models.AuthorListResponse{Total: total}
+	if len(authors) > params.Limit {
+		authors = authors[:params.Limit]
+		response.NextCursor = nextAuthorCursor(&authors[params.Limit-1])
+	}
+	response.Data = authors
+
+	return response, nil
+}
+
+func (r *MemoryAuthorRepository) GetByID(id string) (*models.Author, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	author, ok := r.authors[id]
+	if !ok {
+		return nil, ErrAuthorNotFound
+	}
+	return &author, nil
+}
+
+func (r *MemoryAuthorRepository) Create(author *models.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.findByKey(authorNameKey(author.Name)) != nil {
+		return ErrDuplicateAuthor
+	}
+	r.authors[author.ID] = *author
+	return nil
+}
+
+func (r *MemoryAuthorRepository) Update(author *models.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.authors[author.ID]
+	if !ok {
+		return ErrAuthorNotFound
+	}
+	if other := r.findByKey(authorNameKey(author.Name)); other != nil && other.ID != author.ID {
+		return ErrDuplicateAuthor
+	}
+
+	updated := *author
+	updated.CreatedAt = existing.CreatedAt
+	r.authors[author.ID] = updated
+
+	r.books.mu.Lock()
+	defer r.books.mu.Unlock()
+	for bookID, contributors := range r.contributors {
+		if !creditsAuthor(contributors, author.ID) {
+			continue
+		}
+		book, ok := r.books.books[bookID]
+		if !ok {
+			continue
+		}
+		if names := r.creditedNames(contributors); book.Author != names {
+			book.Author = names
+			book.UpdatedAt = author.UpdatedAt
+			book.Version++
+			r.books.books[bookID] = book
+		}
+	}
+	return nil
+}
+
+func (r *MemoryAuthorRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.authors[id]; !ok {
+		return ErrAuthorNotFound
+	}
+
+	r.books.mu.RLock()
+	defer r.books.mu.RUnlock()
+	for bookID, contributors := range r.contributors {
+		if _, ok := r.books.books[bookID]; ok && creditsAuthor(contributors, id) {
+			return ErrAuthorInUse
+		}
+	}
+
+	delete(r.authors, id)
+	return nil
+}
+
+func (r *MemoryAuthorRepository) ListBooks(authorID string, params *models.AuthorBooksParams) (*models.AuthorBooksResponse, error) {
+	r.mu.RLock()
+	if _, ok := r.authors[authorID]; !ok {
+		r.mu.RUnlock()
+		return nil, ErrAuthorNotFound
+	}
+
+	var books []models.AuthorBook
+	r.books.mu.RLock()
+	for bookID, contributors := range r.contributors {
+		book, ok := r.books.books[bookID]
+		if !ok || book.DeletedAt != nil {
+			continue
+		}
+
+		var roles []string
+		for _, contributor := range contributors {
+			if contributor.AuthorID == authorID && (params.Role == "" || contributor.Role == params.Role) {
+				roles = append(roles, contributor.Role)
+			}
+		}
+		if len(roles) > 0 {
+			books = append(books, models.AuthorBook{Book: book, Roles: roles})
+		}
+	}
+	r.books.mu.RUnlock()
+	r.mu.RUnlock()
+
+	sort.Slice(books, func(i, j int) bool {
+		if books[i].Year != books[j].Year {
+			return books[i].Year < books[j].Year
+		}
+		if books[i].Title != books[j].Title {
+			return books[i].Title < books[j].Title
+		}
+		return books[i].ID < books[j].ID
+	})
+
+	response := &models.AuthorBooksResponse{Data: []models.AuthorBook{}, Total: len(books)}
+	if params.Offset < len(books) {
+		books = books[params.Offset:]
+		if len(books) > params.Limit {
+			books = books[:params.Limit]
+		}
+		response.Data = books
+	}
+
+	return response, nil
+}
+
+func (r *MemoryAuthorRepository) Contributors(bookID string) ([]models.BookContributor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, err := r.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	contributors := make([]models.BookContributor, 0, len(r.contributors[bookID]))
+	for _, contributor := range r.contributors[bookID] {
+		contributor.Name = r.authors[contributor.AuthorID].Name
+		contributors = append(contributors, contributor)
+	}
+	return contributors, nil
+}
+
+func (r *MemoryAuthorRepository) ReplaceContributors(bookID string, version int, contributors []models.BookContributor, author string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, contributor := range contributors {
+		if _, ok := r.authors[contributor.AuthorID]; !ok {
+			return ErrAuthorNotFound
+		}
+	}
+
+	r.books.mu.Lock()
+	defer r.books.mu.Unlock()
+
+	book, ok := r.books.books[bookID]
+	if !ok || book.DeletedAt != nil {
+		return ErrBookNotFound
+	}
+	if book.Version != version {
+		return ErrVersionConflict
+	}
+
+	book.Author = author
+	book.UpdatedAt = updatedAt
+	book.Version++
+	r.books.books[bookID] = book
+
+	stored := make([]models.BookContributor, len(contributors))
+	for i, contributor := range contributors {
+		contributor.Name = ""
+		stored[i] = contributor
+	}
+	sortContributors(stored)
+	r.contributors[bookID] = stored
+	return nil
+}
+
+func (r *MemoryAuthorRepository) LinkAuthors(bookID string, authors []*models.Author) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.books.mu.RLock()
+	_, ok := r.books.books[bookID]
+	r.books.mu.RUnlock()
+	if !ok {
+		return ErrBookNotFound
+	}
+
+	var linked []models.BookContributor
+	for _, contributor := range r.contributors[bookID] {
+		if contributor.Role != models.ContributorAuthor {
+			linked = append(linked, contributor)
+		}
+	}
+
+	for i, author := range authors {
+		if existing := r.findByKey(authorNameKey(author.Name)); existing != nil {
+			author.ID = existing.ID
+		} else {
+			r.authors[author.ID] = *author
+		}
+
+		if !creditsAuthorAs(linked, author.ID, models.ContributorAuthor) {
+			linked = append(linked, models.BookContributor{AuthorID: author.ID, Role: models.ContributorAuthor, Position: i})
+		}
+	}
+
+	sortContributors(linked)
+	r.contributors[bookID] = linked
+	return nil
+}
+
+// findByKey returns the author with a name key. Callers must hold the lock.
+func (r *MemoryAuthorRepository) findByKey(key string) *models.Author {
+	for _, author := range r.authors {
+		if authorNameKey(author.Name) == key {
+			return &author
+		}
+	}
+	return nil
+}
+
+// creditedNames derives the author string of a book like the SQL in
+// refreshCreditedBooks. Callers must hold the lock.
+func (r *MemoryAuthorRepository) creditedNames(contributors []models.BookContributor) string {
+	var authors, everyone []string
+	for _, contributor := range contributors {
+		name := r.authors[contributor.AuthorID].Name
+		if contributor.Role == models.ContributorAuthor {
+			authors = append(authors, name)
+		}
+		everyone = append(everyone, name)
+	}
+	if len(authors) == 0 {
+		return strings.Join(everyone, "; ")
+	}
+	return strings.Join(authors, "; ")
+}
+
+func creditsAuthor(contributors []models.BookContributor, authorID string) bool {
+	for _, contributor := range contributors {
+		if contributor.AuthorID == authorID {
+			return true
+		}
+	}
+	return false
+}
+
+func creditsAuthorAs(contributors []models.BookContributor, authorID, role string) bool {
+	for _, contributor := range contributors {
+		if contributor.AuthorID == authorID && contributor.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// sortContributors orders contributors like the SQL ORDER BY position, role.
+func sortContributors(contributors []models.BookContributor) {
+	sort.SliceStable(contributors, func(i, j int) bool {
+		if contributors[i].Position != contributors[j].Position {
+			return contributors[i].Position < contributors[j].Position
+		}
+		return contributors[i].Role < contributors[j].Role
+	})
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryAuthor(name, sortName string) *models.Author {
+	return &models.Author{
+		ID:        uuid.New().String(),
+		Name:      name,
+		SortName:  sortName,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestAuthorNameKey(t *testing.T) {
+	assert.Equal(t, "jrr tolkien", authorNameKey("J.R.R. Tolkien"))
+	assert.Equal(t, "jrr tolkien", authorNameKey("Tolkien, J.R.R."))
+	assert.Equal(t, "gabriel garcía márquez", authorNameKey("García Márquez, Gabriel"))
+	assert.Equal(t, "", authorNameKey(" -- "))
+}
+
+func TestMemoryAuthorRepository_CRUD(t *testing.T) {
+	repo := NewMemoryAuthorRepository(NewMemoryBookRepository())
+	tolkien := newMemoryAuthor("J.R.R. Tolkien", "Tolkien, J.R.R.")
+	require.NoError(t, repo.Create(tolkien))
+	require.NoError(t, repo.Create(newMemoryAuthor("Ursula K. Le Guin", "Le Guin, Ursula K.")))
+
+	assert.ErrorIs(t, repo.Create(newMemoryAuthor("Tolkien, J.R.R.", "Tolkien, J.R.R.")), ErrDuplicateAuthor)
+
+	page, err := repo.List(&models.AuthorListParams{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+	assert.Equal(t, "Ursula K. Le Guin", page.Data[0].Name)
+
+	page, err = repo.List(&models.AuthorListParams{Limit: 1, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, "J.R.R. Tolkien", page.Data[0].Name)
+	assert.Empty(t, page.NextCursor)
+
+	page, err = repo.List(&models.AuthorListParams{Limit: 10, Query: "guin"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Total)
+
+	_, err = repo.List(&models.AuthorListParams{Limit: 10, Cursor: "garbage"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	require.NoError(t, repo.Delete(tolkien.ID))
+	_, err = repo.GetByID(tolkien.ID)
+	assert.ErrorIs(t, err, ErrAuthorNotFound)
+	assert.ErrorIs(t, repo.Delete(tolkien.ID), ErrAuthorNotFound)
+}
+
+func TestMemoryAuthorRepository_Contributors(t *testing.T) {
+	books := NewMemoryBookRepository()
+	repo := NewMemoryAuthorRepository(books)
+
+	goodOmens := newMemoryBook("Good Omens", "Terry Pratchett & Neil Gaiman", 1990, "Fantasy", time.Now())
+	require.NoError(t, books.Create(goodOmens))
+	pratchett := newMemoryAuthor("Terry Pratchett", "Pratchett, Terry")
+	gaiman := newMemoryAuthor("Gaiman, Neil", "Gaiman, Neil")
+	require.NoError(t, repo.LinkAuthors(goodOmens.ID, []*models.Author{pratchett, gaiman}))
+
+	t.Run("link matches existing authors by key", func(t *testing.T) {
+		sandman := newMemoryBook("The Sandman", "Neil Gaiman", 1989, "Comics", time.Now())
+		require.NoError(t, books.Create(sandman))
+		again := newMemoryAuthor("Neil Gaiman", "Gaiman, Neil")
+		require.NoError(t, repo.LinkAuthors(sandman.ID, []*models.Author{again}))
+		assert.Equal(t, gaiman.ID, again.ID)
+
+		response, err := repo.ListBooks(gaiman.ID, &models.AuthorBooksParams{Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, 2, response.Total)
+		assert.Equal(t, "The Sandman", response.Data[0].Title)
+		assert.Equal(t, []string{models.ContributorAuthor}, response.Data[1].Roles)
+
+		assert.ErrorIs(t, repo.LinkAuthors(uuid.New().String(), nil), ErrBookNotFound)
+	})
+
+	t.Run("replace and rename", func(t *testing.T) {
+		editor := newMemoryAuthor("Some Editor", "Editor, Some")
+		require.NoError(t, repo.Create(editor))
+
+		contributors := []models.BookContributor{
+			{AuthorID: pratchett.ID, Role: models.ContributorAuthor, Position: 0},
+			{AuthorID: editor.ID, Role: models.ContributorEditor, Position: 1},
+		}
+		assert.ErrorIs(t, repo.ReplaceContributors(goodOmens.ID, 2, contributors, "Terry Pratchett", time.Now()), ErrVersionConflict)
+		require.NoError(t, repo.ReplaceContributors(goodOmens.ID, 1, contributors, "Terry Pratchett", time.Now()))
+
+		listed, err := repo.Contributors(goodOmens.ID)
+		require.NoError(t, err)
+		require.Len(t, listed, 2)
+		assert.Equal(t, "Some Editor", listed[1].Name)
+		assert.Equal(t, models.ContributorEditor, listed[1].Role)
+
+		renamed := *pratchett
+		renamed.Name = "Sir Terry Pratchett"
+		require.NoError(t, repo.Update(&renamed))
+
+		book, err := books.GetByID(goodOmens.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Sir Terry Pratchett", book.Author)
+		assert.Equal(t, 3, book.Version)
+
+		assert.ErrorIs(t, repo.Delete(editor.ID), ErrAuthorInUse)
+	})
+
+	t.Run("trashed books", func(t *testing.T) {
+		book, err := books.GetByID(goodOmens.ID)
+		require.NoError(t, err)
+		require.NoError(t, books.Delete(goodOmens.ID, book.Version, time.Now()))
+
+		_, err = repo.Contributors(goodOmens.ID)
+		assert.ErrorIs(t, err, ErrBookNotFound)
+
+		response, err := repo.ListBooks(pratchett.ID, &models.AuthorBooksParams{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 0, response.Total)
+		assert.ErrorIs(t, repo.Delete(pratchett.ID), ErrAuthorInUse)
+	})
+}
@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	authorNameKeyIndex     = "idx_authors_name_key"
+	contributorAuthorFKey  = "book_contributors_author_id_fkey"
+	contributorBookFKey    = "book_contributors_book_id_fkey"
+	authorColumns          = "id, name, sort_name, birth_year, death_year, biography, created_at, updated_at"
+	contributorOrderClause = "ORDER BY bc.position, bc.role"
+)
+
+// refreshCreditedBooks rewrites the author string of every book crediting
+// the author in $1 from its contributors: the author-role names, or every
+// name when a book has no authors, joined with "; ".
+const refreshCreditedBooks = `UPDATE books b SET author = credited.names, updated_at = $2, version = b.version + 1
+			  FROM (SELECT bc.book_id, COALESCE(
+			          string_agg(a.name, '; ' ORDER BY bc.position) FILTER (WHERE bc.role = 'author'),
+			          string_agg(a.name, '; ' ORDER BY bc.position)) AS names
+			        FROM book_contributors bc JOIN authors a ON a.id = bc.author_id
+			        WHERE bc.book_id IN (SELECT book_id FROM book_contributors WHERE author_id = $1)
+			        GROUP BY bc.book_id) AS credited
+			  WHERE b.id = credited.book_id AND b.author <> credited.names`
+
+type PostgresAuthorRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuthorRepository(db *sql.DB) *PostgresAuthorRepository {
+	return &PostgresAuthorRepository{db: db}
+}
+
+func (r *PostgresAuthorRepository) List(params *models.AuthorListParams) (*models.AuthorListResponse, error) {
+	var conditions []string
+	var args []interface{}
+	if params.Query != "" {
+		args = append(args, "%"+escapeLike(params.Query)+"%")
+		conditions = append(conditions, "(name ILIKE $1 OR sort_name ILIKE $1)")
+	}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM authors" + whereClause(conditions)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count authors: %w", err)
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeAuthorCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("(sort_name, id) > ($%d, $%d)", len(args)+1, len(args)+2))
+		args = append(args, cursor.Value, cursor.ID)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM authors%s ORDER BY sort_name, id LIMIT $%d",
+		authorColumns, whereClause(conditions), len(args)+1)
+	args = append(args, params.Limit+1)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch authors: %w", err)
+	}
+	defer rows.Close()
+
+	authors := make([]models.Author, 0, params.Limit)
+	for rows.Next() {
+		var author models.Author
+		err := rows.Scan(&author.ID, &author.Name, &author.SortName, &author.BirthYear,
+			&author.DeathYear, &author.Biography, &author.CreatedAt, &author.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan author: %w", err)
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch authors: %w", err)
+	}
+
+	response := &models.AuthorListResponse{Total: total}
+	if len(authors) > params.Limit {
+		authors = authors[:params.Limit]
+		response.NextCursor = nextAuthorCursor(&authors[params.Limit-1])
+	}
+	response.Data = authors
+
+	return response, nil
+}
+
+func (r *PostgresAuthorRepository) GetByID(id string) (*models.Author, error) {
+	var author models.Author
+	err := r.db.QueryRow("SELECT "+authorColumns+" FROM authors WHERE id = $1", id).Scan(
+		&author.ID, &author.Name, &author.SortName, &author.BirthYear,
+		&author.DeathYear, &author.Biography, &author.CreatedAt, &author.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrAuthorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch author: %w", err)
+	}
+
+	return &author, nil
+}
+
+func (r *PostgresAuthorRepository) Create(author *models.Author) error {
+	query := `INSERT INTO authors (id, name, sort_name, name_key, birth_year, death_year, biography, created_at, updated_at)
+			  VALUES ($1, $2, $3, author_name_key($2), $4, $5, $6, $7, $8)`
+
+	_, err := r.db.Exec(query, author.ID, author.Name, author.SortName, author.BirthYear,
+		author.DeathYear, author.Biography, author.CreatedAt, author.UpdatedAt)
+	if isUniqueViolation(err, authorNameKeyIndex) {
+		return ErrDuplicateAuthor
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create author: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresAuthorRepository) Update(author *models.Author) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE authors SET name = $1, sort_name = $2, name_key = author_name_key($1), birth_year = $3,
+			  death_year = $4, biography = $5, updated_at = $6 WHERE id = $7`
+
+	result, err := tx.Exec(query, author.Name, author.SortName, author.BirthYear,
+		author.DeathYear, author.Biography, author.UpdatedAt, author.ID)
+	if isUniqueViolation(err, authorNameKeyIndex) {
+		return ErrDuplicateAuthor
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update author: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrAuthorNotFound
+	}
+
+	if _, err := tx.Exec(refreshCreditedBooks, author.ID, author.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update credited books: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit author: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresAuthorRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM authors WHERE id = $1", id)
+	if isForeignKeyViolation(err, contributorAuthorFKey) {
+		return ErrAuthorInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete author: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresAuthorRepository) ListBooks(authorID string, params *models.AuthorBooksParams) (*models.AuthorBooksResponse, error) {
+	var exists bool
+	if err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM authors WHERE id = $1)", authorID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to fetch author: %w", err)
+	}
+	if !exists {
+		return nil, ErrAuthorNotFound
+	}
+
+	conditions := []string{"bc.author_id = $1", "b.deleted_at IS NULL"}
+	args := []interface{}{authorID}
+	if params.Role != "" {
+		args = append(args, params.Role)
+		conditions = append(conditions, "bc.role = $2")
+	}
+
+	query := fmt.Sprintf(`SELECT b.id, b.title, b.author, b.year, b.description, b.isbn, b.genre, b.language, b.version, b.created_at, b.updated_at,
+			  array_agg(bc.role %s) AS roles, COUNT(*) OVER () AS total
+			  FROM books b JOIN book_contributors bc ON bc.book_id = b.id%s
+			  GROUP BY b.id ORDER BY b.year, b.title, b.id LIMIT $%d OFFSET $%d`,
+		contributorOrderClause, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch author books: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.AuthorBooksResponse{Data: make([]models.AuthorBook, 0, params.Limit)}
+	for rows.Next() {
+		var book models.AuthorBook
+		err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year,
+			&book.Description, &book.ISBN, &book.Genre, &book.Language, &book.Version,
+			&book.CreatedAt, &book.UpdatedAt, pq.Array(&book.Roles), &response.Total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan author book: %w", err)
+		}
+		response.Data = append(response.Data, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch author books: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresAuthorRepository) Contributors(bookID string) ([]models.BookContributor, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)", bookID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch book: %w", err)
+	}
+	if !exists {
+		return nil, ErrBookNotFound
+	}
+
+	rows, err := r.db.Query(`SELECT bc.author_id, a.name, bc.role, bc.position
+			  FROM book_contributors bc JOIN authors a ON a.id = bc.author_id
+			  WHERE bc.book_id = $1 `+contributorOrderClause, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contributors: %w", err)
+	}
+	defer rows.Close()
+
+	contributors := []models.BookContributor{}
+	for rows.Next() {
+		var contributor models.BookContributor
+		if err := rows.Scan(&contributor.AuthorID, &contributor.Name, &contributor.Role, &contributor.Position); err != nil {
+			return nil, fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		contributors = append(contributors, contributor)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch contributors: %w", err)
+	}
+
+	return contributors, nil
+}
+
+func (r *PostgresAuthorRepository) ReplaceContributors(bookID string, version int, contributors []models.BookContributor, author string, updatedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE books SET author = $1, updated_at = $2, version = version + 1
+			  WHERE id = $3 AND version = $4 AND deleted_at IS NULL`, author, updatedAt, bookID, version)
+	if err != nil {
+		return fmt.Errorf("failed to update book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)", bookID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check book version: %w", err)
+		}
+		if !exists {
+			return ErrBookNotFound
+		}
+		return ErrVersionConflict
+	}
+
+	if _, err := tx.Exec("DELETE FROM book_contributors WHERE book_id = $1", bookID); err != nil {
+		return fmt.Errorf("failed to remove contributors: %w", err)
+	}
+
+	for _, contributor := range contributors {
+		_, err := tx.Exec("INSERT INTO book_contributors (book_id, author_id, role, position) VALUES ($1, $2, $3, $4)",
+			bookID, contributor.AuthorID, contributor.Role, contributor.Position)
+		if isForeignKeyViolation(err, contributorAuthorFKey) {
+			return ErrAuthorNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add contributor: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contributors: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresAuthorRepository) LinkAuthors(bookID string, authors []*models.Author) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The no-op update makes RETURNING yield the existing author's ID when
+	// the name key is taken.
+	upsert := `INSERT INTO authors (id, name, sort_name, name_key, created_at, updated_at)
+			  VALUES ($1, $2, $3, author_name_key($2), $4, $5)
+			  ON CONFLICT (name_key) DO UPDATE SET name_key = EXCLUDED.name_key RETURNING id`
+	for _, author := range authors {
+		err := tx.QueryRow(upsert, author.ID, author.Name, author.SortName, author.CreatedAt, author.UpdatedAt).Scan(&author.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find or create author: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM book_contributors WHERE book_id = $1 AND role = 'author'", bookID); err != nil {
+		return fmt.Errorf("failed to remove authors: %w", err)
+	}
+
+	for i, author := range authors {
+		_, err := tx.Exec(`INSERT INTO book_contributors (book_id, author_id, role, position)
+			  VALUES ($1, $2, 'author', $3) ON CONFLICT DO NOTHING`, bookID, author.ID, i)
+		if isForeignKeyViolation(err, contributorBookFKey) {
+			return ErrBookNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add author: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit authors: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresAuthorRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db)
+	columns := []string{"id", "name", "sort_name", "birth_year", "death_year", "biography", "created_at", "updated_at"}
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM authors WHERE (name ILIKE $1 OR sort_name ILIKE $1)")).
+		WithArgs("%tolk%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, sort_name, birth_year, death_year, biography, created_at, updated_at FROM authors WHERE (name ILIKE $1 OR sort_name ILIKE $1) ORDER BY sort_name, id LIMIT $2")).
+		WithArgs("%tolk%", 2).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e", "Christopher Tolkien", "Tolkien, Christopher", 1924, 2020, nil, now, now).
+			AddRow("8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", "J.R.R. Tolkien", "Tolkien, J.R.R.", 1892, 1973, nil, now, now))
+
+	result, err := repo.List(&models.AuthorListParams{Query: "tolk", Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, result.Data, 1)
+	assert.Equal(t, 2, result.Total)
+	assert.NotEmpty(t, result.NextCursor)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM authors")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, sort_name, birth_year, death_year, biography, created_at, updated_at FROM authors WHERE (sort_name, id) > ($1, $2) ORDER BY sort_name, id LIMIT $3")).
+		WithArgs("Tolkien, Christopher", "2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e", 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	result, err = repo.List(&models.AuthorListParams{Limit: 1, Cursor: result.NextCursor})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Data)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuthorRepository_CreateAndDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db)
+	now := time.Now()
+	author := &models.Author{ID: "1", Name: "Tolkien, J.R.R.", SortName: "Tolkien, J.R.R.", CreatedAt: now, UpdatedAt: now}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO authors (id, name, sort_name, name_key, birth_year, death_year, biography, created_at, updated_at) VALUES ($1, $2, $3, author_name_key($2), $4, $5, $6, $7, $8)")).
+		WithArgs("1", "Tolkien, J.R.R.", "Tolkien, J.R.R.", nil, nil, nil, now, now).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_authors_name_key"})
+	assert.ErrorIs(t, repo.Create(author), ErrDuplicateAuthor)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM authors WHERE id = $1")).
+		WithArgs("1").
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "book_contributors_author_id_fkey"})
+	assert.ErrorIs(t, repo.Delete("1"), ErrAuthorInUse)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM authors WHERE id = $1")).
+		WithArgs("2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete("2"), ErrAuthorNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuthorRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db)
+	now := time.Now()
+	author := &models.Author{ID: "1", Name: "J. R. R. Tolkien", SortName: "Tolkien, J. R. R.", UpdatedAt: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE authors SET name = $1, sort_name = $2, name_key = author_name_key($1), birth_year = $3, death_year = $4, biography = $5, updated_at = $6 WHERE id = $7")).
+		WithArgs("J. R. R. Tolkien", "Tolkien, J. R. R.", nil, nil, nil, now, "1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE books b SET author = credited.names")).
+		WithArgs("1", now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.Update(author))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE authors SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.Update(author), ErrAuthorNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuthorRepository_ListBooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db)
+	now := time.Now()
+	columns := []string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at", "roles", "total"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM authors WHERE id = $1)")).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT b.id, b.title, b.author, b.year, b.description, b.isbn, b.genre, b.language, b.version, b.created_at, b.updated_at,
+			  array_agg(bc.role ORDER BY bc.position, bc.role) AS roles, COUNT(*) OVER () AS total
+			  FROM books b JOIN book_contributors bc ON bc.book_id = b.id WHERE bc.author_id = $1 AND b.deleted_at IS NULL AND bc.role = $2
+			  GROUP BY b.id ORDER BY b.year, b.title, b.id LIMIT $3 OFFSET $4`)).
+		WithArgs("1", "editor", 20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("b1", "The Silmarillion", "J.R.R. Tolkien", 1977, nil, nil, nil, "english", 1, now, now, "{editor}", 1))
+
+	result, err := repo.ListBooks("1", &models.AuthorBooksParams{Role: "editor", Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	if assert.Len(t, result.Data, 1) {
+		assert.Equal(t, []string{"editor"}, result.Data[0].Roles)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM authors WHERE id = $1)")).
+		WithArgs("2").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	_, err = repo.ListBooks("2", &models.AuthorBooksParams{Limit: 20})
+	assert.ErrorIs(t, err, ErrAuthorNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuthorRepository_ReplaceContributors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db)
+	now := time.Now()
+	contributors := []models.BookContributor{
+		{AuthorID: "a1", Role: "author", Position: 0},
+		{AuthorID: "a2", Role: "translator", Position: 1},
+	}
+	update := regexp.QuoteMeta("UPDATE books SET author = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND version = $4 AND deleted_at IS NULL")
+	insert := regexp.QuoteMeta("INSERT INTO book_contributors (book_id, author_id, role, position) VALUES ($1, $2, $3, $4)")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("Leo Tolstoy", now, "b1", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM book_contributors WHERE book_id = $1")).WithArgs("b1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insert).WithArgs("b1", "a1", "author", 0).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(insert).WithArgs("b1", "a2", "translator", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.ReplaceContributors("b1", 3, contributors, "Leo Tolstoy", now))
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("Leo Tolstoy", now, "b1", 2).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)")).WithArgs("b1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.ReplaceContributors("b1", 2, contributors, "Leo Tolstoy", now), ErrVersionConflict)
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs("Leo Tolstoy", now, "b1", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM book_contributors WHERE book_id = $1")).WithArgs("b1").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(insert).WithArgs("b1", "a1", "author", 0).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "book_contributors_author_id_fkey"})
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.ReplaceContributors("b1", 4, contributors, "Leo Tolstoy", now), ErrAuthorNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuthorRepository_LinkAuthors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db)
+	now := time.Now()
+	authors := []*models.Author{
+		{ID: "new-1", Name: "Terry Pratchett", SortName: "Pratchett, Terry", CreatedAt: now, UpdatedAt: now},
+		{ID: "new-2", Name: "Neil Gaiman", SortName: "Gaiman, Neil", CreatedAt: now, UpdatedAt: now},
+	}
+	upsert := regexp.QuoteMeta(`INSERT INTO authors (id, name, sort_name, name_key, created_at, updated_at)
+			  VALUES ($1, $2, $3, author_name_key($2), $4, $5)
+			  ON CONFLICT (name_key) DO UPDATE SET name_key = EXCLUDED.name_key RETURNING id`)
+	link := regexp.QuoteMeta("INSERT INTO book_contributors (book_id, author_id, role, position) VALUES ($1, $2, 'author', $3) ON CONFLICT DO NOTHING")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(upsert).WithArgs("new-1", "Terry Pratchett", "Pratchett, Terry", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("new-1"))
+	mock.ExpectQuery(upsert).WithArgs("new-2", "Neil Gaiman", "Gaiman, Neil", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("existing"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM book_contributors WHERE book_id = $1 AND role = 'author'")).WithArgs("b1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(link).WithArgs("b1", "new-1", 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(link).WithArgs("b1", "existing", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.LinkAuthors("b1", authors))
+	assert.Equal(t, "existing", authors[1].ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrBookNotFound  = errors.New("book not found")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrDuplicateISBN = errors.New("a book with this ISBN already exists")
+	// ErrVersionConflict is returned when a book changed since the version
+	// the caller read.
+	ErrVersionConflict = errors.New("book was modified by another request")
+	// ErrBookInCirculation is returned when deleting a book that has a copy
+	// on loan or a hold waiting, in transit or ready for a member.
+	ErrBookInCirculation = errors.New("book has copies on loan or holds waiting")
+)
+
+// BookRepository persists books. List and Search expect their params to be
+// normalized by the caller: Limit, Sort and Order are always set. Create and
+// Update return ErrDuplicateISBN when another book already has the ISBN.
+//
+// Writes are conditional on the stored version: Update expects book.Version,
+// UpdateFields and Delete the version they are given, and each returns
+// ErrVersionConflict when the stored book has moved on. Update and
+// UpdateFields bump the version; Delete with version 0 skips the check.
+//
+// Delete only moves a book to the trash, and returns ErrBookInCirculation
+// while the book has active loans or holds. Trashed books are invisible to
+// every method except ListTrash, Restore and Purge, and do not hold on to
+// their ISBN.
+type BookRepository interface {
+	List(params *models.BookListParams) (*models.BookListResponse, error)
+	Search(params *models.BookSearchParams) (*models.BookSearchResponse, error)
+	GetByID(id string) (*models.Book, error)
+	// GetByISBN finds the live book with a normalized ISBN.
+	GetByISBN(isbn string) (*models.Book, error)
+	Create(book *models.Book) error
+	// CreateBatch creates every book or, on any error, none of them.
+	CreateBatch(books []*models.Book) error
+	// ExistingISBNs reports which of isbns already belong to a live book.
+	ExistingISBNs(isbns []string) (map[string]bool, error)
+	// Update writes every field of book and increments book.Version.
+	Update(book *models.Book) error
+	// UpdateFields writes only the given columns, keyed by column name.
+	UpdateFields(id string, version int, changes map[string]interface{}) error
+	Delete(id string, version int, deletedAt time.Time) error
+	// ListTrash expects a normalized Limit, like List.
+	ListTrash(params *models.TrashListParams) (*models.BookListResponse, error)
+	// Restore takes a book out of the trash; it returns ErrBookNotFound when
+	// the book is not trashed.
+	Restore(id string, restoredAt time.Time) error
+	// Purge permanently removes books trashed before deletedBefore and
+	// returns how many there were. Books with a copy that has ever been lent
+	// stay in the trash so that their loan history is kept.
+	Purge(deletedBefore time.Time) (int64, error)
+	// Export calls fn for every book matching the filters of params, in the
+	// order of its Sort and Order fields, and stops at the first error fn
+	// returns. Limit and Cursor are ignored. Books are read one at a time
+	// rather than loaded up front.
+	Export(ctx context.Context, params *models.BookListParams, fn func(*models.Book) error) error
+}
+
+const (
+	trashSort  = "deleted_at"
+	trashOrder = "desc"
+)
+
+// bookUpdatableColumns are the columns UpdateFields accepts.
+var bookUpdatableColumns = map[string]bool{
+	"title":       true,
+	"author":      true,
+	"year":        true,
+	"description": true,
+	"isbn":        true,
+	"genre":       true,
+	"language":    true,
+	"updated_at":  true,
+}
+
+func bookSortValue(sortField string, book *models.Book) string {
+	switch sortField {
+	case "title":
+		return book.Title
+	case "author":
+		return book.Author
+	case "year":
+		return strconv.Itoa(book.Year)
+	case "deleted_at":
+		if book.DeletedAt == nil {
+			return ""
+		}
+		return book.DeletedAt.UTC().Format(time.RFC3339Nano)
+	default:
+		return book.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func bookCursorValue(sortField, value string) (interface{}, error) {
+	switch sortField {
+	case "title", "author":
+		return value, nil
+	case "year":
+		return strconv.Atoi(value)
+	default:
+		return time.Parse(time.RFC3339Nano, value)
+	}
+}
+
+// decodeBookCursor validates a cursor against the sort it is being used with
+// and returns it together with its typed sort value.
+func decodeBookCursor(encoded, sortField, order string) (*pageCursor, interface{}, error) {
+	cursor, err := decodePageCursor(encoded)
+	if err != nil || cursor.Sort != sortField || cursor.Order != order {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	value, err := bookCursorValue(sortField, cursor.Value)
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	return cursor, value, nil
+}
+
+func nextBookCursor(sortField, order string, last *models.Book) string {
+	return encodePageCursor(pageCursor{
+		Sort:  sortField,
+		Order: order,
+		Value: bookSortValue(sortField, last),
+		ID:    last.ID,
+	})
+}
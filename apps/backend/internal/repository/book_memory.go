@@ -0,0 +1,606 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryBookRepository keeps books in a map guarded by a RWMutex. It mirrors
+// the behaviour of the Postgres implementation closely enough to exercise
+// services and handlers without a database; full-text search is approximated
+// by case-insensitive prefix matching of words.
+type MemoryBookRepository struct {
+	mu    sync.RWMutex
+	books map[string]models.Book
+	// genres holds the genre IDs of each book by position. It is written by
+	// MemoryGenreRepository and read by the genre filter.
+	genres map[string][]string
+	// items is the MemoryItemRepository built on this repository, which the
+	// branch filter asks for the books at a branch outside the lock.
+	items *MemoryItemRepository
+	// loans is the MemoryLoanRepository built on items, which Delete and
+	// Purge ask for the loans and holds of a book before taking the lock.
+	loans *MemoryLoanRepository
+}
+
+func NewMemoryBookRepository() *MemoryBookRepository {
+	return &MemoryBookRepository{
+		books:  make(map[string]models.Book),
+		genres: make(map[string][]string),
+	}
+}
+
+func (r *MemoryBookRepository) List(params *models.BookListParams) (*models.BookListResponse, error) {
+	if _, ok := bookSortColumns[params.Sort]; !ok {
+		return nil, fmt.Errorf("unsupported sort field: %s", params.Sort)
+	}
+
+	r.mu.RLock()
+	books := make([]models.Book, 0, len(r.books))
+	for _, book := range r.books {
+		if book.DeletedAt == nil && matchesBookFilters(&book, r.genres[book.ID], params) {
+			books = append(books, book)
+		}
+	}
+	r.mu.RUnlock()
+	books = r.filterByBranch(books, params.BranchID)
+
+	return pageBooks(books, params.Sort, params.Order, params.Cursor, params.Limit)
+}
+
+func (r *MemoryBookRepository) Search(params *models.BookSearchParams) (*models.BookSearchResponse, error) {
+	terms := searchTerms(params.Query)
+
+	r.mu.RLock()
+	var results []models.BookSearchResult
+	for _, book := range r.books {
+		if book.DeletedAt != nil || (params.Lang != "" && book.Language != params.Lang) {
+			continue
+		}
+		if params.Genre != "" && !hasAnyGenre(r.genres[book.ID], params.GenreIDs) {
+			continue
+		}
+
+		rank, ok := rankBook(&book, terms)
+		if !ok {
+			continue
+		}
+
+		result := models.BookSearchResult{Book: book, Rank: rank}
+		result.Highlights.Title = highlightTerms(book.Title, terms)
+		if book.Description != nil {
+			result.Highlights.Description = highlightTerms(*book.Description, terms)
+		}
+		results = append(results, result)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	response := &models.BookSearchResponse{Data: []models.BookSearchResult{}, Total: len(results)}
+	if params.Offset < len(results) {
+		results = results[params.Offset:]
+		if len(results) > params.Limit {
+			results = results[:params.Limit]
+		}
+		response.Data = results
+	}
+
+	return response, nil
+}
+
+func (r *MemoryBookRepository) GetByID(id string) (*models.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	book, ok := r.books[id]
+	if !ok || book.DeletedAt != nil {
+		return nil, ErrBookNotFound
+	}
+	return &book, nil
+}
+
+func (r *MemoryBookRepository) GetByISBN(isbn string) (*models.Book, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, book := range r.books {
+		if book.DeletedAt == nil && book.ISBN != nil && *book.ISBN == isbn {
+			return &book, nil
+		}
+	}
+	return nil, ErrBookNotFound
+}
+
+func (r *MemoryBookRepository) Create(book *models.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.isbnTaken(book) {
+		return ErrDuplicateISBN
+	}
+	r.books[book.ID] = *book
+	return nil
+}
+
+func (r *MemoryBookRepository) CreateBatch(books []*models.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, book := range books {
+		if book.ISBN == nil {
+			continue
+		}
+		if seen[*book.ISBN] || r.isbnTaken(book) {
+			return ErrDuplicateISBN
+		}
+		seen[*book.ISBN] = true
+	}
+
+	for _, book := range books {
+		r.books[book.ID] = *book
+	}
+	return nil
+}
+
+func (r *MemoryBookRepository) ExistingISBNs(isbns []string) (map[string]bool, error) {
+	wanted := make(map[string]bool, len(isbns))
+	for _, isbn := range isbns {
+		wanted[isbn] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := make(map[string]bool)
+	for _, book := range r.books {
+		if book.DeletedAt == nil && book.ISBN != nil && wanted[*book.ISBN] {
+			existing[*book.ISBN] = true
+		}
+	}
+	return existing, nil
+}
+
+func (r *MemoryBookRepository) Update(book *models.Book) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.books[book.ID]
+	if !ok || existing.DeletedAt != nil {
+		return ErrBookNotFound
+	}
+	if existing.Version != book.Version {
+		return ErrVersionConflict
+	}
+	if r.isbnTaken(book) {
+		return ErrDuplicateISBN
+	}
+
+	book.Version++
+	updated := *book
+	updated.CreatedAt = existing.CreatedAt
+	r.books[book.ID] = updated
+	return nil
+}
+
+func (r *MemoryBookRepository) UpdateFields(id string, version int, changes map[string]interface{}) error {
+	for column := range changes {
+		if !bookUpdatableColumns[column] {
+			return fmt.Errorf("column %s cannot be updated", column)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	book, ok := r.books[id]
+	if !ok || book.DeletedAt != nil {
+		return ErrBookNotFound
+	}
+	if book.Version != version {
+		return ErrVersionConflict
+	}
+
+	for column, value := range changes {
+		switch column {
+		case "title":
+			book.Title = value.(string)
+		case "author":
+			book.Author = value.(string)
+		case "year":
+			book.Year = value.(int)
+		case "description":
+			book.Description = value.(*string)
+		case "isbn":
+			book.ISBN = value.(*string)
+		case "genre":
+			book.Genre = value.(*string)
+		case "language":
+			book.Language = value.(string)
+		case "updated_at":
+			book.UpdatedAt = value.(time.Time)
+		}
+	}
+
+	if r.isbnTaken(&book) {
+		return ErrDuplicateISBN
+	}
+	book.Version++
+	r.books[id] = book
+	return nil
+}
+
+func (r *MemoryBookRepository) Delete(id string, version int, deletedAt time.Time) error {
+	if r.loans != nil {
+		r.loans.mu.RLock()
+		defer r.loans.mu.RUnlock()
+		r.items.mu.RLock()
+		defer r.items.mu.RUnlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	book, ok := r.books[id]
+	if !ok || book.DeletedAt != nil {
+		return ErrBookNotFound
+	}
+	if version != 0 && book.Version != version {
+		return ErrVersionConflict
+	}
+	if r.loans != nil && r.loans.bookInCirculation(id) {
+		return ErrBookInCirculation
+	}
+
+	book.DeletedAt = &deletedAt
+	book.Version++
+	r.books[id] = book
+	return nil
+}
+
+func (r *MemoryBookRepository) ListTrash(params *models.TrashListParams) (*models.BookListResponse, error) {
+	r.mu.RLock()
+	var books []models.Book
+	for _, book := range r.books {
+		if book.DeletedAt != nil {
+			books = append(books, book)
+		}
+	}
+	r.mu.RUnlock()
+
+	return pageBooks(books, trashSort, trashOrder, params.Cursor, params.Limit)
+}
+
+func (r *MemoryBookRepository) Restore(id string, restoredAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	book, ok := r.books[id]
+	if !ok || book.DeletedAt == nil {
+		return ErrBookNotFound
+	}
+	if r.isbnTaken(&book) {
+		return ErrDuplicateISBN
+	}
+
+	book.DeletedAt = nil
+	book.UpdatedAt = restoredAt
+	book.Version++
+	r.books[id] = book
+	return nil
+}
+
+func (r *MemoryBookRepository) Purge(deletedBefore time.Time) (int64, error) {
+	if r.loans != nil {
+		r.loans.mu.RLock()
+		defer r.loans.mu.RUnlock()
+		r.items.mu.RLock()
+		defer r.items.mu.RUnlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, book := range r.books {
+		if r.loans != nil && r.loans.bookLent(id) {
+			continue
+		}
+		if book.DeletedAt != nil && book.DeletedAt.Before(deletedBefore) {
+			delete(r.books, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// isbnTaken reports whether another live book already uses book's ISBN.
+// Callers must hold the lock.
+func (r *MemoryBookRepository) isbnTaken(book *models.Book) bool {
+	if book.ISBN == nil {
+		return false
+	}
+	for id, other := range r.books {
+		if id != book.ID && other.DeletedAt == nil && other.ISBN != nil && *other.ISBN == *book.ISBN {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryBookRepository) Export(ctx context.Context, params *models.BookListParams, fn func(*models.Book) error) error {
+	if _, ok := bookSortColumns[params.Sort]; !ok {
+		return fmt.Errorf("unsupported sort field: %s", params.Sort)
+	}
+
+	r.mu.RLock()
+	books := make([]models.Book, 0, len(r.books))
+	for _, book := range r.books {
+		if book.DeletedAt == nil && matchesBookFilters(&book, r.genres[book.ID], params) {
+			books = append(books, book)
+		}
+	}
+	r.mu.RUnlock()
+	books = r.filterByBranch(books, params.BranchID)
+
+	sortBooks(books, params.Sort, params.Order)
+	for i := range books {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&books[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortBooks(books []models.Book, sortField, order string) {
+	less := bookLess(sortField)
+	sort.Slice(books, func(i, j int) bool {
+		if order == "desc" {
+			return less(&books[j], &books[i])
+		}
+		return less(&books[i], &books[j])
+	})
+}
+
+// pageBooks sorts books and cuts out the page that follows cursor.
+func pageBooks(books []models.Book, sortField, order, cursor string, limit int) (*models.BookListResponse, error) {
+	sortBooks(books, sortField, order)
+
+	total := len(books)
+	if cursor != "" {
+		decoded, _, err := decodeBookCursor(cursor, sortField, order)
+		if err != nil {
+			return nil, err
+		}
+
+		start := len(books)
+		for i := range books {
+			if afterBookCursor(&books[i], decoded, order) {
+				start = i
+				break
+			}
+		}
+		books = books[start:]
+	}
+
+	response := &models.BookListResponse{Total: total}
+	if len(books) > limit {
+		books = books[:limit]
+		response.NextCursor = nextBookCursor(sortField, order, &books[limit-1])
+	}
+	if books == nil {
+		books = []models.Book{}
+	}
+	response.Data = books
+
+	return response, nil
+}
+
+// filterByBranch keeps the books with a copy at a branch that is not
+// withdrawn, or all of them when branchID is unset. It takes the lock of the
+// item repository, so callers must not hold the lock.
+func (r *MemoryBookRepository) filterByBranch(books []models.Book, branchID string) []models.Book {
+	if branchID == "" {
+		return books
+	}
+	var atBranch map[string]bool
+	if r.items != nil {
+		atBranch = r.items.booksAtBranch(branchID)
+	}
+
+	kept := books[:0]
+	for _, book := range books {
+		if atBranch[book.ID] {
+			kept = append(kept, book)
+		}
+	}
+	return kept
+}
+
+// matchesBookFilters reports whether a book with the genre IDs genres
+// passes the list filters.
+func matchesBookFilters(book *models.Book, genres []string, params *models.BookListParams) bool {
+	if params.Author != "" && !strings.Contains(strings.ToLower(book.Author), strings.ToLower(params.Author)) {
+		return false
+	}
+	if params.Genre != "" && !hasAnyGenre(genres, params.GenreIDs) {
+		return false
+	}
+	if params.YearFrom != nil && book.Year < *params.YearFrom {
+		return false
+	}
+	if params.YearTo != nil && book.Year > *params.YearTo {
+		return false
+	}
+	if params.HasISBN != nil {
+		hasISBN := book.ISBN != nil && *book.ISBN != ""
+		if hasISBN != *params.HasISBN {
+			return false
+		}
+	}
+	return true
+}
+
+// bookLess orders books by the sort field, breaking ties by ID like the
+// (column, id) keyset used in SQL.
+// hasAnyGenre reports whether genres and filter share a genre ID.
+func hasAnyGenre(genres, filter []string) bool {
+	for _, genre := range genres {
+		for _, id := range filter {
+			if genre == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func bookLess(sortField string) func(a, b *models.Book) bool {
+	return func(a, b *models.Book) bool {
+		switch sortField {
+		case "title":
+			if a.Title != b.Title {
+				return a.Title < b.Title
+			}
+		case "author":
+			if a.Author != b.Author {
+				return a.Author < b.Author
+			}
+		case "year":
+			if a.Year != b.Year {
+				return a.Year < b.Year
+			}
+		case "deleted_at":
+			if !a.DeletedAt.Equal(*b.DeletedAt) {
+				return a.DeletedAt.Before(*b.DeletedAt)
+			}
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		}
+		return a.ID < b.ID
+	}
+}
+
+func afterBookCursor(book *models.Book, cursor *pageCursor, order string) bool {
+	value := bookSortValue(cursor.Sort, book)
+
+	var cmp int
+	switch cursor.Sort {
+	case "year", "created_at", "deleted_at":
+		// Both encodings sort lexically only for equal widths, so compare
+		// through the typed values instead.
+		a, _ := bookCursorValue(cursor.Sort, value)
+		b, _ := bookCursorValue(cursor.Sort, cursor.Value)
+		cmp = compareCursorValues(a, b)
+	default:
+		cmp = strings.Compare(value, cursor.Value)
+	}
+	if cmp == 0 {
+		cmp = strings.Compare(book.ID, cursor.ID)
+	}
+
+	if order == "desc" {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), isWordSeparator)
+}
+
+// rankBook scores a book with the same field weights as the search_vector
+// column (title and author A, genre B, description C). Every term must match.
+func rankBook(book *models.Book, terms []string) (float64, bool) {
+	if len(terms) == 0 {
+		return 0, false
+	}
+
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{book.Title, 1.0},
+		{book.Author, 1.0},
+		{derefString(book.Genre), 0.4},
+		{derefString(book.Description), 0.2},
+	}
+
+	var rank float64
+	for _, term := range terms {
+		matched := false
+		for _, field := range fields {
+			for _, word := range strings.FieldsFunc(strings.ToLower(field.text), isWordSeparator) {
+				if strings.HasPrefix(word, term) {
+					rank += field.weight
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+
+	return rank, true
+}
+
+func highlightTerms(text string, terms []string) string {
+	var b strings.Builder
+	word := strings.Builder{}
+
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		lower := strings.ToLower(w)
+		for _, term := range terms {
+			if strings.HasPrefix(lower, term) {
+				w = "<mark>" + w + "</mark>"
+				break
+			}
+		}
+		b.WriteString(w)
+		word.Reset()
+	}
+
+	for _, r := range text {
+		if isWordSeparator(r) {
+			flush()
+			b.WriteRune(r)
+			continue
+		}
+		word.WriteRune(r)
+	}
+	flush()
+
+	return b.String()
+}
+
+func isWordSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryBook(title, author string, year int, genre string, createdAt time.Time) *models.Book {
+	return &models.Book{
+		ID:        uuid.New().String(),
+		Title:     title,
+		Author:    author,
+		Year:      year,
+		Genre:     &genre,
+		Language:  "english",
+		Version:   1,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func TestMemoryBookRepository_CRUD(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	book := newMemoryBook("The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy", time.Now())
+
+	require.NoError(t, repo.Create(book))
+
+	found, err := repo.GetByID(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "The Hobbit", found.Title)
+
+	found.Title = "The Hobbit, or There and Back Again"
+	found.CreatedAt = time.Time{}
+	require.NoError(t, repo.Update(found))
+	assert.Equal(t, book.Version+1, found.Version)
+
+	updated, err := repo.GetByID(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "The Hobbit, or There and Back Again", updated.Title)
+	assert.Equal(t, book.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, found.Version, updated.Version)
+
+	assert.ErrorIs(t, repo.Update(book), ErrVersionConflict)
+	assert.ErrorIs(t, repo.Delete(book.ID, book.Version, time.Now()), ErrVersionConflict)
+	require.NoError(t, repo.Delete(book.ID, updated.Version, time.Now()))
+
+	_, err = repo.GetByID(book.ID)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+	assert.ErrorIs(t, repo.Delete(book.ID, 0, time.Now()), ErrBookNotFound)
+	assert.ErrorIs(t, repo.Update(book), ErrBookNotFound)
+}
+
+func TestMemoryBookRepository_UpdateFields(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	book := newMemoryBook("The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy", time.Now())
+	require.NoError(t, repo.Create(book))
+
+	require.NoError(t, repo.UpdateFields(book.ID, 1, map[string]interface{}{"year": 1938, "genre": (*string)(nil)}))
+
+	updated, err := repo.GetByID(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1938, updated.Year)
+	assert.Nil(t, updated.Genre)
+	assert.Equal(t, "The Hobbit", updated.Title)
+	assert.Equal(t, 2, updated.Version)
+
+	assert.ErrorIs(t, repo.UpdateFields(book.ID, 1, map[string]interface{}{"year": 1939}), ErrVersionConflict)
+	assert.ErrorIs(t, repo.UpdateFields("missing", 1, map[string]interface{}{"year": 1938}), ErrBookNotFound)
+	assert.Error(t, repo.UpdateFields(book.ID, 2, map[string]interface{}{"created_at": time.Now()}))
+}
+
+func TestMemoryBookRepository_Trash(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	now := time.Now()
+	isbn := "9780306406157"
+	hobbit := newMemoryBook("The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy", now)
+	hobbit.ISBN = &isbn
+	dune := newMemoryBook("Dune", "Frank Herbert", 1965, "Science Fiction", now)
+	emma := newMemoryBook("Emma", "Jane Austen", 1815, "Romance", now)
+	for _, book := range []*models.Book{hobbit, dune, emma} {
+		require.NoError(t, repo.Create(book))
+	}
+
+	require.NoError(t, repo.Delete(hobbit.ID, 0, now.Add(-48*time.Hour)))
+	require.NoError(t, repo.Delete(dune.ID, 1, now.Add(-time.Hour)))
+
+	t.Run("trashed books are hidden", func(t *testing.T) {
+		_, err := repo.GetByID(hobbit.ID)
+		assert.ErrorIs(t, err, ErrBookNotFound)
+		assert.ErrorIs(t, repo.Delete(hobbit.ID, 0, now), ErrBookNotFound)
+
+		result, err := repo.List(&models.BookListParams{Limit: 10, Sort: "title", Order: "asc"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, "Emma", result.Data[0].Title)
+
+		search, err := repo.Search(&models.BookSearchParams{Query: "hobbit", Limit: 10})
+		require.NoError(t, err)
+		assert.Zero(t, search.Total)
+	})
+
+	t.Run("trash lists most recently deleted first", func(t *testing.T) {
+		first, err := repo.ListTrash(&models.TrashListParams{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, first.Total)
+		assert.Equal(t, "Dune", first.Data[0].Title)
+		assert.Equal(t, 2, first.Data[0].Version)
+
+		second, err := repo.ListTrash(&models.TrashListParams{Limit: 1, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, "The Hobbit", second.Data[0].Title)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		reused := newMemoryBook("Signal Processing", "Someone Else", 1990, "Science", now)
+		reused.ISBN = &isbn
+		require.NoError(t, repo.Create(reused))
+		assert.ErrorIs(t, repo.Restore(hobbit.ID, now), ErrDuplicateISBN)
+
+		require.NoError(t, repo.Restore(dune.ID, now))
+		restored, err := repo.GetByID(dune.ID)
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.Equal(t, 3, restored.Version)
+
+		assert.ErrorIs(t, repo.Restore(dune.ID, now), ErrBookNotFound)
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		purged, err := repo.Purge(now.Add(-24 * time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		trash, err := repo.ListTrash(&models.TrashListParams{Limit: 10})
+		require.NoError(t, err)
+		assert.Zero(t, trash.Total)
+		assert.Empty(t, trash.Data)
+	})
+}
+
+func TestMemoryBookRepository_CreateBatch(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	isbn := "9780306406157"
+	first := newMemoryBook("Signal Processing", "Author", 1990, "Science", time.Now())
+	first.ISBN = &isbn
+	second := newMemoryBook("Copy", "Author", 1990, "Science", time.Now())
+	second.ISBN = &isbn
+
+	assert.ErrorIs(t, repo.CreateBatch([]*models.Book{first, second}), ErrDuplicateISBN)
+	_, err := repo.GetByID(first.ID)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+
+	require.NoError(t, repo.CreateBatch([]*models.Book{first}))
+	existing, err := repo.ExistingISBNs([]string{isbn, "9780262033848"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{isbn: true}, existing)
+
+	assert.ErrorIs(t, repo.CreateBatch([]*models.Book{second}), ErrDuplicateISBN)
+
+	found, err := repo.GetByISBN(isbn)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+
+	require.NoError(t, repo.Delete(first.ID, 0, time.Now()))
+	_, err = repo.GetByISBN(isbn)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+}
+
+func TestMemoryBookRepository_List(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	genres := NewMemoryGenreRepository(repo)
+	now := time.Now()
+	for _, book := range []*models.Book{
+		newMemoryBook("The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy", now.Add(-3*time.Hour)),
+		newMemoryBook("The Lord of the Rings", "J.R.R. Tolkien", 1954, "Fantasy", now.Add(-2*time.Hour)),
+		newMemoryBook("Dune", "Frank Herbert", 1965, "Science Fiction", now.Add(-time.Hour)),
+	} {
+		require.NoError(t, repo.Create(book))
+		require.NoError(t, genres.LinkGenre(book.ID, &models.Genre{ID: uuid.New().String(), Name: *book.Genre}))
+	}
+	fantasy, err := genres.Subtree("fantasy")
+	require.NoError(t, err)
+
+	t.Run("default order is newest first", func(t *testing.T) {
+		result, err := repo.List(&models.BookListParams{Limit: 10, Sort: "created_at", Order: "desc"})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, "Dune", result.Data[0].Title)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		yearFrom := 1950
+		result, err := repo.List(&models.BookListParams{Limit: 10, Sort: "year", Order: "asc", Author: "tolkien", Genre: "fantasy", GenreIDs: fantasy, YearFrom: &yearFrom})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, "The Lord of the Rings", result.Data[0].Title)
+	})
+
+	t.Run("cursor walks every page", func(t *testing.T) {
+		params := &models.BookListParams{Limit: 1, Sort: "year", Order: "desc"}
+		var titles []string
+		for {
+			result, err := repo.List(params)
+			require.NoError(t, err)
+			for _, book := range result.Data {
+				titles = append(titles, book.Title)
+			}
+			if result.NextCursor == "" {
+				break
+			}
+			params.Cursor = result.NextCursor
+		}
+		assert.Equal(t, []string{"Dune", "The Lord of the Rings", "The Hobbit"}, titles)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := repo.List(&models.BookListParams{Limit: 1, Sort: "year", Order: "desc", Cursor: "garbage"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestMemoryBookRepository_Export(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	now := time.Now()
+	hobbit := newMemoryBook("The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy", now)
+	require.NoError(t, repo.Create(hobbit))
+	require.NoError(t, repo.Create(newMemoryBook("The Lord of the Rings", "J.R.R. Tolkien", 1954, "Fantasy", now)))
+	require.NoError(t, repo.Create(newMemoryBook("Dune", "Frank Herbert", 1965, "Science Fiction", now)))
+	trashed := newMemoryBook("The Silmarillion", "J.R.R. Tolkien", 1977, "Fantasy", now)
+	require.NoError(t, repo.Create(trashed))
+	require.NoError(t, repo.Delete(trashed.ID, 0, now))
+
+	var titles []string
+	err := repo.Export(context.Background(), &models.BookListParams{Author: "tolkien", Sort: "year", Order: "desc"}, func(book *models.Book) error {
+		titles = append(titles, book.Title)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"The Lord of the Rings", "The Hobbit"}, titles)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.Export(context.Background(), &models.BookListParams{Sort: "title", Order: "asc"}, func(book *models.Book) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMemoryBookRepository_Search(t *testing.T) {
+	repo := NewMemoryBookRepository()
+	hobbit := newMemoryBook("The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy", time.Now())
+	description := "Bilbo travels with dwarves to reclaim their mountain home."
+	hobbit.Description = &description
+	require.NoError(t, repo.Create(hobbit))
+	require.NoError(t, repo.Create(newMemoryBook("Dune", "Frank Herbert", 1965, "Science Fiction", time.Now())))
+
+	result, err := repo.Search(&models.BookSearchParams{Query: "dwarves tolk", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Total)
+	assert.Equal(t, "The Hobbit", result.Data[0].Title)
+	assert.Contains(t, result.Data[0].Highlights.Description, "<mark>dwarves</mark>")
+
+	result, err = repo.Search(&models.BookSearchParams{Query: "hobbit", Lang: "french", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Total)
+	assert.Empty(t, result.Data)
+}
+
+func TestMemoryBookRepository_ConcurrentAccess(t *testing.T) {
+	repo := NewMemoryBookRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			book := newMemoryBook(fmt.Sprintf("Book %d", i), "Author", 2000+i, "Genre", time.Now())
+			assert.NoError(t, repo.Create(book))
+			_, err := repo.List(&models.BookListParams{Limit: 5, Sort: "title", Order: "asc"})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	result, err := repo.List(&models.BookListParams{Limit: 100, Sort: "title", Order: "asc"})
+	require.NoError(t, err)
+	assert.Equal(t, 50, result.Total)
+}
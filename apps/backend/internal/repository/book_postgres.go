@@ -0,0 +1,576 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	titleHeadlineOptions       = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	descriptionHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10"
+
+	// bookGenreCondition matches books with any of the genre IDs in the
+	// numbered argument; an empty set matches none.
+	bookGenreCondition = "id IN (SELECT book_id FROM book_genres WHERE genre_id = ANY($%d))"
+	// bookBranchCondition keeps the books with a copy at a branch that is
+	// not withdrawn.
+	bookBranchCondition = "id IN (SELECT book_id FROM items WHERE current_branch_id = $%d AND status <> 'withdrawn')"
+)
+
+var bookSortColumns = map[string]string{
+	"title":      "title",
+	"author":     "author",
+	"year":       "year",
+	"created_at": "created_at",
+}
+
+type PostgresBookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresBookRepository(db *sql.DB) *PostgresBookRepository {
+	return &PostgresBookRepository{db: db}
+}
+
+func (r *PostgresBookRepository) List(params *models.BookListParams) (*models.BookListResponse, error) {
+	sortColumn, ok := bookSortColumns[params.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field: %s", params.Sort)
+	}
+
+	conditions, args := bookFilterConditions(params)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM books" + whereClause(conditions)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count books: %w", err)
+	}
+
+	if params.Cursor != "" {
+		cursor, value, err := decodeBookCursor(params.Cursor, params.Sort, params.Order)
+		if err != nil {
+			return nil, err
+		}
+
+		op := ">"
+		if params.Order == "desc" {
+			op = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d, $%d)", sortColumn, op, len(args)+1, len(args)+2))
+		args = append(args, value, cursor.ID)
+	}
+
+	direction := strings.ToUpper(params.Order)
+	query := fmt.Sprintf(`SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at 
+			  FROM books%s ORDER BY %s %s, id %s LIMIT $%d`,
+		whereClause(conditions), sortColumn, direction, direction, len(args)+1)
+	args = append(args, params.Limit+1)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch books: %w", err)
+	}
+	defer rows.Close()
+
+	books := make([]models.Book, 0, params.Limit)
+	for rows.Next() {
+		var book models.Book
+		err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year,
+			&book.Description, &book.ISBN, &book.Genre, &book.Language, &book.Version,
+			&book.CreatedAt, &book.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan book: %w", err)
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch books: %w", err)
+	}
+
+	response := &models.BookListResponse{Total: total}
+	if len(books) > params.Limit {
+		books = books[:params.Limit]
+		response.NextCursor = nextBookCursor(params.Sort, params.Order, &books[params.Limit-1])
+	}
+	response.Data = books
+
+	return response, nil
+}
+
+// Search uses the search_vector column, so a query given in one language only
+// matches books stemmed with the same configuration reliably.
+func (r *PostgresBookRepository) Search(params *models.BookSearchParams) (*models.BookSearchResponse, error) {
+	language := params.Lang
+	if language == "" {
+		language = models.DefaultSearchLanguage
+	}
+
+	conditions := []string{"search_vector @@ query", "deleted_at IS NULL"}
+	args := []interface{}{language, params.Query, params.Limit, params.Offset}
+	if params.Lang != "" {
+		conditions = append(conditions, "language = $1::regconfig")
+	}
+	if params.Genre != "" {
+		args = append(args, pq.Array(params.GenreIDs))
+		conditions = append(conditions, fmt.Sprintf(bookGenreCondition, len(args)))
+	}
+
+	query := `SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at,
+			  ts_rank_cd(search_vector, query) AS rank,
+			  ts_headline(language, title, query, '` + titleHeadlineOptions + `') AS title_highlight,
+			  ts_headline(language, COALESCE(description, ''), query, '` + descriptionHeadlineOptions + `') AS description_highlight,
+			  COUNT(*) OVER () AS total
+			  FROM books, websearch_to_tsquery($1::regconfig, $2) AS query` + whereClause(conditions) + `
+			  ORDER BY rank DESC, id LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search books: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.BookSearchResponse{Data: make([]models.BookSearchResult, 0, params.Limit)}
+	for rows.Next() {
+		var result models.BookSearchResult
+		err := rows.Scan(&result.ID, &result.Title, &result.Author, &result.Year,
+			&result.Description, &result.ISBN, &result.Genre, &result.Language, &result.Version,
+			&result.CreatedAt, &result.UpdatedAt,
+			&result.Rank, &result.Highlights.Title, &result.Highlights.Description,
+			&response.Total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		response.Data = append(response.Data, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search books: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresBookRepository) GetByID(id string) (*models.Book, error) {
+	query := `SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at 
+			  FROM books WHERE id = $1 AND deleted_at IS NULL`
+
+	var book models.Book
+	err := r.db.QueryRow(query, id).Scan(
+		&book.ID, &book.Title, &book.Author, &book.Year,
+		&book.Description, &book.ISBN, &book.Genre, &book.Language, &book.Version,
+		&book.CreatedAt, &book.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrBookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch book: %w", err)
+	}
+
+	return &book, nil
+}
+
+func (r *PostgresBookRepository) GetByISBN(isbn string) (*models.Book, error) {
+	query := `SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at 
+			  FROM books WHERE isbn = $1 AND deleted_at IS NULL`
+
+	var book models.Book
+	err := r.db.QueryRow(query, isbn).Scan(
+		&book.ID, &book.Title, &book.Author, &book.Year,
+		&book.Description, &book.ISBN, &book.Genre, &book.Language, &book.Version,
+		&book.CreatedAt, &book.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrBookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch book: %w", err)
+	}
+
+	return &book, nil
+}
+
+func (r *PostgresBookRepository) Create(book *models.Book) error {
+	query := `INSERT INTO books (id, title, author, year, description, isbn, genre, language, version, created_at, updated_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	_, err := r.db.Exec(query, book.ID, book.Title, book.Author, book.Year,
+		book.Description, book.ISBN, book.Genre, book.Language, book.Version, book.CreatedAt, book.UpdatedAt)
+	if isUniqueViolation(err, "idx_books_isbn") {
+		return ErrDuplicateISBN
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create book: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresBookRepository) CreateBatch(books []*models.Book) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO books (id, title, author, year, description, isbn, genre, language, version, created_at, updated_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, book := range books {
+		_, err := stmt.Exec(book.ID, book.Title, book.Author, book.Year,
+			book.Description, book.ISBN, book.Genre, book.Language, book.Version, book.CreatedAt, book.UpdatedAt)
+		if isUniqueViolation(err, "idx_books_isbn") {
+			return ErrDuplicateISBN
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create book: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit books: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresBookRepository) ExistingISBNs(isbns []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(isbns) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.db.Query("SELECT isbn FROM books WHERE isbn = ANY($1) AND deleted_at IS NULL", pq.Array(isbns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up isbns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var isbn string
+		if err := rows.Scan(&isbn); err != nil {
+			return nil, fmt.Errorf("failed to scan isbn: %w", err)
+		}
+		existing[isbn] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to look up isbns: %w", err)
+	}
+
+	return existing, nil
+}
+
+func (r *PostgresBookRepository) Update(book *models.Book) error {
+	query := `UPDATE books SET title = $1, author = $2, year = $3, description = $4, 
+			  isbn = $5, genre = $6, language = $7, updated_at = $8, version = version + 1 
+			  WHERE id = $9 AND version = $10 AND deleted_at IS NULL`
+
+	result, err := r.db.Exec(query, book.Title, book.Author, book.Year, book.Description,
+		book.ISBN, book.Genre, book.Language, book.UpdatedAt, book.ID, book.Version)
+	if isUniqueViolation(err, "idx_books_isbn") {
+		return ErrDuplicateISBN
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return r.versionMismatch(book.ID)
+	}
+
+	book.Version++
+	return nil
+}
+
+func (r *PostgresBookRepository) UpdateFields(id string, version int, changes map[string]interface{}) error {
+	columns := make([]string, 0, len(changes))
+	for column := range changes {
+		if !bookUpdatableColumns[column] {
+			return fmt.Errorf("column %s cannot be updated", column)
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	sort.Strings(columns)
+
+	assignments := make([]string, len(columns))
+	args := make([]interface{}, 0, len(columns)+2)
+	for i, column := range columns {
+		args = append(args, changes[column])
+		assignments[i] = fmt.Sprintf("%s = $%d", column, len(args))
+	}
+	args = append(args, id, version)
+
+	query := fmt.Sprintf("UPDATE books SET %s, version = version + 1 WHERE id = $%d AND version = $%d AND deleted_at IS NULL",
+		strings.Join(assignments, ", "), len(args)-1, len(args))
+	result, err := r.db.Exec(query, args...)
+	if isUniqueViolation(err, "idx_books_isbn") {
+		return ErrDuplicateISBN
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return r.versionMismatch(id)
+	}
+
+	return nil
+}
+
+// bookInCirculation matches books with a copy on loan or a hold waiting, in
+// transit or ready for a member.
+const bookInCirculation = `(EXISTS (SELECT 1 FROM items i JOIN loans l ON l.item_id = i.id
+			  WHERE i.book_id = books.id AND l.returned_at IS NULL)
+		  OR EXISTS (SELECT 1 FROM holds h WHERE h.book_id = books.id AND h.status IN ('waiting', 'in_transit', 'ready')))`
+
+// bookLent matches books with a copy that has ever been lent.
+const bookLent = "EXISTS (SELECT 1 FROM items i JOIN loans l ON l.item_id = i.id WHERE i.book_id = books.id)"
+
+func (r *PostgresBookRepository) Delete(id string, version int, deletedAt time.Time) error {
+	query := "UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL AND NOT " + bookInCirculation
+	args := []interface{}{deletedAt, id}
+	if version != 0 {
+		query += " AND version = $3"
+		args = append(args, version)
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return r.deleteMismatch(id, version)
+	}
+
+	return nil
+}
+
+// deleteMismatch explains why Delete matched no rows: the book is gone, in
+// circulation or at another version.
+func (r *PostgresBookRepository) deleteMismatch(id string, version int) error {
+	var storedVersion int
+	var inCirculation bool
+	err := r.db.QueryRow("SELECT version, "+bookInCirculation+" FROM books WHERE id = $1 AND deleted_at IS NULL", id).
+		Scan(&storedVersion, &inCirculation)
+	if err == sql.ErrNoRows {
+		return ErrBookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check book: %w", err)
+	}
+	if version != 0 && storedVersion != version {
+		return ErrVersionConflict
+	}
+	if inCirculation {
+		return ErrBookInCirculation
+	}
+	return ErrVersionConflict
+}
+
+func (r *PostgresBookRepository) ListTrash(params *models.TrashListParams) (*models.BookListResponse, error) {
+	conditions := []string{"deleted_at IS NOT NULL"}
+	var args []interface{}
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM books" + whereClause(conditions)
+	if err := r.db.QueryRow(countQuery).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count trashed books: %w", err)
+	}
+
+	if params.Cursor != "" {
+		cursor, value, err := decodeBookCursor(params.Cursor, trashSort, trashOrder)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "(deleted_at, id) < ($1, $2)")
+		args = append(args, value, cursor.ID)
+	}
+
+	query := fmt.Sprintf(`SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at, deleted_at 
+			  FROM books%s ORDER BY deleted_at DESC, id DESC LIMIT $%d`,
+		whereClause(conditions), len(args)+1)
+	args = append(args, params.Limit+1)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trashed books: %w", err)
+	}
+	defer rows.Close()
+
+	books := make([]models.Book, 0, params.Limit)
+	for rows.Next() {
+		var book models.Book
+		err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year,
+			&book.Description, &book.ISBN, &book.Genre, &book.Language, &book.Version,
+			&book.CreatedAt, &book.UpdatedAt, &book.DeletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan book: %w", err)
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch trashed books: %w", err)
+	}
+
+	response := &models.BookListResponse{Total: total}
+	if len(books) > params.Limit {
+		books = books[:params.Limit]
+		response.NextCursor = nextBookCursor(trashSort, trashOrder, &books[params.Limit-1])
+	}
+	response.Data = books
+
+	return response, nil
+}
+
+func (r *PostgresBookRepository) Restore(id string, restoredAt time.Time) error {
+	query := `UPDATE books SET deleted_at = NULL, updated_at = $1, version = version + 1 
+			  WHERE id = $2 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Exec(query, restoredAt, id)
+	if isUniqueViolation(err, "idx_books_isbn") {
+		return ErrDuplicateISBN
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify restore: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrBookNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresBookRepository) Purge(deletedBefore time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM books WHERE deleted_at < $1 AND NOT "+bookLent, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge books: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify purge: %w", err)
+	}
+
+	return purged, nil
+}
+
+// versionMismatch explains why a conditional write matched no rows: either
+// the book is gone or its version changed.
+func (r *PostgresBookRepository) versionMismatch(id string) error {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check book version: %w", err)
+	}
+	if !exists {
+		return ErrBookNotFound
+	}
+	return ErrVersionConflict
+}
+
+func (r *PostgresBookRepository) Export(ctx context.Context, params *models.BookListParams, fn func(*models.Book) error) error {
+	sortColumn, ok := bookSortColumns[params.Sort]
+	if !ok {
+		return fmt.Errorf("unsupported sort field: %s", params.Sort)
+	}
+
+	conditions, args := bookFilterConditions(params)
+	direction := strings.ToUpper(params.Order)
+	query := fmt.Sprintf(`SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at 
+			  FROM books%s ORDER BY %s %s, id %s`,
+		whereClause(conditions), sortColumn, direction, direction)
+
+	// lib/pq decodes rows from the connection as they are read, so the
+	// result set never has to fit in memory.
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export books: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book models.Book
+		err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year,
+			&book.Description, &book.ISBN, &book.Genre, &book.Language, &book.Version,
+			&book.CreatedAt, &book.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to scan book: %w", err)
+		}
+		if err := fn(&book); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export books: %w", err)
+	}
+
+	return nil
+}
+
+// bookFilterConditions translates the list filters into SQL conditions and
+// their positional arguments. Trashed books are always excluded.
+func bookFilterConditions(params *models.BookListParams) ([]string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	if params.Author != "" {
+		args = append(args, "%"+escapeLike(params.Author)+"%")
+		conditions = append(conditions, fmt.Sprintf("author ILIKE $%d", len(args)))
+	}
+	if params.Genre != "" {
+		args = append(args, pq.Array(params.GenreIDs))
+		conditions = append(conditions, fmt.Sprintf(bookGenreCondition, len(args)))
+	}
+	if params.YearFrom != nil {
+		args = append(args, *params.YearFrom)
+		conditions = append(conditions, fmt.Sprintf("year >= $%d", len(args)))
+	}
+	if params.YearTo != nil {
+		args = append(args, *params.YearTo)
+		conditions = append(conditions, fmt.Sprintf("year <= $%d", len(args)))
+	}
+	if params.HasISBN != nil {
+		if *params.HasISBN {
+			conditions = append(conditions, "isbn IS NOT NULL AND isbn <> ''")
+		} else {
+			conditions = append(conditions, "(isbn IS NULL OR isbn = '')")
+		}
+	}
+	if params.BranchID != "" {
+		args = append(args, params.BranchID)
+		conditions = append(conditions, fmt.Sprintf(bookBranchCondition, len(args)))
+	}
+
+	return conditions, args
+}
@@ -0,0 +1,733 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresBookRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	columns := []string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at"}
+
+	t.Run("success with defaults", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("1", "The Lord of the Rings", "J.R.R. Tolkien", 1954, "Epic fantasy novel.", "978-0618640157", "Fantasy", "english", 3, time.Now(), time.Now())
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1")).
+			WithArgs(21).
+			WillReturnRows(rows)
+
+		result, err := repo.List(&models.BookListParams{Limit: 20, Sort: "created_at", Order: "desc"})
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, 1, result.Total)
+		assert.Empty(t, result.NextCursor)
+		assert.Equal(t, "The Lord of the Rings", result.Data[0].Title)
+	})
+
+	t.Run("filters, sorting and next cursor", func(t *testing.T) {
+		yearFrom, yearTo, hasISBN := 1900, 2000, true
+		params := &models.BookListParams{
+			Limit:    1,
+			Author:   "tolkien",
+			Genre:    "Fantasy",
+			GenreIDs: []string{"5b1d7f0e-3c2a-4e8b-9f6d-1a2b3c4d5e6f"},
+			YearFrom: &yearFrom,
+			YearTo:   &yearTo,
+			HasISBN:  &hasISBN,
+			Sort:     "year",
+			Order:    "asc",
+		}
+		rows := sqlmock.NewRows(columns).
+			AddRow("2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e", "The Hobbit", "J.R.R. Tolkien", 1937, nil, "978-0618260300", "Fantasy", "english", 3, time.Now(), time.Now()).
+			AddRow("8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", "The Lord of the Rings", "J.R.R. Tolkien", 1954, nil, "978-0618640157", "Fantasy", "english", 3, time.Now(), time.Now())
+
+		where := " WHERE deleted_at IS NULL AND author ILIKE $1 AND id IN (SELECT book_id FROM book_genres WHERE genre_id = ANY($2)) AND year >= $3 AND year <= $4 AND isbn IS NOT NULL AND isbn <> ''"
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books"+where)).
+			WithArgs("%tolkien%", pq.Array(params.GenreIDs), 1900, 2000).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books"+where+" ORDER BY year ASC, id ASC LIMIT $5")).
+			WithArgs("%tolkien%", pq.Array(params.GenreIDs), 1900, 2000, 2).
+			WillReturnRows(rows)
+
+		result, err := repo.List(params)
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, 2, result.Total)
+		assert.NotEmpty(t, result.NextCursor)
+
+		params.Cursor = result.NextCursor
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books"+where)).
+			WithArgs("%tolkien%", pq.Array(params.GenreIDs), 1900, 2000).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books"+where+" AND (year, id) > ($5, $6) ORDER BY year ASC, id ASC LIMIT $7")).
+			WithArgs("%tolkien%", pq.Array(params.GenreIDs), 1900, 2000, 1937, "2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e", 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", "The Lord of the Rings", "J.R.R. Tolkien", 1954, nil, "978-0618640157", "Fantasy", "english", 3, time.Now(), time.Now()))
+
+		result, err = repo.List(params)
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "The Lord of the Rings", result.Data[0].Title)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		result, err := repo.List(&models.BookListParams{Limit: 20, Sort: "created_at", Order: "desc", Cursor: "not-a-cursor"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Nil(t, result)
+	})
+
+	t.Run("cursor issued for another sort", func(t *testing.T) {
+		cursor := encodePageCursor(pageCursor{Sort: "title", Order: "asc", Value: "Dune", ID: "2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e"})
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+		result, err := repo.List(&models.BookListParams{Limit: 20, Sort: "year", Order: "asc", Cursor: cursor})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Nil(t, result)
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books WHERE deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT $1")).
+			WillReturnError(errors.New("db error"))
+
+		result, err := repo.List(&models.BookListParams{Limit: 20, Sort: "created_at", Order: "desc"})
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to fetch books: db error")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_Search(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	columns := []string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at",
+		"rank", "title_highlight", "description_highlight", "total"}
+	selectPrefix := "SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at, ts_rank_cd(search_vector, query) AS rank"
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("1", "The Hobbit", "J.R.R. Tolkien", 1937, "A hobbit goes on an adventure.", nil, "Fantasy", "english", 3, time.Now(), time.Now(),
+				0.6, "The <mark>Hobbit</mark>", "A <mark>hobbit</mark> goes on an adventure.", 1)
+
+		mock.ExpectQuery(regexp.QuoteMeta(selectPrefix)+".*"+
+			regexp.QuoteMeta("FROM books, websearch_to_tsquery($1::regconfig, $2) AS query WHERE search_vector @@ query AND deleted_at IS NULL ORDER BY rank DESC, id LIMIT $3 OFFSET $4")).
+			WithArgs("english", "hobbits", 20, 0).
+			WillReturnRows(rows)
+
+		result, err := repo.Search(&models.BookSearchParams{Query: "hobbits", Limit: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, "The Hobbit", result.Data[0].Title)
+		assert.Equal(t, 0.6, result.Data[0].Rank)
+		assert.Equal(t, "The <mark>Hobbit</mark>", result.Data[0].Highlights.Title)
+	})
+
+	t.Run("restricted to language", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectPrefix)+".*"+
+			regexp.QuoteMeta("WHERE search_vector @@ query AND deleted_at IS NULL AND language = $1::regconfig ORDER BY rank DESC, id LIMIT $3 OFFSET $4")).
+			WithArgs("french", "étranger", 5, 10).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		result, err := repo.Search(&models.BookSearchParams{Query: "étranger", Lang: "french", Limit: 5, Offset: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+		assert.Empty(t, result.Data)
+	})
+
+	t.Run("restricted to genres", func(t *testing.T) {
+		genreIDs := pq.Array([]string{"5b1d7f0e-3c2a-4e8b-9f6d-1a2b3c4d5e6f"})
+		mock.ExpectQuery(regexp.QuoteMeta(selectPrefix)+".*"+
+			regexp.QuoteMeta("WHERE search_vector @@ query AND deleted_at IS NULL AND id IN (SELECT book_id FROM book_genres WHERE genre_id = ANY($5)) ORDER BY rank DESC, id LIMIT $3 OFFSET $4")).
+			WithArgs("english", "dragons", 20, 0, genreIDs).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		result, err := repo.Search(&models.BookSearchParams{
+			Query:    "dragons",
+			Genre:    "Fantasy",
+			GenreIDs: []string{"5b1d7f0e-3c2a-4e8b-9f6d-1a2b3c4d5e6f"},
+			Limit:    20,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, result.Data)
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta(selectPrefix)).
+			WillReturnError(errors.New("db error"))
+
+		result, err := repo.Search(&models.BookSearchParams{Query: "hobbits", Limit: 20})
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "failed to search books: db error")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_GetByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	bookID := "some-uuid"
+
+	t.Run("success", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at"}).
+			AddRow(bookID, "The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy novel.", "978-0618260300", "Fantasy", "english", 3, time.Now(), time.Now())
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books WHERE id = $1 AND deleted_at IS NULL")).
+			WithArgs(bookID).
+			WillReturnRows(rows)
+
+		book, err := repo.GetByID(bookID)
+		assert.NoError(t, err)
+		assert.NotNil(t, book)
+		assert.Equal(t, bookID, book.ID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books WHERE id = $1 AND deleted_at IS NULL")).
+			WithArgs(bookID).
+			WillReturnError(sql.ErrNoRows)
+
+		book, err := repo.GetByID(bookID)
+		assert.Error(t, err)
+		assert.Nil(t, book)
+		assert.EqualError(t, err, "book not found")
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books WHERE id = $1 AND deleted_at IS NULL")).
+			WithArgs(bookID).
+			WillReturnError(errors.New("db error"))
+
+		book, err := repo.GetByID(bookID)
+		assert.Error(t, err)
+		assert.Nil(t, book)
+		assert.EqualError(t, err, "failed to fetch book: db error")
+	})
+}
+
+func TestPostgresBookRepository_GetByISBN(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	isbn := "9780306406157"
+	query := regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at FROM books WHERE isbn = $1 AND deleted_at IS NULL")
+
+	rows := sqlmock.NewRows([]string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at"}).
+		AddRow("some-uuid", "Signal Processing", "Someone", 1990, nil, isbn, nil, "english", 1, time.Now(), time.Now())
+	mock.ExpectQuery(query).WithArgs(isbn).WillReturnRows(rows)
+
+	book, err := repo.GetByISBN(isbn)
+	assert.NoError(t, err)
+	if assert.NotNil(t, book) {
+		assert.Equal(t, "some-uuid", book.ID)
+	}
+
+	mock.ExpectQuery(query).WithArgs(isbn).WillReturnError(sql.ErrNoRows)
+	_, err = repo.GetByISBN(isbn)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	book := &models.Book{
+		ID:        "some-uuid",
+		Title:     "New Book",
+		Author:    "Test Author",
+		Year:      2024,
+		Language:  "english",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO books (id, title, author, year, description, isbn, genre, language, version, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)")).
+			WithArgs(book.ID, book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.Version, book.CreatedAt, book.UpdatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Create(book)
+		assert.NoError(t, err)
+	})
+
+	t.Run("duplicate isbn", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO books (id, title, author, year, description, isbn, genre, language, version, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)")).
+			WithArgs(book.ID, book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.Version, book.CreatedAt, book.UpdatedAt).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_books_isbn"})
+
+		err := repo.Create(book)
+		assert.ErrorIs(t, err, ErrDuplicateISBN)
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO books (id, title, author, year, description, isbn, genre, language, version, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)")).
+			WithArgs(book.ID, book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.Version, book.CreatedAt, book.UpdatedAt).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.Create(book)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "failed to create book: db error")
+	})
+}
+
+func TestPostgresBookRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	book := &models.Book{
+		ID:        "some-uuid",
+		Title:     "Updated Title",
+		Author:    "Updated Author",
+		Year:      2025,
+		Language:  "english",
+		UpdatedAt: time.Now(),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		book.Version = 2
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, author = $2, year = $3, description = $4, isbn = $5, genre = $6, language = $7, updated_at = $8, version = version + 1 WHERE id = $9 AND version = $10 AND deleted_at IS NULL")).
+			WithArgs(book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.UpdatedAt, book.ID, 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Update(book)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, book.Version)
+		book.Version = 2
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, author = $2, year = $3, description = $4, isbn = $5, genre = $6, language = $7, updated_at = $8, version = version + 1 WHERE id = $9 AND version = $10 AND deleted_at IS NULL")).
+			WithArgs(book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.UpdatedAt, book.ID, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)")).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := repo.Update(book)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+		assert.Equal(t, 2, book.Version)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, author = $2, year = $3, description = $4, isbn = $5, genre = $6, language = $7, updated_at = $8, version = version + 1 WHERE id = $9 AND version = $10 AND deleted_at IS NULL")).
+			WithArgs(book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.UpdatedAt, book.ID, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)")).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.Update(book)
+		assert.ErrorIs(t, err, ErrBookNotFound)
+	})
+
+	t.Run("db error on update", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, author = $2, year = $3, description = $4, isbn = $5, genre = $6, language = $7, updated_at = $8, version = version + 1 WHERE id = $9 AND version = $10 AND deleted_at IS NULL")).
+			WithArgs(book.Title, book.Author, book.Year, book.Description, book.ISBN, book.Genre, "english", book.UpdatedAt, book.ID, 2).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.Update(book)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "failed to update book: db error")
+	})
+}
+
+func TestPostgresBookRepository_UpdateFields(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	now := time.Now()
+
+	t.Run("only changed columns", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET description = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND version = $4 AND deleted_at IS NULL")).
+			WithArgs(nil, now, "some-uuid", 4).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.UpdateFields("some-uuid", 4, map[string]interface{}{"updated_at": now, "description": (*string)(nil)})
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, version = version + 1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL")).
+			WithArgs("Title", "missing", 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)")).
+			WithArgs("missing").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.UpdateFields("missing", 1, map[string]interface{}{"title": "Title"})
+		assert.ErrorIs(t, err, ErrBookNotFound)
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, version = version + 1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL")).
+			WithArgs("Title", "some-uuid", 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)")).
+			WithArgs("some-uuid").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		err := repo.UpdateFields("some-uuid", 1, map[string]interface{}{"title": "Title"})
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("duplicate isbn", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET isbn = $1, version = version + 1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL")).
+			WithArgs("9780306406157", "some-uuid", 1).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_books_isbn"})
+
+		isbn := "9780306406157"
+		err := repo.UpdateFields("some-uuid", 1, map[string]interface{}{"isbn": &isbn})
+		assert.ErrorIs(t, err, ErrDuplicateISBN)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		err := repo.UpdateFields("some-uuid", 1, map[string]interface{}{"id": "other"})
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	bookID := "some-uuid"
+	deletedAt := time.Now()
+
+	t.Run("moves the book to the trash", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL")).
+			WithArgs(deletedAt, bookID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repo.Delete(bookID, 0, deletedAt)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL")).
+			WithArgs(deletedAt, bookID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, (EXISTS")).
+			WithArgs(bookID).
+			WillReturnError(sql.ErrNoRows)
+
+		err := repo.Delete(bookID, 0, deletedAt)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "book not found")
+	})
+
+	t.Run("in circulation", func(t *testing.T) {
+		mock.ExpectExec("(?s)"+regexp.QuoteMeta("UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL AND NOT (EXISTS")+
+			".*"+regexp.QuoteMeta("h.status IN ('waiting', 'in_transit', 'ready')")).
+			WithArgs(deletedAt, bookID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, (EXISTS")).
+			WithArgs(bookID).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "in_circulation"}).AddRow(3, true))
+
+		err := repo.Delete(bookID, 0, deletedAt)
+		assert.ErrorIs(t, err, ErrBookInCirculation)
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL AND NOT")).
+			WithArgs(deletedAt, bookID, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT version, (EXISTS")).
+			WithArgs(bookID).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "in_circulation"}).AddRow(3, true))
+
+		err := repo.Delete(bookID, 2, deletedAt)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("db error on delete", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL")).
+			WithArgs(deletedAt, bookID).
+			WillReturnError(errors.New("db error"))
+
+		err := repo.Delete(bookID, 0, deletedAt)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "failed to delete book: db error")
+	})
+
+	t.Run("error on rows affected", func(t *testing.T) {
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET deleted_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NULL")).
+			WithArgs(deletedAt, bookID).
+			WillReturnResult(driver.ResultNoRows)
+
+		err := repo.Delete(bookID, 0, deletedAt)
+		assert.Error(t, err)
+		assert.EqualError(t, err, "failed to verify deletion: no RowsAffected available after DDL statement")
+	})
+}
+
+func TestPostgresBookRepository_ListTrash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	columns := []string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at", "deleted_at"}
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("first page", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NOT NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at, deleted_at FROM books WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC LIMIT $1")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", "Dune", "Frank Herbert", 1965, nil, nil, nil, "english", 2, time.Now(), time.Now(), deletedAt).
+				AddRow("2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e", "Emma", "Jane Austen", 1815, nil, nil, nil, "english", 2, time.Now(), time.Now(), deletedAt.Add(-time.Hour)))
+
+		result, err := repo.ListTrash(&models.TrashListParams{Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, deletedAt, *result.Data[0].DeletedAt)
+		assert.NotEmpty(t, result.NextCursor)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NOT NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM books WHERE deleted_at IS NOT NULL AND (deleted_at, id) < ($1, $2) ORDER BY deleted_at DESC, id DESC LIMIT $3")).
+			WithArgs(deletedAt, "8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", 2).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		next, err := repo.ListTrash(&models.TrashListParams{Limit: 1, Cursor: result.NextCursor})
+		assert.NoError(t, err)
+		assert.Empty(t, next.Data)
+	})
+
+	t.Run("cursor from another listing", func(t *testing.T) {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM books WHERE deleted_at IS NOT NULL")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+		cursor := nextBookCursor("created_at", "desc", &models.Book{ID: "8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", CreatedAt: deletedAt})
+		_, err := repo.ListTrash(&models.TrashListParams{Limit: 1, Cursor: cursor})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_Restore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	restoredAt := time.Now()
+	query := regexp.QuoteMeta("UPDATE books SET deleted_at = NULL, updated_at = $1, version = version + 1 WHERE id = $2 AND deleted_at IS NOT NULL")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(restoredAt, "some-uuid").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		assert.NoError(t, repo.Restore("some-uuid", restoredAt))
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(restoredAt, "some-uuid").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.ErrorIs(t, repo.Restore("some-uuid", restoredAt), ErrBookNotFound)
+	})
+
+	t.Run("isbn reused", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(restoredAt, "some-uuid").
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_books_isbn"})
+
+		assert.ErrorIs(t, repo.Restore("some-uuid", restoredAt), ErrDuplicateISBN)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	cutoff := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM books WHERE deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM items i JOIN loans l")).
+		WithArgs(cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := repo.Purge(cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_CreateBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	now := time.Now()
+	books := []*models.Book{
+		{ID: "first", Title: "Dune", Author: "Frank Herbert", Year: 1965, Language: "english", Version: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "second", Title: "Emma", Author: "Jane Austen", Year: 1815, Language: "english", Version: 1, CreatedAt: now, UpdatedAt: now},
+	}
+	insert := regexp.QuoteMeta("INSERT INTO books (id, title, author, year, description, isbn, genre, language, version, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		prepared := mock.ExpectPrepare(insert)
+		prepared.ExpectExec().WithArgs("first", "Dune", "Frank Herbert", 1965, nil, nil, nil, "english", 1, now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		prepared.ExpectExec().WithArgs("second", "Emma", "Jane Austen", 1815, nil, nil, nil, "english", 1, now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.CreateBatch(books))
+	})
+
+	t.Run("rolls back on duplicate isbn", func(t *testing.T) {
+		mock.ExpectBegin()
+		prepared := mock.ExpectPrepare(insert)
+		prepared.ExpectExec().WithArgs("first", "Dune", "Frank Herbert", 1965, nil, nil, nil, "english", 1, now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		prepared.ExpectExec().WithArgs("second", "Emma", "Jane Austen", 1815, nil, nil, nil, "english", 1, now, now).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_books_isbn"})
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.CreateBatch(books), ErrDuplicateISBN)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_ExistingISBNs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	isbns := []string{"9780306406157", "9780262033848"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT isbn FROM books WHERE isbn = ANY($1) AND deleted_at IS NULL")).
+		WithArgs(pq.Array(isbns)).
+		WillReturnRows(sqlmock.NewRows([]string{"isbn"}).AddRow("9780306406157"))
+
+	existing, err := repo.ExistingISBNs(isbns)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"9780306406157": true}, existing)
+
+	existing, err = repo.ExistingISBNs(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, existing)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_Export(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db)
+	now := time.Now()
+	columns := []string{"id", "title", "author", "year", "description", "isbn", "genre", "language", "version", "created_at", "updated_at"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id, title, author, year, description, isbn, genre, language, version, created_at, updated_at 
+			  FROM books WHERE deleted_at IS NULL AND author ILIKE $1 ORDER BY year DESC, id DESC`)).
+		WithArgs("%tolkien%").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("2", "The Lord of the Rings", "J.R.R. Tolkien", 1954, nil, nil, nil, "english", 1, now, now).
+			AddRow("1", "The Hobbit", "J.R.R. Tolkien", 1937, nil, nil, nil, "english", 3, now, now))
+
+	var books []models.Book
+	err = repo.Export(context.Background(), &models.BookListParams{Author: "tolkien", Sort: "year", Order: "desc"}, func(book *models.Book) error {
+		books = append(books, *book)
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, books, 2) {
+		assert.Equal(t, "The Lord of the Rings", books[0].Title)
+		assert.Equal(t, 3, books[1].Version)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
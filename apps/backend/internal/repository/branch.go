@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"errors"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrBranchNotFound = errors.New("branch not found")
+	// ErrDuplicateBranchCode is returned when a branch would get the code of
+	// another branch.
+	ErrDuplicateBranchCode = errors.New("a branch with this code already exists")
+	// ErrBranchInUse is returned when deleting a branch that copies, loans,
+	// holds or transfers still refer to.
+	ErrBranchInUse = errors.New("branch has copies or circulation history")
+)
+
+// BranchRepository persists the library's branches.
+type BranchRepository interface {
+	// List returns every branch ordered by code.
+	List() ([]models.Branch, error)
+	GetByID(id string) (*models.Branch, error)
+	Create(branch *models.Branch) error
+	Update(branch *models.Branch) error
+	Delete(id string) error
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryBranchRepository keeps branches in a map guarded by a RWMutex. It
+// checks deletes against the copies and circulation of a
+// MemoryLoanRepository, whose locks are always taken after its own, and
+// against the sessions of the MemoryInventoryRepository sharing it, whose
+// lock is taken before the loan repository's.
+type MemoryBranchRepository struct {
+	mu        sync.RWMutex
+	loans     *MemoryLoanRepository
+	inventory *MemoryInventoryRepository
+	branches  map[string]models.Branch
+}
+
+func NewMemoryBranchRepository(loans *MemoryLoanRepository) *MemoryBranchRepository {
+	return &MemoryBranchRepository{
+		loans:    loans,
+		branches: make(map[string]models.Branch),
+	}
+}
+
+func (r *MemoryBranchRepository) List() ([]models.Branch, error) {
+	r.mu.RLock()
+	branches := make([]models.Branch, 0, len(r.branches))
+	for _, branch := range r.branches {
+		branches = append(branches, branch)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(branches, func(i, j int) bool {
+		return branches[i].Code < branches[j].Code
+	})
+	return branches, nil
+}
+
+func (r *MemoryBranchRepository) GetByID(id string) (*models.Branch, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	branch, ok := r.branches[id]
+	if !ok {
+		return nil, ErrBranchNotFound
+	}
+	return &branch, nil
+}
+
+func (r *MemoryBranchRepository) Create(branch *models.Branch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasCode(branch.Code, branch.ID) {
+		return ErrDuplicateBranchCode
+	}
+	r.branches[branch.ID] = *branch
+	return nil
+}
+
+func (r *MemoryBranchRepository) Update(branch *models.Branch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.branches[branch.ID]
+	if !ok {
+		return ErrBranchNotFound
+	}
+	if r.hasCode(branch.Code, branch.ID) {
+		return ErrDuplicateBranchCode
+	}
+
+	existing.Code = branch.Code
+	existing.Name = branch.Name
+	existing.Address = branch.Address
+	existing.UpdatedAt = branch.UpdatedAt
+	r.branches[branch.ID] = existing
+	return nil
+}
+
+func (r *MemoryBranchRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.branches[id]; !ok {
+		return ErrBranchNotFound
+	}
+	if (r.inventory != nil && r.inventory.hasBranch(id)) || r.inUse(id) {
+		return ErrBranchInUse
+	}
+
+	delete(r.branches, id)
+	return nil
+}
+
+// hasCode reports whether a branch other than id has a code. Callers must
+// hold the lock.
+func (r *MemoryBranchRepository) hasCode(code, id string) bool {
+	for _, branch := range r.branches {
+		if branch.Code == code && branch.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// inUse reports whether copies, loans, holds or transfers refer to a
+// branch. Callers must hold the lock.
+func (r *MemoryBranchRepository) inUse(id string) bool {
+	loans := r.loans
+	loans.mu.RLock()
+	defer loans.mu.RUnlock()
+	loans.items.mu.RLock()
+	defer loans.items.mu.RUnlock()
+
+	for _, item := range loans.items.items {
+		if isBranch(item.HomeBranchID, id) || isBranch(item.CurrentBranchID, id) {
+			return true
+		}
+	}
+	for _, loan := range loans.loans {
+		if isBranch(loan.BranchID, id) {
+			return true
+		}
+	}
+	for _, stored := range loans.holds {
+		if isBranch(stored.hold.PickupBranchID, id) {
+			return true
+		}
+	}
+	for _, transfer := range loans.transfers {
+		if transfer.FromBranchID == id || transfer.ToBranchID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isBranch reports whether an optional branch reference names a branch.
+func isBranch(branchID *string, id string) bool {
+	return branchID != nil && *branchID == id
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryBranch(code, name string) *models.Branch {
+	return &models.Branch{
+		ID:        uuid.New().String(),
+		Code:      code,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestMemoryBranchRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	loans := NewMemoryLoanRepository(items, NewMemoryMemberRepository())
+	repo := NewMemoryBranchRepository(loans)
+
+	north := newMemoryBranch("NORTH", "North Branch")
+	require.NoError(t, repo.Create(north))
+	main := newMemoryBranch("MAIN", "Main Library")
+	require.NoError(t, repo.Create(main))
+	assert.ErrorIs(t, repo.Create(newMemoryBranch("MAIN", "Another")), ErrDuplicateBranchCode)
+
+	branches, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, branches, 2)
+	assert.Equal(t, "MAIN", branches[0].Code)
+
+	north.Code = "MAIN"
+	assert.ErrorIs(t, repo.Update(north), ErrDuplicateBranchCode)
+	north.Code = "NORTH"
+	north.Name = "North End"
+	require.NoError(t, repo.Update(north))
+	stored, err := repo.GetByID(north.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "North End", stored.Name)
+	assert.ErrorIs(t, repo.Update(newMemoryBranch("WEST", "West")), ErrBranchNotFound)
+
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	item := newMemoryItem(book.ID, "E-1")
+	item.HomeBranchID = &main.ID
+	item.CurrentBranchID = &main.ID
+	require.NoError(t, items.Create(item, HoldPickup{}))
+
+	assert.ErrorIs(t, repo.Delete(main.ID), ErrBranchInUse)
+	require.NoError(t, repo.Delete(north.ID))
+	assert.ErrorIs(t, repo.Delete(north.ID), ErrBranchNotFound)
+}
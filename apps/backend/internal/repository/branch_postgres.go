@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	branchCodeIndex = "idx_branches_code"
+
+	branchColumns = "id, code, name, address, created_at, updated_at"
+)
+
+// branchReferenceFKeys are the foreign keys that keep a branch in use.
+var branchReferenceFKeys = []string{
+	"items_home_branch_id_fkey",
+	"items_current_branch_id_fkey",
+	"loans_branch_id_fkey",
+	"holds_pickup_branch_id_fkey",
+	"transfers_from_branch_id_fkey",
+	"transfers_to_branch_id_fkey",
+	"inventory_sessions_branch_id_fkey",
+}
+
+type PostgresBranchRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresBranchRepository(db *sql.DB) *PostgresBranchRepository {
+	return &PostgresBranchRepository{db: db}
+}
+
+func scanBranch(scanner interface{ Scan(...interface{}) error }, branch *models.Branch) error {
+	return scanner.Scan(&branch.ID, &branch.Code, &branch.Name, &branch.Address, &branch.CreatedAt, &branch.UpdatedAt)
+}
+
+func (r *PostgresBranchRepository) List() ([]models.Branch, error) {
+	rows, err := r.db.Query("SELECT " + branchColumns + " FROM branches ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branches: %w", err)
+	}
+	defer rows.Close()
+
+	branches := []models.Branch{}
+	for rows.Next() {
+		var branch models.Branch
+		if err := scanBranch(rows, &branch); err != nil {
+			return nil, fmt.Errorf("failed to scan branch: %w", err)
+		}
+		branches = append(branches, branch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch branches: %w", err)
+	}
+
+	return branches, nil
+}
+
+func (r *PostgresBranchRepository) GetByID(id string) (*models.Branch, error) {
+	var branch models.Branch
+	err := scanBranch(r.db.QueryRow("SELECT "+branchColumns+" FROM branches WHERE id = $1", id), &branch)
+	if err == sql.ErrNoRows {
+		return nil, ErrBranchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branch: %w", err)
+	}
+
+	return &branch, nil
+}
+
+func (r *PostgresBranchRepository) Create(branch *models.Branch) error {
+	_, err := r.db.Exec(`INSERT INTO branches (id, code, name, address, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)`,
+		branch.ID, branch.Code, branch.Name, branch.Address, branch.CreatedAt, branch.UpdatedAt)
+	if isUniqueViolation(err, branchCodeIndex) {
+		return ErrDuplicateBranchCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresBranchRepository) Update(branch *models.Branch) error {
+	result, err := r.db.Exec("UPDATE branches SET code = $1, name = $2, address = $3, updated_at = $4 WHERE id = $5",
+		branch.Code, branch.Name, branch.Address, branch.UpdatedAt, branch.ID)
+	if isUniqueViolation(err, branchCodeIndex) {
+		return ErrDuplicateBranchCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update branch: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrBranchNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresBranchRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM branches WHERE id = $1", id)
+	for _, constraint := range branchReferenceFKeys {
+		if isForeignKeyViolation(err, constraint) {
+			return ErrBranchInUse
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete branch: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrBranchNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresBranchRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBranchRepository(db)
+	now := time.Now()
+	branch := &models.Branch{ID: "br1", Code: "MAIN", Name: "Main Library", CreatedAt: now, UpdatedAt: now}
+	insert := regexp.QuoteMeta("INSERT INTO branches (id, code, name, address, created_at, updated_at)")
+
+	mock.ExpectExec(insert).
+		WithArgs("br1", "MAIN", "Main Library", nil, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Create(branch))
+
+	mock.ExpectExec(insert).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_branches_code"})
+	assert.ErrorIs(t, repo.Create(branch), ErrDuplicateBranchCode)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBranchRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBranchRepository(db)
+	del := regexp.QuoteMeta("DELETE FROM branches WHERE id = $1")
+
+	mock.ExpectExec(del).WithArgs("br1").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Delete("br1"))
+
+	mock.ExpectExec(del).WithArgs("br2").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete("br2"), ErrBranchNotFound)
+
+	mock.ExpectExec(del).
+		WithArgs("br3").
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "transfers_to_branch_id_fkey"})
+	assert.ErrorIs(t, repo.Delete("br3"), ErrBranchInUse)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+
+	"library-management-backend/internal/models"
+)
+
+var ErrClosureNotFound = errors.New("closure not found")
+
+// CalendarRepository stores the weekly opening hours and the closures of
+// the library and of its branches. Writes for a branch that does not exist
+// return ErrBranchNotFound.
+type CalendarRepository interface {
+	// Hours returns the opening hours of the library and of every branch
+	// with hours of its own, the library's first, then by branch and
+	// weekday.
+	Hours() ([]models.OpeningHours, error)
+	// ReplaceHours replaces the whole weekly schedule of a branch, or of
+	// the library when branchID is nil, in one write.
+	ReplaceHours(branchID *string, hours []models.OpeningHours) error
+	// ListClosures returns the closures overlapping from to to, either of
+	// which may be empty, and every annual closure, by start date. Unless
+	// branchID is empty only the library's closures and the branch's are
+	// returned.
+	ListClosures(from, to, branchID string) ([]models.Closure, error)
+	GetClosure(id string) (*models.Closure, error)
+	CreateClosure(closure *models.Closure) error
+	UpdateClosure(closure *models.Closure) error
+	DeleteClosure(id string) error
+}
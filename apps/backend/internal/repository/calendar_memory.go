@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"library-management-backend/internal/models"
+)
+
+type MemoryCalendarRepository struct {
+	mu       sync.RWMutex
+	hours    []models.OpeningHours
+	closures map[string]models.Closure
+}
+
+func NewMemoryCalendarRepository() *MemoryCalendarRepository {
+	return &MemoryCalendarRepository{
+		hours:    []models.OpeningHours{},
+		closures: make(map[string]models.Closure),
+	}
+}
+
+func (r *MemoryCalendarRepository) Hours() ([]models.OpeningHours, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]models.OpeningHours{}, r.hours...), nil
+}
+
+func (r *MemoryCalendarRepository) ReplaceHours(branchID *string, hours []models.OpeningHours) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := []models.OpeningHours{}
+	for _, day := range r.hours {
+		if !equalOptional(day.BranchID, branchID) {
+			kept = append(kept, day)
+		}
+	}
+	for _, day := range hours {
+		day.BranchID = branchID
+		kept = append(kept, day)
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		a, b := kept[i].BranchID, kept[j].BranchID
+		if !equalOptional(a, b) {
+			return a == nil || b != nil && *a < *b
+		}
+		return kept[i].Weekday < kept[j].Weekday
+	})
+	r.hours = kept
+	return nil
+}
+
+func (r *MemoryCalendarRepository) ListClosures(from, to, branchID string) ([]models.Closure, error) {
+	r.mu.RLock()
+	closures := []models.Closure{}
+	for _, closure := range r.closures {
+		if branchID != "" && closure.BranchID != nil && *closure.BranchID != branchID {
+			continue
+		}
+		if closure.Annual || (from == "" || closure.EndsOn >= from) && (to == "" || closure.StartsOn <= to) {
+			closures = append(closures, closure)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(closures, func(i, j int) bool {
+		if closures[i].StartsOn != closures[j].StartsOn {
+			return closures[i].StartsOn < closures[j].StartsOn
+		}
+		return closures[i].ID < closures[j].ID
+	})
+	return closures, nil
+}
+
+func (r *MemoryCalendarRepository) GetClosure(id string) (*models.Closure, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	closure, ok := r.closures[id]
+	if !ok {
+		return nil, ErrClosureNotFound
+	}
+	return &closure, nil
+}
+
+func (r *MemoryCalendarRepository) CreateClosure(closure *models.Closure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closures[closure.ID] = *closure
+	return nil
+}
+
+func (r *MemoryCalendarRepository) UpdateClosure(closure *models.Closure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.closures[closure.ID]
+	if !ok {
+		return ErrClosureNotFound
+	}
+	closure.CreatedAt = existing.CreatedAt
+	r.closures[closure.ID] = *closure
+	return nil
+}
+
+func (r *MemoryCalendarRepository) DeleteClosure(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.closures[id]; !ok {
+		return ErrClosureNotFound
+	}
+	delete(r.closures, id)
+	return nil
+}
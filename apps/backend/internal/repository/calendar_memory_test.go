@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryClosure(name, startsOn, endsOn string, annual bool) *models.Closure {
+	return &models.Closure{
+		ID:        uuid.New().String(),
+		Name:      name,
+		StartsOn:  startsOn,
+		EndsOn:    endsOn,
+		Annual:    annual,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestMemoryCalendarRepository(t *testing.T) {
+	repo := NewMemoryCalendarRepository()
+
+	require.NoError(t, repo.ReplaceHours(nil, []models.OpeningHours{
+		{Weekday: 3, Opens: "10:00", Closes: "16:00"},
+		{Weekday: 1, Opens: "09:00", Closes: "17:00"},
+	}))
+	hours, err := repo.Hours()
+	require.NoError(t, err)
+	require.Len(t, hours, 2)
+	assert.Equal(t, 1, hours[0].Weekday)
+
+	branchID := "b1"
+	require.NoError(t, repo.ReplaceHours(&branchID, []models.OpeningHours{{Weekday: 6, Opens: "10:00", Closes: "14:00"}}))
+	require.NoError(t, repo.ReplaceHours(nil, []models.OpeningHours{{Weekday: 2, Opens: "09:00", Closes: "17:00"}}))
+	hours, err = repo.Hours()
+	require.NoError(t, err)
+	require.Len(t, hours, 2, "replacing the library's hours keeps the branch's")
+	assert.Nil(t, hours[0].BranchID, "the library's hours come first")
+	assert.Equal(t, 2, hours[0].Weekday)
+	assert.Equal(t, &branchID, hours[1].BranchID)
+
+	christmas := newMemoryClosure("Christmas", "2023-12-25", "2023-12-26", true)
+	require.NoError(t, repo.CreateClosure(christmas))
+	easter := newMemoryClosure("Easter", "2024-03-29", "2024-04-01", false)
+	require.NoError(t, repo.CreateClosure(easter))
+	stocktake := newMemoryClosure("Stocktake", "2024-11-04", "2024-11-05", false)
+	require.NoError(t, repo.CreateClosure(stocktake))
+
+	closures, err := repo.ListClosures("2024-04-01", "2024-06-30", "")
+	require.NoError(t, err)
+	require.Len(t, closures, 2, "annual closures are always listed")
+	assert.Equal(t, christmas.ID, closures[0].ID)
+	assert.Equal(t, easter.ID, closures[1].ID)
+
+	closures, err = repo.ListClosures("", "", "")
+	require.NoError(t, err)
+	assert.Len(t, closures, 3)
+
+	stocktake.BranchID = &branchID
+	require.NoError(t, repo.UpdateClosure(stocktake))
+	closures, err = repo.ListClosures("2024-11-01", "", "b2")
+	require.NoError(t, err)
+	require.Len(t, closures, 1, "another branch's closure is left out")
+	assert.Equal(t, christmas.ID, closures[0].ID)
+	closures, err = repo.ListClosures("2024-11-01", "", branchID)
+	require.NoError(t, err)
+	assert.Len(t, closures, 2)
+
+	updated := *stocktake
+	updated.EndsOn = "2024-11-06"
+	updated.CreatedAt = time.Time{}
+	require.NoError(t, repo.UpdateClosure(&updated))
+	assert.Equal(t, stocktake.CreatedAt, updated.CreatedAt)
+	assert.ErrorIs(t, repo.UpdateClosure(newMemoryClosure("Missing", "2024-01-01", "2024-01-01", false)), ErrClosureNotFound)
+
+	require.NoError(t, repo.DeleteClosure(easter.ID))
+	_, err = repo.GetClosure(easter.ID)
+	assert.ErrorIs(t, err, ErrClosureNotFound)
+	assert.ErrorIs(t, repo.DeleteClosure(easter.ID), ErrClosureNotFound)
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	openingHoursBranchFKey = "opening_hours_branch_id_fkey"
+	closureBranchFKey      = "closures_branch_id_fkey"
+
+	closureColumns = `id, branch_id, name, to_char(starts_on, 'YYYY-MM-DD') AS starts_on, to_char(ends_on, 'YYYY-MM-DD') AS ends_on,
+			  annual, created_at, updated_at`
+)
+
+type PostgresCalendarRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCalendarRepository(db *sql.DB) *PostgresCalendarRepository {
+	return &PostgresCalendarRepository{db: db}
+}
+
+func scanClosure(scanner interface{ Scan(...interface{}) error }, closure *models.Closure) error {
+	return scanner.Scan(&closure.ID, &closure.BranchID, &closure.Name, &closure.StartsOn, &closure.EndsOn, &closure.Annual,
+		&closure.CreatedAt, &closure.UpdatedAt)
+}
+
+func (r *PostgresCalendarRepository) Hours() ([]models.OpeningHours, error) {
+	rows, err := r.db.Query(`SELECT branch_id, weekday, to_char(opens, 'HH24:MI') AS opens, to_char(closes, 'HH24:MI') AS closes
+			  FROM opening_hours ORDER BY branch_id NULLS FIRST, weekday`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch opening hours: %w", err)
+	}
+	defer rows.Close()
+
+	hours := []models.OpeningHours{}
+	for rows.Next() {
+		var day models.OpeningHours
+		if err := rows.Scan(&day.BranchID, &day.Weekday, &day.Opens, &day.Closes); err != nil {
+			return nil, fmt.Errorf("failed to scan opening hours: %w", err)
+		}
+		hours = append(hours, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch opening hours: %w", err)
+	}
+
+	return hours, nil
+}
+
+func (r *PostgresCalendarRepository) ReplaceHours(branchID *string, hours []models.OpeningHours) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM opening_hours WHERE branch_id IS NOT DISTINCT FROM $1", branchID); err != nil {
+		return fmt.Errorf("failed to clear opening hours: %w", err)
+	}
+	for _, day := range hours {
+		_, err := tx.Exec("INSERT INTO opening_hours (branch_id, weekday, opens, closes) VALUES ($1, $2, $3, $4)",
+			branchID, day.Weekday, day.Opens, day.Closes)
+		if isForeignKeyViolation(err, openingHoursBranchFKey) {
+			return ErrBranchNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert opening hours: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit opening hours: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCalendarRepository) ListClosures(from, to, branchID string) ([]models.Closure, error) {
+	dates := []string{}
+	args := []interface{}{}
+	if from != "" {
+		args = append(args, from)
+		dates = append(dates, fmt.Sprintf("ends_on >= $%d", len(args)))
+	}
+	if to != "" {
+		args = append(args, to)
+		dates = append(dates, fmt.Sprintf("starts_on <= $%d", len(args)))
+	}
+
+	conditions := []string{}
+	if len(dates) > 0 {
+		conditions = append(conditions, "(annual OR ("+strings.Join(dates, " AND ")+"))")
+	}
+	if branchID != "" {
+		args = append(args, branchID)
+		conditions = append(conditions, fmt.Sprintf("(branch_id IS NULL OR branch_id = $%d)", len(args)))
+	}
+	query := "SELECT " + closureColumns + " FROM closures" + whereClause(conditions) + " ORDER BY starts_on, id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch closures: %w", err)
+	}
+	defer rows.Close()
+
+	closures := []models.Closure{}
+	for rows.Next() {
+		var closure models.Closure
+		if err := scanClosure(rows, &closure); err != nil {
+			return nil, fmt.Errorf("failed to scan closure: %w", err)
+		}
+		closures = append(closures, closure)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch closures: %w", err)
+	}
+
+	return closures, nil
+}
+
+func (r *PostgresCalendarRepository) GetClosure(id string) (*models.Closure, error) {
+	var closure models.Closure
+	err := scanClosure(r.db.QueryRow("SELECT "+closureColumns+" FROM closures WHERE id = $1", id), &closure)
+	if err == sql.ErrNoRows {
+		return nil, ErrClosureNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch closure: %w", err)
+	}
+	return &closure, nil
+}
+
+func (r *PostgresCalendarRepository) CreateClosure(closure *models.Closure) error {
+	_, err := r.db.Exec(`INSERT INTO closures (id, branch_id, name, starts_on, ends_on, annual, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		closure.ID, closure.BranchID, closure.Name, closure.StartsOn, closure.EndsOn, closure.Annual, closure.CreatedAt, closure.UpdatedAt)
+	if isForeignKeyViolation(err, closureBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create closure: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCalendarRepository) UpdateClosure(closure *models.Closure) error {
+	err := r.db.QueryRow(`UPDATE closures SET branch_id = $1, name = $2, starts_on = $3, ends_on = $4, annual = $5, updated_at = $6
+			  WHERE id = $7 RETURNING created_at`,
+		closure.BranchID, closure.Name, closure.StartsOn, closure.EndsOn, closure.Annual, closure.UpdatedAt, closure.ID).Scan(&closure.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrClosureNotFound
+	}
+	if isForeignKeyViolation(err, closureBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update closure: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresCalendarRepository) DeleteClosure(id string) error {
+	result, err := r.db.Exec("DELETE FROM closures WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete closure: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrClosureNotFound
+	}
+
+	return nil
+}
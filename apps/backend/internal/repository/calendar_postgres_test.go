@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var closureRowColumns = []string{"id", "branch_id", "name", "starts_on", "ends_on", "annual", "created_at", "updated_at"}
+
+func TestPostgresCalendarRepository_ReplaceHours(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresCalendarRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM opening_hours WHERE branch_id IS NOT DISTINCT FROM $1")).
+		WithArgs(nil).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO opening_hours (branch_id, weekday, opens, closes) VALUES ($1, $2, $3, $4)")).
+		WithArgs(nil, 1, "09:00", "17:00").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.ReplaceHours(nil, []models.OpeningHours{{Weekday: 1, Opens: "09:00", Closes: "17:00"}}))
+
+	branchID := "b1"
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM opening_hours WHERE branch_id IS NOT DISTINCT FROM $1")).
+		WithArgs("b1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO opening_hours (branch_id, weekday, opens, closes) VALUES ($1, $2, $3, $4)")).
+		WithArgs("b1", 6, "10:00", "14:00").
+		WillReturnError(&pq.Error{Code: "23503", Constraint: openingHoursBranchFKey})
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.ReplaceHours(&branchID, []models.OpeningHours{{Weekday: 6, Opens: "10:00", Closes: "14:00"}}), ErrBranchNotFound)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM opening_hours ORDER BY branch_id NULLS FIRST, weekday")).
+		WillReturnRows(sqlmock.NewRows([]string{"branch_id", "weekday", "opens", "closes"}).
+			AddRow(nil, 1, "09:00", "17:00").
+			AddRow("b1", 6, "10:00", "14:00"))
+	hours, err := repo.Hours()
+	require.NoError(t, err)
+	assert.Equal(t, []models.OpeningHours{
+		{Weekday: 1, Opens: "09:00", Closes: "17:00"},
+		{BranchID: &branchID, Weekday: 6, Opens: "10:00", Closes: "14:00"},
+	}, hours)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCalendarRepository_ListClosures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresCalendarRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM closures WHERE (annual OR (ends_on >= $1 AND starts_on <= $2)) ORDER BY starts_on, id")).
+		WithArgs("2024-04-01", "2024-06-30").
+		WillReturnRows(sqlmock.NewRows(closureRowColumns).
+			AddRow("c1", nil, "Christmas", "2023-12-25", "2023-12-26", true, now, now).
+			AddRow("c2", nil, "Easter", "2024-03-29", "2024-04-01", false, now, now))
+	closures, err := repo.ListClosures("2024-04-01", "2024-06-30", "")
+	require.NoError(t, err)
+	require.Len(t, closures, 2)
+	assert.True(t, closures[0].Annual)
+	assert.Equal(t, "2024-04-01", closures[1].EndsOn)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM closures ORDER BY starts_on, id")).
+		WillReturnRows(sqlmock.NewRows(closureRowColumns))
+	closures, err = repo.ListClosures("", "", "")
+	require.NoError(t, err)
+	assert.Empty(t, closures)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM closures WHERE (annual OR (ends_on >= $1)) AND (branch_id IS NULL OR branch_id = $2) ORDER BY starts_on, id")).
+		WithArgs("2024-04-01", "b1").
+		WillReturnRows(sqlmock.NewRows(closureRowColumns).
+			AddRow("c3", "b1", "Stocktake", "2024-11-04", "2024-11-05", false, now, now))
+	closures, err = repo.ListClosures("2024-04-01", "", "b1")
+	require.NoError(t, err)
+	require.Len(t, closures, 1)
+	require.NotNil(t, closures[0].BranchID)
+	assert.Equal(t, "b1", *closures[0].BranchID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCalendarRepository_UpdateClosure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresCalendarRepository(db)
+	createdAt := time.Now().Add(-time.Hour)
+	now := time.Now()
+	update := regexp.QuoteMeta("UPDATE closures SET branch_id = $1, name = $2")
+
+	closure := &models.Closure{ID: "c1", Name: "Stocktake", StartsOn: "2024-11-04", EndsOn: "2024-11-06", UpdatedAt: now}
+	mock.ExpectQuery(update).
+		WithArgs(nil, "Stocktake", "2024-11-04", "2024-11-06", false, now, "c1").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+	require.NoError(t, repo.UpdateClosure(closure))
+	assert.Equal(t, createdAt, closure.CreatedAt)
+
+	mock.ExpectQuery(update).WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+	assert.ErrorIs(t, repo.UpdateClosure(&models.Closure{ID: "c9"}), ErrClosureNotFound)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM closures WHERE id = $1")).WithArgs("c9").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.DeleteClosure("c9"), ErrClosureNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
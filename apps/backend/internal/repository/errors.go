@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// isUniqueViolation reports whether err is a unique constraint violation on
+// the named constraint or index.
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == uniqueViolation && pqErr.Constraint == constraint
+}
+
+const foreignKeyViolation = "23503"
+
+// isForeignKeyViolation reports whether err is a foreign key violation on
+// the named constraint.
+func isForeignKeyViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == foreignKeyViolation && pqErr.Constraint == constraint
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// dateLayout formats the days overdue fines are counted in.
+const dateLayout = "2006-01-02"
+
+var (
+	// ErrAmountExceedsBalance is returned when a payment or waiver is for
+	// more than the member owes.
+	ErrAmountExceedsBalance = errors.New("amount exceeds the balance owed")
+	// ErrAmountExceedsPayments is returned when a refund is for more than
+	// the member has paid and not had refunded.
+	ErrAmountExceedsPayments = errors.New("amount exceeds the payments made")
+)
+
+// OverdueRates decide the overdue fine of a loan. Loans carry the rates they
+// were checked out on; these apply to loans made before loan policies.
+type OverdueRates struct {
+	// DailyCents is charged for each day a copy is kept past its due date.
+	DailyCents int
+	// MaxCents caps the overdue fine of one loan; 0 leaves it uncapped.
+	MaxCents int
+}
+
+// FineRepository keeps the fines ledger of each member. Recording an entry
+// locks the member, so that two desks cannot take the same payment.
+type FineRepository interface {
+	// Record adds an entry to a member's ledger, filling in the balance
+	// after it. Payments and waivers may not exceed the balance and refunds
+	// may not exceed the payments not yet refunded. The loan of a charge
+	// must be one of the member's.
+	Record(entry *models.LedgerEntry) error
+	// List expects a normalized Limit.
+	List(memberID string, params *models.LedgerListParams) (*models.LedgerListResponse, error)
+	Balance(memberID string) (int, error)
+	// AccrueOverdue charges each loan kept past its due date, up to today or
+	// the day it was returned, whatever of its overdue fine has not been
+	// charged yet, and returns how many charges were added. Loans without
+	// rates of their own accrue at rates. Only the loans of memberID are
+	// accrued unless it is empty. today is formatted as YYYY-MM-DD and the
+	// day a loan was returned is reckoned in loc. Each loan is locked while
+	// it is charged, so that no part of a fine is charged twice.
+	AccrueOverdue(rates OverdueRates, today string, loc *time.Location, chargedAt time.Time, memberID string) (int64, error)
+	// PendingOverdue returns the overdue fines the loans of a member have
+	// run up to today that AccrueOverdue has not charged yet, without
+	// charging them.
+	PendingOverdue(rates OverdueRates, today string, loc *time.Location, memberID string) (int, error)
+}
+
+// overdueFine is the fine for a loan kept days past its due date.
+func overdueFine(rates OverdueRates, days int) int {
+	fine := days * rates.DailyCents
+	if rates.MaxCents > 0 && fine > rates.MaxCents {
+		fine = rates.MaxCents
+	}
+	return fine
+}
+
+// loanRates returns the rates a loan was checked out on, falling back to
+// rates.
+func loanRates(loan *models.Loan, rates OverdueRates) OverdueRates {
+	if loan.DailyFineCents != nil {
+		rates.DailyCents = *loan.DailyFineCents
+	}
+	if loan.MaxFineCents != nil {
+		rates.MaxCents = *loan.MaxFineCents
+	}
+	return rates
+}
+
+// daysBetween counts the days from one date to another, both formatted as
+// YYYY-MM-DD.
+func daysBetween(from, to string) (int, error) {
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return 0, err
+	}
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return 0, err
+	}
+	return int(end.Sub(start).Hours() / 24), nil
+}
+
+// ledgerEffect is how an entry changes the balance.
+func ledgerEffect(entry *models.LedgerEntry) int {
+	if entry.Kind == models.LedgerCharge || entry.Kind == models.LedgerRefund {
+		return entry.AmountCents
+	}
+	return -entry.AmountCents
+}
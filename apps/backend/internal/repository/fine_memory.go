@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MemoryFineRepository keeps the ledgers of the members of a
+// MemoryLoanRepository. The entries live in the loan repository, under its
+// lock, so that overdue fines accrue against the loans as they stand.
+type MemoryFineRepository struct {
+	loans *MemoryLoanRepository
+}
+
+func NewMemoryFineRepository(loans *MemoryLoanRepository) *MemoryFineRepository {
+	return &MemoryFineRepository{loans: loans}
+}
+
+func (r *MemoryFineRepository) Record(entry *models.LedgerEntry) error {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+
+	loans.members.mu.RLock()
+	_, ok := loans.members.members[entry.MemberID]
+	loans.members.mu.RUnlock()
+	if !ok {
+		return ErrMemberNotFound
+	}
+
+	if entry.LoanID != nil {
+		loan, ok := loans.loans[*entry.LoanID]
+		if !ok || loan.MemberID != entry.MemberID {
+			return ErrLoanNotFound
+		}
+	}
+
+	var balance, paid int
+	for _, existing := range loans.entries {
+		if existing.MemberID != entry.MemberID {
+			continue
+		}
+		balance += ledgerEffect(&existing)
+		switch existing.Kind {
+		case models.LedgerPayment:
+			paid += existing.AmountCents
+		case models.LedgerRefund:
+			paid -= existing.AmountCents
+		}
+	}
+	switch entry.Kind {
+	case models.LedgerPayment, models.LedgerWaiver:
+		if entry.AmountCents > balance {
+			return ErrAmountExceedsBalance
+		}
+	case models.LedgerRefund:
+		if entry.AmountCents > paid {
+			return ErrAmountExceedsPayments
+		}
+	}
+
+	entry.BalanceCents = balance + ledgerEffect(entry)
+	loans.entries = append(loans.entries, *entry)
+	return nil
+}
+
+func (r *MemoryFineRepository) List(memberID string, params *models.LedgerListParams) (*models.LedgerListResponse, error) {
+	r.loans.mu.RLock()
+	entries := []models.LedgerEntry{}
+	balance := 0
+	for _, entry := range r.loans.entries {
+		if entry.MemberID != memberID {
+			continue
+		}
+		balance += ledgerEffect(&entry)
+		entry.BalanceCents = balance
+		if params.Kind == "" || entry.Kind == params.Kind {
+			entries = append([]models.LedgerEntry{entry}, entries...)
+		}
+	}
+	r.loans.mu.RUnlock()
+
+	// Entries recorded together stay newest first.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+
+	response := &models.LedgerListResponse{Total: len(entries)}
+	start := params.Offset
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := start + params.Limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+	response.Data = entries[start:end]
+
+	return response, nil
+}
+
+func (r *MemoryFineRepository) Balance(memberID string) (int, error) {
+	r.loans.mu.RLock()
+	defer r.loans.mu.RUnlock()
+
+	balance := 0
+	for _, entry := range r.loans.entries {
+		if entry.MemberID == memberID {
+			balance += ledgerEffect(&entry)
+		}
+	}
+	return balance, nil
+}
+
+func (r *MemoryFineRepository) AccrueOverdue(rates OverdueRates, today string, loc *time.Location, chargedAt time.Time, memberID string) (int64, error) {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+
+	pending, err := r.pendingOverdue(rates, today, loc, memberID)
+	if err != nil {
+		return 0, err
+	}
+	for _, charge := range pending {
+		loanID, category := charge.loan.ID, models.FeeOverdue
+		loans.entries = append(loans.entries, models.LedgerEntry{
+			ID:          uuid.New().String(),
+			MemberID:    charge.loan.MemberID,
+			LoanID:      &loanID,
+			Kind:        models.LedgerCharge,
+			Category:    &category,
+			AmountCents: charge.fine,
+			CreatedAt:   chargedAt,
+		})
+	}
+	return int64(len(pending)), nil
+}
+
+func (r *MemoryFineRepository) PendingOverdue(rates OverdueRates, today string, loc *time.Location, memberID string) (int, error) {
+	r.loans.mu.RLock()
+	defer r.loans.mu.RUnlock()
+
+	pending, err := r.pendingOverdue(rates, today, loc, memberID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, charge := range pending {
+		total += charge.fine
+	}
+	return total, nil
+}
+
+// pendingCharge is the part of the overdue fine of a loan not yet charged.
+type pendingCharge struct {
+	loan models.Loan
+	fine int
+}
+
+// pendingOverdue returns the overdue fines not yet charged, ordered by
+// loan. The caller holds the loans lock.
+func (r *MemoryFineRepository) pendingOverdue(rates OverdueRates, today string, loc *time.Location, memberID string) ([]pendingCharge, error) {
+	loans := r.loans
+	charged := make(map[string]int)
+	for _, entry := range loans.entries {
+		if entry.Kind == models.LedgerCharge && *entry.Category == models.FeeOverdue && entry.LoanID != nil {
+			charged[*entry.LoanID] += entry.AmountCents
+		}
+	}
+
+	accrued := []models.Loan{}
+	for _, loan := range loans.loans {
+		if memberID == "" || loan.MemberID == memberID {
+			accrued = append(accrued, loan)
+		}
+	}
+	sort.Slice(accrued, func(i, j int) bool {
+		return accrued[i].ID < accrued[j].ID
+	})
+
+	pending := []pendingCharge{}
+	for _, loan := range accrued {
+		end := today
+		if loan.ReturnedAt != nil {
+			end = loan.ReturnedAt.In(loc).Format(dateLayout)
+		}
+		days, err := daysBetween(loan.DueOn, end)
+		if err != nil {
+			return nil, err
+		}
+		if days <= 0 {
+			continue
+		}
+
+		fine := overdueFine(loanRates(&loan, rates), days) - charged[loan.ID]
+		if fine > 0 {
+			pending = append(pending, pendingCharge{loan: loan, fine: fine})
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryEntry(memberID, kind string, amountCents int, createdAt time.Time) *models.LedgerEntry {
+	return &models.LedgerEntry{
+		ID:          uuid.New().String(),
+		MemberID:    memberID,
+		Kind:        kind,
+		AmountCents: amountCents,
+		CreatedAt:   createdAt,
+	}
+}
+
+func TestMemoryFineRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	members := NewMemoryMemberRepository()
+	loans := NewMemoryLoanRepository(items, members)
+	repo := NewMemoryFineRepository(loans)
+
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	first := newMemoryItem(book.ID, "E-1")
+	require.NoError(t, items.Create(first, HoldPickup{}))
+	second := newMemoryItem(book.ID, "E-2")
+	require.NoError(t, items.Create(second, HoldPickup{}))
+	member := newMemoryMember("Harriet", "Smith", "00000000000018", models.MembershipAdult, "2030-01-01")
+	require.NoError(t, members.Create(member))
+
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.Local)
+	kept := newMemoryLoan(first.ID, member.ID, "2024-03-10", now.AddDate(0, 0, -30))
+	require.NoError(t, loans.Checkout(kept, 5))
+	returned := newMemoryLoan(second.ID, member.ID, "2024-03-15", now.AddDate(0, 0, -30))
+	require.NoError(t, loans.Checkout(returned, 5))
+
+	t.Run("accrue overdue", func(t *testing.T) {
+		rates := OverdueRates{DailyCents: 10, MaxCents: 80}
+		added, err := repo.AccrueOverdue(rates, "2024-03-15", time.Local, now, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), added, "the kept loan is five days overdue")
+
+		_, _, err = loans.Checkin(second.ID, nil, now.AddDate(0, 0, -2), HoldPickup{Today: "2024-03-18", PickupBy: "2024-03-25"})
+		require.NoError(t, err)
+
+		pending, err := repo.PendingOverdue(rates, "2024-03-20", time.Local, member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 30+30, pending)
+
+		added, err = repo.AccrueOverdue(rates, "2024-03-20", time.Local, now, member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), added, "the kept loan is charged three more days and the returned loan three")
+
+		added, err = repo.AccrueOverdue(rates, "2024-03-20", time.Local, now, member.ID)
+		require.NoError(t, err)
+		assert.Zero(t, added)
+		pending, err = repo.PendingOverdue(rates, "2024-03-20", time.Local, member.ID)
+		require.NoError(t, err)
+		assert.Zero(t, pending)
+
+		balance, err := repo.Balance(member.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 80+30, balance)
+	})
+
+	t.Run("settle", func(t *testing.T) {
+		later := now.Add(time.Hour)
+		assert.ErrorIs(t, repo.Record(newMemoryEntry(member.ID, models.LedgerPayment, 200, later)), ErrAmountExceedsBalance)
+		assert.ErrorIs(t, repo.Record(newMemoryEntry(member.ID, models.LedgerRefund, 10, later)), ErrAmountExceedsPayments)
+		assert.ErrorIs(t, repo.Record(newMemoryEntry(uuid.New().String(), models.LedgerPayment, 10, later)), ErrMemberNotFound)
+
+		payment := newMemoryEntry(member.ID, models.LedgerPayment, 60, later)
+		require.NoError(t, repo.Record(payment))
+		assert.Equal(t, 50, payment.BalanceCents)
+		refund := newMemoryEntry(member.ID, models.LedgerRefund, 20, later)
+		require.NoError(t, repo.Record(refund))
+		assert.Equal(t, 70, refund.BalanceCents)
+
+		ledger, err := repo.List(member.ID, &models.LedgerListParams{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 5, ledger.Total)
+		require.Len(t, ledger.Data, 2)
+		assert.Equal(t, refund.ID, ledger.Data[0].ID)
+		assert.Equal(t, 70, ledger.Data[0].BalanceCents)
+		assert.Equal(t, 50, ledger.Data[1].BalanceCents)
+
+		charges, err := repo.List(member.ID, &models.LedgerListParams{Kind: models.LedgerCharge, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 3, charges.Total)
+	})
+
+	t.Run("history blocks deletion", func(t *testing.T) {
+		other := newMemoryMember("Jane", "Fairfax", "00000000000026", models.MembershipAdult, "2030-01-01")
+		require.NoError(t, members.Create(other))
+		category := models.FeeProcessing
+		charge := newMemoryEntry(other.ID, models.LedgerCharge, 150, now)
+		charge.Category = &category
+		require.NoError(t, repo.Record(charge))
+
+		hasLoans, err := loans.HasLoans(other.ID)
+		require.NoError(t, err)
+		assert.True(t, hasLoans)
+	})
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ledgerMemberFKey = "ledger_entries_member_id_fkey"
+
+	// ledgerEffectExpr is how an entry e changes the balance.
+	ledgerEffectExpr = "CASE WHEN e.kind IN ('charge', 'refund') THEN e.amount_cents ELSE -e.amount_cents END"
+	ledgerColumns    = "id, member_id, loan_id, kind, category, amount_cents, balance_cents, note, created_at"
+)
+
+type PostgresFineRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFineRepository(db *sql.DB) *PostgresFineRepository {
+	return &PostgresFineRepository{db: db}
+}
+
+func scanLedgerEntry(scanner interface{ Scan(...interface{}) error }, entry *models.LedgerEntry, extra ...interface{}) error {
+	dest := []interface{}{&entry.ID, &entry.MemberID, &entry.LoanID, &entry.Kind, &entry.Category, &entry.AmountCents,
+		&entry.BalanceCents, &entry.Note, &entry.CreatedAt}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+func (r *PostgresFineRepository) Record(entry *models.LedgerEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked string
+	err = tx.QueryRow("SELECT id FROM members WHERE id = $1 FOR UPDATE", entry.MemberID).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock member: %w", err)
+	}
+
+	if entry.LoanID != nil {
+		var borrower string
+		err = tx.QueryRow("SELECT member_id FROM loans WHERE id = $1", *entry.LoanID).Scan(&borrower)
+		if err == sql.ErrNoRows || err == nil && borrower != entry.MemberID {
+			return ErrLoanNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch loan: %w", err)
+		}
+	}
+
+	var balance, paid int
+	err = tx.QueryRow(`SELECT COALESCE(SUM(`+ledgerEffectExpr+`), 0),
+			  COALESCE(SUM(CASE e.kind WHEN 'payment' THEN e.amount_cents WHEN 'refund' THEN -e.amount_cents ELSE 0 END), 0)
+			  FROM ledger_entries e WHERE e.member_id = $1`, entry.MemberID).Scan(&balance, &paid)
+	if err != nil {
+		return fmt.Errorf("failed to fetch balance: %w", err)
+	}
+	switch entry.Kind {
+	case models.LedgerPayment, models.LedgerWaiver:
+		if entry.AmountCents > balance {
+			return ErrAmountExceedsBalance
+		}
+	case models.LedgerRefund:
+		if entry.AmountCents > paid {
+			return ErrAmountExceedsPayments
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO ledger_entries (id, member_id, loan_id, kind, category, amount_cents, note, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		entry.ID, entry.MemberID, entry.LoanID, entry.Kind, entry.Category, entry.AmountCents, entry.Note, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record ledger entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit ledger entry: %w", err)
+	}
+	entry.BalanceCents = balance + ledgerEffect(entry)
+	return nil
+}
+
+func (r *PostgresFineRepository) List(memberID string, params *models.LedgerListParams) (*models.LedgerListResponse, error) {
+	conditions := []string{}
+	args := []interface{}{memberID}
+	if params.Kind != "" {
+		args = append(args, params.Kind)
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`SELECT %s, COUNT(*) OVER () AS total
+			  FROM (SELECT e.*, SUM(%s) OVER (ORDER BY e.created_at, e.id) AS balance_cents
+			  FROM ledger_entries e WHERE e.member_id = $1) ledger%s
+			  ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		ledgerColumns, ledgerEffectExpr, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.LedgerListResponse{Data: make([]models.LedgerEntry, 0, params.Limit)}
+	for rows.Next() {
+		var entry models.LedgerEntry
+		if err := scanLedgerEntry(rows, &entry, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry: %w", err)
+		}
+		response.Data = append(response.Data, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresFineRepository) Balance(memberID string) (int, error) {
+	var balance int
+	err := r.db.QueryRow("SELECT COALESCE(SUM("+ledgerEffectExpr+"), 0) FROM ledger_entries e WHERE e.member_id = $1", memberID).
+		Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch balance: %w", err)
+	}
+	return balance, nil
+}
+
+// AccrueOverdue locks the loans it charges rather than the ledger, so
+// that payments and checkouts go on while overdue fines accrue.
+func (r *PostgresFineRepository) AccrueOverdue(rates OverdueRates, today string, loc *time.Location, chargedAt time.Time, memberID string) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args := overdueQuery("SELECT l.id", rates, today, loc, memberID)
+	rows, err := tx.Query(query+" ORDER BY l.id FOR UPDATE OF l", args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock overdue loans: %w", err)
+	}
+	var loanIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan overdue loan: %w", err)
+		}
+		loanIDs = append(loanIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to lock overdue loans: %w", err)
+	}
+	if len(loanIDs) == 0 {
+		return 0, nil
+	}
+
+	// The charges are worked out again now that the loans are locked, so
+	// that what another accrual charged meanwhile is not charged twice.
+	query, args = overdueQuery(`INSERT INTO ledger_entries (member_id, loan_id, kind, category, amount_cents, created_at)
+			  SELECT l.member_id, l.id, 'charge', 'overdue', f.fine - COALESCE(c.charged, 0), $5`, rates, today, loc, "")
+	args = append(args, chargedAt, pq.Array(loanIDs))
+	result, err := tx.Exec(query+" AND l.id = ANY($6::uuid[])", args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to accrue overdue fines: %w", err)
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count overdue fines: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit overdue fines: %w", err)
+	}
+	return added, nil
+}
+
+func (r *PostgresFineRepository) PendingOverdue(rates OverdueRates, today string, loc *time.Location, memberID string) (int, error) {
+	query, args := overdueQuery("SELECT COALESCE(SUM(f.fine - COALESCE(c.charged, 0)), 0)", rates, today, loc, memberID)
+	var pending int
+	if err := r.db.QueryRow(query, args...).Scan(&pending); err != nil {
+		return 0, fmt.Errorf("failed to fetch pending overdue fines: %w", err)
+	}
+	return pending, nil
+}
+
+// overdueQuery completes a query over the loans l with overdue fines f
+// not yet fully charged c, and returns it with its first four arguments
+// and, unless memberID is empty, the fifth. The day a loan was returned is
+// reckoned in loc.
+func overdueQuery(selection string, rates OverdueRates, today string, loc *time.Location, memberID string) (string, []interface{}) {
+	query := selection + `
+			  FROM loans l
+			  CROSS JOIN LATERAL (SELECT COALESCE((l.returned_at AT TIME ZONE $4::text)::date, $1::date) - l.due_on AS days) d
+			  CROSS JOIN LATERAL (SELECT LEAST(d.days * COALESCE(l.daily_fine_cents, $2),
+			  NULLIF(COALESCE(l.max_fine_cents, $3), 0)) AS fine) f
+			  LEFT JOIN LATERAL (SELECT SUM(amount_cents) AS charged FROM ledger_entries
+			  WHERE loan_id = l.id AND category = 'overdue') c ON TRUE
+			  WHERE d.days > 0 AND f.fine > COALESCE(c.charged, 0)`
+	args := []interface{}{today, rates.DailyCents, rates.MaxCents, zoneName(loc)}
+	if memberID != "" {
+		query += " AND l.member_id = $5"
+		args = append(args, memberID)
+	}
+	return query, args
+}
+
+// zoneName returns the IANA name of loc for the database. The server's own
+// zone is named Local, which the database does not know, so its name is
+// looked up from TZ or /etc/localtime, falling back to UTC.
+func zoneName(loc *time.Location) string {
+	if loc != time.Local {
+		return loc.String()
+	}
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		if tz = strings.TrimPrefix(tz, ":"); tz != "" {
+			return tz
+		}
+		return "UTC"
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if i := strings.LastIndex(target, "zoneinfo/"); i >= 0 {
+			return target[i+len("zoneinfo/"):]
+		}
+	}
+	return "UTC"
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ledgerRowColumns = []string{"id", "member_id", "loan_id", "kind", "category", "amount_cents", "balance_cents",
+	"note", "created_at", "total"}
+
+func TestPostgresFineRepository_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresFineRepository(db)
+	now := time.Now()
+	lockMember := regexp.QuoteMeta("SELECT id FROM members WHERE id = $1 FOR UPDATE")
+	sumLedger := regexp.QuoteMeta("FROM ledger_entries e WHERE e.member_id = $1")
+
+	t.Run("success", func(t *testing.T) {
+		entry := &models.LedgerEntry{ID: "e1", MemberID: "m1", Kind: models.LedgerPayment, AmountCents: 300, CreatedAt: now}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockMember).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("m1"))
+		mock.ExpectQuery(sumLedger).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"balance", "paid"}).AddRow(500, 0))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO ledger_entries (id, member_id, loan_id, kind, category, amount_cents, note, created_at)")).
+			WithArgs("e1", "m1", nil, models.LedgerPayment, nil, 300, nil, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Record(entry))
+		assert.Equal(t, 200, entry.BalanceCents)
+	})
+
+	t.Run("refund exceeds payments", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockMember).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("m1"))
+		mock.ExpectQuery(sumLedger).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"balance", "paid"}).AddRow(0, 100))
+		mock.ExpectRollback()
+
+		err := repo.Record(&models.LedgerEntry{MemberID: "m1", Kind: models.LedgerRefund, AmountCents: 150})
+		assert.ErrorIs(t, err, ErrAmountExceedsPayments)
+	})
+
+	t.Run("loan of another member", func(t *testing.T) {
+		loanID, category := "l1", models.FeeLostItem
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockMember).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("m1"))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT member_id FROM loans WHERE id = $1")).WithArgs("l1").
+			WillReturnRows(sqlmock.NewRows([]string{"member_id"}).AddRow("m2"))
+		mock.ExpectRollback()
+
+		err := repo.Record(&models.LedgerEntry{MemberID: "m1", LoanID: &loanID, Kind: models.LedgerCharge, Category: &category, AmountCents: 2500})
+		assert.ErrorIs(t, err, ErrLoanNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFineRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresFineRepository(db)
+	now := time.Now()
+	category := models.FeeOverdue
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM ledger_entries e WHERE e.member_id = $1) ledger WHERE kind = $2 ORDER BY created_at DESC, id DESC LIMIT $3 OFFSET $4")).
+		WithArgs("m1", models.LedgerCharge, 20, 0).
+		WillReturnRows(sqlmock.NewRows(ledgerRowColumns).
+			AddRow("e1", "m1", "l1", models.LedgerCharge, category, 75, 75, nil, now, 1))
+
+	response, err := repo.List("m1", &models.LedgerListParams{Kind: models.LedgerCharge, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, "l1", *response.Data[0].LoanID)
+	assert.Equal(t, 75, response.Data[0].BalanceCents)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFineRepository_AccrueOverdue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresFineRepository(db)
+	now := time.Now()
+	rates := OverdueRates{DailyCents: 25, MaxCents: 1000}
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+	lockLoans := regexp.QuoteMeta("SELECT l.id") + ".*" + regexp.QuoteMeta("(l.returned_at AT TIME ZONE $4::text)::date") +
+		".*" + regexp.QuoteMeta("AND l.member_id = $5 ORDER BY l.id FOR UPDATE OF l")
+
+	t.Run("charges the locked loans", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockLoans).
+			WithArgs("2024-03-20", 25, 1000, "Europe/London", "m1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("l1").AddRow("l2"))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO ledger_entries (member_id, loan_id, kind, category, amount_cents, created_at)")+
+			".*"+regexp.QuoteMeta("AND l.id = ANY($6::uuid[])")).
+			WithArgs("2024-03-20", 25, 1000, "Europe/London", now, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		added, err := repo.AccrueOverdue(rates, "2024-03-20", london, now, "m1")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), added)
+	})
+
+	t.Run("nothing to charge", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockLoans).
+			WithArgs("2024-03-20", 25, 1000, "Europe/London", "m1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		added, err := repo.AccrueOverdue(rates, "2024-03-20", london, now, "m1")
+		require.NoError(t, err)
+		assert.Zero(t, added)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFineRepository_PendingOverdue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresFineRepository(db)
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(f.fine - COALESCE(c.charged, 0)), 0)")+".*"+regexp.QuoteMeta("AND l.member_id = $5")).
+		WithArgs("2024-03-20", 25, 1000, "Europe/London", "m1").
+		WillReturnRows(sqlmock.NewRows([]string{"pending"}).AddRow(150))
+
+	pending, err := repo.PendingOverdue(OverdueRates{DailyCents: 25, MaxCents: 1000}, "2024-03-20", london, "m1")
+	require.NoError(t, err)
+	assert.Equal(t, 150, pending)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"errors"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrFundNotFound = errors.New("fund not found")
+	// ErrDuplicateFundCode is returned when a fund would get the code of
+	// another fund of the same fiscal year.
+	ErrDuplicateFundCode = errors.New("a fund with this code already exists for this fiscal year")
+	// ErrFundInUse is returned when deleting a fund that purchase order
+	// lines are paid from.
+	ErrFundInUse = errors.New("fund has purchase order lines")
+)
+
+// FundRepository persists the budgets purchases are paid from. The funds it
+// returns carry what their purchase orders have committed and spent.
+type FundRepository interface {
+	// List returns the funds of a fiscal year, or every fund when
+	// fiscalYear is zero, ordered by fiscal year, newest first, and code.
+	List(fiscalYear int) ([]models.Fund, error)
+	GetByID(id string) (*models.Fund, error)
+	Create(fund *models.Fund) error
+	// Update writes the code, name, fiscal year and budget of a fund.
+	Update(fund *models.Fund) error
+	Delete(id string) error
+}
+
+// fundSpending fills in what a fund has committed and spent, and what it
+// has left.
+func fundSpending(fund *models.Fund, encumbered, spent int) {
+	fund.EncumberedCents = encumbered
+	fund.SpentCents = spent
+	fund.AvailableCents = fund.BudgetCents - encumbered - spent
+}
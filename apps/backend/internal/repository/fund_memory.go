@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryFundRepository keeps funds in a map guarded by a RWMutex. It sums
+// the spending of its funds, and checks deletes, against the orders of the
+// MemoryPurchaseOrderRepository sharing it, whose lock is always taken
+// after its own.
+type MemoryFundRepository struct {
+	mu     sync.RWMutex
+	orders *MemoryPurchaseOrderRepository
+	funds  map[string]models.Fund
+}
+
+func NewMemoryFundRepository() *MemoryFundRepository {
+	return &MemoryFundRepository{
+		funds: make(map[string]models.Fund),
+	}
+}
+
+func (r *MemoryFundRepository) List(fiscalYear int) ([]models.Fund, error) {
+	r.mu.RLock()
+	funds := []models.Fund{}
+	for _, fund := range r.funds {
+		if fiscalYear == 0 || fund.FiscalYear == fiscalYear {
+			funds = append(funds, r.withSpending(fund))
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(funds, func(i, j int) bool {
+		if funds[i].FiscalYear != funds[j].FiscalYear {
+			return funds[i].FiscalYear > funds[j].FiscalYear
+		}
+		return funds[i].Code < funds[j].Code
+	})
+	return funds, nil
+}
+
+func (r *MemoryFundRepository) GetByID(id string) (*models.Fund, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fund, ok := r.funds[id]
+	if !ok {
+		return nil, ErrFundNotFound
+	}
+	fund = r.withSpending(fund)
+	return &fund, nil
+}
+
+func (r *MemoryFundRepository) Create(fund *models.Fund) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasCode(fund) {
+		return ErrDuplicateFundCode
+	}
+	r.funds[fund.ID] = *fund
+	return nil
+}
+
+func (r *MemoryFundRepository) Update(fund *models.Fund) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.funds[fund.ID]
+	if !ok {
+		return ErrFundNotFound
+	}
+	if r.hasCode(fund) {
+		return ErrDuplicateFundCode
+	}
+
+	existing.Code = fund.Code
+	existing.Name = fund.Name
+	existing.FiscalYear = fund.FiscalYear
+	existing.BudgetCents = fund.BudgetCents
+	existing.UpdatedAt = fund.UpdatedAt
+	r.funds[fund.ID] = existing
+	return nil
+}
+
+func (r *MemoryFundRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.funds[id]; !ok {
+		return ErrFundNotFound
+	}
+	if r.orders != nil && r.orders.hasFund(id) {
+		return ErrFundInUse
+	}
+
+	delete(r.funds, id)
+	return nil
+}
+
+// hasCode reports whether another fund of the same fiscal year has the
+// code of fund. Callers must hold the lock.
+func (r *MemoryFundRepository) hasCode(fund *models.Fund) bool {
+	for _, other := range r.funds {
+		if other.Code == fund.Code && other.FiscalYear == fund.FiscalYear && other.ID != fund.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// withSpending returns a fund with its spending filled in. Callers must
+// hold the lock.
+func (r *MemoryFundRepository) withSpending(fund models.Fund) models.Fund {
+	var encumbered, spent int
+	if r.orders != nil {
+		r.orders.mu.RLock()
+		encumbered, spent = r.orders.fundSpending(fund.ID)
+		r.orders.mu.RUnlock()
+	}
+	fundSpending(&fund, encumbered, spent)
+	return fund
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryFund(code string, fiscalYear, budgetCents int) *models.Fund {
+	return &models.Fund{
+		ID:          uuid.New().String(),
+		Code:        code,
+		Name:        code + " fund",
+		FiscalYear:  fiscalYear,
+		BudgetCents: budgetCents,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func TestMemoryFundRepository(t *testing.T) {
+	repo := NewMemoryFundRepository()
+
+	adult := newMemoryFund("ADULT", 2026, 50000)
+	require.NoError(t, repo.Create(adult))
+	require.NoError(t, repo.Create(newMemoryFund("ADULT", 2025, 40000)))
+	children := newMemoryFund("CHILD", 2026, 20000)
+	require.NoError(t, repo.Create(children))
+	assert.ErrorIs(t, repo.Create(newMemoryFund("ADULT", 2026, 100)), ErrDuplicateFundCode)
+
+	funds, err := repo.List(0)
+	require.NoError(t, err)
+	require.Len(t, funds, 3)
+	assert.Equal(t, []string{"ADULT", "CHILD", "ADULT"}, []string{funds[0].Code, funds[1].Code, funds[2].Code})
+	assert.Equal(t, 2025, funds[2].FiscalYear)
+	funds, err = repo.List(2026)
+	require.NoError(t, err)
+	require.Len(t, funds, 2)
+	assert.Equal(t, 50000, funds[0].AvailableCents)
+
+	children.Code = "ADULT"
+	assert.ErrorIs(t, repo.Update(children), ErrDuplicateFundCode)
+	children.Code = "CHILD"
+	children.BudgetCents = 25000
+	require.NoError(t, repo.Update(children))
+	stored, err := repo.GetByID(children.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 25000, stored.BudgetCents)
+	assert.Equal(t, 25000, stored.AvailableCents)
+	assert.ErrorIs(t, repo.Update(newMemoryFund("TEEN", 2026, 0)), ErrFundNotFound)
+
+	require.NoError(t, repo.Delete(children.ID))
+	assert.ErrorIs(t, repo.Delete(children.ID), ErrFundNotFound)
+	_, err = repo.GetByID(children.ID)
+	assert.ErrorIs(t, err, ErrFundNotFound)
+}
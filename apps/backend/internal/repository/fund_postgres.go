@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	fundYearCodeIndex = "idx_funds_year_code"
+	orderLineFundFKey = "purchase_order_lines_fund_id_fkey"
+
+	// fundColumns sums the spending of the funds selected from fundFrom,
+	// grouped by fund: the copies still to come on placed orders are
+	// committed, and every copy received is spent, whatever became of its
+	// order.
+	fundColumns = `f.id, f.code, f.name, f.fiscal_year, f.budget_cents,
+			  COALESCE(SUM((l.quantity - l.received_quantity) * l.unit_price_cents)
+			  FILTER (WHERE o.status IN ('ordered', 'partially_received')), 0) AS encumbered_cents,
+			  COALESCE(SUM(l.received_quantity * l.unit_price_cents), 0) AS spent_cents,
+			  f.created_at, f.updated_at`
+	fundFrom = ` FROM funds f LEFT JOIN purchase_order_lines l ON l.fund_id = f.id
+			  LEFT JOIN purchase_orders o ON o.id = l.order_id`
+)
+
+type PostgresFundRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFundRepository(db *sql.DB) *PostgresFundRepository {
+	return &PostgresFundRepository{db: db}
+}
+
+func scanFund(scanner interface{ Scan(...interface{}) error }, fund *models.Fund) error {
+	var encumbered, spent int
+	err := scanner.Scan(&fund.ID, &fund.Code, &fund.Name, &fund.FiscalYear, &fund.BudgetCents, &encumbered, &spent,
+		&fund.CreatedAt, &fund.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	fundSpending(fund, encumbered, spent)
+	return nil
+}
+
+func (r *PostgresFundRepository) List(fiscalYear int) ([]models.Fund, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if fiscalYear != 0 {
+		args = append(args, fiscalYear)
+		conditions = append(conditions, "f.fiscal_year = $1")
+	}
+
+	return queryFunds(r.db, "SELECT "+fundColumns+fundFrom+whereClause(conditions)+
+		" GROUP BY f.id ORDER BY f.fiscal_year DESC, f.code", args...)
+}
+
+func (r *PostgresFundRepository) GetByID(id string) (*models.Fund, error) {
+	var fund models.Fund
+	err := scanFund(r.db.QueryRow("SELECT "+fundColumns+fundFrom+" WHERE f.id = $1 GROUP BY f.id", id), &fund)
+	if err == sql.ErrNoRows {
+		return nil, ErrFundNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fund: %w", err)
+	}
+
+	return &fund, nil
+}
+
+func (r *PostgresFundRepository) Create(fund *models.Fund) error {
+	_, err := r.db.Exec(`INSERT INTO funds (id, code, name, fiscal_year, budget_cents, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		fund.ID, fund.Code, fund.Name, fund.FiscalYear, fund.BudgetCents, fund.CreatedAt, fund.UpdatedAt)
+	if isUniqueViolation(err, fundYearCodeIndex) {
+		return ErrDuplicateFundCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create fund: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresFundRepository) Update(fund *models.Fund) error {
+	result, err := r.db.Exec(`UPDATE funds SET code = $1, name = $2, fiscal_year = $3, budget_cents = $4, updated_at = $5
+			  WHERE id = $6`,
+		fund.Code, fund.Name, fund.FiscalYear, fund.BudgetCents, fund.UpdatedAt, fund.ID)
+	if isUniqueViolation(err, fundYearCodeIndex) {
+		return ErrDuplicateFundCode
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update fund: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrFundNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresFundRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM funds WHERE id = $1", id)
+	if isForeignKeyViolation(err, orderLineFundFKey) {
+		return ErrFundInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete fund: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrFundNotFound
+	}
+
+	return nil
+}
+
+// queryFunds runs a query selecting fundColumns through db or a
+// transaction.
+func queryFunds(q queryer, query string, args ...interface{}) ([]models.Fund, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch funds: %w", err)
+	}
+	defer rows.Close()
+
+	funds := []models.Fund{}
+	for rows.Next() {
+		var fund models.Fund
+		if err := scanFund(rows, &fund); err != nil {
+			return nil, fmt.Errorf("failed to scan fund: %w", err)
+		}
+		funds = append(funds, fund)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch funds: %w", err)
+	}
+
+	return funds, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fundRowColumns = []string{"id", "code", "name", "fiscal_year", "budget_cents", "encumbered_cents", "spent_cents",
+	"created_at", "updated_at"}
+
+func TestPostgresFundRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresFundRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE f.fiscal_year = $1 GROUP BY f.id ORDER BY f.fiscal_year DESC, f.code")).
+		WithArgs(2026).
+		WillReturnRows(sqlmock.NewRows(fundRowColumns).
+			AddRow("f1", "ADULT", "Adult fiction", 2026, 10000, 1500, 3000, now, now).
+			AddRow("f2", "CHILD", "Children", 2026, 1000, 800, 400, now, now))
+
+	funds, err := repo.List(2026)
+	require.NoError(t, err)
+	require.Len(t, funds, 2)
+	assert.Equal(t, 5500, funds[0].AvailableCents)
+	assert.Equal(t, -200, funds[1].AvailableCents)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresFundRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresFundRepository(db)
+	del := regexp.QuoteMeta("DELETE FROM funds WHERE id = $1")
+
+	mock.ExpectExec(del).WithArgs("f1").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.Delete("f1"))
+
+	mock.ExpectExec(del).
+		WithArgs("f2").
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "purchase_order_lines_fund_id_fkey"})
+	assert.ErrorIs(t, repo.Delete("f2"), ErrFundInUse)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
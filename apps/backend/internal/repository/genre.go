@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrGenreNotFound = errors.New("genre not found")
+	// ErrDuplicateGenre is returned when a genre name or alias has the same
+	// key as the name or an alias of another genre.
+	ErrDuplicateGenre = errors.New("a genre with this name already exists")
+	// ErrGenreInUse is returned when deleting a genre that still has books or
+	// subgenres.
+	ErrGenreInUse = errors.New("genre has books or subgenres")
+	// ErrGenreCycle is returned when a genre would become its own ancestor,
+	// or be merged into itself or one of its subgenres.
+	ErrGenreCycle = errors.New("a genre cannot be moved under itself or its subgenres")
+	// ErrUnknownGenre is returned when a genre referenced by a write, such as
+	// a parent or a merge target, does not exist.
+	ErrUnknownGenre = errors.New("unknown genre")
+)
+
+// GenreRepository persists the genre taxonomy and the genres of books.
+// Writes return ErrUnknownGenre when a parent, merge target or book genre
+// does not exist, and ErrGenreNotFound only for the genre being written.
+type GenreRepository interface {
+	// List returns every genre with its aliases, ordered by name.
+	List() ([]models.Genre, error)
+	GetByID(id string) (*models.Genre, error)
+	Create(genre *models.Genre) error
+	// Update writes the name, parent and aliases of a genre and renames it in
+	// the genre field of the books it is the primary genre of.
+	Update(genre *models.Genre) error
+	Delete(id string) error
+	// Merge moves the books, subgenres and aliases of a genre to another one,
+	// keeps its name as an alias of the target and deletes it.
+	Merge(sourceID, targetID string, mergedAt time.Time) error
+	// Subtree returns the IDs of the genre with a name or alias and of all of
+	// its descendants, or none when no genre has it.
+	Subtree(name string) ([]string, error)
+	// BookGenres lists the genres of a live book by position.
+	BookGenres(bookID string) ([]models.Genre, error)
+	// ReplaceBookGenres sets the genres of a book and its genre field in one
+	// write. Like BookRepository.Update it expects the book to be at version
+	// and bumps it.
+	ReplaceBookGenres(bookID string, version int, genreIDs []string, genre *string, updatedAt time.Time) error
+	// LinkGenre makes genre the only genre of a book, matching it by name or
+	// alias and creating it as a top-level genre when none matches; its ID is
+	// filled in. A nil genre removes every genre of the book.
+	LinkGenre(bookID string, genre *models.Genre) error
+}
+
+// genreNameKey mirrors the genre_name_key SQL function for repositories
+// without a database.
+func genreNameKey(name string) string {
+	var key strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			key.WriteRune(r)
+		}
+	}
+	return key.String()
+}
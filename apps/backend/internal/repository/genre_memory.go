@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryGenreRepository keeps genres in a map guarded by a RWMutex. The genres
+// of books live in the MemoryBookRepository it shares, whose lock is always
+// taken after its own.
+type MemoryGenreRepository struct {
+	mu     sync.RWMutex
+	books  *MemoryBookRepository
+	genres map[string]models.Genre
+	// policies is written by the MemoryPolicyRepository sharing this
+	// repository, so that genre rules follow deletes and merges.
+	policies map[string]models.LoanPolicy
+}
+
+func NewMemoryGenreRepository(books *MemoryBookRepository) *MemoryGenreRepository {
+	return &MemoryGenreRepository{
+		books:    books,
+		genres:   make(map[string]models.Genre),
+		policies: make(map[string]models.LoanPolicy),
+	}
+}
+
+func (r *MemoryGenreRepository) List() ([]models.Genre, error) {
+	r.mu.RLock()
+	genres := make([]models.Genre, 0, len(r.genres))
+	for _, genre := range r.genres {
+		genres = append(genres, copyGenre(genre))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(genres, func(i, j int) bool {
+		if genres[i].Name != genres[j].Name {
+			return genres[i].Name < genres[j].Name
+		}
+		return genres[i].ID < genres[j].ID
+	})
+	return genres, nil
+}
+
+func (r *MemoryGenreRepository) GetByID(id string) (*models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	genre, ok := r.genres[id]
+	if !ok {
+		return nil, ErrGenreNotFound
+	}
+	genre = copyGenre(genre)
+	return &genre, nil
+}
+
+func (r *MemoryGenreRepository) Create(genre *models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.keysTaken(genre) {
+		return ErrDuplicateGenre
+	}
+	if genre.ParentID != nil {
+		if _, ok := r.genres[*genre.ParentID]; !ok {
+			return ErrUnknownGenre
+		}
+	}
+
+	r.genres[genre.ID] = storedGenre(genre)
+	return nil
+}
+
+func (r *MemoryGenreRepository) Update(genre *models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.genres[genre.ID]
+	if !ok {
+		return ErrGenreNotFound
+	}
+	if r.keysTaken(genre) {
+		return ErrDuplicateGenre
+	}
+	if genre.ParentID != nil {
+		if _, ok := r.genres[*genre.ParentID]; !ok {
+			return ErrUnknownGenre
+		}
+		if r.isAncestor(genre.ID, *genre.ParentID) {
+			return ErrGenreCycle
+		}
+	}
+
+	updated := storedGenre(genre)
+	updated.CreatedAt = existing.CreatedAt
+	r.genres[genre.ID] = updated
+
+	r.books.mu.Lock()
+	defer r.books.mu.Unlock()
+	r.refreshPrimaryGenre(genre.ID, genre.UpdatedAt)
+	return nil
+}
+
+func (r *MemoryGenreRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.genres[id]; !ok {
+		return ErrGenreNotFound
+	}
+	for _, genre := range r.genres {
+		if genre.ParentID != nil && *genre.ParentID == id {
+			return ErrGenreInUse
+		}
+	}
+
+	r.books.mu.RLock()
+	defer r.books.mu.RUnlock()
+	for _, genres := range r.books.genres {
+		if hasAnyGenre(genres, []string{id}) {
+			return ErrGenreInUse
+		}
+	}
+
+	delete(r.genres, id)
+	for policyID, policy := range r.policies {
+		if policy.GenreID != nil && *policy.GenreID == id {
+			delete(r.policies, policyID)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryGenreRepository) Merge(sourceID, targetID string, mergedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	source, ok := r.genres[sourceID]
+	if !ok {
+		return ErrGenreNotFound
+	}
+	target, ok := r.genres[targetID]
+	if !ok {
+		return ErrUnknownGenre
+	}
+	if r.isAncestor(sourceID, targetID) {
+		return ErrGenreCycle
+	}
+
+	for id, genre := range r.genres {
+		if genre.ParentID != nil && *genre.ParentID == sourceID {
+			genre.ParentID = &targetID
+			r.genres[id] = genre
+		}
+	}
+	target.Aliases = append(append(target.Aliases, source.Aliases...), source.Name)
+	sort.Strings(target.Aliases)
+	r.genres[targetID] = target
+	delete(r.genres, sourceID)
+	r.mergePolicies(sourceID, targetID, mergedAt)
+
+	r.books.mu.Lock()
+	defer r.books.mu.Unlock()
+	for bookID, genres := range r.books.genres {
+		if !hasAnyGenre(genres, []string{sourceID}) {
+			continue
+		}
+		// Replacing the source in place and dropping repeats keeps the
+		// earlier position of a book that had both genres.
+		merged := make([]string, 0, len(genres))
+		for _, id := range genres {
+			if id == sourceID {
+				id = targetID
+			}
+			if !hasAnyGenre(merged, []string{id}) {
+				merged = append(merged, id)
+			}
+		}
+		r.books.genres[bookID] = merged
+	}
+	r.refreshPrimaryGenre(targetID, mergedAt)
+	return nil
+}
+
+// mergePolicies moves the rules of a merged genre to its target, dropping
+// those for a branch and membership type the target already has a rule for. Callers
+// must hold the lock.
+func (r *MemoryGenreRepository) mergePolicies(sourceID, targetID string, mergedAt time.Time) {
+	for id, policy := range r.policies {
+		if policy.GenreID == nil || *policy.GenreID != sourceID {
+			continue
+		}
+		delete(r.policies, id)
+		policy.GenreID = &targetID
+		policy.UpdatedAt = mergedAt
+		duplicate := false
+		for _, other := range r.policies {
+			duplicate = duplicate || samePolicyMatch(&other, &policy)
+		}
+		if !duplicate {
+			r.policies[id] = policy
+		}
+	}
+}
+
+func (r *MemoryGenreRepository) Subtree(name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := []string{}
+	root := r.findByKey(genreNameKey(name))
+	if root == nil {
+		return ids, nil
+	}
+
+	ids = append(ids, root.ID)
+	for i := 0; i < len(ids); i++ {
+		for _, genre := range r.genres {
+			if genre.ParentID != nil && *genre.ParentID == ids[i] {
+				ids = append(ids, genre.ID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (r *MemoryGenreRepository) BookGenres(bookID string) ([]models.Genre, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, err := r.books.GetByID(bookID); err != nil {
+		return nil, err
+	}
+
+	r.books.mu.RLock()
+	defer r.books.mu.RUnlock()
+	genres := make([]models.Genre, 0, len(r.books.genres[bookID]))
+	for _, id := range r.books.genres[bookID] {
+		genres = append(genres, copyGenre(r.genres[id]))
+	}
+	return genres, nil
+}
+
+func (r *MemoryGenreRepository) ReplaceBookGenres(bookID string, version int, genreIDs []string, genre *string, updatedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range genreIDs {
+		if _, ok := r.genres[id]; !ok {
+			return ErrUnknownGenre
+		}
+	}
+
+	r.books.mu.Lock()
+	defer r.books.mu.Unlock()
+
+	book, ok := r.books.books[bookID]
+	if !ok || book.DeletedAt != nil {
+		return ErrBookNotFound
+	}
+	if book.Version != version {
+		return ErrVersionConflict
+	}
+
+	book.Genre = genre
+	book.UpdatedAt = updatedAt
+	book.Version++
+	r.books.books[bookID] = book
+	r.books.genres[bookID] = append([]string(nil), genreIDs...)
+	return nil
+}
+
+func (r *MemoryGenreRepository) LinkGenre(bookID string, genre *models.Genre) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.books.mu.Lock()
+	defer r.books.mu.Unlock()
+
+	if _, ok := r.books.books[bookID]; !ok {
+		return ErrBookNotFound
+	}
+	if genre == nil || genreNameKey(genre.Name) == "" {
+		delete(r.books.genres, bookID)
+		return nil
+	}
+
+	if existing := r.findByKey(genreNameKey(genre.Name)); existing != nil {
+		genre.ID = existing.ID
+	} else {
+		r.genres[genre.ID] = storedGenre(genre)
+	}
+	r.books.genres[bookID] = []string{genre.ID}
+	return nil
+}
+
+// findByKey returns the genre with a name or alias key. Callers must hold
+// the lock.
+func (r *MemoryGenreRepository) findByKey(key string) *models.Genre {
+	for _, genre := range r.genres {
+		if genreNameKey(genre.Name) == key {
+			return &genre
+		}
+		for _, alias := range genre.Aliases {
+			if genreNameKey(alias) == key {
+				return &genre
+			}
+		}
+	}
+	return nil
+}
+
+// keysTaken reports whether the name or an alias of genre belongs to another
+// genre. Callers must hold the lock.
+func (r *MemoryGenreRepository) keysTaken(genre *models.Genre) bool {
+	for _, name := range append([]string{genre.Name}, genre.Aliases...) {
+		if other := r.findByKey(genreNameKey(name)); other != nil && other.ID != genre.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether id is ancestorID itself or one of its
+// ancestors. Callers must hold the lock.
+func (r *MemoryGenreRepository) isAncestor(id, ancestorID string) bool {
+	for current := &ancestorID; current != nil; current = r.genres[*current].ParentID {
+		if *current == id {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshPrimaryGenre renames a genre in the genre field of the books it is
+// the first genre of, like the SQL of the same name. Callers must hold both
+// locks.
+func (r *MemoryGenreRepository) refreshPrimaryGenre(genreID string, updatedAt time.Time) {
+	name := r.genres[genreID].Name
+	for bookID, genres := range r.books.genres {
+		book, ok := r.books.books[bookID]
+		if !ok || len(genres) == 0 || genres[0] != genreID {
+			continue
+		}
+		if book.Genre == nil || *book.Genre != name {
+			book.Genre = &name
+			book.UpdatedAt = updatedAt
+			book.Version++
+			r.books.books[bookID] = book
+		}
+	}
+}
+
+// storedGenre copies genre with its aliases deduplicated by key, without
+// the one matching its own name, and sorted like the SQL aliases array.
+func storedGenre(genre *models.Genre) models.Genre {
+	stored := *genre
+	stored.Aliases = []string{}
+	seen := map[string]bool{genreNameKey(genre.Name): true}
+	for _, alias := range genre.Aliases {
+		if key := genreNameKey(alias); !seen[key] {
+			seen[key] = true
+			stored.Aliases = append(stored.Aliases, alias)
+		}
+	}
+	sort.Strings(stored.Aliases)
+	return stored
+}
+
+func copyGenre(genre models.Genre) models.Genre {
+	genre.Aliases = append([]string{}, genre.Aliases...)
+	return genre
+}
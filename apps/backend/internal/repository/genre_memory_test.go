@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryGenre(name string, parentID *string, aliases ...string) *models.Genre {
+	return &models.Genre{
+		ID:        uuid.New().String(),
+		Name:      name,
+		ParentID:  parentID,
+		Aliases:   aliases,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestGenreNameKey(t *testing.T) {
+	assert.Equal(t, "scifi", genreNameKey("Sci-Fi"))
+	assert.Equal(t, "scifi", genreNameKey(" sci fi "))
+	assert.Equal(t, "", genreNameKey("--"))
+}
+
+func TestMemoryGenreRepository_Tree(t *testing.T) {
+	repo := NewMemoryGenreRepository(NewMemoryBookRepository())
+	fiction := newMemoryGenre("Fiction", nil)
+	require.NoError(t, repo.Create(fiction))
+	sf := newMemoryGenre("Science Fiction", &fiction.ID, "Sci-Fi", "scifi", "science-fiction")
+	require.NoError(t, repo.Create(sf))
+	cyberpunk := newMemoryGenre("Cyberpunk", &sf.ID)
+	require.NoError(t, repo.Create(cyberpunk))
+
+	stored, err := repo.GetByID(sf.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sci-Fi"}, stored.Aliases)
+
+	assert.ErrorIs(t, repo.Create(newMemoryGenre("SCI FI", nil)), ErrDuplicateGenre)
+	assert.ErrorIs(t, repo.Create(newMemoryGenre("Horror", &cyberpunk.ID, "Cyber-Punk")), ErrDuplicateGenre)
+	missing := uuid.New().String()
+	assert.ErrorIs(t, repo.Create(newMemoryGenre("Horror", &missing)), ErrUnknownGenre)
+
+	ids, err := repo.Subtree("sci-fi")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{sf.ID, cyberpunk.ID}, ids)
+
+	ids, err = repo.Subtree("Poetry")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	fiction.ParentID = &cyberpunk.ID
+	assert.ErrorIs(t, repo.Update(fiction), ErrGenreCycle)
+
+	assert.ErrorIs(t, repo.Delete(sf.ID), ErrGenreInUse)
+	require.NoError(t, repo.Delete(cyberpunk.ID))
+	assert.ErrorIs(t, repo.Delete(cyberpunk.ID), ErrGenreNotFound)
+}
+
+func TestMemoryGenreRepository_BookGenres(t *testing.T) {
+	books := NewMemoryBookRepository()
+	repo := NewMemoryGenreRepository(books)
+	book := newMemoryBook("Neuromancer", "William Gibson", 1984, "sci fi", time.Now())
+	require.NoError(t, books.Create(book))
+
+	linked := newMemoryGenre("sci fi", nil)
+	require.NoError(t, repo.LinkGenre(book.ID, linked))
+	cyberpunk := newMemoryGenre("Cyberpunk", nil)
+	require.NoError(t, repo.Create(cyberpunk))
+
+	genres, err := repo.BookGenres(book.ID)
+	require.NoError(t, err)
+	require.Len(t, genres, 1)
+	assert.Equal(t, linked.ID, genres[0].ID)
+
+	t.Run("replace", func(t *testing.T) {
+		primary := "Cyberpunk"
+		require.NoError(t, repo.ReplaceBookGenres(book.ID, 1, []string{cyberpunk.ID, linked.ID}, &primary, time.Now()))
+		assert.ErrorIs(t, repo.ReplaceBookGenres(book.ID, 1, nil, nil, time.Now()), ErrVersionConflict)
+		assert.ErrorIs(t, repo.ReplaceBookGenres(book.ID, 2, []string{uuid.New().String()}, nil, time.Now()), ErrUnknownGenre)
+
+		genres, err := repo.BookGenres(book.ID)
+		require.NoError(t, err)
+		require.Len(t, genres, 2)
+		assert.Equal(t, "Cyberpunk", genres[0].Name)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		require.NoError(t, repo.Merge(cyberpunk.ID, linked.ID, time.Now()))
+
+		genres, err := repo.BookGenres(book.ID)
+		require.NoError(t, err)
+		require.Len(t, genres, 1)
+		assert.Equal(t, linked.ID, genres[0].ID)
+		assert.Equal(t, []string{"Cyberpunk"}, genres[0].Aliases)
+
+		stored, err := books.GetByID(book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "sci fi", *stored.Genre)
+		assert.Equal(t, 3, stored.Version)
+
+		assert.ErrorIs(t, repo.Merge(linked.ID, linked.ID, time.Now()), ErrGenreCycle)
+		assert.ErrorIs(t, repo.Merge(cyberpunk.ID, linked.ID, time.Now()), ErrGenreNotFound)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		linked.Name = "Science Fiction"
+		linked.Aliases = []string{"Cyberpunk"}
+		require.NoError(t, repo.Update(linked))
+
+		stored, err := books.GetByID(book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Science Fiction", *stored.Genre)
+		assert.Equal(t, 4, stored.Version)
+	})
+
+	t.Run("unlink", func(t *testing.T) {
+		require.NoError(t, repo.LinkGenre(book.ID, nil))
+		genres, err := repo.BookGenres(book.ID)
+		require.NoError(t, err)
+		assert.Empty(t, genres)
+		require.NoError(t, repo.Delete(linked.ID))
+	})
+}
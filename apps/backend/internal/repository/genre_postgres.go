@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	genreNameKeyIndex  = "idx_genres_name_key"
+	genreParentFKey    = "genres_parent_id_fkey"
+	bookGenreGenreFKey = "book_genres_genre_id_fkey"
+	bookGenreBookFKey  = "book_genres_book_id_fkey"
+
+	genreColumns = `g.id, g.name, g.parent_id, g.created_at, g.updated_at,
+			  ARRAY(SELECT a.name FROM genre_aliases a WHERE a.genre_id = g.id ORDER BY a.name) AS aliases`
+
+	// genreKeysTaken reports whether any of the names in $1 has the key of
+	// the name or an alias of a genre other than $2.
+	genreKeysTaken = `WITH keys AS (SELECT genre_name_key(n) AS name_key FROM unnest($1::text[]) AS n)
+			  SELECT EXISTS (SELECT 1 FROM genres g JOIN keys k ON g.name_key = k.name_key WHERE g.id <> $2)
+			  OR EXISTS (SELECT 1 FROM genre_aliases a JOIN keys k ON a.name_key = k.name_key WHERE a.genre_id <> $2)`
+
+	// insertGenreAlias skips aliases with the key of the genre's own name in
+	// $3 and repeats of an earlier alias.
+	insertGenreAlias = `INSERT INTO genre_aliases (name_key, name, genre_id)
+			  SELECT genre_name_key($1), $1, $2 WHERE genre_name_key($1) <> genre_name_key($3)
+			  ON CONFLICT (name_key) DO NOTHING`
+
+	// refreshPrimaryGenre renames the genre $1 in the genre field of the
+	// books it is the first genre of.
+	refreshPrimaryGenre = `UPDATE books b SET genre = g.name, updated_at = $2, version = b.version + 1
+			  FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+			  WHERE bg.genre_id = $1 AND bg.book_id = b.id
+			  AND bg.position = (SELECT MIN(position) FROM book_genres WHERE book_id = b.id)
+			  AND b.genre IS DISTINCT FROM g.name`
+)
+
+type PostgresGenreRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresGenreRepository(db *sql.DB) *PostgresGenreRepository {
+	return &PostgresGenreRepository{db: db}
+}
+
+func scanGenre(scanner interface{ Scan(...interface{}) error }, genre *models.Genre) error {
+	return scanner.Scan(&genre.ID, &genre.Name, &genre.ParentID, &genre.CreatedAt, &genre.UpdatedAt, pq.Array(&genre.Aliases))
+}
+
+func (r *PostgresGenreRepository) List() ([]models.Genre, error) {
+	rows, err := r.db.Query("SELECT " + genreColumns + " FROM genres g ORDER BY g.name, g.id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var genre models.Genre
+		if err := scanGenre(rows, &genre); err != nil {
+			return nil, fmt.Errorf("failed to scan genre: %w", err)
+		}
+		genres = append(genres, genre)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch genres: %w", err)
+	}
+
+	return genres, nil
+}
+
+func (r *PostgresGenreRepository) GetByID(id string) (*models.Genre, error) {
+	var genre models.Genre
+	err := scanGenre(r.db.QueryRow("SELECT "+genreColumns+" FROM genres g WHERE g.id = $1", id), &genre)
+	if err == sql.ErrNoRows {
+		return nil, ErrGenreNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genre: %w", err)
+	}
+
+	return &genre, nil
+}
+
+func (r *PostgresGenreRepository) Create(genre *models.Genre) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkGenreKeys(tx, genre); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO genres (id, name, name_key, parent_id, created_at, updated_at)
+			  VALUES ($1, $2, genre_name_key($2), $3, $4, $5)`,
+		genre.ID, genre.Name, genre.ParentID, genre.CreatedAt, genre.UpdatedAt)
+	if isUniqueViolation(err, genreNameKeyIndex) {
+		return ErrDuplicateGenre
+	}
+	if isForeignKeyViolation(err, genreParentFKey) {
+		return ErrUnknownGenre
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create genre: %w", err)
+	}
+
+	if err := insertGenreAliases(tx, genre); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit genre: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresGenreRepository) Update(genre *models.Genre) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkGenreKeys(tx, genre); err != nil {
+		return err
+	}
+	if genre.ParentID != nil {
+		if err := checkGenreCycle(tx, genre.ID, *genre.ParentID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`UPDATE genres SET name = $1, name_key = genre_name_key($1), parent_id = $2, updated_at = $3
+			  WHERE id = $4`, genre.Name, genre.ParentID, genre.UpdatedAt, genre.ID)
+	if isUniqueViolation(err, genreNameKeyIndex) {
+		return ErrDuplicateGenre
+	}
+	if isForeignKeyViolation(err, genreParentFKey) {
+		return ErrUnknownGenre
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update genre: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrGenreNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM genre_aliases WHERE genre_id = $1", genre.ID); err != nil {
+		return fmt.Errorf("failed to remove aliases: %w", err)
+	}
+	if err := insertGenreAliases(tx, genre); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(refreshPrimaryGenre, genre.ID, genre.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to update books of genre: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit genre: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresGenreRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM genres WHERE id = $1", id)
+	if isForeignKeyViolation(err, bookGenreGenreFKey) || isForeignKeyViolation(err, genreParentFKey) {
+		return ErrGenreInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete genre: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrGenreNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresGenreRepository) Merge(sourceID, targetID string, mergedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var sourceName string
+	err = tx.QueryRow("SELECT name FROM genres WHERE id = $1 FOR UPDATE", sourceID).Scan(&sourceName)
+	if err == sql.ErrNoRows {
+		return ErrGenreNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch genre: %w", err)
+	}
+
+	var targetExists bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM genres WHERE id = $1 FOR UPDATE)", targetID).Scan(&targetExists)
+	if err != nil {
+		return fmt.Errorf("failed to fetch genre: %w", err)
+	}
+	if !targetExists {
+		return ErrUnknownGenre
+	}
+	if err := checkGenreCycle(tx, sourceID, targetID); err != nil {
+		return err
+	}
+
+	// A book that had both genres keeps the earlier position of the two.
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO book_genres (book_id, genre_id, position)
+			  SELECT book_id, $2, position FROM book_genres WHERE genre_id = $1
+			  ON CONFLICT (book_id, genre_id) DO UPDATE SET position = LEAST(book_genres.position, EXCLUDED.position)`,
+			[]interface{}{sourceID, targetID}},
+		{"DELETE FROM book_genres WHERE genre_id = $1", []interface{}{sourceID}},
+		{"UPDATE genres SET parent_id = $2 WHERE parent_id = $1", []interface{}{sourceID, targetID}},
+		{"UPDATE genre_aliases SET genre_id = $2 WHERE genre_id = $1", []interface{}{sourceID, targetID}},
+		{`DELETE FROM loan_policies p WHERE p.genre_id = $1 AND EXISTS (SELECT 1 FROM loan_policies t
+			  WHERE t.genre_id = $2 AND t.membership_type IS NOT DISTINCT FROM p.membership_type
+			  AND t.branch_id IS NOT DISTINCT FROM p.branch_id)`,
+			[]interface{}{sourceID, targetID}},
+		{"UPDATE loan_policies SET genre_id = $2, updated_at = $3 WHERE genre_id = $1", []interface{}{sourceID, targetID, mergedAt}},
+		{"DELETE FROM genres WHERE id = $1", []interface{}{sourceID}},
+		{insertGenreAlias, []interface{}{sourceName, targetID, ""}},
+		{refreshPrimaryGenre, []interface{}{targetID, mergedAt}},
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return fmt.Errorf("failed to merge genre: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit merge: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresGenreRepository) Subtree(name string) ([]string, error) {
+	rows, err := r.db.Query(`WITH RECURSIVE subtree AS (
+			  SELECT id FROM genres WHERE name_key = genre_name_key($1)
+			  OR id = (SELECT genre_id FROM genre_aliases WHERE name_key = genre_name_key($1))
+			  UNION
+			  SELECT g.id FROM genres g JOIN subtree s ON g.parent_id = s.id)
+			  SELECT id FROM subtree`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genre subtree: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan genre: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch genre subtree: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *PostgresGenreRepository) BookGenres(bookID string) ([]models.Genre, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)", bookID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch book: %w", err)
+	}
+	if !exists {
+		return nil, ErrBookNotFound
+	}
+
+	rows, err := r.db.Query(`SELECT `+genreColumns+`
+			  FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+			  WHERE bg.book_id = $1 ORDER BY bg.position`, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch book genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := []models.Genre{}
+	for rows.Next() {
+		var genre models.Genre
+		if err := scanGenre(rows, &genre); err != nil {
+			return nil, fmt.Errorf("failed to scan genre: %w", err)
+		}
+		genres = append(genres, genre)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch book genres: %w", err)
+	}
+
+	return genres, nil
+}
+
+func (r *PostgresGenreRepository) ReplaceBookGenres(bookID string, version int, genreIDs []string, genre *string, updatedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE books SET genre = $1, updated_at = $2, version = version + 1
+			  WHERE id = $3 AND version = $4 AND deleted_at IS NULL`, genre, updatedAt, bookID, version)
+	if err != nil {
+		return fmt.Errorf("failed to update book: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)", bookID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check book version: %w", err)
+		}
+		if !exists {
+			return ErrBookNotFound
+		}
+		return ErrVersionConflict
+	}
+
+	if _, err := tx.Exec("DELETE FROM book_genres WHERE book_id = $1", bookID); err != nil {
+		return fmt.Errorf("failed to remove book genres: %w", err)
+	}
+
+	for i, genreID := range genreIDs {
+		_, err := tx.Exec("INSERT INTO book_genres (book_id, genre_id, position) VALUES ($1, $2, $3)", bookID, genreID, i)
+		if isForeignKeyViolation(err, bookGenreGenreFKey) {
+			return ErrUnknownGenre
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add book genre: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit book genres: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresGenreRepository) LinkGenre(bookID string, genre *models.Genre) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM book_genres WHERE book_id = $1", bookID); err != nil {
+		return fmt.Errorf("failed to remove book genres: %w", err)
+	}
+
+	if genre != nil && genreNameKey(genre.Name) != "" {
+		err := tx.QueryRow(`SELECT id FROM genres WHERE name_key = genre_name_key($1)
+			  UNION ALL SELECT genre_id FROM genre_aliases WHERE name_key = genre_name_key($1) LIMIT 1`, genre.Name).Scan(&genre.ID)
+		if err == sql.ErrNoRows {
+			// The no-op update makes RETURNING yield the ID of a genre
+			// created concurrently.
+			err = tx.QueryRow(`INSERT INTO genres (id, name, name_key, created_at, updated_at)
+			  VALUES ($1, $2, genre_name_key($2), $3, $4)
+			  ON CONFLICT (name_key) DO UPDATE SET name_key = EXCLUDED.name_key RETURNING id`,
+				genre.ID, genre.Name, genre.CreatedAt, genre.UpdatedAt).Scan(&genre.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find or create genre: %w", err)
+		}
+
+		_, err = tx.Exec("INSERT INTO book_genres (book_id, genre_id, position) VALUES ($1, $2, 0)", bookID, genre.ID)
+		if isForeignKeyViolation(err, bookGenreBookFKey) {
+			return ErrBookNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add book genre: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit book genre: %w", err)
+	}
+	return nil
+}
+
+// checkGenreKeys returns ErrDuplicateGenre when the name or an alias of
+// genre belongs to another genre.
+func checkGenreKeys(tx *sql.Tx, genre *models.Genre) error {
+	names := append([]string{genre.Name}, genre.Aliases...)
+
+	var taken bool
+	if err := tx.QueryRow(genreKeysTaken, pq.Array(names), genre.ID).Scan(&taken); err != nil {
+		return fmt.Errorf("failed to check genre names: %w", err)
+	}
+	if taken {
+		return ErrDuplicateGenre
+	}
+	return nil
+}
+
+// checkGenreCycle returns ErrGenreCycle when ancestorID is id itself or one
+// of its descendants.
+func checkGenreCycle(tx *sql.Tx, id, ancestorID string) error {
+	var cycle bool
+	err := tx.QueryRow(`WITH RECURSIVE ancestors AS (
+			  SELECT id, parent_id FROM genres WHERE id = $1
+			  UNION
+			  SELECT g.id, g.parent_id FROM genres g JOIN ancestors a ON g.id = a.parent_id)
+			  SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`, ancestorID, id).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("failed to check genre ancestors: %w", err)
+	}
+	if cycle {
+		return ErrGenreCycle
+	}
+	return nil
+}
+
+func insertGenreAliases(tx *sql.Tx, genre *models.Genre) error {
+	for _, alias := range genre.Aliases {
+		if _, err := tx.Exec(insertGenreAlias, alias, genre.ID, genre.Name); err != nil {
+			return fmt.Errorf("failed to add alias: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresGenreRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresGenreRepository(db)
+	now := time.Now()
+	parentID := "2a7e4c5e-4a37-4f39-a6c8-2f6f0c8b1c1e"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT g.id, g.name, g.parent_id, g.created_at, g.updated_at, ARRAY(SELECT a.name FROM genre_aliases a WHERE a.genre_id = g.id ORDER BY a.name) AS aliases FROM genres g ORDER BY g.name, g.id")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "parent_id", "created_at", "updated_at", "aliases"}).
+			AddRow(parentID, "Fiction", nil, now, now, "{}").
+			AddRow("8d0f0a4e-7c1b-4b8e-9c57-0f3a1d2e5b6c", "Science Fiction", parentID, now, now, `{Sci-Fi,SF}`))
+
+	genres, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, genres, 2)
+	assert.Nil(t, genres[0].ParentID)
+	assert.Equal(t, parentID, *genres[1].ParentID)
+	assert.Equal(t, []string{"Sci-Fi", "SF"}, genres[1].Aliases)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGenreRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresGenreRepository(db)
+	now := time.Now()
+	genre := &models.Genre{ID: "1", Name: "Science Fiction", Aliases: []string{"Sci-Fi"}, CreatedAt: now, UpdatedAt: now}
+	keysTaken := regexp.QuoteMeta("WITH keys AS (SELECT genre_name_key(n) AS name_key FROM unnest($1::text[]) AS n)")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(keysTaken).
+			WithArgs(pq.Array([]string{"Science Fiction", "Sci-Fi"}), "1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO genres (id, name, name_key, parent_id, created_at, updated_at) VALUES ($1, $2, genre_name_key($2), $3, $4, $5)")).
+			WithArgs("1", "Science Fiction", nil, now, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO genre_aliases (name_key, name, genre_id) SELECT genre_name_key($1), $1, $2 WHERE genre_name_key($1) <> genre_name_key($3)")).
+			WithArgs("Sci-Fi", "1", "Science Fiction").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Create(genre))
+	})
+
+	t.Run("alias taken", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(keysTaken).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Create(genre), ErrDuplicateGenre)
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(keysTaken).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO genres")).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "genres_parent_id_fkey"})
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Create(genre), ErrUnknownGenre)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGenreRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresGenreRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM genres WHERE id = $1")).
+		WithArgs("1").
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "book_genres_genre_id_fkey"})
+	assert.ErrorIs(t, repo.Delete("1"), ErrGenreInUse)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM genres WHERE id = $1")).
+		WithArgs("2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete("2"), ErrGenreNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGenreRepository_Merge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresGenreRepository(db)
+	mergedAt := time.Now()
+	lockSource := regexp.QuoteMeta("SELECT name FROM genres WHERE id = $1 FOR UPDATE")
+	lockTarget := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM genres WHERE id = $1 FOR UPDATE)")
+	ancestors := regexp.QuoteMeta("WITH RECURSIVE ancestors AS")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockSource).WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("SF"))
+		mock.ExpectQuery(lockTarget).WithArgs("2").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(ancestors).WithArgs("2", "1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO book_genres (book_id, genre_id, position) SELECT book_id, $2, position FROM book_genres WHERE genre_id = $1 ON CONFLICT (book_id, genre_id) DO UPDATE SET position = LEAST(book_genres.position, EXCLUDED.position)")).
+			WithArgs("1", "2").WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM book_genres WHERE genre_id = $1")).
+			WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE genres SET parent_id = $2 WHERE parent_id = $1")).
+			WithArgs("1", "2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE genre_aliases SET genre_id = $2 WHERE genre_id = $1")).
+			WithArgs("1", "2").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM loan_policies p WHERE p.genre_id = $1 AND EXISTS")).
+			WithArgs("1", "2").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE loan_policies SET genre_id = $2, updated_at = $3 WHERE genre_id = $1")).
+			WithArgs("1", "2", mergedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM genres WHERE id = $1")).
+			WithArgs("1").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO genre_aliases")).
+			WithArgs("SF", "2", "").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE books b SET genre = g.name")).
+			WithArgs("2", mergedAt).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Merge("1", "2", mergedAt))
+	})
+
+	t.Run("into a subgenre", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockSource).WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("SF"))
+		mock.ExpectQuery(lockTarget).WithArgs("3").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(ancestors).WithArgs("3", "1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Merge("1", "3", mergedAt), ErrGenreCycle)
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockSource).WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("SF"))
+		mock.ExpectQuery(lockTarget).WithArgs("4").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Merge("1", "4", mergedAt), ErrUnknownGenre)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresGenreRepository_ReplaceBookGenres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresGenreRepository(db)
+	updatedAt := time.Now()
+	primary := "Fantasy"
+	update := regexp.QuoteMeta("UPDATE books SET genre = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND version = $4 AND deleted_at IS NULL")
+
+	t.Run("unknown genre", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs(&primary, updatedAt, "b1", 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM book_genres WHERE book_id = $1")).WithArgs("b1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO book_genres (book_id, genre_id, position) VALUES ($1, $2, $3)")).WithArgs("b1", "g1", 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO book_genres (book_id, genre_id, position) VALUES ($1, $2, $3)")).WithArgs("b1", "g2", 1).
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "book_genres_genre_id_fkey"})
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.ReplaceBookGenres("b1", 2, []string{"g1", "g2"}, &primary, updatedAt), ErrUnknownGenre)
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WithArgs(nil, updatedAt, "b1", 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)")).WithArgs("b1").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.ReplaceBookGenres("b1", 1, nil, nil, updatedAt), ErrVersionConflict)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
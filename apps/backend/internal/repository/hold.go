@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrHoldNotFound = errors.New("hold not found")
+	// ErrDuplicateHold is returned when placing a hold for a member who
+	// already has an active hold on the book.
+	ErrDuplicateHold = errors.New("member already has a hold on this book")
+	// ErrCopyAvailable is returned when placing a hold that a copy on the
+	// shelf could fill.
+	ErrCopyAvailable = errors.New("a copy is available on the shelf")
+	// ErrNoHoldableCopy is returned when placing a hold on a book or copy
+	// that is withdrawn, so no copy could ever fill it.
+	ErrNoHoldableCopy = errors.New("no copy can fill this hold")
+	// ErrHoldNotWaiting is returned when suspending or moving a hold that
+	// is not waiting in the queue.
+	ErrHoldNotWaiting = errors.New("hold is not waiting in the queue")
+	// ErrHoldClosed is returned when cancelling a hold that was already
+	// fulfilled, cancelled or expired.
+	ErrHoldClosed = errors.New("hold is no longer active")
+)
+
+// HoldPickup is what trapping a copy for a hold needs to know.
+type HoldPickup struct {
+	// Today decides which suspensions have ended, formatted as YYYY-MM-DD.
+	Today string
+	// PickupBy is the last day a trapped copy waits on the hold shelf,
+	// formatted as YYYY-MM-DD.
+	PickupBy string
+	// BranchPickupBy overrides PickupBy at the branches whose own opening
+	// hours or closures move it.
+	BranchPickupBy map[string]string
+}
+
+// at returns the last day a copy trapped at a branch waits on its hold
+// shelf.
+func (p HoldPickup) at(branchID *string) string {
+	if branchID != nil {
+		if pickupBy, ok := p.BranchPickupBy[*branchID]; ok {
+			return pickupBy
+		}
+	}
+	return p.PickupBy
+}
+
+// HoldRepository queues members for books. A copy that comes back, whether
+// checked in or released by a cancelled or expired hold, is trapped for the
+// first waiting hold it can fill; see LoanRepository.Checkin. Holds are
+// locked after the copies they trap.
+type HoldRepository interface {
+	// Place adds a hold at the end of the queue of its book, filling in its
+	// position. The book must not be deleted and the copy of an item-level
+	// hold must belong to it.
+	Place(hold *models.Hold) error
+	GetByID(id string) (*models.Hold, error)
+	// ListByBook returns the active holds on a book, ready holds first,
+	// then in-transit holds, then the waiting holds in queue order.
+	ListByBook(bookID string) ([]models.Hold, error)
+	// ListByMember expects a normalized Limit.
+	ListByMember(memberID string, params *models.HoldListParams) (*models.HoldListResponse, error)
+	// Cancel cancels an active hold. The copy of a ready hold is trapped
+	// for the next hold or made available, as at checkin; the copy of an
+	// in-transit hold travels on and is passed on when it is received.
+	Cancel(id string, cancelledAt time.Time, pickup HoldPickup) (*models.Hold, error)
+	// Suspend passes over a waiting hold until the end of the day until,
+	// or resumes it when until is nil.
+	Suspend(id string, until *string, updatedAt time.Time) (*models.Hold, error)
+	// Move puts a waiting hold at position in the queue of its book, or
+	// last when position is past the end.
+	Move(id string, position int, updatedAt time.Time) (*models.Hold, error)
+	// ExpirePickups expires the ready holds whose pickup date is before
+	// pickup.Today, passing their copies on, and returns how many expired.
+	ExpirePickups(expiredAt time.Time, pickup HoldPickup) (int64, error)
+}
+
+// moveInQueue returns queue with id moved to position, counting from 1, or
+// to the end when position is past it. It reports false when id is not in
+// queue.
+func moveInQueue(queue []string, id string, position int) ([]string, bool) {
+	index := -1
+	for i, queued := range queue {
+		if queued == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, false
+	}
+
+	rest := append(append([]string{}, queue[:index]...), queue[index+1:]...)
+	if position > len(rest)+1 {
+		position = len(rest) + 1
+	}
+
+	moved := make([]string, 0, len(queue))
+	moved = append(moved, rest[:position-1]...)
+	moved = append(moved, id)
+	return append(moved, rest[position-1:]...), true
+}
@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// memoryHold is a stored hold with its place in the queue of its book.
+type memoryHold struct {
+	hold models.Hold
+	rank int64
+}
+
+// MemoryHoldRepository queues members for the books of a
+// MemoryLoanRepository. The holds live in the loan repository, under its
+// lock, so that checkouts and checkins can fulfil and trap them.
+type MemoryHoldRepository struct {
+	loans *MemoryLoanRepository
+}
+
+func NewMemoryHoldRepository(loans *MemoryLoanRepository) *MemoryHoldRepository {
+	return &MemoryHoldRepository{loans: loans}
+}
+
+func (r *MemoryHoldRepository) Place(hold *models.Hold) error {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.RLock()
+	defer loans.items.mu.RUnlock()
+
+	loans.items.books.mu.RLock()
+	book, ok := loans.items.books.books[hold.BookID]
+	loans.items.books.mu.RUnlock()
+	if !ok || book.DeletedAt != nil {
+		return ErrBookNotFound
+	}
+
+	var total, available, holdable int
+	for _, item := range loans.items.items {
+		if item.BookID != hold.BookID || (hold.ItemID != nil && item.ID != *hold.ItemID) {
+			continue
+		}
+		total++
+		if item.Status == models.ItemAvailable {
+			available++
+		}
+		if item.Status != models.ItemWithdrawn {
+			holdable++
+		}
+	}
+	if hold.ItemID != nil && total == 0 {
+		return ErrItemNotFound
+	}
+	if available > 0 {
+		return ErrCopyAvailable
+	}
+	if holdable == 0 {
+		return ErrNoHoldableCopy
+	}
+
+	loans.members.mu.RLock()
+	_, ok = loans.members.members[hold.MemberID]
+	loans.members.mu.RUnlock()
+	if !ok {
+		return ErrMemberNotFound
+	}
+
+	for _, stored := range loans.holds {
+		if stored.hold.BookID == hold.BookID && stored.hold.MemberID == hold.MemberID && isActiveHold(&stored.hold) {
+			return ErrDuplicateHold
+		}
+	}
+
+	loans.holdRank++
+	stored := memoryHold{hold: *hold, rank: loans.holdRank}
+	loans.holds[hold.ID] = stored
+	hold.Position = loans.holdView(stored).Position
+	return nil
+}
+
+func (r *MemoryHoldRepository) GetByID(id string) (*models.Hold, error) {
+	r.loans.mu.RLock()
+	defer r.loans.mu.RUnlock()
+
+	stored, ok := r.loans.holds[id]
+	if !ok {
+		return nil, ErrHoldNotFound
+	}
+	hold := r.loans.holdView(stored)
+	return &hold, nil
+}
+
+func (r *MemoryHoldRepository) ListByBook(bookID string) ([]models.Hold, error) {
+	r.loans.mu.RLock()
+	defer r.loans.mu.RUnlock()
+
+	active := []memoryHold{}
+	for _, stored := range r.loans.holds {
+		if stored.hold.BookID == bookID && isActiveHold(&stored.hold) {
+			active = append(active, stored)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		a, b := active[i].hold, active[j].hold
+		if a.Status != b.Status {
+			return holdShelfOrder[a.Status] < holdShelfOrder[b.Status]
+		}
+		if a.Status == models.HoldReady && !a.ReadyAt.Equal(*b.ReadyAt) {
+			return a.ReadyAt.Before(*b.ReadyAt)
+		}
+		return active[i].rank < active[j].rank
+	})
+
+	holds := make([]models.Hold, 0, len(active))
+	for _, stored := range active {
+		holds = append(holds, r.loans.holdView(stored))
+	}
+	return holds, nil
+}
+
+func (r *MemoryHoldRepository) ListByMember(memberID string, params *models.HoldListParams) (*models.HoldListResponse, error) {
+	r.loans.mu.RLock()
+	holds := []models.Hold{}
+	for _, stored := range r.loans.holds {
+		hold := &stored.hold
+		if hold.MemberID == memberID && (params.Status == "" || hold.Status == params.Status) &&
+			(params.BranchID == "" || isBranch(hold.PickupBranchID, params.BranchID)) {
+			holds = append(holds, r.loans.holdView(stored))
+		}
+	}
+	r.loans.mu.RUnlock()
+
+	sort.Slice(holds, func(i, j int) bool {
+		if !holds[i].PlacedAt.Equal(holds[j].PlacedAt) {
+			return holds[i].PlacedAt.After(holds[j].PlacedAt)
+		}
+		return holds[i].ID < holds[j].ID
+	})
+
+	response := &models.HoldListResponse{Total: len(holds)}
+	start := params.Offset
+	if start > len(holds) {
+		start = len(holds)
+	}
+	end := start + params.Limit
+	if end > len(holds) {
+		end = len(holds)
+	}
+	response.Data = holds[start:end]
+
+	return response, nil
+}
+
+func (r *MemoryHoldRepository) Cancel(id string, cancelledAt time.Time, pickup HoldPickup) (*models.Hold, error) {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.Lock()
+	defer loans.items.mu.Unlock()
+
+	stored, ok := loans.holds[id]
+	if !ok {
+		return nil, ErrHoldNotFound
+	}
+	if !isActiveHold(&stored.hold) {
+		return nil, ErrHoldClosed
+	}
+
+	wasReady := stored.hold.Status == models.HoldReady
+	cancelled := loans.closeHold(stored, models.HoldCancelled, cancelledAt)
+	if wasReady {
+		loans.trapNextHold(*stored.hold.ItemID, cancelledAt, pickup, true)
+	}
+	return &cancelled, nil
+}
+
+func (r *MemoryHoldRepository) Suspend(id string, until *string, updatedAt time.Time) (*models.Hold, error) {
+	r.loans.mu.Lock()
+	defer r.loans.mu.Unlock()
+
+	stored, ok := r.loans.holds[id]
+	if !ok {
+		return nil, ErrHoldNotFound
+	}
+	if stored.hold.Status != models.HoldWaiting {
+		return nil, ErrHoldNotWaiting
+	}
+
+	stored.hold.SuspendedUntil = until
+	stored.hold.UpdatedAt = updatedAt
+	r.loans.holds[id] = stored
+
+	hold := r.loans.holdView(stored)
+	return &hold, nil
+}
+
+func (r *MemoryHoldRepository) Move(id string, position int, updatedAt time.Time) (*models.Hold, error) {
+	r.loans.mu.Lock()
+	defer r.loans.mu.Unlock()
+
+	stored, ok := r.loans.holds[id]
+	if !ok {
+		return nil, ErrHoldNotFound
+	}
+
+	waiting := []memoryHold{}
+	for _, other := range r.loans.holds {
+		if other.hold.BookID == stored.hold.BookID && other.hold.Status == models.HoldWaiting {
+			waiting = append(waiting, other)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool {
+		return waiting[i].rank < waiting[j].rank
+	})
+	queue := make([]string, 0, len(waiting))
+	for _, other := range waiting {
+		queue = append(queue, other.hold.ID)
+	}
+
+	queue, ok = moveInQueue(queue, id, position)
+	if !ok {
+		return nil, ErrHoldNotWaiting
+	}
+	for i, queued := range queue {
+		other := r.loans.holds[queued]
+		other.rank = int64(i + 1)
+		if queued == id {
+			other.hold.UpdatedAt = updatedAt
+		}
+		r.loans.holds[queued] = other
+	}
+
+	hold := r.loans.holdView(r.loans.holds[id])
+	return &hold, nil
+}
+
+func (r *MemoryHoldRepository) ExpirePickups(expiredAt time.Time, pickup HoldPickup) (int64, error) {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.Lock()
+	defer loans.items.mu.Unlock()
+
+	due := []memoryHold{}
+	for _, stored := range loans.holds {
+		if stored.hold.Status == models.HoldReady && *stored.hold.PickupBy < pickup.Today {
+			due = append(due, stored)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if *due[i].hold.PickupBy != *due[j].hold.PickupBy {
+			return *due[i].hold.PickupBy < *due[j].hold.PickupBy
+		}
+		return due[i].hold.ID < due[j].hold.ID
+	})
+
+	for _, stored := range due {
+		loans.closeHold(stored, models.HoldExpired, expiredAt)
+		loans.trapNextHold(*stored.hold.ItemID, expiredAt, pickup, true)
+	}
+	return int64(len(due)), nil
+}
+
+// holdShelfOrder orders the active holds on a book: the hold shelf first,
+// then the copies on their way to it, then the queue.
+var holdShelfOrder = map[string]int{
+	models.HoldReady:     0,
+	models.HoldInTransit: 1,
+	models.HoldWaiting:   2,
+}
+
+func isActiveHold(hold *models.Hold) bool {
+	_, ok := holdShelfOrder[hold.Status]
+	return ok
+}
+
+// holdView returns a stored hold with its queue position. Callers must hold
+// the lock.
+func (r *MemoryLoanRepository) holdView(stored memoryHold) models.Hold {
+	hold := stored.hold
+	hold.Position = 0
+	if hold.Status != models.HoldWaiting {
+		return hold
+	}
+	for _, other := range r.holds {
+		if other.hold.BookID == hold.BookID && other.hold.Status == models.HoldWaiting && other.rank <= stored.rank {
+			hold.Position++
+		}
+	}
+	return hold
+}
+
+// closeHold ends a hold as fulfilled, cancelled or expired. Callers must
+// hold the lock.
+func (r *MemoryLoanRepository) closeHold(stored memoryHold, status string, closedAt time.Time) models.Hold {
+	stored.hold.Status = status
+	stored.hold.ClosedAt = &closedAt
+	stored.hold.UpdatedAt = closedAt
+	r.holds[stored.hold.ID] = stored
+	return r.holdView(stored)
+}
+
+// readyHold returns the hold a copy is trapped for, if any. Callers must
+// hold the lock.
+func (r *MemoryLoanRepository) readyHold(itemID string) *memoryHold {
+	for _, stored := range r.holds {
+		if stored.hold.Status == models.HoldReady && *stored.hold.ItemID == itemID {
+			return &stored
+		}
+	}
+	return nil
+}
+
+// fulfilHolds fulfils the holds of the borrower of a new loan that its copy
+// fills. Callers must hold the lock.
+func (r *MemoryLoanRepository) fulfilHolds(loan *models.Loan) {
+	for _, stored := range r.holds {
+		hold := &stored.hold
+		if hold.BookID != loan.BookID || hold.MemberID != loan.MemberID {
+			continue
+		}
+		ready := hold.Status == models.HoldReady && *hold.ItemID == loan.ItemID
+		waiting := hold.Status == models.HoldWaiting && (hold.ItemID == nil || *hold.ItemID == loan.ItemID)
+		if ready || waiting {
+			r.closeHold(stored, models.HoldFulfilled, loan.CheckedOutAt)
+		}
+	}
+}
+
+// trapNextHold traps a copy that has come back for the first waiting hold
+// of its book that it can fill, passing over suspended holds, and puts the
+// copy on the hold shelf. With no such hold the copy becomes available. A
+// copy at one branch that is picked up at another, or that belongs to
+// another when sendHome is set and no hold takes it, is shipped there
+// instead; its hold is in transit until the transfer is received, and
+// transfers requested for the copy are cancelled. A copy that is already
+// available and stays so is left alone. Callers must hold the lock and the
+// items lock.
+func (r *MemoryLoanRepository) trapNextHold(itemID string, trappedAt time.Time, pickup HoldPickup, sendHome bool) *models.Hold {
+	item := r.items.items[itemID]
+
+	var next *memoryHold
+	for _, stored := range r.holds {
+		hold := &stored.hold
+		if hold.BookID != item.BookID || hold.Status != models.HoldWaiting ||
+			(hold.ItemID != nil && *hold.ItemID != itemID) ||
+			(hold.SuspendedUntil != nil && *hold.SuspendedUntil >= pickup.Today) {
+			continue
+		}
+		if next == nil || stored.rank < next.rank {
+			candidate := stored
+			next = &candidate
+		}
+	}
+
+	destination := item.CurrentBranchID
+	if next != nil && next.hold.PickupBranchID != nil {
+		destination = next.hold.PickupBranchID
+	} else if next == nil && sendHome && item.HomeBranchID != nil {
+		destination = item.HomeBranchID
+	}
+	shipped := item.CurrentBranchID != nil && destination != nil && *destination != *item.CurrentBranchID
+	if next == nil && !shipped && item.Status == models.ItemAvailable {
+		return nil
+	}
+
+	item.Status = models.ItemAvailable
+	var trapped *models.Hold
+	if next != nil {
+		trappedItem := itemID
+		next.hold.ItemID = &trappedItem
+		next.hold.Status = models.HoldInTransit
+		next.hold.UpdatedAt = trappedAt
+		if !shipped {
+			setHoldReady(&next.hold, trappedAt, pickup.at(item.CurrentBranchID))
+			item.Status = models.ItemOnHold
+		}
+		r.holds[next.hold.ID] = *next
+
+		hold := r.holdView(*next)
+		trapped = &hold
+	}
+	if shipped {
+		r.cancelRequestedTransfers(itemID, trappedAt)
+		transfer := models.Transfer{
+			ID:           uuid.New().String(),
+			ItemID:       itemID,
+			Barcode:      item.Barcode,
+			FromBranchID: *item.CurrentBranchID,
+			ToBranchID:   *destination,
+			Status:       models.TransferInTransit,
+			RequestedAt:  trappedAt,
+			ShippedAt:    &trappedAt,
+			UpdatedAt:    trappedAt,
+		}
+		if trapped != nil {
+			transfer.HoldID = &trapped.ID
+		}
+		r.transfers[transfer.ID] = transfer
+		item.Status = models.ItemInTransit
+	}
+
+	item.StatusNote = nil
+	item.StatusChangedAt = trappedAt
+	item.UpdatedAt = trappedAt
+	r.items.items[itemID] = item
+	return trapped
+}
+
+// trapAvailable traps a copy that has just become available other than by
+// checkin or transfer, such as a new copy or one found again, for the first
+// waiting hold it can fill, and reads back the copy into item. Callers must
+// hold the lock and the items lock.
+func (r *MemoryLoanRepository) trapAvailable(item *models.Item, trappedAt time.Time, pickup HoldPickup) {
+	r.trapNextHold(item.ID, trappedAt, pickup, false)
+	*item = r.items.items[item.ID]
+}
+
+// cancelRequestedTransfers cancels the transfers requested for a copy that
+// is being shipped by itself. Callers must hold the lock.
+func (r *MemoryLoanRepository) cancelRequestedTransfers(itemID string, cancelledAt time.Time) {
+	for id, transfer := range r.transfers {
+		if transfer.ItemID == itemID && transfer.Status == models.TransferRequested {
+			transfer.Status = models.TransferCancelled
+			transfer.CancelledAt = &cancelledAt
+			transfer.UpdatedAt = cancelledAt
+			r.transfers[id] = transfer
+		}
+	}
+}
+
+// setHoldReady puts the copy trapped for a hold on the hold shelf until
+// pickupBy.
+func setHoldReady(hold *models.Hold, readyAt time.Time, pickupBy string) {
+	hold.Status = models.HoldReady
+	hold.ReadyAt = &readyAt
+	hold.PickupBy = &pickupBy
+	hold.UpdatedAt = readyAt
+}
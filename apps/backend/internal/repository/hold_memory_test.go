@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryHold(bookID, memberID string, itemID *string, placedAt time.Time) *models.Hold {
+	level := models.HoldLevelTitle
+	if itemID != nil {
+		level = models.HoldLevelItem
+	}
+	return &models.Hold{
+		ID:        uuid.New().String(),
+		BookID:    bookID,
+		MemberID:  memberID,
+		ItemID:    itemID,
+		Level:     level,
+		Status:    models.HoldWaiting,
+		PlacedAt:  placedAt,
+		UpdatedAt: placedAt,
+	}
+}
+
+func TestMemoryHoldRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	members := NewMemoryMemberRepository()
+	loans := NewMemoryLoanRepository(items, members)
+	repo := NewMemoryHoldRepository(loans)
+
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	first := newMemoryItem(book.ID, "E-1")
+	require.NoError(t, items.Create(first, HoldPickup{}))
+	second := newMemoryItem(book.ID, "E-2")
+	require.NoError(t, items.Create(second, HoldPickup{}))
+
+	var patrons []*models.Member
+	for _, card := range []string{"00000000000018", "00000000000026", "00000000000034", "00000000000042"} {
+		member := newMemoryMember("Harriet", "Smith", card, models.MembershipAdult, "2030-01-01")
+		require.NoError(t, members.Create(member))
+		patrons = append(patrons, member)
+	}
+
+	placedAt := time.Now().Add(-time.Hour)
+	assert.ErrorIs(t, repo.Place(newMemoryHold(book.ID, patrons[1].ID, nil, placedAt)), ErrCopyAvailable)
+
+	require.NoError(t, loans.Checkout(newMemoryLoan(first.ID, patrons[0].ID, "2026-02-01", placedAt), 5))
+	require.NoError(t, loans.Checkout(newMemoryLoan(second.ID, patrons[0].ID, "2026-02-01", placedAt), 5))
+
+	itemHold := newMemoryHold(book.ID, patrons[1].ID, &second.ID, placedAt)
+	require.NoError(t, repo.Place(itemHold))
+	titleHold := newMemoryHold(book.ID, patrons[2].ID, nil, placedAt.Add(time.Minute))
+	require.NoError(t, repo.Place(titleHold))
+	lastHold := newMemoryHold(book.ID, patrons[3].ID, nil, placedAt.Add(2*time.Minute))
+	require.NoError(t, repo.Place(lastHold))
+	assert.Equal(t, 3, lastHold.Position)
+
+	assert.ErrorIs(t, repo.Place(newMemoryHold(book.ID, patrons[3].ID, nil, time.Now())), ErrDuplicateHold)
+	missing := uuid.New().String()
+	assert.ErrorIs(t, repo.Place(newMemoryHold(book.ID, patrons[0].ID, &missing, time.Now())), ErrItemNotFound)
+	assert.ErrorIs(t, repo.Place(newMemoryHold(uuid.New().String(), patrons[0].ID, nil, time.Now())), ErrBookNotFound)
+
+	moved, err := repo.Move(lastHold.ID, 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position)
+	until := "2026-03-05"
+	_, err = repo.Suspend(lastHold.ID, &until, time.Now())
+	require.NoError(t, err)
+
+	pickup := HoldPickup{Today: "2026-03-01", PickupBy: "2026-03-08"}
+	t.Run("checkin traps the first hold the copy fills", func(t *testing.T) {
+		_, trapped, err := loans.Checkin(first.ID, nil, time.Now(), pickup)
+		require.NoError(t, err)
+		require.NotNil(t, trapped)
+		assert.Equal(t, titleHold.ID, trapped.ID)
+		assert.Equal(t, first.ID, *trapped.ItemID)
+		assert.Equal(t, "2026-03-08", *trapped.PickupBy)
+
+		item, err := items.GetByID(first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemOnHold, item.Status)
+
+		queue, err := repo.ListByBook(book.ID)
+		require.NoError(t, err)
+		require.Len(t, queue, 3)
+		assert.Equal(t, titleHold.ID, queue[0].ID)
+		assert.Equal(t, []int{0, 1, 2}, []int{queue[0].Position, queue[1].Position, queue[2].Position})
+	})
+
+	t.Run("checkout fulfils the ready hold", func(t *testing.T) {
+		assert.ErrorIs(t, loans.Checkout(newMemoryLoan(first.ID, patrons[1].ID, "2026-03-22", time.Now()), 5), ErrItemOnHold)
+		require.NoError(t, loans.Checkout(newMemoryLoan(first.ID, patrons[2].ID, "2026-03-22", time.Now()), 5))
+
+		hold, err := repo.GetByID(titleHold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldFulfilled, hold.Status)
+	})
+
+	t.Run("expiry and cancellation pass the copy on", func(t *testing.T) {
+		_, trapped, err := loans.Checkin(second.ID, nil, time.Now(), pickup)
+		require.NoError(t, err)
+		require.NotNil(t, trapped)
+		assert.Equal(t, itemHold.ID, trapped.ID)
+
+		expired, err := repo.ExpirePickups(time.Now(), HoldPickup{Today: "2026-03-09", PickupBy: "2026-03-16"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		next, err := repo.GetByID(lastHold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldReady, next.Status)
+		assert.Equal(t, second.ID, *next.ItemID)
+
+		_, err = repo.Suspend(lastHold.ID, nil, time.Now())
+		assert.ErrorIs(t, err, ErrHoldNotWaiting)
+		cancelled, err := repo.Cancel(lastHold.ID, time.Now(), pickup)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldCancelled, cancelled.Status)
+		_, err = repo.Cancel(lastHold.ID, time.Now(), pickup)
+		assert.ErrorIs(t, err, ErrHoldClosed)
+
+		item, err := items.GetByID(second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemAvailable, item.Status)
+	})
+
+	history, err := repo.ListByMember(patrons[1].ID, &models.HoldListParams{Limit: 20})
+	require.NoError(t, err)
+	require.Equal(t, 1, history.Total)
+	assert.Equal(t, models.HoldExpired, history.Data[0].Status)
+
+	hasLoans, err := loans.HasLoans(patrons[3].ID)
+	require.NoError(t, err)
+	assert.True(t, hasLoans)
+}
+
+func TestMoveInQueue(t *testing.T) {
+	queue := []string{"a", "b", "c", "d"}
+
+	moved, ok := moveInQueue(queue, "c", 1)
+	require.True(t, ok)
+	assert.Equal(t, []string{"c", "a", "b", "d"}, moved)
+
+	moved, ok = moveInQueue(queue, "a", 9)
+	require.True(t, ok)
+	assert.Equal(t, []string{"b", "c", "d", "a"}, moved)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, queue)
+
+	_, ok = moveInQueue(queue, "e", 1)
+	assert.False(t, ok)
+}
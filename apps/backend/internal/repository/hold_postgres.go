@@ -0,0 +1,511 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	holdActiveMemberIndex = "idx_holds_active_member"
+	holdMemberFKey        = "holds_member_id_fkey"
+	holdPickupBranchFKey  = "holds_pickup_branch_id_fkey"
+
+	holdColumns = `h.id, h.book_id, h.member_id, h.item_id, h.level, h.status, h.pickup_branch_id,
+			  CASE WHEN h.status = 'waiting' THEN (SELECT COUNT(*) FROM holds q
+			  WHERE q.book_id = h.book_id AND q.status = 'waiting' AND q.queue_rank <= h.queue_rank) ELSE 0 END AS position,
+			  to_char(h.suspended_until, 'YYYY-MM-DD') AS suspended_until, to_char(h.pickup_by, 'YYYY-MM-DD') AS pickup_by,
+			  h.placed_at, h.ready_at, h.closed_at, h.updated_at`
+)
+
+type PostgresHoldRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresHoldRepository(db *sql.DB) *PostgresHoldRepository {
+	return &PostgresHoldRepository{db: db}
+}
+
+func scanHold(scanner interface{ Scan(...interface{}) error }, hold *models.Hold, extra ...interface{}) error {
+	dest := []interface{}{&hold.ID, &hold.BookID, &hold.MemberID, &hold.ItemID, &hold.Level, &hold.Status, &hold.PickupBranchID,
+		&hold.Position, &hold.SuspendedUntil, &hold.PickupBy, &hold.PlacedAt, &hold.ReadyAt, &hold.ClosedAt, &hold.UpdatedAt}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+// Place locks the book, so that holds placed at the same time queue one
+// after the other.
+func (r *PostgresHoldRepository) Place(hold *models.Hold) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockHoldBook(tx, hold.BookID, true); err != nil {
+		return err
+	}
+
+	query := `SELECT COUNT(*), COUNT(*) FILTER (WHERE status = 'available'), COUNT(*) FILTER (WHERE status <> 'withdrawn')
+			  FROM items WHERE book_id = $1`
+	args := []interface{}{hold.BookID}
+	if hold.ItemID != nil {
+		query += " AND id = $2"
+		args = append(args, *hold.ItemID)
+	}
+
+	var total, available, holdable int
+	if err := tx.QueryRow(query, args...).Scan(&total, &available, &holdable); err != nil {
+		return fmt.Errorf("failed to count items: %w", err)
+	}
+	if hold.ItemID != nil && total == 0 {
+		return ErrItemNotFound
+	}
+	if available > 0 {
+		return ErrCopyAvailable
+	}
+	if holdable == 0 {
+		return ErrNoHoldableCopy
+	}
+
+	_, err = tx.Exec(`INSERT INTO holds (id, book_id, member_id, item_id, level, status, pickup_branch_id, queue_rank, placed_at,
+			  updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT COALESCE(MAX(queue_rank), 0) + 1 FROM holds WHERE book_id = $2),
+			  $8, $9)`,
+		hold.ID, hold.BookID, hold.MemberID, hold.ItemID, hold.Level, hold.Status, hold.PickupBranchID, hold.PlacedAt, hold.UpdatedAt)
+	if isUniqueViolation(err, holdActiveMemberIndex) {
+		return ErrDuplicateHold
+	}
+	if isForeignKeyViolation(err, holdMemberFKey) {
+		return ErrMemberNotFound
+	}
+	if isForeignKeyViolation(err, holdPickupBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create hold: %w", err)
+	}
+
+	err = tx.QueryRow("SELECT COUNT(*) FROM holds WHERE book_id = $1 AND status = 'waiting'", hold.BookID).Scan(&hold.Position)
+	if err != nil {
+		return fmt.Errorf("failed to count holds: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit hold: %w", err)
+	}
+	return nil
+}
+
+// lockHoldBook locks a book while its queue changes. Only live books take
+// new holds.
+func lockHoldBook(tx *sql.Tx, bookID string, live bool) error {
+	query := "SELECT TRUE FROM books WHERE id = $1"
+	if live {
+		query += " AND deleted_at IS NULL"
+	}
+
+	var exists bool
+	err := tx.QueryRow(query+" FOR UPDATE", bookID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrBookNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock book: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresHoldRepository) GetByID(id string) (*models.Hold, error) {
+	var hold models.Hold
+	err := scanHold(r.db.QueryRow("SELECT "+holdColumns+" FROM holds h WHERE h.id = $1", id), &hold)
+	if err == sql.ErrNoRows {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hold: %w", err)
+	}
+
+	return &hold, nil
+}
+
+func (r *PostgresHoldRepository) ListByBook(bookID string) ([]models.Hold, error) {
+	rows, err := r.db.Query(`SELECT `+holdColumns+` FROM holds h WHERE h.book_id = $1 AND h.status IN ('waiting', 'in_transit', 'ready')
+			  ORDER BY h.status = 'waiting', h.ready_at, h.queue_rank`, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := []models.Hold{}
+	for rows.Next() {
+		var hold models.Hold
+		if err := scanHold(rows, &hold); err != nil {
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		holds = append(holds, hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+func (r *PostgresHoldRepository) ListByMember(memberID string, params *models.HoldListParams) (*models.HoldListResponse, error) {
+	conditions := []string{"h.member_id = $1"}
+	args := []interface{}{memberID}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("h.status = $%d", len(args)))
+	}
+	if params.BranchID != "" {
+		args = append(args, params.BranchID)
+		conditions = append(conditions, fmt.Sprintf("h.pickup_branch_id = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER () AS total FROM holds h%s ORDER BY h.placed_at DESC, h.id LIMIT $%d OFFSET $%d",
+		holdColumns, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.HoldListResponse{Data: make([]models.Hold, 0, params.Limit)}
+	for rows.Next() {
+		var hold models.Hold
+		if err := scanHold(rows, &hold, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		response.Data = append(response.Data, hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresHoldRepository) Cancel(id string, cancelledAt time.Time, pickup HoldPickup) (*models.Hold, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	hold, err := lockHold(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status != models.HoldWaiting && hold.Status != models.HoldInTransit && hold.Status != models.HoldReady {
+		return nil, ErrHoldClosed
+	}
+
+	cancelled, err := closeHold(tx, id, models.HoldCancelled, cancelledAt)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status == models.HoldReady {
+		if _, err := trapNextHold(tx, *hold.ItemID, cancelledAt, pickup, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit hold: %w", err)
+	}
+	return cancelled, nil
+}
+
+func (r *PostgresHoldRepository) Suspend(id string, until *string, updatedAt time.Time) (*models.Hold, error) {
+	var hold models.Hold
+	err := scanHold(r.db.QueryRow(`UPDATE holds h SET suspended_until = $1, updated_at = $2
+			  WHERE h.id = $3 AND h.status = 'waiting' RETURNING `+holdColumns, until, updatedAt, id), &hold)
+	if err == sql.ErrNoRows {
+		return nil, r.notWaiting(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to suspend hold: %w", err)
+	}
+
+	return &hold, nil
+}
+
+// Move locks the book, as Place does, and renumbers its waiting holds.
+func (r *PostgresHoldRepository) Move(id string, position int, updatedAt time.Time) (*models.Hold, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var bookID string
+	err = tx.QueryRow("SELECT book_id FROM holds WHERE id = $1", id).Scan(&bookID)
+	if err == sql.ErrNoRows {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hold: %w", err)
+	}
+	if err := lockHoldBook(tx, bookID, false); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query("SELECT id FROM holds WHERE book_id = $1 AND status = 'waiting' ORDER BY queue_rank FOR UPDATE", bookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock holds: %w", err)
+	}
+	queue := []string{}
+	for rows.Next() {
+		var queued string
+		if err := rows.Scan(&queued); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		queue = append(queue, queued)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock holds: %w", err)
+	}
+
+	queue, ok := moveInQueue(queue, id, position)
+	if !ok {
+		return nil, ErrHoldNotWaiting
+	}
+
+	_, err = tx.Exec(`UPDATE holds SET queue_rank = o.rank
+			  FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, rank) WHERE holds.id = o.id`, pq.Array(queue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder holds: %w", err)
+	}
+
+	var hold models.Hold
+	err = scanHold(tx.QueryRow("UPDATE holds h SET updated_at = $1 WHERE h.id = $2 RETURNING "+holdColumns, updatedAt, id), &hold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to move hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit hold: %w", err)
+	}
+	return &hold, nil
+}
+
+// ExpirePickups expires each hold in a transaction of its own, so that a
+// long list does not hold up the desks.
+func (r *PostgresHoldRepository) ExpirePickups(expiredAt time.Time, pickup HoldPickup) (int64, error) {
+	rows, err := r.db.Query("SELECT id FROM holds WHERE status = 'ready' AND pickup_by < $1 ORDER BY pickup_by, id", pickup.Today)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to fetch holds: %w", err)
+	}
+
+	var expired int64
+	for _, id := range ids {
+		ok, err := r.expire(id, expiredAt, pickup)
+		if err != nil {
+			return expired, err
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// expire expires a ready hold whose pickup date has passed and reports
+// whether it did; the hold may have been picked up in the meantime.
+func (r *PostgresHoldRepository) expire(id string, expiredAt time.Time, pickup HoldPickup) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	hold, err := lockHold(tx, id)
+	if err != nil {
+		return false, err
+	}
+	if hold.Status != models.HoldReady || *hold.PickupBy >= pickup.Today {
+		return false, nil
+	}
+
+	if _, err := closeHold(tx, id, models.HoldExpired, expiredAt); err != nil {
+		return false, err
+	}
+	if _, err := trapNextHold(tx, *hold.ItemID, expiredAt, pickup, true); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit hold: %w", err)
+	}
+	return true, nil
+}
+
+// notWaiting tells a missing hold from one that is not waiting.
+func (r *PostgresHoldRepository) notWaiting(id string) error {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM holds WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to fetch hold: %w", err)
+	}
+	if !exists {
+		return ErrHoldNotFound
+	}
+	return ErrHoldNotWaiting
+}
+
+// lockHold locks a hold, after the copy it names or has trapped.
+func lockHold(tx *sql.Tx, id string) (*models.Hold, error) {
+	var itemID *string
+	err := tx.QueryRow("SELECT item_id FROM holds WHERE id = $1", id).Scan(&itemID)
+	if err == sql.ErrNoRows {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hold: %w", err)
+	}
+	if itemID != nil {
+		var exists bool
+		if err := tx.QueryRow("SELECT TRUE FROM items WHERE id = $1 FOR UPDATE", *itemID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to lock item: %w", err)
+		}
+	}
+
+	var hold models.Hold
+	err = scanHold(tx.QueryRow("SELECT "+holdColumns+" FROM holds h WHERE h.id = $1 FOR UPDATE OF h", id), &hold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock hold: %w", err)
+	}
+	return &hold, nil
+}
+
+// closeHold ends a hold as fulfilled, cancelled or expired.
+func closeHold(tx *sql.Tx, id, status string, closedAt time.Time) (*models.Hold, error) {
+	var hold models.Hold
+	err := scanHold(tx.QueryRow("UPDATE holds h SET status = $1, closed_at = $2, updated_at = $2 WHERE h.id = $3 RETURNING "+holdColumns,
+		status, closedAt, id), &hold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close hold: %w", err)
+	}
+	return &hold, nil
+}
+
+// trapNextHold traps a copy that has come back for the first waiting hold
+// of its book that it can fill, passing over suspended holds, and puts the
+// copy on the hold shelf. With no such hold the copy becomes available. A
+// copy at one branch that is picked up at another, or that belongs to
+// another when sendHome is set and no hold takes it, is shipped there
+// instead; its hold is in transit until the transfer is received, and
+// transfers requested for the copy are cancelled. A copy that is already
+// available and stays so is left alone. The caller must hold the lock on
+// the copy.
+func trapNextHold(tx *sql.Tx, itemID string, trappedAt time.Time, pickup HoldPickup, sendHome bool) (*models.Hold, error) {
+	var current string
+	var homeBranchID, currentBranchID *string
+	err := tx.QueryRow("SELECT status, home_branch_id, current_branch_id FROM items WHERE id = $1", itemID).
+		Scan(&current, &homeBranchID, &currentBranchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+
+	var id string
+	var pickupBranchID *string
+	err = tx.QueryRow(`SELECT h.id, h.pickup_branch_id FROM holds h JOIN items i ON i.book_id = h.book_id
+			  WHERE i.id = $1 AND h.status = 'waiting' AND (h.item_id IS NULL OR h.item_id = i.id)
+			  AND (h.suspended_until IS NULL OR h.suspended_until < $2)
+			  ORDER BY h.queue_rank LIMIT 1 FOR UPDATE OF h`, itemID, pickup.Today).Scan(&id, &pickupBranchID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to find next hold: %w", err)
+	}
+	found := err == nil
+
+	destination := currentBranchID
+	if found && pickupBranchID != nil {
+		destination = pickupBranchID
+	} else if !found && sendHome && homeBranchID != nil {
+		destination = homeBranchID
+	}
+	shipped := currentBranchID != nil && destination != nil && *destination != *currentBranchID
+	if !found && !shipped && current == models.ItemAvailable {
+		return nil, nil
+	}
+
+	status := models.ItemAvailable
+	var hold *models.Hold
+	if found {
+		holdStatus := models.HoldInTransit
+		var readyAt *time.Time
+		var pickupBy *string
+		if !shipped {
+			pickupOn := pickup.at(currentBranchID)
+			holdStatus, readyAt, pickupBy = models.HoldReady, &trappedAt, &pickupOn
+			status = models.ItemOnHold
+		}
+
+		hold = &models.Hold{}
+		err = scanHold(tx.QueryRow(`UPDATE holds h SET status = $1, item_id = $2, ready_at = $3, pickup_by = $4, updated_at = $5
+				  WHERE h.id = $6 RETURNING `+holdColumns, holdStatus, itemID, readyAt, pickupBy, trappedAt, id), hold)
+		if err != nil {
+			return nil, fmt.Errorf("failed to trap hold: %w", err)
+		}
+	}
+	if shipped {
+		var holdID *string
+		if hold != nil {
+			holdID = &hold.ID
+		}
+		_, err = tx.Exec(`UPDATE transfers SET status = $1, cancelled_at = $2, updated_at = $2
+				  WHERE item_id = $3 AND status = $4`, models.TransferCancelled, trappedAt, itemID, models.TransferRequested)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel requested transfers: %w", err)
+		}
+		_, err = tx.Exec(`INSERT INTO transfers (item_id, from_branch_id, to_branch_id, hold_id, status, requested_at, shipped_at,
+				  updated_at) VALUES ($1, $2, $3, $4, $5, $6, $6, $6)`,
+			itemID, *currentBranchID, *destination, holdID, models.TransferInTransit, trappedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transfer: %w", err)
+		}
+		status = models.ItemInTransit
+	}
+
+	_, err = tx.Exec(`UPDATE items SET status = $1, status_note = NULL, status_changed_at = $2, updated_at = $2
+			  WHERE id = $3`, status, trappedAt, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item status: %w", err)
+	}
+	return hold, nil
+}
+
+// trapAvailable traps a copy that has just become available other than by
+// checkin or transfer, such as a new copy or one found again, for the first
+// waiting hold it can fill, and reads back the copy into item. The caller
+// must hold the lock on the copy.
+func trapAvailable(tx *sql.Tx, item *models.Item, trappedAt time.Time, pickup HoldPickup) error {
+	if _, err := trapNextHold(tx, item.ID, trappedAt, pickup, false); err != nil {
+		return err
+	}
+	if err := scanItem(tx.QueryRow("SELECT "+itemColumns+" FROM items WHERE id = $1", item.ID), item); err != nil {
+		return fmt.Errorf("failed to fetch item: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var holdRowColumns = []string{"id", "book_id", "member_id", "item_id", "level", "status", "pickup_branch_id", "position",
+	"suspended_until", "pickup_by", "placed_at", "ready_at", "closed_at", "updated_at"}
+
+func TestPostgresHoldRepository_Place(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresHoldRepository(db)
+	now := time.Now()
+	lockBook := regexp.QuoteMeta("SELECT TRUE FROM books WHERE id = $1 AND deleted_at IS NULL FOR UPDATE")
+	countItems := regexp.QuoteMeta("SELECT COUNT(*), COUNT(*) FILTER (WHERE status = 'available'), COUNT(*) FILTER (WHERE status <> 'withdrawn') FROM items WHERE book_id = $1")
+	insertHold := regexp.QuoteMeta("INSERT INTO holds (id, book_id, member_id, item_id, level, status, pickup_branch_id, queue_rank, placed_at,")
+
+	t.Run("success", func(t *testing.T) {
+		hold := &models.Hold{ID: "h1", BookID: "b1", MemberID: "m1", Level: "title", Status: "waiting", PlacedAt: now, UpdatedAt: now}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockBook).WithArgs("b1").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(countItems).WithArgs("b1").
+			WillReturnRows(sqlmock.NewRows([]string{"total", "available", "holdable"}).AddRow(2, 0, 2))
+		mock.ExpectExec(insertHold).
+			WithArgs("h1", "b1", "m1", nil, "title", "waiting", nil, now, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM holds WHERE book_id = $1 AND status = 'waiting'")).
+			WithArgs("b1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Place(hold))
+		assert.Equal(t, 3, hold.Position)
+	})
+
+	t.Run("copy available", func(t *testing.T) {
+		itemID := "i1"
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockBook).WithArgs("b1").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(countItems+regexp.QuoteMeta(" AND id = $2")).WithArgs("b1", "i1").
+			WillReturnRows(sqlmock.NewRows([]string{"total", "available", "holdable"}).AddRow(1, 1, 1))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Place(&models.Hold{BookID: "b1", MemberID: "m1", ItemID: &itemID}), ErrCopyAvailable)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockBook).WithArgs("b1").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(countItems).WithArgs("b1").
+			WillReturnRows(sqlmock.NewRows([]string{"total", "available", "holdable"}).AddRow(2, 0, 2))
+		mock.ExpectExec(insertHold).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: holdActiveMemberIndex})
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Place(&models.Hold{BookID: "b1", MemberID: "m1"}), ErrDuplicateHold)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresHoldRepository_Suspend(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresHoldRepository(db)
+	now := time.Now()
+	until := "2026-03-05"
+	suspend := regexp.QuoteMeta("UPDATE holds h SET suspended_until = $1, updated_at = $2 WHERE h.id = $3 AND h.status = 'waiting' RETURNING")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(suspend).
+			WithArgs(&until, now, "h1").
+			WillReturnRows(sqlmock.NewRows(holdRowColumns).
+				AddRow("h1", "b1", "m1", nil, "title", "waiting", nil, 2, until, nil, now, nil, nil, now))
+
+		hold, err := repo.Suspend("h1", &until, now)
+		require.NoError(t, err)
+		assert.Equal(t, 2, hold.Position)
+		assert.Equal(t, until, *hold.SuspendedUntil)
+	})
+
+	t.Run("not waiting", func(t *testing.T) {
+		mock.ExpectQuery(suspend).WillReturnRows(sqlmock.NewRows(holdRowColumns))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM holds WHERE id = $1)")).
+			WithArgs("h2").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		_, err := repo.Suspend("h2", nil, now)
+		assert.ErrorIs(t, err, ErrHoldNotWaiting)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLoanRepository_CheckinTrapsHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresLoanRepository(db)
+	now := time.Now()
+	pickup := HoldPickup{Today: "2026-03-01", PickupBy: "2026-03-08"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TRUE FROM items WHERE id = $1 FOR UPDATE")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE l.item_id = $1 AND l.returned_at IS NULL FOR UPDATE OF l")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows(loanRowColumns).
+			AddRow("l1", "i1", "b1", "E-1", "m1", nil, "2026-02-20", 0, now, nil, nil, nil, nil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE loans SET returned_at = $1 WHERE id = $2")).
+		WithArgs(now, "l1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status, home_branch_id, current_branch_id FROM items WHERE id = $1")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "home_branch_id", "current_branch_id"}).AddRow("on_loan", nil, nil))
+	mock.ExpectQuery(regexp.QuoteMeta("AND (h.suspended_until IS NULL OR h.suspended_until < $2) ORDER BY h.queue_rank LIMIT 1 FOR UPDATE OF h")).
+		WithArgs("i1", "2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pickup_branch_id"}).AddRow("h1", nil))
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE holds h SET status = $1, item_id = $2, ready_at = $3, pickup_by = $4, updated_at = $5 WHERE h.id = $6 RETURNING")).
+		WithArgs("ready", "i1", now, "2026-03-08", now, "h1").
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow("h1", "b1", "m2", "i1", "title", "ready", nil, 0, nil, "2026-03-08", now, now, nil, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET status = $1, status_note = NULL, status_changed_at = $2, updated_at = $2 WHERE id = $3")).
+		WithArgs("on_hold", now, "i1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	loan, hold, err := repo.Checkin("i1", nil, now, pickup)
+	require.NoError(t, err)
+	assert.NotNil(t, loan.ReturnedAt)
+	require.NotNil(t, hold)
+	assert.Equal(t, "m2", hold.MemberID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLoanRepository_CheckinShipsToPickupBranch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresLoanRepository(db)
+	now := time.Now()
+	pickup := HoldPickup{Today: "2026-03-01", PickupBy: "2026-03-08"}
+	branch := "br-north"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT TRUE FROM items WHERE id = $1 FOR UPDATE")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE l.item_id = $1 AND l.returned_at IS NULL FOR UPDATE OF l")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows(loanRowColumns).
+			AddRow("l1", "i1", "b1", "E-1", "m1", nil, "2026-02-20", 0, now, nil, nil, nil, nil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE loans SET returned_at = $1 WHERE id = $2")).
+		WithArgs(now, "l1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET current_branch_id = $1 WHERE id = $2")).
+		WithArgs(branch, "i1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status, home_branch_id, current_branch_id FROM items WHERE id = $1")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "home_branch_id", "current_branch_id"}).AddRow("on_loan", "br-main", branch))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY h.queue_rank LIMIT 1 FOR UPDATE OF h")).
+		WithArgs("i1", "2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pickup_branch_id"}).AddRow("h1", "br-south"))
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE holds h SET status = $1, item_id = $2, ready_at = $3, pickup_by = $4, updated_at = $5 WHERE h.id = $6 RETURNING")).
+		WithArgs("in_transit", "i1", nil, nil, now, "h1").
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow("h1", "b1", "m2", "i1", "title", "in_transit", "br-south", 0, nil, nil, now, nil, nil, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE transfers SET status = $1, cancelled_at = $2, updated_at = $2 WHERE item_id = $3 AND status = $4")).
+		WithArgs("cancelled", now, "i1", "requested").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO transfers (item_id, from_branch_id, to_branch_id, hold_id, status, requested_at, shipped_at,")).
+		WithArgs("i1", branch, "br-south", "h1", "in_transit", now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET status = $1, status_note = NULL, status_changed_at = $2, updated_at = $2 WHERE id = $3")).
+		WithArgs("in_transit", now, "i1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, hold, err := repo.Checkin("i1", &branch, now, pickup)
+	require.NoError(t, err)
+	require.NotNil(t, hold)
+	assert.Equal(t, models.HoldInTransit, hold.Status)
+	assert.Equal(t, "br-south", *hold.PickupBranchID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
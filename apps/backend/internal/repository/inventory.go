@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrInventoryNotFound = errors.New("inventory session not found")
+	// ErrInventoryClosed is returned when scanning into or closing a session
+	// that has been closed.
+	ErrInventoryClosed = errors.New("inventory session is closed")
+)
+
+// InventoryRepository keeps stocktake sessions and the barcodes scanned in
+// them.
+type InventoryRepository interface {
+	// Open records a new open session, returning ErrBranchNotFound when its
+	// branch does not exist.
+	Open(session *models.InventorySession) error
+	GetByID(id string) (*models.InventorySession, error)
+	// List expects a normalized Limit.
+	List(params *models.InventoryListParams) (*models.InventoryListResponse, error)
+	// Scan records barcodes in an open session, keeping the first scan of
+	// each, and returns how many were new to the session.
+	Scan(id string, barcodes []string, scannedAt time.Time) (int, error)
+	// Close closes an open session.
+	Close(id string, closedAt time.Time) (*models.InventorySession, error)
+	// Report compares the barcodes scanned in a session with the copies it
+	// covers; see models.InventoryReport. Copies are listed by location and
+	// barcode, unknown barcodes in the order they were scanned.
+	Report(id string) (*models.InventoryReport, error)
+}
+
+// fileInventoryScan adds a copy scanned in a session to the part of its
+// report it belongs in, if any.
+func fileInventoryScan(report *models.InventoryReport, item models.Item) {
+	switch {
+	case item.Status == models.ItemLost:
+		report.Found = append(report.Found, item)
+	case !report.Session.Covers(&item):
+		report.WrongLocation = append(report.WrongLocation, item)
+	}
+}
+
+// isExpectedOnShelf reports whether a session expects to scan a copy.
+func isExpectedOnShelf(session *models.InventorySession, item *models.Item) bool {
+	return item.Status == models.ItemAvailable && session.Covers(item)
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryInventoryRepository keeps sessions and their scans in maps guarded
+// by a RWMutex. It reports on the copies of a MemoryItemRepository, whose
+// lock is always taken after its own, and keeps the branches of a
+// MemoryBranchRepository with sessions from being deleted.
+type MemoryInventoryRepository struct {
+	mu       sync.RWMutex
+	items    *MemoryItemRepository
+	sessions map[string]models.InventorySession
+	// scans holds the barcodes scanned in each session in the order they
+	// were first scanned, and scanned holds them as a set.
+	scans   map[string][]string
+	scanned map[string]map[string]bool
+}
+
+func NewMemoryInventoryRepository(items *MemoryItemRepository, branches *MemoryBranchRepository) *MemoryInventoryRepository {
+	r := &MemoryInventoryRepository{
+		items:    items,
+		sessions: make(map[string]models.InventorySession),
+		scans:    make(map[string][]string),
+		scanned:  make(map[string]map[string]bool),
+	}
+	branches.inventory = r
+	return r
+}
+
+func (r *MemoryInventoryRepository) Open(session *models.InventorySession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[session.ID] = *session
+	r.scanned[session.ID] = make(map[string]bool)
+	return nil
+}
+
+func (r *MemoryInventoryRepository) GetByID(id string) (*models.InventorySession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrInventoryNotFound
+	}
+	return r.sessionView(session), nil
+}
+
+func (r *MemoryInventoryRepository) List(params *models.InventoryListParams) (*models.InventoryListResponse, error) {
+	r.mu.RLock()
+	sessions := []models.InventorySession{}
+	for _, session := range r.sessions {
+		if params.Status != "" && session.Status != params.Status {
+			continue
+		}
+		if params.BranchID != "" && !isBranch(session.BranchID, params.BranchID) {
+			continue
+		}
+		sessions = append(sessions, *r.sessionView(session))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].OpenedAt.Equal(sessions[j].OpenedAt) {
+			return sessions[i].OpenedAt.After(sessions[j].OpenedAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+
+	response := &models.InventoryListResponse{Total: len(sessions)}
+	start := params.Offset
+	if start > len(sessions) {
+		start = len(sessions)
+	}
+	end := start + params.Limit
+	if end > len(sessions) {
+		end = len(sessions)
+	}
+	response.Data = sessions[start:end]
+
+	return response, nil
+}
+
+func (r *MemoryInventoryRepository) Scan(id string, barcodes []string, scannedAt time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return 0, ErrInventoryNotFound
+	}
+	if session.Status != models.InventoryOpen {
+		return 0, ErrInventoryClosed
+	}
+
+	recorded := 0
+	for _, barcode := range barcodes {
+		if r.scanned[id][barcode] {
+			continue
+		}
+		r.scanned[id][barcode] = true
+		r.scans[id] = append(r.scans[id], barcode)
+		recorded++
+	}
+	session.UpdatedAt = scannedAt
+	r.sessions[id] = session
+	return recorded, nil
+}
+
+func (r *MemoryInventoryRepository) Close(id string, closedAt time.Time) (*models.InventorySession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrInventoryNotFound
+	}
+	if session.Status != models.InventoryOpen {
+		return nil, ErrInventoryClosed
+	}
+
+	session.Status = models.InventoryClosed
+	session.ClosedAt = &closedAt
+	session.UpdatedAt = closedAt
+	r.sessions[id] = session
+	return r.sessionView(session), nil
+}
+
+func (r *MemoryInventoryRepository) Report(id string) (*models.InventoryReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.items.mu.RLock()
+	defer r.items.mu.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, ErrInventoryNotFound
+	}
+	report := &models.InventoryReport{
+		Session:         r.sessionView(session),
+		Missing:         []models.Item{},
+		WrongLocation:   []models.Item{},
+		Found:           []models.Item{},
+		UnknownBarcodes: []string{},
+	}
+
+	for _, item := range r.items.items {
+		if !isExpectedOnShelf(report.Session, &item) {
+			continue
+		}
+		report.Expected++
+		if !r.scanned[id][item.Barcode] {
+			report.Missing = append(report.Missing, item)
+		}
+	}
+	seen := []models.Item{}
+	for _, barcode := range r.scans[id] {
+		item := r.items.findByBarcode(barcode)
+		if item == nil {
+			report.UnknownBarcodes = append(report.UnknownBarcodes, barcode)
+			continue
+		}
+		seen = append(seen, *item)
+	}
+	sortByShelf(report.Missing)
+	sortByShelf(seen)
+	for _, item := range seen {
+		fileInventoryScan(report, item)
+	}
+
+	return report, nil
+}
+
+// hasBranch reports whether a session takes stock of a branch.
+func (r *MemoryInventoryRepository) hasBranch(branchID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, session := range r.sessions {
+		if isBranch(session.BranchID, branchID) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionView returns a session with its scan count. Callers must hold the
+// lock.
+func (r *MemoryInventoryRepository) sessionView(session models.InventorySession) *models.InventorySession {
+	session.Scanned = len(r.scans[session.ID])
+	return &session
+}
+
+// sortByShelf orders copies by location, copies without one last, and then
+// by barcode.
+func sortByShelf(items []models.Item) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i].Location, items[j].Location
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		if a != nil && *a != *b {
+			return *a < *b
+		}
+		return items[i].Barcode < items[j].Barcode
+	})
+}
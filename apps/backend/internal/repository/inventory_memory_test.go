@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryInventorySession(branchID, from, to *string, openedAt time.Time) *models.InventorySession {
+	return &models.InventorySession{
+		ID:           uuid.New().String(),
+		BranchID:     branchID,
+		LocationFrom: from,
+		LocationTo:   to,
+		Status:       models.InventoryOpen,
+		OpenedAt:     openedAt,
+		UpdatedAt:    openedAt,
+	}
+}
+
+func TestMemoryInventoryRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	loans := NewMemoryLoanRepository(items, NewMemoryMemberRepository())
+	branches := NewMemoryBranchRepository(loans)
+	repo := NewMemoryInventoryRepository(items, branches)
+
+	main := newMemoryBranch("MAIN", "Main Library")
+	require.NoError(t, branches.Create(main))
+	north := newMemoryBranch("NORTH", "North Branch")
+	require.NoError(t, branches.Create(north))
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	shelve := func(barcode, location, branchID, status string) *models.Item {
+		item := newMemoryItem(book.ID, barcode)
+		item.Location = &location
+		item.HomeBranchID = &branchID
+		item.CurrentBranchID = &branchID
+		item.Status = status
+		require.NoError(t, items.Create(item, HoldPickup{}))
+		return item
+	}
+	onShelf := shelve("E-1", "PR 4034", main.ID, models.ItemAvailable)
+	missing := shelve("E-2", "PR 4036", main.ID, models.ItemAvailable)
+	shelve("E-3", "PR 4038", main.ID, models.ItemOnLoan)
+	lost := shelve("E-4", "PR 4034", main.ID, models.ItemLost)
+	misplaced := shelve("E-5", "PR 4034", north.ID, models.ItemAvailable)
+	shelve("E-6", "PS 1000", main.ID, models.ItemAvailable)
+
+	from, to := "PR", "PR 9999"
+	session := newMemoryInventorySession(&main.ID, &from, &to, time.Now())
+	require.NoError(t, repo.Open(session))
+	assert.ErrorIs(t, branches.Delete(main.ID), ErrBranchInUse)
+
+	recorded, err := repo.Scan(session.ID, []string{"E-1", "E-4", "E-5", "X-9", "E-1"}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 4, recorded)
+	recorded, err = repo.Scan(session.ID, []string{"E-5"}, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, recorded)
+
+	report, err := repo.Report(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Session.Scanned)
+	assert.Equal(t, 2, report.Expected)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, missing.ID, report.Missing[0].ID)
+	require.Len(t, report.WrongLocation, 1)
+	assert.Equal(t, misplaced.ID, report.WrongLocation[0].ID)
+	require.Len(t, report.Found, 1)
+	assert.Equal(t, lost.ID, report.Found[0].ID)
+	assert.Equal(t, []string{"X-9"}, report.UnknownBarcodes)
+
+	closed, err := repo.Close(session.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, models.InventoryClosed, closed.Status)
+	_, err = repo.Close(session.ID, time.Now())
+	assert.ErrorIs(t, err, ErrInventoryClosed)
+	_, err = repo.Scan(session.ID, []string{onShelf.Barcode}, time.Now())
+	assert.ErrorIs(t, err, ErrInventoryClosed)
+
+	whole := newMemoryInventorySession(nil, nil, nil, time.Now().Add(time.Hour))
+	require.NoError(t, repo.Open(whole))
+	report, err = repo.Report(whole.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Expected)
+	assert.Len(t, report.Missing, 4)
+
+	open, err := repo.List(&models.InventoryListParams{Status: models.InventoryOpen, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, open.Total)
+	assert.Equal(t, whole.ID, open.Data[0].ID)
+	atMain, err := repo.List(&models.InventoryListParams{BranchID: main.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, atMain.Total)
+
+	_, err = repo.GetByID(uuid.New().String())
+	assert.ErrorIs(t, err, ErrInventoryNotFound)
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	inventoryBranchFKey = "inventory_sessions_branch_id_fkey"
+
+	inventoryColumns = `s.id, s.branch_id, s.location_from, s.location_to, s.status, s.note,
+			  (SELECT COUNT(*) FROM inventory_scans c WHERE c.session_id = s.id) AS scanned,
+			  s.opened_at, s.closed_at, s.updated_at`
+	inventoryScanned = "SELECT barcode FROM inventory_scans WHERE session_id = $%d"
+	itemShelfOrder   = ` ORDER BY location COLLATE "C", barcode`
+)
+
+type PostgresInventoryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresInventoryRepository(db *sql.DB) *PostgresInventoryRepository {
+	return &PostgresInventoryRepository{db: db}
+}
+
+func scanInventorySession(scanner interface{ Scan(...interface{}) error }, session *models.InventorySession, extra ...interface{}) error {
+	dest := []interface{}{&session.ID, &session.BranchID, &session.LocationFrom, &session.LocationTo, &session.Status,
+		&session.Note, &session.Scanned, &session.OpenedAt, &session.ClosedAt, &session.UpdatedAt}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+func (r *PostgresInventoryRepository) Open(session *models.InventorySession) error {
+	_, err := r.db.Exec(`INSERT INTO inventory_sessions (id, branch_id, location_from, location_to, status, note, opened_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		session.ID, session.BranchID, session.LocationFrom, session.LocationTo, session.Status, session.Note,
+		session.OpenedAt, session.UpdatedAt)
+	if isForeignKeyViolation(err, inventoryBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open inventory session: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresInventoryRepository) GetByID(id string) (*models.InventorySession, error) {
+	var session models.InventorySession
+	err := scanInventorySession(r.db.QueryRow("SELECT "+inventoryColumns+" FROM inventory_sessions s WHERE s.id = $1", id), &session)
+	if err == sql.ErrNoRows {
+		return nil, ErrInventoryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch inventory session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *PostgresInventoryRepository) List(params *models.InventoryListParams) (*models.InventoryListResponse, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("s.status = $%d", len(args)))
+	}
+	if params.BranchID != "" {
+		args = append(args, params.BranchID)
+		conditions = append(conditions, fmt.Sprintf("s.branch_id = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER () AS total FROM inventory_sessions s%s ORDER BY s.opened_at DESC, s.id LIMIT $%d OFFSET $%d",
+		inventoryColumns, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch inventory sessions: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.InventoryListResponse{Data: make([]models.InventorySession, 0, params.Limit)}
+	for rows.Next() {
+		var session models.InventorySession
+		if err := scanInventorySession(rows, &session, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan inventory session: %w", err)
+		}
+		response.Data = append(response.Data, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch inventory sessions: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresInventoryRepository) Scan(id string, barcodes []string, scannedAt time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM inventory_sessions WHERE id = $1 FOR UPDATE", id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return 0, ErrInventoryNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock inventory session: %w", err)
+	}
+	if status != models.InventoryOpen {
+		return 0, ErrInventoryClosed
+	}
+
+	result, err := tx.Exec(`INSERT INTO inventory_scans (session_id, barcode, scanned_at)
+			  SELECT DISTINCT $1::uuid, barcode, $3::timestamptz FROM unnest($2::text[]) AS barcode
+			  ON CONFLICT (session_id, barcode) DO NOTHING`, id, pq.Array(barcodes), scannedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record scans: %w", err)
+	}
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count scans: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE inventory_sessions SET updated_at = $1 WHERE id = $2", scannedAt, id); err != nil {
+		return 0, fmt.Errorf("failed to update inventory session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit scans: %w", err)
+	}
+	return int(recorded), nil
+}
+
+func (r *PostgresInventoryRepository) Close(id string, closedAt time.Time) (*models.InventorySession, error) {
+	var session models.InventorySession
+	err := scanInventorySession(r.db.QueryRow(`UPDATE inventory_sessions s SET status = $1, closed_at = $2, updated_at = $2
+			  WHERE s.id = $3 AND s.status = $4 RETURNING `+inventoryColumns,
+		models.InventoryClosed, closedAt, id, models.InventoryOpen), &session)
+	if err == sql.ErrNoRows {
+		if _, err := r.GetByID(id); err != nil {
+			return nil, err
+		}
+		return nil, ErrInventoryClosed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to close inventory session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *PostgresInventoryRepository) Report(id string) (*models.InventoryReport, error) {
+	session, err := r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	report := &models.InventoryReport{
+		Session:         session,
+		Missing:         []models.Item{},
+		WrongLocation:   []models.Item{},
+		Found:           []models.Item{},
+		UnknownBarcodes: []string{},
+	}
+
+	conditions, args := inventoryScope(session)
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM items"+whereClause(conditions), args...).Scan(&report.Expected); err != nil {
+		return nil, fmt.Errorf("failed to count expected items: %w", err)
+	}
+	args = append(args, id)
+	conditions = append(conditions, "barcode NOT IN ("+fmt.Sprintf(inventoryScanned, len(args))+")")
+	report.Missing, err = r.queryItems("SELECT "+itemColumns+" FROM items"+whereClause(conditions)+itemShelfOrder, args...)
+	if err != nil {
+		return nil, err
+	}
+	seen, err := r.queryItems("SELECT "+itemColumns+" FROM items WHERE barcode IN ("+fmt.Sprintf(inventoryScanned, 1)+")"+itemShelfOrder, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range seen {
+		fileInventoryScan(report, item)
+	}
+
+	rows, err := r.db.Query(`SELECT s.barcode FROM inventory_scans s WHERE s.session_id = $1
+			  AND NOT EXISTS (SELECT 1 FROM items i WHERE i.barcode = s.barcode) ORDER BY s.scanned_at, s.barcode`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unknown barcodes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var barcode string
+		if err := rows.Scan(&barcode); err != nil {
+			return nil, fmt.Errorf("failed to scan barcode: %w", err)
+		}
+		report.UnknownBarcodes = append(report.UnknownBarcodes, barcode)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch unknown barcodes: %w", err)
+	}
+
+	return report, nil
+}
+
+func (r *PostgresInventoryRepository) queryItems(query string, args ...interface{}) ([]models.Item, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.Item{}
+	for rows.Next() {
+		var item models.Item
+		if err := scanItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+	return items, nil
+}
+
+// inventoryScope returns the conditions matching the copies a session
+// expects on the shelf, as isExpectedOnShelf does.
+func inventoryScope(session *models.InventorySession) ([]string, []interface{}) {
+	args := []interface{}{models.ItemAvailable}
+	conditions := []string{"status = $1"}
+	if session.BranchID != nil {
+		args = append(args, *session.BranchID)
+		conditions = append(conditions, fmt.Sprintf("current_branch_id = $%d", len(args)))
+	}
+	if session.LocationFrom != nil {
+		args = append(args, *session.LocationFrom)
+		conditions = append(conditions, fmt.Sprintf(`location COLLATE "C" >= $%d`, len(args)))
+	}
+	if session.LocationTo != nil {
+		args = append(args, *session.LocationTo)
+		conditions = append(conditions, fmt.Sprintf(`location COLLATE "C" <= $%d`, len(args)))
+	}
+	return conditions, args
+}
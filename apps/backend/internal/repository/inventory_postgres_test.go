@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var inventoryRowColumns = []string{"id", "branch_id", "location_from", "location_to", "status", "note", "scanned",
+	"opened_at", "closed_at", "updated_at"}
+
+func TestPostgresInventoryRepository_Scan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresInventoryRepository(db)
+	now := time.Now()
+	lock := regexp.QuoteMeta("SELECT status FROM inventory_sessions WHERE id = $1 FOR UPDATE")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("s1").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("open"))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO inventory_scans (session_id, barcode, scanned_at)")).
+			WithArgs("s1", pq.Array([]string{"E-1", "E-2"}), now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE inventory_sessions SET updated_at = $1 WHERE id = $2")).
+			WithArgs(now, "s1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		recorded, err := repo.Scan("s1", []string{"E-1", "E-2"}, now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, recorded)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("s1").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("closed"))
+		mock.ExpectRollback()
+
+		_, err := repo.Scan("s1", []string{"E-1"}, now)
+		assert.ErrorIs(t, err, ErrInventoryClosed)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresInventoryRepository_Report(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresInventoryRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM inventory_sessions s WHERE s.id = $1")).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows(inventoryRowColumns).
+			AddRow("s1", "br-main", "PR", "PR 9999", "closed", nil, 4, now, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(*) FROM items WHERE status = $1 AND current_branch_id = $2 AND location COLLATE "C" >= $3 AND location COLLATE "C" <= $4`)).
+		WithArgs("available", "br-main", "PR", "PR 9999").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta(`AND barcode NOT IN (SELECT barcode FROM inventory_scans WHERE session_id = $5) ORDER BY location COLLATE "C", barcode`)).
+		WithArgs("available", "br-main", "PR", "PR 9999", "s1").
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow("i2", "b1", "E-2", "PR 4036", "br-main", "br-main", nil, nil, "good", "available", nil, now, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM items WHERE barcode IN (SELECT barcode FROM inventory_scans WHERE session_id = $1)")).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow("i1", "b1", "E-1", "PR 4034", "br-main", "br-main", nil, nil, "good", "available", nil, now, now, now).
+			AddRow("i4", "b1", "E-4", "PR 4034", "br-main", "br-main", nil, nil, "good", "lost", nil, now, now, now).
+			AddRow("i5", "b1", "E-5", "PR 4034", "br-north", "br-north", nil, nil, "good", "available", nil, now, now, now))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT s.barcode FROM inventory_scans s WHERE s.session_id = $1")).
+		WithArgs("s1").
+		WillReturnRows(sqlmock.NewRows([]string{"barcode"}).AddRow("X-9"))
+
+	report, err := repo.Report("s1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Expected)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "i2", report.Missing[0].ID)
+	require.Len(t, report.Found, 1)
+	assert.Equal(t, "i4", report.Found[0].ID)
+	require.Len(t, report.WrongLocation, 1)
+	assert.Equal(t, "i5", report.WrongLocation[0].ID)
+	assert.Equal(t, []string{"X-9"}, report.UnknownBarcodes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrItemNotFound = errors.New("item not found")
+	// ErrDuplicateBarcode is returned when a copy would get the barcode of
+	// another copy.
+	ErrDuplicateBarcode = errors.New("an item with this barcode already exists")
+	// ErrInvalidItemTransition is returned when a copy cannot change from its
+	// current status to the requested one.
+	ErrInvalidItemTransition = errors.New("invalid item status change")
+)
+
+// ItemRepository persists the physical copies of books.
+type ItemRepository interface {
+	// ListByBook returns the copies of a book ordered by barcode, only those
+	// at a branch when branchID is set.
+	ListByBook(bookID, branchID string) ([]models.Item, error)
+	GetByID(id string) (*models.Item, error)
+	GetByBarcode(barcode string) (*models.Item, error)
+	// Create adds a copy, returning ErrBookNotFound when the book does not
+	// exist. An available copy is trapped for the first waiting hold it can
+	// fill, and item is updated with its resulting status.
+	Create(item *models.Item, pickup HoldPickup) error
+	// Update writes the details and branches of a copy, leaving its status
+	// alone.
+	Update(item *models.Item) error
+	// SetStatus changes the status of a copy when models.CanTransitionItem
+	// allows it, checking the current status under a row lock, and returns
+	// the updated copy. A copy made available is trapped for the first
+	// waiting hold it can fill, like one created.
+	SetStatus(id, status string, note *string, changedAt time.Time, pickup HoldPickup) (*models.Item, error)
+	// Availability counts the copies of a book by status, only those at a
+	// branch when branchID is set.
+	Availability(bookID, branchID string) (*models.ItemAvailability, error)
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryItemRepository keeps copies in a map guarded by a RWMutex. It checks
+// books against a MemoryBookRepository, whose lock is always taken after its
+// own, and serves that repository's branch filter.
+type MemoryItemRepository struct {
+	mu    sync.RWMutex
+	books *MemoryBookRepository
+	items map[string]models.Item
+}
+
+func NewMemoryItemRepository(books *MemoryBookRepository) *MemoryItemRepository {
+	r := &MemoryItemRepository{
+		books: books,
+		items: make(map[string]models.Item),
+	}
+	books.items = r
+	return r
+}
+
+func (r *MemoryItemRepository) ListByBook(bookID, branchID string) ([]models.Item, error) {
+	r.mu.RLock()
+	items := []models.Item{}
+	for _, item := range r.items {
+		if item.BookID == bookID && atBranch(&item, branchID) {
+			items = append(items, item)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Barcode < items[j].Barcode
+	})
+	return items, nil
+}
+
+func (r *MemoryItemRepository) GetByID(id string) (*models.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+	return &item, nil
+}
+
+func (r *MemoryItemRepository) GetByBarcode(barcode string) (*models.Item, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if item := r.findByBarcode(barcode); item != nil {
+		return item, nil
+	}
+	return nil, ErrItemNotFound
+}
+
+func (r *MemoryItemRepository) Create(item *models.Item, pickup HoldPickup) error {
+	loans := r.books.loans
+	if loans != nil {
+		loans.mu.Lock()
+		defer loans.mu.Unlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.books.mu.RLock()
+	_, ok := r.books.books[item.BookID]
+	r.books.mu.RUnlock()
+	if !ok {
+		return ErrBookNotFound
+	}
+	if r.findByBarcode(item.Barcode) != nil {
+		return ErrDuplicateBarcode
+	}
+
+	r.items[item.ID] = *item
+	if loans != nil && item.Status == models.ItemAvailable {
+		loans.trapAvailable(item, item.CreatedAt, pickup)
+	}
+	return nil
+}
+
+func (r *MemoryItemRepository) Update(item *models.Item) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[item.ID]
+	if !ok {
+		return ErrItemNotFound
+	}
+	if other := r.findByBarcode(item.Barcode); other != nil && other.ID != item.ID {
+		return ErrDuplicateBarcode
+	}
+
+	existing.Barcode = item.Barcode
+	existing.Location = item.Location
+	existing.HomeBranchID = item.HomeBranchID
+	existing.CurrentBranchID = item.CurrentBranchID
+	existing.AcquiredOn = item.AcquiredOn
+	existing.PriceCents = item.PriceCents
+	existing.Condition = item.Condition
+	existing.UpdatedAt = item.UpdatedAt
+	r.items[item.ID] = existing
+	return nil
+}
+
+func (r *MemoryItemRepository) SetStatus(id, status string, note *string, changedAt time.Time, pickup HoldPickup) (*models.Item, error) {
+	loans := r.books.loans
+	if loans != nil {
+		loans.mu.Lock()
+		defer loans.mu.Unlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, ErrItemNotFound
+	}
+	if !models.CanTransitionItem(item.Status, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidItemTransition, item.Status, status)
+	}
+
+	item.Status = status
+	item.StatusNote = note
+	item.StatusChangedAt = changedAt
+	item.UpdatedAt = changedAt
+	r.items[id] = item
+	if loans != nil && status == models.ItemAvailable {
+		loans.trapAvailable(&item, changedAt, pickup)
+	}
+	return &item, nil
+}
+
+func (r *MemoryItemRepository) Availability(bookID, branchID string) (*models.ItemAvailability, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var availability models.ItemAvailability
+	for _, item := range r.items {
+		if item.BookID != bookID || !atBranch(&item, branchID) {
+			continue
+		}
+		switch item.Status {
+		case models.ItemAvailable:
+			availability.Available++
+		case models.ItemOnLoan:
+			availability.OnLoan++
+		case models.ItemOnHold:
+			availability.OnHold++
+		case models.ItemInTransit:
+			availability.InTransit++
+		case models.ItemLost:
+			availability.Lost++
+		case models.ItemWithdrawn:
+			availability.Withdrawn++
+		}
+		if item.Status != models.ItemWithdrawn {
+			availability.Total++
+		}
+	}
+	return &availability, nil
+}
+
+// booksAtBranch returns the IDs of the books with a copy at a branch that is
+// not withdrawn.
+func (r *MemoryItemRepository) booksAtBranch(branchID string) map[string]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	books := make(map[string]bool)
+	for _, item := range r.items {
+		if item.Status != models.ItemWithdrawn && atBranch(&item, branchID) {
+			books[item.BookID] = true
+		}
+	}
+	return books
+}
+
+// atBranch reports whether a copy is at a branch, or whether branchID is
+// unset.
+func atBranch(item *models.Item, branchID string) bool {
+	return branchID == "" || isBranch(item.CurrentBranchID, branchID)
+}
+
+// findByBarcode returns the copy with a barcode. Callers must hold the lock.
+func (r *MemoryItemRepository) findByBarcode(barcode string) *models.Item {
+	for _, item := range r.items {
+		if item.Barcode == barcode {
+			return &item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryItem(bookID, barcode string) *models.Item {
+	now := time.Now()
+	return &models.Item{
+		ID:              uuid.New().String(),
+		BookID:          bookID,
+		Barcode:         barcode,
+		Condition:       models.ItemConditionGood,
+		Status:          models.ItemAvailable,
+		StatusChangedAt: now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+func TestMemoryItemRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	repo := NewMemoryItemRepository(books)
+	book := newMemoryBook("Dune", "Frank Herbert", 1965, "Science Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+
+	second := newMemoryItem(book.ID, "B-0002")
+	require.NoError(t, repo.Create(second, HoldPickup{}))
+	first := newMemoryItem(book.ID, "B-0001")
+	require.NoError(t, repo.Create(first, HoldPickup{}))
+	assert.ErrorIs(t, repo.Create(newMemoryItem(book.ID, "B-0001"), HoldPickup{}), ErrDuplicateBarcode)
+	assert.ErrorIs(t, repo.Create(newMemoryItem(uuid.New().String(), "B-0003"), HoldPickup{}), ErrBookNotFound)
+
+	items, err := repo.ListByBook(book.ID, "")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "B-0001", items[0].Barcode)
+
+	found, err := repo.GetByBarcode("B-0002")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID)
+
+	second.Barcode = "B-0001"
+	assert.ErrorIs(t, repo.Update(second), ErrDuplicateBarcode)
+
+	changedAt := time.Now()
+	note := "Dropped in the bath"
+	lost, err := repo.SetStatus(second.ID, models.ItemLost, &note, changedAt, HoldPickup{})
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemLost, lost.Status)
+	assert.Equal(t, &note, lost.StatusNote)
+	assert.Equal(t, changedAt, lost.StatusChangedAt)
+
+	_, err = repo.SetStatus(second.ID, models.ItemOnLoan, nil, changedAt, HoldPickup{})
+	assert.ErrorIs(t, err, ErrInvalidItemTransition)
+	_, err = repo.SetStatus(second.ID, models.ItemWithdrawn, nil, changedAt, HoldPickup{})
+	require.NoError(t, err)
+	_, err = repo.SetStatus(second.ID, models.ItemAvailable, nil, changedAt, HoldPickup{})
+	assert.ErrorIs(t, err, ErrInvalidItemTransition)
+	_, err = repo.SetStatus(uuid.New().String(), models.ItemLost, nil, changedAt, HoldPickup{})
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	availability, err := repo.Availability(book.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAvailability{Total: 1, Available: 1, Withdrawn: 1}, *availability)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	itemBarcodeIndex      = "idx_items_barcode"
+	itemBookFKey          = "items_book_id_fkey"
+	itemHomeBranchFKey    = "items_home_branch_id_fkey"
+	itemCurrentBranchFKey = "items_current_branch_id_fkey"
+
+	itemColumns = `id, book_id, barcode, location, home_branch_id, current_branch_id,
+			  to_char(acquired_on, 'YYYY-MM-DD') AS acquired_on, price_cents,
+			  condition, status, status_note, status_changed_at, created_at, updated_at`
+)
+
+type PostgresItemRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresItemRepository(db *sql.DB) *PostgresItemRepository {
+	return &PostgresItemRepository{db: db}
+}
+
+func scanItem(scanner interface{ Scan(...interface{}) error }, item *models.Item) error {
+	return scanner.Scan(&item.ID, &item.BookID, &item.Barcode, &item.Location, &item.HomeBranchID, &item.CurrentBranchID,
+		&item.AcquiredOn, &item.PriceCents, &item.Condition, &item.Status, &item.StatusNote, &item.StatusChangedAt,
+		&item.CreatedAt, &item.UpdatedAt)
+}
+
+func (r *PostgresItemRepository) ListByBook(bookID, branchID string) ([]models.Item, error) {
+	conditions, args := itemBranchConditions(bookID, branchID)
+	rows, err := r.db.Query("SELECT "+itemColumns+" FROM items"+whereClause(conditions)+" ORDER BY barcode", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []models.Item{}
+	for rows.Next() {
+		var item models.Item
+		if err := scanItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan item: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r *PostgresItemRepository) GetByID(id string) (*models.Item, error) {
+	return r.getItem("id", id)
+}
+
+func (r *PostgresItemRepository) GetByBarcode(barcode string) (*models.Item, error) {
+	return r.getItem("barcode", barcode)
+}
+
+func (r *PostgresItemRepository) getItem(column, value string) (*models.Item, error) {
+	var item models.Item
+	err := scanItem(r.db.QueryRow("SELECT "+itemColumns+" FROM items WHERE "+column+" = $1", value), &item)
+	if err == sql.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r *PostgresItemRepository) Create(item *models.Item, pickup HoldPickup) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertItem(tx, item); err != nil {
+		return err
+	}
+	if item.Status == models.ItemAvailable {
+		if err := trapAvailable(tx, item, item.CreatedAt, pickup); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit item: %w", err)
+	}
+	return nil
+}
+
+// insertItem adds a copy through db or a transaction.
+func insertItem(e execer, item *models.Item) error {
+	_, err := e.Exec(`INSERT INTO items (id, book_id, barcode, location, home_branch_id, current_branch_id, acquired_on,
+			  price_cents, condition, status, status_note, status_changed_at, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		item.ID, item.BookID, item.Barcode, item.Location, item.HomeBranchID, item.CurrentBranchID, item.AcquiredOn,
+		item.PriceCents, item.Condition, item.Status, item.StatusNote, item.StatusChangedAt, item.CreatedAt, item.UpdatedAt)
+	if isUniqueViolation(err, itemBarcodeIndex) {
+		return ErrDuplicateBarcode
+	}
+	if isForeignKeyViolation(err, itemBookFKey) {
+		return ErrBookNotFound
+	}
+	if isItemBranchViolation(err) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresItemRepository) Update(item *models.Item) error {
+	result, err := r.db.Exec(`UPDATE items SET barcode = $1, location = $2, home_branch_id = $3, current_branch_id = $4,
+			  acquired_on = $5, price_cents = $6, condition = $7, updated_at = $8 WHERE id = $9`,
+		item.Barcode, item.Location, item.HomeBranchID, item.CurrentBranchID, item.AcquiredOn, item.PriceCents,
+		item.Condition, item.UpdatedAt, item.ID)
+	if isUniqueViolation(err, itemBarcodeIndex) {
+		return ErrDuplicateBarcode
+	}
+	if isItemBranchViolation(err) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresItemRepository) SetStatus(id, status string, note *string, changedAt time.Time, pickup HoldPickup) (*models.Item, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRow("SELECT status FROM items WHERE id = $1 FOR UPDATE", id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+	if !models.CanTransitionItem(current, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidItemTransition, current, status)
+	}
+
+	var item models.Item
+	err = scanItem(tx.QueryRow(`UPDATE items SET status = $1, status_note = $2, status_changed_at = $3, updated_at = $3
+			  WHERE id = $4 RETURNING `+itemColumns, status, note, changedAt, id), &item)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item status: %w", err)
+	}
+	if status == models.ItemAvailable {
+		if err := trapAvailable(tx, &item, changedAt, pickup); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit item status: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *PostgresItemRepository) Availability(bookID, branchID string) (*models.ItemAvailability, error) {
+	conditions, args := itemBranchConditions(bookID, branchID)
+	var availability models.ItemAvailability
+	err := r.db.QueryRow(`SELECT COUNT(*) FILTER (WHERE status <> 'withdrawn'),
+			  COUNT(*) FILTER (WHERE status = 'available'),
+			  COUNT(*) FILTER (WHERE status = 'on_loan'),
+			  COUNT(*) FILTER (WHERE status = 'on_hold'),
+			  COUNT(*) FILTER (WHERE status = 'in_transit'),
+			  COUNT(*) FILTER (WHERE status = 'lost'),
+			  COUNT(*) FILTER (WHERE status = 'withdrawn')
+			  FROM items`+whereClause(conditions), args...).
+		Scan(&availability.Total, &availability.Available, &availability.OnLoan, &availability.OnHold,
+			&availability.InTransit, &availability.Lost, &availability.Withdrawn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
+	}
+
+	return &availability, nil
+}
+
+// itemBranchConditions selects the copies of a book, only those at a branch
+// when branchID is set.
+func itemBranchConditions(bookID, branchID string) ([]string, []interface{}) {
+	conditions := []string{"book_id = $1"}
+	args := []interface{}{bookID}
+	if branchID != "" {
+		args = append(args, branchID)
+		conditions = append(conditions, "current_branch_id = $2")
+	}
+	return conditions, args
+}
+
+func isItemBranchViolation(err error) bool {
+	return isForeignKeyViolation(err, itemHomeBranchFKey) || isForeignKeyViolation(err, itemCurrentBranchFKey)
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var itemRowColumns = []string{"id", "book_id", "barcode", "location", "home_branch_id", "current_branch_id", "acquired_on",
+	"price_cents", "condition", "status", "status_note", "status_changed_at", "created_at", "updated_at"}
+
+func TestPostgresItemRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresItemRepository(db)
+	now := time.Now()
+	item := &models.Item{ID: "1", BookID: "b1", Barcode: "B-0001", Condition: models.ItemConditionGood,
+		Status: models.ItemAvailable, StatusChangedAt: now, CreatedAt: now, UpdatedAt: now}
+	insert := regexp.QuoteMeta("INSERT INTO items (id, book_id, barcode, location, home_branch_id, current_branch_id, acquired_on,")
+	pickup := HoldPickup{Today: "2024-03-01", PickupBy: "2024-03-08"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).
+		WithArgs("1", "b1", "B-0001", nil, nil, nil, nil, nil, "good", "available", nil, now, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectNoHoldTrapped(mock, "1")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, book_id, barcode")).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow("1", "b1", "B-0001", nil, nil, nil, nil, nil, "good", "available", nil, now, now, now))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.Create(item, pickup))
+	assert.Equal(t, models.ItemAvailable, item.Status)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_items_barcode"})
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.Create(item, pickup), ErrDuplicateBarcode)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "items_book_id_fkey"})
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.Create(item, pickup), ErrBookNotFound)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "items_home_branch_id_fkey"})
+	mock.ExpectRollback()
+	assert.ErrorIs(t, repo.Create(item, pickup), ErrBranchNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectNoHoldTrapped expects an available copy to be offered to the holds
+// of its book and find none waiting.
+func expectNoHoldTrapped(mock sqlmock.Sqlmock, itemID string) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status, home_branch_id, current_branch_id FROM items WHERE id = $1")).
+		WithArgs(itemID).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "home_branch_id", "current_branch_id"}).AddRow("available", nil, nil))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY h.queue_rank LIMIT 1 FOR UPDATE OF h")).
+		WithArgs(itemID, "2024-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "pickup_branch_id"}))
+}
+
+func TestPostgresItemRepository_SetStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresItemRepository(db)
+	now := time.Now()
+	lock := regexp.QuoteMeta("SELECT status FROM items WHERE id = $1 FOR UPDATE")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("available"))
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE items SET status = $1, status_note = $2, status_changed_at = $3, updated_at = $3 WHERE id = $4 RETURNING id, book_id, barcode")).
+			WithArgs("lost", nil, now, "1").
+			WillReturnRows(sqlmock.NewRows(itemRowColumns).
+				AddRow("1", "b1", "B-0001", "A1", nil, nil, "2024-03-01", 1999, "good", "lost", nil, now, now, now))
+		mock.ExpectCommit()
+
+		item, err := repo.SetStatus("1", models.ItemLost, nil, now, HoldPickup{})
+		assert.NoError(t, err)
+		assert.Equal(t, models.ItemLost, item.Status)
+		assert.Equal(t, "2024-03-01", *item.AcquiredOn)
+		assert.Equal(t, 1999, *item.PriceCents)
+	})
+
+	t.Run("found copy is trapped for a hold", func(t *testing.T) {
+		pickup := HoldPickup{Today: "2024-03-01", PickupBy: "2024-03-08"}
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("lost"))
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE items SET status = $1, status_note = $2")).
+			WithArgs("available", nil, now, "1").
+			WillReturnRows(sqlmock.NewRows(itemRowColumns).
+				AddRow("1", "b1", "B-0001", "A1", nil, nil, nil, nil, "good", "available", nil, now, now, now))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT status, home_branch_id, current_branch_id FROM items WHERE id = $1")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"status", "home_branch_id", "current_branch_id"}).AddRow("available", nil, nil))
+		mock.ExpectQuery(regexp.QuoteMeta("ORDER BY h.queue_rank LIMIT 1 FOR UPDATE OF h")).
+			WithArgs("1", "2024-03-01").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "pickup_branch_id"}).AddRow("h1", nil))
+		mock.ExpectQuery(regexp.QuoteMeta("UPDATE holds h SET status = $1, item_id = $2, ready_at = $3, pickup_by = $4")).
+			WithArgs(models.HoldReady, "1", now, "2024-03-08", now, "h1").
+			WillReturnRows(sqlmock.NewRows(holdRowColumns).
+				AddRow("h1", "b1", "m1", "1", "title", "ready", nil, 0, nil, "2024-03-08", now, now, nil, now))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET status = $1, status_note = NULL")).
+			WithArgs(models.ItemOnHold, now, "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, book_id, barcode")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(itemRowColumns).
+				AddRow("1", "b1", "B-0001", "A1", nil, nil, nil, nil, "good", "on_hold", nil, now, now, now))
+		mock.ExpectCommit()
+
+		item, err := repo.SetStatus("1", models.ItemAvailable, nil, now, pickup)
+		assert.NoError(t, err)
+		assert.Equal(t, models.ItemOnHold, item.Status)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("withdrawn"))
+		mock.ExpectRollback()
+
+		_, err := repo.SetStatus("1", models.ItemAvailable, nil, now, HoldPickup{})
+		assert.ErrorIs(t, err, ErrInvalidItemTransition)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("2").
+			WillReturnRows(sqlmock.NewRows([]string{"status"}))
+		mock.ExpectRollback()
+
+		_, err := repo.SetStatus("2", models.ItemLost, nil, now, HoldPickup{})
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresItemRepository_Availability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresItemRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FILTER (WHERE status <> 'withdrawn'),")).
+		WithArgs("b1").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "available", "on_loan", "on_hold", "in_transit", "lost", "withdrawn"}).
+			AddRow(4, 2, 1, 0, 0, 1, 3))
+
+	availability, err := repo.Availability("b1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.ItemAvailability{Total: 4, Available: 2, OnLoan: 1, Lost: 1, Withdrawn: 3}, *availability)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM items WHERE book_id = $1 AND current_branch_id = $2")).
+		WithArgs("b1", "br1").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "available", "on_loan", "on_hold", "in_transit", "lost", "withdrawn"}).
+			AddRow(1, 1, 0, 0, 0, 0, 0))
+
+	availability, err = repo.Availability("b1", "br1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, availability.Available)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrLoanNotFound = errors.New("loan not found")
+	// ErrItemNotAvailable is returned when checking out a copy that is not
+	// available, such as one already on loan.
+	ErrItemNotAvailable = errors.New("item is not available for loan")
+	// ErrItemOnHold is returned when checking out a copy trapped for
+	// another member's hold.
+	ErrItemOnHold = errors.New("item is on hold for another member")
+	// ErrNoActiveLoan is returned when checking in or renewing a copy that
+	// is not on loan.
+	ErrNoActiveLoan = errors.New("item is not on loan")
+	// ErrLoanLimitReached is returned when a member already has as many
+	// copies on loan as they may.
+	ErrLoanLimitReached = errors.New("member has reached the loan limit")
+	// ErrRenewalLimitReached is returned when a loan has been renewed as
+	// often as it may.
+	ErrRenewalLimitReached = errors.New("loan has reached the renewal limit")
+	// ErrHoldsWaiting is returned when renewing a copy that other members'
+	// waiting holds could be filled with.
+	ErrHoldsWaiting = errors.New("other members are waiting for this book")
+	// ErrMemberHasLoans is returned when deleting a member with loans,
+	// holds or fines on record.
+	ErrMemberHasLoans = errors.New("member has circulation history")
+)
+
+// LoanRepository lends copies to members. Checkout, Checkin and Renew lock
+// the copy before the member, so that two desks cannot lend the same copy
+// and a member cannot exceed the loan limit by borrowing at two desks.
+type LoanRepository interface {
+	// Checkout records a loan and puts the copy on loan. The copy must be
+	// available, or trapped for a hold of the member, and the member must
+	// have fewer than maxLoans active loans. The member's holds the copy
+	// fills are fulfilled. The loan's book ID and barcode are filled in, and
+	// the copy is at the loan's branch, if set.
+	Checkout(loan *models.Loan, maxLoans int) error
+	// Checkin ends the active loan of a copy and returns the loan. The copy
+	// is at branchID, if set. It is trapped for the first waiting hold it
+	// can fill, which is returned, or made available when there is none. A
+	// copy needed at another branch, the hold's pickup branch or else its
+	// home branch, goes in transit there.
+	Checkin(itemID string, branchID *string, returnedAt time.Time, pickup HoldPickup) (*models.Loan, *models.Hold, error)
+	// Renew moves the due date of the active loan of a copy to dueOn when
+	// it has been renewed fewer than maxRenewals times and no other
+	// member's hold that the copy could fill is waiting, passing over holds
+	// suspended past today.
+	Renew(itemID, dueOn string, maxRenewals int, renewedAt time.Time, today string) (*models.Loan, error)
+	// GetActiveByItem returns the active loan of a copy, or ErrNoActiveLoan.
+	GetActiveByItem(itemID string) (*models.Loan, error)
+	// ListByMember and ListByItem expect a normalized Limit and Today.
+	ListByMember(memberID string, params *models.LoanListParams) (*models.LoanListResponse, error)
+	ListByItem(itemID string, params *models.LoanListParams) (*models.LoanListResponse, error)
+	// HasLoans reports whether a member has any loans, holds or fines on
+	// record.
+	HasLoans(memberID string) (bool, error)
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryLoanRepository keeps loans in a map guarded by a RWMutex. It lends
+// the copies of a MemoryItemRepository to the members of a
+// MemoryMemberRepository, taking their locks after its own, copies first.
+type MemoryLoanRepository struct {
+	mu      sync.RWMutex
+	items   *MemoryItemRepository
+	members *MemoryMemberRepository
+	loans   map[string]models.Loan
+	// holds is written by the MemoryHoldRepository sharing this
+	// repository, so that circulation can trap and fulfil holds under the
+	// same lock. holdRank numbers holds as they are placed.
+	holds    map[string]memoryHold
+	holdRank int64
+	// entries is the fines ledger written by the MemoryFineRepository
+	// sharing this repository, in the order entries were recorded.
+	entries []models.LedgerEntry
+	// transfers is written by the MemoryTransferRepository sharing this
+	// repository and by the checkins that send copies on to another
+	// branch.
+	transfers map[string]models.Transfer
+}
+
+func NewMemoryLoanRepository(items *MemoryItemRepository, members *MemoryMemberRepository) *MemoryLoanRepository {
+	r := &MemoryLoanRepository{
+		items:     items,
+		members:   members,
+		loans:     make(map[string]models.Loan),
+		holds:     make(map[string]memoryHold),
+		transfers: make(map[string]models.Transfer),
+	}
+	items.books.loans = r
+	return r
+}
+
+func (r *MemoryLoanRepository) Checkout(loan *models.Loan, maxLoans int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items.mu.Lock()
+	defer r.items.mu.Unlock()
+
+	item, ok := r.items.items[loan.ItemID]
+	if !ok {
+		return ErrItemNotFound
+	}
+	r.items.books.mu.RLock()
+	book, ok := r.items.books.books[item.BookID]
+	r.items.books.mu.RUnlock()
+	if !ok || book.DeletedAt != nil {
+		return ErrItemNotFound
+	}
+	switch item.Status {
+	case models.ItemAvailable:
+	case models.ItemOnHold:
+		if ready := r.readyHold(item.ID); ready == nil || ready.hold.MemberID != loan.MemberID {
+			return ErrItemOnHold
+		}
+	default:
+		return ErrItemNotAvailable
+	}
+
+	r.members.mu.RLock()
+	_, ok = r.members.members[loan.MemberID]
+	r.members.mu.RUnlock()
+	if !ok {
+		return ErrMemberNotFound
+	}
+
+	active := 0
+	for _, existing := range r.loans {
+		if existing.MemberID == loan.MemberID && existing.ReturnedAt == nil {
+			active++
+		}
+	}
+	if active >= maxLoans {
+		return ErrLoanLimitReached
+	}
+
+	loan.BookID = item.BookID
+	loan.Barcode = item.Barcode
+	r.loans[loan.ID] = *loan
+
+	item.Status = models.ItemOnLoan
+	if loan.BranchID != nil {
+		item.CurrentBranchID = loan.BranchID
+	}
+	item.StatusNote = nil
+	item.StatusChangedAt = loan.CheckedOutAt
+	item.UpdatedAt = loan.CheckedOutAt
+	r.items.items[item.ID] = item
+
+	r.fulfilHolds(loan)
+	return nil
+}
+
+func (r *MemoryLoanRepository) Checkin(itemID string, branchID *string, returnedAt time.Time, pickup HoldPickup) (*models.Loan, *models.Hold, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items.mu.Lock()
+	defer r.items.mu.Unlock()
+
+	loan, err := r.activeLoan(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loan.ReturnedAt = &returnedAt
+	r.loans[loan.ID] = *loan
+
+	if branchID != nil {
+		item := r.items.items[itemID]
+		item.CurrentBranchID = branchID
+		r.items.items[itemID] = item
+	}
+	return loan, r.trapNextHold(itemID, returnedAt, pickup, true), nil
+}
+
+func (r *MemoryLoanRepository) Renew(itemID, dueOn string, maxRenewals int, renewedAt time.Time, today string) (*models.Loan, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items.mu.RLock()
+	defer r.items.mu.RUnlock()
+
+	loan, err := r.activeLoan(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Renewals >= maxRenewals {
+		return nil, ErrRenewalLimitReached
+	}
+	item := r.items.items[itemID]
+	for _, stored := range r.holds {
+		hold := &stored.hold
+		if hold.BookID == item.BookID && hold.Status == models.HoldWaiting && hold.MemberID != loan.MemberID &&
+			(hold.ItemID == nil || *hold.ItemID == itemID) &&
+			(hold.SuspendedUntil == nil || *hold.SuspendedUntil < today) {
+			return nil, ErrHoldsWaiting
+		}
+	}
+
+	loan.DueOn = dueOn
+	loan.Renewals++
+	loan.LastRenewedAt = &renewedAt
+	r.loans[loan.ID] = *loan
+	return loan, nil
+}
+
+func (r *MemoryLoanRepository) GetActiveByItem(itemID string) (*models.Loan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.items.mu.RLock()
+	defer r.items.mu.RUnlock()
+
+	return r.activeLoan(itemID)
+}
+
+// activeLoan returns the active loan of a copy. Callers must hold the lock
+// and the items lock.
+func (r *MemoryLoanRepository) activeLoan(itemID string) (*models.Loan, error) {
+	if _, ok := r.items.items[itemID]; !ok {
+		return nil, ErrItemNotFound
+	}
+	for _, loan := range r.loans {
+		if loan.ItemID == itemID && loan.ReturnedAt == nil {
+			return &loan, nil
+		}
+	}
+	return nil, ErrNoActiveLoan
+}
+
+func (r *MemoryLoanRepository) ListByMember(memberID string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	return r.list(func(loan *models.Loan) bool { return loan.MemberID == memberID }, params)
+}
+
+func (r *MemoryLoanRepository) ListByItem(itemID string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	return r.list(func(loan *models.Loan) bool { return loan.ItemID == itemID }, params)
+}
+
+func (r *MemoryLoanRepository) list(match func(*models.Loan) bool, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	r.mu.RLock()
+	loans := []models.Loan{}
+	for _, loan := range r.loans {
+		if match(&loan) && matchesLoanStatus(&loan, params) && (params.BranchID == "" || isBranch(loan.BranchID, params.BranchID)) {
+			loans = append(loans, loan)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(loans, func(i, j int) bool {
+		if !loans[i].CheckedOutAt.Equal(loans[j].CheckedOutAt) {
+			return loans[i].CheckedOutAt.After(loans[j].CheckedOutAt)
+		}
+		return loans[i].ID < loans[j].ID
+	})
+
+	response := &models.LoanListResponse{Total: len(loans)}
+	start := params.Offset
+	if start > len(loans) {
+		start = len(loans)
+	}
+	end := start + params.Limit
+	if end > len(loans) {
+		end = len(loans)
+	}
+	response.Data = loans[start:end]
+
+	return response, nil
+}
+
+func matchesLoanStatus(loan *models.Loan, params *models.LoanListParams) bool {
+	switch params.Status {
+	case models.LoanActive:
+		return loan.ReturnedAt == nil
+	case models.LoanOverdue:
+		return loan.ReturnedAt == nil && loan.DueOn < params.Today
+	case models.LoanReturned:
+		return loan.ReturnedAt != nil
+	}
+	return true
+}
+
+func (r *MemoryLoanRepository) HasLoans(memberID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, loan := range r.loans {
+		if loan.MemberID == memberID {
+			return true, nil
+		}
+	}
+	for _, stored := range r.holds {
+		if stored.hold.MemberID == memberID {
+			return true, nil
+		}
+	}
+	for _, entry := range r.entries {
+		if entry.MemberID == memberID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bookInCirculation reports whether a copy of a book is on loan or a hold on
+// it is waiting, in transit or ready. Callers must hold the lock and the
+// items lock.
+func (r *MemoryLoanRepository) bookInCirculation(bookID string) bool {
+	for _, loan := range r.loans {
+		if loan.ReturnedAt == nil && r.items.items[loan.ItemID].BookID == bookID {
+			return true
+		}
+	}
+	for _, held := range r.holds {
+		if held.hold.BookID != bookID {
+			continue
+		}
+		switch held.hold.Status {
+		case models.HoldWaiting, models.HoldInTransit, models.HoldReady:
+			return true
+		}
+	}
+	return false
+}
+
+// bookLent reports whether a copy of a book has ever been lent. Callers must
+// hold the lock and the items lock.
+func (r *MemoryLoanRepository) bookLent(bookID string) bool {
+	for _, loan := range r.loans {
+		if r.items.items[loan.ItemID].BookID == bookID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryLoan(itemID, memberID, dueOn string, checkedOutAt time.Time) *models.Loan {
+	return &models.Loan{
+		ID:           uuid.New().String(),
+		ItemID:       itemID,
+		MemberID:     memberID,
+		DueOn:        dueOn,
+		CheckedOutAt: checkedOutAt,
+	}
+}
+
+func TestMemoryLoanRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	members := NewMemoryMemberRepository()
+	repo := NewMemoryLoanRepository(items, members)
+
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	first := newMemoryItem(book.ID, "E-1")
+	require.NoError(t, items.Create(first, HoldPickup{}))
+	second := newMemoryItem(book.ID, "E-2")
+	require.NoError(t, items.Create(second, HoldPickup{}))
+	member := newMemoryMember("Harriet", "Smith", "00000000000018", models.MembershipAdult, "2030-01-01")
+	require.NoError(t, members.Create(member))
+
+	checkedOutAt := time.Now().Add(-time.Hour)
+	loan := newMemoryLoan(first.ID, member.ID, "2026-02-01", checkedOutAt)
+	require.NoError(t, repo.Checkout(loan, 1))
+	assert.Equal(t, "E-1", loan.Barcode)
+	assert.Equal(t, book.ID, loan.BookID)
+
+	item, err := items.GetByID(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemOnLoan, item.Status)
+
+	assert.ErrorIs(t, repo.Checkout(newMemoryLoan(first.ID, member.ID, "2026-02-01", time.Now()), 5), ErrItemNotAvailable)
+	assert.ErrorIs(t, repo.Checkout(newMemoryLoan(second.ID, member.ID, "2026-02-01", time.Now()), 1), ErrLoanLimitReached)
+	assert.ErrorIs(t, repo.Checkout(newMemoryLoan(second.ID, uuid.New().String(), "2026-02-01", time.Now()), 1), ErrMemberNotFound)
+
+	renewed, err := repo.Renew(first.ID, "2026-02-22", 1, time.Now(), "2026-02-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-02-22", renewed.DueOn)
+	assert.Equal(t, 1, renewed.Renewals)
+	_, err = repo.Renew(first.ID, "2026-03-15", 1, time.Now(), "2026-02-01")
+	assert.ErrorIs(t, err, ErrRenewalLimitReached)
+
+	overdue, err := repo.ListByMember(member.ID, &models.LoanListParams{Status: models.LoanOverdue, Limit: 20, Today: "2026-03-01"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, overdue.Total)
+
+	pickup := HoldPickup{Today: "2026-03-01", PickupBy: "2026-03-08"}
+	returned, trapped, err := repo.Checkin(first.ID, nil, time.Now(), pickup)
+	require.NoError(t, err)
+	assert.NotNil(t, returned.ReturnedAt)
+	assert.Nil(t, trapped)
+	_, _, err = repo.Checkin(first.ID, nil, time.Now(), pickup)
+	assert.ErrorIs(t, err, ErrNoActiveLoan)
+	_, _, err = repo.Checkin(uuid.New().String(), nil, time.Now(), pickup)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	item, err = items.GetByID(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAvailable, item.Status)
+
+	require.NoError(t, repo.Checkout(newMemoryLoan(first.ID, member.ID, "2026-04-01", time.Now()), 1))
+	history, err := repo.ListByItem(first.ID, &models.LoanListParams{Limit: 20, Today: "2026-03-01"})
+	require.NoError(t, err)
+	require.Len(t, history.Data, 2)
+	assert.Nil(t, history.Data[0].ReturnedAt)
+	assert.Equal(t, loan.ID, history.Data[1].ID)
+
+	hasLoans, err := repo.HasLoans(member.ID)
+	require.NoError(t, err)
+	assert.True(t, hasLoans)
+}
+
+func TestMemoryLoanRepository_TrashedBook(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	members := NewMemoryMemberRepository()
+	repo := NewMemoryLoanRepository(items, members)
+
+	book := newMemoryBook("Sanditon", "Jane Austen", 1817, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	item := newMemoryItem(book.ID, "S-1")
+	require.NoError(t, items.Create(item, HoldPickup{}))
+	member := newMemoryMember("Charlotte", "Heywood", "00000000000018", models.MembershipAdult, "2030-01-01")
+	require.NoError(t, members.Create(member))
+	require.NoError(t, books.Delete(book.ID, 0, time.Now()))
+
+	assert.ErrorIs(t, repo.Checkout(newMemoryLoan(item.ID, member.ID, "2026-02-01", time.Now()), 5), ErrItemNotFound)
+	stored, err := items.GetByID(item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAvailable, stored.Status)
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	loanActiveItemIndex = "idx_loans_active_item"
+	loanBranchFKey      = "loans_branch_id_fkey"
+
+	loanColumns = `l.id, l.item_id, i.book_id, i.barcode, l.member_id, l.branch_id, to_char(l.due_on, 'YYYY-MM-DD') AS due_on,
+			  l.renewals, l.checked_out_at, l.last_renewed_at, l.returned_at, l.daily_fine_cents, l.max_fine_cents`
+	loanFrom = " FROM loans l JOIN items i ON i.id = l.item_id"
+)
+
+type PostgresLoanRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLoanRepository(db *sql.DB) *PostgresLoanRepository {
+	return &PostgresLoanRepository{db: db}
+}
+
+func scanLoan(scanner interface{ Scan(...interface{}) error }, loan *models.Loan, extra ...interface{}) error {
+	dest := []interface{}{&loan.ID, &loan.ItemID, &loan.BookID, &loan.Barcode, &loan.MemberID, &loan.BranchID, &loan.DueOn,
+		&loan.Renewals, &loan.CheckedOutAt, &loan.LastRenewedAt, &loan.ReturnedAt, &loan.DailyFineCents, &loan.MaxFineCents}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+func (r *PostgresLoanRepository) Checkout(loan *models.Loan, maxLoans int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The book is share locked so that it cannot be moved to the trash
+	// while the loan is made; copies of a trashed book are not lent.
+	var status string
+	err = tx.QueryRow(`SELECT i.book_id, i.barcode, i.status FROM items i JOIN books b ON b.id = i.book_id
+			  WHERE i.id = $1 AND b.deleted_at IS NULL FOR UPDATE OF i FOR SHARE OF b`, loan.ItemID).
+		Scan(&loan.BookID, &loan.Barcode, &status)
+	if err == sql.ErrNoRows {
+		return ErrItemNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+	switch status {
+	case models.ItemAvailable:
+	case models.ItemOnHold:
+		var holder string
+		err = tx.QueryRow("SELECT member_id FROM holds WHERE item_id = $1 AND status = 'ready' FOR UPDATE", loan.ItemID).Scan(&holder)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to lock hold: %w", err)
+		}
+		if holder != loan.MemberID {
+			return ErrItemOnHold
+		}
+	default:
+		return ErrItemNotAvailable
+	}
+
+	var active int
+	err = tx.QueryRow(`SELECT (SELECT COUNT(*) FROM loans WHERE member_id = m.id AND returned_at IS NULL)
+			  FROM members m WHERE m.id = $1 FOR UPDATE`, loan.MemberID).Scan(&active)
+	if err == sql.ErrNoRows {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock member: %w", err)
+	}
+	if active >= maxLoans {
+		return ErrLoanLimitReached
+	}
+
+	_, err = tx.Exec(`INSERT INTO loans (id, item_id, member_id, branch_id, due_on, renewals, checked_out_at, daily_fine_cents,
+			  max_fine_cents) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		loan.ID, loan.ItemID, loan.MemberID, loan.BranchID, loan.DueOn, loan.Renewals, loan.CheckedOutAt, loan.DailyFineCents,
+		loan.MaxFineCents)
+	if isUniqueViolation(err, loanActiveItemIndex) {
+		return ErrItemNotAvailable
+	}
+	if isForeignKeyViolation(err, loanBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create loan: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE items SET status = $1, current_branch_id = COALESCE($2, current_branch_id), status_note = NULL,
+			  status_changed_at = $3, updated_at = $3 WHERE id = $4`, models.ItemOnLoan, loan.BranchID, loan.CheckedOutAt, loan.ItemID)
+	if err != nil {
+		return fmt.Errorf("failed to update item status: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE holds SET status = $1, closed_at = $2, updated_at = $2
+			  WHERE book_id = $3 AND member_id = $4 AND (status = 'ready' AND item_id = $5
+			  OR status = 'waiting' AND (item_id IS NULL OR item_id = $5))`,
+		models.HoldFulfilled, loan.CheckedOutAt, loan.BookID, loan.MemberID, loan.ItemID)
+	if err != nil {
+		return fmt.Errorf("failed to fulfil holds: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit checkout: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresLoanRepository) Checkin(itemID string, branchID *string, returnedAt time.Time, pickup HoldPickup) (*models.Loan, *models.Hold, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	loan, err := lockActiveLoan(tx, itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = tx.Exec("UPDATE loans SET returned_at = $1 WHERE id = $2", returnedAt, loan.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to return loan: %w", err)
+	}
+	loan.ReturnedAt = &returnedAt
+
+	if branchID != nil {
+		_, err = tx.Exec("UPDATE items SET current_branch_id = $1 WHERE id = $2", *branchID, itemID)
+		if isForeignKeyViolation(err, itemCurrentBranchFKey) {
+			return nil, nil, ErrBranchNotFound
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update item branch: %w", err)
+		}
+	}
+
+	hold, err := trapNextHold(tx, itemID, returnedAt, pickup, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit checkin: %w", err)
+	}
+	return loan, hold, nil
+}
+
+func (r *PostgresLoanRepository) Renew(itemID, dueOn string, maxRenewals int, renewedAt time.Time, today string) (*models.Loan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	loan, err := lockActiveLoan(tx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Renewals >= maxRenewals {
+		return nil, ErrRenewalLimitReached
+	}
+
+	var waiting bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM holds h JOIN items i ON i.book_id = h.book_id
+			  WHERE i.id = $1 AND h.status = 'waiting' AND (h.item_id IS NULL OR h.item_id = i.id)
+			  AND h.member_id <> $2 AND (h.suspended_until IS NULL OR h.suspended_until < $3))`,
+		itemID, loan.MemberID, today).Scan(&waiting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check waiting holds: %w", err)
+	}
+	if waiting {
+		return nil, ErrHoldsWaiting
+	}
+
+	_, err = tx.Exec("UPDATE loans SET due_on = $1, renewals = renewals + 1, last_renewed_at = $2 WHERE id = $3",
+		dueOn, renewedAt, loan.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew loan: %w", err)
+	}
+	loan.DueOn = dueOn
+	loan.Renewals++
+	loan.LastRenewedAt = &renewedAt
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit renewal: %w", err)
+	}
+	return loan, nil
+}
+
+// lockActiveLoan locks a copy and its active loan, returning the loan.
+func lockActiveLoan(tx *sql.Tx, itemID string) (*models.Loan, error) {
+	var exists bool
+	err := tx.QueryRow("SELECT TRUE FROM items WHERE id = $1 FOR UPDATE", itemID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, ErrItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock item: %w", err)
+	}
+
+	var loan models.Loan
+	err = scanLoan(tx.QueryRow("SELECT "+loanColumns+loanFrom+" WHERE l.item_id = $1 AND l.returned_at IS NULL FOR UPDATE OF l", itemID), &loan)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoActiveLoan
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock loan: %w", err)
+	}
+	return &loan, nil
+}
+
+func (r *PostgresLoanRepository) GetActiveByItem(itemID string) (*models.Loan, error) {
+	var loan models.Loan
+	err := scanLoan(r.db.QueryRow("SELECT "+loanColumns+loanFrom+" WHERE l.item_id = $1 AND l.returned_at IS NULL", itemID), &loan)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoActiveLoan
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loan: %w", err)
+	}
+
+	return &loan, nil
+}
+
+func (r *PostgresLoanRepository) ListByMember(memberID string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	return r.list("l.member_id", memberID, params)
+}
+
+func (r *PostgresLoanRepository) ListByItem(itemID string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	return r.list("l.item_id", itemID, params)
+}
+
+func (r *PostgresLoanRepository) list(column, id string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	conditions := []string{column + " = $1"}
+	args := []interface{}{id}
+	switch params.Status {
+	case models.LoanActive:
+		conditions = append(conditions, "l.returned_at IS NULL")
+	case models.LoanOverdue:
+		args = append(args, params.Today)
+		conditions = append(conditions, "l.returned_at IS NULL", fmt.Sprintf("l.due_on < $%d", len(args)))
+	case models.LoanReturned:
+		conditions = append(conditions, "l.returned_at IS NOT NULL")
+	}
+	if params.BranchID != "" {
+		args = append(args, params.BranchID)
+		conditions = append(conditions, fmt.Sprintf("l.branch_id = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER () AS total%s%s ORDER BY l.checked_out_at DESC, l.id LIMIT $%d OFFSET $%d",
+		loanColumns, loanFrom, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loans: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.LoanListResponse{Data: make([]models.Loan, 0, params.Limit)}
+	for rows.Next() {
+		var loan models.Loan
+		if err := scanLoan(rows, &loan, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan loan: %w", err)
+		}
+		response.Data = append(response.Data, loan)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch loans: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresLoanRepository) HasLoans(memberID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM loans WHERE member_id = $1)
+			  OR EXISTS (SELECT 1 FROM holds WHERE member_id = $1)
+			  OR EXISTS (SELECT 1 FROM ledger_entries WHERE member_id = $1)`, memberID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check loans: %w", err)
+	}
+	return exists, nil
+}
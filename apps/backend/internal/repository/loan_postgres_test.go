@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var loanRowColumns = []string{"id", "item_id", "book_id", "barcode", "member_id", "branch_id", "due_on",
+	"renewals", "checked_out_at", "last_renewed_at", "returned_at", "daily_fine_cents", "max_fine_cents"}
+
+func TestPostgresLoanRepository_Checkout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresLoanRepository(db)
+	now := time.Now()
+	lockItem := regexp.QuoteMeta(`SELECT i.book_id, i.barcode, i.status FROM items i JOIN books b ON b.id = i.book_id
+			  WHERE i.id = $1 AND b.deleted_at IS NULL FOR UPDATE OF i FOR SHARE OF b`)
+	lockMember := regexp.QuoteMeta("SELECT (SELECT COUNT(*) FROM loans WHERE member_id = m.id AND returned_at IS NULL) FROM members m WHERE m.id = $1 FOR UPDATE")
+
+	t.Run("success", func(t *testing.T) {
+		dailyFine, maxFine := 25, 1000
+		loan := &models.Loan{ID: "l1", ItemID: "i1", MemberID: "m1", DueOn: "2026-02-01", CheckedOutAt: now,
+			DailyFineCents: &dailyFine, MaxFineCents: &maxFine}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "barcode", "status"}).AddRow("b1", "E-1", "available"))
+		mock.ExpectQuery(lockMember).
+			WithArgs("m1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO loans (id, item_id, member_id, branch_id, due_on, renewals, checked_out_at, daily_fine_cents,")).
+			WithArgs("l1", "i1", "m1", nil, "2026-02-01", 0, now, 25, 1000).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET status = $1, current_branch_id = COALESCE($2, current_branch_id), status_note = NULL,")).
+			WithArgs("on_loan", nil, now, "i1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE holds SET status = $1, closed_at = $2, updated_at = $2 WHERE book_id = $3 AND member_id = $4")).
+			WithArgs("fulfilled", now, "b1", "m1", "i1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Checkout(loan, 2))
+		assert.Equal(t, "E-1", loan.Barcode)
+	})
+
+	t.Run("item on loan", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "barcode", "status"}).AddRow("b1", "E-1", "on_loan"))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Checkout(&models.Loan{ItemID: "i1", MemberID: "m1"}, 2), ErrItemNotAvailable)
+	})
+
+	t.Run("book in the trash", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i3").
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "barcode", "status"}))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Checkout(&models.Loan{ItemID: "i3", MemberID: "m1"}, 2), ErrItemNotFound)
+	})
+
+	t.Run("loan limit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i2").
+			WillReturnRows(sqlmock.NewRows([]string{"book_id", "barcode", "status"}).AddRow("b1", "E-2", "available"))
+		mock.ExpectQuery(lockMember).
+			WithArgs("m1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Checkout(&models.Loan{ItemID: "i2", MemberID: "m1"}, 2), ErrLoanLimitReached)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLoanRepository_Renew(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresLoanRepository(db)
+	now := time.Now()
+	lockItem := regexp.QuoteMeta("SELECT TRUE FROM items WHERE id = $1 FOR UPDATE")
+	lockLoan := regexp.QuoteMeta("FROM loans l JOIN items i ON i.id = l.item_id WHERE l.item_id = $1 AND l.returned_at IS NULL FOR UPDATE OF l")
+	holdsWaiting := regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM holds h JOIN items i ON i.book_id = h.book_id")
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).WithArgs("i1").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(lockLoan).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows(loanRowColumns).AddRow("l1", "i1", "b1", "E-1", "m1", nil, "2026-02-01", 0, now, nil, nil, 25, 1000))
+		mock.ExpectQuery(holdsWaiting).WithArgs("i1", "m1", "2026-02-01").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE loans SET due_on = $1, renewals = renewals + 1, last_renewed_at = $2 WHERE id = $3")).
+			WithArgs("2026-02-22", now, "l1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		loan, err := repo.Renew("i1", "2026-02-22", 2, now, "2026-02-01")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, loan.Renewals)
+		assert.Equal(t, "2026-02-22", loan.DueOn)
+	})
+
+	t.Run("holds waiting", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).WithArgs("i1").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(lockLoan).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows(loanRowColumns).AddRow("l1", "i1", "b1", "E-1", "m1", nil, "2026-02-01", 0, now, nil, nil, 25, 1000))
+		mock.ExpectQuery(holdsWaiting).WithArgs("i1", "m1", "2026-02-01").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		_, err := repo.Renew("i1", "2026-02-22", 2, now, "2026-02-01")
+		assert.ErrorIs(t, err, ErrHoldsWaiting)
+	})
+
+	t.Run("renewal limit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).WithArgs("i1").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(lockLoan).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows(loanRowColumns).AddRow("l1", "i1", "b1", "E-1", "m1", nil, "2026-02-22", 2, now, now, nil, nil, nil))
+		mock.ExpectRollback()
+
+		_, err := repo.Renew("i1", "2026-03-15", 2, now, "2026-02-01")
+		assert.ErrorIs(t, err, ErrRenewalLimitReached)
+	})
+
+	t.Run("not on loan", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).WithArgs("i2").WillReturnRows(sqlmock.NewRows([]string{"bool"}).AddRow(true))
+		mock.ExpectQuery(lockLoan).WithArgs("i2").WillReturnRows(sqlmock.NewRows(loanRowColumns))
+		mock.ExpectRollback()
+
+		_, err := repo.Renew("i2", "2026-03-15", 2, now, "2026-02-01")
+		assert.ErrorIs(t, err, ErrNoActiveLoan)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLoanRepository_ListByMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresLoanRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM loans l JOIN items i ON i.id = l.item_id WHERE l.member_id = $1 AND l.returned_at IS NULL AND l.due_on < $2 ORDER BY l.checked_out_at DESC, l.id LIMIT $3 OFFSET $4")).
+		WithArgs("m1", "2026-03-01", 20, 0).
+		WillReturnRows(sqlmock.NewRows(append(loanRowColumns, "total")).
+			AddRow("l1", "i1", "b1", "E-1", "m1", nil, "2026-02-01", 0, now, nil, nil, nil, nil, 1))
+
+	response, err := repo.ListByMember("m1", &models.LoanListParams{Status: models.LoanOverdue, Limit: 20, Today: "2026-03-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, "E-1", response.Data[0].Barcode)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrMemberNotFound = errors.New("member not found")
+	// ErrDuplicateCardNumber is returned when a member would get the card
+	// number of another member.
+	ErrDuplicateCardNumber = errors.New("a member with this card number already exists")
+)
+
+// MemberRepository persists library members.
+type MemberRepository interface {
+	// List expects a normalized Limit and Today and returns members ordered
+	// by last name, first name and ID.
+	List(params *models.MemberListParams) (*models.MemberListResponse, error)
+	GetByID(id string) (*models.Member, error)
+	GetByCardNumber(cardNumber string) (*models.Member, error)
+	// NextCardSerial hands out the serial number for a new card. Serial
+	// numbers are never handed out twice.
+	NextCardSerial() (int64, error)
+	Create(member *models.Member) error
+	// Update writes the details of a member, leaving the card number and
+	// blocked status alone.
+	Update(member *models.Member) error
+	// SetBlocked blocks a member for a reason, or unblocks them and clears
+	// the reason, returning the updated member.
+	SetBlocked(id string, blocked bool, reason *string, updatedAt time.Time) (*models.Member, error)
+	Delete(id string) error
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryMemberRepository keeps members in a map guarded by a RWMutex.
+type MemoryMemberRepository struct {
+	mu         sync.RWMutex
+	members    map[string]models.Member
+	cardSerial int64
+}
+
+func NewMemoryMemberRepository() *MemoryMemberRepository {
+	return &MemoryMemberRepository{
+		members: make(map[string]models.Member),
+	}
+}
+
+func (r *MemoryMemberRepository) List(params *models.MemberListParams) (*models.MemberListResponse, error) {
+	r.mu.RLock()
+	members := make([]models.Member, 0, len(r.members))
+	for _, member := range r.members {
+		if matchesMemberFilters(&member, params) {
+			members = append(members, member)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].LastName != members[j].LastName {
+			return members[i].LastName < members[j].LastName
+		}
+		if members[i].FirstName != members[j].FirstName {
+			return members[i].FirstName < members[j].FirstName
+		}
+		return members[i].ID < members[j].ID
+	})
+
+	response := &models.MemberListResponse{Total: len(members)}
+	start := params.Offset
+	if start > len(members) {
+		start = len(members)
+	}
+	end := start + params.Limit
+	if end > len(members) {
+		end = len(members)
+	}
+	response.Data = members[start:end]
+
+	return response, nil
+}
+
+// matchesMemberFilters mirrors the conditions of PostgresMemberRepository.List.
+// Dates formatted as YYYY-MM-DD compare correctly as strings.
+func matchesMemberFilters(member *models.Member, params *models.MemberListParams) bool {
+	if params.Query != "" {
+		query := strings.ToLower(params.Query)
+		fields := []string{
+			member.FirstName + " " + member.LastName,
+			member.LastName + ", " + member.FirstName,
+			member.CardNumber,
+		}
+		if member.Email != nil {
+			fields = append(fields, *member.Email)
+		}
+		if member.Phone != nil {
+			fields = append(fields, *member.Phone)
+		}
+
+		matched := false
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), query) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if params.MembershipType != "" && member.MembershipType != params.MembershipType {
+		return false
+	}
+
+	switch params.Status {
+	case models.MemberActive:
+		return !member.Blocked && member.ExpiresOn >= params.Today
+	case models.MemberExpired:
+		return member.ExpiresOn < params.Today
+	case models.MemberBlocked:
+		return member.Blocked
+	}
+	return true
+}
+
+func (r *MemoryMemberRepository) GetByID(id string) (*models.Member, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	member, ok := r.members[id]
+	if !ok {
+		return nil, ErrMemberNotFound
+	}
+	return &member, nil
+}
+
+func (r *MemoryMemberRepository) GetByCardNumber(cardNumber string) (*models.Member, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, member := range r.members {
+		if member.CardNumber == cardNumber {
+			return &member, nil
+		}
+	}
+	return nil, ErrMemberNotFound
+}
+
+func (r *MemoryMemberRepository) NextCardSerial() (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cardSerial++
+	return r.cardSerial, nil
+}
+
+func (r *MemoryMemberRepository) Create(member *models.Member) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.members {
+		if existing.CardNumber == member.CardNumber {
+			return ErrDuplicateCardNumber
+		}
+	}
+
+	r.members[member.ID] = *member
+	return nil
+}
+
+func (r *MemoryMemberRepository) Update(member *models.Member) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.members[member.ID]
+	if !ok {
+		return ErrMemberNotFound
+	}
+
+	existing.FirstName = member.FirstName
+	existing.LastName = member.LastName
+	existing.Email = member.Email
+	existing.Phone = member.Phone
+	existing.Address = member.Address
+	existing.MembershipType = member.MembershipType
+	existing.ExpiresOn = member.ExpiresOn
+	existing.Notes = member.Notes
+	existing.UpdatedAt = member.UpdatedAt
+	r.members[member.ID] = existing
+	return nil
+}
+
+func (r *MemoryMemberRepository) SetBlocked(id string, blocked bool, reason *string, updatedAt time.Time) (*models.Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[id]
+	if !ok {
+		return nil, ErrMemberNotFound
+	}
+
+	member.Blocked = blocked
+	member.BlockedReason = reason
+	member.UpdatedAt = updatedAt
+	r.members[id] = member
+	return &member, nil
+}
+
+func (r *MemoryMemberRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.members[id]; !ok {
+		return ErrMemberNotFound
+	}
+	delete(r.members, id)
+	return nil
+}
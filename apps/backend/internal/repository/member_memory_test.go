@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryMember(first, last, card, membershipType, expiresOn string) *models.Member {
+	return &models.Member{
+		ID:             uuid.New().String(),
+		CardNumber:     card,
+		FirstName:      first,
+		LastName:       last,
+		MembershipType: membershipType,
+		ExpiresOn:      expiresOn,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+func TestMemoryMemberRepository_List(t *testing.T) {
+	repo := NewMemoryMemberRepository()
+	email := "ada@example.com"
+	ada := newMemoryMember("Ada", "Lovelace", "00000000000018", models.MembershipAdult, "2027-01-01")
+	ada.Email = &email
+	require.NoError(t, repo.Create(ada))
+	charles := newMemoryMember("Charles", "Babbage", "00000000000026", models.MembershipSenior, "2025-06-30")
+	require.NoError(t, repo.Create(charles))
+	mary := newMemoryMember("Mary", "Somerville", "00000000000034", models.MembershipAdult, "2027-01-01")
+	require.NoError(t, repo.Create(mary))
+	_, err := repo.SetBlocked(mary.ID, true, nil, time.Now())
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, repo.Create(newMemoryMember("Ada", "Byron", "00000000000018", models.MembershipAdult, "2027-01-01")), ErrDuplicateCardNumber)
+
+	testCases := []struct {
+		name     string
+		params   models.MemberListParams
+		expected []string
+	}{
+		{name: "all by name", params: models.MemberListParams{}, expected: []string{charles.ID, ada.ID, mary.ID}},
+		{name: "email", params: models.MemberListParams{Query: "EXAMPLE.com"}, expected: []string{ada.ID}},
+		{name: "inverted name", params: models.MemberListParams{Query: "babbage, ch"}, expected: []string{charles.ID}},
+		{name: "card number", params: models.MemberListParams{Query: "0026"}, expected: []string{charles.ID}},
+		{name: "membership type", params: models.MemberListParams{MembershipType: models.MembershipAdult}, expected: []string{ada.ID, mary.ID}},
+		{name: "active", params: models.MemberListParams{Status: models.MemberActive}, expected: []string{ada.ID}},
+		{name: "expired", params: models.MemberListParams{Status: models.MemberExpired}, expected: []string{charles.ID}},
+		{name: "blocked", params: models.MemberListParams{Status: models.MemberBlocked}, expected: []string{mary.ID}},
+		{name: "offset", params: models.MemberListParams{Offset: 2}, expected: []string{mary.ID}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := tc.params
+			params.Limit = 20
+			params.Today = "2026-01-01"
+
+			response, err := repo.List(&params)
+			require.NoError(t, err)
+			ids := make([]string, len(response.Data))
+			for i, member := range response.Data {
+				ids[i] = member.ID
+			}
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestMemoryMemberRepository_CardSerial(t *testing.T) {
+	repo := NewMemoryMemberRepository()
+
+	first, err := repo.NextCardSerial()
+	require.NoError(t, err)
+	second, err := repo.NextCardSerial()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first)
+	assert.Equal(t, int64(2), second)
+}
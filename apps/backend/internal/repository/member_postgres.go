@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	memberCardNumberIndex = "idx_members_card_number"
+	loanMemberFKey        = "loans_member_id_fkey"
+
+	memberColumns = `id, card_number, first_name, last_name, email, phone, address, membership_type,
+			  to_char(expires_on, 'YYYY-MM-DD') AS expires_on, blocked, blocked_reason, notes, created_at, updated_at`
+)
+
+type PostgresMemberRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMemberRepository(db *sql.DB) *PostgresMemberRepository {
+	return &PostgresMemberRepository{db: db}
+}
+
+func scanMember(scanner interface{ Scan(...interface{}) error }, member *models.Member) error {
+	return scanner.Scan(&member.ID, &member.CardNumber, &member.FirstName, &member.LastName, &member.Email,
+		&member.Phone, &member.Address, &member.MembershipType, &member.ExpiresOn, &member.Blocked,
+		&member.BlockedReason, &member.Notes, &member.CreatedAt, &member.UpdatedAt)
+}
+
+func (r *PostgresMemberRepository) List(params *models.MemberListParams) (*models.MemberListResponse, error) {
+	var conditions []string
+	var args []interface{}
+	if params.Query != "" {
+		args = append(args, "%"+escapeLike(params.Query)+"%")
+		conditions = append(conditions, fmt.Sprintf(`(first_name || ' ' || last_name ILIKE $%[1]d
+			  OR last_name || ', ' || first_name ILIKE $%[1]d OR email ILIKE $%[1]d
+			  OR phone ILIKE $%[1]d OR card_number ILIKE $%[1]d)`, len(args)))
+	}
+	if params.MembershipType != "" {
+		args = append(args, params.MembershipType)
+		conditions = append(conditions, fmt.Sprintf("membership_type = $%d", len(args)))
+	}
+	switch params.Status {
+	case models.MemberActive:
+		args = append(args, params.Today)
+		conditions = append(conditions, fmt.Sprintf("NOT blocked AND expires_on >= $%d", len(args)))
+	case models.MemberExpired:
+		args = append(args, params.Today)
+		conditions = append(conditions, fmt.Sprintf("expires_on < $%d", len(args)))
+	case models.MemberBlocked:
+		conditions = append(conditions, "blocked")
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER () AS total FROM members%s ORDER BY last_name, first_name, id LIMIT $%d OFFSET $%d",
+		memberColumns, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch members: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.MemberListResponse{Data: make([]models.Member, 0, params.Limit)}
+	for rows.Next() {
+		var member models.Member
+		err := rows.Scan(&member.ID, &member.CardNumber, &member.FirstName, &member.LastName, &member.Email,
+			&member.Phone, &member.Address, &member.MembershipType, &member.ExpiresOn, &member.Blocked,
+			&member.BlockedReason, &member.Notes, &member.CreatedAt, &member.UpdatedAt, &response.Total)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan member: %w", err)
+		}
+		response.Data = append(response.Data, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch members: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresMemberRepository) GetByID(id string) (*models.Member, error) {
+	return r.getMember("id", id)
+}
+
+func (r *PostgresMemberRepository) GetByCardNumber(cardNumber string) (*models.Member, error) {
+	return r.getMember("card_number", cardNumber)
+}
+
+func (r *PostgresMemberRepository) getMember(column, value string) (*models.Member, error) {
+	var member models.Member
+	err := scanMember(r.db.QueryRow("SELECT "+memberColumns+" FROM members WHERE "+column+" = $1", value), &member)
+	if err == sql.ErrNoRows {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch member: %w", err)
+	}
+
+	return &member, nil
+}
+
+func (r *PostgresMemberRepository) NextCardSerial() (int64, error) {
+	var serial int64
+	if err := r.db.QueryRow("SELECT nextval('member_card_serial_seq')").Scan(&serial); err != nil {
+		return 0, fmt.Errorf("failed to generate card serial: %w", err)
+	}
+	return serial, nil
+}
+
+func (r *PostgresMemberRepository) Create(member *models.Member) error {
+	_, err := r.db.Exec(`INSERT INTO members (id, card_number, first_name, last_name, email, phone, address,
+			  membership_type, expires_on, blocked, blocked_reason, notes, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		member.ID, member.CardNumber, member.FirstName, member.LastName, member.Email, member.Phone, member.Address,
+		member.MembershipType, member.ExpiresOn, member.Blocked, member.BlockedReason, member.Notes,
+		member.CreatedAt, member.UpdatedAt)
+	if isUniqueViolation(err, memberCardNumberIndex) {
+		return ErrDuplicateCardNumber
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create member: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresMemberRepository) Update(member *models.Member) error {
+	result, err := r.db.Exec(`UPDATE members SET first_name = $1, last_name = $2, email = $3, phone = $4, address = $5,
+			  membership_type = $6, expires_on = $7, notes = $8, updated_at = $9 WHERE id = $10`,
+		member.FirstName, member.LastName, member.Email, member.Phone, member.Address,
+		member.MembershipType, member.ExpiresOn, member.Notes, member.UpdatedAt, member.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresMemberRepository) SetBlocked(id string, blocked bool, reason *string, updatedAt time.Time) (*models.Member, error) {
+	var member models.Member
+	err := scanMember(r.db.QueryRow(`UPDATE members SET blocked = $1, blocked_reason = $2, updated_at = $3
+			  WHERE id = $4 RETURNING `+memberColumns, blocked, reason, updatedAt, id), &member)
+	if err == sql.ErrNoRows {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update member: %w", err)
+	}
+
+	return &member, nil
+}
+
+func (r *PostgresMemberRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM members WHERE id = $1", id)
+	if isForeignKeyViolation(err, loanMemberFKey) || isForeignKeyViolation(err, holdMemberFKey) ||
+		isForeignKeyViolation(err, ledgerMemberFKey) {
+		return ErrMemberHasLoans
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete member: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
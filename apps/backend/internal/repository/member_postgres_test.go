@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var memberRowColumns = []string{"id", "card_number", "first_name", "last_name", "email", "phone", "address",
+	"membership_type", "expires_on", "blocked", "blocked_reason", "notes", "created_at", "updated_at"}
+
+func TestPostgresMemberRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresMemberRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM members WHERE (first_name || ' ' || last_name ILIKE $1 OR last_name || ', ' || first_name ILIKE $1 OR email ILIKE $1 OR phone ILIKE $1 OR card_number ILIKE $1) AND membership_type = $2 AND NOT blocked AND expires_on >= $3 ORDER BY last_name, first_name, id LIMIT $4 OFFSET $5")).
+		WithArgs("%love\\_%", "adult", "2026-01-01", 20, 0).
+		WillReturnRows(sqlmock.NewRows(append(memberRowColumns, "total")).
+			AddRow("1", "00000000000018", "Ada", "Lovelace", "ada@example.com", nil, nil, "adult", "2027-01-01", false, nil, nil, now, now, 1))
+
+	response, err := repo.List(&models.MemberListParams{Query: "love_", MembershipType: "adult", Status: models.MemberActive, Limit: 20, Today: "2026-01-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Total)
+	assert.Equal(t, "2027-01-01", response.Data[0].ExpiresOn)
+	assert.Equal(t, "ada@example.com", *response.Data[0].Email)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresMemberRepository_SetBlocked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresMemberRepository(db)
+	now := time.Now()
+	reason := "Lost card reported stolen"
+	update := regexp.QuoteMeta("UPDATE members SET blocked = $1, blocked_reason = $2, updated_at = $3 WHERE id = $4 RETURNING id, card_number")
+
+	mock.ExpectQuery(update).
+		WithArgs(true, reason, now, "1").
+		WillReturnRows(sqlmock.NewRows(memberRowColumns).
+			AddRow("1", "00000000000018", "Ada", "Lovelace", nil, nil, nil, "adult", "2027-01-01", true, reason, nil, now, now))
+	member, err := repo.SetBlocked("1", true, &reason, now)
+	assert.NoError(t, err)
+	assert.True(t, member.Blocked)
+
+	mock.ExpectQuery(update).
+		WithArgs(false, nil, now, "2").
+		WillReturnRows(sqlmock.NewRows(memberRowColumns))
+	_, err = repo.SetBlocked("2", false, nil, now)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresMemberRepository_NextCardSerial(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresMemberRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT nextval('member_card_serial_seq')")).
+		WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(int64(42)))
+
+	serial, err := repo.NextCardSerial()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), serial)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
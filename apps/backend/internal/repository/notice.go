@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var ErrNoticeNotFound = errors.New("notice not found")
+
+// NoticeEvent is something a member is owed a notice about: a loan still out
+// past its due date or a hold whose copy is waiting on the hold shelf.
+type NoticeEvent struct {
+	Kind string
+	// Key is the models.Notice Key of the notices for the event.
+	Key    string
+	LoanID *string
+	HoldID *string
+	Member models.Member
+	Title  string
+	// Barcode is the copy on loan or on the hold shelf.
+	Barcode string
+	// Date is the due date of an overdue loan or the pickup date of a ready
+	// hold, formatted as YYYY-MM-DD.
+	Date string
+}
+
+// NoticeRepository is the outbox of notices to members and the channels
+// each member prefers.
+type NoticeRepository interface {
+	// Events returns the loans overdue on today and the ready holds that
+	// no notice has been queued for, oldest date first. today is formatted
+	// as YYYY-MM-DD.
+	Events(today string) ([]NoticeEvent, error)
+	// Enqueue adds notices to the outbox, leaving out any with the Key and
+	// Channel of one already queued, and returns how many were added.
+	Enqueue(notices []models.Notice) (int64, error)
+	// Claim returns up to limit pending notices due to be tried by now,
+	// longest due first, and puts their next attempt back to leaseUntil so
+	// that another dispatcher does not send them too.
+	Claim(now, leaseUntil time.Time, limit int) ([]models.Notice, error)
+	// Update records the outcome of trying a notice: its Status, Attempts,
+	// NextAttemptAt, LastError and SentAt.
+	Update(notice *models.Notice) error
+	// List expects a normalized Limit.
+	List(memberID string, params *models.NoticeListParams) (*models.NoticeListResponse, error)
+	// Preferences returns nil when the member has not chosen any.
+	Preferences(memberID string) (*models.NoticePreferences, error)
+	SetPreferences(preferences *models.NoticePreferences) error
+}
+
+// overdueNoticeKey is the key of the overdue notices for a loan due on
+// dueOn, so that a loan renewed and overdue again is noticed again.
+func overdueNoticeKey(loanID, dueOn string) string {
+	return models.NoticeOverdue + ":" + loanID + ":" + dueOn
+}
+
+// holdReadyNoticeKey is the key of the notices that a hold is ready.
+func holdReadyNoticeKey(holdID string) string {
+	return models.NoticeHoldReady + ":" + holdID
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryNoticeRepository keeps the outbox of the members of a
+// MemoryLoanRepository, finding overdue loans and ready holds among its
+// loans and holds.
+type MemoryNoticeRepository struct {
+	mu    sync.RWMutex
+	loans *MemoryLoanRepository
+	// notices is the outbox in the order notices were queued.
+	notices     []models.Notice
+	preferences map[string]models.NoticePreferences
+}
+
+func NewMemoryNoticeRepository(loans *MemoryLoanRepository) *MemoryNoticeRepository {
+	return &MemoryNoticeRepository{
+		loans:       loans,
+		notices:     []models.Notice{},
+		preferences: make(map[string]models.NoticePreferences),
+	}
+}
+
+func (r *MemoryNoticeRepository) Events(today string) ([]NoticeEvent, error) {
+	r.mu.RLock()
+	queued := make(map[string]bool, len(r.notices))
+	for _, notice := range r.notices {
+		queued[notice.Key] = true
+	}
+	r.mu.RUnlock()
+
+	loans := r.loans
+	loans.mu.RLock()
+	defer loans.mu.RUnlock()
+	loans.items.mu.RLock()
+	defer loans.items.mu.RUnlock()
+	loans.items.books.mu.RLock()
+	defer loans.items.books.mu.RUnlock()
+	loans.members.mu.RLock()
+	defer loans.members.mu.RUnlock()
+
+	event := func(kind, key, itemID, memberID, date string) NoticeEvent {
+		item := loans.items.items[itemID]
+		return NoticeEvent{
+			Kind:    kind,
+			Key:     key,
+			Member:  loans.members.members[memberID],
+			Title:   loans.items.books.books[item.BookID].Title,
+			Barcode: item.Barcode,
+			Date:    date,
+		}
+	}
+
+	events := []NoticeEvent{}
+	for _, loan := range loans.loans {
+		key := overdueNoticeKey(loan.ID, loan.DueOn)
+		if loan.ReturnedAt != nil || loan.DueOn >= today || queued[key] {
+			continue
+		}
+		e := event(models.NoticeOverdue, key, loan.ItemID, loan.MemberID, loan.DueOn)
+		loanID := loan.ID
+		e.LoanID = &loanID
+		events = append(events, e)
+	}
+	for _, held := range loans.holds {
+		hold := held.hold
+		key := holdReadyNoticeKey(hold.ID)
+		if hold.Status != models.HoldReady || queued[key] {
+			continue
+		}
+		e := event(models.NoticeHoldReady, key, *hold.ItemID, hold.MemberID, *hold.PickupBy)
+		holdID := hold.ID
+		e.HoldID = &holdID
+		events = append(events, e)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Date != events[j].Date {
+			return events[i].Date < events[j].Date
+		}
+		return events[i].Key < events[j].Key
+	})
+	return events, nil
+}
+
+func (r *MemoryNoticeRepository) Enqueue(notices []models.Notice) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	queued := make(map[string]bool, len(r.notices))
+	for _, notice := range r.notices {
+		queued[noticeChannelKey(&notice)] = true
+	}
+
+	var added int64
+	for _, notice := range notices {
+		key := noticeChannelKey(&notice)
+		if queued[key] {
+			continue
+		}
+		queued[key] = true
+		r.notices = append(r.notices, notice)
+		added++
+	}
+	return added, nil
+}
+
+func (r *MemoryNoticeRepository) Claim(now, leaseUntil time.Time, limit int) ([]models.Notice, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []int{}
+	for i, notice := range r.notices {
+		if notice.Status == models.NoticePending && !notice.NextAttemptAt.After(now) {
+			due = append(due, i)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return r.notices[due[i]].NextAttemptAt.Before(*r.notices[due[j]].NextAttemptAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]models.Notice, 0, len(due))
+	for _, i := range due {
+		lease := leaseUntil
+		r.notices[i].NextAttemptAt = &lease
+		claimed = append(claimed, r.notices[i])
+	}
+	return claimed, nil
+}
+
+func (r *MemoryNoticeRepository) Update(notice *models.Notice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.notices {
+		if existing.ID != notice.ID {
+			continue
+		}
+		existing.Status = notice.Status
+		existing.Attempts = notice.Attempts
+		existing.NextAttemptAt = notice.NextAttemptAt
+		existing.LastError = notice.LastError
+		existing.SentAt = notice.SentAt
+		r.notices[i] = existing
+		return nil
+	}
+	return ErrNoticeNotFound
+}
+
+func (r *MemoryNoticeRepository) List(memberID string, params *models.NoticeListParams) (*models.NoticeListResponse, error) {
+	r.mu.RLock()
+	notices := []models.Notice{}
+	for i := len(r.notices) - 1; i >= 0; i-- {
+		notice := r.notices[i]
+		if notice.MemberID != memberID ||
+			params.Kind != "" && notice.Kind != params.Kind ||
+			params.Status != "" && notice.Status != params.Status {
+			continue
+		}
+		notices = append(notices, notice)
+	}
+	r.mu.RUnlock()
+
+	// Notices queued together stay newest first.
+	sort.SliceStable(notices, func(i, j int) bool {
+		return notices[i].CreatedAt.After(notices[j].CreatedAt)
+	})
+
+	response := &models.NoticeListResponse{Total: len(notices)}
+	start := params.Offset
+	if start > len(notices) {
+		start = len(notices)
+	}
+	end := start + params.Limit
+	if end > len(notices) {
+		end = len(notices)
+	}
+	response.Data = notices[start:end]
+
+	return response, nil
+}
+
+func (r *MemoryNoticeRepository) Preferences(memberID string) (*models.NoticePreferences, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preferences, ok := r.preferences[memberID]
+	if !ok {
+		return nil, nil
+	}
+	return &preferences, nil
+}
+
+func (r *MemoryNoticeRepository) SetPreferences(preferences *models.NoticePreferences) error {
+	r.loans.members.mu.RLock()
+	_, ok := r.loans.members.members[preferences.MemberID]
+	r.loans.members.mu.RUnlock()
+	if !ok {
+		return ErrMemberNotFound
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.preferences[preferences.MemberID] = *preferences
+	return nil
+}
+
+// noticeChannelKey is what no two notices in the outbox share.
+func noticeChannelKey(notice *models.Notice) string {
+	if notice.Channel == nil {
+		return notice.Key + "|"
+	}
+	return notice.Key + "|" + *notice.Channel
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryNotice(event NoticeEvent, channel *string, createdAt time.Time) models.Notice {
+	notice := models.Notice{
+		ID:        uuid.New().String(),
+		MemberID:  event.Member.ID,
+		Kind:      event.Kind,
+		Key:       event.Key,
+		Channel:   channel,
+		LoanID:    event.LoanID,
+		HoldID:    event.HoldID,
+		Subject:   event.Title,
+		Status:    models.NoticeSkipped,
+		CreatedAt: createdAt,
+	}
+	if channel != nil {
+		notice.Status = models.NoticePending
+		notice.NextAttemptAt = &createdAt
+	}
+	return notice
+}
+
+func TestMemoryNoticeRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	members := NewMemoryMemberRepository()
+	loans := NewMemoryLoanRepository(items, members)
+	holds := NewMemoryHoldRepository(loans)
+	repo := NewMemoryNoticeRepository(loans)
+
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	first := newMemoryItem(book.ID, "E-1")
+	require.NoError(t, items.Create(first, HoldPickup{}))
+	second := newMemoryItem(book.ID, "E-2")
+	require.NoError(t, items.Create(second, HoldPickup{}))
+	harriet := newMemoryMember("Harriet", "Smith", "00000000000018", models.MembershipAdult, "2030-01-01")
+	require.NoError(t, members.Create(harriet))
+	jane := newMemoryMember("Jane", "Fairfax", "00000000000026", models.MembershipAdult, "2030-01-01")
+	require.NoError(t, members.Create(jane))
+
+	now := time.Date(2024, 3, 12, 12, 0, 0, 0, time.Local)
+	overdue := newMemoryLoan(first.ID, harriet.ID, "2024-03-10", now.AddDate(0, 0, -21))
+	require.NoError(t, loans.Checkout(overdue, 5))
+	require.NoError(t, loans.Checkout(newMemoryLoan(second.ID, jane.ID, "2024-03-11", now.AddDate(0, 0, -21)), 5))
+	hold := newMemoryHold(book.ID, harriet.ID, nil, now.AddDate(0, 0, -7))
+	require.NoError(t, holds.Place(hold))
+	_, _, err := loans.Checkin(second.ID, nil, now, HoldPickup{Today: "2024-03-12", PickupBy: "2024-03-19"})
+	require.NoError(t, err)
+
+	events, err := repo.Events("2024-03-12")
+	require.NoError(t, err)
+	require.Len(t, events, 2, "the returned loan is not overdue")
+	assert.Equal(t, models.NoticeOverdue, events[0].Kind)
+	assert.Equal(t, overdue.ID, *events[0].LoanID)
+	assert.Equal(t, "2024-03-10", events[0].Date)
+	assert.Equal(t, "Emma", events[0].Title)
+	assert.Equal(t, "E-1", events[0].Barcode)
+	assert.Equal(t, harriet.CardNumber, events[0].Member.CardNumber)
+	assert.Equal(t, models.NoticeHoldReady, events[1].Kind)
+	assert.Equal(t, hold.ID, *events[1].HoldID)
+	assert.Equal(t, "2024-03-19", events[1].Date)
+	assert.Equal(t, "E-2", events[1].Barcode)
+
+	email, log := models.ChannelEmail, models.ChannelLog
+	queued := []models.Notice{
+		newMemoryNotice(events[0], &email, now),
+		newMemoryNotice(events[0], &log, now),
+		newMemoryNotice(events[1], nil, now),
+	}
+	added, err := repo.Enqueue(queued)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), added)
+	added, err = repo.Enqueue([]models.Notice{newMemoryNotice(events[0], &email, now), newMemoryNotice(events[1], nil, now)})
+	require.NoError(t, err)
+	assert.Zero(t, added, "each event is queued once per channel")
+
+	events, err = repo.Events("2024-03-12")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	t.Run("claim", func(t *testing.T) {
+		lease := now.Add(5 * time.Minute)
+		claimed, err := repo.Claim(now, lease, 1)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		assert.Equal(t, lease, *claimed[0].NextAttemptAt)
+
+		claimed, err = repo.Claim(now, lease, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1, "the claimed and skipped notices are left out")
+
+		sentAt := now.Add(time.Minute)
+		claimed[0].Status = models.NoticeSent
+		claimed[0].Attempts = 1
+		claimed[0].NextAttemptAt = nil
+		claimed[0].SentAt = &sentAt
+		require.NoError(t, repo.Update(&claimed[0]))
+
+		claimed, err = repo.Claim(lease, lease.Add(5*time.Minute), 10)
+		require.NoError(t, err)
+		assert.Len(t, claimed, 1, "the lease of the unsent notice has run out")
+
+		unknown := newMemoryNotice(NoticeEvent{Kind: models.NoticeOverdue, Key: "overdue:unknown"}, &email, now)
+		assert.ErrorIs(t, repo.Update(&unknown), ErrNoticeNotFound)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		notices, err := repo.List(harriet.ID, &models.NoticeListParams{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 3, notices.Total)
+		require.Len(t, notices.Data, 2)
+		assert.Equal(t, models.NoticeHoldReady, notices.Data[0].Kind, "newest first")
+
+		notices, err = repo.List(harriet.ID, &models.NoticeListParams{Status: models.NoticeSent, Limit: 20})
+		require.NoError(t, err)
+		require.Len(t, notices.Data, 1)
+		assert.Equal(t, 1, notices.Data[0].Attempts)
+
+		notices, err = repo.List(jane.ID, &models.NoticeListParams{Limit: 20})
+		require.NoError(t, err)
+		assert.Zero(t, notices.Total)
+	})
+
+	t.Run("preferences", func(t *testing.T) {
+		preferences, err := repo.Preferences(harriet.ID)
+		require.NoError(t, err)
+		assert.Nil(t, preferences)
+
+		chosen := &models.NoticePreferences{MemberID: harriet.ID, Overdue: []string{email, log}, HoldReady: []string{}}
+		require.NoError(t, repo.SetPreferences(chosen))
+		preferences, err = repo.Preferences(harriet.ID)
+		require.NoError(t, err)
+		assert.Equal(t, chosen, preferences)
+
+		assert.ErrorIs(t, repo.SetPreferences(&models.NoticePreferences{MemberID: uuid.New().String()}), ErrMemberNotFound)
+	})
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	noticePreferencesMemberFKey = "notice_preferences_member_id_fkey"
+
+	noticeColumns = `id, member_id, kind, event_key, channel, loan_id, hold_id, recipient, subject, body, status,
+			  attempts, next_attempt_at, last_error, created_at, sent_at`
+
+	// noticeEventsQuery finds the overdue loans and ready holds without
+	// notices. The keys match overdueNoticeKey and holdReadyNoticeKey.
+	noticeEventsQuery = `SELECT kind, event_key, loan_id, hold_id, member_id, card_number, first_name, last_name, email,
+			  title, barcode, date FROM (
+			  SELECT 'overdue' AS kind, 'overdue:' || l.id || ':' || to_char(l.due_on, 'YYYY-MM-DD') AS event_key,
+			  l.id AS loan_id, NULL::uuid AS hold_id, m.id AS member_id, m.card_number, m.first_name, m.last_name, m.email,
+			  b.title, i.barcode, to_char(l.due_on, 'YYYY-MM-DD') AS date
+			  FROM loans l JOIN items i ON i.id = l.item_id JOIN books b ON b.id = i.book_id JOIN members m ON m.id = l.member_id
+			  WHERE l.returned_at IS NULL AND l.due_on < $1
+			  UNION ALL
+			  SELECT 'hold_ready', 'hold_ready:' || h.id, NULL, h.id, m.id, m.card_number, m.first_name, m.last_name, m.email,
+			  b.title, i.barcode, to_char(h.pickup_by, 'YYYY-MM-DD')
+			  FROM holds h JOIN items i ON i.id = h.item_id JOIN books b ON b.id = h.book_id JOIN members m ON m.id = h.member_id
+			  WHERE h.status = 'ready') events
+			  WHERE NOT EXISTS (SELECT 1 FROM notices n WHERE n.event_key = events.event_key)
+			  ORDER BY date, event_key`
+)
+
+type PostgresNoticeRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresNoticeRepository(db *sql.DB) *PostgresNoticeRepository {
+	return &PostgresNoticeRepository{db: db}
+}
+
+func scanNotice(scanner interface{ Scan(...interface{}) error }, notice *models.Notice, extra ...interface{}) error {
+	dest := []interface{}{&notice.ID, &notice.MemberID, &notice.Kind, &notice.Key, &notice.Channel, &notice.LoanID,
+		&notice.HoldID, &notice.Recipient, &notice.Subject, &notice.Body, &notice.Status, &notice.Attempts,
+		&notice.NextAttemptAt, &notice.LastError, &notice.CreatedAt, &notice.SentAt}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+func (r *PostgresNoticeRepository) Events(today string) ([]NoticeEvent, error) {
+	rows, err := r.db.Query(noticeEventsQuery, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notice events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []NoticeEvent{}
+	for rows.Next() {
+		var event NoticeEvent
+		err := rows.Scan(&event.Kind, &event.Key, &event.LoanID, &event.HoldID, &event.Member.ID, &event.Member.CardNumber,
+			&event.Member.FirstName, &event.Member.LastName, &event.Member.Email, &event.Title, &event.Barcode, &event.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notice event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch notice events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *PostgresNoticeRepository) Enqueue(notices []models.Notice) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var added int64
+	for _, notice := range notices {
+		result, err := tx.Exec(`INSERT INTO notices (id, member_id, kind, event_key, channel, loan_id, hold_id, recipient,
+				  subject, body, status, attempts, next_attempt_at, last_error, created_at, sent_at)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+				  ON CONFLICT (event_key, COALESCE(channel, '')) DO NOTHING`,
+			notice.ID, notice.MemberID, notice.Kind, notice.Key, notice.Channel, notice.LoanID, notice.HoldID,
+			notice.Recipient, notice.Subject, notice.Body, notice.Status, notice.Attempts, notice.NextAttemptAt,
+			notice.LastError, notice.CreatedAt, notice.SentAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to queue notice: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to verify notice: %w", err)
+		}
+		added += rowsAffected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit notices: %w", err)
+	}
+	return added, nil
+}
+
+// Claim skips notices another dispatcher is claiming at the same time.
+func (r *PostgresNoticeRepository) Claim(now, leaseUntil time.Time, limit int) ([]models.Notice, error) {
+	rows, err := r.db.Query(`UPDATE notices SET next_attempt_at = $1
+			  WHERE id IN (SELECT id FROM notices WHERE status = 'pending' AND next_attempt_at <= $2
+			  ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED)
+			  RETURNING `+noticeColumns, leaseUntil, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notices: %w", err)
+	}
+	defer rows.Close()
+
+	notices := []models.Notice{}
+	for rows.Next() {
+		var notice models.Notice
+		if err := scanNotice(rows, &notice); err != nil {
+			return nil, fmt.Errorf("failed to scan notice: %w", err)
+		}
+		notices = append(notices, notice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim notices: %w", err)
+	}
+
+	// RETURNING gives no order; send the oldest first.
+	sort.Slice(notices, func(i, j int) bool {
+		if !notices[i].CreatedAt.Equal(notices[j].CreatedAt) {
+			return notices[i].CreatedAt.Before(notices[j].CreatedAt)
+		}
+		return notices[i].ID < notices[j].ID
+	})
+	return notices, nil
+}
+
+func (r *PostgresNoticeRepository) Update(notice *models.Notice) error {
+	result, err := r.db.Exec(`UPDATE notices SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, sent_at = $5
+			  WHERE id = $6`,
+		notice.Status, notice.Attempts, notice.NextAttemptAt, notice.LastError, notice.SentAt, notice.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNoticeNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresNoticeRepository) List(memberID string, params *models.NoticeListParams) (*models.NoticeListResponse, error) {
+	conditions := []string{"member_id = $1"}
+	args := []interface{}{memberID}
+	if params.Kind != "" {
+		args = append(args, params.Kind)
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`SELECT %s, COUNT(*) OVER () AS total FROM notices%s
+			  ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		noticeColumns, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notices: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.NoticeListResponse{Data: make([]models.Notice, 0, params.Limit)}
+	for rows.Next() {
+		var notice models.Notice
+		if err := scanNotice(rows, &notice, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan notice: %w", err)
+		}
+		response.Data = append(response.Data, notice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch notices: %w", err)
+	}
+
+	return response, nil
+}
+
+func (r *PostgresNoticeRepository) Preferences(memberID string) (*models.NoticePreferences, error) {
+	preferences := models.NoticePreferences{MemberID: memberID}
+	err := r.db.QueryRow("SELECT overdue, hold_ready, updated_at FROM notice_preferences WHERE member_id = $1", memberID).
+		Scan(pq.Array(&preferences.Overdue), pq.Array(&preferences.HoldReady), &preferences.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notice preferences: %w", err)
+	}
+	return &preferences, nil
+}
+
+func (r *PostgresNoticeRepository) SetPreferences(preferences *models.NoticePreferences) error {
+	_, err := r.db.Exec(`INSERT INTO notice_preferences (member_id, overdue, hold_ready, updated_at)
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (member_id) DO UPDATE SET overdue = EXCLUDED.overdue, hold_ready = EXCLUDED.hold_ready,
+			  updated_at = EXCLUDED.updated_at`,
+		preferences.MemberID, pq.Array(preferences.Overdue), pq.Array(preferences.HoldReady), preferences.UpdatedAt)
+	if isForeignKeyViolation(err, noticePreferencesMemberFKey) {
+		return ErrMemberNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set notice preferences: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var noticeRowColumns = []string{"id", "member_id", "kind", "event_key", "channel", "loan_id", "hold_id", "recipient",
+	"subject", "body", "status", "attempts", "next_attempt_at", "last_error", "created_at", "sent_at"}
+
+func TestPostgresNoticeRepository_Events(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresNoticeRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE NOT EXISTS (SELECT 1 FROM notices n WHERE n.event_key = events.event_key)")).
+		WithArgs("2024-03-12").
+		WillReturnRows(sqlmock.NewRows([]string{"kind", "event_key", "loan_id", "hold_id", "member_id", "card_number",
+			"first_name", "last_name", "email", "title", "barcode", "date"}).
+			AddRow("overdue", "overdue:l1:2024-03-10", "l1", nil, "m1", "00000000000018", "Harriet", "Smith", "harriet@example.org",
+				"Emma", "E-1", "2024-03-10").
+			AddRow("hold_ready", "hold_ready:h1", nil, "h1", "m1", "00000000000018", "Harriet", "Smith", nil,
+				"Emma", "E-2", "2024-03-19"))
+	events, err := repo.Events("2024-03-12")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, overdueNoticeKey("l1", "2024-03-10"), events[0].Key)
+	assert.Equal(t, "l1", *events[0].LoanID)
+	assert.Equal(t, "harriet@example.org", *events[0].Member.Email)
+	assert.Equal(t, holdReadyNoticeKey("h1"), events[1].Key)
+	assert.Equal(t, "h1", *events[1].HoldID)
+	assert.Nil(t, events[1].Member.Email)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresNoticeRepository_Enqueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresNoticeRepository(db)
+	now := time.Now()
+	email := models.ChannelEmail
+	notices := []models.Notice{
+		{ID: "n1", MemberID: "m1", Kind: models.NoticeOverdue, Key: "overdue:l1:2024-03-10", Channel: &email,
+			Status: models.NoticePending, NextAttemptAt: &now, CreatedAt: now},
+		{ID: "n2", MemberID: "m1", Kind: models.NoticeHoldReady, Key: "hold_ready:h1", Status: models.NoticeSkipped, CreatedAt: now},
+	}
+
+	insert := regexp.QuoteMeta("ON CONFLICT (event_key, COALESCE(channel, '')) DO NOTHING")
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).
+		WithArgs("n1", "m1", models.NoticeOverdue, "overdue:l1:2024-03-10", &email, nil, nil, nil, "", "",
+			models.NoticePending, 0, &now, nil, now, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	added, err := repo.Enqueue(notices)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), added, "the second notice was already queued")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresNoticeRepository_Claim(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresNoticeRepository(db)
+	now := time.Now()
+	lease := now.Add(5 * time.Minute)
+
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED)")).
+		WithArgs(lease, now, 50).
+		WillReturnRows(sqlmock.NewRows(noticeRowColumns).
+			AddRow("n2", "m1", "hold_ready", "hold_ready:h1", "email", nil, "h1", "harriet@example.org", "Ready", "Body",
+				"pending", 0, lease, nil, now, nil).
+			AddRow("n1", "m1", "overdue", "overdue:l1:2024-03-10", "email", "l1", nil, "harriet@example.org", "Overdue", "Body",
+				"pending", 1, lease, "timeout", now.Add(-time.Hour), nil))
+	claimed, err := repo.Claim(now, lease, 50)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, "n1", claimed[0].ID, "oldest first")
+	assert.Equal(t, "timeout", *claimed[0].LastError)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE notices SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4, sent_at = $5")).
+		WithArgs(models.NoticeSent, 2, nil, nil, &now, "n1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	claimed[0].Status = models.NoticeSent
+	claimed[0].Attempts = 2
+	claimed[0].NextAttemptAt = nil
+	claimed[0].LastError = nil
+	claimed[0].SentAt = &now
+	assert.ErrorIs(t, repo.Update(&claimed[0]), ErrNoticeNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresNoticeRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresNoticeRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM notices WHERE member_id = $1 AND kind = $2 AND status = $3")).
+		WithArgs("m1", models.NoticeOverdue, models.NoticeSent, 20, 0).
+		WillReturnRows(sqlmock.NewRows(append(noticeRowColumns, "total")).
+			AddRow("n1", "m1", "overdue", "overdue:l1:2024-03-10", "email", "l1", nil, "harriet@example.org", "Overdue", "Body",
+				"sent", 1, nil, nil, now, now, 1))
+	notices, err := repo.List("m1", &models.NoticeListParams{Kind: models.NoticeOverdue, Status: models.NoticeSent, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1, notices.Total)
+	require.Len(t, notices.Data, 1)
+	assert.Equal(t, models.ChannelEmail, *notices.Data[0].Channel)
+	assert.Nil(t, notices.Data[0].NextAttemptAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresNoticeRepository_Preferences(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresNoticeRepository(db)
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT overdue, hold_ready, updated_at FROM notice_preferences WHERE member_id = $1")
+
+	mock.ExpectQuery(query).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"overdue", "hold_ready", "updated_at"}))
+	preferences, err := repo.Preferences("m1")
+	require.NoError(t, err)
+	assert.Nil(t, preferences)
+
+	mock.ExpectQuery(query).WithArgs("m1").
+		WillReturnRows(sqlmock.NewRows([]string{"overdue", "hold_ready", "updated_at"}).AddRow("{email,log}", "{}", now))
+	preferences, err = repo.Preferences("m1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email", "log"}, preferences.Overdue)
+	assert.Empty(t, preferences.HoldReady)
+
+	upsert := regexp.QuoteMeta("ON CONFLICT (member_id) DO UPDATE SET overdue = EXCLUDED.overdue")
+	chosen := &models.NoticePreferences{MemberID: "m1", Overdue: []string{"log"}, HoldReady: []string{}, UpdatedAt: &now}
+	mock.ExpectExec(upsert).
+		WithArgs("m1", pq.Array(chosen.Overdue), pq.Array(chosen.HoldReady), &now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.SetPreferences(chosen))
+
+	mock.ExpectExec(upsert).WillReturnError(&pq.Error{Code: "23503", Constraint: noticePreferencesMemberFKey})
+	assert.ErrorIs(t, repo.SetPreferences(chosen), ErrMemberNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
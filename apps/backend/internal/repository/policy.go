@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrPolicyNotFound = errors.New("loan policy not found")
+	// ErrDuplicatePolicy is returned when creating or updating a rule to
+	// match the same branch, membership type and genre as another.
+	ErrDuplicatePolicy = errors.New("a policy for this branch, membership type and genre already exists")
+)
+
+// PolicyRepository stores the loan policy rules. A genre's rules go with it
+// when it is deleted, and move to the target when it is merged unless the
+// target has a rule for the same branch and membership type. A branch's
+// rules go with it when it is deleted.
+type PolicyRepository interface {
+	// List returns every rule, by name.
+	List() ([]models.LoanPolicy, error)
+	GetByID(id string) (*models.LoanPolicy, error)
+	// Create and Update return ErrUnknownGenre for a genre that does not
+	// exist, and ErrBranchNotFound for a branch that does not.
+	Create(policy *models.LoanPolicy) error
+	Update(policy *models.LoanPolicy) error
+	Delete(id string) error
+}
+
+// samePolicyMatch reports whether two rules match the same branch,
+// membership type and genre.
+func samePolicyMatch(a, b *models.LoanPolicy) bool {
+	return equalOptional(a.BranchID, b.BranchID) && equalOptional(a.MembershipType, b.MembershipType) &&
+		equalOptional(a.GenreID, b.GenreID)
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"sort"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryPolicyRepository stores loan policies for the genres of a
+// MemoryGenreRepository. The rules live in the genre repository, under its
+// lock, so that deleting and merging genres can drop and move them.
+type MemoryPolicyRepository struct {
+	genres *MemoryGenreRepository
+}
+
+func NewMemoryPolicyRepository(genres *MemoryGenreRepository) *MemoryPolicyRepository {
+	return &MemoryPolicyRepository{genres: genres}
+}
+
+func (r *MemoryPolicyRepository) List() ([]models.LoanPolicy, error) {
+	r.genres.mu.RLock()
+	policies := make([]models.LoanPolicy, 0, len(r.genres.policies))
+	for _, policy := range r.genres.policies {
+		policies = append(policies, policy)
+	}
+	r.genres.mu.RUnlock()
+
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].Name != policies[j].Name {
+			return policies[i].Name < policies[j].Name
+		}
+		return policies[i].ID < policies[j].ID
+	})
+	return policies, nil
+}
+
+func (r *MemoryPolicyRepository) GetByID(id string) (*models.LoanPolicy, error) {
+	r.genres.mu.RLock()
+	defer r.genres.mu.RUnlock()
+
+	policy, ok := r.genres.policies[id]
+	if !ok {
+		return nil, ErrPolicyNotFound
+	}
+	return &policy, nil
+}
+
+func (r *MemoryPolicyRepository) Create(policy *models.LoanPolicy) error {
+	r.genres.mu.Lock()
+	defer r.genres.mu.Unlock()
+
+	if err := r.check(policy); err != nil {
+		return err
+	}
+	r.genres.policies[policy.ID] = *policy
+	return nil
+}
+
+func (r *MemoryPolicyRepository) Update(policy *models.LoanPolicy) error {
+	r.genres.mu.Lock()
+	defer r.genres.mu.Unlock()
+
+	existing, ok := r.genres.policies[policy.ID]
+	if !ok {
+		return ErrPolicyNotFound
+	}
+	if err := r.check(policy); err != nil {
+		return err
+	}
+
+	policy.CreatedAt = existing.CreatedAt
+	r.genres.policies[policy.ID] = *policy
+	return nil
+}
+
+// check validates the genre of a rule and that no other rule matches the
+// same members and books. Callers must hold the genres lock.
+func (r *MemoryPolicyRepository) check(policy *models.LoanPolicy) error {
+	if policy.GenreID != nil {
+		if _, ok := r.genres.genres[*policy.GenreID]; !ok {
+			return ErrUnknownGenre
+		}
+	}
+	for _, other := range r.genres.policies {
+		if other.ID != policy.ID && samePolicyMatch(&other, policy) {
+			return ErrDuplicatePolicy
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPolicyRepository) Delete(id string) error {
+	r.genres.mu.Lock()
+	defer r.genres.mu.Unlock()
+
+	if _, ok := r.genres.policies[id]; !ok {
+		return ErrPolicyNotFound
+	}
+	delete(r.genres.policies, id)
+	return nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryPolicy(name string, membershipType, genreID *string) *models.LoanPolicy {
+	return &models.LoanPolicy{
+		ID:             uuid.New().String(),
+		Name:           name,
+		MembershipType: membershipType,
+		GenreID:        genreID,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+func TestMemoryPolicyRepository(t *testing.T) {
+	genres := NewMemoryGenreRepository(NewMemoryBookRepository())
+	repo := NewMemoryPolicyRepository(genres)
+	fiction := newMemoryGenre("Fiction", nil)
+	require.NoError(t, genres.Create(fiction))
+	novels := newMemoryGenre("Novels", nil)
+	require.NoError(t, genres.Create(novels))
+	child := models.MembershipChild
+
+	everyone := newMemoryPolicy("Everyone", nil, nil)
+	require.NoError(t, repo.Create(everyone))
+	children := newMemoryPolicy("Children's fiction", &child, &fiction.ID)
+	require.NoError(t, repo.Create(children))
+	onNovels := newMemoryPolicy("Children's novels", &child, &novels.ID)
+	require.NoError(t, repo.Create(onNovels))
+
+	assert.ErrorIs(t, repo.Create(newMemoryPolicy("Also everyone", nil, nil)), ErrDuplicatePolicy)
+	missing := uuid.New().String()
+	assert.ErrorIs(t, repo.Create(newMemoryPolicy("Missing", nil, &missing)), ErrUnknownGenre)
+
+	policies, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, policies, 3)
+	assert.Equal(t, "Children's fiction", policies[0].Name)
+
+	t.Run("update", func(t *testing.T) {
+		updated := *children
+		updated.GenreID = nil
+		updated.CreatedAt = time.Time{}
+		require.NoError(t, repo.Update(&updated))
+		assert.Equal(t, children.CreatedAt, updated.CreatedAt)
+
+		updated.MembershipType = nil
+		assert.ErrorIs(t, repo.Update(&updated), ErrDuplicatePolicy)
+		assert.ErrorIs(t, repo.Update(newMemoryPolicy("Missing", nil, nil)), ErrPolicyNotFound)
+
+		updated.MembershipType = &child
+		updated.GenreID = &fiction.ID
+		require.NoError(t, repo.Update(&updated))
+	})
+
+	t.Run("genre merge and delete", func(t *testing.T) {
+		adult := models.MembershipAdult
+		adults := newMemoryPolicy("Adult novels", &adult, &novels.ID)
+		require.NoError(t, repo.Create(adults))
+
+		require.NoError(t, genres.Merge(novels.ID, fiction.ID, time.Now()))
+		_, err := repo.GetByID(onNovels.ID)
+		assert.ErrorIs(t, err, ErrPolicyNotFound, "the target already has a rule for children")
+		moved, err := repo.GetByID(adults.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fiction.ID, *moved.GenreID)
+
+		require.NoError(t, genres.Delete(fiction.ID))
+		_, err = repo.GetByID(children.ID)
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+		_, err = repo.GetByID(adults.ID)
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+		_, err = repo.GetByID(everyone.ID)
+		assert.NoError(t, err)
+	})
+
+	require.NoError(t, repo.Delete(everyone.ID))
+	assert.ErrorIs(t, repo.Delete(everyone.ID), ErrPolicyNotFound)
+}
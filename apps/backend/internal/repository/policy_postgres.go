@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	policyMatchIndex = "idx_loan_policies_match"
+	policyGenreFKey  = "loan_policies_genre_id_fkey"
+	policyBranchFKey = "loan_policies_branch_id_fkey"
+
+	policyColumns = `id, name, branch_id, membership_type, genre_id, priority, loan_days, max_renewals,
+			  daily_fine_cents, max_fine_cents, holds_allowed, created_at, updated_at`
+)
+
+type PostgresPolicyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPolicyRepository(db *sql.DB) *PostgresPolicyRepository {
+	return &PostgresPolicyRepository{db: db}
+}
+
+func scanPolicy(scanner interface{ Scan(...interface{}) error }, policy *models.LoanPolicy) error {
+	return scanner.Scan(&policy.ID, &policy.Name, &policy.BranchID, &policy.MembershipType, &policy.GenreID, &policy.Priority,
+		&policy.LoanDays, &policy.MaxRenewals, &policy.DailyFineCents, &policy.MaxFineCents, &policy.HoldsAllowed,
+		&policy.CreatedAt, &policy.UpdatedAt)
+}
+
+func (r *PostgresPolicyRepository) List() ([]models.LoanPolicy, error) {
+	rows, err := r.db.Query("SELECT " + policyColumns + " FROM loan_policies ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loan policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []models.LoanPolicy{}
+	for rows.Next() {
+		var policy models.LoanPolicy
+		if err := scanPolicy(rows, &policy); err != nil {
+			return nil, fmt.Errorf("failed to scan loan policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch loan policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r *PostgresPolicyRepository) GetByID(id string) (*models.LoanPolicy, error) {
+	var policy models.LoanPolicy
+	err := scanPolicy(r.db.QueryRow("SELECT "+policyColumns+" FROM loan_policies WHERE id = $1", id), &policy)
+	if err == sql.ErrNoRows {
+		return nil, ErrPolicyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loan policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (r *PostgresPolicyRepository) Create(policy *models.LoanPolicy) error {
+	_, err := r.db.Exec(`INSERT INTO loan_policies (id, name, branch_id, membership_type, genre_id, priority, loan_days, max_renewals,
+			  daily_fine_cents, max_fine_cents, holds_allowed, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		policy.ID, policy.Name, policy.BranchID, policy.MembershipType, policy.GenreID, policy.Priority, policy.LoanDays, policy.MaxRenewals,
+		policy.DailyFineCents, policy.MaxFineCents, policy.HoldsAllowed, policy.CreatedAt, policy.UpdatedAt)
+	return policyWriteError(err, "create")
+}
+
+func (r *PostgresPolicyRepository) Update(policy *models.LoanPolicy) error {
+	err := r.db.QueryRow(`UPDATE loan_policies SET name = $1, branch_id = $2, membership_type = $3, genre_id = $4, priority = $5,
+			  loan_days = $6, max_renewals = $7, daily_fine_cents = $8, max_fine_cents = $9, holds_allowed = $10,
+			  updated_at = $11 WHERE id = $12 RETURNING created_at`,
+		policy.Name, policy.BranchID, policy.MembershipType, policy.GenreID, policy.Priority, policy.LoanDays, policy.MaxRenewals,
+		policy.DailyFineCents, policy.MaxFineCents, policy.HoldsAllowed, policy.UpdatedAt, policy.ID).Scan(&policy.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrPolicyNotFound
+	}
+	return policyWriteError(err, "update")
+}
+
+func policyWriteError(err error, action string) error {
+	if isUniqueViolation(err, policyMatchIndex) {
+		return ErrDuplicatePolicy
+	}
+	if isForeignKeyViolation(err, policyGenreFKey) {
+		return ErrUnknownGenre
+	}
+	if isForeignKeyViolation(err, policyBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s loan policy: %w", action, err)
+	}
+	return nil
+}
+
+func (r *PostgresPolicyRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM loan_policies WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete loan policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPolicyNotFound
+	}
+
+	return nil
+}
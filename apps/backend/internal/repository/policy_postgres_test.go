@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policyRowColumns = []string{"id", "name", "branch_id", "membership_type", "genre_id", "priority", "loan_days", "max_renewals",
+	"daily_fine_cents", "max_fine_cents", "holds_allowed", "created_at", "updated_at"}
+
+func TestPostgresPolicyRepository_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresPolicyRepository(db)
+	now := time.Now()
+	rows := sqlmock.NewRows(policyRowColumns).
+		AddRow("p1", "Children", nil, models.MembershipChild, nil, 0, 14, 2, nil, nil, nil, now, now).
+		AddRow("p2", "Reference", "b1", nil, "g1", 10, nil, 0, nil, nil, false, now, now)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM loan_policies ORDER BY name, id")).WillReturnRows(rows)
+
+	policies, err := repo.List()
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, models.MembershipChild, *policies[0].MembershipType)
+	assert.Nil(t, policies[0].GenreID)
+	assert.Nil(t, policies[0].BranchID)
+	assert.Equal(t, "b1", *policies[1].BranchID)
+	assert.Equal(t, 14, *policies[0].LoanDays)
+	assert.Nil(t, policies[1].LoanDays)
+	assert.False(t, *policies[1].HoldsAllowed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPolicyRepository_Create(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresPolicyRepository(db)
+	now := time.Now()
+	genreID, loanDays := "g1", 7
+	policy := &models.LoanPolicy{ID: "p1", Name: "Reference", GenreID: &genreID, LoanDays: &loanDays, CreatedAt: now, UpdatedAt: now}
+	insert := regexp.QuoteMeta("INSERT INTO loan_policies")
+
+	mock.ExpectExec(insert).
+		WithArgs("p1", "Reference", nil, nil, &genreID, 0, &loanDays, nil, nil, nil, nil, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Create(policy))
+
+	mock.ExpectExec(insert).WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_loan_policies_match"})
+	assert.ErrorIs(t, repo.Create(policy), ErrDuplicatePolicy)
+
+	mock.ExpectExec(insert).WillReturnError(&pq.Error{Code: "23503", Constraint: "loan_policies_genre_id_fkey"})
+	assert.ErrorIs(t, repo.Create(policy), ErrUnknownGenre)
+
+	mock.ExpectExec(insert).WillReturnError(&pq.Error{Code: "23503", Constraint: policyBranchFKey})
+	assert.ErrorIs(t, repo.Create(policy), ErrBranchNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPolicyRepository_Update(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresPolicyRepository(db)
+	createdAt := time.Now().Add(-time.Hour)
+	now := time.Now()
+	update := regexp.QuoteMeta("UPDATE loan_policies SET name = $1")
+
+	t.Run("success", func(t *testing.T) {
+		policy := &models.LoanPolicy{ID: "p1", Name: "Everyone", Priority: 5, UpdatedAt: now}
+		mock.ExpectQuery(update).
+			WithArgs("Everyone", nil, nil, nil, 5, nil, nil, nil, nil, nil, now, "p1").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+		require.NoError(t, repo.Update(policy))
+		assert.Equal(t, createdAt, policy.CreatedAt)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(update).WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+		assert.ErrorIs(t, repo.Update(&models.LoanPolicy{ID: "p9", Name: "Missing"}), ErrPolicyNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPolicyRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresPolicyRepository(db)
+	remove := regexp.QuoteMeta("DELETE FROM loan_policies WHERE id = $1")
+
+	mock.ExpectExec(remove).WithArgs("p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Delete("p1"))
+
+	mock.ExpectExec(remove).WithArgs("p9").WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, repo.Delete("p9"), ErrPolicyNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
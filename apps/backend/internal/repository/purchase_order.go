@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrPurchaseOrderNotFound = errors.New("purchase order not found")
+	// ErrOrderNotDraft is returned when editing or deleting an order that
+	// has been placed.
+	ErrOrderNotDraft = errors.New("purchase order has already been placed")
+	// ErrInvalidOrderTransition is returned when a purchase order cannot
+	// move from its current status to the requested one.
+	ErrInvalidOrderTransition = errors.New("invalid purchase order status change")
+	ErrOrderLineNotFound      = errors.New("purchase order line not found")
+	// ErrOverReceipt is returned when a delivery has more copies for a line
+	// than are still to come.
+	ErrOverReceipt = errors.New("more copies received than are on order")
+	// ErrFundOverCommitted is returned when placing an order would charge a
+	// fund more than it has left.
+	ErrFundOverCommitted = errors.New("fund does not have enough budget left")
+)
+
+// LineReceipt is the copies of a delivery received against one line of an
+// order.
+type LineReceipt struct {
+	LineID string
+	Items  []*models.Item
+}
+
+// PurchaseOrderRepository persists purchase orders and their lines. Orders
+// are locked before the funds they are checked against and the copies they
+// add.
+type PurchaseOrderRepository interface {
+	// Create stores a draft and its lines, keeping the lines in order.
+	Create(order *models.PurchaseOrder) error
+	// Update replaces the vendor, note and lines of a draft.
+	Update(order *models.PurchaseOrder) error
+	// Delete removes a draft.
+	Delete(id string) error
+	GetByID(id string) (*models.PurchaseOrder, error)
+	// List expects a normalized Limit.
+	List(params *models.PurchaseOrderListParams) (*models.PurchaseOrderListResponse, error)
+	// Submit places a draft, committing its cost against its funds. It is
+	// refused with ErrFundOverCommitted when a fund has less left than the
+	// order charges it.
+	Submit(id string, orderedAt time.Time) (*models.PurchaseOrder, error)
+	// Receive adds the copies of a delivery to the catalog as copies of the
+	// books of their lines and counts them as received. Each copy is trapped
+	// for the first waiting hold it can fill, and the items of receipts are
+	// updated with their resulting status. The order is received, and
+	// closed, once every copy has arrived.
+	Receive(id string, receipts []LineReceipt, receivedAt time.Time, pickup HoldPickup) (*models.PurchaseOrder, error)
+	// Cancel closes an order that has not been received in full, releasing
+	// what it still has committed. Copies already received stay spent.
+	Cancel(id string, cancelledAt time.Time) (*models.PurchaseOrder, error)
+}
+
+// orderTotal fills in the total cost of an order from its lines.
+func orderTotal(order *models.PurchaseOrder) {
+	order.TotalCents = 0
+	for _, line := range order.Lines {
+		order.TotalCents += line.Quantity * line.UnitPriceCents
+	}
+}
+
+// orderFunds returns the funds the lines of an order are paid from, in the
+// order of the lines, and what the order costs each of them.
+func orderFunds(order *models.PurchaseOrder) ([]string, map[string]int) {
+	fundIDs := []string{}
+	costs := make(map[string]int)
+	for _, line := range order.Lines {
+		if _, ok := costs[line.FundID]; !ok {
+			fundIDs = append(fundIDs, line.FundID)
+		}
+		costs[line.FundID] += line.Quantity * line.UnitPriceCents
+	}
+	return fundIDs, costs
+}
+
+// receiveLines counts a delivery against the lines of an order and fills in
+// the books and prices of its copies. It returns the indexes of the lines received,
+// in order.
+func receiveLines(order *models.PurchaseOrder, receipts []LineReceipt) ([]int, error) {
+	counts := make(map[string]int)
+	for _, receipt := range receipts {
+		counts[receipt.LineID] += len(receipt.Items)
+	}
+
+	var changed []int
+	received := make(map[string]*models.PurchaseOrderLine)
+	for i := range order.Lines {
+		line := &order.Lines[i]
+		count, ok := counts[line.ID]
+		if !ok {
+			continue
+		}
+		delete(counts, line.ID)
+		if line.ReceivedQuantity+count > line.Quantity {
+			return nil, ErrOverReceipt
+		}
+		if line.BookID == nil {
+			return nil, ErrBookNotFound
+		}
+		line.ReceivedQuantity += count
+		received[line.ID] = line
+		changed = append(changed, i)
+	}
+	if len(counts) > 0 {
+		return nil, ErrOrderLineNotFound
+	}
+
+	for _, receipt := range receipts {
+		line := received[receipt.LineID]
+		for _, item := range receipt.Items {
+			price := line.UnitPriceCents
+			item.BookID = *line.BookID
+			item.PriceCents = &price
+		}
+	}
+	return changed, nil
+}
+
+// closeReceipt moves an order after a delivery to partially received, or
+// to received once every copy has arrived.
+func closeReceipt(order *models.PurchaseOrder, receivedAt time.Time) {
+	order.Status = models.OrderReceived
+	for _, line := range order.Lines {
+		if line.ReceivedQuantity < line.Quantity {
+			order.Status = models.OrderPartiallyReceived
+		}
+	}
+	if order.Status == models.OrderReceived {
+		order.ClosedAt = &receivedAt
+	}
+	order.UpdatedAt = receivedAt
+}
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryPurchaseOrderRepository keeps purchase orders and their lines in a
+// map guarded by a RWMutex. The MemoryVendorRepository and
+// MemoryFundRepository it is built with check their deletes, and sum the
+// spending of funds, against it, taking their locks before its own; it adds
+// received copies to a MemoryItemRepository, and traps them for holds in
+// the MemoryLoanRepository built on it, whose locks are always taken after
+// its own.
+type MemoryPurchaseOrderRepository struct {
+	mu     sync.RWMutex
+	items  *MemoryItemRepository
+	funds  *MemoryFundRepository
+	orders map[string]models.PurchaseOrder
+}
+
+func NewMemoryPurchaseOrderRepository(items *MemoryItemRepository, vendors *MemoryVendorRepository,
+	funds *MemoryFundRepository) *MemoryPurchaseOrderRepository {
+	r := &MemoryPurchaseOrderRepository{
+		items:  items,
+		funds:  funds,
+		orders: make(map[string]models.PurchaseOrder),
+	}
+	vendors.orders = r
+	funds.orders = r
+	return r
+}
+
+func (r *MemoryPurchaseOrderRepository) Create(order *models.PurchaseOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orderTotal(order)
+	r.orders[order.ID] = copyOrder(order)
+	return nil
+}
+
+func (r *MemoryPurchaseOrderRepository) Update(order *models.PurchaseOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, err := r.draft(order.ID)
+	if err != nil {
+		return err
+	}
+
+	existing.VendorID = order.VendorID
+	existing.Note = order.Note
+	existing.Lines = order.Lines
+	existing.UpdatedAt = order.UpdatedAt
+	orderTotal(existing)
+	orderTotal(order)
+	r.orders[order.ID] = copyOrder(existing)
+	return nil
+}
+
+func (r *MemoryPurchaseOrderRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.draft(id); err != nil {
+		return err
+	}
+	delete(r.orders, id)
+	return nil
+}
+
+func (r *MemoryPurchaseOrderRepository) GetByID(id string) (*models.PurchaseOrder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	order = copyOrder(&order)
+	return &order, nil
+}
+
+func (r *MemoryPurchaseOrderRepository) List(params *models.PurchaseOrderListParams) (*models.PurchaseOrderListResponse, error) {
+	r.mu.RLock()
+	orders := []models.PurchaseOrder{}
+	for _, order := range r.orders {
+		if params.Status != "" && order.Status != params.Status {
+			continue
+		}
+		if params.VendorID != "" && order.VendorID != params.VendorID {
+			continue
+		}
+		if params.FundID != "" && !paysFrom(&order, params.FundID) {
+			continue
+		}
+		orders = append(orders, copyOrder(&order))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].ID < orders[j].ID
+	})
+
+	response := &models.PurchaseOrderListResponse{Total: len(orders)}
+	start := params.Offset
+	if start > len(orders) {
+		start = len(orders)
+	}
+	end := start + params.Limit
+	if end > len(orders) {
+		end = len(orders)
+	}
+	response.Data = orders[start:end]
+
+	return response, nil
+}
+
+func (r *MemoryPurchaseOrderRepository) Submit(id string, orderedAt time.Time) (*models.PurchaseOrder, error) {
+	r.funds.mu.RLock()
+	defer r.funds.mu.RUnlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, err := r.orderIn(id, models.OrderOrdered)
+	if err != nil {
+		return nil, err
+	}
+	fundIDs, costs := orderFunds(order)
+	for _, fundID := range fundIDs {
+		fund, ok := r.funds.funds[fundID]
+		if !ok {
+			return nil, ErrFundNotFound
+		}
+		encumbered, spent := r.fundSpending(fundID)
+		fundSpending(&fund, encumbered, spent)
+		if fund.AvailableCents < costs[fundID] {
+			return nil, fmt.Errorf("%w: %s", ErrFundOverCommitted, fund.Code)
+		}
+	}
+
+	order.Status = models.OrderOrdered
+	order.OrderedAt = &orderedAt
+	order.UpdatedAt = orderedAt
+	r.orders[id] = copyOrder(order)
+	return order, nil
+}
+
+func (r *MemoryPurchaseOrderRepository) Receive(id string, receipts []LineReceipt, receivedAt time.Time, pickup HoldPickup) (*models.PurchaseOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	loans := r.items.books.loans
+	if loans != nil {
+		loans.mu.Lock()
+		defer loans.mu.Unlock()
+	}
+	r.items.mu.Lock()
+	defer r.items.mu.Unlock()
+
+	order, err := r.orderIn(id, models.OrderReceived)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := receiveLines(order, receipts); err != nil {
+		return nil, err
+	}
+
+	barcodes := make(map[string]bool)
+	r.items.books.mu.RLock()
+	for _, receipt := range receipts {
+		for _, item := range receipt.Items {
+			if barcodes[item.Barcode] || r.items.findByBarcode(item.Barcode) != nil {
+				r.items.books.mu.RUnlock()
+				return nil, ErrDuplicateBarcode
+			}
+			barcodes[item.Barcode] = true
+			if _, ok := r.items.books.books[item.BookID]; !ok {
+				r.items.books.mu.RUnlock()
+				return nil, ErrBookNotFound
+			}
+		}
+	}
+	r.items.books.mu.RUnlock()
+
+	for _, receipt := range receipts {
+		for _, item := range receipt.Items {
+			r.items.items[item.ID] = *item
+			if loans != nil {
+				loans.trapAvailable(item, receivedAt, pickup)
+			}
+		}
+	}
+	closeReceipt(order, receivedAt)
+	r.orders[id] = copyOrder(order)
+	return order, nil
+}
+
+func (r *MemoryPurchaseOrderRepository) Cancel(id string, cancelledAt time.Time) (*models.PurchaseOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, err := r.orderIn(id, models.OrderCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Status = models.OrderCancelled
+	order.ClosedAt = &cancelledAt
+	order.UpdatedAt = cancelledAt
+	r.orders[id] = copyOrder(order)
+	return order, nil
+}
+
+// draft returns a copy of an order that has not been placed. Callers must
+// hold the lock.
+func (r *MemoryPurchaseOrderRepository) draft(id string) (*models.PurchaseOrder, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	if order.Status != models.OrderDraft {
+		return nil, ErrOrderNotDraft
+	}
+	order = copyOrder(&order)
+	return &order, nil
+}
+
+// orderIn returns a copy of an order that is to move to status to.
+// Callers must hold the lock.
+func (r *MemoryPurchaseOrderRepository) orderIn(id, to string) (*models.PurchaseOrder, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	if !models.CanTransitionOrder(order.Status, to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidOrderTransition, order.Status, to)
+	}
+	order = copyOrder(&order)
+	return &order, nil
+}
+
+// fundSpending sums what the orders have committed and spent from a fund.
+// Callers must hold the lock.
+func (r *MemoryPurchaseOrderRepository) fundSpending(fundID string) (encumbered, spent int) {
+	for _, order := range r.orders {
+		open := order.Status == models.OrderOrdered || order.Status == models.OrderPartiallyReceived
+		for _, line := range order.Lines {
+			if line.FundID != fundID {
+				continue
+			}
+			if open {
+				encumbered += (line.Quantity - line.ReceivedQuantity) * line.UnitPriceCents
+			}
+			spent += line.ReceivedQuantity * line.UnitPriceCents
+		}
+	}
+	return encumbered, spent
+}
+
+func (r *MemoryPurchaseOrderRepository) hasVendor(vendorID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, order := range r.orders {
+		if order.VendorID == vendorID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryPurchaseOrderRepository) hasFund(fundID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, order := range r.orders {
+		if paysFrom(&order, fundID) {
+			return true
+		}
+	}
+	return false
+}
+
+// paysFrom reports whether a line of an order is paid from a fund.
+func paysFrom(order *models.PurchaseOrder, fundID string) bool {
+	for _, line := range order.Lines {
+		if line.FundID == fundID {
+			return true
+		}
+	}
+	return false
+}
+
+// copyOrder copies an order with its lines, so that the stored order does
+// not share them with callers.
+func copyOrder(order *models.PurchaseOrder) models.PurchaseOrder {
+	copied := *order
+	copied.Lines = append([]models.PurchaseOrderLine(nil), order.Lines...)
+	return copied
+}
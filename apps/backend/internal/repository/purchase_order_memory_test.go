@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryVendor(name string) *models.Vendor {
+	return &models.Vendor{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func newMemoryPurchaseOrder(vendorID string, createdAt time.Time, lines ...models.PurchaseOrderLine) *models.PurchaseOrder {
+	order := &models.PurchaseOrder{
+		ID:        uuid.New().String(),
+		VendorID:  vendorID,
+		Status:    models.OrderDraft,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	for _, line := range lines {
+		line.ID = uuid.New().String()
+		line.OrderID = order.ID
+		order.Lines = append(order.Lines, line)
+	}
+	return order
+}
+
+func newMemoryOrderLine(book *models.Book, fundID string, quantity, unitPriceCents int) models.PurchaseOrderLine {
+	return models.PurchaseOrderLine{
+		BookID:         &book.ID,
+		Title:          book.Title,
+		FundID:         fundID,
+		Quantity:       quantity,
+		UnitPriceCents: unitPriceCents,
+	}
+}
+
+func newMemoryReceipt(lineID string, barcodes ...string) LineReceipt {
+	receipt := LineReceipt{LineID: lineID}
+	for _, barcode := range barcodes {
+		item := newMemoryItem("", barcode)
+		item.Condition = models.ItemConditionNew
+		receipt.Items = append(receipt.Items, item)
+	}
+	return receipt
+}
+
+func TestMemoryPurchaseOrderRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	vendors := NewMemoryVendorRepository()
+	funds := NewMemoryFundRepository()
+	repo := NewMemoryPurchaseOrderRepository(items, vendors, funds)
+
+	vendor := newMemoryVendor("Blackwell's")
+	require.NoError(t, vendors.Create(vendor))
+	adult := newMemoryFund("ADULT", 2026, 10000)
+	require.NoError(t, funds.Create(adult))
+	child := newMemoryFund("CHILD", 2026, 1000)
+	require.NoError(t, funds.Create(child))
+	emma := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(emma))
+	require.NoError(t, items.Create(newMemoryItem(emma.ID, "E-1"), HoldPickup{}))
+
+	order := newMemoryPurchaseOrder(vendor.ID, time.Now(),
+		newMemoryOrderLine(emma, adult.ID, 3, 1500),
+		newMemoryOrderLine(emma, child.ID, 1, 800))
+	require.NoError(t, repo.Create(order))
+	assert.Equal(t, 5300, order.TotalCents)
+	assert.ErrorIs(t, vendors.Delete(vendor.ID), ErrVendorInUse)
+	assert.ErrorIs(t, funds.Delete(child.ID), ErrFundInUse)
+
+	stored, err := funds.GetByID(adult.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.EncumberedCents)
+
+	order.Lines[1].Quantity = 2
+	order.UpdatedAt = time.Now()
+	require.NoError(t, repo.Update(order))
+	assert.Equal(t, 6100, order.TotalCents)
+	_, err = repo.Submit(order.ID, time.Now())
+	assert.ErrorIs(t, err, ErrFundOverCommitted)
+	assert.ErrorContains(t, err, "CHILD")
+
+	order.Lines[1].Quantity = 1
+	require.NoError(t, repo.Update(order))
+	placed, err := repo.Submit(order.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderOrdered, placed.Status)
+	assert.NotNil(t, placed.OrderedAt)
+	assert.ErrorIs(t, repo.Update(order), ErrOrderNotDraft)
+	assert.ErrorIs(t, repo.Delete(order.ID), ErrOrderNotDraft)
+	_, err = repo.Submit(order.ID, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidOrderTransition)
+
+	stored, err = funds.GetByID(adult.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4500, stored.EncumberedCents)
+	assert.Equal(t, 5500, stored.AvailableCents)
+
+	adultLine := order.Lines[0].ID
+	_, err = repo.Receive(order.ID, []LineReceipt{newMemoryReceipt(adultLine, "E-2", "E-3", "E-4", "E-5")}, time.Now(), HoldPickup{})
+	assert.ErrorIs(t, err, ErrOverReceipt)
+	_, err = repo.Receive(order.ID, []LineReceipt{newMemoryReceipt(adultLine, "E-1")}, time.Now(), HoldPickup{})
+	assert.ErrorIs(t, err, ErrDuplicateBarcode)
+	_, err = repo.Receive(order.ID, []LineReceipt{newMemoryReceipt(uuid.New().String(), "E-2")}, time.Now(), HoldPickup{})
+	assert.ErrorIs(t, err, ErrOrderLineNotFound)
+
+	receipt := newMemoryReceipt(adultLine, "E-2", "E-3")
+	received, err := repo.Receive(order.ID, []LineReceipt{receipt}, time.Now(), HoldPickup{})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderPartiallyReceived, received.Status)
+	assert.Nil(t, received.ClosedAt)
+	assert.Equal(t, 2, received.Lines[0].ReceivedQuantity)
+	added, err := items.GetByBarcode("E-2")
+	require.NoError(t, err)
+	assert.Equal(t, emma.ID, added.BookID)
+	require.NotNil(t, added.PriceCents)
+	assert.Equal(t, 1500, *added.PriceCents)
+
+	stored, err = funds.GetByID(adult.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1500, stored.EncumberedCents)
+	assert.Equal(t, 3000, stored.SpentCents)
+	assert.Equal(t, 5500, stored.AvailableCents)
+
+	cancelled, err := repo.Cancel(order.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderCancelled, cancelled.Status)
+	assert.NotNil(t, cancelled.ClosedAt)
+	_, err = repo.Receive(order.ID, []LineReceipt{newMemoryReceipt(adultLine, "E-6")}, time.Now(), HoldPickup{})
+	assert.ErrorIs(t, err, ErrInvalidOrderTransition)
+
+	stored, err = funds.GetByID(adult.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.EncumberedCents)
+	assert.Equal(t, 3000, stored.SpentCents)
+	assert.Equal(t, 7000, stored.AvailableCents)
+
+	draft := newMemoryPurchaseOrder(vendor.ID, time.Now().Add(time.Hour), newMemoryOrderLine(emma, adult.ID, 1, 100))
+	require.NoError(t, repo.Create(draft))
+	list, err := repo.List(&models.PurchaseOrderListParams{FundID: child.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, order.ID, list.Data[0].ID)
+	list, err = repo.List(&models.PurchaseOrderListParams{VendorID: vendor.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, list.Total)
+	assert.Equal(t, draft.ID, list.Data[0].ID)
+	require.NoError(t, repo.Delete(draft.ID))
+	_, err = repo.GetByID(draft.ID)
+	assert.ErrorIs(t, err, ErrPurchaseOrderNotFound)
+}
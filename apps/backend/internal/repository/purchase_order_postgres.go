@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+const (
+	orderLineBookFKey = "purchase_order_lines_book_id_fkey"
+
+	purchaseOrderColumns = "o.id, o.vendor_id, o.status, o.note, o.ordered_at, o.closed_at, o.created_at, o.updated_at"
+	orderLineColumns     = "id, order_id, book_id, title, fund_id, quantity, unit_price_cents, received_quantity"
+)
+
+type PostgresPurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPurchaseOrderRepository(db *sql.DB) *PostgresPurchaseOrderRepository {
+	return &PostgresPurchaseOrderRepository{db: db}
+}
+
+func scanPurchaseOrder(scanner interface{ Scan(...interface{}) error }, order *models.PurchaseOrder, extra ...interface{}) error {
+	dest := []interface{}{&order.ID, &order.VendorID, &order.Status, &order.Note, &order.OrderedAt, &order.ClosedAt,
+		&order.CreatedAt, &order.UpdatedAt}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+func (r *PostgresPurchaseOrderRepository) Create(order *models.PurchaseOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO purchase_orders (id, vendor_id, status, note, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)`,
+		order.ID, order.VendorID, order.Status, order.Note, order.CreatedAt, order.UpdatedAt)
+	if isForeignKeyViolation(err, purchaseOrderVendorFKey) {
+		return ErrVendorNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create purchase order: %w", err)
+	}
+	if err := insertOrderLines(tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+	orderTotal(order)
+	return nil
+}
+
+func (r *PostgresPurchaseOrderRepository) Update(order *models.PurchaseOrder) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockDraft(tx, order.ID); err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE purchase_orders SET vendor_id = $1, note = $2, updated_at = $3 WHERE id = $4",
+		order.VendorID, order.Note, order.UpdatedAt, order.ID)
+	if isForeignKeyViolation(err, purchaseOrderVendorFKey) {
+		return ErrVendorNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update purchase order: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM purchase_order_lines WHERE order_id = $1", order.ID); err != nil {
+		return fmt.Errorf("failed to clear purchase order lines: %w", err)
+	}
+	if err := insertOrderLines(tx, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+	orderTotal(order)
+	return nil
+}
+
+func (r *PostgresPurchaseOrderRepository) Delete(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockDraft(tx, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM purchase_orders WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete purchase order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresPurchaseOrderRepository) GetByID(id string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := scanPurchaseOrder(r.db.QueryRow("SELECT "+purchaseOrderColumns+" FROM purchase_orders o WHERE o.id = $1", id), &order)
+	if err == sql.ErrNoRows {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch purchase order: %w", err)
+	}
+	if err := loadOrderLines(r.db, []*models.PurchaseOrder{&order}); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func (r *PostgresPurchaseOrderRepository) List(params *models.PurchaseOrderListParams) (*models.PurchaseOrderListResponse, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if params.VendorID != "" {
+		args = append(args, params.VendorID)
+		conditions = append(conditions, fmt.Sprintf("o.vendor_id = $%d", len(args)))
+	}
+	if params.FundID != "" {
+		args = append(args, params.FundID)
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM purchase_order_lines l WHERE l.order_id = o.id AND l.fund_id = $%d)", len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER () AS total FROM purchase_orders o%s ORDER BY o.created_at DESC, o.id LIMIT $%d OFFSET $%d",
+		purchaseOrderColumns, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch purchase orders: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.PurchaseOrderListResponse{Data: make([]models.PurchaseOrder, 0, params.Limit)}
+	for rows.Next() {
+		var order models.PurchaseOrder
+		if err := scanPurchaseOrder(rows, &order, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan purchase order: %w", err)
+		}
+		response.Data = append(response.Data, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch purchase orders: %w", err)
+	}
+
+	orders := make([]*models.PurchaseOrder, len(response.Data))
+	for i := range response.Data {
+		orders[i] = &response.Data[i]
+	}
+	if err := loadOrderLines(r.db, orders); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (r *PostgresPurchaseOrderRepository) Submit(id string, orderedAt time.Time) (*models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := lockOrder(tx, id, models.OrderOrdered)
+	if err != nil {
+		return nil, err
+	}
+	fundIDs, costs := orderFunds(order)
+	if _, err := tx.Exec("SELECT id FROM funds WHERE id = ANY($1) ORDER BY id FOR UPDATE", pq.Array(fundIDs)); err != nil {
+		return nil, fmt.Errorf("failed to lock funds: %w", err)
+	}
+	funds, err := queryFunds(tx, "SELECT "+fundColumns+fundFrom+" WHERE f.id = ANY($1) GROUP BY f.id ORDER BY f.code",
+		pq.Array(fundIDs))
+	if err != nil {
+		return nil, err
+	}
+	for _, fund := range funds {
+		if fund.AvailableCents < costs[fund.ID] {
+			return nil, fmt.Errorf("%w: %s", ErrFundOverCommitted, fund.Code)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1, ordered_at = $2, updated_at = $2 WHERE id = $3",
+		models.OrderOrdered, orderedAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to place purchase order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+	order.Status = models.OrderOrdered
+	order.OrderedAt = &orderedAt
+	order.UpdatedAt = orderedAt
+	return order, nil
+}
+
+func (r *PostgresPurchaseOrderRepository) Receive(id string, receipts []LineReceipt, receivedAt time.Time, pickup HoldPickup) (*models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := lockOrder(tx, id, models.OrderReceived)
+	if err != nil {
+		return nil, err
+	}
+	received, err := receiveLines(order, receipts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, receipt := range receipts {
+		for _, item := range receipt.Items {
+			if err := insertItem(tx, item); err != nil {
+				return nil, err
+			}
+			if err := trapAvailable(tx, item, receivedAt, pickup); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, i := range received {
+		line := order.Lines[i]
+		_, err := tx.Exec("UPDATE purchase_order_lines SET received_quantity = $1 WHERE id = $2", line.ReceivedQuantity, line.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive purchase order line: %w", err)
+		}
+	}
+	closeReceipt(order, receivedAt)
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1, closed_at = $2, updated_at = $3 WHERE id = $4",
+		order.Status, order.ClosedAt, order.UpdatedAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update purchase order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+	return order, nil
+}
+
+func (r *PostgresPurchaseOrderRepository) Cancel(id string, cancelledAt time.Time) (*models.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	order, err := lockOrder(tx, id, models.OrderCancelled)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec("UPDATE purchase_orders SET status = $1, closed_at = $2, updated_at = $2 WHERE id = $3",
+		models.OrderCancelled, cancelledAt, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel purchase order: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+	order.Status = models.OrderCancelled
+	order.ClosedAt = &cancelledAt
+	order.UpdatedAt = cancelledAt
+	return order, nil
+}
+
+// lockDraft locks an order that has not been placed.
+func lockDraft(tx *sql.Tx, id string) error {
+	order, err := lockOrderRow(tx, id)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.OrderDraft {
+		return ErrOrderNotDraft
+	}
+	return nil
+}
+
+// lockOrder locks an order that is to move to status to and loads its
+// lines.
+func lockOrder(tx *sql.Tx, id, to string) (*models.PurchaseOrder, error) {
+	order, err := lockOrderRow(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !models.CanTransitionOrder(order.Status, to) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidOrderTransition, order.Status, to)
+	}
+	if err := loadOrderLines(tx, []*models.PurchaseOrder{order}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func lockOrderRow(tx *sql.Tx, id string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := scanPurchaseOrder(tx.QueryRow("SELECT "+purchaseOrderColumns+" FROM purchase_orders o WHERE o.id = $1 FOR UPDATE", id), &order)
+	if err == sql.ErrNoRows {
+		return nil, ErrPurchaseOrderNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock purchase order: %w", err)
+	}
+	return &order, nil
+}
+
+// insertOrderLines adds the lines of an order, numbered in order.
+func insertOrderLines(tx *sql.Tx, order *models.PurchaseOrder) error {
+	for i, line := range order.Lines {
+		_, err := tx.Exec(`INSERT INTO purchase_order_lines (id, order_id, position, book_id, title, fund_id, quantity,
+				  unit_price_cents, received_quantity) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			line.ID, order.ID, i+1, line.BookID, line.Title, line.FundID, line.Quantity, line.UnitPriceCents,
+			line.ReceivedQuantity)
+		if isForeignKeyViolation(err, orderLineFundFKey) {
+			return ErrFundNotFound
+		}
+		if isForeignKeyViolation(err, orderLineBookFKey) {
+			return ErrBookNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create purchase order line: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadOrderLines fills in the lines and totals of orders.
+func loadOrderLines(q queryer, orders []*models.PurchaseOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	byID := make(map[string]*models.PurchaseOrder, len(orders))
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		order.Lines = []models.PurchaseOrderLine{}
+		byID[order.ID] = order
+		ids[i] = order.ID
+	}
+
+	rows, err := q.Query("SELECT "+orderLineColumns+" FROM purchase_order_lines WHERE order_id = ANY($1) ORDER BY order_id, position",
+		pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to fetch purchase order lines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.PurchaseOrderLine
+		err := rows.Scan(&line.ID, &line.OrderID, &line.BookID, &line.Title, &line.FundID, &line.Quantity,
+			&line.UnitPriceCents, &line.ReceivedQuantity)
+		if err != nil {
+			return fmt.Errorf("failed to scan purchase order line: %w", err)
+		}
+		order := byID[line.OrderID]
+		order.Lines = append(order.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to fetch purchase order lines: %w", err)
+	}
+
+	for _, order := range orders {
+		orderTotal(order)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	purchaseOrderRowColumns = []string{"id", "vendor_id", "status", "note", "ordered_at", "closed_at", "created_at", "updated_at"}
+	orderLineRowColumns     = []string{"id", "order_id", "book_id", "title", "fund_id", "quantity", "unit_price_cents",
+		"received_quantity"}
+)
+
+func TestPostgresPurchaseOrderRepository_Submit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresPurchaseOrderRepository(db)
+	now := time.Now()
+	lock := regexp.QuoteMeta("FROM purchase_orders o WHERE o.id = $1 FOR UPDATE")
+	lines := regexp.QuoteMeta("FROM purchase_order_lines WHERE order_id = ANY($1) ORDER BY order_id, position")
+	lockFunds := regexp.QuoteMeta("SELECT id FROM funds WHERE id = ANY($1) ORDER BY id FOR UPDATE")
+	funds := regexp.QuoteMeta("WHERE f.id = ANY($1) GROUP BY f.id ORDER BY f.code")
+	expectDraft := func() {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lock).
+			WithArgs("po1").
+			WillReturnRows(sqlmock.NewRows(purchaseOrderRowColumns).AddRow("po1", "v1", "draft", nil, nil, nil, now, now))
+		mock.ExpectQuery(lines).
+			WithArgs(pq.Array([]string{"po1"})).
+			WillReturnRows(sqlmock.NewRows(orderLineRowColumns).
+				AddRow("l1", "po1", "b1", "Emma", "f1", 3, 1500, 0).
+				AddRow("l2", "po1", "b2", "Persuasion", "f2", 1, 800, 0))
+		mock.ExpectExec(lockFunds).
+			WithArgs(pq.Array([]string{"f1", "f2"})).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		expectDraft()
+		mock.ExpectQuery(funds).
+			WithArgs(pq.Array([]string{"f1", "f2"})).
+			WillReturnRows(sqlmock.NewRows(fundRowColumns).
+				AddRow("f1", "ADULT", "Adult fiction", 2026, 10000, 0, 0, now, now).
+				AddRow("f2", "CHILD", "Children", 2026, 1000, 0, 0, now, now))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE purchase_orders SET status = $1, ordered_at = $2, updated_at = $2 WHERE id = $3")).
+			WithArgs("ordered", now, "po1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		order, err := repo.Submit("po1", now)
+		require.NoError(t, err)
+		assert.Equal(t, models.OrderOrdered, order.Status)
+		assert.Equal(t, 5300, order.TotalCents)
+	})
+
+	t.Run("over committed", func(t *testing.T) {
+		expectDraft()
+		mock.ExpectQuery(funds).
+			WithArgs(pq.Array([]string{"f1", "f2"})).
+			WillReturnRows(sqlmock.NewRows(fundRowColumns).
+				AddRow("f1", "ADULT", "Adult fiction", 2026, 10000, 0, 0, now, now).
+				AddRow("f2", "CHILD", "Children", 2026, 1000, 300, 0, now, now))
+		mock.ExpectRollback()
+
+		_, err := repo.Submit("po1", now)
+		assert.ErrorIs(t, err, ErrFundOverCommitted)
+		assert.ErrorContains(t, err, "CHILD")
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresPurchaseOrderRepository_Receive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresPurchaseOrderRepository(db)
+	now := time.Now()
+	ordered := now.Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM purchase_orders o WHERE o.id = $1 FOR UPDATE")).
+		WithArgs("po1").
+		WillReturnRows(sqlmock.NewRows(purchaseOrderRowColumns).AddRow("po1", "v1", "ordered", nil, ordered, nil, ordered, ordered))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM purchase_order_lines WHERE order_id = ANY($1)")).
+		WithArgs(pq.Array([]string{"po1"})).
+		WillReturnRows(sqlmock.NewRows(orderLineRowColumns).
+			AddRow("l1", "po1", "b1", "Emma", "f1", 2, 1500, 1).
+			AddRow("l2", "po1", "b2", "Persuasion", "f2", 1, 800, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO items")).
+		WithArgs("i1", "b1", "E-2", nil, nil, nil, nil, 1500, "new", "available", nil, now, now, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectNoHoldTrapped(mock, "i1")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, book_id, barcode")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows(itemRowColumns).
+			AddRow("i1", "b1", "E-2", nil, nil, nil, nil, 1500, "new", "available", nil, now, now, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE purchase_order_lines SET received_quantity = $1 WHERE id = $2")).
+		WithArgs(2, "l1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE purchase_orders SET status = $1, closed_at = $2, updated_at = $3 WHERE id = $4")).
+		WithArgs("received", now, now, "po1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	item := &models.Item{ID: "i1", Barcode: "E-2", Condition: models.ItemConditionNew, Status: models.ItemAvailable,
+		StatusChangedAt: now, CreatedAt: now, UpdatedAt: now}
+	order, err := repo.Receive("po1", []LineReceipt{{LineID: "l1", Items: []*models.Item{item}}}, now,
+		HoldPickup{Today: "2024-03-01", PickupBy: "2024-03-08"})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderReceived, order.Status)
+	require.NotNil(t, order.ClosedAt)
+	assert.Equal(t, "b1", item.BookID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrTransferNotFound = errors.New("transfer not found")
+	// ErrDuplicateTransfer is returned when requesting a transfer of a copy
+	// that already has an open transfer.
+	ErrDuplicateTransfer = errors.New("item already has an open transfer")
+	// ErrInvalidTransferTransition is returned when a transfer cannot move
+	// from its current status to the requested one.
+	ErrInvalidTransferTransition = errors.New("invalid transfer status change")
+	// ErrItemHasNoBranch is returned when transferring a copy that is not
+	// at any branch.
+	ErrItemHasNoBranch = errors.New("item is not at a branch")
+	// ErrSameBranch is returned when transferring a copy to the branch it
+	// is at.
+	ErrSameBranch = errors.New("item is already at this branch")
+)
+
+// TransferRepository moves copies between branches. Like circulation it
+// locks the copy before the transfer and the holds it traps.
+type TransferRepository interface {
+	// Request records a requested transfer of a copy on the shelf from the
+	// branch it is at, filling in the transfer's barcode and from branch.
+	// Requesting is refused with ErrItemNotAvailable for a copy that is not
+	// available.
+	Request(transfer *models.Transfer) error
+	// Ship puts a requested transfer and its copy in transit. The copy
+	// must still be available.
+	Ship(id string, shippedAt time.Time) (*models.Transfer, error)
+	// Receive closes a transfer in transit at the branch it was sent to,
+	// which the copy is then at. The copy goes on the hold shelf for the
+	// hold it travelled for while that hold is active, and is otherwise
+	// trapped for the next hold it can fill or made available, as at
+	// checkin but without being sent home.
+	Receive(id string, receivedAt time.Time, pickup HoldPickup) (*models.Transfer, *models.Hold, error)
+	// Cancel cancels a requested transfer. Transfers in transit can only
+	// be received.
+	Cancel(id string, cancelledAt time.Time) (*models.Transfer, error)
+	GetByID(id string) (*models.Transfer, error)
+	// List expects a normalized Limit.
+	List(params *models.TransferListParams) (*models.TransferListResponse, error)
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryTransferRepository moves the copies of a MemoryLoanRepository
+// between branches. The transfers live in the loan repository, under its
+// lock, so that checkins can ship copies and receipts can trap holds.
+type MemoryTransferRepository struct {
+	loans *MemoryLoanRepository
+}
+
+func NewMemoryTransferRepository(loans *MemoryLoanRepository) *MemoryTransferRepository {
+	return &MemoryTransferRepository{loans: loans}
+}
+
+func (r *MemoryTransferRepository) Request(transfer *models.Transfer) error {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.Lock()
+	defer loans.items.mu.Unlock()
+
+	item, ok := loans.items.items[transfer.ItemID]
+	if !ok {
+		return ErrItemNotFound
+	}
+	if item.Status != models.ItemAvailable {
+		return ErrItemNotAvailable
+	}
+	if item.CurrentBranchID == nil {
+		return ErrItemHasNoBranch
+	}
+	if *item.CurrentBranchID == transfer.ToBranchID {
+		return ErrSameBranch
+	}
+	for _, other := range loans.transfers {
+		if other.ItemID == item.ID && isOpenTransfer(&other) {
+			return ErrDuplicateTransfer
+		}
+	}
+
+	transfer.Barcode = item.Barcode
+	transfer.FromBranchID = *item.CurrentBranchID
+	loans.transfers[transfer.ID] = *transfer
+	return nil
+}
+
+func (r *MemoryTransferRepository) Ship(id string, shippedAt time.Time) (*models.Transfer, error) {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.Lock()
+	defer loans.items.mu.Unlock()
+
+	transfer, err := loans.transferIn(id, models.TransferRequested, models.TransferInTransit)
+	if err != nil {
+		return nil, err
+	}
+	item := loans.items.items[transfer.ItemID]
+	if item.Status != models.ItemAvailable {
+		return nil, ErrItemNotAvailable
+	}
+
+	item.Status = models.ItemInTransit
+	item.StatusNote = nil
+	item.StatusChangedAt = shippedAt
+	item.UpdatedAt = shippedAt
+	loans.items.items[item.ID] = item
+
+	transfer.Status = models.TransferInTransit
+	transfer.ShippedAt = &shippedAt
+	transfer.UpdatedAt = shippedAt
+	loans.transfers[id] = *transfer
+	return loans.transferView(*transfer), nil
+}
+
+func (r *MemoryTransferRepository) Receive(id string, receivedAt time.Time, pickup HoldPickup) (*models.Transfer, *models.Hold, error) {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.Lock()
+	defer loans.items.mu.Unlock()
+
+	transfer, err := loans.transferIn(id, models.TransferInTransit, models.TransferReceived)
+	if err != nil {
+		return nil, nil, err
+	}
+	item := loans.items.items[transfer.ItemID]
+	if item.Status != models.ItemInTransit {
+		return nil, nil, fmt.Errorf("%w: %s to %s", ErrInvalidItemTransition, item.Status, models.ItemAvailable)
+	}
+
+	transfer.Status = models.TransferReceived
+	transfer.ReceivedAt = &receivedAt
+	transfer.UpdatedAt = receivedAt
+	loans.transfers[id] = *transfer
+
+	to := transfer.ToBranchID
+	item.CurrentBranchID = &to
+	item.StatusNote = nil
+	item.StatusChangedAt = receivedAt
+	item.UpdatedAt = receivedAt
+
+	if transfer.HoldID != nil {
+		if stored, ok := loans.holds[*transfer.HoldID]; ok && stored.hold.Status == models.HoldInTransit {
+			setHoldReady(&stored.hold, receivedAt, pickup.at(&to))
+			loans.holds[stored.hold.ID] = stored
+			item.Status = models.ItemOnHold
+			loans.items.items[item.ID] = item
+
+			hold := loans.holdView(stored)
+			return loans.transferView(*transfer), &hold, nil
+		}
+	}
+
+	loans.items.items[item.ID] = item
+	hold := loans.trapNextHold(item.ID, receivedAt, pickup, false)
+	return loans.transferView(*transfer), hold, nil
+}
+
+func (r *MemoryTransferRepository) Cancel(id string, cancelledAt time.Time) (*models.Transfer, error) {
+	loans := r.loans
+	loans.mu.Lock()
+	defer loans.mu.Unlock()
+	loans.items.mu.RLock()
+	defer loans.items.mu.RUnlock()
+
+	transfer, err := loans.transferIn(id, models.TransferRequested, models.TransferCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.Status = models.TransferCancelled
+	transfer.CancelledAt = &cancelledAt
+	transfer.UpdatedAt = cancelledAt
+	loans.transfers[id] = *transfer
+	return loans.transferView(*transfer), nil
+}
+
+func (r *MemoryTransferRepository) GetByID(id string) (*models.Transfer, error) {
+	loans := r.loans
+	loans.mu.RLock()
+	defer loans.mu.RUnlock()
+	loans.items.mu.RLock()
+	defer loans.items.mu.RUnlock()
+
+	transfer, ok := loans.transfers[id]
+	if !ok {
+		return nil, ErrTransferNotFound
+	}
+	return loans.transferView(transfer), nil
+}
+
+func (r *MemoryTransferRepository) List(params *models.TransferListParams) (*models.TransferListResponse, error) {
+	loans := r.loans
+	loans.mu.RLock()
+	loans.items.mu.RLock()
+	transfers := []models.Transfer{}
+	for _, transfer := range loans.transfers {
+		if params.Status != "" && transfer.Status != params.Status {
+			continue
+		}
+		if params.BranchID != "" && transfer.FromBranchID != params.BranchID && transfer.ToBranchID != params.BranchID {
+			continue
+		}
+		transfers = append(transfers, *loans.transferView(transfer))
+	}
+	loans.items.mu.RUnlock()
+	loans.mu.RUnlock()
+
+	sort.Slice(transfers, func(i, j int) bool {
+		if !transfers[i].RequestedAt.Equal(transfers[j].RequestedAt) {
+			return transfers[i].RequestedAt.After(transfers[j].RequestedAt)
+		}
+		return transfers[i].ID < transfers[j].ID
+	})
+
+	response := &models.TransferListResponse{Total: len(transfers)}
+	start := params.Offset
+	if start > len(transfers) {
+		start = len(transfers)
+	}
+	end := start + params.Limit
+	if end > len(transfers) {
+		end = len(transfers)
+	}
+	response.Data = transfers[start:end]
+
+	return response, nil
+}
+
+func isOpenTransfer(transfer *models.Transfer) bool {
+	return transfer.Status == models.TransferRequested || transfer.Status == models.TransferInTransit
+}
+
+// transferIn returns a transfer that is to move from status from to status
+// to. Callers must hold the lock.
+func (r *MemoryLoanRepository) transferIn(id, from, to string) (*models.Transfer, error) {
+	transfer, ok := r.transfers[id]
+	if !ok {
+		return nil, ErrTransferNotFound
+	}
+	if transfer.Status != from {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransferTransition, transfer.Status, to)
+	}
+	return &transfer, nil
+}
+
+// transferView returns a stored transfer with the current barcode of its
+// copy. Callers must hold the lock and the items lock.
+func (r *MemoryLoanRepository) transferView(transfer models.Transfer) *models.Transfer {
+	if item, ok := r.items.items[transfer.ItemID]; ok {
+		transfer.Barcode = item.Barcode
+	}
+	return &transfer
+}
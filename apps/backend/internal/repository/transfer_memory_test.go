@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMemoryTransfer(itemID, toBranchID string, requestedAt time.Time) *models.Transfer {
+	return &models.Transfer{
+		ID:          uuid.New().String(),
+		ItemID:      itemID,
+		ToBranchID:  toBranchID,
+		Status:      models.TransferRequested,
+		RequestedAt: requestedAt,
+		UpdatedAt:   requestedAt,
+	}
+}
+
+func TestMemoryTransferRepository(t *testing.T) {
+	books := NewMemoryBookRepository()
+	items := NewMemoryItemRepository(books)
+	members := NewMemoryMemberRepository()
+	loans := NewMemoryLoanRepository(items, members)
+	holds := NewMemoryHoldRepository(loans)
+	repo := NewMemoryTransferRepository(loans)
+
+	main, north := uuid.New().String(), uuid.New().String()
+	book := newMemoryBook("Emma", "Jane Austen", 1815, "Fiction", time.Now())
+	require.NoError(t, books.Create(book))
+	item := newMemoryItem(book.ID, "E-1")
+	item.HomeBranchID = &main
+	item.CurrentBranchID = &main
+	require.NoError(t, items.Create(item, HoldPickup{}))
+	stray := newMemoryItem(book.ID, "E-2")
+	require.NoError(t, items.Create(stray, HoldPickup{}))
+	pickup := HoldPickup{Today: "2026-03-01", PickupBy: "2026-03-08"}
+
+	t.Run("request, ship and receive", func(t *testing.T) {
+		requestedAt := time.Now().Add(-time.Hour)
+		transfer := newMemoryTransfer(item.ID, north, requestedAt)
+		require.NoError(t, repo.Request(transfer))
+		assert.Equal(t, main, transfer.FromBranchID)
+		assert.Equal(t, "E-1", transfer.Barcode)
+		assert.ErrorIs(t, repo.Request(newMemoryTransfer(item.ID, north, time.Now())), ErrDuplicateTransfer)
+		assert.ErrorIs(t, repo.Request(newMemoryTransfer(stray.ID, north, time.Now())), ErrItemHasNoBranch)
+
+		_, _, err := repo.Receive(transfer.ID, time.Now(), pickup)
+		assert.ErrorIs(t, err, ErrInvalidTransferTransition)
+
+		shipped, err := repo.Ship(transfer.ID, time.Now())
+		require.NoError(t, err)
+		assert.Equal(t, models.TransferInTransit, shipped.Status)
+		stored, err := items.GetByID(item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemInTransit, stored.Status)
+		_, err = repo.Cancel(transfer.ID, time.Now())
+		assert.ErrorIs(t, err, ErrInvalidTransferTransition)
+
+		received, hold, err := repo.Receive(transfer.ID, time.Now(), pickup)
+		require.NoError(t, err)
+		assert.Nil(t, hold)
+		assert.Equal(t, models.TransferReceived, received.Status)
+		stored, err = items.GetByID(item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemAvailable, stored.Status)
+		assert.Equal(t, north, *stored.CurrentBranchID)
+		assert.ErrorIs(t, repo.Request(newMemoryTransfer(item.ID, north, time.Now())), ErrSameBranch)
+	})
+
+	t.Run("checkin ships the copy to the pickup branch", func(t *testing.T) {
+		borrower := newMemoryMember("Emma", "Woodhouse", "00000000000018", models.MembershipAdult, "2030-01-01")
+		require.NoError(t, members.Create(borrower))
+		holder := newMemoryMember("Harriet", "Smith", "00000000000026", models.MembershipAdult, "2030-01-01")
+		require.NoError(t, members.Create(holder))
+		require.NoError(t, loans.Checkout(newMemoryLoan(item.ID, borrower.ID, "2026-02-01", time.Now()), 5))
+		require.NoError(t, loans.Checkout(newMemoryLoan(stray.ID, borrower.ID, "2026-02-01", time.Now()), 5))
+		hold := newMemoryHold(book.ID, holder.ID, &item.ID, time.Now())
+		hold.PickupBranchID = &main
+		require.NoError(t, holds.Place(hold))
+
+		_, trapped, err := loans.Checkin(item.ID, &north, time.Now(), pickup)
+		require.NoError(t, err)
+		require.NotNil(t, trapped)
+		assert.Equal(t, models.HoldInTransit, trapped.Status)
+		assert.Nil(t, trapped.PickupBy)
+
+		open, err := repo.List(&models.TransferListParams{Status: models.TransferInTransit, BranchID: main, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, open.Data, 1)
+		assert.Equal(t, trapped.ID, *open.Data[0].HoldID)
+
+		_, ready, err := repo.Receive(open.Data[0].ID, time.Now(), pickup)
+		require.NoError(t, err)
+		require.NotNil(t, ready)
+		assert.Equal(t, models.HoldReady, ready.Status)
+		assert.Equal(t, pickup.PickupBy, *ready.PickupBy)
+		stored, err := items.GetByID(item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemOnHold, stored.Status)
+		assert.Equal(t, main, *stored.CurrentBranchID)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		all, err := repo.List(&models.TransferListParams{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 2, all.Total)
+		received, err := repo.List(&models.TransferListParams{Status: models.TransferReceived, Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 2, received.Total)
+		assert.Len(t, received.Data, 1)
+
+		_, err = repo.GetByID(uuid.New().String())
+		assert.ErrorIs(t, err, ErrTransferNotFound)
+	})
+}
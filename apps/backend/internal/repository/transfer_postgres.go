@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	transferOpenItemIndex = "idx_transfers_open_item"
+	transferToBranchFKey  = "transfers_to_branch_id_fkey"
+
+	transferColumns = `t.id, t.item_id, i.barcode, t.from_branch_id, t.to_branch_id, t.hold_id, t.status, t.note,
+			  t.requested_at, t.shipped_at, t.received_at, t.cancelled_at, t.updated_at`
+	transferFrom = " FROM transfers t JOIN items i ON i.id = t.item_id"
+)
+
+type PostgresTransferRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTransferRepository(db *sql.DB) *PostgresTransferRepository {
+	return &PostgresTransferRepository{db: db}
+}
+
+func scanTransfer(scanner interface{ Scan(...interface{}) error }, transfer *models.Transfer, extra ...interface{}) error {
+	dest := []interface{}{&transfer.ID, &transfer.ItemID, &transfer.Barcode, &transfer.FromBranchID, &transfer.ToBranchID,
+		&transfer.HoldID, &transfer.Status, &transfer.Note, &transfer.RequestedAt, &transfer.ShippedAt, &transfer.ReceivedAt,
+		&transfer.CancelledAt, &transfer.UpdatedAt}
+	return scanner.Scan(append(dest, extra...)...)
+}
+
+func (r *PostgresTransferRepository) Request(transfer *models.Transfer) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	var currentBranchID *string
+	err = tx.QueryRow("SELECT barcode, status, current_branch_id FROM items WHERE id = $1 FOR UPDATE", transfer.ItemID).
+		Scan(&transfer.Barcode, &status, &currentBranchID)
+	if err == sql.ErrNoRows {
+		return ErrItemNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock item: %w", err)
+	}
+	if status != models.ItemAvailable {
+		return ErrItemNotAvailable
+	}
+	if currentBranchID == nil {
+		return ErrItemHasNoBranch
+	}
+	if *currentBranchID == transfer.ToBranchID {
+		return ErrSameBranch
+	}
+	transfer.FromBranchID = *currentBranchID
+
+	_, err = tx.Exec(`INSERT INTO transfers (id, item_id, from_branch_id, to_branch_id, status, note, requested_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		transfer.ID, transfer.ItemID, transfer.FromBranchID, transfer.ToBranchID, transfer.Status, transfer.Note,
+		transfer.RequestedAt, transfer.UpdatedAt)
+	if isUniqueViolation(err, transferOpenItemIndex) {
+		return ErrDuplicateTransfer
+	}
+	if isForeignKeyViolation(err, transferToBranchFKey) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTransferRepository) Ship(id string, shippedAt time.Time) (*models.Transfer, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transfer, itemStatus, err := lockTransfer(tx, id, models.TransferRequested, models.TransferInTransit)
+	if err != nil {
+		return nil, err
+	}
+	if itemStatus != models.ItemAvailable {
+		return nil, ErrItemNotAvailable
+	}
+
+	_, err = tx.Exec(`UPDATE items SET status = $1, status_note = NULL, status_changed_at = $2, updated_at = $2 WHERE id = $3`,
+		models.ItemInTransit, shippedAt, transfer.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update item status: %w", err)
+	}
+	shipped, err := updateTransfer(tx, `UPDATE transfers t SET status = $1, shipped_at = $2, updated_at = $2 FROM items i
+			  WHERE t.id = $3 AND i.id = t.item_id RETURNING `+transferColumns, models.TransferInTransit, shippedAt, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return shipped, nil
+}
+
+func (r *PostgresTransferRepository) Receive(id string, receivedAt time.Time, pickup HoldPickup) (*models.Transfer, *models.Hold, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transfer, itemStatus, err := lockTransfer(tx, id, models.TransferInTransit, models.TransferReceived)
+	if err != nil {
+		return nil, nil, err
+	}
+	if itemStatus != models.ItemInTransit {
+		return nil, nil, fmt.Errorf("%w: %s to %s", ErrInvalidItemTransition, itemStatus, models.ItemAvailable)
+	}
+
+	received, err := updateTransfer(tx, `UPDATE transfers t SET status = $1, received_at = $2, updated_at = $2 FROM items i
+			  WHERE t.id = $3 AND i.id = t.item_id RETURNING `+transferColumns, models.TransferReceived, receivedAt, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, err = tx.Exec("UPDATE items SET current_branch_id = $1 WHERE id = $2", transfer.ToBranchID, transfer.ItemID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to move item: %w", err)
+	}
+
+	var hold *models.Hold
+	if transfer.HoldID != nil {
+		hold = &models.Hold{}
+		err = scanHold(tx.QueryRow(`UPDATE holds h SET status = $1, ready_at = $2, pickup_by = $3, updated_at = $2
+				  WHERE h.id = $4 AND h.status = $5 RETURNING `+holdColumns,
+			models.HoldReady, receivedAt, pickup.at(&transfer.ToBranchID), *transfer.HoldID, models.HoldInTransit), hold)
+		if err == sql.ErrNoRows {
+			hold = nil
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("failed to ready hold: %w", err)
+		}
+	}
+	if hold != nil {
+		_, err = tx.Exec(`UPDATE items SET status = $1, status_note = NULL, status_changed_at = $2, updated_at = $2 WHERE id = $3`,
+			models.ItemOnHold, receivedAt, transfer.ItemID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update item status: %w", err)
+		}
+	} else {
+		hold, err = trapNextHold(tx, transfer.ItemID, receivedAt, pickup, false)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return received, hold, nil
+}
+
+func (r *PostgresTransferRepository) Cancel(id string, cancelledAt time.Time) (*models.Transfer, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, _, err := lockTransfer(tx, id, models.TransferRequested, models.TransferCancelled); err != nil {
+		return nil, err
+	}
+	cancelled, err := updateTransfer(tx, `UPDATE transfers t SET status = $1, cancelled_at = $2, updated_at = $2 FROM items i
+			  WHERE t.id = $3 AND i.id = t.item_id RETURNING `+transferColumns, models.TransferCancelled, cancelledAt, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+	return cancelled, nil
+}
+
+func (r *PostgresTransferRepository) GetByID(id string) (*models.Transfer, error) {
+	var transfer models.Transfer
+	err := scanTransfer(r.db.QueryRow("SELECT "+transferColumns+transferFrom+" WHERE t.id = $1", id), &transfer)
+	if err == sql.ErrNoRows {
+		return nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+func (r *PostgresTransferRepository) List(params *models.TransferListParams) (*models.TransferListResponse, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+	if params.BranchID != "" {
+		args = append(args, params.BranchID)
+		conditions = append(conditions, fmt.Sprintf("(t.from_branch_id = $%d OR t.to_branch_id = $%[1]d)", len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) OVER () AS total%s%s ORDER BY t.requested_at DESC, t.id LIMIT $%d OFFSET $%d",
+		transferColumns, transferFrom, whereClause(conditions), len(args)+1, len(args)+2)
+	args = append(args, params.Limit, params.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transfers: %w", err)
+	}
+	defer rows.Close()
+
+	response := &models.TransferListResponse{Data: make([]models.Transfer, 0, params.Limit)}
+	for rows.Next() {
+		var transfer models.Transfer
+		if err := scanTransfer(rows, &transfer, &response.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %w", err)
+		}
+		response.Data = append(response.Data, transfer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch transfers: %w", err)
+	}
+
+	return response, nil
+}
+
+// lockTransfer locks a transfer that is to move from status from to status
+// to, after its copy, and returns it with the status of the copy.
+func lockTransfer(tx *sql.Tx, id, from, to string) (*models.Transfer, string, error) {
+	var itemID string
+	err := tx.QueryRow("SELECT item_id FROM transfers WHERE id = $1", id).Scan(&itemID)
+	if err == sql.ErrNoRows {
+		return nil, "", ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch transfer: %w", err)
+	}
+	var itemStatus string
+	if err := tx.QueryRow("SELECT status FROM items WHERE id = $1 FOR UPDATE", itemID).Scan(&itemStatus); err != nil {
+		return nil, "", fmt.Errorf("failed to lock item: %w", err)
+	}
+
+	var transfer models.Transfer
+	err = scanTransfer(tx.QueryRow("SELECT "+transferColumns+transferFrom+" WHERE t.id = $1 FOR UPDATE OF t", id), &transfer)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to lock transfer: %w", err)
+	}
+	if transfer.Status != from {
+		return nil, "", fmt.Errorf("%w: %s to %s", ErrInvalidTransferTransition, transfer.Status, to)
+	}
+	return &transfer, itemStatus, nil
+}
+
+func updateTransfer(tx *sql.Tx, query string, args ...interface{}) (*models.Transfer, error) {
+	var transfer models.Transfer
+	if err := scanTransfer(tx.QueryRow(query, args...), &transfer); err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+	return &transfer, nil
+}
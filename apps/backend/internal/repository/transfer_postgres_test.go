@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var transferRowColumns = []string{"id", "item_id", "barcode", "from_branch_id", "to_branch_id", "hold_id", "status", "note",
+	"requested_at", "shipped_at", "received_at", "cancelled_at", "updated_at"}
+
+func TestPostgresTransferRepository_Request(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresTransferRepository(db)
+	now := time.Now()
+	lockItem := regexp.QuoteMeta("SELECT barcode, status, current_branch_id FROM items WHERE id = $1 FOR UPDATE")
+	insert := regexp.QuoteMeta("INSERT INTO transfers (id, item_id, from_branch_id, to_branch_id, status, note, requested_at, updated_at)")
+	newTransfer := func() *models.Transfer {
+		return &models.Transfer{ID: "t1", ItemID: "i1", ToBranchID: "br-north", Status: models.TransferRequested,
+			RequestedAt: now, UpdatedAt: now}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		transfer := newTransfer()
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows([]string{"barcode", "status", "current_branch_id"}).AddRow("E-1", "available", "br-main"))
+		mock.ExpectExec(insert).
+			WithArgs("t1", "i1", "br-main", "br-north", "requested", nil, now, now).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		require.NoError(t, repo.Request(transfer))
+		assert.Equal(t, "E-1", transfer.Barcode)
+		assert.Equal(t, "br-main", transfer.FromBranchID)
+	})
+
+	t.Run("not on the shelf", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows([]string{"barcode", "status", "current_branch_id"}).AddRow("E-1", "on_loan", "br-main"))
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Request(newTransfer()), ErrItemNotAvailable)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockItem).
+			WithArgs("i1").
+			WillReturnRows(sqlmock.NewRows([]string{"barcode", "status", "current_branch_id"}).AddRow("E-1", "available", "br-main"))
+		mock.ExpectExec(insert).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_transfers_open_item"})
+		mock.ExpectRollback()
+
+		assert.ErrorIs(t, repo.Request(newTransfer()), ErrDuplicateTransfer)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresTransferRepository_ReceiveForHold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresTransferRepository(db)
+	now := time.Now()
+	pickup := HoldPickup{Today: "2026-03-01", PickupBy: "2026-03-08"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT item_id FROM transfers WHERE id = $1")).
+		WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"item_id"}).AddRow("i1"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM items WHERE id = $1 FOR UPDATE")).
+		WithArgs("i1").
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("in_transit"))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE t.id = $1 FOR UPDATE OF t")).
+		WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows(transferRowColumns).
+			AddRow("t1", "i1", "E-1", "br-main", "br-north", "h1", "in_transit", nil, now, now, nil, nil, now))
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE transfers t SET status = $1, received_at = $2, updated_at = $2 FROM items i")).
+		WithArgs("received", now, "t1").
+		WillReturnRows(sqlmock.NewRows(transferRowColumns).
+			AddRow("t1", "i1", "E-1", "br-main", "br-north", "h1", "received", nil, now, now, now, nil, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET current_branch_id = $1 WHERE id = $2")).
+		WithArgs("br-north", "i1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE holds h SET status = $1, ready_at = $2, pickup_by = $3, updated_at = $2 WHERE h.id = $4 AND h.status = $5")).
+		WithArgs("ready", now, "2026-03-08", "h1", "in_transit").
+		WillReturnRows(sqlmock.NewRows(holdRowColumns).
+			AddRow("h1", "b1", "m2", "i1", "title", "ready", "br-north", 0, nil, "2026-03-08", now, now, nil, now))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE items SET status = $1, status_note = NULL, status_changed_at = $2, updated_at = $2 WHERE id = $3")).
+		WithArgs("on_hold", now, "i1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	transfer, hold, err := repo.Receive("t1", now, pickup)
+	require.NoError(t, err)
+	assert.Equal(t, models.TransferReceived, transfer.Status)
+	require.NotNil(t, hold)
+	assert.Equal(t, models.HoldReady, hold.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"errors"
+
+	"library-management-backend/internal/models"
+)
+
+var (
+	ErrVendorNotFound = errors.New("vendor not found")
+	// ErrVendorInUse is returned when deleting a vendor that purchase orders
+	// refer to.
+	ErrVendorInUse = errors.New("vendor has purchase orders")
+)
+
+// VendorRepository persists the vendors the library orders from.
+type VendorRepository interface {
+	// List returns every vendor ordered by name.
+	List() ([]models.Vendor, error)
+	GetByID(id string) (*models.Vendor, error)
+	Create(vendor *models.Vendor) error
+	Update(vendor *models.Vendor) error
+	Delete(id string) error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+
+	"library-management-backend/internal/models"
+)
+
+// MemoryVendorRepository keeps vendors in a map guarded by a RWMutex. It
+// checks deletes against the orders of the MemoryPurchaseOrderRepository
+// sharing it, whose lock is always taken after its own.
+type MemoryVendorRepository struct {
+	mu      sync.RWMutex
+	orders  *MemoryPurchaseOrderRepository
+	vendors map[string]models.Vendor
+}
+
+func NewMemoryVendorRepository() *MemoryVendorRepository {
+	return &MemoryVendorRepository{
+		vendors: make(map[string]models.Vendor),
+	}
+}
+
+func (r *MemoryVendorRepository) List() ([]models.Vendor, error) {
+	r.mu.RLock()
+	vendors := make([]models.Vendor, 0, len(r.vendors))
+	for _, vendor := range r.vendors {
+		vendors = append(vendors, vendor)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(vendors, func(i, j int) bool {
+		if vendors[i].Name != vendors[j].Name {
+			return vendors[i].Name < vendors[j].Name
+		}
+		return vendors[i].ID < vendors[j].ID
+	})
+	return vendors, nil
+}
+
+func (r *MemoryVendorRepository) GetByID(id string) (*models.Vendor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vendor, ok := r.vendors[id]
+	if !ok {
+		return nil, ErrVendorNotFound
+	}
+	return &vendor, nil
+}
+
+func (r *MemoryVendorRepository) Create(vendor *models.Vendor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.vendors[vendor.ID] = *vendor
+	return nil
+}
+
+func (r *MemoryVendorRepository) Update(vendor *models.Vendor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.vendors[vendor.ID]
+	if !ok {
+		return ErrVendorNotFound
+	}
+
+	existing.Name = vendor.Name
+	existing.Email = vendor.Email
+	existing.Phone = vendor.Phone
+	existing.Address = vendor.Address
+	existing.UpdatedAt = vendor.UpdatedAt
+	r.vendors[vendor.ID] = existing
+	return nil
+}
+
+func (r *MemoryVendorRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.vendors[id]; !ok {
+		return ErrVendorNotFound
+	}
+	if r.orders != nil && r.orders.hasVendor(id) {
+		return ErrVendorInUse
+	}
+
+	delete(r.vendors, id)
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"library-management-backend/internal/models"
+)
+
+const (
+	purchaseOrderVendorFKey = "purchase_orders_vendor_id_fkey"
+
+	vendorColumns = "id, name, email, phone, address, created_at, updated_at"
+)
+
+type PostgresVendorRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresVendorRepository(db *sql.DB) *PostgresVendorRepository {
+	return &PostgresVendorRepository{db: db}
+}
+
+func scanVendor(scanner interface{ Scan(...interface{}) error }, vendor *models.Vendor) error {
+	return scanner.Scan(&vendor.ID, &vendor.Name, &vendor.Email, &vendor.Phone, &vendor.Address,
+		&vendor.CreatedAt, &vendor.UpdatedAt)
+}
+
+func (r *PostgresVendorRepository) List() ([]models.Vendor, error) {
+	rows, err := r.db.Query("SELECT " + vendorColumns + " FROM vendors ORDER BY name, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vendors: %w", err)
+	}
+	defer rows.Close()
+
+	vendors := []models.Vendor{}
+	for rows.Next() {
+		var vendor models.Vendor
+		if err := scanVendor(rows, &vendor); err != nil {
+			return nil, fmt.Errorf("failed to scan vendor: %w", err)
+		}
+		vendors = append(vendors, vendor)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch vendors: %w", err)
+	}
+
+	return vendors, nil
+}
+
+func (r *PostgresVendorRepository) GetByID(id string) (*models.Vendor, error) {
+	var vendor models.Vendor
+	err := scanVendor(r.db.QueryRow("SELECT "+vendorColumns+" FROM vendors WHERE id = $1", id), &vendor)
+	if err == sql.ErrNoRows {
+		return nil, ErrVendorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch vendor: %w", err)
+	}
+
+	return &vendor, nil
+}
+
+func (r *PostgresVendorRepository) Create(vendor *models.Vendor) error {
+	_, err := r.db.Exec(`INSERT INTO vendors (id, name, email, phone, address, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		vendor.ID, vendor.Name, vendor.Email, vendor.Phone, vendor.Address, vendor.CreatedAt, vendor.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create vendor: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PostgresVendorRepository) Update(vendor *models.Vendor) error {
+	result, err := r.db.Exec("UPDATE vendors SET name = $1, email = $2, phone = $3, address = $4, updated_at = $5 WHERE id = $6",
+		vendor.Name, vendor.Email, vendor.Phone, vendor.Address, vendor.UpdatedAt, vendor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update vendor: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify update: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrVendorNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresVendorRepository) Delete(id string) error {
+	result, err := r.db.Exec("DELETE FROM vendors WHERE id = $1", id)
+	if isForeignKeyViolation(err, purchaseOrderVendorFKey) {
+		return ErrVendorInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete vendor: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to verify deletion: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrVendorNotFound
+	}
+
+	return nil
+}
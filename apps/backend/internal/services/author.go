@@ -0,0 +1,202 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrAuthorNotFound  = repository.ErrAuthorNotFound
+	ErrDuplicateAuthor = repository.ErrDuplicateAuthor
+	ErrAuthorInUse     = repository.ErrAuthorInUse
+	ErrInvalidLifespan = errors.New("death year is before birth year")
+)
+
+type AuthorService struct {
+	repo   repository.AuthorRepository
+	logger *logrus.Logger
+}
+
+func NewAuthorService(repo repository.AuthorRepository, logger *logrus.Logger) *AuthorService {
+	return &AuthorService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *AuthorService) ListAuthors(params *models.AuthorListParams) (*models.AuthorListResponse, error) {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	s.logger.WithFields(logrus.Fields{
+		"query": query.Query,
+		"limit": query.Limit,
+	}).Info("Fetching authors")
+
+	response, err := s.repo.List(&query)
+	if errors.Is(err, ErrInvalidCursor) {
+		s.logger.WithField("cursor", params.Cursor).Warn("Invalid author cursor")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query authors")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": len(response.Data),
+		"total": response.Total,
+	}).Info("Successfully fetched authors")
+	return response, nil
+}
+
+func (s *AuthorService) GetAuthorByID(id string) (*models.Author, error) {
+	s.logger.WithField("author_id", id).Info("Fetching author by ID")
+
+	author, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrAuthorNotFound) {
+		s.logger.WithField("author_id", id).Warn("Author not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("author_id", id).Error("Failed to fetch author")
+		return nil, err
+	}
+
+	return author, nil
+}
+
+func (s *AuthorService) CreateAuthor(req *models.CreateAuthorRequest) (*models.Author, error) {
+	s.logger.WithField("name", req.Name).Info("Creating new author")
+
+	if err := checkLifespan(req.BirthYear, req.DeathYear); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	author := &models.Author{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		SortName:  authorSortName(req.Name, req.SortName),
+		BirthYear: req.BirthYear,
+		DeathYear: req.DeathYear,
+		Biography: req.Biography,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(author); err != nil {
+		if errors.Is(err, ErrDuplicateAuthor) {
+			s.logger.WithField("name", author.Name).Warn("Duplicate author")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create author")
+		return nil, err
+	}
+
+	s.logger.WithField("author_id", author.ID).Info("Successfully created author")
+	return author, nil
+}
+
+// UpdateAuthor replaces the details of an author. Renaming an author also
+// renames them in the author string of every book crediting them.
+func (s *AuthorService) UpdateAuthor(id string, req *models.UpdateAuthorRequest) (*models.Author, error) {
+	s.logger.WithField("author_id", id).Info("Updating author")
+
+	if err := checkLifespan(req.BirthYear, req.DeathYear); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.GetAuthorByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &models.Author{
+		ID:        existing.ID,
+		Name:      strings.TrimSpace(req.Name),
+		SortName:  authorSortName(req.Name, req.SortName),
+		BirthYear: req.BirthYear,
+		DeathYear: req.DeathYear,
+		Biography: req.Biography,
+		CreatedAt: existing.CreatedAt,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.repo.Update(updated); err != nil {
+		if errors.Is(err, ErrAuthorNotFound) || errors.Is(err, ErrDuplicateAuthor) {
+			s.logger.WithError(err).WithField("author_id", id).Warn("Failed to update author")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("author_id", id).Error("Failed to update author")
+		return nil, err
+	}
+
+	s.logger.WithField("author_id", id).Info("Successfully updated author")
+	return updated, nil
+}
+
+// DeleteAuthor removes an author who is not credited on any book.
+func (s *AuthorService) DeleteAuthor(id string) error {
+	s.logger.WithField("author_id", id).Info("Deleting author")
+
+	err := s.repo.Delete(id)
+	if errors.Is(err, ErrAuthorNotFound) || errors.Is(err, ErrAuthorInUse) {
+		s.logger.WithError(err).WithField("author_id", id).Warn("Failed to delete author")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("author_id", id).Error("Failed to delete author")
+		return err
+	}
+
+	s.logger.WithField("author_id", id).Info("Successfully deleted author")
+	return nil
+}
+
+// ListAuthorBooks pages through the books an author contributed to in any
+// role, or in the role given in params.
+func (s *AuthorService) ListAuthorBooks(id string, params *models.AuthorBooksParams) (*models.AuthorBooksResponse, error) {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	s.logger.WithFields(logrus.Fields{
+		"author_id": id,
+		"role":      query.Role,
+	}).Info("Fetching author books")
+
+	response, err := s.repo.ListBooks(id, &query)
+	if errors.Is(err, ErrAuthorNotFound) {
+		s.logger.WithField("author_id", id).Warn("Author not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("author_id", id).Error("Failed to fetch author books")
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func checkLifespan(birthYear, deathYear *int) error {
+	if birthYear != nil && deathYear != nil && *deathYear < *birthYear {
+		return ErrInvalidLifespan
+	}
+	return nil
+}
+
+// authorSortName returns the requested sort name, or else the name inverted
+// as "Family, Given".
+func authorSortName(name string, sortName *string) string {
+	if sortName != nil && strings.TrimSpace(*sortName) != "" {
+		return strings.TrimSpace(*sortName)
+	}
+	return parsePersonName(strings.TrimSpace(name)).inverted()
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"io"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthorServices() (*BookService, *AuthorService) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	books := repository.NewMemoryBookRepository()
+	authors := repository.NewMemoryAuthorRepository(books)
+	return NewBookService(books, authors, repository.NewMemoryGenreRepository(books), repository.NewMemoryItemRepository(books), logger), NewAuthorService(authors, logger)
+}
+
+func TestAuthorService_CreateAuthor(t *testing.T) {
+	_, service := newTestAuthorServices()
+
+	author, err := service.CreateAuthor(&models.CreateAuthorRequest{Name: " Ursula K. Le Guin "})
+	require.NoError(t, err)
+	assert.Equal(t, "Ursula K. Le Guin", author.Name)
+	assert.Equal(t, "Le Guin, Ursula K.", author.SortName)
+
+	_, err = service.CreateAuthor(&models.CreateAuthorRequest{Name: "Le Guin, Ursula K."})
+	assert.ErrorIs(t, err, ErrDuplicateAuthor)
+
+	birth, death := 1900, 1850
+	_, err = service.CreateAuthor(&models.CreateAuthorRequest{Name: "Nobody", BirthYear: &birth, DeathYear: &death})
+	assert.ErrorIs(t, err, ErrInvalidLifespan)
+}
+
+func TestBookService_Contributors(t *testing.T) {
+	bookService, authorService := newTestAuthorServices()
+
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "War and Peace", Author: "Tolstoy, Leo", Year: 1869})
+	require.NoError(t, err)
+
+	_, contributors, err := bookService.GetContributors(book.ID)
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, "Tolstoy, Leo", contributors[0].Name)
+	assert.Equal(t, models.ContributorAuthor, contributors[0].Role)
+
+	tolstoy := contributors[0].AuthorID
+	translator, err := authorService.CreateAuthor(&models.CreateAuthorRequest{Name: "Louise Maude"})
+	require.NoError(t, err)
+
+	t.Run("set", func(t *testing.T) {
+		req := &models.SetContributorsRequest{Contributors: []models.ContributorInput{
+			{AuthorID: tolstoy, Role: models.ContributorAuthor},
+			{AuthorID: translator.ID, Role: models.ContributorTranslator},
+		}}
+		updated, contributors, err := bookService.SetContributors(book.ID, req, book.Version)
+		require.NoError(t, err)
+		assert.Equal(t, "Tolstoy, Leo", updated.Author)
+		assert.Equal(t, 2, updated.Version)
+		assert.Equal(t, "Louise Maude", contributors[1].Name)
+		assert.Equal(t, 1, contributors[1].Position)
+
+		_, _, err = bookService.SetContributors(book.ID, req, book.Version)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("no author role", func(t *testing.T) {
+		req := &models.SetContributorsRequest{Contributors: []models.ContributorInput{
+			{AuthorID: translator.ID, Role: models.ContributorEditor},
+		}}
+		updated, _, err := bookService.SetContributors(book.ID, req, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Louise Maude", updated.Author)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		req := &models.SetContributorsRequest{Contributors: []models.ContributorInput{
+			{AuthorID: tolstoy, Role: models.ContributorAuthor},
+			{AuthorID: tolstoy, Role: models.ContributorAuthor},
+		}}
+		_, _, err := bookService.SetContributors(book.ID, req, 0)
+		assert.ErrorIs(t, err, ErrInvalidContributors)
+
+		req.Contributors[1].AuthorID = "3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e"
+		_, _, err = bookService.SetContributors(book.ID, req, 0)
+		assert.ErrorIs(t, err, ErrAuthorNotFound)
+	})
+
+	t.Run("author string edits relink authors", func(t *testing.T) {
+		current, err := bookService.GetBookByID(book.ID)
+		require.NoError(t, err)
+		patch := current.Editable()
+		patch.Author = "Leo Tolstoy & Louise Maude"
+		_, err = bookService.PatchBook(current, &patch)
+		require.NoError(t, err)
+
+		_, contributors, err := bookService.GetContributors(book.ID)
+		require.NoError(t, err)
+		require.Len(t, contributors, 3)
+		assert.Equal(t, tolstoy, contributors[0].AuthorID)
+		assert.Equal(t, models.ContributorAuthor, contributors[0].Role)
+
+		response, err := authorService.ListAuthorBooks(translator.ID, &models.AuthorBooksParams{})
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Total)
+		assert.ElementsMatch(t, []string{models.ContributorAuthor, models.ContributorEditor}, response.Data[0].Roles)
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		_, err := authorService.UpdateAuthor(tolstoy, &models.UpdateAuthorRequest{Name: "Lev Tolstoy"})
+		require.NoError(t, err)
+
+		renamed, err := bookService.GetBookByID(book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Lev Tolstoy; Louise Maude", renamed.Author)
+
+		assert.ErrorIs(t, authorService.DeleteAuthor(tolstoy), ErrAuthorInUse)
+	})
+}
@@ -1,129 +1,242 @@
 package services
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/pkg/isbn"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	defaultBookPageSize = 20
+	maxBookPageSize     = 100
+)
+
+var (
+	ErrBookNotFound    = repository.ErrBookNotFound
+	ErrInvalidCursor   = repository.ErrInvalidCursor
+	ErrDuplicateISBN   = repository.ErrDuplicateISBN
+	ErrVersionConflict = repository.ErrVersionConflict
+	ErrInvalidISBN     = errors.New("invalid isbn")
+	// ErrBookInCirculation is returned when deleting a book with copies on
+	// loan or holds waiting.
+	ErrBookInCirculation = repository.ErrBookInCirculation
+)
+
 type BookService struct {
-	db     *sql.DB
-	logger *logrus.Logger
+	repo    repository.BookRepository
+	authors repository.AuthorRepository
+	genres  repository.GenreRepository
+	items   repository.ItemRepository
+	logger  *logrus.Logger
 }
 
-func NewBookService(db *sql.DB, logger *logrus.Logger) *BookService {
+func NewBookService(repo repository.BookRepository, authors repository.AuthorRepository, genres repository.GenreRepository, items repository.ItemRepository, logger *logrus.Logger) *BookService {
 	return &BookService{
-		db:     db,
-		logger: logger,
+		repo:    repo,
+		authors: authors,
+		genres:  genres,
+		items:   items,
+		logger:  logger,
 	}
 }
 
-func (s *BookService) GetAllBooks() ([]models.Book, error) {
-	s.logger.Info("Fetching all books")
+func (s *BookService) ListBooks(params *models.BookListParams) (*models.BookListResponse, error) {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+	normalizeBookOrder(&query)
 
-	query := `SELECT id, title, author, year, description, isbn, genre, created_at, updated_at 
-			  FROM books ORDER BY created_at DESC`
+	s.logger.WithFields(logrus.Fields{
+		"limit": query.Limit,
+		"sort":  query.Sort,
+		"order": query.Order,
+	}).Info("Fetching books")
 
-	rows, err := s.db.Query(query)
+	genreIDs, err := s.genreSubtree(query.Genre)
+	if err != nil {
+		return nil, err
+	}
+	query.GenreIDs = genreIDs
+
+	response, err := s.repo.List(&query)
+	if errors.Is(err, ErrInvalidCursor) {
+		s.logger.WithField("cursor", params.Cursor).Warn("Invalid book cursor")
+		return nil, err
+	}
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to query books")
-		return nil, fmt.Errorf("failed to fetch books: %w", err)
-	}
-	defer rows.Close()
-
-	var books []models.Book
-	for rows.Next() {
-		var book models.Book
-		err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Year,
-			&book.Description, &book.ISBN, &book.Genre,
-			&book.CreatedAt, &book.UpdatedAt)
-		if err != nil {
-			s.logger.WithError(err).Error("Failed to scan book")
-			return nil, fmt.Errorf("failed to scan book: %w", err)
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": len(response.Data),
+		"total": response.Total,
+	}).Info("Successfully fetched books")
+	return response, nil
+}
+
+// normalizeBookOrder defaults to newest first, and to ascending order for
+// any other sort field.
+func normalizeBookOrder(params *models.BookListParams) {
+	if params.Sort == "" {
+		params.Sort = "created_at"
+	}
+	if params.Order == "" {
+		params.Order = "asc"
+		if params.Sort == "created_at" {
+			params.Order = "desc"
 		}
-		books = append(books, book)
 	}
+}
+
+// SearchBooks runs a full-text search over title, author, genre and
+// description, ranked by relevance. When a language is given the query is
+// stemmed with that configuration and only books in that language match;
+// a genre limits the results like it does for ListBooks.
+func (s *BookService) SearchBooks(params *models.BookSearchParams) (*models.BookSearchResponse, error) {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
 
-	s.logger.WithField("count", len(books)).Info("Successfully fetched books")
-	return books, nil
+	s.logger.WithFields(logrus.Fields{
+		"query":    query.Query,
+		"language": query.Lang,
+	}).Info("Searching books")
+
+	genreIDs, err := s.genreSubtree(query.Genre)
+	if err != nil {
+		return nil, err
+	}
+	query.GenreIDs = genreIDs
+
+	response, err := s.repo.Search(&query)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to search books")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"query": query.Query,
+		"count": len(response.Data),
+		"total": response.Total,
+	}).Info("Successfully searched books")
+	return response, nil
 }
 
 func (s *BookService) GetBookByID(id string) (*models.Book, error) {
 	s.logger.WithField("book_id", id).Info("Fetching book by ID")
 
-	query := `SELECT id, title, author, year, description, isbn, genre, created_at, updated_at 
-			  FROM books WHERE id = $1`
-
-	var book models.Book
-	err := s.db.QueryRow(query, id).Scan(
-		&book.ID, &book.Title, &book.Author, &book.Year,
-		&book.Description, &book.ISBN, &book.Genre,
-		&book.CreatedAt, &book.UpdatedAt)
-
-	if err == sql.ErrNoRows {
+	book, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrBookNotFound) {
 		s.logger.WithField("book_id", id).Warn("Book not found")
-		return nil, fmt.Errorf("book not found")
+		return nil, err
 	}
 	if err != nil {
 		s.logger.WithError(err).WithField("book_id", id).Error("Failed to fetch book")
-		return nil, fmt.Errorf("failed to fetch book: %w", err)
+		return nil, err
 	}
 
 	s.logger.WithField("book_id", id).Info("Successfully fetched book")
-	return &book, nil
+	return book, nil
+}
+
+// GetBookWithAvailability returns a book with the availability of its
+// copies filled in.
+func (s *BookService) GetBookWithAvailability(id string) (*models.Book, error) {
+	book, err := s.GetBookByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	availability, err := s.items.Availability(id, "")
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to count book items")
+		return nil, err
+	}
+	book.Availability = availability
+
+	return book, nil
 }
 
 func (s *BookService) CreateBook(req *models.CreateBookRequest) (*models.Book, error) {
 	s.logger.WithField("title", req.Title).Info("Creating new book")
 
+	normalizedISBN, err := normalizeISBN(req.ISBN)
+	if err != nil {
+		s.logger.WithError(err).Warn("Rejected invalid ISBN")
+		return nil, err
+	}
+
+	language := models.DefaultSearchLanguage
+	if req.Language != nil {
+		language = *req.Language
+	}
+
 	book := &models.Book{
 		ID:          uuid.New().String(),
 		Title:       req.Title,
 		Author:      req.Author,
 		Year:        req.Year,
 		Description: req.Description,
-		ISBN:        req.ISBN,
+		ISBN:        normalizedISBN,
 		Genre:       req.Genre,
+		Language:    language,
+		Version:     1,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
-	query := `INSERT INTO books (id, title, author, year, description, isbn, genre, created_at, updated_at) 
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
-
-	_, err := s.db.Exec(query, book.ID, book.Title, book.Author, book.Year,
-		book.Description, book.ISBN, book.Genre, book.CreatedAt, book.UpdatedAt)
-	if err != nil {
+	if err := s.repo.Create(book); err != nil {
+		if errors.Is(err, ErrDuplicateISBN) {
+			s.logger.WithField("isbn", *book.ISBN).Warn("Duplicate ISBN")
+			return nil, err
+		}
 		s.logger.WithError(err).Error("Failed to create book")
-		return nil, fmt.Errorf("failed to create book: %w", err)
+		return nil, err
 	}
 
+	s.linkAuthors(book)
+	s.linkGenre(book)
+
 	s.logger.WithField("book_id", book.ID).Info("Successfully created book")
 	return book, nil
 }
 
-func (s *BookService) UpdateBook(id string, req *models.UpdateBookRequest) (*models.Book, error) {
+// UpdateBook replaces the editable fields of a book. When expectedVersion is
+// not 0 the update only happens if the book is still at that version;
+// otherwise it fails with ErrVersionConflict. Either way a concurrent write
+// between reading and updating the book is reported as ErrVersionConflict.
+func (s *BookService) UpdateBook(id string, req *models.UpdateBookRequest, expectedVersion int) (*models.Book, error) {
 	s.logger.WithField("book_id", id).Info("Updating book")
 
-	existingBook, err := s.GetBookByID(id)
+	normalizedISBN, err := normalizeISBN(req.ISBN)
 	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Warn("Rejected invalid ISBN")
 		return nil, err
 	}
 
-	query := `UPDATE books SET title = $1, author = $2, year = $3, description = $4, 
-			  isbn = $5, genre = $6, updated_at = $7 WHERE id = $8`
-
-	now := time.Now()
-	_, err = s.db.Exec(query, req.Title, req.Author, req.Year, req.Description,
-		req.ISBN, req.Genre, now, id)
+	existingBook, err := s.GetBookByID(id)
 	if err != nil {
-		s.logger.WithError(err).WithField("book_id", id).Error("Failed to update book")
-		return nil, fmt.Errorf("failed to update book: %w", err)
+		return nil, err
+	}
+	if expectedVersion != 0 && existingBook.Version != expectedVersion {
+		s.logger.WithFields(logrus.Fields{
+			"book_id":  id,
+			"expected": expectedVersion,
+			"version":  existingBook.Version,
+		}).Warn("Book version mismatch")
+		return nil, ErrVersionConflict
+	}
+
+	language := existingBook.Language
+	if req.Language != nil {
+		language = *req.Language
 	}
 
 	updatedBook := &models.Book{
@@ -132,36 +245,240 @@ func (s *BookService) UpdateBook(id string, req *models.UpdateBookRequest) (*mod
 		Author:      req.Author,
 		Year:        req.Year,
 		Description: req.Description,
-		ISBN:        req.ISBN,
+		ISBN:        normalizedISBN,
 		Genre:       req.Genre,
+		Language:    language,
+		Version:     existingBook.Version,
 		CreatedAt:   existingBook.CreatedAt,
-		UpdatedAt:   now,
+		UpdatedAt:   time.Now(),
+	}
+
+	if err := s.repo.Update(updatedBook); err != nil {
+		if errors.Is(err, ErrDuplicateISBN) {
+			s.logger.WithField("isbn", *updatedBook.ISBN).Warn("Duplicate ISBN")
+			return nil, err
+		}
+		if errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrVersionConflict) {
+			s.logger.WithError(err).WithField("book_id", id).Warn("Failed to update book")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to update book")
+		return nil, err
+	}
+
+	if updatedBook.Author != existingBook.Author {
+		s.linkAuthors(updatedBook)
+	}
+	if !equalStringPtr(updatedBook.Genre, existingBook.Genre) {
+		s.linkGenre(updatedBook)
 	}
 
 	s.logger.WithField("book_id", id).Info("Successfully updated book")
 	return updatedBook, nil
 }
 
-func (s *BookService) DeleteBook(id string) error {
+// PatchBook writes the fields of merged that differ from current, the book
+// the patch was applied to. It returns current unchanged when nothing differs
+// and ErrVersionConflict when the stored book is no longer at current's
+// version.
+func (s *BookService) PatchBook(current *models.Book, merged *models.UpdateBookRequest) (*models.Book, error) {
+	s.logger.WithField("book_id", current.ID).Info("Patching book")
+
+	normalizedISBN, err := normalizeISBN(merged.ISBN)
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", current.ID).Warn("Rejected invalid ISBN")
+		return nil, err
+	}
+
+	patched := *current
+	patched.Title = merged.Title
+	patched.Author = merged.Author
+	patched.Year = merged.Year
+	patched.Description = merged.Description
+	patched.ISBN = normalizedISBN
+	patched.Genre = merged.Genre
+	if merged.Language != nil {
+		patched.Language = *merged.Language
+	}
+
+	changes := bookChanges(current, &patched)
+	if len(changes) == 0 {
+		s.logger.WithField("book_id", current.ID).Info("Patch did not change the book")
+		return current, nil
+	}
+
+	patched.UpdatedAt = time.Now()
+	changes["updated_at"] = patched.UpdatedAt
+
+	if err := s.repo.UpdateFields(current.ID, current.Version, changes); err != nil {
+		if errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrDuplicateISBN) || errors.Is(err, ErrVersionConflict) {
+			s.logger.WithError(err).WithField("book_id", current.ID).Warn("Failed to patch book")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("book_id", current.ID).Error("Failed to patch book")
+		return nil, err
+	}
+	patched.Version++
+	if _, ok := changes["author"]; ok {
+		s.linkAuthors(&patched)
+	}
+	if _, ok := changes["genre"]; ok {
+		s.linkGenre(&patched)
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"book_id": current.ID,
+		"fields":  len(changes) - 1,
+	}).Info("Successfully patched book")
+	return &patched, nil
+}
+
+// DeleteBook moves a book to the trash, from where it can be restored until it
+// is purged. When expectedVersion is not 0 the book is only trashed if it is
+// still at that version; otherwise it fails with ErrVersionConflict.
+func (s *BookService) DeleteBook(id string, expectedVersion int) error {
 	s.logger.WithField("book_id", id).Info("Deleting book")
 
-	result, err := s.db.Exec("DELETE FROM books WHERE id = $1", id)
+	err := s.repo.Delete(id, expectedVersion, time.Now())
+	if errors.Is(err, ErrBookNotFound) {
+		s.logger.WithField("book_id", id).Warn("Book not found for deletion")
+		return err
+	}
+	if errors.Is(err, ErrVersionConflict) {
+		s.logger.WithFields(logrus.Fields{
+			"book_id":  id,
+			"expected": expectedVersion,
+		}).Warn("Book version mismatch")
+		return err
+	}
+	if errors.Is(err, ErrBookInCirculation) {
+		s.logger.WithField("book_id", id).Warn("Book is in circulation")
+		return err
+	}
 	if err != nil {
 		s.logger.WithError(err).WithField("book_id", id).Error("Failed to delete book")
-		return fmt.Errorf("failed to delete book: %w", err)
+		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	s.logger.WithField("book_id", id).Info("Successfully moved book to trash")
+	return nil
+}
+
+// ListTrash pages through trashed books, most recently deleted first.
+func (s *BookService) ListTrash(params *models.TrashListParams) (*models.BookListResponse, error) {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	s.logger.WithField("limit", query.Limit).Info("Fetching trashed books")
+
+	response, err := s.repo.ListTrash(&query)
+	if errors.Is(err, ErrInvalidCursor) {
+		s.logger.WithField("cursor", params.Cursor).Warn("Invalid trash cursor")
+		return nil, err
+	}
 	if err != nil {
-		s.logger.WithError(err).WithField("book_id", id).Error("Failed to get rows affected")
-		return fmt.Errorf("failed to verify deletion: %w", err)
+		s.logger.WithError(err).Error("Failed to query trashed books")
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		s.logger.WithField("book_id", id).Warn("Book not found for deletion")
-		return fmt.Errorf("book not found")
+	s.logger.WithFields(logrus.Fields{
+		"count": len(response.Data),
+		"total": response.Total,
+	}).Info("Successfully fetched trashed books")
+	return response, nil
+}
+
+// RestoreBook takes a book out of the trash. It fails with ErrBookNotFound
+// when the book is not in the trash and with ErrDuplicateISBN when its ISBN
+// has been given to another book in the meantime.
+func (s *BookService) RestoreBook(id string) (*models.Book, error) {
+	s.logger.WithField("book_id", id).Info("Restoring book")
+
+	err := s.repo.Restore(id, time.Now())
+	if errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrDuplicateISBN) {
+		s.logger.WithError(err).WithField("book_id", id).Warn("Failed to restore book")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to restore book")
+		return nil, err
 	}
 
-	s.logger.WithField("book_id", id).Info("Successfully deleted book")
-	return nil
+	s.logger.WithField("book_id", id).Info("Successfully restored book")
+	return s.GetBookByID(id)
+}
+
+// PurgeTrash permanently removes books that have been in the trash for longer
+// than retention and returns how many were removed.
+func (s *BookService) PurgeTrash(retention time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-retention)
+	s.logger.WithField("deleted_before", cutoff).Info("Purging trashed books")
+
+	purged, err := s.repo.Purge(cutoff)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to purge trashed books")
+		return 0, err
+	}
+
+	s.logger.WithField("purged", purged).Info("Successfully purged trashed books")
+	return purged, nil
+}
+
+func normalizePageSize(limit int) int {
+	if limit <= 0 {
+		return defaultBookPageSize
+	}
+	if limit > maxBookPageSize {
+		return maxBookPageSize
+	}
+	return limit
+}
+
+// normalizeISBN stores every ISBN as an unhyphenated ISBN-13; blank values are
+// stored as NULL.
+func normalizeISBN(value *string) (*string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil, nil
+	}
+
+	normalized, err := isbn.Normalize(*value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidISBN, err)
+	}
+	return &normalized, nil
+}
+
+// bookChanges returns the columns whose values differ between before and
+// after, keyed by column name.
+func bookChanges(before, after *models.Book) map[string]interface{} {
+	changes := make(map[string]interface{})
+	if before.Title != after.Title {
+		changes["title"] = after.Title
+	}
+	if before.Author != after.Author {
+		changes["author"] = after.Author
+	}
+	if before.Year != after.Year {
+		changes["year"] = after.Year
+	}
+	if !equalStringPtr(before.Description, after.Description) {
+		changes["description"] = after.Description
+	}
+	if !equalStringPtr(before.ISBN, after.ISBN) {
+		changes["isbn"] = after.ISBN
+	}
+	if !equalStringPtr(before.Genre, after.Genre) {
+		changes["genre"] = after.Genre
+	}
+	if before.Language != after.Language {
+		changes["language"] = after.Language
+	}
+	return changes
+}
+
+func equalStringPtr(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
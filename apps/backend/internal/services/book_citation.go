@@ -0,0 +1,397 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"library-management-backend/internal/models"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// personName is an author split into family and given names. Organizations
+// and single names only have a family name.
+type personName struct {
+	Family string
+	Given  string
+}
+
+// nameParticles belong to the family name when they precede it, as in
+// "Ursula K. Le Guin" or "Ludwig van Beethoven".
+var nameParticles = map[string]bool{
+	"da": true, "de": true, "del": true, "della": true, "der": true, "di": true,
+	"du": true, "la": true, "le": true, "van": true, "von": true,
+}
+
+var authorSeparator = regexp.MustCompile(`\s*(?:;|\s&\s|\sand\s)\s*`)
+
+// splitAuthorNames splits the author field into names. Several authors may
+// be separated by ";", "&" or "and".
+func splitAuthorNames(author string) []string {
+	var names []string
+	for _, part := range authorSeparator.Split(strings.TrimSpace(author), -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			names = append(names, part)
+		}
+	}
+	return names
+}
+
+// bookAuthors splits the author field into names, each of which may be
+// written either as "Given Family" or inverted as "Family, Given".
+func bookAuthors(author string) []personName {
+	var names []personName
+	for _, name := range splitAuthorNames(author) {
+		names = append(names, parsePersonName(name))
+	}
+	return names
+}
+
+func parsePersonName(name string) personName {
+	if family, given, found := strings.Cut(name, ","); found {
+		return personName{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)}
+	}
+
+	words := strings.Fields(name)
+	if len(words) == 1 {
+		return personName{Family: words[0]}
+	}
+	start := len(words) - 1
+	for start > 1 && nameParticles[strings.ToLower(words[start-1])] {
+		start--
+	}
+	return personName{
+		Family: strings.Join(words[start:], " "),
+		Given:  strings.Join(words[:start], " "),
+	}
+}
+
+// inverted returns "Family, Given".
+func (n personName) inverted() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Family + ", " + n.Given
+}
+
+// natural returns "Given Family".
+func (n personName) natural() string {
+	if n.Given == "" {
+		return n.Family
+	}
+	return n.Given + " " + n.Family
+}
+
+// initials abbreviates the given names for APA: "John Ronald" and "J.R."
+// both become "J. R.", and "Jean-Paul" becomes "J.-P.".
+func (n personName) initials() string {
+	var parts []string
+	for _, word := range strings.FieldsFunc(n.Given, func(r rune) bool { return r == ' ' || r == '.' }) {
+		var hyphenated []string
+		for _, piece := range strings.Split(word, "-") {
+			if r := []rune(piece); len(r) > 0 {
+				hyphenated = append(hyphenated, string(r[0])+".")
+			}
+		}
+		parts = append(parts, strings.Join(hyphenated, "-"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatCitation renders a reference list entry for a book in the given
+// style. Titles are used as cataloged; the styles' capitalization rules are
+// not applied because they cannot tell proper nouns apart.
+func FormatCitation(book *models.Book, style string) (*models.BookCitation, error) {
+	var authors string
+	names := bookAuthors(book.Author)
+
+	switch style {
+	case models.CitationAPA:
+		authors = apaAuthors(names)
+	case models.CitationMLA:
+		authors = mlaAuthors(names)
+	case models.CitationChicago:
+		authors = chicagoAuthors(names)
+	default:
+		return nil, fmt.Errorf("unsupported citation style: %s", style)
+	}
+
+	title := strings.TrimRight(book.Title, ".")
+	var text, markup string
+	if style == models.CitationAPA {
+		// Author, A. A. (Year). Title.
+		prefix := fmt.Sprintf("%s (%d). ", sentence(authors), book.Year)
+		text = prefix + title + "."
+		markup = html.EscapeString(prefix) + "<i>" + html.EscapeString(title) + "</i>."
+	} else {
+		// Author, Given. Title. Year.
+		prefix := sentence(authors) + " "
+		suffix := fmt.Sprintf(". %d.", book.Year)
+		text = prefix + title + suffix
+		markup = html.EscapeString(prefix) + "<i>" + html.EscapeString(title) + "</i>" + suffix
+	}
+
+	return &models.BookCitation{Style: style, Text: text, HTML: markup}, nil
+}
+
+// sentence ends s with a single period.
+func sentence(s string) string {
+	return strings.TrimRight(s, ".") + "."
+}
+
+// apaAuthors lists every author inverted with initials, joining the last
+// one with "&".
+func apaAuthors(names []personName) string {
+	formatted := make([]string, len(names))
+	for i, name := range names {
+		formatted[i] = personName{Family: name.Family, Given: name.initials()}.inverted()
+	}
+	switch len(formatted) {
+	case 0:
+		return ""
+	case 1:
+		return formatted[0]
+	case 2:
+		return formatted[0] + ", & " + formatted[1]
+	default:
+		return strings.Join(formatted[:len(formatted)-1], ", ") + ", & " + formatted[len(formatted)-1]
+	}
+}
+
+// mlaAuthors inverts the first author; a second is added in natural order
+// and three or more are shortened to "et al.".
+func mlaAuthors(names []personName) string {
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0].inverted()
+	case 2:
+		return names[0].inverted() + ", and " + names[1].natural()
+	default:
+		return names[0].inverted() + ", et al."
+	}
+}
+
+// chicagoAuthors inverts the first author and lists the rest in natural
+// order, as in "Family, Given, Given Family, and Given Family".
+func chicagoAuthors(names []personName) string {
+	if len(names) == 0 {
+		return ""
+	}
+	formatted := []string{names[0].inverted()}
+	for _, name := range names[1:] {
+		formatted = append(formatted, name.natural())
+	}
+	switch len(formatted) {
+	case 1:
+		return formatted[0]
+	case 2:
+		return formatted[0] + ", and " + formatted[1]
+	default:
+		return strings.Join(formatted[:len(formatted)-1], ", ") + ", and " + formatted[len(formatted)-1]
+	}
+}
+
+// bibtexEncoder writes one @book entry per book. Citation keys are made of
+// the first author's family name, the year and the first word of the title,
+// with a letter appended when an export would repeat a key.
+type bibtexEncoder struct {
+	w    io.Writer
+	keys map[string]int
+}
+
+var bibtexEscapes = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+)
+
+// titleStopWords are skipped when picking the title word of a citation key.
+var titleStopWords = map[string]bool{"a": true, "an": true, "the": true}
+
+func (e *bibtexEncoder) citationKey(book *models.Book, names []personName) string {
+	var family, word string
+	if len(names) > 0 {
+		family = asciiWord(names[0].Family)
+	}
+	for _, w := range strings.Fields(book.Title) {
+		if w = asciiWord(w); w != "" && !titleStopWords[w] {
+			word = w
+			break
+		}
+	}
+
+	key := fmt.Sprintf("%s%d%s", family, book.Year, word)
+	count := e.keys[key]
+	e.keys[key] = count + 1
+	if count == 0 {
+		return key
+	}
+	return key + string(rune('a'+(count-1)%26)) + strings.Repeat("z", (count-1)/26)
+}
+
+// asciiWord lower-cases s and keeps only its ASCII letters and digits,
+// dropping accents first so "Gödel" becomes "godel".
+func asciiWord(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+func (e *bibtexEncoder) Encode(book *models.Book) error {
+	names := bookAuthors(book.Author)
+	authors := make([]string, len(names))
+	for i, name := range names {
+		authors[i] = name.inverted()
+	}
+
+	fields := [][2]string{
+		{"author", strings.Join(authors, " and ")},
+		{"title", book.Title},
+		{"year", strconv.Itoa(book.Year)},
+	}
+	if book.ISBN != nil {
+		fields = append(fields, [2]string{"isbn", *book.ISBN})
+	}
+	if book.Description != nil {
+		fields = append(fields, [2]string{"abstract", *book.Description})
+	}
+	if book.Genre != nil {
+		fields = append(fields, [2]string{"keywords", *book.Genre})
+	}
+	fields = append(fields, [2]string{"language", book.Language})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@book{%s,\n", e.citationKey(book, names))
+	for _, field := range fields {
+		value := bibtexEscapes.Replace(field[1])
+		if field[0] == "title" {
+			// Double braces keep BibTeX styles from changing the case.
+			value = "{" + value + "}"
+		}
+		fmt.Fprintf(&b, "  %s = {%s},\n", field[0], value)
+	}
+	b.WriteString("}\n\n")
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *bibtexEncoder) Close() error {
+	return nil
+}
+
+// risEncoder writes RIS records, with the CRLF line endings the format
+// specifies.
+type risEncoder struct {
+	w io.Writer
+}
+
+func (e *risEncoder) Encode(book *models.Book) error {
+	var b strings.Builder
+	tag := func(name, value string) {
+		// A value cannot span lines in RIS.
+		value = strings.Join(strings.Fields(value), " ")
+		fmt.Fprintf(&b, "%s  - %s\r\n", name, value)
+	}
+
+	tag("TY", "BOOK")
+	for _, name := range bookAuthors(book.Author) {
+		tag("AU", name.inverted())
+	}
+	tag("TI", book.Title)
+	tag("PY", strconv.Itoa(book.Year))
+	if book.ISBN != nil {
+		tag("SN", *book.ISBN)
+	}
+	if book.Description != nil {
+		tag("AB", *book.Description)
+	}
+	if book.Genre != nil {
+		tag("KW", *book.Genre)
+	}
+	tag("LA", book.Language)
+	tag("ID", book.ID)
+	b.WriteString("ER  - \r\n")
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *risEncoder) Close() error {
+	return nil
+}
+
+type cslName struct {
+	Family string `json:"family"`
+	Given  string `json:"given,omitempty"`
+}
+
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// cslItem is a CSL-JSON item, the input format of citeproc processors.
+type cslItem struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Author   []cslName `json:"author,omitempty"`
+	Issued   cslDate   `json:"issued"`
+	ISBN     string    `json:"ISBN,omitempty"`
+	Abstract string    `json:"abstract,omitempty"`
+	Keyword  string    `json:"keyword,omitempty"`
+	Language string    `json:"language,omitempty"`
+}
+
+func newCSLItem(book *models.Book) cslItem {
+	item := cslItem{
+		ID:       book.ID,
+		Type:     "book",
+		Title:    book.Title,
+		Issued:   cslDate{DateParts: [][]int{{book.Year}}},
+		ISBN:     exportCell(book.ISBN),
+		Abstract: exportCell(book.Description),
+		Keyword:  exportCell(book.Genre),
+		Language: book.Language,
+	}
+	for _, name := range bookAuthors(book.Author) {
+		item.Author = append(item.Author, cslName{Family: name.Family, Given: name.Given})
+	}
+	return item
+}
+
+// cslJSONEncoder writes a CSL-JSON array, one item per line.
+type cslJSONEncoder struct {
+	items *jsonBookEncoder
+}
+
+func (e *cslJSONEncoder) Encode(book *models.Book) error {
+	data, err := json.Marshal(newCSLItem(book))
+	if err != nil {
+		return err
+	}
+	return e.items.writeElement(data)
+}
+
+func (e *cslJSONEncoder) Close() error {
+	return e.items.Close()
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"library-management-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookAuthors(t *testing.T) {
+	testCases := []struct {
+		author   string
+		expected []personName
+	}{
+		{author: "Frank Herbert", expected: []personName{{Family: "Herbert", Given: "Frank"}}},
+		{author: "Herbert, Frank", expected: []personName{{Family: "Herbert", Given: "Frank"}}},
+		{author: "Ursula K. Le Guin", expected: []personName{{Family: "Le Guin", Given: "Ursula K."}}},
+		{author: "Plato", expected: []personName{{Family: "Plato"}}},
+		{author: "Terry Pratchett & Neil Gaiman", expected: []personName{
+			{Family: "Pratchett", Given: "Terry"},
+			{Family: "Gaiman", Given: "Neil"},
+		}},
+		{author: "Kernighan, Brian; Ritchie, Dennis", expected: []personName{
+			{Family: "Kernighan", Given: "Brian"},
+			{Family: "Ritchie", Given: "Dennis"},
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.author, func(t *testing.T) {
+			assert.Equal(t, tc.expected, bookAuthors(tc.author))
+		})
+	}
+
+	assert.Equal(t, "J. R. R.", personName{Given: "J.R.R."}.initials())
+	assert.Equal(t, "J.-P.", personName{Given: "Jean-Paul"}.initials())
+}
+
+func TestFormatCitation(t *testing.T) {
+	hobbit := &models.Book{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937}
+	goodOmens := &models.Book{Title: "Good Omens", Author: "Terry Pratchett and Neil Gaiman", Year: 1990}
+	sicp := &models.Book{Title: "Structure & Interpretation", Author: "Harold Abelson; Gerald Jay Sussman; Julie Sussman", Year: 1985}
+
+	testCases := []struct {
+		name     string
+		book     *models.Book
+		style    string
+		expected string
+	}{
+		{name: "APA", book: hobbit, style: models.CitationAPA, expected: "Tolkien, J. R. R. (1937). The Hobbit."},
+		{name: "APA two authors", book: goodOmens, style: models.CitationAPA, expected: "Pratchett, T., & Gaiman, N. (1990). Good Omens."},
+		{name: "APA three authors", book: sicp, style: models.CitationAPA, expected: "Abelson, H., Sussman, G. J., & Sussman, J. (1985). Structure & Interpretation."},
+		{name: "MLA", book: hobbit, style: models.CitationMLA, expected: "Tolkien, J.R.R. The Hobbit. 1937."},
+		{name: "MLA two authors", book: goodOmens, style: models.CitationMLA, expected: "Pratchett, Terry, and Neil Gaiman. Good Omens. 1990."},
+		{name: "MLA three authors", book: sicp, style: models.CitationMLA, expected: "Abelson, Harold, et al. Structure & Interpretation. 1985."},
+		{name: "Chicago", book: hobbit, style: models.CitationChicago, expected: "Tolkien, J.R.R. The Hobbit. 1937."},
+		{name: "Chicago three authors", book: sicp, style: models.CitationChicago, expected: "Abelson, Harold, Gerald Jay Sussman, and Julie Sussman. Structure & Interpretation. 1985."},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			citation, err := FormatCitation(tc.book, tc.style)
+			require.NoError(t, err)
+			assert.Equal(t, tc.style, citation.Style)
+			assert.Equal(t, tc.expected, citation.Text)
+		})
+	}
+
+	citation, err := FormatCitation(sicp, models.CitationAPA)
+	require.NoError(t, err)
+	assert.Equal(t, "Abelson, H., Sussman, G. J., &amp; Sussman, J. (1985). <i>Structure &amp; Interpretation</i>.", citation.HTML)
+
+	_, err = FormatCitation(hobbit, "harvard")
+	assert.Error(t, err)
+}
+
+func encodeBooks(t *testing.T, format string, books ...*models.Book) string {
+	t.Helper()
+	var buf bytes.Buffer
+	encoder, err := NewBookEncoder(format, &buf)
+	require.NoError(t, err)
+	for _, book := range books {
+		require.NoError(t, encoder.Encode(book))
+	}
+	require.NoError(t, encoder.Close())
+	return buf.String()
+}
+
+func TestCitationEncoders(t *testing.T) {
+	isbn := "9780306406157"
+	genre := "Fantasy"
+	description := "100% {unexpected} journey"
+	hobbit := &models.Book{ID: "1", Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937, ISBN: &isbn, Genre: &genre, Description: &description, Language: "english"}
+	sequel := &models.Book{ID: "2", Title: "The Hobbit Returns", Author: "J.R.R. Tolkien", Year: 1937, Language: "english"}
+	godel := &models.Book{ID: "3", Title: "On Formally Undecidable Propositions", Author: "Kurt Gödel", Year: 1931, Language: "german"}
+
+	t.Run("BibTeX", func(t *testing.T) {
+		output := encodeBooks(t, models.BookExportBibTeX, hobbit, sequel, godel)
+		assert.Contains(t, output, "@book{tolkien1937hobbit,\n"+
+			"  author = {Tolkien, J.R.R.},\n"+
+			"  title = {{The Hobbit}},\n"+
+			"  year = {1937},\n"+
+			"  isbn = {9780306406157},\n"+
+			"  abstract = {100\\% \\{unexpected\\} journey},\n"+
+			"  keywords = {Fantasy},\n"+
+			"  language = {english},\n"+
+			"}\n")
+		assert.Contains(t, output, "@book{tolkien1937hobbita,")
+		assert.Contains(t, output, "@book{godel1931on,")
+	})
+
+	t.Run("RIS", func(t *testing.T) {
+		output := encodeBooks(t, models.BookExportRIS, hobbit)
+		assert.Equal(t, "TY  - BOOK\r\n"+
+			"AU  - Tolkien, J.R.R.\r\n"+
+			"TI  - The Hobbit\r\n"+
+			"PY  - 1937\r\n"+
+			"SN  - 9780306406157\r\n"+
+			"AB  - 100% {unexpected} journey\r\n"+
+			"KW  - Fantasy\r\n"+
+			"LA  - english\r\n"+
+			"ID  - 1\r\n"+
+			"ER  - \r\n", output)
+	})
+
+	t.Run("CSL-JSON", func(t *testing.T) {
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(encodeBooks(t, models.BookExportCSLJSON, hobbit, godel)), &items))
+		require.Len(t, items, 2)
+		assert.Equal(t, "book", items[0]["type"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"family": "Tolkien", "given": "J.R.R."}}, items[0]["author"])
+		assert.Equal(t, map[string]interface{}{"date-parts": []interface{}{[]interface{}{1937.0}}}, items[0]["issued"])
+		assert.Equal(t, isbn, items[0]["ISBN"])
+		assert.NotContains(t, items[1], "ISBN")
+
+		assert.Equal(t, "[]\n", encodeBooks(t, models.BookExportCSLJSON))
+	})
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxCreditedNamesLength is the length of the books.author column.
+const maxCreditedNamesLength = 255
+
+var ErrInvalidContributors = errors.New("invalid contributors")
+
+// GetContributors returns a book together with its contributors in credit
+// order.
+func (s *BookService) GetContributors(id string) (*models.Book, []models.BookContributor, error) {
+	book, err := s.GetBookByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contributors, err := s.authors.Contributors(id)
+	if errors.Is(err, ErrBookNotFound) {
+		s.logger.WithField("book_id", id).Warn("Book not found")
+		return nil, nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to fetch contributors")
+		return nil, nil, err
+	}
+
+	return book, contributors, nil
+}
+
+// SetContributors replaces the contributors of a book with the listed
+// authors, credited in order. The author string of the book becomes the
+// names of its author-role contributors, or of every contributor when it has
+// no authors, joined with "; ". When expectedVersion is not 0 the book must
+// still be at that version, as for UpdateBook.
+func (s *BookService) SetContributors(id string, req *models.SetContributorsRequest, expectedVersion int) (*models.Book, []models.BookContributor, error) {
+	s.logger.WithField("book_id", id).Info("Setting book contributors")
+
+	book, err := s.GetBookByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if expectedVersion != 0 && book.Version != expectedVersion {
+		s.logger.WithFields(logrus.Fields{
+			"book_id":  id,
+			"expected": expectedVersion,
+			"version":  book.Version,
+		}).Warn("Book version mismatch")
+		return nil, nil, ErrVersionConflict
+	}
+
+	contributors := make([]models.BookContributor, len(req.Contributors))
+	credited := make(map[models.ContributorInput]bool)
+	var authorNames, allNames []string
+	for i, input := range req.Contributors {
+		if credited[input] {
+			return nil, nil, fmt.Errorf("%w: author %s is listed twice as %s", ErrInvalidContributors, input.AuthorID, input.Role)
+		}
+		credited[input] = true
+
+		author, err := s.authors.GetByID(input.AuthorID)
+		if errors.Is(err, ErrAuthorNotFound) {
+			s.logger.WithField("author_id", input.AuthorID).Warn("Contributor not found")
+			return nil, nil, fmt.Errorf("%w: %s", ErrAuthorNotFound, input.AuthorID)
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("author_id", input.AuthorID).Error("Failed to fetch contributor")
+			return nil, nil, err
+		}
+
+		contributors[i] = models.BookContributor{
+			AuthorID: author.ID,
+			Name:     author.Name,
+			Role:     input.Role,
+			Position: i,
+		}
+		if input.Role == models.ContributorAuthor {
+			authorNames = append(authorNames, author.Name)
+		}
+		allNames = append(allNames, author.Name)
+	}
+
+	if len(authorNames) == 0 {
+		authorNames = allNames
+	}
+	credits := strings.Join(authorNames, "; ")
+	if len([]rune(credits)) > maxCreditedNamesLength {
+		return nil, nil, fmt.Errorf("%w: the credited names are longer than %d characters", ErrInvalidContributors, maxCreditedNamesLength)
+	}
+
+	updatedAt := time.Now()
+	err = s.authors.ReplaceContributors(id, book.Version, contributors, credits, updatedAt)
+	if errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrAuthorNotFound) {
+		s.logger.WithError(err).WithField("book_id", id).Warn("Failed to set book contributors")
+		return nil, nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to set book contributors")
+		return nil, nil, err
+	}
+
+	book.Author = credits
+	book.UpdatedAt = updatedAt
+	book.Version++
+
+	s.logger.WithFields(logrus.Fields{
+		"book_id":      id,
+		"contributors": len(contributors),
+	}).Info("Successfully set book contributors")
+	return book, contributors, nil
+}
+
+// linkAuthors credits the names in the author string of a book that was just
+// written as its authors, creating authors for names not seen before. A
+// failure is only logged: the book itself was saved, and its contributors
+// can be set again through SetContributors.
+func (s *BookService) linkAuthors(book *models.Book) {
+	now := time.Now()
+	var authors []*models.Author
+	for _, name := range splitAuthorNames(book.Author) {
+		authors = append(authors, &models.Author{
+			ID:        uuid.New().String(),
+			Name:      name,
+			SortName:  authorSortName(name, nil),
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	if err := s.authors.LinkAuthors(book.ID, authors); err != nil {
+		s.logger.WithError(err).WithField("book_id", book.ID).Error("Failed to link book authors")
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/pkg/marc"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BookEncoder writes books to an export one at a time. Nothing is written
+// before the first call to Encode or Close, so a caller can still report an
+// error in place of the export until then.
+type BookEncoder interface {
+	Encode(book *models.Book) error
+	// Close completes the document; it does not close the underlying writer.
+	Close() error
+}
+
+// bookExportColumns are the CSV export columns. They use the names the CSV
+// import reads, so an export can be imported again.
+var bookExportColumns = []string{
+	"id", "title", "author", "year", "description", "isbn", "genre",
+	"language", "version", "created_at", "updated_at",
+}
+
+// NewBookEncoder returns an encoder for the given export format.
+func NewBookEncoder(format string, w io.Writer) (BookEncoder, error) {
+	switch format {
+	case models.BookExportCSV:
+		return &csvBookEncoder{writer: csv.NewWriter(w)}, nil
+	case models.BookExportJSON:
+		return &jsonBookEncoder{w: w}, nil
+	case models.BookExportNDJSON:
+		return &ndjsonBookEncoder{encoder: json.NewEncoder(w)}, nil
+	case models.BookExportMARC:
+		return &marcBookEncoder{writer: marc.NewWriter(w)}, nil
+	case models.BookExportMARCXML:
+		return &marcXMLBookEncoder{writer: marc.NewXMLWriter(w)}, nil
+	case models.BookExportBibTeX:
+		return &bibtexEncoder{w: w, keys: make(map[string]int)}, nil
+	case models.BookExportRIS:
+		return &risEncoder{w: w}, nil
+	case models.BookExportCSLJSON:
+		return &cslJSONEncoder{items: &jsonBookEncoder{w: w}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+type csvBookEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvBookEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.writer.Write(bookExportColumns)
+}
+
+func (e *csvBookEncoder) Encode(book *models.Book) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.writer.Write([]string{
+		book.ID,
+		book.Title,
+		book.Author,
+		strconv.Itoa(book.Year),
+		exportCell(book.Description),
+		exportCell(book.ISBN),
+		exportCell(book.Genre),
+		book.Language,
+		strconv.Itoa(book.Version),
+		book.CreatedAt.UTC().Format(time.RFC3339),
+		book.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func exportCell(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func (e *csvBookEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// jsonBookEncoder writes a single JSON array, one book per line.
+type jsonBookEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonBookEncoder) Encode(book *models.Book) error {
+	data, err := json.Marshal(book)
+	if err != nil {
+		return err
+	}
+	return e.writeElement(data)
+}
+
+// writeElement writes an encoded array element.
+func (e *jsonBookEncoder) writeElement(data []byte) error {
+	prefix := ",\n"
+	if e.count == 0 {
+		prefix = "[\n"
+	}
+	e.count++
+
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	_, err := e.w.Write(data)
+	return err
+}
+
+func (e *jsonBookEncoder) Close() error {
+	suffix := "\n]\n"
+	if e.count == 0 {
+		suffix = "[]\n"
+	}
+	_, err := io.WriteString(e.w, suffix)
+	return err
+}
+
+type ndjsonBookEncoder struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonBookEncoder) Encode(book *models.Book) error {
+	return e.encoder.Encode(book)
+}
+
+func (e *ndjsonBookEncoder) Close() error {
+	return nil
+}
+
+type marcBookEncoder struct {
+	writer *marc.Writer
+}
+
+func (e *marcBookEncoder) Encode(book *models.Book) error {
+	return e.writer.Write(BookToMARC(book))
+}
+
+func (e *marcBookEncoder) Close() error {
+	return nil
+}
+
+type marcXMLBookEncoder struct {
+	writer *marc.XMLWriter
+}
+
+func (e *marcXMLBookEncoder) Encode(book *models.Book) error {
+	return e.writer.Write(BookToMARC(book))
+}
+
+func (e *marcXMLBookEncoder) Close() error {
+	return e.writer.Close()
+}
+
+// ExportBooks streams every book matching the filters in params to encoder,
+// ordered like ListBooks. The export stops when ctx is cancelled.
+func (s *BookService) ExportBooks(ctx context.Context, params *models.BookExportParams, encoder BookEncoder) error {
+	query := params.ListParams()
+	normalizeBookOrder(&query)
+
+	s.logger.WithFields(logrus.Fields{
+		"format": params.Format,
+		"sort":   query.Sort,
+		"order":  query.Order,
+	}).Info("Exporting books")
+
+	genreIDs, err := s.genreSubtree(query.Genre)
+	if err != nil {
+		return err
+	}
+	query.GenreIDs = genreIDs
+
+	count := 0
+	err = s.repo.Export(ctx, &query, func(book *models.Book) error {
+		count++
+		return encoder.Encode(book)
+	})
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("exported", count).Error("Failed to export books")
+		return err
+	}
+
+	s.logger.WithField("count", count).Info("Successfully exported books")
+	return nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidBookGenres = errors.New("invalid book genres")
+
+// GetBookGenres returns a book together with its genres, primary genre
+// first.
+func (s *BookService) GetBookGenres(id string) (*models.Book, []models.Genre, error) {
+	book, err := s.GetBookByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	genres, err := s.genres.BookGenres(id)
+	if errors.Is(err, ErrBookNotFound) {
+		s.logger.WithField("book_id", id).Warn("Book not found")
+		return nil, nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to fetch book genres")
+		return nil, nil, err
+	}
+
+	return book, genres, nil
+}
+
+// SetBookGenres replaces the genres of a book with the listed ones. The
+// first becomes the book's primary genre and its genre field; an empty list
+// clears it. When expectedVersion is not 0 the book must still be at that
+// version, as for UpdateBook.
+func (s *BookService) SetBookGenres(id string, req *models.SetBookGenresRequest, expectedVersion int) (*models.Book, []models.Genre, error) {
+	s.logger.WithField("book_id", id).Info("Setting book genres")
+
+	book, err := s.GetBookByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if expectedVersion != 0 && book.Version != expectedVersion {
+		s.logger.WithFields(logrus.Fields{
+			"book_id":  id,
+			"expected": expectedVersion,
+			"version":  book.Version,
+		}).Warn("Book version mismatch")
+		return nil, nil, ErrVersionConflict
+	}
+
+	genres := make([]models.Genre, len(req.GenreIDs))
+	listed := make(map[string]bool)
+	for i, genreID := range req.GenreIDs {
+		if listed[genreID] {
+			return nil, nil, fmt.Errorf("%w: genre %s is listed twice", ErrInvalidBookGenres, genreID)
+		}
+		listed[genreID] = true
+
+		genre, err := s.genres.GetByID(genreID)
+		if errors.Is(err, ErrGenreNotFound) {
+			s.logger.WithField("genre_id", genreID).Warn("Book genre not found")
+			return nil, nil, fmt.Errorf("%w: %s", ErrUnknownGenre, genreID)
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("genre_id", genreID).Error("Failed to fetch book genre")
+			return nil, nil, err
+		}
+		genres[i] = *genre
+	}
+
+	var primary *string
+	if len(genres) > 0 {
+		primary = &genres[0].Name
+	}
+
+	updatedAt := time.Now()
+	err = s.genres.ReplaceBookGenres(id, book.Version, req.GenreIDs, primary, updatedAt)
+	if errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrUnknownGenre) {
+		s.logger.WithError(err).WithField("book_id", id).Warn("Failed to set book genres")
+		return nil, nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", id).Error("Failed to set book genres")
+		return nil, nil, err
+	}
+
+	book.Genre = primary
+	book.UpdatedAt = updatedAt
+	book.Version++
+
+	s.logger.WithFields(logrus.Fields{
+		"book_id": id,
+		"genres":  len(genres),
+	}).Info("Successfully set book genres")
+	return book, genres, nil
+}
+
+// linkGenre makes the genre field of a book that was just written its only
+// genre, creating a top-level genre for a name not seen before. A failure is
+// only logged: the book itself was saved, and its genres can be set again
+// through SetBookGenres.
+func (s *BookService) linkGenre(book *models.Book) {
+	var genre *models.Genre
+	if book.Genre != nil && strings.TrimSpace(*book.Genre) != "" {
+		now := time.Now()
+		genre = &models.Genre{
+			ID:        uuid.New().String(),
+			Name:      strings.TrimSpace(*book.Genre),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+
+	if err := s.genres.LinkGenre(book.ID, genre); err != nil {
+		s.logger.WithError(err).WithField("book_id", book.ID).Error("Failed to link book genre")
+	}
+}
+
+// genreSubtree resolves a genre filter to the IDs of the genre with that
+// name or alias and of its subgenres. An empty filter resolves to nil.
+func (s *BookService) genreSubtree(genre string) ([]string, error) {
+	if genre == "" {
+		return nil, nil
+	}
+
+	ids, err := s.genres.Subtree(genre)
+	if err != nil {
+		s.logger.WithError(err).WithField("genre", genre).Error("Failed to resolve genre filter")
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MaxBookImportRows caps the number of data rows in a single import.
+const MaxBookImportRows = 5000
+
+var (
+	ErrInvalidCSV           = errors.New("invalid csv")
+	ErrInvalidColumnMapping = errors.New("invalid column mapping")
+	ErrTooManyImportRows    = fmt.Errorf("an import may contain at most %d rows", MaxBookImportRows)
+)
+
+// requiredImportFields must be mapped to a column for an import to start.
+var requiredImportFields = []string{"title", "author", "year"}
+
+// ParseBookCSV decodes a CSV file with a header row into import records.
+// mapping maps book fields to CSV headers; fields it leaves out are read from
+// the column with the same name, ignoring case. Problems with a single row,
+// such as a malformed year, are recorded on its record; problems with the
+// file as a whole are returned as errors.
+func ParseBookCSV(r io.Reader, mapping map[string]string) ([]models.BookImportRecord, error) {
+	for field := range mapping {
+		if !slices.Contains(models.BookImportFields, field) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidColumnMapping, field)
+		}
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidCSV)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns, err := importColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []models.BookImportRecord
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if len(records) == MaxBookImportRows {
+			return nil, ErrTooManyImportRows
+		}
+
+		line, _ := reader.FieldPos(0)
+		records = append(records, decodeImportRow(line, row, len(header), columns))
+	}
+
+	return records, nil
+}
+
+// importColumns resolves every importable field to its column index.
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for _, field := range models.BookImportFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+
+		index := slices.IndexFunc(header, func(h string) bool {
+			return strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name))
+		})
+		if index >= 0 {
+			columns[field] = index
+			continue
+		}
+		if mapped {
+			return nil, fmt.Errorf("%w: column %q for %s is not in the header", ErrInvalidColumnMapping, name, field)
+		}
+		if slices.Contains(requiredImportFields, field) {
+			return nil, fmt.Errorf("%w: no column for required field %s", ErrInvalidColumnMapping, field)
+		}
+	}
+	return columns, nil
+}
+
+func decodeImportRow(line int, row []string, width int, columns map[string]int) models.BookImportRecord {
+	record := models.BookImportRecord{Line: line}
+	if len(row) != width {
+		record.Errors = append(record.Errors, models.ValidationError{
+			Field:   "row",
+			Message: fmt.Sprintf("Has %d columns, expected %d", len(row), width),
+		})
+		return record
+	}
+
+	cell := func(field string) string {
+		index, ok := columns[field]
+		if !ok {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+	optional := func(field string) *string {
+		if value := cell(field); value != "" {
+			return &value
+		}
+		return nil
+	}
+
+	record.Book = models.CreateBookRequest{
+		Title:       cell("title"),
+		Author:      cell("author"),
+		Description: optional("description"),
+		ISBN:        optional("isbn"),
+		Genre:       optional("genre"),
+		Language:    optional("language"),
+	}
+	if year := cell("year"); year != "" {
+		parsed, err := strconv.Atoi(year)
+		if err != nil {
+			record.Errors = append(record.Errors, models.ValidationError{
+				Field:   "Year",
+				Message: "Must be a whole number",
+			})
+		}
+		record.Book.Year = parsed
+	}
+
+	return record
+}
+
+// ImportBooks creates the books described by records. Records that already
+// carry errors are rejected, and rows whose ISBN belongs to an existing book
+// or to an earlier row are skipped as duplicates. Nothing is written in a dry
+// run, or in transactional mode when any row is rejected.
+func (s *BookService) ImportBooks(records []models.BookImportRecord, params *models.BookImportParams) (*models.BookImportReport, error) {
+	mode := params.Mode
+	if mode == "" {
+		mode = models.BookImportTransactional
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"rows":    len(records),
+		"mode":    mode,
+		"dry_run": params.DryRun,
+	}).Info("Importing books")
+
+	report := &models.BookImportReport{
+		Mode:   mode,
+		DryRun: params.DryRun,
+		Total:  len(records),
+		Rows:   make([]models.BookImportRowResult, len(records)),
+	}
+
+	books := make([]*models.Book, len(records))
+	var isbns []string
+	now := time.Now()
+	for i := range records {
+		record := &records[i]
+		row := &report.Rows[i]
+		row.Line = record.Line
+		row.Title = record.Book.Title
+
+		if len(record.Errors) == 0 {
+			book, err := s.newImportedBook(&record.Book, now)
+			if err != nil {
+				record.Errors = append(record.Errors, models.ValidationError{
+					Field:   "ISBN",
+					Message: "Must be a valid ISBN-10 or ISBN-13",
+				})
+			} else {
+				books[i] = book
+				if book.ISBN != nil {
+					row.ISBN = *book.ISBN
+					isbns = append(isbns, *book.ISBN)
+				}
+			}
+		}
+		if len(record.Errors) > 0 {
+			row.Status = models.BookImportRejected
+			row.Errors = record.Errors
+			report.Rejected++
+		}
+	}
+
+	existing, err := s.repo.ExistingISBNs(isbns)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to look up existing ISBNs")
+		return nil, err
+	}
+
+	var pending []*models.Book
+	seen := make(map[string]bool)
+	for i, book := range books {
+		if book == nil {
+			continue
+		}
+		if book.ISBN != nil {
+			if existing[*book.ISBN] || seen[*book.ISBN] {
+				report.Rows[i].Status = models.BookImportDuplicate
+				report.Duplicates++
+				books[i] = nil
+				continue
+			}
+			seen[*book.ISBN] = true
+		}
+		report.Rows[i].Status = models.BookImportCreated
+		report.Rows[i].BookID = book.ID
+		report.Created++
+		pending = append(pending, book)
+	}
+
+	switch {
+	case params.DryRun:
+		// Report what would happen without writing anything.
+	case mode == models.BookImportTransactional && report.Rejected > 0:
+		s.logger.WithField("rejected", report.Rejected).Warn("Import rolled back because of rejected rows")
+	case mode == models.BookImportTransactional && len(pending) > 0:
+		if err := s.repo.CreateBatch(pending); err != nil {
+			s.logger.WithError(err).Error("Failed to import books")
+			return nil, err
+		}
+		for _, book := range pending {
+			s.linkAuthors(book)
+			s.linkGenre(book)
+		}
+		report.Committed = true
+	case mode == models.BookImportTransactional:
+		// Nothing to create.
+	default:
+		for i, book := range books {
+			if book == nil {
+				continue
+			}
+			err := s.repo.Create(book)
+			if errors.Is(err, ErrDuplicateISBN) {
+				// Another request took the ISBN since it was checked.
+				report.Rows[i].Status = models.BookImportDuplicate
+				report.Rows[i].BookID = ""
+				report.Created--
+				report.Duplicates++
+				continue
+			}
+			if err != nil {
+				s.logger.WithError(err).WithField("line", report.Rows[i].Line).Error("Failed to import book")
+				return nil, err
+			}
+			s.linkAuthors(book)
+			s.linkGenre(book)
+		}
+		report.Committed = report.Created > 0
+	}
+
+	if !report.Committed {
+		for i := range report.Rows {
+			report.Rows[i].BookID = ""
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"created":    report.Created,
+		"duplicates": report.Duplicates,
+		"rejected":   report.Rejected,
+		"committed":  report.Committed,
+	}).Info("Finished importing books")
+	return report, nil
+}
+
+func (s *BookService) newImportedBook(req *models.CreateBookRequest, now time.Time) (*models.Book, error) {
+	normalizedISBN, err := normalizeISBN(req.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
+	language := models.DefaultSearchLanguage
+	if req.Language != nil {
+		language = *req.Language
+	}
+
+	return &models.Book{
+		ID:          uuid.New().String(),
+		Title:       req.Title,
+		Author:      req.Author,
+		Year:        req.Year,
+		Description: req.Description,
+		ISBN:        normalizedISBN,
+		Genre:       req.Genre,
+		Language:    language,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
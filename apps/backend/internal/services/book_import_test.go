@@ -0,0 +1,163 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"library-management-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBookCSV(t *testing.T) {
+	t.Run("columns by name", func(t *testing.T) {
+		input := "\ufeffTitle,Author,Year,ISBN,Notes\n" +
+			"The Hobbit,J.R.R. Tolkien,1937,0-306-40615-2,first\n" +
+			"\"Dune,\nthe novel\",Frank Herbert,1965,,\n"
+
+		records, err := ParseBookCSV(strings.NewReader(input), nil)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+
+		assert.Equal(t, 2, records[0].Line)
+		assert.Equal(t, "The Hobbit", records[0].Book.Title)
+		assert.Equal(t, 1937, records[0].Book.Year)
+		assert.Equal(t, "0-306-40615-2", *records[0].Book.ISBN)
+		assert.Nil(t, records[0].Book.Description)
+		assert.Empty(t, records[0].Errors)
+
+		assert.Equal(t, 3, records[1].Line)
+		assert.Equal(t, "Dune,\nthe novel", records[1].Book.Title)
+		assert.Nil(t, records[1].Book.ISBN)
+	})
+
+	t.Run("mapping", func(t *testing.T) {
+		input := "Book Title,Writer,Published,Genre\nEmma,Jane Austen,1815,Romance\n"
+		mapping := map[string]string{"title": "book title", "author": "Writer", "year": "Published"}
+
+		records, err := ParseBookCSV(strings.NewReader(input), mapping)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "Emma", records[0].Book.Title)
+		assert.Equal(t, "Jane Austen", records[0].Book.Author)
+		assert.Equal(t, "Romance", *records[0].Book.Genre)
+	})
+
+	t.Run("row errors", func(t *testing.T) {
+		input := "title,author,year\nEmma,Jane Austen,eighteen\nEmma,Jane Austen\n"
+
+		records, err := ParseBookCSV(strings.NewReader(input), nil)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "Year", records[0].Errors[0].Field)
+		assert.Equal(t, "row", records[1].Errors[0].Field)
+	})
+
+	t.Run("file errors", func(t *testing.T) {
+		_, err := ParseBookCSV(strings.NewReader(""), nil)
+		assert.ErrorIs(t, err, ErrInvalidCSV)
+
+		_, err = ParseBookCSV(strings.NewReader("title,author\nEmma,Jane Austen\n"), nil)
+		assert.ErrorIs(t, err, ErrInvalidColumnMapping)
+
+		_, err = ParseBookCSV(strings.NewReader("title,author,year\n"), map[string]string{"publisher": "Publisher"})
+		assert.ErrorIs(t, err, ErrInvalidColumnMapping)
+
+		_, err = ParseBookCSV(strings.NewReader("title,author,year\n"), map[string]string{"year": "Published"})
+		assert.ErrorIs(t, err, ErrInvalidColumnMapping)
+
+		_, err = ParseBookCSV(strings.NewReader("title,author,year\n\"Emma,Jane Austen,1815\n"), nil)
+		assert.ErrorIs(t, err, ErrInvalidCSV)
+	})
+
+	t.Run("row limit", func(t *testing.T) {
+		input := "title,author,year\n" + strings.Repeat("Emma,Jane Austen,1815\n", MaxBookImportRows+1)
+
+		_, err := ParseBookCSV(strings.NewReader(input), nil)
+		assert.ErrorIs(t, err, ErrTooManyImportRows)
+	})
+}
+
+func TestBookService_ImportBooks(t *testing.T) {
+	existingISBN := "9780306406157"
+	newISBN := "9780262033848"
+	records := func() []models.BookImportRecord {
+		return []models.BookImportRecord{
+			{Line: 2, Book: models.CreateBookRequest{Title: "Dune", Author: "Frank Herbert", Year: 1965}},
+			{Line: 3, Book: models.CreateBookRequest{Title: "Copy", Author: "Someone", Year: 1990, ISBN: &existingISBN}},
+			{Line: 4, Book: models.CreateBookRequest{Title: "Algorithms", Author: "Cormen", Year: 2009, ISBN: &newISBN}},
+			{Line: 5, Book: models.CreateBookRequest{Title: "Algorithms again", Author: "Cormen", Year: 2009, ISBN: &newISBN}},
+			{Line: 6, Errors: []models.ValidationError{{Field: "Title", Message: "This field is required"}}},
+		}
+	}
+	setup := func(t *testing.T) *BookService {
+		service := newTestBookService()
+		_, err := service.CreateBook(&models.CreateBookRequest{Title: "Existing", Author: "Author", Year: 1990, ISBN: &existingISBN})
+		require.NoError(t, err)
+		return service
+	}
+	countBooks := func(t *testing.T, service *BookService) int {
+		result, err := service.ListBooks(&models.BookListParams{})
+		require.NoError(t, err)
+		return result.Total
+	}
+
+	t.Run("transactional with rejected rows writes nothing", func(t *testing.T) {
+		service := setup(t)
+
+		report, err := service.ImportBooks(records(), &models.BookImportParams{})
+		require.NoError(t, err)
+		assert.Equal(t, models.BookImportTransactional, report.Mode)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 5, report.Total)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 2, report.Duplicates)
+		assert.Equal(t, 1, report.Rejected)
+		assert.Empty(t, report.Rows[0].BookID)
+		assert.Equal(t, 1, countBooks(t, service))
+	})
+
+	t.Run("transactional", func(t *testing.T) {
+		service := setup(t)
+
+		report, err := service.ImportBooks(records()[:4], &models.BookImportParams{Mode: models.BookImportTransactional})
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		assert.Equal(t, 2, report.Created)
+		assert.NotEmpty(t, report.Rows[0].BookID)
+		assert.Equal(t, 3, countBooks(t, service))
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		service := setup(t)
+
+		report, err := service.ImportBooks(records(), &models.BookImportParams{Mode: models.BookImportBestEffort})
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		assert.Equal(t, models.BookImportCreated, report.Rows[0].Status)
+		assert.Equal(t, models.BookImportDuplicate, report.Rows[1].Status)
+		assert.Equal(t, models.BookImportCreated, report.Rows[2].Status)
+		assert.Equal(t, newISBN, report.Rows[2].ISBN)
+		assert.Equal(t, models.BookImportDuplicate, report.Rows[3].Status)
+		assert.Equal(t, models.BookImportRejected, report.Rows[4].Status)
+		assert.Equal(t, "Title", report.Rows[4].Errors[0].Field)
+		assert.Equal(t, 3, countBooks(t, service))
+
+		book, err := service.GetBookByID(report.Rows[0].BookID)
+		require.NoError(t, err)
+		assert.Equal(t, "english", book.Language)
+		assert.Equal(t, 1, book.Version)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		service := setup(t)
+
+		report, err := service.ImportBooks(records(), &models.BookImportParams{Mode: models.BookImportBestEffort, DryRun: true})
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, countBooks(t, service))
+	})
+}
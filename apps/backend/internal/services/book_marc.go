@@ -0,0 +1,424 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/pkg/marc"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrInvalidMARC = errors.New("invalid marc file")
+
+// marcLanguageCodes maps the search languages to MARC language codes. Books
+// in the "simple" configuration are exported as undetermined.
+var marcLanguageCodes = map[string]string{
+	"simple":     "und",
+	"danish":     "dan",
+	"dutch":      "dut",
+	"english":    "eng",
+	"finnish":    "fin",
+	"french":     "fre",
+	"german":     "ger",
+	"hungarian":  "hun",
+	"italian":    "ita",
+	"norwegian":  "nor",
+	"portuguese": "por",
+	"romanian":   "rum",
+	"russian":    "rus",
+	"spanish":    "spa",
+	"swedish":    "swe",
+	"turkish":    "tur",
+}
+
+var marcYear = regexp.MustCompile(`\d{4}`)
+
+// BookToMARC describes a book as a MARC 21 bibliographic record:
+//
+//	001     book ID
+//	005     last update
+//	008     date entered, publication year and language
+//	020 $a  ISBN
+//	100 $a  author
+//	245 $a  title
+//	264 $c  publication year
+//	520 $a  description
+//	650 $a  genre
+func BookToMARC(book *models.Book) *marc.Record {
+	language, ok := marcLanguageCodes[book.Language]
+	if !ok {
+		language = "und"
+	}
+
+	record := marc.NewRecord()
+	record.AddControlField("001", book.ID)
+	record.AddControlField("005", book.UpdatedAt.UTC().Format("20060102150405.0"))
+	record.AddControlField("008", fmt.Sprintf("%ss%04d%s%s d",
+		book.CreatedAt.UTC().Format("060102"), book.Year, strings.Repeat(" ", 24), language))
+	if book.ISBN != nil {
+		record.AddDataField("020", ' ', ' ', "a", *book.ISBN)
+	}
+	// Authors are stored as written, so they are exported as forenames
+	// rather than guessed into inverted "Surname, Forename" form.
+	record.AddDataField("100", '0', ' ', "a", book.Author)
+	record.AddDataField("245", '1', '0', "a", book.Title)
+	record.AddDataField("264", ' ', '1', "c", strconv.Itoa(book.Year))
+	if book.Description != nil {
+		record.AddDataField("520", ' ', ' ', "a", *book.Description)
+	}
+	if book.Genre != nil {
+		record.AddDataField("650", ' ', '4', "a", *book.Genre)
+	}
+	return record
+}
+
+// BookFromMARC reads the fields BookToMARC writes from a record. Records
+// from other systems are read leniently: the title includes 245 $b, the
+// author falls back to 110 or 111 and inverted names are turned around, the
+// year is taken from 264, 260 or 008, and the ISBD punctuation catalogers end
+// subfields with is dropped.
+// Missing fields are left empty for validation to report.
+func BookFromMARC(record *marc.Record) models.CreateBookRequest {
+	var book models.CreateBookRequest
+
+	if fields := record.FieldsByTag("245"); len(fields) > 0 {
+		title := trimISBD(fields[0].Subfield('a'))
+		if subtitle := trimISBD(fields[0].Subfield('b')); subtitle != "" {
+			title += ": " + subtitle
+		}
+		book.Title = title
+	}
+
+	for _, tag := range []string{"100", "110", "111"} {
+		if fields := record.FieldsByTag(tag); len(fields) > 0 {
+			book.Author = trimISBD(fields[0].Subfield('a'))
+			if tag == "100" && fields[0].Indicator1 == '1' {
+				book.Author = uninvertName(book.Author)
+			}
+			break
+		}
+	}
+
+	book.Year = marcPublicationYear(record)
+
+	if fields := record.FieldsByTag("020"); len(fields) > 0 {
+		if isbn := strings.Fields(fields[0].Subfield('a')); len(isbn) > 0 {
+			book.ISBN = &isbn[0]
+		}
+	}
+	if fields := record.FieldsByTag("520"); len(fields) > 0 {
+		description := strings.TrimSpace(strings.Join([]string{fields[0].Subfield('a'), fields[0].Subfield('b')}, " "))
+		if description != "" {
+			book.Description = &description
+		}
+	}
+	if fields := record.FieldsByTag("650"); len(fields) > 0 {
+		if genre := trimISBD(fields[0].Subfield('a')); genre != "" {
+			book.Genre = &genre
+		}
+	}
+
+	if fixed := record.ControlField("008"); len(fixed) >= 38 {
+		code := fixed[35:38]
+		for language, c := range marcLanguageCodes {
+			if c == code && language != "simple" {
+				book.Language = &language
+				break
+			}
+		}
+	}
+
+	return book
+}
+
+// marcPublicationYear prefers the publication statement of 264 over the
+// older 260, and falls back to the first date in 008.
+func marcPublicationYear(record *marc.Record) int {
+	var candidates []string
+	for _, field := range record.FieldsByTag("264") {
+		if field.Indicator2 == '1' {
+			candidates = append(candidates, field.Subfield('c'))
+		}
+	}
+	for _, field := range record.FieldsByTag("260") {
+		candidates = append(candidates, field.Subfield('c'))
+	}
+	if fixed := record.ControlField("008"); len(fixed) >= 11 {
+		candidates = append(candidates, fixed[7:11])
+	}
+
+	for _, candidate := range candidates {
+		if match := marcYear.FindString(candidate); match != "" {
+			year, _ := strconv.Atoi(match)
+			return year
+		}
+	}
+	return 0
+}
+
+// trimISBD removes the punctuation that separates MARC subfields, such as
+// the " /" ending a title. A final period is kept after an initial, as in
+// "Tolkien, J. R. R.".
+func trimISBD(value string) string {
+	value = strings.TrimRight(strings.TrimSpace(value), " /:;,=")
+	if strings.HasSuffix(value, ".") {
+		words := strings.Fields(value)
+		last := words[len(words)-1]
+		if len(last) != 2 {
+			value = strings.TrimSuffix(value, ".")
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+// uninvertName turns "Herbert, Frank" into "Frank Herbert".
+func uninvertName(name string) string {
+	surname, forenames, found := strings.Cut(name, ", ")
+	if !found {
+		return name
+	}
+	return strings.TrimSpace(forenames) + " " + strings.TrimSpace(surname)
+}
+
+type marcReader interface {
+	Read() (*marc.Record, error)
+}
+
+// ParseBookMARC decodes a binary MARC or MARCXML file into import records.
+// An empty format detects MARCXML by a leading "<".
+func ParseBookMARC(r io.Reader, format string) ([]models.BookImportRecord, error) {
+	buffered := bufio.NewReader(r)
+	if format == "" {
+		format = models.BookMARCBinary
+		if peek, _ := buffered.Peek(512); strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(string(peek), "\ufeff")), "<") {
+			format = models.BookMARCXML
+		}
+	}
+
+	var reader marcReader = marc.NewReader(buffered)
+	if format == models.BookMARCXML {
+		reader = marc.NewXMLReader(buffered)
+	}
+
+	var records []models.BookImportRecord
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidMARC, len(records)+1, err)
+		}
+		if len(records) == MaxBookImportRows {
+			return nil, ErrTooManyImportRows
+		}
+
+		records = append(records, models.BookImportRecord{
+			Line: len(records) + 1,
+			ID:   strings.TrimSpace(record.ControlField("001")),
+			Book: BookFromMARC(record),
+		})
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no records", ErrInvalidMARC)
+	}
+	return records, nil
+}
+
+// ImportMARCBooks creates or updates a book for every record. A record
+// updates the book whose ID is in its 001 field, or else the book with its
+// ISBN, and creates a new book when neither exists. Records are applied one
+// by one, so a rejected record does not stop the others; in a dry run
+// nothing is written.
+func (s *BookService) ImportMARCBooks(records []models.BookImportRecord, dryRun bool) (*models.BookImportReport, error) {
+	s.logger.WithFields(logrus.Fields{
+		"records": len(records),
+		"dry_run": dryRun,
+	}).Info("Importing MARC records")
+
+	report := &models.BookImportReport{
+		Mode:   models.BookImportBestEffort,
+		DryRun: dryRun,
+		Total:  len(records),
+		Rows:   make([]models.BookImportRowResult, len(records)),
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	for i := range records {
+		record := &records[i]
+		row := &report.Rows[i]
+		row.Line = record.Line
+		row.Title = record.Book.Title
+
+		if len(record.Errors) > 0 {
+			rejectImportRow(report, row, record.Errors...)
+			continue
+		}
+
+		book, err := s.newImportedBook(&record.Book, now)
+		if err != nil {
+			rejectImportRow(report, row, models.ValidationError{Field: "ISBN", Message: "Must be a valid ISBN-10 or ISBN-13"})
+			continue
+		}
+		if book.ISBN != nil {
+			row.ISBN = *book.ISBN
+		}
+
+		existing, conflict, err := s.findImportTarget(record.ID, book.ISBN)
+		if err != nil {
+			s.logger.WithError(err).WithField("record", record.Line).Error("Failed to look up imported book")
+			return nil, err
+		}
+		if conflict {
+			rejectImportRow(report, row, models.ValidationError{Field: "ISBN", Message: "Belongs to another book"})
+			continue
+		}
+
+		key := book.ID
+		if existing != nil {
+			key = existing.ID
+		}
+		if seen[key] || (book.ISBN != nil && seen[*book.ISBN]) {
+			row.Status = models.BookImportDuplicate
+			report.Duplicates++
+			continue
+		}
+		seen[key] = true
+		if book.ISBN != nil {
+			seen[*book.ISBN] = true
+		}
+
+		if existing == nil {
+			err = s.createImportedBook(book, dryRun)
+		} else {
+			book, err = s.updateImportedBook(existing, book, dryRun)
+		}
+		switch {
+		case errors.Is(err, ErrDuplicateISBN) && existing == nil:
+			// Another request took the ISBN since it was checked.
+			row.Status = models.BookImportDuplicate
+			report.Duplicates++
+			continue
+		case errors.Is(err, ErrDuplicateISBN):
+			rejectImportRow(report, row, models.ValidationError{Field: "ISBN", Message: "Belongs to another book"})
+			continue
+		case errors.Is(err, ErrVersionConflict):
+			rejectImportRow(report, row, models.ValidationError{Field: "record", Message: "The book was changed by another request"})
+			continue
+		case err != nil:
+			s.logger.WithError(err).WithField("record", record.Line).Error("Failed to import MARC record")
+			return nil, err
+		}
+
+		row.BookID = book.ID
+		if existing == nil {
+			row.Status = models.BookImportCreated
+			report.Created++
+		} else {
+			row.Status = models.BookImportUpdated
+			report.Updated++
+		}
+	}
+
+	report.Committed = !dryRun && report.Created+report.Updated > 0
+	if dryRun {
+		for i := range report.Rows {
+			if report.Rows[i].Status == models.BookImportCreated {
+				report.Rows[i].BookID = ""
+			}
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"created":    report.Created,
+		"updated":    report.Updated,
+		"duplicates": report.Duplicates,
+		"rejected":   report.Rejected,
+	}).Info("Finished importing MARC records")
+	return report, nil
+}
+
+func rejectImportRow(report *models.BookImportReport, row *models.BookImportRowResult, errs ...models.ValidationError) {
+	row.Status = models.BookImportRejected
+	row.Errors = errs
+	report.Rejected++
+}
+
+// findImportTarget returns the book a record should update, or nil when it
+// describes a new book. Control numbers that are not book IDs are ignored.
+// conflict is true when the record names a book by ID but its ISBN belongs
+// to a different one.
+func (s *BookService) findImportTarget(id string, isbn *string) (target *models.Book, conflict bool, err error) {
+	if _, parseErr := uuid.Parse(id); parseErr == nil {
+		target, err = s.repo.GetByID(id)
+		if errors.Is(err, ErrBookNotFound) {
+			target, err = nil, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if isbn == nil {
+		return target, false, nil
+	}
+
+	owner, err := s.repo.GetByISBN(*isbn)
+	if errors.Is(err, ErrBookNotFound) {
+		return target, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if target == nil {
+		return owner, false, nil
+	}
+	return target, owner.ID != target.ID, nil
+}
+
+func (s *BookService) createImportedBook(book *models.Book, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	if err := s.repo.Create(book); err != nil {
+		return err
+	}
+	s.linkAuthors(book)
+	s.linkGenre(book)
+	return nil
+}
+
+func (s *BookService) updateImportedBook(existing, imported *models.Book, dryRun bool) (*models.Book, error) {
+	updated := *existing
+	updated.Title = imported.Title
+	updated.Author = imported.Author
+	updated.Year = imported.Year
+	updated.Description = imported.Description
+	updated.ISBN = imported.ISBN
+	updated.Genre = imported.Genre
+	updated.Language = imported.Language
+	updated.UpdatedAt = imported.UpdatedAt
+	if dryRun {
+		return &updated, nil
+	}
+	if err := s.repo.Update(&updated); err != nil {
+		return nil, err
+	}
+	if updated.Author != existing.Author {
+		s.linkAuthors(&updated)
+	}
+	if !equalStringPtr(updated.Genre, existing.Genre) {
+		s.linkGenre(&updated)
+	}
+	return &updated, nil
+}
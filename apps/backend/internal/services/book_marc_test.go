@@ -0,0 +1,177 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/pkg/marc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookMARCMapping(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		isbn := "9780306406157"
+		description := "A desert planet."
+		genre := "Science Fiction"
+		book := &models.Book{
+			ID:          "3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e",
+			Title:       "Dune",
+			Author:      "Frank Herbert",
+			Year:        1965,
+			Description: &description,
+			ISBN:        &isbn,
+			Genre:       &genre,
+			Language:    "french",
+			CreatedAt:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2024, 3, 2, 10, 30, 0, 0, time.UTC),
+		}
+
+		record := BookToMARC(book)
+		assert.Equal(t, book.ID, record.ControlField("001"))
+		assert.Equal(t, "20240302103000.0", record.ControlField("005"))
+		assert.Len(t, record.ControlField("008"), 40)
+
+		decoded := BookFromMARC(record)
+		assert.Equal(t, "Dune", decoded.Title)
+		assert.Equal(t, "Frank Herbert", decoded.Author)
+		assert.Equal(t, 1965, decoded.Year)
+		assert.Equal(t, isbn, *decoded.ISBN)
+		assert.Equal(t, description, *decoded.Description)
+		assert.Equal(t, genre, *decoded.Genre)
+		assert.Equal(t, "french", *decoded.Language)
+	})
+
+	t.Run("catalog record", func(t *testing.T) {
+		record := marc.NewRecord()
+		record.AddControlField("008", "850101s1937    enk           000 1 eng d")
+		record.AddDataField("020", ' ', ' ', "a", "0-306-40615-2 (pbk.)")
+		record.AddDataField("100", '1', ' ', "a", "Tolkien, J. R. R.,", "e", "author.")
+		record.AddDataField("245", '1', '4', "a", "The hobbit :", "b", "or, There and back again /", "c", "J.R.R. Tolkien.")
+		record.AddDataField("260", ' ', ' ', "a", "London :", "b", "Allen & Unwin,", "c", "c1951.")
+		record.AddDataField("650", ' ', '0', "a", "Middle Earth (Imaginary place)", "v", "Fiction.")
+
+		decoded := BookFromMARC(record)
+		assert.Equal(t, "The hobbit: or, There and back again", decoded.Title)
+		assert.Equal(t, "J. R. R. Tolkien", decoded.Author)
+		assert.Equal(t, 1951, decoded.Year)
+		assert.Equal(t, "0-306-40615-2", *decoded.ISBN)
+		assert.Equal(t, "Middle Earth (Imaginary place)", *decoded.Genre)
+		assert.Equal(t, "english", *decoded.Language)
+		assert.Nil(t, decoded.Description)
+	})
+
+	t.Run("year from 008", func(t *testing.T) {
+		record := marc.NewRecord()
+		record.AddControlField("008", "850101s1937    enk           000 1 und d")
+		record.AddDataField("264", ' ', '4', "c", "©2001")
+
+		decoded := BookFromMARC(record)
+		assert.Equal(t, 1937, decoded.Year)
+		assert.Nil(t, decoded.Language)
+	})
+}
+
+func TestParseBookMARC(t *testing.T) {
+	book := &models.Book{ID: "not-a-uuid", Title: "Emma", Author: "Jane Austen", Year: 1815, Language: "english"}
+
+	t.Run("detects both formats", func(t *testing.T) {
+		var binary, xml bytes.Buffer
+		require.NoError(t, marc.NewWriter(&binary).Write(BookToMARC(book)))
+		xmlWriter := marc.NewXMLWriter(&xml)
+		require.NoError(t, xmlWriter.Write(BookToMARC(book)))
+		require.NoError(t, xmlWriter.Close())
+
+		for _, input := range []*bytes.Buffer{&binary, &xml} {
+			records, err := ParseBookMARC(input, "")
+			require.NoError(t, err)
+			require.Len(t, records, 1)
+			assert.Equal(t, 1, records[0].Line)
+			assert.Equal(t, "not-a-uuid", records[0].ID)
+			assert.Equal(t, "Emma", records[0].Book.Title)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ParseBookMARC(strings.NewReader("garbage"), "")
+		assert.ErrorIs(t, err, ErrInvalidMARC)
+
+		_, err = ParseBookMARC(strings.NewReader(`<collection xmlns="http://www.loc.gov/MARC21/slim"></collection>`), models.BookMARCXML)
+		assert.ErrorIs(t, err, ErrInvalidMARC)
+	})
+}
+
+func TestBookService_ImportMARCBooks(t *testing.T) {
+	service := newTestBookService()
+	isbn := "9780306406157"
+	existing, err := service.CreateBook(&models.CreateBookRequest{Title: "Dune", Author: "Frank Herbert", Year: 1965})
+	require.NoError(t, err)
+	other, err := service.CreateBook(&models.CreateBookRequest{Title: "Signals", Author: "Someone", Year: 1990, ISBN: &isbn})
+	require.NoError(t, err)
+
+	newISBN := "0-262-03384-4"
+	records := []models.BookImportRecord{
+		{Line: 1, ID: existing.ID, Book: models.CreateBookRequest{Title: "Dune", Author: "Frank Herbert", Year: 1966}},
+		{Line: 2, Book: models.CreateBookRequest{Title: "Signals, 2nd ed.", Author: "Someone", Year: 1995, ISBN: &isbn}},
+		{Line: 3, Book: models.CreateBookRequest{Title: "Algorithms", Author: "Cormen", Year: 1990, ISBN: &newISBN}},
+		{Line: 4, Book: models.CreateBookRequest{Title: "Algorithms", Author: "Cormen", Year: 1990, ISBN: &newISBN}},
+		{Line: 5, ID: existing.ID, Book: models.CreateBookRequest{Title: "Dune", Author: "Frank Herbert", Year: 1965, ISBN: &isbn}},
+		{Line: 6, Errors: []models.ValidationError{{Field: "Title", Message: "This field is required"}}},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		report, err := service.ImportMARCBooks(records, true)
+		require.NoError(t, err)
+		assert.False(t, report.Committed)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Updated)
+		assert.Empty(t, report.Rows[2].BookID)
+
+		unchanged, err := service.GetBookByID(existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1965, unchanged.Year)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		report, err := service.ImportMARCBooks(records, false)
+		require.NoError(t, err)
+		assert.True(t, report.Committed)
+		assert.Equal(t, models.BookImportBestEffort, report.Mode)
+		assert.Equal(t, 6, report.Total)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 2, report.Updated)
+		assert.Equal(t, 1, report.Duplicates)
+		assert.Equal(t, 2, report.Rejected)
+
+		statuses := make([]string, len(report.Rows))
+		for i, row := range report.Rows {
+			statuses[i] = row.Status
+		}
+		assert.Equal(t, []string{
+			models.BookImportUpdated,
+			models.BookImportUpdated,
+			models.BookImportCreated,
+			models.BookImportDuplicate,
+			models.BookImportRejected,
+			models.BookImportRejected,
+		}, statuses)
+		assert.Equal(t, "Belongs to another book", report.Rows[4].Errors[0].Message)
+
+		updated, err := service.GetBookByID(existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1966, updated.Year)
+		assert.Equal(t, 2, updated.Version)
+
+		updated, err = service.GetBookByID(other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Signals, 2nd ed.", updated.Title)
+
+		created, err := service.GetBookByID(report.Rows[2].BookID)
+		require.NoError(t, err)
+		assert.Equal(t, "9780262033848", *created.ISBN)
+	})
+}
@@ -1,263 +1,314 @@
 package services
 
 import (
-	"database/sql"
-	"database/sql/driver"
-	"errors"
 	"io"
-	"regexp"
 	"testing"
 	"time"
 
 	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestBookService_GetAllBooks(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
-
+func newTestBookService() *BookService {
 	logger := logrus.New()
 	logger.SetOutput(io.Discard)
 
-	service := NewBookService(db, logger)
+	books := repository.NewMemoryBookRepository()
+	return NewBookService(books, repository.NewMemoryAuthorRepository(books), repository.NewMemoryGenreRepository(books), repository.NewMemoryItemRepository(books), logger)
+}
 
-	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "year", "description", "isbn", "genre", "created_at", "updated_at"}).
-			AddRow("1", "The Lord of the Rings", "J.R.R. Tolkien", 1954, "Epic fantasy novel.", "978-0618640157", "Fantasy", time.Now(), time.Now())
+func TestBookService_ListBooks(t *testing.T) {
+	service := newTestBookService()
+	for _, title := range []string{"Dune", "Emma", "Beloved"} {
+		_, err := service.CreateBook(&models.CreateBookRequest{Title: title, Author: "Author", Year: 2000})
+		require.NoError(t, err)
+	}
 
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books ORDER BY created_at DESC")).
-			WillReturnRows(rows)
+	t.Run("defaults", func(t *testing.T) {
+		result, err := service.ListBooks(&models.BookListParams{})
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 3)
+		assert.Equal(t, 3, result.Total)
+		assert.Empty(t, result.NextCursor)
+	})
+
+	t.Run("sort ascending by default for non-date fields", func(t *testing.T) {
+		result, err := service.ListBooks(&models.BookListParams{Sort: "title", Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, "Beloved", result.Data[0].Title)
+		assert.Equal(t, "Dune", result.Data[1].Title)
+		assert.NotEmpty(t, result.NextCursor)
 
-		books, err := service.GetAllBooks()
+		next, err := service.ListBooks(&models.BookListParams{Sort: "title", Limit: 2, Cursor: result.NextCursor})
 		assert.NoError(t, err)
-		assert.NotNil(t, books)
-		assert.Len(t, books, 1)
-		assert.Equal(t, "The Lord of the Rings", books[0].Title)
+		assert.Len(t, next.Data, 1)
+		assert.Equal(t, "Emma", next.Data[0].Title)
 	})
 
-	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books ORDER BY created_at DESC")).
-			WillReturnError(errors.New("db error"))
+	t.Run("limit is capped", func(t *testing.T) {
+		assert.Equal(t, maxBookPageSize, normalizePageSize(1000))
+		assert.Equal(t, defaultBookPageSize, normalizePageSize(0))
+	})
 
-		books, err := service.GetAllBooks()
-		assert.Error(t, err)
-		assert.Nil(t, books)
-		assert.EqualError(t, err, "failed to fetch books: db error")
+	t.Run("invalid cursor", func(t *testing.T) {
+		result, err := service.ListBooks(&models.BookListParams{Cursor: "garbage"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		assert.Nil(t, result)
 	})
 }
 
-func TestBookService_GetBookByID(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
+func TestBookService_SearchBooks(t *testing.T) {
+	service := newTestBookService()
+	_, err := service.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937})
+	require.NoError(t, err)
 
-	logger := logrus.New()
-	logger.SetOutput(io.Discard)
+	result, err := service.SearchBooks(&models.BookSearchParams{Query: "hobbit"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+	assert.Equal(t, "The <mark>Hobbit</mark>", result.Data[0].Highlights.Title)
+}
 
-	service := NewBookService(db, logger)
-	bookID := "some-uuid"
+func TestBookService_GetBookByID(t *testing.T) {
+	service := newTestBookService()
+	created, err := service.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937})
+	require.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "year", "description", "isbn", "genre", "created_at", "updated_at"}).
-			AddRow(bookID, "The Hobbit", "J.R.R. Tolkien", 1937, "Fantasy novel.", "978-0618260300", "Fantasy", time.Now(), time.Now())
-
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnRows(rows)
-
-		book, err := service.GetBookByID(bookID)
+		book, err := service.GetBookByID(created.ID)
 		assert.NoError(t, err)
-		assert.NotNil(t, book)
-		assert.Equal(t, bookID, book.ID)
+		assert.Equal(t, created.ID, book.ID)
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnError(sql.ErrNoRows)
-
-		book, err := service.GetBookByID(bookID)
-		assert.Error(t, err)
+		book, err := service.GetBookByID("missing")
 		assert.Nil(t, book)
+		assert.ErrorIs(t, err, ErrBookNotFound)
 		assert.EqualError(t, err, "book not found")
 	})
+}
 
-	t.Run("db error", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnError(errors.New("db error"))
+func TestBookService_CreateBook(t *testing.T) {
+	service := newTestBookService()
 
-		book, err := service.GetBookByID(bookID)
-		assert.Error(t, err)
+	t.Run("defaults language", func(t *testing.T) {
+		book, err := service.CreateBook(&models.CreateBookRequest{Title: "New Book", Author: "Test Author", Year: 2024})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, book.ID)
+		assert.Equal(t, "english", book.Language)
+		assert.False(t, book.CreatedAt.IsZero())
+	})
+
+	t.Run("normalizes ISBN", func(t *testing.T) {
+		isbn10 := "0-306-40615-2"
+		book, err := service.CreateBook(&models.CreateBookRequest{Title: "Signal Processing", Author: "Test Author", Year: 1990, ISBN: &isbn10})
+		assert.NoError(t, err)
+		assert.Equal(t, "9780306406157", *book.ISBN)
+	})
+
+	t.Run("duplicate ISBN", func(t *testing.T) {
+		hyphenated := "978-0-306-40615-7"
+		book, err := service.CreateBook(&models.CreateBookRequest{Title: "Copy", Author: "Test Author", Year: 1990, ISBN: &hyphenated})
 		assert.Nil(t, book)
-		assert.EqualError(t, err, "failed to fetch book: db error")
+		assert.ErrorIs(t, err, ErrDuplicateISBN)
 	})
-}
 
-func TestBookService_CreateBook(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
+	t.Run("invalid ISBN", func(t *testing.T) {
+		garbage := "not-an-isbn"
+		book, err := service.CreateBook(&models.CreateBookRequest{Title: "Garbage", Author: "Test Author", Year: 1990, ISBN: &garbage})
+		assert.Nil(t, book)
+		assert.ErrorIs(t, err, ErrInvalidISBN)
+	})
 
-	logger := logrus.New()
-	logger.SetOutput(io.Discard)
+	t.Run("blank ISBN is stored as null", func(t *testing.T) {
+		blank := " "
+		book, err := service.CreateBook(&models.CreateBookRequest{Title: "Blank", Author: "Test Author", Year: 1990, ISBN: &blank})
+		assert.NoError(t, err)
+		assert.Nil(t, book.ISBN)
+	})
 
-	service := NewBookService(db, logger)
-	req := &models.CreateBookRequest{
-		Title:  "New Book",
-		Author: "Test Author",
-		Year:   2024,
-	}
+	t.Run("explicit language", func(t *testing.T) {
+		language := "french"
+		book, err := service.CreateBook(&models.CreateBookRequest{Title: "L'Étranger", Author: "Albert Camus", Year: 1942, Language: &language})
+		assert.NoError(t, err)
+		assert.Equal(t, "french", book.Language)
+	})
+}
 
-	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO books (id, title, author, year, description, isbn, genre, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)")).
-			WithArgs(sqlmock.AnyArg(), req.Title, req.Author, req.Year, req.Description, req.ISBN, req.Genre, sqlmock.AnyArg(), sqlmock.AnyArg()).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+func TestBookService_UpdateBook(t *testing.T) {
+	service := newTestBookService()
+	language := "french"
+	created, err := service.CreateBook(&models.CreateBookRequest{Title: "Old Title", Author: "Old Author", Year: 2024, Language: &language})
+	require.NoError(t, err)
+
+	t.Run("success keeps language", func(t *testing.T) {
+		book, err := service.UpdateBook(created.ID, &models.UpdateBookRequest{Title: "Updated Title", Author: "Updated Author", Year: 2025}, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "Updated Title", book.Title)
+		assert.Equal(t, "french", book.Language)
+		assert.Equal(t, created.CreatedAt, book.CreatedAt)
+		assert.Equal(t, 2, book.Version)
 
-		book, err := service.CreateBook(req)
+		stored, err := service.GetBookByID(created.ID)
 		assert.NoError(t, err)
-		assert.NotNil(t, book)
-		assert.Equal(t, req.Title, book.Title)
+		assert.Equal(t, "Updated Title", stored.Title)
 	})
 
-	t.Run("db error", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO books (id, title, author, year, description, isbn, genre, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)")).
-			WithArgs(sqlmock.AnyArg(), req.Title, req.Author, req.Year, req.Description, req.ISBN, req.Genre, sqlmock.AnyArg(), sqlmock.AnyArg()).
-			WillReturnError(errors.New("db error"))
+	t.Run("duplicate ISBN", func(t *testing.T) {
+		taken := "9780306406157"
+		_, err := service.CreateBook(&models.CreateBookRequest{Title: "Other", Author: "Other Author", Year: 1990, ISBN: &taken})
+		require.NoError(t, err)
 
-		book, err := service.CreateBook(req)
-		assert.Error(t, err)
+		isbn10 := "0306406152"
+		book, err := service.UpdateBook(created.ID, &models.UpdateBookRequest{Title: "Updated Title", Author: "Updated Author", Year: 2025, ISBN: &isbn10}, 0)
 		assert.Nil(t, book)
-		assert.EqualError(t, err, "failed to create book: db error")
+		assert.ErrorIs(t, err, ErrDuplicateISBN)
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		book, err := service.UpdateBook(created.ID, &models.UpdateBookRequest{Title: "Stale", Author: "Updated Author", Year: 2025}, 1)
+		assert.Nil(t, book)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+
+		book, err = service.UpdateBook(created.ID, &models.UpdateBookRequest{Title: "Fresh", Author: "Updated Author", Year: 2025}, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, book.Version)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		book, err := service.UpdateBook("missing", &models.UpdateBookRequest{Title: "Updated Title", Author: "Updated Author", Year: 2025}, 0)
+		assert.Nil(t, book)
+		assert.ErrorIs(t, err, ErrBookNotFound)
 	})
 }
 
-func TestBookService_UpdateBook(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
+func TestBookService_PatchBook(t *testing.T) {
+	service := newTestBookService()
+	description := "A hobbit goes on an adventure."
+	created, err := service.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937, Description: &description})
+	require.NoError(t, err)
 
-	logger := logrus.New()
-	logger.SetOutput(io.Discard)
+	t.Run("writes changed fields", func(t *testing.T) {
+		merged := created.Editable()
+		merged.Year = 1938
+		merged.Description = nil
 
-	service := NewBookService(db, logger)
-	bookID := "some-uuid"
-	req := &models.UpdateBookRequest{
-		Title:  "Updated Title",
-		Author: "Updated Author",
-		Year:   2025,
-	}
+		book, err := service.PatchBook(created, &merged)
+		assert.NoError(t, err)
+		assert.Equal(t, 1938, book.Year)
+		assert.Nil(t, book.Description)
+		assert.True(t, book.UpdatedAt.After(created.UpdatedAt))
+		assert.Equal(t, 2, book.Version)
 
-	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "year", "description", "isbn", "genre", "created_at", "updated_at"}).
-			AddRow(bookID, "Old Title", "Old Author", 2024, "Old Description", "Old ISBN", "Old Genre", time.Now(), time.Now())
+		stored, err := service.GetBookByID(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1938, stored.Year)
+		assert.Nil(t, stored.Description)
+		assert.Equal(t, "The Hobbit", stored.Title)
+	})
 
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnRows(rows)
+	t.Run("no changes", func(t *testing.T) {
+		current, err := service.GetBookByID(created.ID)
+		require.NoError(t, err)
 
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, author = $2, year = $3, description = $4, isbn = $5, genre = $6, updated_at = $7 WHERE id = $8")).
-			WithArgs(req.Title, req.Author, req.Year, req.Description, req.ISBN, req.Genre, sqlmock.AnyArg(), bookID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+		merged := current.Editable()
+		book, err := service.PatchBook(current, &merged)
+		assert.NoError(t, err)
+		assert.Same(t, current, book)
+	})
+
+	t.Run("normalizes ISBN", func(t *testing.T) {
+		current, err := service.GetBookByID(created.ID)
+		require.NoError(t, err)
 
-		book, err := service.UpdateBook(bookID, req)
+		isbn10 := "0-306-40615-2"
+		merged := current.Editable()
+		merged.ISBN = &isbn10
+
+		book, err := service.PatchBook(current, &merged)
 		assert.NoError(t, err)
-		assert.NotNil(t, book)
-		assert.Equal(t, req.Title, book.Title)
+		assert.Equal(t, "9780306406157", *book.ISBN)
 	})
 
-	t.Run("not found", func(t *testing.T) {
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnError(sql.ErrNoRows)
+	t.Run("stale version", func(t *testing.T) {
+		merged := created.Editable()
+		merged.Title = "Stale"
 
-		book, err := service.UpdateBook(bookID, req)
-		assert.Error(t, err)
+		book, err := service.PatchBook(created, &merged)
 		assert.Nil(t, book)
-		assert.EqualError(t, err, "book not found")
+		assert.ErrorIs(t, err, ErrVersionConflict)
 	})
 
-	t.Run("db error on update", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "title", "author", "year", "description", "isbn", "genre", "created_at", "updated_at"}).
-			AddRow(bookID, "Old Title", "Old Author", 2024, "Old Description", "Old ISBN", "Old Genre", time.Now(), time.Now())
-
-		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, author, year, description, isbn, genre, created_at, updated_at FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnRows(rows)
+	t.Run("duplicate ISBN", func(t *testing.T) {
+		other, err := service.CreateBook(&models.CreateBookRequest{Title: "Other", Author: "Other Author", Year: 1990})
+		require.NoError(t, err)
 
-		mock.ExpectExec(regexp.QuoteMeta("UPDATE books SET title = $1, author = $2, year = $3, description = $4, isbn = $5, genre = $6, updated_at = $7 WHERE id = $8")).
-			WithArgs(req.Title, req.Author, req.Year, req.Description, req.ISBN, req.Genre, sqlmock.AnyArg(), bookID).
-			WillReturnError(errors.New("db error"))
+		taken := "978-0-306-40615-7"
+		merged := other.Editable()
+		merged.ISBN = &taken
 
-		book, err := service.UpdateBook(bookID, req)
-		assert.Error(t, err)
+		book, err := service.PatchBook(other, &merged)
 		assert.Nil(t, book)
-		assert.EqualError(t, err, "failed to update book: db error")
+		assert.ErrorIs(t, err, ErrDuplicateISBN)
 	})
 }
 
 func TestBookService_DeleteBook(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
-	}
-	defer db.Close()
+	service := newTestBookService()
+	created, err := service.CreateBook(&models.CreateBookRequest{Title: "New Book", Author: "Test Author", Year: 2024})
+	require.NoError(t, err)
 
-	logger := logrus.New()
-	logger.SetOutput(io.Discard)
+	assert.ErrorIs(t, service.DeleteBook(created.ID, 2), ErrVersionConflict)
+	assert.NoError(t, service.DeleteBook(created.ID, 1))
+	assert.ErrorIs(t, service.DeleteBook(created.ID, 0), ErrBookNotFound)
 
-	service := NewBookService(db, logger)
-	bookID := "some-uuid"
+	_, err = service.GetBookByID(created.ID)
+	assert.ErrorIs(t, err, ErrBookNotFound)
+}
 
-	t.Run("success", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+func TestBookService_Trash(t *testing.T) {
+	service := newTestBookService()
+	created, err := service.CreateBook(&models.CreateBookRequest{Title: "New Book", Author: "Test Author", Year: 2024})
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteBook(created.ID, 0))
 
-		err := service.DeleteBook(bookID)
+	t.Run("list", func(t *testing.T) {
+		trash, err := service.ListTrash(&models.TrashListParams{})
 		assert.NoError(t, err)
-	})
+		assert.Equal(t, 1, trash.Total)
+		assert.NotNil(t, trash.Data[0].DeletedAt)
 
-	t.Run("not found", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		_, err = service.ListTrash(&models.TrashListParams{Cursor: "garbage"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
 
-		err := service.DeleteBook(bookID)
-		assert.Error(t, err)
-		assert.EqualError(t, err, "book not found")
+	t.Run("purge keeps books within retention", func(t *testing.T) {
+		purged, err := service.PurgeTrash(time.Hour)
+		assert.NoError(t, err)
+		assert.Zero(t, purged)
 	})
 
-	t.Run("db error on delete", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnError(errors.New("db error"))
+	t.Run("restore", func(t *testing.T) {
+		book, err := service.RestoreBook(created.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, book.ID)
+		assert.Nil(t, book.DeletedAt)
 
-		err := service.DeleteBook(bookID)
-		assert.Error(t, err)
-		assert.EqualError(t, err, "failed to delete book: db error")
+		_, err = service.RestoreBook(created.ID)
+		assert.ErrorIs(t, err, ErrBookNotFound)
 	})
 
-	t.Run("error on rows affected", func(t *testing.T) {
-		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM books WHERE id = $1")).
-			WithArgs(bookID).
-			WillReturnResult(driver.ResultNoRows)
+	t.Run("purge removes books past retention", func(t *testing.T) {
+		require.NoError(t, service.DeleteBook(created.ID, 0))
+
+		purged, err := service.PurgeTrash(0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
 
-		err := service.DeleteBook(bookID)
-		assert.Error(t, err)
-		assert.EqualError(t, err, "failed to verify deletion: no RowsAffected available after DDL statement")
+		_, err = service.RestoreBook(created.ID)
+		assert.ErrorIs(t, err, ErrBookNotFound)
 	})
 }
@@ -0,0 +1,461 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrClosureNotFound     = repository.ErrClosureNotFound
+	ErrInvalidOpeningHours = errors.New("invalid opening hours")
+	ErrInvalidClosure      = errors.New("invalid closure")
+	// ErrNoOpenDay is returned when a due date or pickup deadline cannot be
+	// moved to an open day because the library is closed for a whole year.
+	ErrNoOpenDay = errors.New("the library is not open on any day in the next year")
+)
+
+const (
+	clockLayout = "15:04"
+	// openDaySearchDays bounds the search for an open day.
+	openDaySearchDays = 400
+)
+
+// CalendarService keeps the opening hours and closures of the library and
+// its branches. A branch keeps to its own hours when it has any and the
+// library's otherwise, and is closed for the library's closures and its
+// own.
+type CalendarService struct {
+	repo     repository.CalendarRepository
+	branches repository.BranchRepository
+	location *time.Location
+	logger   *logrus.Logger
+}
+
+// NewCalendarService returns a service working out days in the library's
+// time zone, location.
+func NewCalendarService(repo repository.CalendarRepository, branches repository.BranchRepository, location *time.Location, logger *logrus.Logger) *CalendarService {
+	return &CalendarService{
+		repo:     repo,
+		branches: branches,
+		location: location,
+		logger:   logger,
+	}
+}
+
+// GetHours returns the hours a branch keeps to, or the library's.
+func (s *CalendarService) GetHours(params *models.OpeningHoursParams) (*models.OpeningHoursResponse, error) {
+	s.logger.WithField("branch_id", params.BranchID).Info("Fetching opening hours")
+
+	branchID := optionalID(params.BranchID)
+	if err := checkBranch(s.branches, branchID, s.logger); err != nil {
+		return nil, err
+	}
+	hours, err := s.repo.Hours()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query opening hours")
+		return nil, err
+	}
+	return &models.OpeningHoursResponse{
+		TimeZone: s.location.String(),
+		BranchID: branchID,
+		Data:     branchHours(hours, branchID),
+	}, nil
+}
+
+// SetHours replaces the weekly schedule of the library or a branch. Each
+// weekday may appear once and must close after it opens.
+func (s *CalendarService) SetHours(req *models.SetOpeningHoursRequest) (*models.OpeningHoursResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"branch_id": req.BranchID,
+		"days":      len(req.Hours),
+	}).Info("Setting opening hours")
+
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	hours := make([]models.OpeningHours, 0, len(req.Hours))
+	listed := make(map[int]bool)
+	for _, day := range req.Hours {
+		if listed[day.Weekday] {
+			return nil, fmt.Errorf("%w: weekday %d is listed twice", ErrInvalidOpeningHours, day.Weekday)
+		}
+		listed[day.Weekday] = true
+
+		opens, err := time.Parse(clockLayout, strings.TrimSpace(day.Opens))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not a time", ErrInvalidOpeningHours, day.Opens)
+		}
+		closes, err := time.Parse(clockLayout, strings.TrimSpace(day.Closes))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not a time", ErrInvalidOpeningHours, day.Closes)
+		}
+		if !closes.After(opens) {
+			return nil, fmt.Errorf("%w: weekday %d closes before it opens", ErrInvalidOpeningHours, day.Weekday)
+		}
+
+		hours = append(hours, models.OpeningHours{
+			Weekday: day.Weekday,
+			Opens:   opens.Format(clockLayout),
+			Closes:  closes.Format(clockLayout),
+		})
+	}
+
+	if err := s.repo.ReplaceHours(req.BranchID, hours); err != nil {
+		if errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).Warn("Failed to set opening hours")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to set opening hours")
+		return nil, err
+	}
+
+	s.logger.Info("Successfully set opening hours")
+	params := &models.OpeningHoursParams{}
+	if req.BranchID != nil {
+		params.BranchID = *req.BranchID
+	}
+	return s.GetHours(params)
+}
+
+func (s *CalendarService) ListClosures(params *models.ClosureListParams) (*models.ClosureListResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"from":      params.From,
+		"to":        params.To,
+		"branch_id": params.BranchID,
+	}).Info("Fetching closures")
+
+	closures, err := s.repo.ListClosures(params.From, params.To, params.BranchID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query closures")
+		return nil, err
+	}
+	return &models.ClosureListResponse{Data: closures}, nil
+}
+
+func (s *CalendarService) GetClosure(id string) (*models.Closure, error) {
+	s.logger.WithField("closure_id", id).Info("Fetching closure by ID")
+
+	closure, err := s.repo.GetClosure(id)
+	if errors.Is(err, ErrClosureNotFound) {
+		s.logger.WithField("closure_id", id).Warn("Closure not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("closure_id", id).Error("Failed to fetch closure")
+		return nil, err
+	}
+
+	return closure, nil
+}
+
+func (s *CalendarService) CreateClosure(req *models.ClosureRequest) (*models.Closure, error) {
+	s.logger.WithField("name", req.Name).Info("Creating closure")
+
+	if err := checkClosure(req); err != nil {
+		s.logger.WithError(err).Warn("Invalid closure")
+		return nil, err
+	}
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	closure := &models.Closure{
+		ID:        uuid.New().String(),
+		BranchID:  req.BranchID,
+		Name:      strings.TrimSpace(req.Name),
+		StartsOn:  req.StartsOn,
+		EndsOn:    req.EndsOn,
+		Annual:    req.Annual,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.CreateClosure(closure); err != nil {
+		if errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).Warn("Failed to create closure")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create closure")
+		return nil, err
+	}
+
+	s.logger.WithField("closure_id", closure.ID).Info("Successfully created closure")
+	return closure, nil
+}
+
+func (s *CalendarService) UpdateClosure(id string, req *models.ClosureRequest) (*models.Closure, error) {
+	s.logger.WithField("closure_id", id).Info("Updating closure")
+
+	if err := checkClosure(req); err != nil {
+		s.logger.WithError(err).WithField("closure_id", id).Warn("Invalid closure")
+		return nil, err
+	}
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	closure := &models.Closure{
+		ID:        id,
+		BranchID:  req.BranchID,
+		Name:      strings.TrimSpace(req.Name),
+		StartsOn:  req.StartsOn,
+		EndsOn:    req.EndsOn,
+		Annual:    req.Annual,
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.repo.UpdateClosure(closure)
+	if errors.Is(err, ErrClosureNotFound) || errors.Is(err, ErrBranchNotFound) {
+		s.logger.WithError(err).WithField("closure_id", id).Warn("Failed to update closure")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("closure_id", id).Error("Failed to update closure")
+		return nil, err
+	}
+
+	s.logger.WithField("closure_id", id).Info("Successfully updated closure")
+	return closure, nil
+}
+
+func (s *CalendarService) DeleteClosure(id string) error {
+	s.logger.WithField("closure_id", id).Info("Deleting closure")
+
+	err := s.repo.DeleteClosure(id)
+	if errors.Is(err, ErrClosureNotFound) {
+		s.logger.WithField("closure_id", id).Warn("Closure not found")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("closure_id", id).Error("Failed to delete closure")
+		return err
+	}
+
+	s.logger.WithField("closure_id", id).Info("Successfully deleted closure")
+	return nil
+}
+
+// NextOpenDay returns the first day the library, or a branch, is open on
+// or after a date, today by default.
+func (s *CalendarService) NextOpenDay(params *models.OpenDayParams) (*models.OpenDayResponse, error) {
+	branchID := optionalID(params.BranchID)
+	if err := checkBranch(s.branches, branchID, s.logger); err != nil {
+		return nil, err
+	}
+	day := s.today(time.Now())
+	if params.Date != "" {
+		parsed, err := time.Parse(dateLayout, params.Date)
+		if err != nil {
+			return nil, err
+		}
+		day = parsed
+	}
+
+	openOn, err := s.openOn(day, branchID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OpenDayResponse{Date: day.Format(dateLayout), OpenOn: openOn}, nil
+}
+
+// dueDate is the first day a branch, or the library when branchID is nil,
+// is open at least days after today.
+func (s *CalendarService) dueDate(now time.Time, days int, branchID *string) (string, error) {
+	return s.openOn(s.today(now).AddDate(0, 0, days), branchID)
+}
+
+// holdPickup is when a copy trapped now waits on the hold shelf until: the
+// first day the branch it waits at is open at least pickupDays after today.
+// It is worked out for each branch with opening hours or closures of its
+// own, and the library's for the others.
+func (s *CalendarService) holdPickup(now time.Time, pickupDays int) (repository.HoldPickup, error) {
+	today := s.today(now)
+	day := today.AddDate(0, 0, pickupDays)
+	schedule, err := s.schedule(day)
+	if err != nil {
+		return repository.HoldPickup{}, err
+	}
+	pickup := repository.HoldPickup{Today: today.Format(dateLayout)}
+	if pickup.PickupBy, err = s.searchOpenDay(schedule, day, nil); err != nil {
+		return repository.HoldPickup{}, err
+	}
+	for _, branchID := range schedule.branches() {
+		pickupBy, err := s.searchOpenDay(schedule, day, &branchID)
+		if err != nil {
+			return repository.HoldPickup{}, err
+		}
+		if pickupBy != pickup.PickupBy {
+			if pickup.BranchPickupBy == nil {
+				pickup.BranchPickupBy = make(map[string]string)
+			}
+			pickup.BranchPickupBy[branchID] = pickupBy
+		}
+	}
+	return pickup, nil
+}
+
+// today is the date in the library's time zone at now, as midnight UTC so
+// that adding days never crosses a daylight saving change.
+func (s *CalendarService) today(now time.Time) time.Time {
+	year, month, day := now.In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// openOn returns the first day on or after day that a branch, or the
+// library when branchID is nil, has opening hours and no closure.
+func (s *CalendarService) openOn(day time.Time, branchID *string) (string, error) {
+	schedule, err := s.schedule(day)
+	if err != nil {
+		return "", err
+	}
+	return s.searchOpenDay(schedule, day, branchID)
+}
+
+// calendarSchedule is the opening hours and closures of the library and
+// every branch over the days searched for an open day.
+type calendarSchedule struct {
+	hours    []models.OpeningHours
+	closures []models.Closure
+}
+
+// schedule loads the opening hours and the closures over the days searched
+// for an open day from day on.
+func (s *CalendarService) schedule(day time.Time) (*calendarSchedule, error) {
+	hours, err := s.repo.Hours()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query opening hours")
+		return nil, err
+	}
+	last := day.AddDate(0, 0, openDaySearchDays)
+	closures, err := s.repo.ListClosures(day.Format(dateLayout), last.Format(dateLayout), "")
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query closures")
+		return nil, err
+	}
+	return &calendarSchedule{hours: hours, closures: closures}, nil
+}
+
+// branches returns the branches with opening hours or closures of their
+// own.
+func (c *calendarSchedule) branches() []string {
+	seen := make(map[string]bool)
+	branches := []string{}
+	add := func(branchID *string) {
+		if branchID != nil && !seen[*branchID] {
+			seen[*branchID] = true
+			branches = append(branches, *branchID)
+		}
+	}
+	for _, h := range c.hours {
+		add(h.BranchID)
+	}
+	for _, closure := range c.closures {
+		add(closure.BranchID)
+	}
+	return branches
+}
+
+// searchOpenDay returns the first day on or after day that a branch, or the
+// library when branchID is nil, has opening hours and no closure.
+func (s *CalendarService) searchOpenDay(schedule *calendarSchedule, day time.Time, branchID *string) (string, error) {
+	hours := branchHours(schedule.hours, branchID)
+	open := make(map[time.Weekday]bool, len(hours))
+	for _, h := range hours {
+		open[time.Weekday(h.Weekday)] = true
+	}
+	closures := []models.Closure{}
+	for _, closure := range schedule.closures {
+		if closure.BranchID == nil || branchID != nil && *closure.BranchID == *branchID {
+			closures = append(closures, closure)
+		}
+	}
+
+	for last := day.AddDate(0, 0, openDaySearchDays); day.Before(last); day = day.AddDate(0, 0, 1) {
+		if len(hours) > 0 && !open[day.Weekday()] {
+			continue
+		}
+		closed := false
+		for _, closure := range closures {
+			closed = closed || closureCovers(closure, day)
+		}
+		if !closed {
+			return day.Format(dateLayout), nil
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"from":      day.Format(dateLayout),
+		"branch_id": branchID,
+	}).Warn("No open day found")
+	return "", ErrNoOpenDay
+}
+
+// branchHours picks the hours a branch keeps to out of every branch's: its
+// own when it has any, the library's otherwise.
+func branchHours(hours []models.OpeningHours, branchID *string) []models.OpeningHours {
+	own, library := []models.OpeningHours{}, []models.OpeningHours{}
+	for _, h := range hours {
+		switch {
+		case h.BranchID == nil:
+			library = append(library, h)
+		case branchID != nil && *h.BranchID == *branchID:
+			own = append(own, h)
+		}
+	}
+	if len(own) > 0 {
+		return own
+	}
+	return library
+}
+
+// closureCovers reports whether the library is closed on day for a
+// closure. An annual closure is tried in the year of day and, for one
+// running into the next year, the year before.
+func closureCovers(closure models.Closure, day time.Time) bool {
+	date := day.Format(dateLayout)
+	if !closure.Annual {
+		return closure.StartsOn <= date && date <= closure.EndsOn
+	}
+
+	startsOn, err := time.Parse(dateLayout, closure.StartsOn)
+	if err != nil {
+		return false
+	}
+	endsOn, err := time.Parse(dateLayout, closure.EndsOn)
+	if err != nil {
+		return false
+	}
+	for _, year := range []int{day.Year() - 1, day.Year()} {
+		shift := year - startsOn.Year()
+		if !day.Before(startsOn.AddDate(shift, 0, 0)) && !day.After(endsOn.AddDate(shift, 0, 0)) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkClosure(req *models.ClosureRequest) error {
+	if req.EndsOn < req.StartsOn {
+		return fmt.Errorf("%w: it ends before it starts", ErrInvalidClosure)
+	}
+	if req.Annual {
+		startsOn, err := time.Parse(dateLayout, req.StartsOn)
+		if err != nil {
+			return fmt.Errorf("%w: %s is not a date", ErrInvalidClosure, req.StartsOn)
+		}
+		if req.EndsOn >= startsOn.AddDate(1, 0, 0).Format(dateLayout) {
+			return fmt.Errorf("%w: an annual closure must be shorter than a year", ErrInvalidClosure)
+		}
+	}
+	return nil
+}
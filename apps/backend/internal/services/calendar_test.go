@@ -0,0 +1,192 @@
+package services
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCalendarService(location *time.Location) *CalendarService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	books := repository.NewMemoryBookRepository()
+	loans := repository.NewMemoryLoanRepository(repository.NewMemoryItemRepository(books), repository.NewMemoryMemberRepository())
+	return NewCalendarService(repository.NewMemoryCalendarRepository(), repository.NewMemoryBranchRepository(loans), location, logger)
+}
+
+func TestCalendarService_OpenDays(t *testing.T) {
+	calendar := newTestCalendarService(time.FixedZone("NZDT", 13*60*60))
+
+	weekdays := []models.OpeningHoursRequest{}
+	for weekday := 1; weekday <= 5; weekday++ {
+		weekdays = append(weekdays, models.OpeningHoursRequest{Weekday: weekday, Opens: "9:00", Closes: "17:30"})
+	}
+	hours, err := calendar.SetHours(&models.SetOpeningHoursRequest{Hours: weekdays})
+	require.NoError(t, err)
+	require.Len(t, hours.Data, 5)
+	assert.Equal(t, "09:00", hours.Data[0].Opens)
+	assert.Equal(t, "NZDT", hours.TimeZone)
+
+	_, err = calendar.CreateClosure(&models.ClosureRequest{Name: "Christmas", StartsOn: "2023-12-24", EndsOn: "2024-01-01", Annual: true})
+	require.NoError(t, err)
+	_, err = calendar.CreateClosure(&models.ClosureRequest{Name: "Stocktake", StartsOn: "2024-11-04", EndsOn: "2024-11-05"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		date string
+		want string
+	}{
+		{"open", "2024-11-01", "2024-11-01"},
+		{"weekend", "2024-11-02", "2024-11-06"},
+		{"annual closure", "2024-12-24", "2025-01-02"},
+		{"annual closure into the next year", "2026-01-01", "2026-01-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, err := calendar.NextOpenDay(&models.OpenDayParams{Date: tt.date})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, day.OpenOn)
+		})
+	}
+
+	t.Run("due dates in the library's time zone", func(t *testing.T) {
+		// Friday evening in UTC is already Saturday in the library.
+		now := time.Date(2024, 12, 20, 23, 30, 0, 0, time.UTC)
+		dueOn, err := calendar.dueDate(now, 3, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "2025-01-02", dueOn)
+
+		pickup, err := calendar.holdPickup(now, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-12-21", pickup.Today)
+		assert.Equal(t, "2024-12-23", pickup.PickupBy)
+		assert.Empty(t, pickup.BranchPickupBy)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := calendar.SetHours(&models.SetOpeningHoursRequest{Hours: []models.OpeningHoursRequest{
+			{Weekday: 1, Opens: "09:00", Closes: "17:00"},
+			{Weekday: 1, Opens: "18:00", Closes: "20:00"},
+		}})
+		assert.ErrorIs(t, err, ErrInvalidOpeningHours)
+		_, err = calendar.SetHours(&models.SetOpeningHoursRequest{Hours: []models.OpeningHoursRequest{{Weekday: 2, Opens: "17:00", Closes: "09:00"}}})
+		assert.ErrorIs(t, err, ErrInvalidOpeningHours)
+
+		_, err = calendar.CreateClosure(&models.ClosureRequest{Name: "Backwards", StartsOn: "2024-05-02", EndsOn: "2024-05-01"})
+		assert.ErrorIs(t, err, ErrInvalidClosure)
+		_, err = calendar.CreateClosure(&models.ClosureRequest{Name: "Forever", StartsOn: "2024-01-01", EndsOn: "2025-01-01", Annual: true})
+		assert.ErrorIs(t, err, ErrInvalidClosure)
+	})
+
+	t.Run("closed all year", func(t *testing.T) {
+		closed := newTestCalendarService(time.UTC)
+		_, err := closed.CreateClosure(&models.ClosureRequest{Name: "Closed", StartsOn: "2024-01-01", EndsOn: "2025-12-31"})
+		require.NoError(t, err)
+		_, err = closed.NextOpenDay(&models.OpenDayParams{Date: "2024-06-01"})
+		assert.ErrorIs(t, err, ErrNoOpenDay)
+	})
+}
+
+func TestCalendarService_Branches(t *testing.T) {
+	fixture := newCirculationFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	calendar := fixture.calendar
+	kellynch, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "KEL", Name: "Kellynch"})
+	require.NoError(t, err)
+	uppercross, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "UPX", Name: "Uppercross"})
+	require.NoError(t, err)
+
+	weekdays := []models.OpeningHoursRequest{}
+	for weekday := 1; weekday <= 5; weekday++ {
+		weekdays = append(weekdays, models.OpeningHoursRequest{Weekday: weekday, Opens: "09:00", Closes: "17:00"})
+	}
+	_, err = calendar.SetHours(&models.SetOpeningHoursRequest{Hours: weekdays})
+	require.NoError(t, err)
+	saturdays, err := calendar.SetHours(&models.SetOpeningHoursRequest{BranchID: &kellynch.ID,
+		Hours: []models.OpeningHoursRequest{{Weekday: 6, Opens: "10:00", Closes: "14:00"}}})
+	require.NoError(t, err)
+	assert.Equal(t, &kellynch.ID, saturdays.BranchID)
+	_, err = calendar.CreateClosure(&models.ClosureRequest{Name: "Stocktake", BranchID: &uppercross.ID, StartsOn: "2024-11-04", EndsOn: "2024-11-05"})
+	require.NoError(t, err)
+
+	hours, err := calendar.GetHours(&models.OpeningHoursParams{BranchID: uppercross.ID})
+	require.NoError(t, err)
+	assert.Len(t, hours.Data, 5, "a branch without hours of its own keeps the library's")
+	hours, err = calendar.GetHours(&models.OpeningHoursParams{BranchID: kellynch.ID})
+	require.NoError(t, err)
+	assert.Len(t, hours.Data, 1)
+
+	tests := []struct {
+		name     string
+		branchID string
+		date     string
+		want     string
+	}{
+		{"library", "", "2024-11-02", "2024-11-04"},
+		{"own hours", kellynch.ID, "2024-11-03", "2024-11-09"},
+		{"own closure", uppercross.ID, "2024-11-02", "2024-11-06"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day, err := calendar.NextOpenDay(&models.OpenDayParams{BranchID: tt.branchID, Date: tt.date})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, day.OpenOn)
+		})
+	}
+
+	t.Run("closures listed for a branch", func(t *testing.T) {
+		closures, err := calendar.ListClosures(&models.ClosureListParams{BranchID: kellynch.ID})
+		require.NoError(t, err)
+		assert.Empty(t, closures.Data)
+		closures, err = calendar.ListClosures(&models.ClosureListParams{BranchID: uppercross.ID})
+		require.NoError(t, err)
+		assert.Len(t, closures.Data, 1)
+	})
+
+	t.Run("hold pickup at each branch", func(t *testing.T) {
+		now := time.Date(2024, 11, 1, 12, 0, 0, 0, time.Local)
+		pickup, err := calendar.holdPickup(now, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-11-04", pickup.PickupBy)
+		assert.Equal(t, map[string]string{kellynch.ID: "2024-11-09", uppercross.ID: "2024-11-06"}, pickup.BranchPickupBy)
+
+		dueOn, err := calendar.dueDate(now, 2, &kellynch.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-11-09", dueOn)
+	})
+
+	t.Run("unknown branch", func(t *testing.T) {
+		missing := uuid.New().String()
+		_, err := calendar.SetHours(&models.SetOpeningHoursRequest{BranchID: &missing, Hours: weekdays})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+		_, err = calendar.CreateClosure(&models.ClosureRequest{Name: "Missing", BranchID: &missing, StartsOn: "2024-11-04", EndsOn: "2024-11-04"})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+		_, err = calendar.NextOpenDay(&models.OpenDayParams{BranchID: missing, Date: "2024-11-04"})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+	})
+}
+
+func TestCalendarService_Circulation(t *testing.T) {
+	fixture := newCirculationFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	now := time.Now()
+	closedOn := now.AddDate(0, 0, 14).Format(dateLayout)
+	_, err = fixture.calendar.CreateClosure(&models.ClosureRequest{Name: "Inset day", StartsOn: closedOn, EndsOn: closedOn})
+	require.NoError(t, err)
+
+	loan, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, 15).Format(dateLayout), loan.DueOn)
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/pkg/cardnumber"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrItemNotAvailable    = repository.ErrItemNotAvailable
+	ErrItemOnHold          = repository.ErrItemOnHold
+	ErrNoActiveLoan        = repository.ErrNoActiveLoan
+	ErrLoanLimitReached    = repository.ErrLoanLimitReached
+	ErrRenewalLimitReached = repository.ErrRenewalLimitReached
+	ErrHoldsWaiting        = repository.ErrHoldsWaiting
+	ErrMemberBlocked       = errors.New("member is blocked")
+	ErrMembershipExpired   = errors.New("membership has expired")
+)
+
+// LoanRules are the lending limits circulation enforces.
+type LoanRules struct {
+	// LoanDays is how many days a checkout or renewal lends a copy for when
+	// no loan policy sets it.
+	LoanDays int
+	// MaxLoans is how many copies a member may have on loan at once.
+	MaxLoans int
+	// MaxRenewals is how many times a loan may be renewed when no loan
+	// policy sets it.
+	MaxRenewals int
+	// HoldPickupDays is how many days a copy trapped for a hold waits on
+	// the hold shelf. A deadline on a closed day moves to the next open day.
+	HoldPickupDays int
+}
+
+type CirculationService struct {
+	loans    repository.LoanRepository
+	items    repository.ItemRepository
+	members  repository.MemberRepository
+	fines    *FineService
+	policies *PolicyService
+	calendar *CalendarService
+	branches repository.BranchRepository
+	rules    LoanRules
+	logger   *logrus.Logger
+}
+
+func NewCirculationService(loans repository.LoanRepository, items repository.ItemRepository, members repository.MemberRepository, fines *FineService, policies *PolicyService, calendar *CalendarService, branches repository.BranchRepository, rules LoanRules, logger *logrus.Logger) *CirculationService {
+	return &CirculationService{
+		loans:    loans,
+		items:    items,
+		members:  members,
+		fines:    fines,
+		policies: policies,
+		calendar: calendar,
+		branches: branches,
+		rules:    rules,
+		logger:   logger,
+	}
+}
+
+// Checkout lends the copy with a barcode to the member with a card number.
+// The loan period and fine rates come from the loan policy at the desk's
+// branch, or the copy's current branch when the desk gives none, and the
+// due date moves to the next day that branch is open. A copy on the hold
+// shelf is only lent to the member it was trapped for, and members who owe
+// more than the fine limit may not borrow.
+func (s *CirculationService) Checkout(req *models.CheckoutRequest) (*models.Loan, error) {
+	s.logger.WithFields(logrus.Fields{
+		"barcode":     req.Barcode,
+		"card_number": req.CardNumber,
+	}).Info("Checking out item")
+
+	item, err := s.itemByBarcode(req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.memberByCard(req.CardNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := checkMember(s.logger, member, s.calendar.today(now)); err != nil {
+		return nil, err
+	}
+	if err := s.fines.checkBalance(member.ID); err != nil {
+		return nil, err
+	}
+	branchID := req.BranchID
+	if branchID == nil {
+		branchID = item.CurrentBranchID
+	}
+	terms, err := s.policies.loanTerms(member.MembershipType, item.BookID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	dueOn, err := s.calendar.dueDate(now, terms.LoanDays, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	loan := &models.Loan{
+		ID:             uuid.New().String(),
+		ItemID:         item.ID,
+		MemberID:       member.ID,
+		BranchID:       req.BranchID,
+		DueOn:          dueOn,
+		DailyFineCents: &terms.DailyFineCents,
+		MaxFineCents:   &terms.MaxFineCents,
+		CheckedOutAt:   now,
+	}
+
+	if err := s.loans.Checkout(loan, s.rules.MaxLoans); err != nil {
+		if errors.Is(err, ErrItemNotAvailable) || errors.Is(err, ErrItemOnHold) || errors.Is(err, ErrLoanLimitReached) ||
+			errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("item_id", item.ID).Warn("Failed to check out item")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("item_id", item.ID).Error("Failed to check out item")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"loan_id": loan.ID,
+		"due_on":  loan.DueOn,
+	}).Info("Successfully checked out item")
+	return loan, nil
+}
+
+// Checkin ends the loan of the copy with a barcode, at a branch if given.
+// The copy is trapped for the next hold on its book it can fill, if any,
+// and shipped to the hold's pickup branch, or to its home branch when no
+// hold takes it, if it was returned elsewhere.
+func (s *CirculationService) Checkin(req *models.CheckinRequest) (*models.CheckinResponse, error) {
+	s.logger.WithField("barcode", req.Barcode).Info("Checking in item")
+
+	item, err := s.itemByBarcode(req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return nil, err
+	}
+	loan, hold, err := s.loans.Checkin(item.ID, req.BranchID, now, pickup)
+	if errors.Is(err, ErrNoActiveLoan) {
+		s.logger.WithField("item_id", item.ID).Warn("Item is not on loan")
+		return nil, err
+	}
+	if errors.Is(err, ErrBranchNotFound) {
+		s.logger.WithError(err).WithField("item_id", item.ID).Warn("Failed to check in item")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("item_id", item.ID).Error("Failed to check in item")
+		return nil, err
+	}
+
+	if hold != nil {
+		s.logger.WithFields(logrus.Fields{
+			"loan_id": loan.ID,
+			"hold_id": hold.ID,
+		}).Info("Successfully checked in item for a hold")
+	} else {
+		s.logger.WithField("loan_id", loan.ID).Info("Successfully checked in item")
+	}
+	return &models.CheckinResponse{Loan: loan, Hold: hold}, nil
+}
+
+// Renew extends the loan of the copy with a barcode by the loan period of
+// the policy at the branch the loan was made at, or the copy's current
+// branch, and moves the due date to the next day that branch is open. It
+// fails past the policy's renewal limit, when the borrower may no longer
+// borrow and while another member is waiting for the copy. A renewal never
+// brings the due date forward.
+func (s *CirculationService) Renew(req *models.RenewRequest) (*models.Loan, error) {
+	s.logger.WithField("barcode", req.Barcode).Info("Renewing loan")
+
+	item, err := s.itemByBarcode(req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err := s.loans.GetActiveByItem(item.ID)
+	if errors.Is(err, ErrNoActiveLoan) {
+		s.logger.WithField("item_id", item.ID).Warn("Item is not on loan")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("item_id", item.ID).Error("Failed to fetch loan")
+		return nil, err
+	}
+
+	member, err := s.members.GetByID(loan.MemberID)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", loan.MemberID).Error("Failed to fetch member")
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := checkMember(s.logger, member, s.calendar.today(now)); err != nil {
+		return nil, err
+	}
+
+	branchID := loan.BranchID
+	if branchID == nil {
+		branchID = item.CurrentBranchID
+	}
+	terms, err := s.policies.loanTerms(member.MembershipType, item.BookID, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	dueOn, err := s.calendar.dueDate(now, terms.LoanDays, branchID)
+	if err != nil {
+		return nil, err
+	}
+	if dueOn < loan.DueOn {
+		dueOn = loan.DueOn
+	}
+
+	renewed, err := s.loans.Renew(item.ID, dueOn, terms.MaxRenewals, now, s.calendar.today(now).Format(dateLayout))
+	if errors.Is(err, ErrNoActiveLoan) || errors.Is(err, ErrRenewalLimitReached) || errors.Is(err, ErrHoldsWaiting) {
+		s.logger.WithError(err).WithField("item_id", item.ID).Warn("Failed to renew loan")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("item_id", item.ID).Error("Failed to renew loan")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"loan_id": renewed.ID,
+		"due_on":  renewed.DueOn,
+	}).Info("Successfully renewed loan")
+	return renewed, nil
+}
+
+func (s *CirculationService) ListMemberLoans(memberID string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	s.logger.WithField("member_id", memberID).Info("Fetching member loans")
+
+	if _, err := s.members.GetByID(memberID); err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			s.logger.WithField("member_id", memberID).Warn("Member not found")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch member")
+		return nil, err
+	}
+
+	response, err := s.loans.ListByMember(memberID, s.loanQuery(params))
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to query loans")
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *CirculationService) ListItemLoans(itemID string, params *models.LoanListParams) (*models.LoanListResponse, error) {
+	s.logger.WithField("item_id", itemID).Info("Fetching item loans")
+
+	if _, err := s.items.GetByID(itemID); err != nil {
+		if errors.Is(err, ErrItemNotFound) {
+			s.logger.WithField("item_id", itemID).Warn("Item not found")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("item_id", itemID).Error("Failed to fetch item")
+		return nil, err
+	}
+
+	response, err := s.loans.ListByItem(itemID, s.loanQuery(params))
+	if err != nil {
+		s.logger.WithError(err).WithField("item_id", itemID).Error("Failed to query loans")
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *CirculationService) loanQuery(params *models.LoanListParams) *models.LoanListParams {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+	query.Today = s.calendar.today(time.Now()).Format(dateLayout)
+	return &query
+}
+
+func (s *CirculationService) itemByBarcode(barcode string) (*models.Item, error) {
+	barcode = strings.TrimSpace(barcode)
+	item, err := s.items.GetByBarcode(barcode)
+	if errors.Is(err, ErrItemNotFound) {
+		s.logger.WithField("barcode", barcode).Warn("Item not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("barcode", barcode).Error("Failed to fetch item")
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *CirculationService) memberByCard(card string) (*models.Member, error) {
+	card = strings.TrimSpace(card)
+	if !cardnumber.IsValid(card) {
+		s.logger.WithField("card_number", card).Warn("Invalid card number")
+		return nil, ErrInvalidCardNumber
+	}
+
+	member, err := s.members.GetByCardNumber(card)
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("card_number", card).Warn("Member not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("card_number", card).Error("Failed to fetch member")
+		return nil, err
+	}
+	return member, nil
+}
+
+// checkMember returns an error when a member may not borrow or place holds
+// on the library date today.
+func checkMember(logger *logrus.Logger, member *models.Member, today time.Time) error {
+	if member.Blocked {
+		logger.WithField("member_id", member.ID).Warn("Member is blocked")
+		return ErrMemberBlocked
+	}
+	if member.ExpiresOn < today.Format(dateLayout) {
+		logger.WithField("member_id", member.ID).Warn("Membership has expired")
+		return ErrMembershipExpired
+	}
+	return nil
+}
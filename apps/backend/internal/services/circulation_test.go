@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCirculationService lends copies on the loan rules of the library,
+// subject to the policies and fines given.
+func (l *testLibrary) newCirculationService(policies *PolicyService, fines *FineService) *CirculationService {
+	return NewCirculationService(l.loanRepo, l.itemRepo, l.memberRepo, fines, policies, l.calendar, l.branchRepo, l.rules, l.logger)
+}
+
+type circulationFixture struct {
+	*testLibrary
+	circulation *CirculationService
+}
+
+func newCirculationFixture(t *testing.T, rules LoanRules) *circulationFixture {
+	lib := newTestLibrary(t, rules)
+	return &circulationFixture{
+		testLibrary: lib,
+		circulation: lib.newCirculationService(lib.newPolicyService(), lib.newFineService()),
+	}
+}
+
+func TestCirculationService_Checkout(t *testing.T) {
+	fixture := newCirculationFixture(t, LoanRules{LoanDays: 14, MaxLoans: 1, MaxRenewals: 1})
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	_, err = fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-2"})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	expiresOn := time.Now().AddDate(0, 0, -1).Format(dateLayout)
+	mary, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Mary", LastName: "Musgrove", MembershipType: models.MembershipAdult, ExpiresOn: &expiresOn})
+	require.NoError(t, err)
+
+	loan, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: " P-1 ", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().AddDate(0, 0, 14).Format(dateLayout), loan.DueOn)
+	assert.Equal(t, fixture.bookID, loan.BookID)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-2", CardNumber: anne.CardNumber})
+	assert.ErrorIs(t, err, ErrLoanLimitReached)
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-2", CardNumber: mary.CardNumber})
+	assert.ErrorIs(t, err, ErrMembershipExpired)
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-2", CardNumber: "12345"})
+	assert.ErrorIs(t, err, ErrInvalidCardNumber)
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-9", CardNumber: anne.CardNumber})
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	_, err = fixture.members.BlockMember(anne.ID, &models.BlockMemberRequest{Reason: "Overdue books"})
+	require.NoError(t, err)
+	_, err = fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+	assert.ErrorIs(t, err, ErrMemberBlocked)
+
+	assert.ErrorIs(t, fixture.members.DeleteMember(anne.ID), ErrMemberHasLoans)
+}
+
+func TestCirculationService_LibraryDates(t *testing.T) {
+	fixture := newCirculationFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1})
+	// Membership expiry and suspensions follow the library's date, which
+	// is usually a day ahead of the server's in this zone.
+	fixture.calendar.location = time.FixedZone("UTC+14", 14*60*60)
+	yesterday := fixture.calendar.today(time.Now()).AddDate(0, 0, -1).Format(dateLayout)
+
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	member, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult, ExpiresOn: &yesterday})
+	require.NoError(t, err)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: member.CardNumber})
+	assert.ErrorIs(t, err, ErrMembershipExpired)
+	holds := fixture.newHoldService(fixture.newPolicyService())
+	_, err = holds.SuspendHold("some-hold", &models.SuspendHoldRequest{Until: yesterday})
+	assert.ErrorIs(t, err, ErrSuspensionEnded)
+}
+
+func TestCirculationService_CheckinAndRenew(t *testing.T) {
+	fixture := newCirculationFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1})
+	item, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	member, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Frederick", LastName: "Wentworth", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: member.CardNumber})
+	require.NoError(t, err)
+
+	renewed, err := fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, renewed.Renewals)
+	_, err = fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+	assert.ErrorIs(t, err, ErrRenewalLimitReached)
+
+	_, err = fixture.items.SetItemStatus(item.ID, &models.ItemStatusRequest{Status: models.ItemLost})
+	require.NoError(t, err)
+	_, err = fixture.items.SetItemStatus(item.ID, &models.ItemStatusRequest{Status: models.ItemAvailable})
+	assert.ErrorIs(t, err, ErrItemOnLoan)
+	assert.ErrorIs(t, fixture.books.DeleteBook(fixture.bookID, 0), ErrBookInCirculation)
+
+	returned, err := fixture.circulation.Checkin(&models.CheckinRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	assert.NotNil(t, returned.Loan.ReturnedAt)
+	assert.Nil(t, returned.Hold)
+	_, err = fixture.circulation.Checkin(&models.CheckinRequest{Barcode: "P-1"})
+	assert.ErrorIs(t, err, ErrNoActiveLoan)
+
+	found, err := fixture.items.GetItemByID(item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAvailable, found.Status)
+
+	history, err := fixture.circulation.ListMemberLoans(member.ID, &models.LoanListParams{Status: models.LoanReturned})
+	require.NoError(t, err)
+	assert.Equal(t, 1, history.Total)
+
+	// A book whose copies have been lent stays in the trash.
+	require.NoError(t, fixture.books.DeleteBook(fixture.bookID, 0))
+	purged, err := fixture.books.PurgeTrash(0)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+}
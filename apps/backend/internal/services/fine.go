@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrLoanNotFound          = repository.ErrLoanNotFound
+	ErrAmountExceedsBalance  = repository.ErrAmountExceedsBalance
+	ErrAmountExceedsPayments = repository.ErrAmountExceedsPayments
+	// ErrFineLimitExceeded is returned when checking out to a member who
+	// owes more than the fine limit.
+	ErrFineLimitExceeded = errors.New("member owes more than the fine limit")
+)
+
+// FineRules are how overdue fines accrue and when fines stop a member
+// borrowing.
+type FineRules struct {
+	// DailyRateCents is charged for each day a copy is kept past its due
+	// date, unless a loan policy sets another rate.
+	DailyRateCents int
+	// MaxFineCents caps the overdue fine of one loan unless a loan policy
+	// sets another cap; 0 leaves it uncapped.
+	MaxFineCents int
+	// MaxBalanceCents is the balance above which a member may not borrow;
+	// 0 turns the limit off.
+	MaxBalanceCents int
+}
+
+// FineService keeps the fines ledgers. Overdue fines are charged to the
+// ledger by AccrueOverdueFines and before a settlement; balances count
+// what has run up since without charging it.
+type FineService struct {
+	fines    repository.FineRepository
+	members  repository.MemberRepository
+	calendar *CalendarService
+	rules    FineRules
+	logger   *logrus.Logger
+}
+
+func NewFineService(fines repository.FineRepository, members repository.MemberRepository, calendar *CalendarService, rules FineRules, logger *logrus.Logger) *FineService {
+	return &FineService{
+		fines:    fines,
+		members:  members,
+		calendar: calendar,
+		rules:    rules,
+		logger:   logger,
+	}
+}
+
+// ListLedger returns the ledger of a member, with a balance counting the
+// overdue fines run up to today.
+func (s *FineService) ListLedger(memberID string, params *models.LedgerListParams) (*models.LedgerListResponse, error) {
+	s.logger.WithField("member_id", memberID).Info("Fetching member ledger")
+
+	if err := s.checkMemberExists(memberID); err != nil {
+		return nil, err
+	}
+
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	response, err := s.fines.List(memberID, &query)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to query ledger")
+		return nil, err
+	}
+	balance, err := s.balance(memberID)
+	if err != nil {
+		return nil, err
+	}
+	response.BalanceCents = balance.BalanceCents
+	response.AccruingCents = balance.AccruingCents
+	return response, nil
+}
+
+// GetBalance returns what a member owes, counting the overdue fines run up
+// to today.
+func (s *FineService) GetBalance(memberID string) (*models.MemberBalance, error) {
+	s.logger.WithField("member_id", memberID).Info("Fetching member balance")
+
+	if err := s.checkMemberExists(memberID); err != nil {
+		return nil, err
+	}
+	return s.balance(memberID)
+}
+
+// Charge charges a member a lost item, processing or other fee.
+func (s *FineService) Charge(memberID string, req *models.ChargeRequest) (*models.LedgerEntry, error) {
+	category := req.Category
+	entry := &models.LedgerEntry{
+		MemberID:    memberID,
+		LoanID:      req.LoanID,
+		Kind:        models.LedgerCharge,
+		Category:    &category,
+		AmountCents: req.AmountCents,
+		Note:        trimOptional(req.Note),
+	}
+	return s.record(entry)
+}
+
+// Pay records a payment of up to what a member owes.
+func (s *FineService) Pay(memberID string, req *models.SettlementRequest) (*models.LedgerEntry, error) {
+	return s.settle(memberID, models.LedgerPayment, req)
+}
+
+// Waive forgives up to what a member owes.
+func (s *FineService) Waive(memberID string, req *models.SettlementRequest) (*models.LedgerEntry, error) {
+	return s.settle(memberID, models.LedgerWaiver, req)
+}
+
+// Refund gives back up to what a member has paid and not had refunded.
+func (s *FineService) Refund(memberID string, req *models.SettlementRequest) (*models.LedgerEntry, error) {
+	return s.settle(memberID, models.LedgerRefund, req)
+}
+
+func (s *FineService) settle(memberID, kind string, req *models.SettlementRequest) (*models.LedgerEntry, error) {
+	// Accrue first, so that a member can settle the fines run up today.
+	if err := s.accrue(memberID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.LedgerEntry{
+		MemberID:    memberID,
+		Kind:        kind,
+		AmountCents: req.AmountCents,
+		Note:        trimOptional(req.Note),
+	}
+	return s.record(entry)
+}
+
+func (s *FineService) record(entry *models.LedgerEntry) (*models.LedgerEntry, error) {
+	s.logger.WithFields(logrus.Fields{
+		"member_id":    entry.MemberID,
+		"kind":         entry.Kind,
+		"amount_cents": entry.AmountCents,
+	}).Info("Recording ledger entry")
+
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = time.Now()
+
+	if err := s.fines.Record(entry); err != nil {
+		if errors.Is(err, ErrMemberNotFound) || errors.Is(err, ErrLoanNotFound) ||
+			errors.Is(err, ErrAmountExceedsBalance) || errors.Is(err, ErrAmountExceedsPayments) {
+			s.logger.WithError(err).WithField("member_id", entry.MemberID).Warn("Failed to record ledger entry")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("member_id", entry.MemberID).Error("Failed to record ledger entry")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"entry_id":      entry.ID,
+		"balance_cents": entry.BalanceCents,
+	}).Info("Successfully recorded ledger entry")
+	return entry, nil
+}
+
+// AccrueOverdueFines charges the overdue fines all loans have run up to
+// today and returns how many charges were added.
+func (s *FineService) AccrueOverdueFines() (int64, error) {
+	now := time.Now()
+	today := s.calendar.today(now).Format(dateLayout)
+	s.logger.WithField("today", today).Info("Accruing overdue fines")
+
+	added, err := s.fines.AccrueOverdue(s.overdueRates(), today, s.calendar.location, now, "")
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to accrue overdue fines")
+		return added, err
+	}
+
+	s.logger.WithField("charges", added).Info("Successfully accrued overdue fines")
+	return added, nil
+}
+
+// checkBalance returns ErrFineLimitExceeded when a member owes too much to
+// borrow.
+func (s *FineService) checkBalance(memberID string) error {
+	balance, err := s.balance(memberID)
+	if err != nil {
+		return err
+	}
+	if balance.CheckoutBlocked {
+		s.logger.WithFields(logrus.Fields{
+			"member_id":     memberID,
+			"balance_cents": balance.BalanceCents,
+		}).Warn("Member owes more than the fine limit")
+		return ErrFineLimitExceeded
+	}
+	return nil
+}
+
+// balance counts what a member owes, adding the overdue fines run up to
+// today to the ledger without charging them.
+func (s *FineService) balance(memberID string) (*models.MemberBalance, error) {
+	charged, err := s.fines.Balance(memberID)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch balance")
+		return nil, err
+	}
+	accruing, err := s.fines.PendingOverdue(s.overdueRates(), s.calendar.today(time.Now()).Format(dateLayout), s.calendar.location, memberID)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch accruing overdue fines")
+		return nil, err
+	}
+
+	balance := charged + accruing
+	return &models.MemberBalance{
+		MemberID:        memberID,
+		BalanceCents:    balance,
+		AccruingCents:   accruing,
+		LimitCents:      s.rules.MaxBalanceCents,
+		CheckoutBlocked: s.rules.MaxBalanceCents > 0 && balance > s.rules.MaxBalanceCents,
+	}, nil
+}
+
+// accrue charges the overdue fines the loans of a member have run up to
+// today.
+func (s *FineService) accrue(memberID string) error {
+	now := time.Now()
+	if _, err := s.fines.AccrueOverdue(s.overdueRates(), s.calendar.today(now).Format(dateLayout), s.calendar.location, now, memberID); err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to accrue overdue fines")
+		return err
+	}
+	return nil
+}
+
+func (s *FineService) overdueRates() repository.OverdueRates {
+	return repository.OverdueRates{
+		DailyCents: s.rules.DailyRateCents,
+		MaxCents:   s.rules.MaxFineCents,
+	}
+}
+
+func (s *FineService) checkMemberExists(memberID string) error {
+	if _, err := s.members.GetByID(memberID); err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			s.logger.WithField("member_id", memberID).Warn("Member not found")
+			return err
+		}
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch member")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFineRules = FineRules{DailyRateCents: 25, MaxFineCents: 200, MaxBalanceCents: 150}
+
+func (l *testLibrary) newFineService() *FineService {
+	return NewFineService(repository.NewMemoryFineRepository(l.loanRepo), l.memberRepo, l.calendar, testFineRules, l.logger)
+}
+
+type fineFixture struct {
+	*testLibrary
+	circulation *CirculationService
+	fines       *FineService
+}
+
+func newFineFixture(t *testing.T, rules LoanRules) *fineFixture {
+	lib := newTestLibrary(t, rules)
+	fines := lib.newFineService()
+	return &fineFixture{
+		testLibrary: lib,
+		circulation: lib.newCirculationService(lib.newPolicyService(), fines),
+		fines:       fines,
+	}
+}
+
+func TestFineService_OverdueFines(t *testing.T) {
+	// Loans fall due ten days before they are checked out, so they are
+	// overdue straight away.
+	fixture := newFineFixture(t, LoanRules{LoanDays: -10, MaxLoans: 2, MaxRenewals: 1})
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	_, err = fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-2"})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	loan, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+
+	balance, err := fixture.fines.GetBalance(anne.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 200, balance.BalanceCents, "ten days at 25 is capped at 200")
+	assert.Equal(t, 200, balance.AccruingCents)
+	assert.True(t, balance.CheckoutBlocked)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-2", CardNumber: anne.CardNumber})
+	assert.ErrorIs(t, err, ErrFineLimitExceeded)
+
+	ledger, err := fixture.fines.ListLedger(anne.ID, &models.LedgerListParams{})
+	require.NoError(t, err)
+	assert.Empty(t, ledger.Data, "reading the balance charges nothing")
+	assert.Equal(t, 200, ledger.BalanceCents)
+
+	added, err := fixture.fines.AccrueOverdueFines()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), added)
+	added, err = fixture.fines.AccrueOverdueFines()
+	require.NoError(t, err)
+	assert.Zero(t, added, "accruing again charges nothing more")
+
+	ledger, err = fixture.fines.ListLedger(anne.ID, &models.LedgerListParams{})
+	require.NoError(t, err)
+	require.Len(t, ledger.Data, 1)
+	assert.Equal(t, models.FeeOverdue, *ledger.Data[0].Category)
+	assert.Equal(t, loan.ID, *ledger.Data[0].LoanID)
+	assert.Equal(t, 200, ledger.BalanceCents)
+	assert.Zero(t, ledger.AccruingCents)
+
+	_, err = fixture.fines.Pay(anne.ID, &models.SettlementRequest{AmountCents: 300})
+	assert.ErrorIs(t, err, ErrAmountExceedsBalance)
+	payment, err := fixture.fines.Pay(anne.ID, &models.SettlementRequest{AmountCents: 100})
+	require.NoError(t, err)
+	assert.Equal(t, 100, payment.BalanceCents)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-2", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+}
+
+func TestFineService_Settlements(t *testing.T) {
+	fixture := newFineFixture(t, LoanRules{LoanDays: 14, MaxLoans: 2, MaxRenewals: 1})
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	mary, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Mary", LastName: "Musgrove", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	loan, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+
+	_, err = fixture.fines.Charge(mary.ID, &models.ChargeRequest{Category: models.FeeLostItem, AmountCents: 2500, LoanID: &loan.ID})
+	assert.ErrorIs(t, err, ErrLoanNotFound)
+	charge, err := fixture.fines.Charge(anne.ID, &models.ChargeRequest{Category: models.FeeLostItem, AmountCents: 2500, LoanID: &loan.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2500, charge.BalanceCents)
+
+	_, err = fixture.fines.Refund(anne.ID, &models.SettlementRequest{AmountCents: 100})
+	assert.ErrorIs(t, err, ErrAmountExceedsPayments)
+
+	_, err = fixture.fines.Pay(anne.ID, &models.SettlementRequest{AmountCents: 2500})
+	require.NoError(t, err)
+	_, err = fixture.fines.Waive(anne.ID, &models.SettlementRequest{AmountCents: 1})
+	assert.ErrorIs(t, err, ErrAmountExceedsBalance)
+	refund, err := fixture.fines.Refund(anne.ID, &models.SettlementRequest{AmountCents: 2500})
+	require.NoError(t, err)
+	assert.Equal(t, 2500, refund.BalanceCents)
+	waiver, err := fixture.fines.Waive(anne.ID, &models.SettlementRequest{AmountCents: 2500})
+	require.NoError(t, err)
+	assert.Equal(t, 0, waiver.BalanceCents)
+
+	_, err = fixture.fines.GetBalance("3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e")
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+}
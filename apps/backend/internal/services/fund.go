@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrFundNotFound      = repository.ErrFundNotFound
+	ErrDuplicateFundCode = repository.ErrDuplicateFundCode
+	ErrFundInUse         = repository.ErrFundInUse
+)
+
+type FundService struct {
+	repo   repository.FundRepository
+	logger *logrus.Logger
+}
+
+func NewFundService(repo repository.FundRepository, logger *logrus.Logger) *FundService {
+	return &FundService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ListFunds reports the budget, commitments and spending of the funds of a
+// fiscal year, or of every fund, with their sums.
+func (s *FundService) ListFunds(params *models.FundListParams) (*models.FundListResponse, error) {
+	s.logger.WithField("fiscal_year", params.FiscalYear).Info("Fetching funds")
+
+	funds, err := s.repo.List(params.FiscalYear)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query funds")
+		return nil, err
+	}
+
+	response := &models.FundListResponse{Data: funds}
+	for _, fund := range funds {
+		response.BudgetCents += fund.BudgetCents
+		response.EncumberedCents += fund.EncumberedCents
+		response.SpentCents += fund.SpentCents
+		response.AvailableCents += fund.AvailableCents
+	}
+
+	s.logger.WithField("count", len(funds)).Info("Successfully fetched funds")
+	return response, nil
+}
+
+func (s *FundService) GetFundByID(id string) (*models.Fund, error) {
+	s.logger.WithField("fund_id", id).Info("Fetching fund by ID")
+
+	fund, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrFundNotFound) {
+		s.logger.WithField("fund_id", id).Warn("Fund not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("fund_id", id).Error("Failed to fetch fund")
+		return nil, err
+	}
+
+	return fund, nil
+}
+
+func (s *FundService) CreateFund(req *models.CreateFundRequest) (*models.Fund, error) {
+	s.logger.WithFields(logrus.Fields{
+		"code":        req.Code,
+		"fiscal_year": req.FiscalYear,
+	}).Info("Creating new fund")
+
+	now := time.Now()
+	fund := &models.Fund{
+		ID:             uuid.New().String(),
+		Code:           fundCode(req.Code),
+		Name:           strings.TrimSpace(req.Name),
+		FiscalYear:     req.FiscalYear,
+		BudgetCents:    req.BudgetCents,
+		AvailableCents: req.BudgetCents,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.repo.Create(fund); err != nil {
+		if errors.Is(err, ErrDuplicateFundCode) {
+			s.logger.WithError(err).WithField("code", fund.Code).Warn("Failed to create fund")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create fund")
+		return nil, err
+	}
+
+	s.logger.WithField("fund_id", fund.ID).Info("Successfully created fund")
+	return fund, nil
+}
+
+func (s *FundService) UpdateFund(id string, req *models.UpdateFundRequest) (*models.Fund, error) {
+	s.logger.WithField("fund_id", id).Info("Updating fund")
+
+	err := s.repo.Update(&models.Fund{
+		ID:          id,
+		Code:        fundCode(req.Code),
+		Name:        strings.TrimSpace(req.Name),
+		FiscalYear:  req.FiscalYear,
+		BudgetCents: req.BudgetCents,
+		UpdatedAt:   time.Now(),
+	})
+	if errors.Is(err, ErrFundNotFound) || errors.Is(err, ErrDuplicateFundCode) {
+		s.logger.WithError(err).WithField("fund_id", id).Warn("Failed to update fund")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("fund_id", id).Error("Failed to update fund")
+		return nil, err
+	}
+
+	s.logger.WithField("fund_id", id).Info("Successfully updated fund")
+	return s.GetFundByID(id)
+}
+
+// DeleteFund removes a fund that no purchase order line is paid from.
+func (s *FundService) DeleteFund(id string) error {
+	s.logger.WithField("fund_id", id).Info("Deleting fund")
+
+	err := s.repo.Delete(id)
+	if errors.Is(err, ErrFundNotFound) || errors.Is(err, ErrFundInUse) {
+		s.logger.WithError(err).WithField("fund_id", id).Warn("Failed to delete fund")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("fund_id", id).Error("Failed to delete fund")
+		return err
+	}
+
+	s.logger.WithField("fund_id", id).Info("Successfully deleted fund")
+	return nil
+}
+
+func fundCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrGenreNotFound  = repository.ErrGenreNotFound
+	ErrDuplicateGenre = repository.ErrDuplicateGenre
+	ErrGenreInUse     = repository.ErrGenreInUse
+	ErrGenreCycle     = repository.ErrGenreCycle
+	ErrUnknownGenre   = repository.ErrUnknownGenre
+)
+
+type GenreService struct {
+	repo   repository.GenreRepository
+	logger *logrus.Logger
+}
+
+func NewGenreService(repo repository.GenreRepository, logger *logrus.Logger) *GenreService {
+	return &GenreService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ListGenres returns the genre taxonomy as a tree, with siblings ordered by
+// name.
+func (s *GenreService) ListGenres() (*models.GenreTreeResponse, error) {
+	s.logger.Info("Fetching genres")
+
+	genres, err := s.repo.List()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query genres")
+		return nil, err
+	}
+
+	s.logger.WithField("count", len(genres)).Info("Successfully fetched genres")
+	return &models.GenreTreeResponse{Data: genreTree(genres, nil)}, nil
+}
+
+func (s *GenreService) GetGenreByID(id string) (*models.Genre, error) {
+	s.logger.WithField("genre_id", id).Info("Fetching genre by ID")
+
+	genre, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrGenreNotFound) {
+		s.logger.WithField("genre_id", id).Warn("Genre not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("genre_id", id).Error("Failed to fetch genre")
+		return nil, err
+	}
+
+	return genre, nil
+}
+
+func (s *GenreService) CreateGenre(req *models.CreateGenreRequest) (*models.Genre, error) {
+	s.logger.WithField("name", req.Name).Info("Creating new genre")
+
+	now := time.Now()
+	genre := &models.Genre{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		ParentID:  req.ParentID,
+		Aliases:   trimGenreAliases(req.Aliases),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(genre); err != nil {
+		if errors.Is(err, ErrDuplicateGenre) || errors.Is(err, ErrUnknownGenre) {
+			s.logger.WithError(err).WithField("name", genre.Name).Warn("Failed to create genre")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create genre")
+		return nil, err
+	}
+
+	s.logger.WithField("genre_id", genre.ID).Info("Successfully created genre")
+	return s.GetGenreByID(genre.ID)
+}
+
+// UpdateGenre replaces the name, parent and aliases of a genre. Renaming a
+// genre also renames it in the genre field of the books it is the primary
+// genre of.
+func (s *GenreService) UpdateGenre(id string, req *models.UpdateGenreRequest) (*models.Genre, error) {
+	s.logger.WithField("genre_id", id).Info("Updating genre")
+
+	existing, err := s.GetGenreByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &models.Genre{
+		ID:        existing.ID,
+		Name:      strings.TrimSpace(req.Name),
+		ParentID:  req.ParentID,
+		Aliases:   trimGenreAliases(req.Aliases),
+		CreatedAt: existing.CreatedAt,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.repo.Update(updated); err != nil {
+		if errors.Is(err, ErrGenreNotFound) || errors.Is(err, ErrDuplicateGenre) ||
+			errors.Is(err, ErrUnknownGenre) || errors.Is(err, ErrGenreCycle) {
+			s.logger.WithError(err).WithField("genre_id", id).Warn("Failed to update genre")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("genre_id", id).Error("Failed to update genre")
+		return nil, err
+	}
+
+	s.logger.WithField("genre_id", id).Info("Successfully updated genre")
+	return s.GetGenreByID(id)
+}
+
+// DeleteGenre removes a genre that has neither books nor subgenres.
+func (s *GenreService) DeleteGenre(id string) error {
+	s.logger.WithField("genre_id", id).Info("Deleting genre")
+
+	err := s.repo.Delete(id)
+	if errors.Is(err, ErrGenreNotFound) || errors.Is(err, ErrGenreInUse) {
+		s.logger.WithError(err).WithField("genre_id", id).Warn("Failed to delete genre")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("genre_id", id).Error("Failed to delete genre")
+		return err
+	}
+
+	s.logger.WithField("genre_id", id).Info("Successfully deleted genre")
+	return nil
+}
+
+// MergeGenre folds a genre into another one: its books, subgenres and
+// aliases move to the target, which also gets its name as an alias, and the
+// genre is deleted. It returns the target.
+func (s *GenreService) MergeGenre(id string, req *models.MergeGenreRequest) (*models.Genre, error) {
+	s.logger.WithFields(logrus.Fields{
+		"genre_id": id,
+		"into_id":  req.IntoID,
+	}).Info("Merging genre")
+
+	err := s.repo.Merge(id, req.IntoID, time.Now())
+	if errors.Is(err, ErrGenreNotFound) || errors.Is(err, ErrUnknownGenre) || errors.Is(err, ErrGenreCycle) {
+		s.logger.WithError(err).WithField("genre_id", id).Warn("Failed to merge genre")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("genre_id", id).Error("Failed to merge genre")
+		return nil, err
+	}
+
+	s.logger.WithField("genre_id", id).Info("Successfully merged genre")
+	return s.GetGenreByID(req.IntoID)
+}
+
+// genreTree nests the genres under parentID, keeping the order of genres.
+func genreTree(genres []models.Genre, parentID *string) []models.GenreNode {
+	nodes := []models.GenreNode{}
+	for _, genre := range genres {
+		if equalStringPtr(genre.ParentID, parentID) {
+			id := genre.ID
+			nodes = append(nodes, models.GenreNode{Genre: genre, Children: genreTree(genres, &id)})
+		}
+	}
+	return nodes
+}
+
+func trimGenreAliases(aliases []string) []string {
+	trimmed := []string{}
+	for _, alias := range aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			trimmed = append(trimmed, alias)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"io"
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGenreServices() (*BookService, *GenreService) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	books := repository.NewMemoryBookRepository()
+	genres := repository.NewMemoryGenreRepository(books)
+	return NewBookService(books, repository.NewMemoryAuthorRepository(books), genres, repository.NewMemoryItemRepository(books), logger), NewGenreService(genres, logger)
+}
+
+func TestGenreService_ListGenres(t *testing.T) {
+	_, service := newTestGenreServices()
+
+	fiction, err := service.CreateGenre(&models.CreateGenreRequest{Name: " Fiction "})
+	require.NoError(t, err)
+	assert.Equal(t, "Fiction", fiction.Name)
+	_, err = service.CreateGenre(&models.CreateGenreRequest{Name: "Science Fiction", ParentID: &fiction.ID, Aliases: []string{" Sci-Fi "}})
+	require.NoError(t, err)
+	_, err = service.CreateGenre(&models.CreateGenreRequest{Name: "Fantasy", ParentID: &fiction.ID})
+	require.NoError(t, err)
+	_, err = service.CreateGenre(&models.CreateGenreRequest{Name: "Biography"})
+	require.NoError(t, err)
+
+	tree, err := service.ListGenres()
+	require.NoError(t, err)
+	require.Len(t, tree.Data, 2)
+	assert.Equal(t, "Biography", tree.Data[0].Name)
+	assert.Empty(t, tree.Data[0].Children)
+	require.Len(t, tree.Data[1].Children, 2)
+	assert.Equal(t, "Fantasy", tree.Data[1].Children[0].Name)
+	assert.Equal(t, []string{"Sci-Fi"}, tree.Data[1].Children[1].Aliases)
+
+	_, err = service.CreateGenre(&models.CreateGenreRequest{Name: "SciFi"})
+	assert.ErrorIs(t, err, ErrDuplicateGenre)
+}
+
+func TestBookService_Genres(t *testing.T) {
+	bookService, genreService := newTestGenreServices()
+
+	fiction, err := genreService.CreateGenre(&models.CreateGenreRequest{Name: "Fiction"})
+	require.NoError(t, err)
+	fantasy, err := genreService.CreateGenre(&models.CreateGenreRequest{Name: "Fantasy", ParentID: &fiction.ID})
+	require.NoError(t, err)
+
+	genre := "fantasy"
+	hobbit, err := bookService.CreateBook(&models.CreateBookRequest{Title: "The Hobbit", Author: "J.R.R. Tolkien", Year: 1937, Genre: &genre})
+	require.NoError(t, err)
+	genre = "Travel Writing"
+	_, err = bookService.CreateBook(&models.CreateBookRequest{Title: "In Patagonia", Author: "Bruce Chatwin", Year: 1977, Genre: &genre})
+	require.NoError(t, err)
+
+	t.Run("genre strings link genres", func(t *testing.T) {
+		_, genres, err := bookService.GetBookGenres(hobbit.ID)
+		require.NoError(t, err)
+		require.Len(t, genres, 1)
+		assert.Equal(t, fantasy.ID, genres[0].ID)
+
+		tree, err := genreService.ListGenres()
+		require.NoError(t, err)
+		assert.Len(t, tree.Data, 2)
+	})
+
+	t.Run("filters include subgenres", func(t *testing.T) {
+		result, err := bookService.ListBooks(&models.BookListParams{Genre: "Fiction"})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Total)
+		assert.Equal(t, "The Hobbit", result.Data[0].Title)
+
+		result, err = bookService.ListBooks(&models.BookListParams{Genre: "Poetry"})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+
+		found, err := bookService.SearchBooks(&models.BookSearchParams{Query: "hobbit", Genre: "fiction"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.Total)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		req := &models.SetBookGenresRequest{GenreIDs: []string{fiction.ID, fantasy.ID}}
+		updated, genres, err := bookService.SetBookGenres(hobbit.ID, req, hobbit.Version)
+		require.NoError(t, err)
+		assert.Equal(t, "Fiction", *updated.Genre)
+		assert.Equal(t, 2, updated.Version)
+		assert.Len(t, genres, 2)
+
+		_, _, err = bookService.SetBookGenres(hobbit.ID, req, hobbit.Version)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+
+		req.GenreIDs = []string{fantasy.ID, fantasy.ID}
+		_, _, err = bookService.SetBookGenres(hobbit.ID, req, 0)
+		assert.ErrorIs(t, err, ErrInvalidBookGenres)
+
+		req.GenreIDs = []string{"3f2b0c1e-4d5a-4b6c-8d7e-9f0a1b2c3d4e"}
+		_, _, err = bookService.SetBookGenres(hobbit.ID, req, 0)
+		assert.ErrorIs(t, err, ErrUnknownGenre)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		travel, err := genreService.CreateGenre(&models.CreateGenreRequest{Name: "Travel", ParentID: &fiction.ID})
+		require.NoError(t, err)
+
+		tree, err := genreService.ListGenres()
+		require.NoError(t, err)
+		var travelWriting string
+		for _, node := range tree.Data {
+			if node.Name == "Travel Writing" {
+				travelWriting = node.ID
+			}
+		}
+		require.NotEmpty(t, travelWriting)
+
+		merged, err := genreService.MergeGenre(travelWriting, &models.MergeGenreRequest{IntoID: travel.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Travel Writing"}, merged.Aliases)
+
+		result, err := bookService.ListBooks(&models.BookListParams{Genre: "travel writing"})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Total)
+		assert.Equal(t, "Travel", *result.Data[0].Genre)
+
+		_, err = genreService.MergeGenre(fiction.ID, &models.MergeGenreRequest{IntoID: travel.ID})
+		assert.ErrorIs(t, err, ErrGenreCycle)
+		assert.ErrorIs(t, genreService.DeleteGenre(travel.ID), ErrGenreInUse)
+	})
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrHoldNotFound   = repository.ErrHoldNotFound
+	ErrDuplicateHold  = repository.ErrDuplicateHold
+	ErrCopyAvailable  = repository.ErrCopyAvailable
+	ErrNoHoldableCopy = repository.ErrNoHoldableCopy
+	ErrHoldNotWaiting = repository.ErrHoldNotWaiting
+	ErrHoldClosed     = repository.ErrHoldClosed
+	// ErrSuspensionEnded is returned when suspending a hold until a day
+	// that has passed.
+	ErrSuspensionEnded = errors.New("suspension must end today or later")
+)
+
+type HoldService struct {
+	holds    repository.HoldRepository
+	books    repository.BookRepository
+	members  repository.MemberRepository
+	policies *PolicyService
+	calendar *CalendarService
+	branches repository.BranchRepository
+	rules    LoanRules
+	logger   *logrus.Logger
+}
+
+func NewHoldService(holds repository.HoldRepository, books repository.BookRepository, members repository.MemberRepository, policies *PolicyService, calendar *CalendarService, branches repository.BranchRepository, rules LoanRules, logger *logrus.Logger) *HoldService {
+	return &HoldService{
+		holds:    holds,
+		books:    books,
+		members:  members,
+		policies: policies,
+		calendar: calendar,
+		branches: branches,
+		rules:    rules,
+		logger:   logger,
+	}
+}
+
+// PlaceHold queues a member for a book whose copies are all out. The member
+// must be allowed to borrow, and the loan policy for them and the book at
+// the pickup branch must allow holds. Copies trapped for a hold with a pickup branch are shipped
+// there.
+func (s *HoldService) PlaceHold(bookID string, req *models.PlaceHoldRequest) (*models.Hold, error) {
+	s.logger.WithFields(logrus.Fields{
+		"book_id":   bookID,
+		"member_id": req.MemberID,
+	}).Info("Placing hold")
+
+	member, err := s.members.GetByID(req.MemberID)
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("member_id", req.MemberID).Warn("Member not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", req.MemberID).Error("Failed to fetch member")
+		return nil, err
+	}
+
+	if err := checkBranch(s.branches, req.PickupBranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := checkMember(s.logger, member, s.calendar.today(now)); err != nil {
+		return nil, err
+	}
+	terms, err := s.policies.loanTerms(member.MembershipType, bookID, req.PickupBranchID)
+	if err != nil {
+		return nil, err
+	}
+	if !terms.HoldsAllowed {
+		s.logger.WithFields(logrus.Fields{
+			"book_id":   bookID,
+			"member_id": member.ID,
+		}).Warn("Loan policy does not allow holds")
+		return nil, ErrHoldsNotAllowed
+	}
+
+	level := models.HoldLevelTitle
+	if req.ItemID != nil {
+		level = models.HoldLevelItem
+	}
+
+	hold := &models.Hold{
+		ID:             uuid.New().String(),
+		BookID:         bookID,
+		MemberID:       member.ID,
+		ItemID:         req.ItemID,
+		Level:          level,
+		Status:         models.HoldWaiting,
+		PickupBranchID: req.PickupBranchID,
+		PlacedAt:       now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.holds.Place(hold); err != nil {
+		if errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrItemNotFound) || errors.Is(err, ErrMemberNotFound) ||
+			errors.Is(err, ErrDuplicateHold) || errors.Is(err, ErrCopyAvailable) || errors.Is(err, ErrNoHoldableCopy) ||
+			errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("book_id", bookID).Warn("Failed to place hold")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to place hold")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"hold_id":  hold.ID,
+		"position": hold.Position,
+	}).Info("Successfully placed hold")
+	return hold, nil
+}
+
+func (s *HoldService) GetHold(id string) (*models.Hold, error) {
+	s.logger.WithField("hold_id", id).Info("Fetching hold by ID")
+
+	hold, err := s.holds.GetByID(id)
+	if errors.Is(err, ErrHoldNotFound) {
+		s.logger.WithField("hold_id", id).Warn("Hold not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("hold_id", id).Error("Failed to fetch hold")
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// ListBookHolds returns the hold shelf and queue of a live book.
+func (s *HoldService) ListBookHolds(bookID string) (*models.BookHoldsResponse, error) {
+	s.logger.WithField("book_id", bookID).Info("Fetching book holds")
+
+	if _, err := s.books.GetByID(bookID); err != nil {
+		if errors.Is(err, ErrBookNotFound) {
+			s.logger.WithField("book_id", bookID).Warn("Book not found")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to fetch book")
+		return nil, err
+	}
+
+	holds, err := s.holds.ListByBook(bookID)
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to fetch book holds")
+		return nil, err
+	}
+
+	return &models.BookHoldsResponse{BookID: bookID, Data: holds}, nil
+}
+
+func (s *HoldService) ListMemberHolds(memberID string, params *models.HoldListParams) (*models.HoldListResponse, error) {
+	s.logger.WithField("member_id", memberID).Info("Fetching member holds")
+
+	if _, err := s.members.GetByID(memberID); err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			s.logger.WithField("member_id", memberID).Warn("Member not found")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch member")
+		return nil, err
+	}
+
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	response, err := s.holds.ListByMember(memberID, &query)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to query holds")
+		return nil, err
+	}
+	return response, nil
+}
+
+// CancelHold cancels an active hold. A copy trapped for it goes to the next
+// hold in the queue.
+func (s *HoldService) CancelHold(id string) (*models.Hold, error) {
+	s.logger.WithField("hold_id", id).Info("Cancelling hold")
+
+	now := time.Now()
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := s.holds.Cancel(id, now, pickup)
+	if errors.Is(err, ErrHoldNotFound) || errors.Is(err, ErrHoldClosed) {
+		s.logger.WithError(err).WithField("hold_id", id).Warn("Failed to cancel hold")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("hold_id", id).Error("Failed to cancel hold")
+		return nil, err
+	}
+
+	s.logger.WithField("hold_id", id).Info("Successfully cancelled hold")
+	return hold, nil
+}
+
+// SuspendHold passes over a waiting hold until the end of req.Until without
+// losing its place in the queue.
+func (s *HoldService) SuspendHold(id string, req *models.SuspendHoldRequest) (*models.Hold, error) {
+	s.logger.WithFields(logrus.Fields{
+		"hold_id": id,
+		"until":   req.Until,
+	}).Info("Suspending hold")
+
+	now := time.Now()
+	if req.Until < s.calendar.today(now).Format(dateLayout) {
+		s.logger.WithField("hold_id", id).Warn("Suspension has already ended")
+		return nil, ErrSuspensionEnded
+	}
+
+	until := req.Until
+	return s.suspend(id, &until, now)
+}
+
+// ResumeHold ends the suspension of a waiting hold.
+func (s *HoldService) ResumeHold(id string) (*models.Hold, error) {
+	s.logger.WithField("hold_id", id).Info("Resuming hold")
+	return s.suspend(id, nil, time.Now())
+}
+
+func (s *HoldService) suspend(id string, until *string, now time.Time) (*models.Hold, error) {
+	hold, err := s.holds.Suspend(id, until, now)
+	if errors.Is(err, ErrHoldNotFound) || errors.Is(err, ErrHoldNotWaiting) {
+		s.logger.WithError(err).WithField("hold_id", id).Warn("Failed to suspend hold")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("hold_id", id).Error("Failed to suspend hold")
+		return nil, err
+	}
+
+	s.logger.WithField("hold_id", id).Info("Successfully updated hold suspension")
+	return hold, nil
+}
+
+// MoveHold puts a waiting hold at another place in the queue of its book.
+func (s *HoldService) MoveHold(id string, req *models.MoveHoldRequest) (*models.Hold, error) {
+	s.logger.WithFields(logrus.Fields{
+		"hold_id":  id,
+		"position": req.Position,
+	}).Info("Moving hold")
+
+	hold, err := s.holds.Move(id, req.Position, time.Now())
+	if errors.Is(err, ErrHoldNotFound) || errors.Is(err, ErrHoldNotWaiting) {
+		s.logger.WithError(err).WithField("hold_id", id).Warn("Failed to move hold")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("hold_id", id).Error("Failed to move hold")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"hold_id":  id,
+		"position": hold.Position,
+	}).Info("Successfully moved hold")
+	return hold, nil
+}
+
+// ExpirePickups expires the ready holds whose pickup date has passed, passing
+// their copies to the next hold in the queue, and returns how many expired.
+func (s *HoldService) ExpirePickups() (int64, error) {
+	now := time.Now()
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return 0, err
+	}
+	s.logger.WithField("picked_up_before", pickup.Today).Info("Expiring uncollected holds")
+
+	expired, err := s.holds.ExpirePickups(now, pickup)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to expire uncollected holds")
+		return expired, err
+	}
+
+	s.logger.WithField("expired", expired).Info("Successfully expired uncollected holds")
+	return expired, nil
+}
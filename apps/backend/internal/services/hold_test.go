@@ -0,0 +1,219 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHoldService places holds subject to the policies given.
+func (l *testLibrary) newHoldService(policies *PolicyService) *HoldService {
+	return NewHoldService(repository.NewMemoryHoldRepository(l.loanRepo), l.bookRepo, l.memberRepo, policies, l.calendar, l.branchRepo, l.rules, l.logger)
+}
+
+type holdFixture struct {
+	*testLibrary
+	circulation *CirculationService
+	holds       *HoldService
+}
+
+func newHoldFixture(t *testing.T, rules LoanRules) *holdFixture {
+	lib := newTestLibrary(t, rules)
+	policies := lib.newPolicyService()
+	return &holdFixture{
+		testLibrary: lib,
+		circulation: lib.newCirculationService(policies, lib.newFineService()),
+		holds:       lib.newHoldService(policies),
+	}
+}
+
+func TestHoldService_PlaceHold(t *testing.T) {
+	fixture := newHoldFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	first, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	mary, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Mary", LastName: "Musgrove", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: mary.ID})
+	assert.ErrorIs(t, err, ErrCopyAvailable)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+
+	hold, err := fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: mary.ID, ItemID: &first.ID})
+	require.NoError(t, err)
+	assert.Equal(t, models.HoldLevelItem, hold.Level)
+	assert.Equal(t, 1, hold.Position)
+
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: mary.ID})
+	assert.ErrorIs(t, err, ErrDuplicateHold)
+	other := uuid.New().String()
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: anne.ID, ItemID: &other})
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	_, err = fixture.holds.PlaceHold(uuid.New().String(), &models.PlaceHoldRequest{MemberID: anne.ID})
+	assert.ErrorIs(t, err, ErrBookNotFound)
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: uuid.New().String()})
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+
+	_, err = fixture.members.BlockMember(anne.ID, &models.BlockMemberRequest{Reason: "Lost cards"})
+	require.NoError(t, err)
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: anne.ID})
+	assert.ErrorIs(t, err, ErrMemberBlocked)
+
+	_, err = fixture.holds.SuspendHold(hold.ID, &models.SuspendHoldRequest{Until: time.Now().AddDate(0, 0, -1).Format(dateLayout)})
+	assert.ErrorIs(t, err, ErrSuspensionEnded)
+	assert.ErrorIs(t, fixture.members.DeleteMember(mary.ID), ErrMemberHasLoans)
+}
+
+func TestHoldService_Queue(t *testing.T) {
+	// A negative pickup period makes trapped copies overdue for pickup at
+	// once, so that ExpirePickups can be exercised.
+	fixture := newHoldFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: -1})
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+
+	var members []*models.Member
+	for _, name := range []string{"Anne", "Louisa", "Henrietta", "Charles"} {
+		member, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: name, LastName: "Musgrove", MembershipType: models.MembershipAdult})
+		require.NoError(t, err)
+		members = append(members, member)
+	}
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: members[0].CardNumber})
+	require.NoError(t, err)
+
+	var holds []*models.Hold
+	for _, member := range members[1:] {
+		hold, err := fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: member.ID})
+		require.NoError(t, err)
+		holds = append(holds, hold)
+	}
+
+	moved, err := fixture.holds.MoveHold(holds[2].ID, &models.MoveHoldRequest{Position: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Position)
+	_, err = fixture.holds.SuspendHold(holds[2].ID, &models.SuspendHoldRequest{Until: time.Now().Format(dateLayout)})
+	require.NoError(t, err)
+
+	queue, err := fixture.holds.ListBookHolds(fixture.bookID)
+	require.NoError(t, err)
+	require.Len(t, queue.Data, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{queue.Data[0].Position, queue.Data[1].Position, queue.Data[2].Position})
+	assert.Equal(t, holds[2].ID, queue.Data[0].ID)
+
+	t.Run("checkin passes over suspended holds", func(t *testing.T) {
+		checkin, err := fixture.circulation.Checkin(&models.CheckinRequest{Barcode: "P-1"})
+		require.NoError(t, err)
+		require.NotNil(t, checkin.Hold)
+		assert.Equal(t, holds[0].ID, checkin.Hold.ID)
+
+		item, err := fixture.items.GetItemByBarcode("P-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemOnHold, item.Status)
+		_, err = fixture.items.SetItemStatus(item.ID, &models.ItemStatusRequest{Status: models.ItemAvailable})
+		assert.ErrorIs(t, err, ErrInvalidItemTransition)
+	})
+
+	t.Run("expiry advances the queue", func(t *testing.T) {
+		_, err := fixture.holds.ResumeHold(holds[2].ID)
+		require.NoError(t, err)
+
+		expired, err := fixture.holds.ExpirePickups()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), expired)
+
+		first, err := fixture.holds.GetHold(holds[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldExpired, first.Status)
+		next, err := fixture.holds.GetHold(holds[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldReady, next.Status)
+	})
+
+	t.Run("cancelling a ready hold passes the copy on", func(t *testing.T) {
+		_, err := fixture.holds.CancelHold(holds[2].ID)
+		require.NoError(t, err)
+		_, err = fixture.holds.CancelHold(holds[2].ID)
+		assert.ErrorIs(t, err, ErrHoldClosed)
+
+		last, err := fixture.holds.GetHold(holds[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldReady, last.Status)
+
+		_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: members[0].CardNumber})
+		assert.ErrorIs(t, err, ErrItemOnHold)
+		_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: members[2].CardNumber})
+		require.NoError(t, err)
+
+		history, err := fixture.holds.ListMemberHolds(members[2].ID, &models.HoldListParams{Status: models.HoldFulfilled})
+		require.NoError(t, err)
+		assert.Equal(t, 1, history.Total)
+	})
+}
+
+func TestHoldService_TrapOnAvailable(t *testing.T) {
+	fixture := newHoldFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 3, HoldPickupDays: 7})
+	first, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	lost, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-3"})
+	require.NoError(t, err)
+	_, err = fixture.items.SetItemStatus(lost.ID, &models.ItemStatusRequest{Status: models.ItemLost})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	mary, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Mary", LastName: "Musgrove", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	louisa, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Louisa", LastName: "Musgrove", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+	_, err = fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+
+	maryHold, err := fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: mary.ID})
+	require.NoError(t, err)
+	louisaHold, err := fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: louisa.ID})
+	require.NoError(t, err)
+
+	t.Run("renewal is refused while others wait", func(t *testing.T) {
+		_, err := fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+		assert.ErrorIs(t, err, ErrHoldsWaiting)
+	})
+
+	t.Run("a new copy fills the first hold", func(t *testing.T) {
+		item, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-2"})
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemOnHold, item.Status)
+
+		hold, err := fixture.holds.GetHold(maryHold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldReady, hold.Status)
+		require.NotNil(t, hold.ItemID)
+		assert.Equal(t, item.ID, *hold.ItemID)
+	})
+
+	t.Run("a found copy fills the next hold", func(t *testing.T) {
+		found, err := fixture.items.SetItemStatus(lost.ID, &models.ItemStatusRequest{Status: models.ItemAvailable})
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemOnHold, found.Status)
+
+		hold, err := fixture.holds.GetHold(louisaHold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.HoldReady, hold.Status)
+	})
+
+	t.Run("renewal is allowed once the queue is served", func(t *testing.T) {
+		renewed, err := fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, renewed.ItemID)
+		assert.Equal(t, 2, renewed.Renewals)
+	})
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInventoryNotFound = repository.ErrInventoryNotFound
+	ErrInventoryClosed   = repository.ErrInventoryClosed
+	// ErrInventoryOpen is returned when updating statuses from a session
+	// that is still open.
+	ErrInventoryOpen = errors.New("inventory session is still open")
+	// ErrInvalidShelfRange is returned when opening a session whose shelf
+	// range ends before it starts.
+	ErrInvalidShelfRange = errors.New("location_from must not come after location_to")
+)
+
+type InventoryService struct {
+	inventory repository.InventoryRepository
+	items     *ItemService
+	branches  repository.BranchRepository
+	logger    *logrus.Logger
+}
+
+func NewInventoryService(inventory repository.InventoryRepository, items *ItemService, branches repository.BranchRepository, logger *logrus.Logger) *InventoryService {
+	return &InventoryService{
+		inventory: inventory,
+		items:     items,
+		branches:  branches,
+		logger:    logger,
+	}
+}
+
+// OpenInventory starts a stocktake of a branch, a range of shelves, both, or
+// the whole collection.
+func (s *InventoryService) OpenInventory(req *models.OpenInventoryRequest) (*models.InventorySession, error) {
+	s.logger.Info("Opening inventory session")
+
+	from, to := trimLocation(req.LocationFrom), trimLocation(req.LocationTo)
+	if from != nil && to != nil && *from > *to {
+		s.logger.WithFields(logrus.Fields{
+			"location_from": *from,
+			"location_to":   *to,
+		}).Warn("Invalid shelf range")
+		return nil, ErrInvalidShelfRange
+	}
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.InventorySession{
+		ID:           uuid.New().String(),
+		BranchID:     req.BranchID,
+		LocationFrom: from,
+		LocationTo:   to,
+		Status:       models.InventoryOpen,
+		Note:         req.Note,
+		OpenedAt:     now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.inventory.Open(session); err != nil {
+		if errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).Warn("Failed to open inventory session")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to open inventory session")
+		return nil, err
+	}
+
+	s.logger.WithField("session_id", session.ID).Info("Successfully opened inventory session")
+	return session, nil
+}
+
+func (s *InventoryService) GetInventory(id string) (*models.InventorySession, error) {
+	s.logger.WithField("session_id", id).Info("Fetching inventory session by ID")
+
+	session, err := s.inventory.GetByID(id)
+	if errors.Is(err, ErrInventoryNotFound) {
+		s.logger.WithField("session_id", id).Warn("Inventory session not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("session_id", id).Error("Failed to fetch inventory session")
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *InventoryService) ListInventories(params *models.InventoryListParams) (*models.InventoryListResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"status":    params.Status,
+		"branch_id": params.BranchID,
+	}).Info("Fetching inventory sessions")
+
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	response, err := s.inventory.List(&query)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query inventory sessions")
+		return nil, err
+	}
+	return response, nil
+}
+
+// ScanInventory records a batch of barcodes read at the shelf in an open
+// session. Barcodes scanned before, in this batch or an earlier one, are
+// counted as repeated.
+func (s *InventoryService) ScanInventory(id string, req *models.InventoryScanRequest) (*models.InventoryScanResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"session_id": id,
+		"count":      len(req.Barcodes),
+	}).Info("Recording inventory scans")
+
+	barcodes := make([]string, 0, len(req.Barcodes))
+	for _, barcode := range req.Barcodes {
+		if barcode = strings.TrimSpace(barcode); barcode != "" {
+			barcodes = append(barcodes, barcode)
+		}
+	}
+
+	recorded, err := s.inventory.Scan(id, barcodes, time.Now())
+	if errors.Is(err, ErrInventoryNotFound) || errors.Is(err, ErrInventoryClosed) {
+		s.logger.WithError(err).WithField("session_id", id).Warn("Failed to record inventory scans")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("session_id", id).Error("Failed to record inventory scans")
+		return nil, err
+	}
+	session, err := s.GetInventory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InventoryScanResponse{
+		Recorded: recorded,
+		Repeated: len(barcodes) - recorded,
+		Scanned:  session.Scanned,
+	}, nil
+}
+
+// CloseInventory ends the scanning of a session and returns its report.
+func (s *InventoryService) CloseInventory(id string) (*models.InventoryReport, error) {
+	s.logger.WithField("session_id", id).Info("Closing inventory session")
+
+	_, err := s.inventory.Close(id, time.Now())
+	if errors.Is(err, ErrInventoryNotFound) || errors.Is(err, ErrInventoryClosed) {
+		s.logger.WithError(err).WithField("session_id", id).Warn("Failed to close inventory session")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("session_id", id).Error("Failed to close inventory session")
+		return nil, err
+	}
+
+	s.logger.WithField("session_id", id).Info("Successfully closed inventory session")
+	return s.GetInventoryReport(id)
+}
+
+// GetInventoryReport compares what a session has scanned so far with the
+// copies it covers, as they are now.
+func (s *InventoryService) GetInventoryReport(id string) (*models.InventoryReport, error) {
+	s.logger.WithField("session_id", id).Info("Fetching inventory report")
+
+	report, err := s.inventory.Report(id)
+	if errors.Is(err, ErrInventoryNotFound) {
+		s.logger.WithField("session_id", id).Warn("Inventory session not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("session_id", id).Error("Failed to build inventory report")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"session_id":     id,
+		"missing":        len(report.Missing),
+		"wrong_location": len(report.WrongLocation),
+		"found":          len(report.Found),
+	}).Info("Successfully built inventory report")
+	return report, nil
+}
+
+// ApplyInventory updates the statuses of the copies in the report of a
+// closed session: missing copies become lost and found copies available,
+// as asked. Each copy changes status as through SetItemStatus; a copy whose
+// status has moved on since, or a found copy still on loan, is skipped.
+func (s *InventoryService) ApplyInventory(id string, req *models.ApplyInventoryRequest) (*models.ApplyInventoryResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"session_id":           id,
+		"mark_missing_lost":    req.MarkMissingLost,
+		"mark_found_available": req.MarkFoundAvailable,
+	}).Info("Applying inventory report")
+
+	report, err := s.GetInventoryReport(id)
+	if err != nil {
+		return nil, err
+	}
+	if report.Session.Status != models.InventoryClosed {
+		s.logger.WithField("session_id", id).Warn("Inventory session is still open")
+		return nil, ErrInventoryOpen
+	}
+
+	response := &models.ApplyInventoryResponse{Updated: []models.Item{}, Skipped: []models.InventorySkippedItem{}}
+	taken := report.Session.OpenedAt.Format("2006-01-02")
+	if req.MarkMissingLost {
+		note := "Not found in the inventory taken " + taken
+		if err := s.applyStatus(response, report.Missing, models.ItemLost, note); err != nil {
+			return nil, err
+		}
+	}
+	if req.MarkFoundAvailable {
+		note := "Found in the inventory taken " + taken
+		if err := s.applyStatus(response, report.Found, models.ItemAvailable, note); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"session_id": id,
+		"updated":    len(response.Updated),
+		"skipped":    len(response.Skipped),
+	}).Info("Successfully applied inventory report")
+	return response, nil
+}
+
+func (s *InventoryService) applyStatus(response *models.ApplyInventoryResponse, items []models.Item, status, note string) error {
+	for _, item := range items {
+		updated, err := s.items.SetItemStatus(item.ID, &models.ItemStatusRequest{Status: status, Note: &note})
+		if errors.Is(err, ErrInvalidItemTransition) || errors.Is(err, ErrItemOnLoan) || errors.Is(err, ErrItemNotFound) {
+			response.Skipped = append(response.Skipped, models.InventorySkippedItem{
+				ItemID:  item.ID,
+				Barcode: item.Barcode,
+				Status:  status,
+				Reason:  err.Error(),
+			})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		response.Updated = append(response.Updated, *updated)
+	}
+	return nil
+}
+
+// trimLocation trims an optional shelf location, dropping it when blank.
+func trimLocation(location *string) *string {
+	if location == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*location)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inventoryFixture struct {
+	*testLibrary
+	circulation *CirculationService
+	inventory   *InventoryService
+}
+
+func newInventoryFixture(t *testing.T, rules LoanRules) *inventoryFixture {
+	lib := newTestLibrary(t, rules)
+	return &inventoryFixture{
+		testLibrary: lib,
+		circulation: lib.newCirculationService(lib.newPolicyService(), lib.newFineService()),
+		inventory:   NewInventoryService(repository.NewMemoryInventoryRepository(lib.itemRepo, lib.branchRepo), lib.items, lib.branchRepo, lib.logger),
+	}
+}
+
+func TestInventoryService(t *testing.T) {
+	fixture := newInventoryFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	main, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "MAIN", Name: "Main Library"})
+	require.NoError(t, err)
+	shelf := "PR 4034"
+	for _, barcode := range []string{"P-1", "P-2", "P-3", "P-4"} {
+		_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: barcode, Location: &shelf, HomeBranchID: &main.ID})
+		require.NoError(t, err)
+	}
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	lost, err := fixture.items.GetItemByBarcode("P-3")
+	require.NoError(t, err)
+	_, err = fixture.items.SetItemStatus(lost.ID, &models.ItemStatusRequest{Status: models.ItemLost})
+	require.NoError(t, err)
+	_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-4", CardNumber: anne.CardNumber})
+	require.NoError(t, err)
+	borrowed, err := fixture.items.GetItemByBarcode("P-4")
+	require.NoError(t, err)
+	_, err = fixture.items.SetItemStatus(borrowed.ID, &models.ItemStatusRequest{Status: models.ItemLost})
+	require.NoError(t, err)
+
+	from, to := "PS", "PR"
+	_, err = fixture.inventory.OpenInventory(&models.OpenInventoryRequest{BranchID: &main.ID, LocationFrom: &from, LocationTo: &to})
+	assert.ErrorIs(t, err, ErrInvalidShelfRange)
+	missing := uuid.New().String()
+	_, err = fixture.inventory.OpenInventory(&models.OpenInventoryRequest{BranchID: &missing})
+	assert.ErrorIs(t, err, ErrBranchNotFound)
+
+	session, err := fixture.inventory.OpenInventory(&models.OpenInventoryRequest{BranchID: &main.ID})
+	require.NoError(t, err)
+	scanned, err := fixture.inventory.ScanInventory(session.ID, &models.InventoryScanRequest{Barcodes: []string{" P-1 ", "P-3", "P-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, scanned.Recorded)
+	assert.Equal(t, 1, scanned.Repeated)
+	scanned, err = fixture.inventory.ScanInventory(session.ID, &models.InventoryScanRequest{Barcodes: []string{"P-4"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, scanned.Scanned)
+
+	_, err = fixture.inventory.ApplyInventory(session.ID, &models.ApplyInventoryRequest{MarkMissingLost: true})
+	assert.ErrorIs(t, err, ErrInventoryOpen)
+
+	report, err := fixture.inventory.CloseInventory(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Expected)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "P-2", report.Missing[0].Barcode)
+	assert.Len(t, report.Found, 2)
+	_, err = fixture.inventory.ScanInventory(session.ID, &models.InventoryScanRequest{Barcodes: []string{"P-2"}})
+	assert.ErrorIs(t, err, ErrInventoryClosed)
+
+	applied, err := fixture.inventory.ApplyInventory(session.ID, &models.ApplyInventoryRequest{MarkMissingLost: true, MarkFoundAvailable: true})
+	require.NoError(t, err)
+	require.Len(t, applied.Updated, 2)
+	assert.Equal(t, "P-2", applied.Updated[0].Barcode)
+	assert.Equal(t, models.ItemLost, applied.Updated[0].Status)
+	assert.Equal(t, "P-3", applied.Updated[1].Barcode)
+	assert.Equal(t, models.ItemAvailable, applied.Updated[1].Status)
+	require.Len(t, applied.Skipped, 1)
+	assert.Equal(t, "P-4", applied.Skipped[0].Barcode)
+	assert.Equal(t, ErrItemOnLoan.Error(), applied.Skipped[0].Reason)
+
+	report, err = fixture.inventory.GetInventoryReport(session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+	assert.Len(t, report.Found, 1)
+}
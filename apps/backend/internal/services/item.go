@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrItemNotFound          = repository.ErrItemNotFound
+	ErrDuplicateBarcode      = repository.ErrDuplicateBarcode
+	ErrInvalidItemTransition = repository.ErrInvalidItemTransition
+	ErrItemWithdrawn         = errors.New("item is withdrawn")
+	// ErrItemOnLoan is returned when making a copy with an active loan
+	// available other than by checking it in.
+	ErrItemOnLoan = errors.New("item is on loan; check it in instead")
+)
+
+type ItemService struct {
+	repo     repository.ItemRepository
+	books    repository.BookRepository
+	loans    repository.LoanRepository
+	branches repository.BranchRepository
+	calendar *CalendarService
+	rules    LoanRules
+	logger   *logrus.Logger
+}
+
+func NewItemService(repo repository.ItemRepository, books repository.BookRepository, loans repository.LoanRepository,
+	branches repository.BranchRepository, calendar *CalendarService, rules LoanRules, logger *logrus.Logger) *ItemService {
+	return &ItemService{
+		repo:     repo,
+		books:    books,
+		loans:    loans,
+		branches: branches,
+		calendar: calendar,
+		rules:    rules,
+		logger:   logger,
+	}
+}
+
+// ListBookItems returns the copies of a live book with their availability,
+// only those currently at a branch when params.BranchID is set.
+func (s *ItemService) ListBookItems(bookID string, params *models.BookItemsParams) (*models.BookItemsResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"book_id":   bookID,
+		"branch_id": params.BranchID,
+	}).Info("Fetching book items")
+
+	if err := s.checkBook(bookID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.ListByBook(bookID, params.BranchID)
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to fetch book items")
+		return nil, err
+	}
+
+	availability, err := s.repo.Availability(bookID, params.BranchID)
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to count book items")
+		return nil, err
+	}
+
+	return &models.BookItemsResponse{BookID: bookID, Availability: *availability, Data: items}, nil
+}
+
+func (s *ItemService) GetItemByID(id string) (*models.Item, error) {
+	s.logger.WithField("item_id", id).Info("Fetching item by ID")
+
+	item, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrItemNotFound) {
+		s.logger.WithField("item_id", id).Warn("Item not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("item_id", id).Error("Failed to fetch item")
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *ItemService) GetItemByBarcode(barcode string) (*models.Item, error) {
+	s.logger.WithField("barcode", barcode).Info("Fetching item by barcode")
+
+	item, err := s.repo.GetByBarcode(strings.TrimSpace(barcode))
+	if errors.Is(err, ErrItemNotFound) {
+		s.logger.WithField("barcode", barcode).Warn("Item not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("barcode", barcode).Error("Failed to fetch item")
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// CreateItem adds an available copy of a live book, at its home branch if
+// it has one. The copy is trapped for the first waiting hold it can fill.
+func (s *ItemService) CreateItem(bookID string, req *models.CreateItemRequest) (*models.Item, error) {
+	s.logger.WithFields(logrus.Fields{
+		"book_id": bookID,
+		"barcode": req.Barcode,
+	}).Info("Creating new item")
+
+	if err := s.checkBook(bookID); err != nil {
+		return nil, err
+	}
+	if err := checkBranch(s.branches, req.HomeBranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	condition := req.Condition
+	if condition == "" {
+		condition = models.ItemConditionGood
+	}
+
+	now := time.Now()
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return nil, err
+	}
+	item := &models.Item{
+		ID:              uuid.New().String(),
+		BookID:          bookID,
+		Barcode:         strings.TrimSpace(req.Barcode),
+		Location:        req.Location,
+		HomeBranchID:    req.HomeBranchID,
+		CurrentBranchID: req.HomeBranchID,
+		AcquiredOn:      req.AcquiredOn,
+		PriceCents:      req.PriceCents,
+		Condition:       condition,
+		Status:          models.ItemAvailable,
+		StatusChangedAt: now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.repo.Create(item, pickup); err != nil {
+		if errors.Is(err, ErrDuplicateBarcode) || errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("barcode", item.Barcode).Warn("Failed to create item")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create item")
+		return nil, err
+	}
+
+	s.logger.WithField("item_id", item.ID).Info("Successfully created item")
+	return item, nil
+}
+
+// UpdateItem replaces the details of a copy that is not withdrawn. Giving a
+// copy that is not at any branch a home branch puts it there; otherwise the
+// copy stays where it is, and only moves by circulation and transfers.
+func (s *ItemService) UpdateItem(id string, req *models.UpdateItemRequest) (*models.Item, error) {
+	s.logger.WithField("item_id", id).Info("Updating item")
+
+	item, err := s.editableItem(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBranch(s.branches, req.HomeBranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	item.Barcode = strings.TrimSpace(req.Barcode)
+	item.Location = req.Location
+	item.HomeBranchID = req.HomeBranchID
+	if item.CurrentBranchID == nil {
+		item.CurrentBranchID = req.HomeBranchID
+	}
+	item.AcquiredOn = req.AcquiredOn
+	item.PriceCents = req.PriceCents
+	item.Condition = req.Condition
+	item.UpdatedAt = time.Now()
+
+	return s.saveItem(item)
+}
+
+// MoveItem shelves a copy that is not withdrawn at a new location.
+func (s *ItemService) MoveItem(id string, req *models.MoveItemRequest) (*models.Item, error) {
+	s.logger.WithFields(logrus.Fields{
+		"item_id":  id,
+		"location": req.Location,
+	}).Info("Moving item")
+
+	item, err := s.editableItem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	location := strings.TrimSpace(req.Location)
+	item.Location = &location
+	item.UpdatedAt = time.Now()
+
+	return s.saveItem(item)
+}
+
+// SetItemStatus moves a copy to another status when its current status
+// allows it; see models.CanTransitionItem. A copy with an active loan, such
+// as one reported lost by the borrower, only becomes available on checkin.
+// A copy made available is trapped for the first waiting hold it can fill.
+func (s *ItemService) SetItemStatus(id string, req *models.ItemStatusRequest) (*models.Item, error) {
+	s.logger.WithFields(logrus.Fields{
+		"item_id": id,
+		"status":  req.Status,
+	}).Info("Changing item status")
+
+	if req.Status == models.ItemAvailable {
+		_, err := s.loans.GetActiveByItem(id)
+		if err == nil {
+			s.logger.WithField("item_id", id).Warn("Item is on loan")
+			return nil, ErrItemOnLoan
+		}
+		if !errors.Is(err, repository.ErrNoActiveLoan) && !errors.Is(err, ErrItemNotFound) {
+			s.logger.WithError(err).WithField("item_id", id).Error("Failed to fetch item loan")
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return nil, err
+	}
+	item, err := s.repo.SetStatus(id, req.Status, req.Note, now, pickup)
+	if errors.Is(err, ErrItemNotFound) || errors.Is(err, ErrInvalidItemTransition) {
+		s.logger.WithError(err).WithField("item_id", id).Warn("Failed to change item status")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("item_id", id).Error("Failed to change item status")
+		return nil, err
+	}
+
+	s.logger.WithField("item_id", id).Info("Successfully changed item status")
+	return item, nil
+}
+
+// RetireItem withdraws a copy from the collection for good.
+func (s *ItemService) RetireItem(id string, req *models.RetireItemRequest) (*models.Item, error) {
+	return s.SetItemStatus(id, &models.ItemStatusRequest{Status: models.ItemWithdrawn, Note: req.Reason})
+}
+
+func (s *ItemService) checkBook(bookID string) error {
+	_, err := s.books.GetByID(bookID)
+	if errors.Is(err, ErrBookNotFound) {
+		s.logger.WithField("book_id", bookID).Warn("Book not found")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to fetch book")
+		return err
+	}
+	return nil
+}
+
+func (s *ItemService) editableItem(id string) (*models.Item, error) {
+	item, err := s.GetItemByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if item.Status == models.ItemWithdrawn {
+		s.logger.WithField("item_id", id).Warn("Item is withdrawn")
+		return nil, ErrItemWithdrawn
+	}
+	return item, nil
+}
+
+func (s *ItemService) saveItem(item *models.Item) (*models.Item, error) {
+	if err := s.repo.Update(item); err != nil {
+		if errors.Is(err, ErrItemNotFound) || errors.Is(err, ErrDuplicateBarcode) || errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("item_id", item.ID).Warn("Failed to update item")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("item_id", item.ID).Error("Failed to update item")
+		return nil, err
+	}
+
+	s.logger.WithField("item_id", item.ID).Info("Successfully updated item")
+	return item, nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestItemServices() (*BookService, *ItemService) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	books := repository.NewMemoryBookRepository()
+	items := repository.NewMemoryItemRepository(books)
+	loans := repository.NewMemoryLoanRepository(items, repository.NewMemoryMemberRepository())
+	bookService := NewBookService(books, repository.NewMemoryAuthorRepository(books), repository.NewMemoryGenreRepository(books), items, logger)
+	branches := repository.NewMemoryBranchRepository(loans)
+	calendar := NewCalendarService(repository.NewMemoryCalendarRepository(), branches, time.Local, logger)
+	return bookService, NewItemService(items, books, loans, branches, calendar, LoanRules{HoldPickupDays: 7}, logger)
+}
+
+func TestItemService(t *testing.T) {
+	bookService, service := newTestItemServices()
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Middlemarch", Author: "George Eliot", Year: 1871})
+	require.NoError(t, err)
+
+	item, err := service.CreateItem(book.ID, &models.CreateItemRequest{Barcode: " 31234000012345 "})
+	require.NoError(t, err)
+	assert.Equal(t, "31234000012345", item.Barcode)
+	assert.Equal(t, models.ItemAvailable, item.Status)
+	assert.Equal(t, models.ItemConditionGood, item.Condition)
+
+	_, err = service.CreateItem(book.ID, &models.CreateItemRequest{Barcode: "31234000012345"})
+	assert.ErrorIs(t, err, ErrDuplicateBarcode)
+	_, err = service.CreateItem("missing", &models.CreateItemRequest{Barcode: "31234000099999"})
+	assert.ErrorIs(t, err, ErrBookNotFound)
+
+	moved, err := service.MoveItem(item.ID, &models.MoveItemRequest{Location: "Stacks 3F"})
+	require.NoError(t, err)
+	assert.Equal(t, "Stacks 3F", *moved.Location)
+
+	found, err := service.GetItemByBarcode("31234000012345")
+	require.NoError(t, err)
+	assert.Equal(t, "Stacks 3F", *found.Location)
+
+	reason := "Spine broken"
+	retired, err := service.RetireItem(item.ID, &models.RetireItemRequest{Reason: &reason})
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemWithdrawn, retired.Status)
+	assert.Equal(t, &reason, retired.StatusNote)
+
+	_, err = service.MoveItem(item.ID, &models.MoveItemRequest{Location: "Stacks 4F"})
+	assert.ErrorIs(t, err, ErrItemWithdrawn)
+	_, err = service.SetItemStatus(item.ID, &models.ItemStatusRequest{Status: models.ItemAvailable})
+	assert.ErrorIs(t, err, ErrInvalidItemTransition)
+
+	_, err = service.CreateItem(book.ID, &models.CreateItemRequest{Barcode: "31234000012346"})
+	require.NoError(t, err)
+
+	withAvailability, err := bookService.GetBookWithAvailability(book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAvailability{Total: 1, Available: 1, Withdrawn: 1}, *withAvailability.Availability)
+
+	list, err := service.ListBookItems(book.ID, &models.BookItemsParams{})
+	require.NoError(t, err)
+	assert.Len(t, list.Data, 2)
+	assert.Equal(t, 1, list.Availability.Available)
+}
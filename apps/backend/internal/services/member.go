@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/pkg/cardnumber"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// dateLayout formats the calendar dates of the API, such as membership
+// expiry dates.
+const dateLayout = "2006-01-02"
+
+var (
+	ErrMemberNotFound      = repository.ErrMemberNotFound
+	ErrDuplicateCardNumber = repository.ErrDuplicateCardNumber
+	ErrInvalidCardNumber   = errors.New("invalid card number")
+	ErrMemberHasLoans      = repository.ErrMemberHasLoans
+)
+
+type MemberService struct {
+	repo   repository.MemberRepository
+	loans  repository.LoanRepository
+	logger *logrus.Logger
+}
+
+func NewMemberService(repo repository.MemberRepository, loans repository.LoanRepository, logger *logrus.Logger) *MemberService {
+	return &MemberService{
+		repo:   repo,
+		loans:  loans,
+		logger: logger,
+	}
+}
+
+func (s *MemberService) ListMembers(params *models.MemberListParams) (*models.MemberListResponse, error) {
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+	query.Today = time.Now().Format(dateLayout)
+
+	s.logger.WithFields(logrus.Fields{
+		"query":  query.Query,
+		"status": query.Status,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	}).Info("Fetching members")
+
+	response, err := s.repo.List(&query)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query members")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"count": len(response.Data),
+		"total": response.Total,
+	}).Info("Successfully fetched members")
+	return response, nil
+}
+
+func (s *MemberService) GetMemberByID(id string) (*models.Member, error) {
+	s.logger.WithField("member_id", id).Info("Fetching member by ID")
+
+	member, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("member_id", id).Warn("Member not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", id).Error("Failed to fetch member")
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// GetMemberByCardNumber looks a member up by card number, rejecting numbers
+// with a wrong check digit before they reach the repository.
+func (s *MemberService) GetMemberByCardNumber(cardNumber string) (*models.Member, error) {
+	s.logger.WithField("card_number", cardNumber).Info("Fetching member by card number")
+
+	cardNumber = strings.TrimSpace(cardNumber)
+	if !cardnumber.IsValid(cardNumber) {
+		s.logger.WithField("card_number", cardNumber).Warn("Invalid card number")
+		return nil, ErrInvalidCardNumber
+	}
+
+	member, err := s.repo.GetByCardNumber(cardNumber)
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("card_number", cardNumber).Warn("Member not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("card_number", cardNumber).Error("Failed to fetch member")
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// CreateMember registers a member under a newly generated card number. The
+// membership runs for a year unless an expiry date is given.
+func (s *MemberService) CreateMember(req *models.CreateMemberRequest) (*models.Member, error) {
+	s.logger.WithField("last_name", req.LastName).Info("Registering new member")
+
+	serial, err := s.repo.NextCardSerial()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to generate card number")
+		return nil, err
+	}
+	card, err := cardnumber.Generate(serial)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to generate card number")
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresOn := now.AddDate(1, 0, 0).Format(dateLayout)
+	if req.ExpiresOn != nil {
+		expiresOn = *req.ExpiresOn
+	}
+
+	member := &models.Member{
+		ID:             uuid.New().String(),
+		CardNumber:     card,
+		FirstName:      strings.TrimSpace(req.FirstName),
+		LastName:       strings.TrimSpace(req.LastName),
+		Email:          trimOptional(req.Email),
+		Phone:          trimOptional(req.Phone),
+		Address:        trimOptional(req.Address),
+		MembershipType: req.MembershipType,
+		ExpiresOn:      expiresOn,
+		Notes:          req.Notes,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := s.repo.Create(member); err != nil {
+		s.logger.WithError(err).Error("Failed to create member")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"member_id":   member.ID,
+		"card_number": member.CardNumber,
+	}).Info("Successfully registered member")
+	return member, nil
+}
+
+func (s *MemberService) UpdateMember(id string, req *models.UpdateMemberRequest) (*models.Member, error) {
+	s.logger.WithField("member_id", id).Info("Updating member")
+
+	member, err := s.GetMemberByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	member.FirstName = strings.TrimSpace(req.FirstName)
+	member.LastName = strings.TrimSpace(req.LastName)
+	member.Email = trimOptional(req.Email)
+	member.Phone = trimOptional(req.Phone)
+	member.Address = trimOptional(req.Address)
+	member.MembershipType = req.MembershipType
+	member.ExpiresOn = req.ExpiresOn
+	member.Notes = req.Notes
+	member.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(member); err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			s.logger.WithField("member_id", id).Warn("Member not found")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("member_id", id).Error("Failed to update member")
+		return nil, err
+	}
+
+	s.logger.WithField("member_id", id).Info("Successfully updated member")
+	return member, nil
+}
+
+func (s *MemberService) BlockMember(id string, req *models.BlockMemberRequest) (*models.Member, error) {
+	s.logger.WithField("member_id", id).Info("Blocking member")
+
+	reason := strings.TrimSpace(req.Reason)
+	return s.setBlocked(id, true, &reason)
+}
+
+func (s *MemberService) UnblockMember(id string) (*models.Member, error) {
+	s.logger.WithField("member_id", id).Info("Unblocking member")
+
+	return s.setBlocked(id, false, nil)
+}
+
+func (s *MemberService) setBlocked(id string, blocked bool, reason *string) (*models.Member, error) {
+	member, err := s.repo.SetBlocked(id, blocked, reason, time.Now())
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("member_id", id).Warn("Member not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", id).Error("Failed to update member")
+		return nil, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"member_id": id,
+		"blocked":   blocked,
+	}).Info("Successfully updated member")
+	return member, nil
+}
+
+// DeleteMember deletes a member without circulation history. Members with
+// history can be blocked instead.
+func (s *MemberService) DeleteMember(id string) error {
+	s.logger.WithField("member_id", id).Info("Deleting member")
+
+	hasLoans, err := s.loans.HasLoans(id)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", id).Error("Failed to check member loans")
+		return err
+	}
+	if hasLoans {
+		s.logger.WithField("member_id", id).Warn("Member has circulation history")
+		return ErrMemberHasLoans
+	}
+
+	err = s.repo.Delete(id)
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("member_id", id).Warn("Member not found")
+		return err
+	}
+	if errors.Is(err, ErrMemberHasLoans) {
+		s.logger.WithField("member_id", id).Warn("Member has circulation history")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", id).Error("Failed to delete member")
+		return err
+	}
+
+	s.logger.WithField("member_id", id).Info("Successfully deleted member")
+	return nil
+}
+
+// trimOptional trims an optional string, dropping it when nothing is left.
+func trimOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemberService() *MemberService {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	members := repository.NewMemoryMemberRepository()
+	loans := repository.NewMemoryLoanRepository(repository.NewMemoryItemRepository(repository.NewMemoryBookRepository()), members)
+	return NewMemberService(members, loans, logger)
+}
+
+func TestMemberService_CreateMember(t *testing.T) {
+	service := newTestMemberService()
+
+	email := " ada@example.com "
+	blank := "  "
+	ada, err := service.CreateMember(&models.CreateMemberRequest{FirstName: " Ada ", LastName: "Lovelace", Email: &email, Phone: &blank, MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	assert.Equal(t, "00000000000018", ada.CardNumber)
+	assert.Equal(t, "Ada", ada.FirstName)
+	assert.Equal(t, "ada@example.com", *ada.Email)
+	assert.Nil(t, ada.Phone)
+	assert.Equal(t, time.Now().AddDate(1, 0, 0).Format(dateLayout), ada.ExpiresOn)
+
+	expiresOn := "2030-12-31"
+	charles, err := service.CreateMember(&models.CreateMemberRequest{FirstName: "Charles", LastName: "Babbage", MembershipType: models.MembershipSenior, ExpiresOn: &expiresOn})
+	require.NoError(t, err)
+	assert.NotEqual(t, ada.CardNumber, charles.CardNumber)
+	assert.Equal(t, expiresOn, charles.ExpiresOn)
+
+	found, err := service.GetMemberByCardNumber(" " + charles.CardNumber + " ")
+	require.NoError(t, err)
+	assert.Equal(t, charles.ID, found.ID)
+
+	_, err = service.GetMemberByCardNumber("00000000000019")
+	assert.ErrorIs(t, err, ErrInvalidCardNumber)
+	_, err = service.GetMemberByCardNumber("00000000000042")
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+}
+
+func TestMemberService_Block(t *testing.T) {
+	service := newTestMemberService()
+	member, err := service.CreateMember(&models.CreateMemberRequest{FirstName: "Mary", LastName: "Somerville", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	blocked, err := service.BlockMember(member.ID, &models.BlockMemberRequest{Reason: " Damaged three books "})
+	require.NoError(t, err)
+	assert.True(t, blocked.Blocked)
+	assert.Equal(t, "Damaged three books", *blocked.BlockedReason)
+
+	list, err := service.ListMembers(&models.MemberListParams{Status: models.MemberActive})
+	require.NoError(t, err)
+	assert.Equal(t, 0, list.Total)
+
+	unblocked, err := service.UnblockMember(member.ID)
+	require.NoError(t, err)
+	assert.False(t, unblocked.Blocked)
+	assert.Nil(t, unblocked.BlockedReason)
+
+	list, err = service.ListMembers(&models.MemberListParams{Status: models.MemberActive})
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.Total)
+
+	_, err = service.BlockMember("missing", &models.BlockMemberRequest{Reason: "Unknown"})
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+}
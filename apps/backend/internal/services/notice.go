@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/pkg/notify"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// noticeBatchSize is how many notices a dispatcher claims at a time.
+	noticeBatchSize = 50
+	// noticeSendTimeout bounds one attempt at sending a notice.
+	noticeSendTimeout = 30 * time.Second
+	// noticeLease is how long claimed notices are held back from other
+	// dispatchers, should this one stop before recording the outcome.
+	noticeLease = 5 * time.Minute
+	// maxNoticeBackoff caps the wait between attempts.
+	maxNoticeBackoff = 24 * time.Hour
+)
+
+// defaultNoticeChannels are the channels of members who have not chosen.
+var defaultNoticeChannels = []string{models.ChannelEmail}
+
+//go:embed templates/*.tmpl
+var noticeTemplateFS embed.FS
+
+// noticeTemplates render the subject and body of each kind of notice.
+var noticeTemplates = map[string]*template.Template{
+	models.NoticeOverdue:   template.Must(template.ParseFS(noticeTemplateFS, "templates/overdue.tmpl")),
+	models.NoticeHoldReady: template.Must(template.ParseFS(noticeTemplateFS, "templates/hold_ready.tmpl")),
+}
+
+// noticeData is what a notice template is rendered with.
+type noticeData struct {
+	FirstName  string
+	LastName   string
+	CardNumber string
+	Title      string
+	Barcode    string
+	// Date is the due date of an overdue loan or the pickup date of a ready
+	// hold.
+	Date string
+}
+
+// NoticeRules are how the outbox retries notices that could not be sent.
+type NoticeRules struct {
+	// MaxAttempts is how many times a notice is tried before it fails.
+	MaxAttempts int
+	// RetryBackoff is the wait after the first failed attempt. It doubles
+	// with each failure after that, up to a day.
+	RetryBackoff time.Duration
+}
+
+type NoticeService struct {
+	notices  repository.NoticeRepository
+	members  repository.MemberRepository
+	calendar *CalendarService
+	// channels are the notifiers by channel name, such as
+	// models.ChannelEmail.
+	channels map[string]notify.Notifier
+	rules    NoticeRules
+	logger   *logrus.Logger
+}
+
+func NewNoticeService(notices repository.NoticeRepository, members repository.MemberRepository, calendar *CalendarService, channels map[string]notify.Notifier, rules NoticeRules, logger *logrus.Logger) *NoticeService {
+	return &NoticeService{
+		notices:  notices,
+		members:  members,
+		calendar: calendar,
+		channels: channels,
+		rules:    rules,
+		logger:   logger,
+	}
+}
+
+func (s *NoticeService) ListNotices(memberID string, params *models.NoticeListParams) (*models.NoticeListResponse, error) {
+	s.logger.WithField("member_id", memberID).Info("Fetching member notices")
+
+	if err := s.checkMemberExists(memberID); err != nil {
+		return nil, err
+	}
+
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	response, err := s.notices.List(memberID, &query)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to query notices")
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetPreferences returns the channels a member is sent notices over, which
+// are email for both kinds until the member chooses.
+func (s *NoticeService) GetPreferences(memberID string) (*models.NoticePreferences, error) {
+	s.logger.WithField("member_id", memberID).Info("Fetching notice preferences")
+
+	if err := s.checkMemberExists(memberID); err != nil {
+		return nil, err
+	}
+	return s.preferences(memberID)
+}
+
+func (s *NoticeService) SetPreferences(memberID string, req *models.NoticePreferencesRequest) (*models.NoticePreferences, error) {
+	s.logger.WithField("member_id", memberID).Info("Setting notice preferences")
+
+	now := time.Now()
+	preferences := &models.NoticePreferences{
+		MemberID:  memberID,
+		Overdue:   req.Overdue,
+		HoldReady: req.HoldReady,
+		UpdatedAt: &now,
+	}
+
+	err := s.notices.SetPreferences(preferences)
+	if errors.Is(err, ErrMemberNotFound) {
+		s.logger.WithField("member_id", memberID).Warn("Member not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to set notice preferences")
+		return nil, err
+	}
+
+	s.logger.WithField("member_id", memberID).Info("Successfully set notice preferences")
+	return preferences, nil
+}
+
+// SendNotices queues notices for the loans that have fallen overdue and the
+// holds that have become ready, then sends the notices that are due. It
+// returns how many notices were queued and how many sent.
+func (s *NoticeService) SendNotices() (int64, int64, error) {
+	queued, err := s.QueueNotices()
+	if err != nil {
+		return queued, 0, err
+	}
+	sent, err := s.DeliverNotices()
+	return queued, sent, err
+}
+
+// QueueNotices adds a notice to the outbox for each channel a member
+// prefers, for each loan overdue today and each ready hold not noticed yet,
+// and returns how many were added. A notice that cannot be sent, such as an
+// email to a member without an address, is recorded as skipped.
+func (s *NoticeService) QueueNotices() (int64, error) {
+	now := time.Now()
+	today := s.calendar.today(now).Format(dateLayout)
+	s.logger.WithField("today", today).Info("Queueing notices")
+
+	events, err := s.notices.Events(today)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to find notice events")
+		return 0, err
+	}
+
+	preferences := make(map[string]*models.NoticePreferences)
+	notices := []models.Notice{}
+	for i := range events {
+		event := &events[i]
+		memberPreferences, ok := preferences[event.Member.ID]
+		if !ok {
+			memberPreferences, err = s.preferences(event.Member.ID)
+			if err != nil {
+				return 0, err
+			}
+			preferences[event.Member.ID] = memberPreferences
+		}
+
+		channels := memberPreferences.Overdue
+		if event.Kind == models.NoticeHoldReady {
+			channels = memberPreferences.HoldReady
+		}
+		eventNotices, err := s.eventNotices(event, channels, now)
+		if err != nil {
+			s.logger.WithError(err).WithField("event", event.Key).Error("Failed to render notice")
+			return 0, err
+		}
+		notices = append(notices, eventNotices...)
+	}
+
+	added, err := s.notices.Enqueue(notices)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to queue notices")
+		return 0, err
+	}
+
+	s.logger.WithField("notices", added).Info("Successfully queued notices")
+	return added, nil
+}
+
+// DeliverNotices tries each pending notice that is due and returns how
+// many were sent. A notice that fails is tried again after a backoff until
+// it runs out of attempts.
+func (s *NoticeService) DeliverNotices() (int64, error) {
+	s.logger.Info("Delivering notices")
+
+	var sent, failed int64
+	for {
+		now := time.Now()
+		claimed, err := s.notices.Claim(now, now.Add(noticeLease), noticeBatchSize)
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to claim notices")
+			return sent, err
+		}
+
+		for i := range claimed {
+			notice := &claimed[i]
+			s.deliver(notice)
+			if err := s.notices.Update(notice); err != nil {
+				s.logger.WithError(err).WithField("notice_id", notice.ID).Error("Failed to record notice delivery")
+				return sent, err
+			}
+			if notice.Status == models.NoticeSent {
+				sent++
+			} else {
+				failed++
+			}
+		}
+		if len(claimed) < noticeBatchSize {
+			break
+		}
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"sent":   sent,
+		"failed": failed,
+	}).Info("Successfully delivered notices")
+	return sent, nil
+}
+
+// deliver makes one attempt at sending a pending notice and records the
+// outcome on it.
+func (s *NoticeService) deliver(notice *models.Notice) {
+	err := fmt.Errorf("channel %s is not configured", *notice.Channel)
+	if notifier, ok := s.channels[*notice.Channel]; ok {
+		ctx, cancel := context.WithTimeout(context.Background(), noticeSendTimeout)
+		err = notifier.Send(ctx, notify.Message{To: *notice.Recipient, Subject: notice.Subject, Body: notice.Body})
+		cancel()
+	}
+
+	now := time.Now()
+	notice.Attempts++
+	if err == nil {
+		notice.Status = models.NoticeSent
+		notice.NextAttemptAt = nil
+		notice.LastError = nil
+		notice.SentAt = &now
+		return
+	}
+
+	message := err.Error()
+	notice.LastError = &message
+	logger := s.logger.WithError(err).WithFields(logrus.Fields{
+		"notice_id": notice.ID,
+		"attempts":  notice.Attempts,
+	})
+	if notice.Attempts >= s.rules.MaxAttempts {
+		notice.Status = models.NoticeFailed
+		notice.NextAttemptAt = nil
+		logger.Error("Notice failed")
+		return
+	}
+	next := now.Add(s.backoff(notice.Attempts))
+	notice.NextAttemptAt = &next
+	logger.Warn("Failed to send notice, will retry")
+}
+
+// backoff is the wait after a notice has failed attempts times.
+func (s *NoticeService) backoff(attempts int) time.Duration {
+	wait := s.rules.RetryBackoff
+	for i := 1; i < attempts && wait < maxNoticeBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxNoticeBackoff {
+		wait = maxNoticeBackoff
+	}
+	return wait
+}
+
+// eventNotices renders the notices for an event, one for each channel.
+func (s *NoticeService) eventNotices(event *repository.NoticeEvent, channels []string, now time.Time) ([]models.Notice, error) {
+	subject, body, err := renderNotice(event)
+	if err != nil {
+		return nil, err
+	}
+
+	notice := func(channel *string) models.Notice {
+		return models.Notice{
+			ID:        uuid.New().String(),
+			MemberID:  event.Member.ID,
+			Kind:      event.Kind,
+			Key:       event.Key,
+			Channel:   channel,
+			LoanID:    event.LoanID,
+			HoldID:    event.HoldID,
+			Subject:   subject,
+			Body:      body,
+			CreatedAt: now,
+		}
+	}
+
+	if len(channels) == 0 {
+		skipped := notice(nil)
+		skipNotice(&skipped, "member has turned this kind of notice off")
+		return []models.Notice{skipped}, nil
+	}
+
+	notices := make([]models.Notice, 0, len(channels))
+	for _, channel := range channels {
+		queued := notice(&channel)
+		recipient, reason := s.recipient(channel, &event.Member)
+		if reason != "" {
+			skipNotice(&queued, reason)
+		} else {
+			queued.Recipient = &recipient
+			queued.Status = models.NoticePending
+			queued.NextAttemptAt = &now
+		}
+		notices = append(notices, queued)
+	}
+	return notices, nil
+}
+
+// recipient is where a member is sent notices on a channel, or why they
+// cannot be.
+func (s *NoticeService) recipient(channel string, member *models.Member) (string, string) {
+	if _, ok := s.channels[channel]; !ok {
+		return "", fmt.Sprintf("channel %s is not configured", channel)
+	}
+	email := ""
+	if member.Email != nil {
+		email = strings.TrimSpace(*member.Email)
+	}
+
+	switch {
+	case email != "":
+		return email, ""
+	case channel == models.ChannelEmail:
+		return "", "member has no email address"
+	default:
+		return member.CardNumber, ""
+	}
+}
+
+// preferences returns the notice preferences of a member, or the defaults.
+func (s *NoticeService) preferences(memberID string) (*models.NoticePreferences, error) {
+	preferences, err := s.notices.Preferences(memberID)
+	if err != nil {
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch notice preferences")
+		return nil, err
+	}
+	if preferences == nil {
+		preferences = &models.NoticePreferences{
+			MemberID:  memberID,
+			Overdue:   defaultNoticeChannels,
+			HoldReady: defaultNoticeChannels,
+		}
+	}
+	return preferences, nil
+}
+
+func (s *NoticeService) checkMemberExists(memberID string) error {
+	if _, err := s.members.GetByID(memberID); err != nil {
+		if errors.Is(err, ErrMemberNotFound) {
+			s.logger.WithField("member_id", memberID).Warn("Member not found")
+			return err
+		}
+		s.logger.WithError(err).WithField("member_id", memberID).Error("Failed to fetch member")
+		return err
+	}
+	return nil
+}
+
+// renderNotice renders the subject and body of the notice for an event.
+func renderNotice(event *repository.NoticeEvent) (string, string, error) {
+	tmpl, ok := noticeTemplates[event.Kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for %s notices", event.Kind)
+	}
+	data := noticeData{
+		FirstName:  event.Member.FirstName,
+		LastName:   event.Member.LastName,
+		CardNumber: event.Member.CardNumber,
+		Title:      event.Title,
+		Barcode:    event.Barcode,
+		Date:       event.Date,
+	}
+
+	var subject, body strings.Builder
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
+
+// skipNotice marks a notice as not to be sent, and why.
+func skipNotice(notice *models.Notice, reason string) {
+	notice.Status = models.NoticeSkipped
+	notice.LastError = &reason
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+	"library-management-backend/pkg/notify"
+	"library-management-backend/pkg/notify/smtptest"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoticeService(t *testing.T) {
+	// Loans fall due ten days before they are checked out, so they are
+	// overdue straight away.
+	fixture := newHoldFixture(t, LoanRules{LoanDays: -10, MaxLoans: 2, MaxRenewals: 1, HoldPickupDays: 7})
+	for _, barcode := range []string{"P-1", "P-2", "P-3", "P-4"} {
+		_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: barcode})
+		require.NoError(t, err)
+	}
+	member := func(first, last string, email *string) *models.Member {
+		created, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: first, LastName: last, Email: email, MembershipType: models.MembershipAdult})
+		require.NoError(t, err)
+		return created
+	}
+	anneEmail, janeEmail, elizaEmail := "anne@example.org", "jane@example.org", "eliza@example.org"
+	anne := member("Anne", "Elliot", &anneEmail)
+	mary := member("Mary", "Musgrove", nil)
+	jane := member("Jane", "Fairfax", &janeEmail)
+	eliza := member("Elizabeth", "Elliot", &elizaEmail)
+
+	server := smtptest.NewServer()
+	defer server.Close()
+	var noticeLog bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	channels := map[string]notify.Notifier{
+		models.ChannelEmail: notify.NewSMTPNotifier(notify.SMTPConfig{Host: server.Host, Port: server.Port, From: "library@example.org"}),
+		models.ChannelLog:   notify.NewLogNotifier(&noticeLog),
+	}
+	notices := NewNoticeService(repository.NewMemoryNoticeRepository(fixture.loanRepo), fixture.memberRepo, fixture.calendar,
+		channels, NoticeRules{MaxAttempts: 2}, logger)
+
+	preferences, err := notices.GetPreferences(mary.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.ChannelEmail}, preferences.HoldReady)
+	assert.Nil(t, preferences.UpdatedAt)
+	_, err = notices.SetPreferences(jane.ID, &models.NoticePreferencesRequest{Overdue: []string{}, HoldReady: []string{models.ChannelEmail}})
+	require.NoError(t, err)
+	_, err = notices.SetPreferences(anne.ID, &models.NoticePreferencesRequest{
+		Overdue:   []string{models.ChannelEmail},
+		HoldReady: []string{models.ChannelEmail, models.ChannelLog},
+	})
+	require.NoError(t, err)
+
+	for barcode, borrower := range map[string]*models.Member{"P-1": anne, "P-2": mary, "P-3": jane, "P-4": eliza} {
+		_, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: barcode, CardNumber: borrower.CardNumber})
+		require.NoError(t, err)
+	}
+	hold, err := fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: anne.ID})
+	require.NoError(t, err)
+	_, err = fixture.circulation.Checkin(&models.CheckinRequest{Barcode: "P-4"})
+	require.NoError(t, err)
+	hold, err = fixture.holds.GetHold(hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.HoldReady, hold.Status)
+
+	queued, err := notices.QueueNotices()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), queued, "overdue for Anne, Mary and Jane and ready over two channels for Anne")
+	queued, err = notices.QueueNotices()
+	require.NoError(t, err)
+	assert.Zero(t, queued)
+
+	t.Run("skipped", func(t *testing.T) {
+		skipped, err := notices.ListNotices(mary.ID, &models.NoticeListParams{})
+		require.NoError(t, err)
+		require.Len(t, skipped.Data, 1)
+		assert.Equal(t, models.NoticeSkipped, skipped.Data[0].Status)
+		assert.Equal(t, "member has no email address", *skipped.Data[0].LastError)
+
+		skipped, err = notices.ListNotices(jane.ID, &models.NoticeListParams{})
+		require.NoError(t, err)
+		require.Len(t, skipped.Data, 1)
+		assert.Nil(t, skipped.Data[0].Channel, "Jane turned overdue notices off")
+		assert.Equal(t, "Overdue: Persuasion", skipped.Data[0].Subject)
+	})
+
+	t.Run("deliver with retries", func(t *testing.T) {
+		server.FailNext(1)
+		sent, err := notices.DeliverNotices()
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), sent)
+		assert.Len(t, server.Messages(), 1)
+		assert.Contains(t, noticeLog.String(), "To: anne@example.org\nSubject: Ready for pickup: Persuasion\n")
+		assert.Contains(t, noticeLog.String(), "Please collect it by "+*hold.PickupBy)
+
+		pending, err := notices.ListNotices(anne.ID, &models.NoticeListParams{Status: models.NoticePending})
+		require.NoError(t, err)
+		require.Len(t, pending.Data, 1)
+		assert.Equal(t, 1, pending.Data[0].Attempts)
+		assert.Contains(t, *pending.Data[0].LastError, "451")
+
+		server.FailNext(1)
+		sent, err = notices.DeliverNotices()
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		failed, err := notices.ListNotices(anne.ID, &models.NoticeListParams{Status: models.NoticeFailed})
+		require.NoError(t, err)
+		require.Len(t, failed.Data, 1, "the notice has run out of attempts")
+		assert.Nil(t, failed.Data[0].NextAttemptAt)
+
+		history, err := notices.ListNotices(anne.ID, &models.NoticeListParams{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, history.Total)
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		service := &NoticeService{rules: NoticeRules{RetryBackoff: time.Minute}}
+		assert.Equal(t, time.Minute, service.backoff(1))
+		assert.Equal(t, 4*time.Minute, service.backoff(3))
+		assert.Equal(t, 24*time.Hour, service.backoff(40))
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		_, err := notices.ListNotices(uuid.New().String(), &models.NoticeListParams{})
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+		_, err = notices.SetPreferences(uuid.New().String(), &models.NoticePreferencesRequest{Overdue: []string{}, HoldReady: []string{}})
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+	})
+}
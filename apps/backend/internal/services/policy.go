@@ -0,0 +1,369 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrPolicyNotFound  = repository.ErrPolicyNotFound
+	ErrDuplicatePolicy = repository.ErrDuplicatePolicy
+	// ErrHoldsNotAllowed is returned when placing a hold the loan policy of
+	// the member and book does not allow.
+	ErrHoldsNotAllowed = errors.New("loan policy does not allow holds on this book")
+)
+
+// policySourceDefault is the source of a term no rule sets.
+const policySourceDefault = "default"
+
+type PolicyService struct {
+	policies repository.PolicyRepository
+	genres   repository.GenreRepository
+	members  repository.MemberRepository
+	branches repository.BranchRepository
+	defaults models.LoanTerms
+	logger   *logrus.Logger
+}
+
+// NewPolicyService returns a service whose rules fall back to the loan and
+// fine rules of the configuration for the terms no rule sets.
+func NewPolicyService(policies repository.PolicyRepository, genres repository.GenreRepository, members repository.MemberRepository, branches repository.BranchRepository, loanRules LoanRules, fineRules FineRules, logger *logrus.Logger) *PolicyService {
+	return &PolicyService{
+		policies: policies,
+		genres:   genres,
+		members:  members,
+		branches: branches,
+		defaults: models.LoanTerms{
+			LoanDays:       loanRules.LoanDays,
+			MaxRenewals:    loanRules.MaxRenewals,
+			DailyFineCents: fineRules.DailyRateCents,
+			MaxFineCents:   fineRules.MaxFineCents,
+			HoldsAllowed:   true,
+		},
+		logger: logger,
+	}
+}
+
+func (s *PolicyService) ListPolicies() ([]models.LoanPolicy, error) {
+	s.logger.Info("Fetching loan policies")
+
+	policies, err := s.policies.List()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query loan policies")
+		return nil, err
+	}
+
+	s.logger.WithField("count", len(policies)).Info("Successfully fetched loan policies")
+	return policies, nil
+}
+
+func (s *PolicyService) GetPolicyByID(id string) (*models.LoanPolicy, error) {
+	s.logger.WithField("policy_id", id).Info("Fetching loan policy by ID")
+
+	policy, err := s.policies.GetByID(id)
+	if errors.Is(err, ErrPolicyNotFound) {
+		s.logger.WithField("policy_id", id).Warn("Loan policy not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("policy_id", id).Error("Failed to fetch loan policy")
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *PolicyService) CreatePolicy(req *models.LoanPolicyRequest) (*models.LoanPolicy, error) {
+	s.logger.WithField("name", req.Name).Info("Creating loan policy")
+
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	policy := policyFromRequest(req)
+	policy.ID = uuid.New().String()
+	policy.CreatedAt = now
+	policy.UpdatedAt = now
+
+	if err := s.policies.Create(policy); err != nil {
+		if errors.Is(err, ErrDuplicatePolicy) || errors.Is(err, ErrUnknownGenre) || errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("name", policy.Name).Warn("Failed to create loan policy")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create loan policy")
+		return nil, err
+	}
+
+	s.logger.WithField("policy_id", policy.ID).Info("Successfully created loan policy")
+	return policy, nil
+}
+
+// UpdatePolicy replaces every field of a rule; terms left out of the request
+// are unset and fall through to less specific rules.
+func (s *PolicyService) UpdatePolicy(id string, req *models.LoanPolicyRequest) (*models.LoanPolicy, error) {
+	s.logger.WithField("policy_id", id).Info("Updating loan policy")
+
+	if err := checkBranch(s.branches, req.BranchID, s.logger); err != nil {
+		return nil, err
+	}
+	policy := policyFromRequest(req)
+	policy.ID = id
+	policy.UpdatedAt = time.Now()
+
+	if err := s.policies.Update(policy); err != nil {
+		if errors.Is(err, ErrPolicyNotFound) || errors.Is(err, ErrDuplicatePolicy) || errors.Is(err, ErrUnknownGenre) ||
+			errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("policy_id", id).Warn("Failed to update loan policy")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("policy_id", id).Error("Failed to update loan policy")
+		return nil, err
+	}
+
+	s.logger.WithField("policy_id", id).Info("Successfully updated loan policy")
+	return policy, nil
+}
+
+func (s *PolicyService) DeletePolicy(id string) error {
+	s.logger.WithField("policy_id", id).Info("Deleting loan policy")
+
+	err := s.policies.Delete(id)
+	if errors.Is(err, ErrPolicyNotFound) {
+		s.logger.WithField("policy_id", id).Warn("Loan policy not found")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("policy_id", id).Error("Failed to delete loan policy")
+		return err
+	}
+
+	s.logger.WithField("policy_id", id).Info("Successfully deleted loan policy")
+	return nil
+}
+
+// Evaluate works out the terms a member, or any member of a membership type,
+// would borrow a book on at a branch, and which rules they come from.
+func (s *PolicyService) Evaluate(params *models.PolicyEvaluationParams) (*models.PolicyEvaluation, error) {
+	s.logger.WithFields(logrus.Fields{
+		"branch_id":       params.BranchID,
+		"member_id":       params.MemberID,
+		"membership_type": params.MembershipType,
+		"book_id":         params.BookID,
+	}).Info("Evaluating loan policies")
+
+	branchID := optionalID(params.BranchID)
+	if err := checkBranch(s.branches, branchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	membershipType := params.MembershipType
+	if params.MemberID != "" {
+		member, err := s.members.GetByID(params.MemberID)
+		if errors.Is(err, ErrMemberNotFound) {
+			s.logger.WithField("member_id", params.MemberID).Warn("Member not found")
+			return nil, err
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("member_id", params.MemberID).Error("Failed to fetch member")
+			return nil, err
+		}
+		membershipType = member.MembershipType
+	}
+
+	var bookGenres []models.Genre
+	if params.BookID != "" {
+		var err error
+		bookGenres, err = s.genres.BookGenres(params.BookID)
+		if errors.Is(err, ErrBookNotFound) {
+			s.logger.WithField("book_id", params.BookID).Warn("Book not found")
+			return nil, err
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("book_id", params.BookID).Error("Failed to fetch book genres")
+			return nil, err
+		}
+	}
+
+	return s.evaluate(branchID, membershipType, bookGenres)
+}
+
+// loanTerms returns the terms a member of a membership type borrows a book
+// on at a branch, or anywhere when branchID is nil. A book that is not live
+// only matches the rules for every book.
+func (s *PolicyService) loanTerms(membershipType, bookID string, branchID *string) (*models.LoanTerms, error) {
+	bookGenres, err := s.genres.BookGenres(bookID)
+	if err != nil && !errors.Is(err, ErrBookNotFound) {
+		s.logger.WithError(err).WithField("book_id", bookID).Error("Failed to fetch book genres")
+		return nil, err
+	}
+
+	evaluation, err := s.evaluate(branchID, membershipType, bookGenres)
+	if err != nil {
+		return nil, err
+	}
+	return &evaluation.Terms, nil
+}
+
+// policyMatch is a rule that matches a loan, with how far the genre it is
+// for is above the closest genre of the book.
+type policyMatch struct {
+	policy   models.LoanPolicy
+	distance int
+}
+
+// evaluate ranks the rules matching a branch, a membership type and a book
+// with genres, most specific first: rules for the branch before rules for
+// every branch, then rules for the membership type before rules for every
+// member, then rules for a genre, the closer to the book the better, before
+// rules for every book, then by priority. Each term comes from the first
+// rule that sets it.
+func (s *PolicyService) evaluate(branchID *string, membershipType string, bookGenres []models.Genre) (*models.PolicyEvaluation, error) {
+	policies, err := s.policies.List()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query loan policies")
+		return nil, err
+	}
+
+	var distances map[string]int
+	if len(bookGenres) > 0 {
+		genres, err := s.genres.List()
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to query genres")
+			return nil, err
+		}
+		distances = genreDistances(genres, bookGenres)
+	}
+
+	matches := []policyMatch{}
+	for _, policy := range policies {
+		if policy.BranchID != nil && (branchID == nil || *policy.BranchID != *branchID) {
+			continue
+		}
+		if policy.MembershipType != nil && *policy.MembershipType != membershipType {
+			continue
+		}
+		distance := -1
+		if policy.GenreID != nil {
+			d, ok := distances[*policy.GenreID]
+			if !ok {
+				continue
+			}
+			distance = d
+		}
+		matches = append(matches, policyMatch{policy: policy, distance: distance})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if (a.policy.BranchID != nil) != (b.policy.BranchID != nil) {
+			return a.policy.BranchID != nil
+		}
+		if (a.policy.MembershipType != nil) != (b.policy.MembershipType != nil) {
+			return a.policy.MembershipType != nil
+		}
+		if (a.distance < 0) != (b.distance < 0) {
+			return a.distance >= 0
+		}
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if a.policy.Priority != b.policy.Priority {
+			return a.policy.Priority > b.policy.Priority
+		}
+		if a.policy.Name != b.policy.Name {
+			return a.policy.Name < b.policy.Name
+		}
+		return a.policy.ID < b.policy.ID
+	})
+
+	evaluation := &models.PolicyEvaluation{
+		Terms: s.defaults,
+		Sources: map[string]string{
+			"loan_days":        policySourceDefault,
+			"max_renewals":     policySourceDefault,
+			"daily_fine_cents": policySourceDefault,
+			"max_fine_cents":   policySourceDefault,
+			"holds_allowed":    policySourceDefault,
+		},
+		Matched: make([]models.LoanPolicy, 0, len(matches)),
+	}
+	// Walk from the least specific rule up, so that more specific ones
+	// overwrite what it sets.
+	for i := len(matches) - 1; i >= 0; i-- {
+		policy := matches[i].policy
+		if policy.LoanDays != nil {
+			evaluation.Terms.LoanDays = *policy.LoanDays
+			evaluation.Sources["loan_days"] = policy.ID
+		}
+		if policy.MaxRenewals != nil {
+			evaluation.Terms.MaxRenewals = *policy.MaxRenewals
+			evaluation.Sources["max_renewals"] = policy.ID
+		}
+		if policy.DailyFineCents != nil {
+			evaluation.Terms.DailyFineCents = *policy.DailyFineCents
+			evaluation.Sources["daily_fine_cents"] = policy.ID
+		}
+		if policy.MaxFineCents != nil {
+			evaluation.Terms.MaxFineCents = *policy.MaxFineCents
+			evaluation.Sources["max_fine_cents"] = policy.ID
+		}
+		if policy.HoldsAllowed != nil {
+			evaluation.Terms.HoldsAllowed = *policy.HoldsAllowed
+			evaluation.Sources["holds_allowed"] = policy.ID
+		}
+	}
+	for _, match := range matches {
+		evaluation.Matched = append(evaluation.Matched, match.policy)
+	}
+
+	return evaluation, nil
+}
+
+// genreDistances maps the genres of a book and all of their ancestors to
+// how many levels the genre is above the closest genre of the book.
+func genreDistances(genres []models.Genre, bookGenres []models.Genre) map[string]int {
+	parents := make(map[string]*string, len(genres))
+	for _, genre := range genres {
+		parents[genre.ID] = genre.ParentID
+	}
+
+	distances := map[string]int{}
+	for _, genre := range bookGenres {
+		id := genre.ID
+		for distance := 0; distance <= len(genres); distance++ {
+			if d, ok := distances[id]; ok && d <= distance {
+				break
+			}
+			distances[id] = distance
+			parent := parents[id]
+			if parent == nil {
+				break
+			}
+			id = *parent
+		}
+	}
+	return distances
+}
+
+func policyFromRequest(req *models.LoanPolicyRequest) *models.LoanPolicy {
+	return &models.LoanPolicy{
+		Name:           strings.TrimSpace(req.Name),
+		BranchID:       req.BranchID,
+		MembershipType: req.MembershipType,
+		GenreID:        req.GenreID,
+		Priority:       req.Priority,
+		LoanDays:       req.LoanDays,
+		MaxRenewals:    req.MaxRenewals,
+		DailyFineCents: req.DailyFineCents,
+		MaxFineCents:   req.MaxFineCents,
+		HoldsAllowed:   req.HoldsAllowed,
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func (l *testLibrary) newPolicyService() *PolicyService {
+	return NewPolicyService(repository.NewMemoryPolicyRepository(l.genreRepo), l.genreRepo, l.memberRepo, l.branchRepo, l.rules, testFineRules, l.logger)
+}
+
+type policyFixture struct {
+	*testLibrary
+	circulation *CirculationService
+	holds       *HoldService
+	policies    *PolicyService
+	genres      *GenreService
+}
+
+func newPolicyFixture(t *testing.T, rules LoanRules) *policyFixture {
+	lib := newTestLibrary(t, rules)
+	policies := lib.newPolicyService()
+	return &policyFixture{
+		testLibrary: lib,
+		circulation: lib.newCirculationService(policies, lib.newFineService()),
+		holds:       lib.newHoldService(policies),
+		policies:    policies,
+		genres:      NewGenreService(lib.genreRepo, lib.logger),
+	}
+}
+
+func TestPolicyService_Evaluate(t *testing.T) {
+	fixture := newPolicyFixture(t, LoanRules{LoanDays: 21, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	fiction, err := fixture.genres.CreateGenre(&models.CreateGenreRequest{Name: "Fiction"})
+	require.NoError(t, err)
+	mystery, err := fixture.genres.CreateGenre(&models.CreateGenreRequest{Name: "Mystery", ParentID: &fiction.ID})
+	require.NoError(t, err)
+	reference, err := fixture.genres.CreateGenre(&models.CreateGenreRequest{Name: "Reference"})
+	require.NoError(t, err)
+	_, _, err = fixture.books.SetBookGenres(fixture.bookID, &models.SetBookGenresRequest{GenreIDs: []string{mystery.ID}}, 0)
+	require.NoError(t, err)
+
+	child := models.MembershipChild
+	noHolds := false
+	everyone, err := fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Everyone", LoanDays: intPtr(28), DailyFineCents: intPtr(10)})
+	require.NoError(t, err)
+	onFiction, err := fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Fiction", GenreID: &fiction.ID, LoanDays: intPtr(21), MaxRenewals: intPtr(3)})
+	require.NoError(t, err)
+	onMystery, err := fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Mystery", GenreID: &mystery.ID, LoanDays: intPtr(14)})
+	require.NoError(t, err)
+	children, err := fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Children", MembershipType: &child, MaxRenewals: intPtr(0), HoldsAllowed: &noHolds})
+	require.NoError(t, err)
+
+	_, err = fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Everyone again"})
+	assert.ErrorIs(t, err, ErrDuplicatePolicy)
+	missing := uuid.New().String()
+	_, err = fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Missing", GenreID: &missing})
+	assert.ErrorIs(t, err, ErrUnknownGenre)
+
+	t.Run("terms cascade from the most specific rule", func(t *testing.T) {
+		evaluation, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{MembershipType: models.MembershipAdult, BookID: fixture.bookID})
+		require.NoError(t, err)
+		assert.Equal(t, models.LoanTerms{LoanDays: 14, MaxRenewals: 3, DailyFineCents: 10, MaxFineCents: 200, HoldsAllowed: true}, evaluation.Terms)
+		assert.Equal(t, map[string]string{
+			"loan_days":        onMystery.ID,
+			"max_renewals":     onFiction.ID,
+			"daily_fine_cents": everyone.ID,
+			"max_fine_cents":   "default",
+			"holds_allowed":    "default",
+		}, evaluation.Sources)
+		require.Len(t, evaluation.Matched, 3)
+		assert.Equal(t, []string{onMystery.ID, onFiction.ID, everyone.ID},
+			[]string{evaluation.Matched[0].ID, evaluation.Matched[1].ID, evaluation.Matched[2].ID})
+	})
+
+	t.Run("membership type before genre", func(t *testing.T) {
+		member, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Charles", LastName: "Musgrove", MembershipType: models.MembershipChild})
+		require.NoError(t, err)
+
+		evaluation, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{MemberID: member.ID, MembershipType: models.MembershipAdult, BookID: fixture.bookID})
+		require.NoError(t, err)
+		assert.Equal(t, models.LoanTerms{LoanDays: 14, MaxRenewals: 0, DailyFineCents: 10, MaxFineCents: 200, HoldsAllowed: false}, evaluation.Terms)
+		assert.Equal(t, children.ID, evaluation.Matched[0].ID)
+	})
+
+	t.Run("priority breaks ties", func(t *testing.T) {
+		_, _, err := fixture.books.SetBookGenres(fixture.bookID, &models.SetBookGenresRequest{GenreIDs: []string{mystery.ID, reference.ID}}, 0)
+		require.NoError(t, err)
+		onReference, err := fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Reference", GenreID: &reference.ID, Priority: 5, LoanDays: intPtr(7)})
+		require.NoError(t, err)
+
+		evaluation, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{MembershipType: models.MembershipAdult, BookID: fixture.bookID})
+		require.NoError(t, err)
+		assert.Equal(t, 7, evaluation.Terms.LoanDays)
+		assert.Equal(t, onReference.ID, evaluation.Sources["loan_days"])
+
+		require.NoError(t, fixture.policies.DeletePolicy(onReference.ID))
+		_, _, err = fixture.books.SetBookGenres(fixture.bookID, &models.SetBookGenresRequest{GenreIDs: []string{mystery.ID}}, 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("without a book", func(t *testing.T) {
+		evaluation, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{MembershipType: models.MembershipAdult})
+		require.NoError(t, err)
+		assert.Equal(t, 28, evaluation.Terms.LoanDays)
+		assert.Equal(t, 1, evaluation.Terms.MaxRenewals)
+		assert.Len(t, evaluation.Matched, 1)
+	})
+
+	t.Run("branch rules first", func(t *testing.T) {
+		branch, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "KEL", Name: "Kellynch"})
+		require.NoError(t, err)
+		atBranch, err := fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Kellynch", BranchID: &branch.ID, LoanDays: intPtr(10)})
+		require.NoError(t, err)
+		_, err = fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Kellynch again", BranchID: &branch.ID})
+		assert.ErrorIs(t, err, ErrDuplicatePolicy)
+
+		evaluation, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{BranchID: branch.ID, MembershipType: models.MembershipAdult, BookID: fixture.bookID})
+		require.NoError(t, err)
+		assert.Equal(t, 10, evaluation.Terms.LoanDays)
+		assert.Equal(t, atBranch.ID, evaluation.Sources["loan_days"])
+
+		evaluation, err = fixture.policies.Evaluate(&models.PolicyEvaluationParams{MembershipType: models.MembershipAdult, BookID: fixture.bookID})
+		require.NoError(t, err)
+		assert.Equal(t, 14, evaluation.Terms.LoanDays, "branch rules only match at the branch")
+
+		_, err = fixture.policies.Evaluate(&models.PolicyEvaluationParams{BranchID: uuid.New().String(), MembershipType: models.MembershipAdult})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+		missing := uuid.New().String()
+		_, err = fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Missing", BranchID: &missing})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+
+		require.NoError(t, fixture.policies.DeletePolicy(atBranch.ID))
+	})
+
+	t.Run("unknown member or book", func(t *testing.T) {
+		_, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{MemberID: uuid.New().String()})
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+		_, err = fixture.policies.Evaluate(&models.PolicyEvaluationParams{MembershipType: models.MembershipAdult, BookID: uuid.New().String()})
+		assert.ErrorIs(t, err, ErrBookNotFound)
+	})
+
+	t.Run("update unsets terms", func(t *testing.T) {
+		updated, err := fixture.policies.UpdatePolicy(onFiction.ID, &models.LoanPolicyRequest{Name: "Fiction", GenreID: &fiction.ID, LoanDays: intPtr(21)})
+		require.NoError(t, err)
+		assert.Nil(t, updated.MaxRenewals)
+		assert.Equal(t, onFiction.CreatedAt, updated.CreatedAt)
+
+		evaluation, err := fixture.policies.Evaluate(&models.PolicyEvaluationParams{MembershipType: models.MembershipAdult, BookID: fixture.bookID})
+		require.NoError(t, err)
+		assert.Equal(t, 1, evaluation.Terms.MaxRenewals)
+
+		_, err = fixture.policies.UpdatePolicy(uuid.New().String(), &models.LoanPolicyRequest{Name: "Missing"})
+		assert.ErrorIs(t, err, ErrPolicyNotFound)
+	})
+}
+
+func TestPolicyService_Circulation(t *testing.T) {
+	fixture := newPolicyFixture(t, LoanRules{LoanDays: 21, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	_, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1"})
+	require.NoError(t, err)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	charles, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Charles", LastName: "Musgrove", MembershipType: models.MembershipChild})
+	require.NoError(t, err)
+
+	child := models.MembershipChild
+	noHolds := false
+	_, err = fixture.policies.CreatePolicy(&models.LoanPolicyRequest{Name: "Children", MembershipType: &child,
+		LoanDays: intPtr(7), MaxRenewals: intPtr(0), DailyFineCents: intPtr(5), MaxFineCents: intPtr(50), HoldsAllowed: &noHolds})
+	require.NoError(t, err)
+
+	loan, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: charles.CardNumber})
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().AddDate(0, 0, 7).Format(dateLayout), loan.DueOn)
+	assert.Equal(t, 5, *loan.DailyFineCents)
+	assert.Equal(t, 50, *loan.MaxFineCents)
+
+	_, err = fixture.circulation.Renew(&models.RenewRequest{Barcode: "P-1"})
+	assert.ErrorIs(t, err, ErrRenewalLimitReached)
+
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: charles.ID})
+	assert.ErrorIs(t, err, ErrHoldsNotAllowed)
+	_, err = fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: anne.ID})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrPurchaseOrderNotFound  = repository.ErrPurchaseOrderNotFound
+	ErrOrderNotDraft          = repository.ErrOrderNotDraft
+	ErrInvalidOrderTransition = repository.ErrInvalidOrderTransition
+	ErrOrderLineNotFound      = repository.ErrOrderLineNotFound
+	ErrOverReceipt            = repository.ErrOverReceipt
+	ErrFundOverCommitted      = repository.ErrFundOverCommitted
+)
+
+// PurchaseOrderService drafts purchase orders for catalog records, places
+// them against the budgets of their funds and receives their copies into
+// the catalog.
+type PurchaseOrderService struct {
+	orders   repository.PurchaseOrderRepository
+	vendors  repository.VendorRepository
+	funds    repository.FundRepository
+	books    repository.BookRepository
+	branches repository.BranchRepository
+	calendar *CalendarService
+	rules    LoanRules
+	logger   *logrus.Logger
+}
+
+func NewPurchaseOrderService(orders repository.PurchaseOrderRepository, vendors repository.VendorRepository,
+	funds repository.FundRepository, books repository.BookRepository, branches repository.BranchRepository,
+	calendar *CalendarService, rules LoanRules, logger *logrus.Logger) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		orders:   orders,
+		vendors:  vendors,
+		funds:    funds,
+		books:    books,
+		branches: branches,
+		calendar: calendar,
+		rules:    rules,
+		logger:   logger,
+	}
+}
+
+func (s *PurchaseOrderService) ListPurchaseOrders(params *models.PurchaseOrderListParams) (*models.PurchaseOrderListResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"status":    params.Status,
+		"vendor_id": params.VendorID,
+		"fund_id":   params.FundID,
+	}).Info("Fetching purchase orders")
+
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	response, err := s.orders.List(&query)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query purchase orders")
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *PurchaseOrderService) GetPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	s.logger.WithField("order_id", id).Info("Fetching purchase order by ID")
+
+	order, err := s.orders.GetByID(id)
+	if errors.Is(err, ErrPurchaseOrderNotFound) {
+		s.logger.WithField("order_id", id).Warn("Purchase order not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("order_id", id).Error("Failed to fetch purchase order")
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreatePurchaseOrder drafts an order from a vendor. Each line keeps the
+// title its book has now.
+func (s *PurchaseOrderService) CreatePurchaseOrder(req *models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	s.logger.WithField("vendor_id", req.VendorID).Info("Creating new purchase order")
+
+	now := time.Now()
+	order := &models.PurchaseOrder{
+		ID:        uuid.New().String(),
+		VendorID:  req.VendorID,
+		Status:    models.OrderDraft,
+		Note:      req.Note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.draftLines(order, req.Lines); err != nil {
+		return nil, err
+	}
+
+	if err := s.orders.Create(order); err != nil {
+		if errors.Is(err, ErrVendorNotFound) || errors.Is(err, ErrFundNotFound) || errors.Is(err, ErrBookNotFound) {
+			s.logger.WithError(err).WithField("vendor_id", order.VendorID).Warn("Failed to create purchase order")
+			return nil, err
+		}
+		s.logger.WithError(err).Error("Failed to create purchase order")
+		return nil, err
+	}
+
+	s.logger.WithField("order_id", order.ID).Info("Successfully created purchase order")
+	return order, nil
+}
+
+// UpdatePurchaseOrder replaces the vendor, note and lines of a draft.
+func (s *PurchaseOrderService) UpdatePurchaseOrder(id string, req *models.UpdatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	s.logger.WithField("order_id", id).Info("Updating purchase order")
+
+	order, err := s.GetPurchaseOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderDraft {
+		s.logger.WithField("order_id", id).Warn("Purchase order has already been placed")
+		return nil, ErrOrderNotDraft
+	}
+
+	order.VendorID = req.VendorID
+	order.Note = req.Note
+	order.UpdatedAt = time.Now()
+	if err := s.draftLines(order, req.Lines); err != nil {
+		return nil, err
+	}
+
+	if err := s.orders.Update(order); err != nil {
+		if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrOrderNotDraft) || errors.Is(err, ErrVendorNotFound) ||
+			errors.Is(err, ErrFundNotFound) || errors.Is(err, ErrBookNotFound) {
+			s.logger.WithError(err).WithField("order_id", id).Warn("Failed to update purchase order")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("order_id", id).Error("Failed to update purchase order")
+		return nil, err
+	}
+
+	s.logger.WithField("order_id", id).Info("Successfully updated purchase order")
+	return order, nil
+}
+
+// DeletePurchaseOrder removes a draft. Placed orders are cancelled instead.
+func (s *PurchaseOrderService) DeletePurchaseOrder(id string) error {
+	s.logger.WithField("order_id", id).Info("Deleting purchase order")
+
+	err := s.orders.Delete(id)
+	if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrOrderNotDraft) {
+		s.logger.WithError(err).WithField("order_id", id).Warn("Failed to delete purchase order")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("order_id", id).Error("Failed to delete purchase order")
+		return err
+	}
+
+	s.logger.WithField("order_id", id).Info("Successfully deleted purchase order")
+	return nil
+}
+
+// SubmitPurchaseOrder places a draft with its vendor, committing its cost
+// against its funds, as long as every fund has enough left to pay for it.
+func (s *PurchaseOrderService) SubmitPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	s.logger.WithField("order_id", id).Info("Placing purchase order")
+
+	order, err := s.orders.Submit(id, time.Now())
+	if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrInvalidOrderTransition) ||
+		errors.Is(err, ErrFundOverCommitted) || errors.Is(err, ErrFundNotFound) {
+		s.logger.WithError(err).WithField("order_id", id).Warn("Failed to place purchase order")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("order_id", id).Error("Failed to place purchase order")
+		return nil, err
+	}
+
+	s.logger.WithField("order_id", id).Info("Successfully placed purchase order")
+	return order, nil
+}
+
+// ReceivePurchaseOrder adds the copies of a delivery to the catalog, new
+// and available, acquired today at the unit price of their line, and counts
+// them against the order. Copies that waiting holds can use are trapped for
+// them.
+func (s *PurchaseOrderService) ReceivePurchaseOrder(id string, req *models.ReceivePurchaseOrderRequest) (*models.ReceivePurchaseOrderResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"order_id": id,
+		"lines":    len(req.Lines),
+	}).Info("Receiving purchase order")
+
+	now := time.Now()
+	acquiredOn := now.Format(dateLayout)
+	receipts := make([]repository.LineReceipt, 0, len(req.Lines))
+	items := []models.Item{}
+	for _, line := range req.Lines {
+		if err := checkBranch(s.branches, line.HomeBranchID, s.logger); err != nil {
+			return nil, err
+		}
+		receipt := repository.LineReceipt{LineID: line.LineID}
+		for _, barcode := range line.Barcodes {
+			receipt.Items = append(receipt.Items, &models.Item{
+				ID:              uuid.New().String(),
+				Barcode:         strings.TrimSpace(barcode),
+				Location:        line.Location,
+				HomeBranchID:    line.HomeBranchID,
+				CurrentBranchID: line.HomeBranchID,
+				AcquiredOn:      &acquiredOn,
+				Condition:       models.ItemConditionNew,
+				Status:          models.ItemAvailable,
+				StatusChangedAt: now,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			})
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orders.Receive(id, receipts, now, pickup)
+	if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrInvalidOrderTransition) ||
+		errors.Is(err, ErrOrderLineNotFound) || errors.Is(err, ErrOverReceipt) || errors.Is(err, ErrDuplicateBarcode) ||
+		errors.Is(err, ErrBookNotFound) || errors.Is(err, ErrBranchNotFound) {
+		s.logger.WithError(err).WithField("order_id", id).Warn("Failed to receive purchase order")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("order_id", id).Error("Failed to receive purchase order")
+		return nil, err
+	}
+
+	for _, receipt := range receipts {
+		for _, item := range receipt.Items {
+			items = append(items, *item)
+		}
+	}
+	s.logger.WithFields(logrus.Fields{
+		"order_id": id,
+		"items":    len(items),
+		"status":   order.Status,
+	}).Info("Successfully received purchase order")
+	return &models.ReceivePurchaseOrderResponse{Order: order, Items: items}, nil
+}
+
+// CancelPurchaseOrder closes an order that has not been received in full.
+// Copies already received stay in the catalog and stay paid for.
+func (s *PurchaseOrderService) CancelPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	s.logger.WithField("order_id", id).Info("Cancelling purchase order")
+
+	order, err := s.orders.Cancel(id, time.Now())
+	if errors.Is(err, ErrPurchaseOrderNotFound) || errors.Is(err, ErrInvalidOrderTransition) {
+		s.logger.WithError(err).WithField("order_id", id).Warn("Failed to cancel purchase order")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("order_id", id).Error("Failed to cancel purchase order")
+		return nil, err
+	}
+
+	s.logger.WithField("order_id", id).Info("Successfully cancelled purchase order")
+	return order, nil
+}
+
+// draftLines checks the vendor, books and funds of a draft and gives it
+// its lines.
+func (s *PurchaseOrderService) draftLines(order *models.PurchaseOrder, reqs []models.PurchaseOrderLineRequest) error {
+	if _, err := s.vendors.GetByID(order.VendorID); err != nil {
+		if errors.Is(err, ErrVendorNotFound) {
+			s.logger.WithField("vendor_id", order.VendorID).Warn("Vendor not found")
+			return err
+		}
+		s.logger.WithError(err).WithField("vendor_id", order.VendorID).Error("Failed to fetch vendor")
+		return err
+	}
+
+	lines := make([]models.PurchaseOrderLine, 0, len(reqs))
+	for _, req := range reqs {
+		book, err := s.books.GetByID(req.BookID)
+		if errors.Is(err, ErrBookNotFound) {
+			s.logger.WithField("book_id", req.BookID).Warn("Book not found")
+			return err
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("book_id", req.BookID).Error("Failed to fetch book")
+			return err
+		}
+		_, err = s.funds.GetByID(req.FundID)
+		if errors.Is(err, ErrFundNotFound) {
+			s.logger.WithField("fund_id", req.FundID).Warn("Fund not found")
+			return err
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("fund_id", req.FundID).Error("Failed to fetch fund")
+			return err
+		}
+
+		bookID := book.ID
+		lines = append(lines, models.PurchaseOrderLine{
+			ID:             uuid.New().String(),
+			OrderID:        order.ID,
+			BookID:         &bookID,
+			Title:          book.Title,
+			FundID:         req.FundID,
+			Quantity:       req.Quantity,
+			UnitPriceCents: req.UnitPriceCents,
+		})
+	}
+	order.Lines = lines
+	return nil
+}
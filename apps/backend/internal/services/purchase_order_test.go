@@ -0,0 +1,121 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type purchaseOrderFixture struct {
+	*testLibrary
+	vendors *VendorService
+	funds   *FundService
+	orders  *PurchaseOrderService
+}
+
+func newPurchaseOrderFixture(t *testing.T, rules LoanRules) *purchaseOrderFixture {
+	lib := newTestLibrary(t, rules)
+	vendors := repository.NewMemoryVendorRepository()
+	funds := repository.NewMemoryFundRepository()
+	orders := repository.NewMemoryPurchaseOrderRepository(lib.itemRepo, vendors, funds)
+	return &purchaseOrderFixture{
+		testLibrary: lib,
+		vendors:     NewVendorService(vendors, lib.logger),
+		funds:       NewFundService(funds, lib.logger),
+		orders:      NewPurchaseOrderService(orders, vendors, funds, lib.bookRepo, lib.branchRepo, lib.calendar, rules, lib.logger),
+	}
+}
+
+func TestPurchaseOrderService(t *testing.T) {
+	fixture := newPurchaseOrderFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	main, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "MAIN", Name: "Main Library"})
+	require.NoError(t, err)
+	vendor, err := fixture.vendors.CreateVendor(&models.CreateVendorRequest{Name: " Blackwell's "})
+	require.NoError(t, err)
+	assert.Equal(t, "Blackwell's", vendor.Name)
+	adult, err := fixture.funds.CreateFund(&models.CreateFundRequest{Code: " adult ", Name: "Adult fiction", FiscalYear: 2026, BudgetCents: 5000})
+	require.NoError(t, err)
+	assert.Equal(t, "ADULT", adult.Code)
+	_, err = fixture.funds.CreateFund(&models.CreateFundRequest{Code: "Adult", Name: "Again", FiscalYear: 2026})
+	assert.ErrorIs(t, err, ErrDuplicateFundCode)
+
+	line := models.PurchaseOrderLineRequest{BookID: fixture.bookID, FundID: adult.ID, Quantity: 2, UnitPriceCents: 1500}
+	_, err = fixture.orders.CreatePurchaseOrder(&models.CreatePurchaseOrderRequest{VendorID: uuid.New().String(), Lines: []models.PurchaseOrderLineRequest{line}})
+	assert.ErrorIs(t, err, ErrVendorNotFound)
+	missing := line
+	missing.FundID = uuid.New().String()
+	_, err = fixture.orders.CreatePurchaseOrder(&models.CreatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{missing}})
+	assert.ErrorIs(t, err, ErrFundNotFound)
+
+	order, err := fixture.orders.CreatePurchaseOrder(&models.CreatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{line, line}})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderDraft, order.Status)
+	assert.Equal(t, "Persuasion", order.Lines[0].Title)
+	assert.Equal(t, 6000, order.TotalCents)
+	_, err = fixture.orders.SubmitPurchaseOrder(order.ID)
+	assert.ErrorIs(t, err, ErrFundOverCommitted)
+
+	order, err = fixture.orders.UpdatePurchaseOrder(order.ID, &models.UpdatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{line}})
+	require.NoError(t, err)
+	require.Len(t, order.Lines, 1)
+	order, err = fixture.orders.SubmitPurchaseOrder(order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderOrdered, order.Status)
+	_, err = fixture.orders.UpdatePurchaseOrder(order.ID, &models.UpdatePurchaseOrderRequest{VendorID: vendor.ID, Lines: []models.PurchaseOrderLineRequest{line}})
+	assert.ErrorIs(t, err, ErrOrderNotDraft)
+	assert.ErrorIs(t, fixture.vendors.DeleteVendor(vendor.ID), ErrVendorInUse)
+
+	shelf := "PR 4034"
+	nowhere := uuid.New().String()
+	_, err = fixture.orders.ReceivePurchaseOrder(order.ID, &models.ReceivePurchaseOrderRequest{Lines: []models.ReceiveOrderLineRequest{
+		{LineID: order.Lines[0].ID, Barcodes: []string{"P-1"}, HomeBranchID: &nowhere},
+	}})
+	assert.ErrorIs(t, err, ErrBranchNotFound)
+
+	received, err := fixture.orders.ReceivePurchaseOrder(order.ID, &models.ReceivePurchaseOrderRequest{Lines: []models.ReceiveOrderLineRequest{
+		{LineID: order.Lines[0].ID, Barcodes: []string{" P-1 "}, Location: &shelf, HomeBranchID: &main.ID},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderPartiallyReceived, received.Order.Status)
+	require.Len(t, received.Items, 1)
+	item := received.Items[0]
+	assert.Equal(t, "P-1", item.Barcode)
+	assert.Equal(t, fixture.bookID, item.BookID)
+	assert.Equal(t, main.ID, *item.CurrentBranchID)
+	assert.Equal(t, models.ItemConditionNew, item.Condition)
+	assert.Equal(t, time.Now().Format(dateLayout), *item.AcquiredOn)
+	assert.Equal(t, 1500, *item.PriceCents)
+
+	shelved, err := fixture.items.GetItemByBarcode("P-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemAvailable, shelved.Status)
+	assert.Equal(t, shelf, *shelved.Location)
+
+	funds, err := fixture.funds.ListFunds(&models.FundListParams{FiscalYear: 2026})
+	require.NoError(t, err)
+	require.Len(t, funds.Data, 1)
+	assert.Equal(t, 1500, funds.EncumberedCents)
+	assert.Equal(t, 1500, funds.SpentCents)
+	assert.Equal(t, 2000, funds.AvailableCents)
+
+	received, err = fixture.orders.ReceivePurchaseOrder(order.ID, &models.ReceivePurchaseOrderRequest{Lines: []models.ReceiveOrderLineRequest{
+		{LineID: order.Lines[0].ID, Barcodes: []string{"P-2"}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderReceived, received.Order.Status)
+	_, err = fixture.orders.CancelPurchaseOrder(order.ID)
+	assert.ErrorIs(t, err, ErrInvalidOrderTransition)
+	assert.ErrorIs(t, fixture.orders.DeletePurchaseOrder(order.ID), ErrOrderNotDraft)
+
+	fund, err := fixture.funds.GetFundByID(adult.ID)
+	require.NoError(t, err)
+	assert.Zero(t, fund.EncumberedCents)
+	assert.Equal(t, 3000, fund.SpentCents)
+	assert.ErrorIs(t, fixture.funds.DeleteFund(adult.ID), ErrFundInUse)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// testLibrary is a catalog holding one book, with the copies, members and
+// branches around it kept in memory repositories wired to each other, and
+// the calendar every due date comes from. The tests of each feature build
+// the services they exercise on top of it.
+type testLibrary struct {
+	logger *logrus.Logger
+	rules  LoanRules
+
+	books    *BookService
+	items    *ItemService
+	members  *MemberService
+	branches *BranchService
+	calendar *CalendarService
+	bookID   string
+
+	// The repositories behind the services, for services built on the same
+	// data.
+	bookRepo   *repository.MemoryBookRepository
+	genreRepo  *repository.MemoryGenreRepository
+	itemRepo   *repository.MemoryItemRepository
+	memberRepo *repository.MemoryMemberRepository
+	loanRepo   *repository.MemoryLoanRepository
+	branchRepo *repository.MemoryBranchRepository
+}
+
+func newTestLibrary(t *testing.T, rules LoanRules) *testLibrary {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	books := repository.NewMemoryBookRepository()
+	items := repository.NewMemoryItemRepository(books)
+	members := repository.NewMemoryMemberRepository()
+	loans := repository.NewMemoryLoanRepository(items, members)
+	branches := repository.NewMemoryBranchRepository(loans)
+	genres := repository.NewMemoryGenreRepository(books)
+	calendar := NewCalendarService(repository.NewMemoryCalendarRepository(), branches, time.Local, logger)
+	bookService := NewBookService(books, repository.NewMemoryAuthorRepository(books), genres, items, logger)
+
+	book, err := bookService.CreateBook(&models.CreateBookRequest{Title: "Persuasion", Author: "Jane Austen", Year: 1817})
+	require.NoError(t, err)
+
+	return &testLibrary{
+		logger:     logger,
+		rules:      rules,
+		books:      bookService,
+		items:      NewItemService(items, books, loans, branches, calendar, rules, logger),
+		members:    NewMemberService(members, loans, logger),
+		branches:   NewBranchService(branches, logger),
+		calendar:   calendar,
+		bookID:     book.ID,
+		bookRepo:   books,
+		genreRepo:  genres,
+		itemRepo:   items,
+		memberRepo: members,
+		loanRepo:   loans,
+		branchRepo: branches,
+	}
+}
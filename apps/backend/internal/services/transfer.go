@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrTransferNotFound          = repository.ErrTransferNotFound
+	ErrDuplicateTransfer         = repository.ErrDuplicateTransfer
+	ErrInvalidTransferTransition = repository.ErrInvalidTransferTransition
+	ErrItemHasNoBranch           = repository.ErrItemHasNoBranch
+	ErrSameBranch                = repository.ErrSameBranch
+)
+
+type TransferService struct {
+	transfers repository.TransferRepository
+	items     repository.ItemRepository
+	branches  repository.BranchRepository
+	calendar  *CalendarService
+	rules     LoanRules
+	logger    *logrus.Logger
+}
+
+func NewTransferService(transfers repository.TransferRepository, items repository.ItemRepository, branches repository.BranchRepository, calendar *CalendarService, rules LoanRules, logger *logrus.Logger) *TransferService {
+	return &TransferService{
+		transfers: transfers,
+		items:     items,
+		branches:  branches,
+		calendar:  calendar,
+		rules:     rules,
+		logger:    logger,
+	}
+}
+
+// RequestTransfer asks for the copy with a barcode, which must be on the
+// shelf, to be sent from the branch it is at to another one.
+func (s *TransferService) RequestTransfer(req *models.CreateTransferRequest) (*models.Transfer, error) {
+	s.logger.WithFields(logrus.Fields{
+		"barcode":      req.Barcode,
+		"to_branch_id": req.ToBranchID,
+	}).Info("Requesting transfer")
+
+	barcode := strings.TrimSpace(req.Barcode)
+	item, err := s.items.GetByBarcode(barcode)
+	if errors.Is(err, ErrItemNotFound) {
+		s.logger.WithField("barcode", barcode).Warn("Item not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("barcode", barcode).Error("Failed to fetch item")
+		return nil, err
+	}
+	if err := checkBranch(s.branches, &req.ToBranchID, s.logger); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer := &models.Transfer{
+		ID:          uuid.New().String(),
+		ItemID:      item.ID,
+		ToBranchID:  req.ToBranchID,
+		Status:      models.TransferRequested,
+		Note:        req.Note,
+		RequestedAt: now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.transfers.Request(transfer); err != nil {
+		if errors.Is(err, ErrItemNotFound) || errors.Is(err, ErrItemNotAvailable) || errors.Is(err, ErrItemHasNoBranch) ||
+			errors.Is(err, ErrSameBranch) || errors.Is(err, ErrDuplicateTransfer) || errors.Is(err, ErrBranchNotFound) {
+			s.logger.WithError(err).WithField("item_id", item.ID).Warn("Failed to request transfer")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("item_id", item.ID).Error("Failed to request transfer")
+		return nil, err
+	}
+
+	s.logger.WithField("transfer_id", transfer.ID).Info("Successfully requested transfer")
+	return transfer, nil
+}
+
+// ShipTransfer sends a requested transfer on its way, putting its copy in
+// transit.
+func (s *TransferService) ShipTransfer(id string) (*models.Transfer, error) {
+	s.logger.WithField("transfer_id", id).Info("Shipping transfer")
+
+	transfer, err := s.transfers.Ship(id, time.Now())
+	if errors.Is(err, ErrTransferNotFound) || errors.Is(err, ErrInvalidTransferTransition) || errors.Is(err, ErrItemNotAvailable) {
+		s.logger.WithError(err).WithField("transfer_id", id).Warn("Failed to ship transfer")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("transfer_id", id).Error("Failed to ship transfer")
+		return nil, err
+	}
+
+	s.logger.WithField("transfer_id", id).Info("Successfully shipped transfer")
+	return transfer, nil
+}
+
+// ReceiveTransfer checks a copy in transit in at the branch it was sent to.
+// A copy sent for a hold goes on the hold shelf there; any other copy is
+// trapped for the next hold it can fill, and otherwise stays at the branch
+// as available.
+func (s *TransferService) ReceiveTransfer(id string) (*models.ReceiveTransferResponse, error) {
+	s.logger.WithField("transfer_id", id).Info("Receiving transfer")
+
+	now := time.Now()
+	pickup, err := s.calendar.holdPickup(now, s.rules.HoldPickupDays)
+	if err != nil {
+		return nil, err
+	}
+	transfer, hold, err := s.transfers.Receive(id, now, pickup)
+	if errors.Is(err, ErrTransferNotFound) || errors.Is(err, ErrInvalidTransferTransition) || errors.Is(err, ErrInvalidItemTransition) {
+		s.logger.WithError(err).WithField("transfer_id", id).Warn("Failed to receive transfer")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("transfer_id", id).Error("Failed to receive transfer")
+		return nil, err
+	}
+
+	if hold != nil {
+		s.logger.WithFields(logrus.Fields{
+			"transfer_id": id,
+			"hold_id":     hold.ID,
+		}).Info("Successfully received transfer for a hold")
+	} else {
+		s.logger.WithField("transfer_id", id).Info("Successfully received transfer")
+	}
+	return &models.ReceiveTransferResponse{Transfer: transfer, Hold: hold}, nil
+}
+
+// CancelTransfer cancels a transfer that has not been shipped.
+func (s *TransferService) CancelTransfer(id string) (*models.Transfer, error) {
+	s.logger.WithField("transfer_id", id).Info("Cancelling transfer")
+
+	transfer, err := s.transfers.Cancel(id, time.Now())
+	if errors.Is(err, ErrTransferNotFound) || errors.Is(err, ErrInvalidTransferTransition) {
+		s.logger.WithError(err).WithField("transfer_id", id).Warn("Failed to cancel transfer")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("transfer_id", id).Error("Failed to cancel transfer")
+		return nil, err
+	}
+
+	s.logger.WithField("transfer_id", id).Info("Successfully cancelled transfer")
+	return transfer, nil
+}
+
+func (s *TransferService) GetTransfer(id string) (*models.Transfer, error) {
+	s.logger.WithField("transfer_id", id).Info("Fetching transfer by ID")
+
+	transfer, err := s.transfers.GetByID(id)
+	if errors.Is(err, ErrTransferNotFound) {
+		s.logger.WithField("transfer_id", id).Warn("Transfer not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("transfer_id", id).Error("Failed to fetch transfer")
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (s *TransferService) ListTransfers(params *models.TransferListParams) (*models.TransferListResponse, error) {
+	s.logger.WithFields(logrus.Fields{
+		"status":    params.Status,
+		"branch_id": params.BranchID,
+	}).Info("Fetching transfers")
+
+	query := *params
+	query.Limit = normalizePageSize(params.Limit)
+
+	response, err := s.transfers.List(&query)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query transfers")
+		return nil, err
+	}
+	return response, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transferFixture struct {
+	*testLibrary
+	circulation *CirculationService
+	holds       *HoldService
+	transfers   *TransferService
+}
+
+func newTransferFixture(t *testing.T, rules LoanRules) *transferFixture {
+	lib := newTestLibrary(t, rules)
+	policies := lib.newPolicyService()
+	return &transferFixture{
+		testLibrary: lib,
+		circulation: lib.newCirculationService(policies, lib.newFineService()),
+		holds:       lib.newHoldService(policies),
+		transfers:   NewTransferService(repository.NewMemoryTransferRepository(lib.loanRepo), lib.itemRepo, lib.branchRepo, lib.calendar, rules, lib.logger),
+	}
+}
+
+func TestBranchService(t *testing.T) {
+	fixture := newTestLibrary(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+
+	main, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: " main ", Name: "Main Library"})
+	require.NoError(t, err)
+	assert.Equal(t, "MAIN", main.Code)
+	_, err = fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "Main", Name: "Another"})
+	assert.ErrorIs(t, err, ErrDuplicateBranchCode)
+
+	_, err = fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1", HomeBranchID: &main.ID})
+	require.NoError(t, err)
+	assert.ErrorIs(t, fixture.branches.DeleteBranch(main.ID), ErrBranchInUse)
+
+	missing := uuid.New().String()
+	_, err = fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-2", HomeBranchID: &missing})
+	assert.ErrorIs(t, err, ErrBranchNotFound)
+	assert.ErrorIs(t, fixture.branches.DeleteBranch(missing), ErrBranchNotFound)
+}
+
+func TestTransferService(t *testing.T) {
+	fixture := newTransferFixture(t, LoanRules{LoanDays: 14, MaxLoans: 5, MaxRenewals: 1, HoldPickupDays: 7})
+	main, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "MAIN", Name: "Main Library"})
+	require.NoError(t, err)
+	north, err := fixture.branches.CreateBranch(&models.CreateBranchRequest{Code: "NORTH", Name: "North Branch"})
+	require.NoError(t, err)
+	item, err := fixture.items.CreateItem(fixture.bookID, &models.CreateItemRequest{Barcode: "P-1", HomeBranchID: &main.ID})
+	require.NoError(t, err)
+	assert.Equal(t, main.ID, *item.CurrentBranchID)
+	anne, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Anne", LastName: "Elliot", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+	mary, err := fixture.members.CreateMember(&models.CreateMemberRequest{FirstName: "Mary", LastName: "Musgrove", MembershipType: models.MembershipAdult})
+	require.NoError(t, err)
+
+	atNorth, err := fixture.items.ListBookItems(fixture.bookID, &models.BookItemsParams{BranchID: north.ID})
+	require.NoError(t, err)
+	assert.Empty(t, atNorth.Data)
+
+	t.Run("manual transfer", func(t *testing.T) {
+		transfer, err := fixture.transfers.RequestTransfer(&models.CreateTransferRequest{Barcode: " P-1 ", ToBranchID: north.ID})
+		require.NoError(t, err)
+		assert.Equal(t, main.ID, transfer.FromBranchID)
+		_, err = fixture.transfers.RequestTransfer(&models.CreateTransferRequest{Barcode: "P-1", ToBranchID: uuid.New().String()})
+		assert.ErrorIs(t, err, ErrBranchNotFound)
+
+		_, err = fixture.transfers.ShipTransfer(transfer.ID)
+		require.NoError(t, err)
+		received, err := fixture.transfers.ReceiveTransfer(transfer.ID)
+		require.NoError(t, err)
+		assert.Nil(t, received.Hold)
+
+		atNorth, err := fixture.items.ListBookItems(fixture.bookID, &models.BookItemsParams{BranchID: north.ID})
+		require.NoError(t, err)
+		require.Len(t, atNorth.Data, 1)
+		assert.Equal(t, 1, atNorth.Availability.Available)
+	})
+
+	t.Run("checkin routes the copy to the pickup branch", func(t *testing.T) {
+		loan, err := fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: anne.CardNumber, BranchID: &north.ID})
+		require.NoError(t, err)
+		assert.Equal(t, north.ID, *loan.BranchID)
+		hold, err := fixture.holds.PlaceHold(fixture.bookID, &models.PlaceHoldRequest{MemberID: mary.ID, PickupBranchID: &main.ID})
+		require.NoError(t, err)
+
+		checkin, err := fixture.circulation.Checkin(&models.CheckinRequest{Barcode: "P-1", BranchID: &north.ID})
+		require.NoError(t, err)
+		require.NotNil(t, checkin.Hold)
+		assert.Equal(t, models.HoldInTransit, checkin.Hold.Status)
+		_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: mary.CardNumber})
+		assert.ErrorIs(t, err, ErrItemNotAvailable)
+		assert.ErrorIs(t, fixture.books.DeleteBook(fixture.bookID, 0), ErrBookInCirculation, "a copy on its way to a holder keeps the book")
+
+		inTransit, err := fixture.transfers.ListTransfers(&models.TransferListParams{Status: models.TransferInTransit})
+		require.NoError(t, err)
+		require.Len(t, inTransit.Data, 1)
+		assert.Equal(t, main.ID, inTransit.Data[0].ToBranchID)
+
+		received, err := fixture.transfers.ReceiveTransfer(inTransit.Data[0].ID)
+		require.NoError(t, err)
+		require.NotNil(t, received.Hold)
+		assert.Equal(t, hold.ID, received.Hold.ID)
+		assert.Equal(t, models.HoldReady, received.Hold.Status)
+
+		_, err = fixture.circulation.Checkout(&models.CheckoutRequest{Barcode: "P-1", CardNumber: mary.CardNumber, BranchID: &main.ID})
+		require.NoError(t, err)
+	})
+
+	t.Run("checkin sends the copy home", func(t *testing.T) {
+		checkin, err := fixture.circulation.Checkin(&models.CheckinRequest{Barcode: "P-1", BranchID: &north.ID})
+		require.NoError(t, err)
+		assert.Nil(t, checkin.Hold)
+
+		returned, err := fixture.items.GetItemByBarcode("P-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.ItemInTransit, returned.Status)
+
+		home, err := fixture.transfers.ListTransfers(&models.TransferListParams{Status: models.TransferInTransit, BranchID: main.ID})
+		require.NoError(t, err)
+		require.Len(t, home.Data, 1)
+		assert.Equal(t, north.ID, home.Data[0].FromBranchID)
+		assert.Nil(t, home.Data[0].HoldID)
+
+		loans, err := fixture.circulation.ListMemberLoans(anne.ID, &models.LoanListParams{BranchID: north.ID})
+		require.NoError(t, err)
+		assert.Equal(t, 1, loans.Total)
+	})
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"library-management-backend/internal/models"
+	"library-management-backend/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrVendorNotFound = repository.ErrVendorNotFound
+	ErrVendorInUse    = repository.ErrVendorInUse
+)
+
+type VendorService struct {
+	repo   repository.VendorRepository
+	logger *logrus.Logger
+}
+
+func NewVendorService(repo repository.VendorRepository, logger *logrus.Logger) *VendorService {
+	return &VendorService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *VendorService) ListVendors() (*models.VendorListResponse, error) {
+	s.logger.Info("Fetching vendors")
+
+	vendors, err := s.repo.List()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to query vendors")
+		return nil, err
+	}
+
+	s.logger.WithField("count", len(vendors)).Info("Successfully fetched vendors")
+	return &models.VendorListResponse{Data: vendors}, nil
+}
+
+func (s *VendorService) GetVendorByID(id string) (*models.Vendor, error) {
+	s.logger.WithField("vendor_id", id).Info("Fetching vendor by ID")
+
+	vendor, err := s.repo.GetByID(id)
+	if errors.Is(err, ErrVendorNotFound) {
+		s.logger.WithField("vendor_id", id).Warn("Vendor not found")
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("vendor_id", id).Error("Failed to fetch vendor")
+		return nil, err
+	}
+
+	return vendor, nil
+}
+
+func (s *VendorService) CreateVendor(req *models.CreateVendorRequest) (*models.Vendor, error) {
+	s.logger.WithField("name", req.Name).Info("Creating new vendor")
+
+	now := time.Now()
+	vendor := &models.Vendor{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(req.Name),
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Address:   req.Address,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(vendor); err != nil {
+		s.logger.WithError(err).Error("Failed to create vendor")
+		return nil, err
+	}
+
+	s.logger.WithField("vendor_id", vendor.ID).Info("Successfully created vendor")
+	return vendor, nil
+}
+
+func (s *VendorService) UpdateVendor(id string, req *models.UpdateVendorRequest) (*models.Vendor, error) {
+	s.logger.WithField("vendor_id", id).Info("Updating vendor")
+
+	vendor, err := s.GetVendorByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	vendor.Name = strings.TrimSpace(req.Name)
+	vendor.Email = req.Email
+	vendor.Phone = req.Phone
+	vendor.Address = req.Address
+	vendor.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(vendor); err != nil {
+		if errors.Is(err, ErrVendorNotFound) {
+			s.logger.WithError(err).WithField("vendor_id", id).Warn("Failed to update vendor")
+			return nil, err
+		}
+		s.logger.WithError(err).WithField("vendor_id", id).Error("Failed to update vendor")
+		return nil, err
+	}
+
+	s.logger.WithField("vendor_id", id).Info("Successfully updated vendor")
+	return vendor, nil
+}
+
+// DeleteVendor removes a vendor that no purchase order refers to.
+func (s *VendorService) DeleteVendor(id string) error {
+	s.logger.WithField("vendor_id", id).Info("Deleting vendor")
+
+	err := s.repo.Delete(id)
+	if errors.Is(err, ErrVendorNotFound) || errors.Is(err, ErrVendorInUse) {
+		s.logger.WithError(err).WithField("vendor_id", id).Warn("Failed to delete vendor")
+		return err
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("vendor_id", id).Error("Failed to delete vendor")
+		return err
+	}
+
+	s.logger.WithField("vendor_id", id).Info("Successfully deleted vendor")
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package cardnumber generates and checks library card numbers. A card
+// number is 14 digits: a 13-digit zero-padded serial number followed by a
+// Luhn check digit, so that mistyped numbers are caught at the desk.
+package cardnumber
+
+import (
+	"fmt"
+)
+
+// Length is the number of digits in a card number.
+const Length = 14
+
+// maxSerial is the largest serial number that fits in a card number.
+const maxSerial = 9999999999999
+
+// Generate returns the card number for a serial number.
+func Generate(serial int64) (string, error) {
+	if serial < 1 || serial > maxSerial {
+		return "", fmt.Errorf("cardnumber: serial %d out of range", serial)
+	}
+	digits := fmt.Sprintf("%013d", serial)
+	return digits + string(rune('0'+checkDigit(digits))), nil
+}
+
+// IsValid reports whether s is a well-formed card number with a correct check
+// digit.
+func IsValid(s string) bool {
+	if len(s) != Length {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return checkDigit(s[:Length-1]) == int(s[Length-1]-'0')
+}
+
+// checkDigit computes the Luhn check digit for a string of digits.
+func checkDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
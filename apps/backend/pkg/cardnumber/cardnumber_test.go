@@ -0,0 +1,39 @@
+package cardnumber
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		serial   int64
+		expected string
+	}{
+		{serial: 1, expected: "00000000000018"},
+		{serial: 42, expected: "00000000000422"},
+		{serial: 7992739871, expected: "00079927398713"},
+		{serial: 9999999999999, expected: "99999999999993"},
+	}
+
+	for _, tc := range testCases {
+		card, err := Generate(tc.serial)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, card)
+		assert.True(t, IsValid(card))
+	}
+
+	_, err := Generate(0)
+	assert.Error(t, err)
+	_, err = Generate(10000000000000)
+	assert.Error(t, err)
+}
+
+func TestIsValid(t *testing.T) {
+	assert.False(t, IsValid("00000000000019"), "wrong check digit")
+	assert.False(t, IsValid("00000000000081"), "transposed digits")
+	assert.False(t, IsValid("0000000000018"), "too short")
+	assert.False(t, IsValid("0000000000001X"), "letters")
+}
@@ -2,13 +2,20 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Books       BooksConfig
+	Circulation CirculationConfig
+	Fines       FinesConfig
+	Calendar    CalendarConfig
+	Notices     NoticesConfig
 }
 
 type ServerConfig struct {
@@ -23,6 +30,74 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// AutoMigrate applies pending schema migrations when the server starts.
+	AutoMigrate bool
+}
+
+type BooksConfig struct {
+	// TrashRetention is how long deleted books stay restorable before they
+	// are purged.
+	TrashRetention time.Duration
+	// TrashPurgeInterval is how often the server purges the trash; 0
+	// disables the background purge.
+	TrashPurgeInterval time.Duration
+}
+
+type CirculationConfig struct {
+	// LoanDays is how many days a checkout or renewal lends a copy for.
+	LoanDays int
+	// MaxLoans is how many copies a member may have on loan at once.
+	MaxLoans int
+	// MaxRenewals is how many times a loan may be renewed.
+	MaxRenewals int
+	// HoldPickupDays is how many days a copy trapped for a hold waits on
+	// the hold shelf.
+	HoldPickupDays int
+	// HoldExpiryInterval is how often the server expires uncollected
+	// holds; 0 disables the background expiry.
+	HoldExpiryInterval time.Duration
+}
+
+type FinesConfig struct {
+	// DailyRateCents is charged for each day a copy is kept past its due
+	// date.
+	DailyRateCents int
+	// MaxFineCents caps the overdue fine of one loan; 0 leaves it uncapped.
+	MaxFineCents int
+	// MaxBalanceCents is the balance above which a member may not borrow;
+	// 0 turns the limit off.
+	MaxBalanceCents int
+	// AccrualInterval is how often the server accrues overdue fines; 0
+	// disables the background accrual.
+	AccrualInterval time.Duration
+}
+
+type CalendarConfig struct {
+	// TimeZone is the IANA name of the library's time zone, in which due
+	// dates, pickup deadlines and opening hours are reckoned.
+	TimeZone string
+}
+
+type NoticesConfig struct {
+	// SMTPHost is the mail server email notices are sent through. When it
+	// is empty, email notices are written to the notice log instead.
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	// From is the sender of email notices.
+	From string
+	// LogPath is the file the log channel appends notices to; empty writes
+	// them to standard output.
+	LogPath string
+	// Interval is how often the server queues and sends notices; 0
+	// disables the background dispatch.
+	Interval time.Duration
+	// MaxAttempts is how many times a notice is tried before it fails.
+	MaxAttempts int
+	// RetryBackoff is the wait after a first failed attempt, doubling with
+	// each failure after that.
+	RetryBackoff time.Duration
 }
 
 func Load() *Config {
@@ -40,6 +115,40 @@ func Load() *Config {
 			Password: getEnv("DB_PASSWORD", ""),
 			Name:     getEnv("DB_NAME", "library"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
+		},
+		Books: BooksConfig{
+			TrashRetention:     getEnvDuration("BOOK_TRASH_RETENTION", 30*24*time.Hour),
+			TrashPurgeInterval: getEnvDuration("BOOK_TRASH_PURGE_INTERVAL", 24*time.Hour),
+		},
+		Circulation: CirculationConfig{
+			LoanDays:    getEnvInt("LOAN_PERIOD_DAYS", 21),
+			MaxLoans:    getEnvInt("LOAN_LIMIT", 10),
+			MaxRenewals: getEnvInt("LOAN_MAX_RENEWALS", 2),
+
+			HoldPickupDays:     getEnvInt("HOLD_PICKUP_DAYS", 7),
+			HoldExpiryInterval: getEnvDuration("HOLD_EXPIRY_INTERVAL", time.Hour),
+		},
+		Fines: FinesConfig{
+			DailyRateCents:  getEnvInt("FINE_DAILY_RATE_CENTS", 25),
+			MaxFineCents:    getEnvInt("FINE_MAX_CENTS", 1000),
+			MaxBalanceCents: getEnvInt("FINE_BLOCK_THRESHOLD_CENTS", 1000),
+			AccrualInterval: getEnvDuration("FINE_ACCRUAL_INTERVAL", 24*time.Hour),
+		},
+		Calendar: CalendarConfig{
+			TimeZone: getEnv("LIBRARY_TIMEZONE", "Local"),
+		},
+		Notices: NoticesConfig{
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			From:         getEnv("NOTICE_FROM", "library@localhost"),
+			LogPath:      getEnv("NOTICE_LOG_PATH", ""),
+			Interval:     getEnvDuration("NOTICE_INTERVAL", 5*time.Minute),
+			MaxAttempts:  getEnvInt("NOTICE_MAX_ATTEMPTS", 5),
+			RetryBackoff: getEnvDuration("NOTICE_RETRY_BACKOFF", time.Minute),
 		},
 	}
 }
@@ -50,3 +159,30 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	return defaultValue
+}
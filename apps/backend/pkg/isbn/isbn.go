@@ -0,0 +1,205 @@
+// Package isbn validates, normalizes and converts International Standard Book
+// Numbers. The canonical form used throughout the application is the
+// unhyphenated 13-digit ISBN.
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength    = errors.New("isbn: must have 10 or 13 digits")
+	ErrInvalidCharacter = errors.New("isbn: contains invalid characters")
+	ErrInvalidChecksum  = errors.New("isbn: invalid check digit")
+	ErrNotBookland      = errors.New("isbn: EAN-13 must start with 978 or 979")
+	ErrNoISBN10         = errors.New("isbn: only 978-prefixed ISBN-13s have an ISBN-10 form")
+)
+
+// Clean strips an optional "ISBN" label, hyphens and whitespace and
+// upper-cases an ISBN-10 "x" check digit. It does not validate.
+func Clean(s string) string {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	for _, prefix := range []string{"ISBN-13", "ISBN-10", "ISBN13", "ISBN10", "ISBN"} {
+		if strings.HasPrefix(upper, prefix) {
+			s = s[len(prefix):]
+			break
+		}
+	}
+	s = strings.TrimLeft(s, ": ")
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '-' || r == ' ' || r == '\t':
+			continue
+		case r == 'x':
+			b.WriteRune('X')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Validate reports whether s is a well-formed ISBN-10, ISBN-13 or EAN-13
+// barcode scan of an ISBN, ignoring hyphens and spaces.
+func Validate(s string) error {
+	_, err := Normalize(s)
+	return err
+}
+
+func IsValid(s string) bool {
+	return Validate(s) == nil
+}
+
+// Normalize returns the canonical ISBN-13 for any accepted input: ISBN-10,
+// ISBN-13 (hyphenated or not) or a Bookland EAN-13 scan, including scans that
+// carry a 2 or 5 digit price add-on.
+func Normalize(s string) (string, error) {
+	code := Clean(s)
+
+	switch len(code) {
+	case 10:
+		return ToISBN13(code)
+	case 13:
+		if err := validate13(code); err != nil {
+			return "", err
+		}
+		return code, nil
+	case 15, 18:
+		return FromEAN13(code)
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+// ToISBN13 converts an ISBN-10 to ISBN-13. A valid ISBN-13 is returned
+// unchanged.
+func ToISBN13(s string) (string, error) {
+	code := Clean(s)
+
+	if len(code) == 13 {
+		if err := validate13(code); err != nil {
+			return "", err
+		}
+		return code, nil
+	}
+
+	if err := validate10(code); err != nil {
+		return "", err
+	}
+
+	body := "978" + code[:9]
+	return body + string(CheckDigit13(body)), nil
+}
+
+// ToISBN10 converts a 978-prefixed ISBN-13 to ISBN-10. A valid ISBN-10 is
+// returned unchanged.
+func ToISBN10(s string) (string, error) {
+	code := Clean(s)
+
+	if len(code) == 10 {
+		if err := validate10(code); err != nil {
+			return "", err
+		}
+		return code, nil
+	}
+
+	if err := validate13(code); err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(code, "978") {
+		return "", ErrNoISBN10
+	}
+
+	body := code[3:12]
+	return body + string(CheckDigit10(body)), nil
+}
+
+// FromEAN13 extracts the ISBN-13 from a barcode scan. Scanners append the
+// 2 or 5 digit supplemental code to the EAN-13, which is discarded.
+func FromEAN13(scan string) (string, error) {
+	code := Clean(scan)
+
+	switch len(code) {
+	case 13:
+	case 15, 18:
+		if !isDigits(code) {
+			return "", ErrInvalidCharacter
+		}
+		code = code[:13]
+	default:
+		return "", ErrInvalidLength
+	}
+
+	if err := validate13(code); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// CheckDigit10 computes the ISBN-10 check digit for the first nine digits.
+func CheckDigit10(body string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// CheckDigit13 computes the EAN-13 check digit for the first twelve digits.
+func CheckDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(body[i]-'0') * weight
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func validate10(code string) error {
+	if len(code) != 10 {
+		return ErrInvalidLength
+	}
+	if !isDigits(code[:9]) || !(isDigits(code[9:]) || code[9] == 'X') {
+		return ErrInvalidCharacter
+	}
+	if CheckDigit10(code[:9]) != code[9] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+func validate13(code string) error {
+	if len(code) != 13 {
+		return ErrInvalidLength
+	}
+	if !isDigits(code) {
+		return ErrInvalidCharacter
+	}
+	if !strings.HasPrefix(code, "978") && !strings.HasPrefix(code, "979") {
+		return ErrNotBookland
+	}
+	if CheckDigit13(code[:12]) != code[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package isbn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "ISBN-13", input: "9780306406157", expected: "9780306406157"},
+		{name: "hyphenated ISBN-13", input: "978-0-306-40615-7", expected: "9780306406157"},
+		{name: "labelled ISBN-13", input: "ISBN-13: 978-0-306-40615-7", expected: "9780306406157"},
+		{name: "979 prefix", input: "979-10-90636-07-1", expected: "9791090636071"},
+		{name: "ISBN-10", input: "0-306-40615-2", expected: "9780306406157"},
+		{name: "ISBN-10 with X check digit", input: "0-8044-2957-x", expected: "9780804429573"},
+		{name: "EAN-13 scan with 5 digit add-on", input: "9780306406157 51299", expected: "9780306406157"},
+		{name: "EAN-13 scan with 2 digit add-on", input: "978030640615705", expected: "9780306406157"},
+		{name: "bad ISBN-13 checksum", input: "9780306406158", err: ErrInvalidChecksum},
+		{name: "bad ISBN-10 checksum", input: "0306406153", err: ErrInvalidChecksum},
+		{name: "non-Bookland EAN", input: "4006381333931", err: ErrNotBookland},
+		{name: "letters", input: "97803064O6157", err: ErrInvalidCharacter},
+		{name: "X in the middle", input: "03X6406152", err: ErrInvalidCharacter},
+		{name: "wrong length", input: "12345", err: ErrInvalidLength},
+		{name: "empty", input: "", err: ErrInvalidLength},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			normalized, err := Normalize(tc.input)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.False(t, IsValid(tc.input))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, normalized)
+			assert.True(t, IsValid(tc.input))
+		})
+	}
+}
+
+func TestToISBN10(t *testing.T) {
+	isbn10, err := ToISBN10("978-0-8044-2957-3")
+	assert.NoError(t, err)
+	assert.Equal(t, "080442957X", isbn10)
+
+	isbn10, err = ToISBN10("0306406152")
+	assert.NoError(t, err)
+	assert.Equal(t, "0306406152", isbn10)
+
+	_, err = ToISBN10("9791090636071")
+	assert.ErrorIs(t, err, ErrNoISBN10)
+
+	_, err = ToISBN10("9780306406158")
+	assert.ErrorIs(t, err, ErrInvalidChecksum)
+}
+
+func TestToISBN13(t *testing.T) {
+	isbn13, err := ToISBN13("0306406152")
+	assert.NoError(t, err)
+	assert.Equal(t, "9780306406157", isbn13)
+
+	isbn13, err = ToISBN13("9791090636071")
+	assert.NoError(t, err)
+	assert.Equal(t, "9791090636071", isbn13)
+
+	_, err = ToISBN13("123")
+	assert.ErrorIs(t, err, ErrInvalidLength)
+}
+
+func TestFromEAN13(t *testing.T) {
+	code, err := FromEAN13("978030640615751299")
+	assert.NoError(t, err)
+	assert.Equal(t, "9780306406157", code)
+
+	_, err = FromEAN13("0306406152")
+	assert.ErrorIs(t, err, ErrInvalidLength)
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	for _, isbn10 := range []string{"0306406152", "080442957X", "0140449132", "1566199093"} {
+		isbn13, err := ToISBN13(isbn10)
+		assert.NoError(t, err)
+		back, err := ToISBN10(isbn13)
+		assert.NoError(t, err)
+		assert.Equal(t, isbn10, back)
+	}
+}
@@ -0,0 +1,220 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	leaderLength      = 24
+	directoryEntryLen = 12
+	maxRecordLength   = 99999
+	maxFieldLength    = 9999
+
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+var ErrRecordTooLong = errors.New("marc: record does not fit in 99999 bytes")
+
+// Reader reads binary MARC 21 records. Records are expected to be UTF-8
+// encoded; MARC-8 records are passed through byte for byte.
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF when there are no more. Line breaks
+// between records, which some tools add, are skipped.
+func (r *Reader) Read() (*Record, error) {
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b != '\n' && b != '\r' {
+			r.r.UnreadByte()
+			break
+		}
+	}
+
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(r.r, prefix); err != nil {
+		return nil, fmt.Errorf("%w: truncated leader", ErrInvalidRecord)
+	}
+	length, err := strconv.Atoi(string(prefix))
+	if err != nil || length < leaderLength+2 {
+		return nil, fmt.Errorf("%w: bad record length %q", ErrInvalidRecord, prefix)
+	}
+
+	data := make([]byte, length)
+	copy(data, prefix)
+	if _, err := io.ReadFull(r.r, data[5:]); err != nil {
+		return nil, fmt.Errorf("%w: record is shorter than its length %d", ErrInvalidRecord, length)
+	}
+	return Unmarshal(data)
+}
+
+// Unmarshal decodes a single binary record.
+func Unmarshal(data []byte) (*Record, error) {
+	if len(data) < leaderLength+2 || data[len(data)-1] != recordTerminator {
+		return nil, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+
+	base, err := strconv.Atoi(string(data[12:17]))
+	if err != nil || base <= leaderLength || base > len(data) || data[base-1] != fieldTerminator {
+		return nil, fmt.Errorf("%w: bad base address %q", ErrInvalidRecord, data[12:17])
+	}
+	directory := data[leaderLength : base-1]
+	if len(directory)%directoryEntryLen != 0 {
+		return nil, fmt.Errorf("%w: directory length is not a multiple of 12", ErrInvalidRecord)
+	}
+
+	record := &Record{Leader: string(data[:leaderLength])}
+	for i := 0; i < len(directory); i += directoryEntryLen {
+		entry := directory[i : i+directoryEntryLen]
+		tag := string(entry[:3])
+		length, lengthErr := strconv.Atoi(string(entry[3:7]))
+		start, startErr := strconv.Atoi(string(entry[7:12]))
+		end := base + start + length
+		if lengthErr != nil || startErr != nil || length < 1 || end > len(data)-1 {
+			return nil, fmt.Errorf("%w: bad directory entry for %s", ErrInvalidRecord, tag)
+		}
+
+		content := bytes.TrimSuffix(data[base+start:end], []byte{fieldTerminator})
+		field, err := decodeField(tag, content)
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return record, nil
+}
+
+func decodeField(tag string, content []byte) (Field, error) {
+	if IsControlTag(tag) {
+		return Field{Tag: tag, Value: string(content)}, nil
+	}
+	if len(content) < 2 {
+		return Field{}, fmt.Errorf("%w: field %s has no indicators", ErrInvalidRecord, tag)
+	}
+
+	field := Field{Tag: tag, Indicator1: content[0], Indicator2: content[1]}
+	parts := bytes.Split(content[2:], []byte{subfieldDelimiter})
+	// Anything before the first delimiter is not part of a subfield.
+	for _, part := range parts[1:] {
+		if len(part) == 0 {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+	}
+	return field, nil
+}
+
+// Writer writes binary MARC 21 records.
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) Write(record *Record) error {
+	data, err := Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+// Marshal encodes a record in ISO 2709. The record length, base address,
+// character coding and entry map of the leader are always set by Marshal.
+func Marshal(record *Record) ([]byte, error) {
+	leader := []byte(record.Leader)
+	if len(leader) == 0 {
+		leader = []byte(DefaultLeader)
+	}
+	if len(leader) != leaderLength {
+		return nil, fmt.Errorf("%w: leader must be 24 characters", ErrInvalidRecord)
+	}
+
+	var directory, fields bytes.Buffer
+	for _, field := range record.Fields {
+		if !validTag(field.Tag) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTag, field.Tag)
+		}
+
+		content, err := encodeField(&field)
+		if err != nil {
+			return nil, err
+		}
+		if len(content) > maxFieldLength {
+			return nil, fmt.Errorf("%w: field %s is longer than 9999 bytes", ErrRecordTooLong, field.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, len(content), fields.Len())
+		fields.Write(content)
+	}
+
+	base := leaderLength + directory.Len() + 1
+	length := base + fields.Len() + 1
+	if length > maxRecordLength {
+		return nil, ErrRecordTooLong
+	}
+
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	leader[9] = 'a'
+	leader[10] = '2'
+	leader[11] = '2'
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+
+	data := make([]byte, 0, length)
+	data = append(data, leader...)
+	data = append(data, directory.Bytes()...)
+	data = append(data, fieldTerminator)
+	data = append(data, fields.Bytes()...)
+	data = append(data, recordTerminator)
+	return data, nil
+}
+
+func encodeField(field *Field) ([]byte, error) {
+	var content bytes.Buffer
+	if IsControlTag(field.Tag) {
+		if err := checkValue(field.Tag, field.Value); err != nil {
+			return nil, err
+		}
+		content.WriteString(field.Value)
+	} else {
+		content.WriteByte(indicator(field.Indicator1))
+		content.WriteByte(indicator(field.Indicator2))
+		for _, subfield := range field.Subfields {
+			if err := checkValue(field.Tag, subfield.Value); err != nil {
+				return nil, err
+			}
+			content.WriteByte(subfieldDelimiter)
+			content.WriteByte(subfield.Code)
+			content.WriteString(subfield.Value)
+		}
+	}
+	content.WriteByte(fieldTerminator)
+	return content.Bytes(), nil
+}
+
+func checkValue(tag, value string) error {
+	if strings.ContainsAny(value, "\x1d\x1e\x1f") {
+		return fmt.Errorf("%w: field %s contains a MARC delimiter", ErrInvalidRecord, tag)
+	}
+	return nil
+}
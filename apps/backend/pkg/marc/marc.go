@@ -0,0 +1,125 @@
+// Package marc reads and writes bibliographic records in MARC 21, both in the
+// binary ISO 2709 exchange format and as MARCXML. It knows the structure of a
+// record but not the meaning of its fields; mapping fields onto application
+// data is left to the caller.
+package marc
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidRecord = errors.New("marc: invalid record")
+	ErrInvalidTag    = errors.New("marc: tags must be three alphanumeric characters")
+)
+
+// DefaultLeader is the leader of a new bibliographic record for a language
+// material monograph encoded in UTF-8. The record length and base address are
+// filled in when the record is written.
+const DefaultLeader = "00000nam a2200000 i 4500"
+
+// Record is a MARC record: a 24 character leader followed by control fields
+// (tags 001 to 009) and data fields.
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a control field, which only has a Value, or a data field, which
+// has two indicators and a list of subfields.
+type Field struct {
+	Tag        string
+	Value      string
+	Indicator1 byte
+	Indicator2 byte
+	Subfields  []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// NewRecord returns an empty record with DefaultLeader.
+func NewRecord() *Record {
+	return &Record{Leader: DefaultLeader}
+}
+
+// IsControlTag reports whether tag belongs to a control field.
+func IsControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
+
+func validTag(tag string) bool {
+	if len(tag) != 3 {
+		return false
+	}
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// AddControlField appends a control field.
+func (r *Record) AddControlField(tag, value string) {
+	r.Fields = append(r.Fields, Field{Tag: tag, Value: value})
+}
+
+// AddDataField appends a data field. codesAndValues alternates subfield codes
+// and values, so AddDataField("245", '1', '0', "a", "Dune") adds 245 $a Dune.
+// Pairs with an empty value are left out.
+func (r *Record) AddDataField(tag string, ind1, ind2 byte, codesAndValues ...string) {
+	field := Field{Tag: tag, Indicator1: ind1, Indicator2: ind2}
+	for i := 0; i+1 < len(codesAndValues); i += 2 {
+		if codesAndValues[i+1] == "" || codesAndValues[i] == "" {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{Code: codesAndValues[i][0], Value: codesAndValues[i+1]})
+	}
+	r.Fields = append(r.Fields, field)
+}
+
+// FieldsByTag returns the fields with the given tag in record order.
+func (r *Record) FieldsByTag(tag string) []Field {
+	var fields []Field
+	for _, field := range r.Fields {
+		if field.Tag == tag {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ControlField returns the value of the first control field with the given
+// tag, or "" when there is none.
+func (r *Record) ControlField(tag string) string {
+	for _, field := range r.Fields {
+		if field.Tag == tag {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// Subfield returns the value of the first subfield with the given code, or
+// "" when there is none.
+func (f *Field) Subfield(code byte) string {
+	for _, subfield := range f.Subfields {
+		if subfield.Code == code {
+			return subfield.Value
+		}
+	}
+	return ""
+}
+
+// indicator returns a blank for an unset indicator.
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
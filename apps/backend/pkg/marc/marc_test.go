@@ -0,0 +1,156 @@
+package marc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleRecord() *Record {
+	record := NewRecord()
+	record.AddControlField("001", "book-1")
+	record.AddControlField("008", "      s1937    enk           000 1 eng d")
+	record.AddDataField("020", ' ', ' ', "a", "9780261102217")
+	record.AddDataField("100", '1', ' ', "a", "Tolkien, J. R. R.")
+	record.AddDataField("245", '1', '4', "a", "The hobbit :", "b", "or there and back again ·")
+	record.AddDataField("520", ' ', ' ', "a", "Bilbo & the dwarves <travel>.", "b", "")
+	return record
+}
+
+func TestAddDataField(t *testing.T) {
+	fields := sampleRecord().FieldsByTag("520")
+	require.Len(t, fields, 1)
+	assert.Len(t, fields[0].Subfields, 1)
+	assert.Equal(t, "Bilbo & the dwarves <travel>.", fields[0].Subfield('a'))
+	assert.Empty(t, fields[0].Subfield('b'))
+}
+
+func TestISO2709RoundTrip(t *testing.T) {
+	record := sampleRecord()
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+	require.NoError(t, writer.Write(record))
+	require.NoError(t, writer.Write(record))
+
+	data := buf.Bytes()
+	length := len(data) / 2
+	assert.Equal(t, fmt.Sprintf("%05d", length), string(data[:5]))
+	assert.Equal(t, byte(recordTerminator), data[length-1])
+	assert.Equal(t, "4500", string(data[20:24]))
+
+	// Some tools put line breaks between records.
+	separated := append(append(bytes.Clone(data[:length]), "\r\n"...), data[length:]...)
+	reader := NewReader(bytes.NewReader(separated))
+	for i := 0; i < 2; i++ {
+		decoded, err := reader.Read()
+		require.NoError(t, err)
+		assert.Equal(t, record.Fields, decoded.Fields)
+		assert.Equal(t, "book-1", decoded.ControlField("001"))
+	}
+	_, err := reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestISO2709Errors(t *testing.T) {
+	valid, err := Marshal(sampleRecord())
+	require.NoError(t, err)
+
+	badBase := bytes.Clone(valid)
+	copy(badBase[12:17], "00010")
+	badEntry := bytes.Clone(valid)
+	copy(badEntry[27:31], "9999")
+
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated", data: valid[:len(valid)-10]},
+		{name: "bad length", data: append([]byte("abcde"), valid[5:]...)},
+		{name: "bad base address", data: badBase},
+		{name: "field past the end", data: badEntry},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tc.data)).Read()
+			assert.ErrorIs(t, err, ErrInvalidRecord)
+		})
+	}
+
+	t.Run("bad tag", func(t *testing.T) {
+		record := NewRecord()
+		record.AddDataField("24", ' ', ' ', "a", "Title")
+		_, err := Marshal(record)
+		assert.ErrorIs(t, err, ErrInvalidTag)
+	})
+
+	t.Run("delimiter in a value", func(t *testing.T) {
+		record := NewRecord()
+		record.AddDataField("245", ' ', ' ', "a", "Title\x1eInjected")
+		_, err := Marshal(record)
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+	})
+
+	t.Run("field too long", func(t *testing.T) {
+		record := NewRecord()
+		record.AddDataField("520", ' ', ' ', "a", strings.Repeat("x", 10000))
+		_, err := Marshal(record)
+		assert.ErrorIs(t, err, ErrRecordTooLong)
+	})
+}
+
+func TestMARCXMLRoundTrip(t *testing.T) {
+	record := sampleRecord()
+	var buf bytes.Buffer
+	writer := NewXMLWriter(&buf)
+	require.NoError(t, writer.Write(record))
+	require.NoError(t, writer.Write(record))
+	require.NoError(t, writer.Close())
+
+	document := buf.String()
+	assert.Contains(t, document, `<collection xmlns="`+Namespace+`">`)
+	assert.Contains(t, document, `<datafield tag="245" ind1="1" ind2="4">`)
+
+	reader := NewXMLReader(strings.NewReader(document))
+	for i := 0; i < 2; i++ {
+		decoded, err := reader.Read()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultLeader, decoded.Leader)
+		assert.Equal(t, record.Fields, decoded.Fields)
+	}
+	_, err := reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestMARCXMLReader(t *testing.T) {
+	t.Run("single record", func(t *testing.T) {
+		document := `<record xmlns="http://www.loc.gov/MARC21/slim">
+  <leader>00000nam a2200000 a 4500</leader>
+  <datafield tag="100" ind1="1" ind2=" "><subfield code="a">Herbert, Frank.</subfield></datafield>
+  <controlfield tag="001">42</controlfield>
+</record>`
+		record, err := NewXMLReader(strings.NewReader(document)).Read()
+		require.NoError(t, err)
+		assert.Equal(t, "001", record.Fields[0].Tag)
+
+		author := record.FieldsByTag("100")
+		require.Len(t, author, 1)
+		assert.Equal(t, "Herbert, Frank.", author[0].Subfield('a'))
+	})
+
+	t.Run("empty collection", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, NewXMLWriter(&buf).Close())
+		_, err := NewXMLReader(&buf).Read()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := NewXMLReader(strings.NewReader(`<collection><record><leader>`)).Read()
+		assert.ErrorIs(t, err, ErrInvalidRecord)
+	})
+}
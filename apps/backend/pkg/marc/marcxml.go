@@ -0,0 +1,162 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML namespace of the Library of Congress slim schema.
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// XMLReader reads the records of a MARCXML document, either a <collection>
+// or a single <record>, one at a time.
+type XMLReader struct {
+	decoder *xml.Decoder
+}
+
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{decoder: xml.NewDecoder(r)}
+}
+
+// Read returns the next record, or io.EOF when there are no more.
+func (r *XMLReader) Read() (*Record, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var decoded xmlRecord
+		if err := r.decoder.DecodeElement(&decoded, &start); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		return decoded.record(), nil
+	}
+}
+
+// record converts a decoded <record>. The decoder keeps control and data
+// fields in separate lists, so the control fields come first, as MARC
+// requires anyway.
+func (x *xmlRecord) record() *Record {
+	record := &Record{Leader: x.Leader}
+	for _, field := range x.ControlFields {
+		record.Fields = append(record.Fields, Field{Tag: field.Tag, Value: field.Value})
+	}
+	for _, field := range x.DataFields {
+		decoded := Field{Tag: field.Tag, Indicator1: xmlIndicator(field.Ind1), Indicator2: xmlIndicator(field.Ind2)}
+		for _, subfield := range field.Subfields {
+			if subfield.Code == "" {
+				continue
+			}
+			decoded.Subfields = append(decoded.Subfields, Subfield{Code: subfield.Code[0], Value: subfield.Value})
+		}
+		record.Fields = append(record.Fields, decoded)
+	}
+	return record
+}
+
+func xmlIndicator(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
+
+// XMLWriter writes records into a MARCXML <collection>. Close must be called
+// to end the document.
+type XMLWriter struct {
+	w       io.Writer
+	encoder *xml.Encoder
+	started bool
+}
+
+func NewXMLWriter(w io.Writer) *XMLWriter {
+	return &XMLWriter{w: w, encoder: xml.NewEncoder(w)}
+}
+
+func (w *XMLWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := io.WriteString(w.w, xml.Header+`<collection xmlns="`+Namespace+`">`)
+	return err
+}
+
+func (w *XMLWriter) Write(record *Record) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	leader := record.Leader
+	if leader == "" {
+		leader = DefaultLeader
+	}
+	encoded := xmlRecord{Leader: leader}
+	for _, field := range record.Fields {
+		if !validTag(field.Tag) {
+			return fmt.Errorf("%w: %q", ErrInvalidTag, field.Tag)
+		}
+		if IsControlTag(field.Tag) {
+			encoded.ControlFields = append(encoded.ControlFields, xmlControlField{Tag: field.Tag, Value: field.Value})
+			continue
+		}
+
+		dataField := xmlDataField{
+			Tag:  field.Tag,
+			Ind1: string(indicator(field.Indicator1)),
+			Ind2: string(indicator(field.Indicator2)),
+		}
+		for _, subfield := range field.Subfields {
+			dataField.Subfields = append(dataField.Subfields, xmlSubfield{Code: string(subfield.Code), Value: subfield.Value})
+		}
+		encoded.DataFields = append(encoded.DataFields, dataField)
+	}
+
+	if _, err := io.WriteString(w.w, "\n"); err != nil {
+		return err
+	}
+	return w.encoder.Encode(encoded)
+}
+
+// Close ends the collection; it does not close the underlying writer.
+func (w *XMLWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, "\n</collection>\n")
+	return err
+}
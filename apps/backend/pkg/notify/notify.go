@@ -0,0 +1,59 @@
+// Package notify delivers messages to library members. Each channel, such as
+// email, is a Notifier, so that the code deciding what to tell members does
+// not depend on how the message gets to them.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Message is a plain text message to one recipient.
+type Message struct {
+	// To is the address of the recipient on the channel, such as an email
+	// address.
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages over one channel. Send returns once the
+// message has been handed on; an error means it was not and may be retried.
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes messages to a writer, such as a file or standard
+// output, for development and for libraries without a mail server.
+type LogNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewLogNotifier(w io.Writer) *LogNotifier {
+	return &LogNotifier{w: w}
+}
+
+// Send writes msg as a block of headers and body, followed by a blank line.
+func (n *LogNotifier) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "To: %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+	if !strings.HasSuffix(msg.Body, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, err := io.WriteString(n.w, b.String()); err != nil {
+		return fmt.Errorf("notify: failed to write message: %w", err)
+	}
+	return nil
+}
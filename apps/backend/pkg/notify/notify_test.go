@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/mail"
+	"testing"
+
+	"library-management-backend/pkg/notify/smtptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPNotifier(t *testing.T) {
+	server := smtptest.NewServer()
+	defer server.Close()
+
+	notifier := NewSMTPNotifier(SMTPConfig{Host: server.Host, Port: server.Port, From: "Library <library@example.org>"})
+	msg := Message{
+		To:      "anne@example.org",
+		Subject: "Überfällig: Persuasion",
+		Body:    "Dear Anne,\n.\nPlease return it.\n",
+	}
+	require.NoError(t, notifier.Send(context.Background(), msg))
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "library@example.org", messages[0].From)
+	assert.Equal(t, []string{"anne@example.org"}, messages[0].To)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(messages[0].Data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, msg.Subject, subject)
+	assert.Equal(t, "<anne@example.org>", parsed.Header.Get("To"))
+	assert.Equal(t, "text/plain; charset=utf-8", parsed.Header.Get("Content-Type"))
+	body, err := io.ReadAll(parsed.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "Dear Anne,\r\n.\r\nPlease return it.\r\n", string(body))
+
+	t.Run("refused", func(t *testing.T) {
+		server.FailNext(1)
+		assert.Error(t, notifier.Send(context.Background(), msg))
+		require.NoError(t, notifier.Send(context.Background(), msg))
+		assert.Len(t, server.Messages(), 2)
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		err := notifier.Send(context.Background(), Message{To: "anne@example.org\r\nBcc: mary@example.org", Subject: "Hi"})
+		assert.Error(t, err)
+	})
+
+	t.Run("no server", func(t *testing.T) {
+		closed := smtptest.NewServer()
+		closed.Close()
+		notifier := NewSMTPNotifier(SMTPConfig{Host: closed.Host, Port: closed.Port, From: "library@example.org"})
+		assert.Error(t, notifier.Send(context.Background(), msg))
+	})
+}
+
+func TestLogNotifier(t *testing.T) {
+	var out bytes.Buffer
+	notifier := NewLogNotifier(&out)
+
+	require.NoError(t, notifier.Send(context.Background(), Message{To: "anne@example.org", Subject: "Ready", Body: "Your hold is ready."}))
+	assert.Equal(t, "To: anne@example.org\nSubject: Ready\n\nYour hold is ready.\n\n", out.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, notifier.Send(ctx, Message{To: "anne@example.org"}))
+}
@@ -0,0 +1,182 @@
+// Package smtptest provides an in-process SMTP server for testing code that
+// sends mail, in the manner of net/http/httptest. It speaks just enough
+// SMTP for net/smtp and records each message it accepts.
+package smtptest
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is a message the server accepted.
+type Message struct {
+	From string
+	To   []string
+	// Data is the message as sent, headers and body, with CRLF line
+	// endings and leading dots unescaped.
+	Data []byte
+}
+
+// Server is an SMTP server listening on a loopback address.
+type Server struct {
+	// Host and Port are where the server listens.
+	Host string
+	Port string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	failures int
+}
+
+// NewServer starts a server on a free loopback port. It panics if it cannot
+// listen, like httptest.NewServer.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("smtptest: failed to listen: " + err.Error())
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	s := &Server{Host: host, Port: port, listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close stops the server and waits for open connections to finish.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+// Messages returns the messages accepted so far, in the order they arrived.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message{}, s.messages...)
+}
+
+// FailNext makes the server refuse the next n messages with a temporary
+// failure once their data has been sent.
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *Server) handle(conn *textproto.Conn) {
+	var msg Message
+	reply := func(format string, args ...interface{}) bool {
+		return conn.PrintfLine(format, args...) == nil
+	}
+
+	if !reply("220 smtptest ESMTP ready") {
+		return
+	}
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		var ok bool
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ok = reply("250-smtptest greets %s", arg) && reply("250 8BITMIME")
+		case "HELO":
+			ok = reply("250 smtptest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			ok = reply("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			ok = reply("250 OK")
+		case "DATA":
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+			data, err := readData(conn.Reader.R)
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			ok = s.accept(msg, reply)
+			msg = Message{}
+		case "RSET":
+			msg = Message{}
+			ok = reply("250 OK")
+		case "NOOP":
+			ok = reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			ok = reply("502 Command not implemented")
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// accept records msg, or refuses it when failures are pending.
+func (s *Server) accept(msg Message, reply func(string, ...interface{}) bool) bool {
+	s.mu.Lock()
+	if s.failures > 0 {
+		s.failures--
+		s.mu.Unlock()
+		return reply("451 Try again later")
+	}
+	s.messages = append(s.messages, msg)
+	s.mu.Unlock()
+	return reply("250 OK")
+}
+
+// readData reads message data up to the terminating dot, keeping CRLF line
+// endings and removing the dot stuffing.
+func readData(r *bufio.Reader) ([]byte, error) {
+	var data []byte
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if line == ".\r\n" {
+			return data, nil
+		}
+		data = append(data, strings.TrimPrefix(line, ".")...)
+	}
+}
+
+// address takes the address out of a "FROM:<address>" or "TO:<address>"
+// argument.
+func address(arg string) string {
+	start := strings.Index(arg, "<")
+	end := strings.LastIndex(arg, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
@@ -44,8 +44,8 @@ export const DeleteConfirmationDialog: React.FC<DeleteConfirmationDialogProps> =
         <AlertDialogHeader>
           <AlertDialogTitle>Are you absolutely sure?</AlertDialogTitle>
           <AlertDialogDescription>
-            This action cannot be undone. This will permanently delete the book
-            "<span className="font-semibold">{book.title}</span>" by <span className="font-semibold">{book.author}</span>.
+            This will move the book "<span className="font-semibold">{book.title}</span>" by{" "}
+            <span className="font-semibold">{book.author}</span> to the trash. It can be restored until the trash is purged.
           </AlertDialogDescription>
         </AlertDialogHeader>
         <AlertDialogFooter>
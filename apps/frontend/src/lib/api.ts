@@ -1,4 +1,9 @@
-import { Book, CreateBookRequest, UpdateBookRequest } from '@/types/book';
+import {
+  Book,
+  BookListResponse,
+  CreateBookRequest,
+  UpdateBookRequest,
+} from '@/types/book';
 
 const API_BASE_URL =
   process.env.NEXT_PUBLIC_API_BASE_URL || 'http://localhost:8080/api';
@@ -22,10 +27,19 @@ const handleResponse = async (response: Response) => {
 };
 
 export const bookApi = {
-  // Get all books
+  // Get all books, following the cursor across pages
   getBooks: async (): Promise<Book[]> => {
-    const response = await fetch(`${API_BASE_URL}/books`);
-    return handleResponse(response);
+    const books: Book[] = [];
+    let cursor: string | undefined;
+    do {
+      const params = new URLSearchParams({ limit: '100' });
+      if (cursor) params.set('cursor', cursor);
+      const response = await fetch(`${API_BASE_URL}/books?${params}`);
+      const page: BookListResponse = await handleResponse(response);
+      books.push(...page.data);
+      cursor = page.next_cursor;
+    } while (cursor);
+    return books;
   },
 
   // Get single book
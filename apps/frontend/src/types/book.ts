@@ -6,8 +6,11 @@ export interface Book {
   description?: string;
   isbn?: string;
   genre?: string;
+  language?: string;
+  version?: number;
   created_at?: string;
   updated_at?: string;
+  deleted_at?: string;
 }
 
 export interface CreateBookRequest {
@@ -22,3 +25,9 @@ export interface CreateBookRequest {
 export interface UpdateBookRequest extends CreateBookRequest {
   id: string;
 }
+
+export interface BookListResponse {
+  data: Book[];
+  next_cursor?: string;
+  total: number;
+}